@@ -3,6 +3,7 @@ package main
 import (
 	"context"
 	"fmt"
+	"io"
 	"log"
 	"net/http"
 	"os"
@@ -11,10 +12,14 @@ import (
 	"time"
 
 	"github.com/rs/zerolog"
+	"github.com/shopspring/decimal"
 	"router/internal/api"
+	"router/internal/auth"
 	"router/internal/binance"
 	"router/internal/config"
 	"router/internal/orders"
+	"router/internal/trace"
+	"router/internal/wsapi"
 )
 
 func main() {
@@ -28,25 +33,67 @@ func main() {
 		logger.Fatal().Err(err).Msg("Failed to load config")
 	}
 
-	// Apply testnet URLs if enabled
-	cfg.GetBinanceTestnetURLs()
+	// Bracket slots are freed when the monitor sees an exit fill on the user
+	// data stream. Simulated fills never reach it, so a limit would lock
+	// symbols until restart.
+	if cfg.Trading.MaxBracketsPerSymbol > 0 && cfg.Trading.PaperTrading {
+		logger.Fatal().
+			Int("max_brackets_per_symbol", cfg.Trading.MaxBracketsPerSymbol).
+			Msg("MAX_BRACKETS_PER_SYMBOL is not supported with paper trading; set it to 0")
+	}
+
+	// Resolve every endpoint from the same environment
+	urls := cfg.ResolveBinanceURLs()
 
-	// Create Binance clients based on enabled trading modes
+	// Create Binance clients only for the enabled venues
 	var spotClient *binance.Client
 	var futuresClient *binance.Client
+	var wsClient *wsapi.Client
+	spotEnabled := cfg.IsVenueEnabled(config.VenueSpot)
+	futuresEnabled := cfg.IsVenueEnabled(config.VenueFutures)
 
-	if cfg.Binance.IsSpotEnabled() {
-		spotClient, err = binance.NewTestnetSpotClient(&cfg.Binance, logger.With().Str("client", "spot").Logger())
+	if spotEnabled {
+		spotClient, err = binance.NewSpotClient(&cfg.Binance, urls.SpotREST, logger.With().Str("client", "spot").Logger())
 		if err != nil {
 			logger.Fatal().Err(err).Msg("Failed to create spot client")
 		}
 		logger.Info().Msg("Spot trading enabled")
+
+		if cfg.Binance.UseWSAPI {
+			wsClient = wsapi.NewClient(
+				urls.WSAPI,
+				auth.NewSignerWithRecvWindow(cfg.Binance.SpotAPIKey, cfg.Binance.SpotSecretKey, cfg.Binance.RecvWindow),
+				wsapi.WithLogger(logger.With().Str("client", "ws_api").Logger()),
+			)
+			if err := wsClient.Connect(context.Background()); err != nil {
+				logger.Fatal().Err(err).Msg("Failed to connect to WebSocket API")
+			}
+			defer wsClient.Close()
+
+			spotClient.SetOrderPlacer(wsClient)
+			logger.Info().Str("url", urls.WSAPI).Msg("Spot orders will be placed via WebSocket API")
+		}
+
+		if cfg.Trading.PaperTrading {
+			simulator, marketData, err := startPaperTrading(context.Background(), cfg, urls.SpotWS, urls.SpotREST, spotClient,
+				logger.With().Str("client", "paper").Logger())
+			if err != nil {
+				logger.Fatal().Err(err).Msg("Failed to start paper trading")
+			}
+			defer marketData.Close()
+
+			// Cancels and order lookups must see the simulated orders too,
+			// never the live account's
+			spotClient.SetOrderPlacer(simulator)
+			spotClient.SetOrderStore(simulator)
+			logger.Warn().Strs("symbols", cfg.Trading.PaperSymbols).Msg("Paper trading: spot orders are simulated and never sent to Binance")
+		}
 	} else {
 		logger.Info().Msg("Spot trading disabled")
 	}
 
-	if cfg.Binance.IsFuturesEnabled() {
-		futuresClient, err = binance.NewTestnetFuturesClient(&cfg.Binance, logger.With().Str("client", "futures").Logger())
+	if futuresEnabled {
+		futuresClient, err = binance.NewFuturesClient(&cfg.Binance, urls.FuturesREST, logger.With().Str("client", "futures").Logger())
 		if err != nil {
 			logger.Fatal().Err(err).Msg("Failed to create futures client")
 		}
@@ -55,6 +102,12 @@ func main() {
 		logger.Info().Msg("Futures trading disabled")
 	}
 
+	// Build and verify named API key profiles
+	profiles, err := profileClients(context.Background(), cfg, urls, logger)
+	if err != nil {
+		logger.Fatal().Err(err).Msg("Failed to verify API key profiles")
+	}
+
 	// Create event emitter
 	var eventEmitter orders.EventEmitter
 	orderUpdateURL := os.Getenv("ORDER_UPDATE_URL")
@@ -66,11 +119,74 @@ func main() {
 		logger.Info().Msg("Order updates will be logged to console")
 	}
 
+	// Open the order audit log
+	var auditLogger *orders.AuditLogger
+	if cfg.Logging.AuditOutput != "" {
+		sink, err := openAuditSink(cfg.Logging.AuditOutput)
+		if err != nil {
+			logger.Fatal().Err(err).Msg("Failed to open audit log")
+		}
+		defer sink.Close()
+
+		auditLogger = orders.NewAuditLogger(sink, orders.DefaultAuditBufferSize, logger.With().Str("component", "audit").Logger())
+		defer auditLogger.Close()
+		logger.Info().Str("output", cfg.Logging.AuditOutput).Msg("Order operations will be audited")
+	}
+
 	// Create order manager
-	orderManager := orders.NewManager(spotClient, futuresClient, eventEmitter, logger)
+	managerOpts := []orders.ManagerOption{
+		orders.WithMaxBracketsPerSymbol(cfg.Trading.MaxBracketsPerSymbol),
+		orders.WithMaxTakeProfitLegs(cfg.Trading.MaxTakeProfitLegs),
+		orders.WithDailyNotionalCap(decimal.NewFromFloat(cfg.Trading.DailyNotionalCap), cfg.Trading.NotionalResetOffset),
+		orders.WithKillSwitchSymbols(cfg.Trading.KillSwitchSymbols...),
+	}
+	if auditLogger != nil {
+		managerOpts = append(managerOpts, orders.WithAuditLogger(auditLogger))
+	}
+	for _, profile := range cfg.Binance.Profiles {
+		if client, ok := profiles[profile.Name]; ok && !profile.ReadOnly {
+			managerOpts = append(managerOpts, orders.WithProfileClient(profile.Name, client))
+		}
+	}
+	orderManager := orders.NewManager(spotClient, futuresClient, eventEmitter, logger, managerOpts...)
+
+	// Drive brackets from the accounts' user data streams: OCO cancels,
+	// breakeven stops, slot release and account cache invalidation
+	monitor := orders.NewBracketMonitor(orderManager, logger.With().Str("component", "bracket_monitor").Logger())
+	userStreams, err := startUserStreams(context.Background(), cfg, urls, spotClient, futuresClient, profiles, monitor, logger)
+	if err != nil {
+		logger.Fatal().Err(err).Msg("Failed to start user data streams")
+	}
+	defer func() {
+		ctx, cancel := context.WithTimeout(context.Background(), listenKeyTimeout)
+		defer cancel()
+		for _, stream := range userStreams {
+			if err := stream.Close(ctx); err != nil {
+				logger.Warn().Err(err).Str("user_stream", stream.name).Msg("Failed to close user data stream")
+			}
+		}
+	}()
+
+	// Reload symbol rules well within their TTL so the read-only readiness
+	// check sees them fresh while Binance is reachable
+	refreshCtx, stopRefresh := context.WithCancel(context.Background())
+	defer stopRefresh()
+	go keepExchangeInfoFresh(refreshCtx, venueClients(spotClient, futuresClient),
+		cfg.Binance.ExchangeInfoCacheTTL/2, logger.With().Str("component", "exchange_info").Logger())
 
 	// Create HTTP handlers
-	handlers := api.NewHandlers(orderManager, logger)
+	venueResolver := orders.NewVenueResolver(
+		dataClient(cfg, profiles, false, spotClient),
+		dataClient(cfg, profiles, true, futuresClient),
+		cfg.Binance.ExchangeInfoCacheTTL)
+	handlerOpts := append([]api.HandlersOption{
+		api.WithVenues(spotEnabled, futuresEnabled),
+		api.WithVenueResolver(venueResolver),
+	}, readinessChecks(spotClient, futuresClient, wsClient)...)
+	if auditLogger != nil {
+		handlerOpts = append(handlerOpts, auditReadinessCheck(auditLogger))
+	}
+	handlers := api.NewHandlers(orderManager, logger, handlerOpts...)
 
 	// Create and configure HTTP server
 	mux := http.NewServeMux()
@@ -78,14 +194,16 @@ func main() {
 	// Register routes
 	mux.HandleFunc("/place_bracket", handlers.PlaceBracketHandler)
 	mux.HandleFunc("/cancel", handlers.CancelHandler)
+	mux.HandleFunc("/cancel_bracket", handlers.CancelBracketHandler)
 	mux.HandleFunc("/close_all", handlers.CloseAllHandler)
+	mux.HandleFunc("/kill_switch", handlers.KillSwitchHandler)
 	mux.HandleFunc("/healthz", handlers.HealthzHandler)
 	mux.HandleFunc("/readyz", handlers.ReadyzHandler)
 
 	// Create server
 	server := &http.Server{
 		Addr:         fmt.Sprintf(":%d", cfg.Server.Port),
-		Handler:      loggingMiddleware(mux),
+		Handler:      api.TraceMiddleware(loggingMiddleware(deadlineMiddleware(mux, cfg.Server.RequestTimeout))),
 		ReadTimeout:  cfg.Server.ReadTimeout,
 		WriteTimeout: cfg.Server.WriteTimeout,
 		IdleTimeout:  cfg.Server.IdleTimeout,
@@ -97,8 +215,8 @@ func main() {
 		logger.Info().
 			Str("addr", server.Addr).
 			Bool("testnet", cfg.Binance.Testnet).
-			Str("spot_url", cfg.Binance.BaseURL).
-			Str("futures_url", cfg.Binance.FuturesBaseURL).
+			Str("spot_url", urls.SpotREST).
+			Str("futures_url", urls.FuturesREST).
 			Msg("Order Router starting")
 		serverErrors <- server.ListenAndServe()
 	}()
@@ -129,6 +247,25 @@ func main() {
 	}
 }
 
+// openAuditSink opens the audit log destination. Files are opened for
+// append only, so existing entries are never rewritten.
+func openAuditSink(output string) (io.WriteCloser, error) {
+	switch output {
+	case "stdout":
+		return nopWriteCloser{os.Stdout}, nil
+	case "stderr":
+		return nopWriteCloser{os.Stderr}, nil
+	}
+	return os.OpenFile(output, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o600)
+}
+
+// nopWriteCloser leaves the standard streams open when the audit log closes
+type nopWriteCloser struct {
+	io.Writer
+}
+
+func (nopWriteCloser) Close() error { return nil }
+
 // loggingMiddleware logs HTTP requests
 func loggingMiddleware(next http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
@@ -140,16 +277,30 @@ func loggingMiddleware(next http.Handler) http.Handler {
 		next.ServeHTTP(wrapped, r)
 
 		duration := time.Since(start)
-		fmt.Printf("%s %s %s - %d - %v\n",
+		fmt.Printf("%s %s %s - %d - %v trace_id=%s\n",
 			r.Method,
 			r.URL.Path,
 			r.RemoteAddr,
 			wrapped.statusCode,
 			duration,
+			trace.ID(r.Context()),
 		)
 	})
 }
 
+// deadlineMiddleware bounds each request's context by timeout. The server's
+// write timeout alone does not cancel the context, so Binance calls would
+// keep running after the response could no longer be delivered. timeout must
+// be shorter than the write timeout so a 504 can still be written.
+func deadlineMiddleware(next http.Handler, timeout time.Duration) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ctx, cancel := context.WithTimeout(r.Context(), timeout)
+		defer cancel()
+
+		next.ServeHTTP(w, r.WithContext(ctx))
+	})
+}
+
 // responseWriter wraps http.ResponseWriter to capture status code
 type responseWriter struct {
 	http.ResponseWriter
@@ -0,0 +1,52 @@
+package main
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/rs/zerolog"
+	"router/internal/binance"
+	"router/internal/config"
+	"router/internal/paper"
+	"router/internal/rest"
+	"router/internal/websocket"
+)
+
+// startPaperTrading connects to market data for the configured paper symbols
+// and returns a simulated exchange fed by it. Each symbol's depth is kept in a
+// book synced from a restURL snapshot. Callers close the returned WebSocket
+// client on shutdown.
+func startPaperTrading(ctx context.Context, cfg *config.Config, wsURL, restURL string, spotClient *binance.Client, logger zerolog.Logger) (*paper.SimulatedExchange, *websocket.Client, error) {
+	balances, err := paper.ParseBalances(cfg.Trading.PaperBalances)
+	if err != nil {
+		return nil, nil, fmt.Errorf("invalid paper balances: %w", err)
+	}
+
+	simulator := paper.NewSimulatedExchange(
+		paper.WithBalances(balances),
+		paper.WithSymbolResolver(spotClient),
+		paper.WithLogger(logger),
+	)
+
+	marketData := websocket.NewClient(websocket.WithBaseURL(wsURL), websocket.WithLoggerClient(logger))
+	if err := marketData.Connect(ctx); err != nil {
+		return nil, nil, fmt.Errorf("failed to connect to market data: %w", err)
+	}
+
+	// Depth snapshots are public, so no signer is needed
+	snapshots := rest.NewClient(restURL, nil)
+	for _, symbol := range cfg.Trading.PaperSymbols {
+		book, err := marketData.MaintainDepthBook(ctx, symbol, snapshots)
+		if err != nil {
+			marketData.Close()
+			return nil, nil, fmt.Errorf("failed to maintain %s depth: %w", symbol, err)
+		}
+		simulator.SetDepthSource(symbol, book)
+		if err := marketData.SubscribeToTicker(ctx, symbol, simulator.HandleTicker); err != nil {
+			marketData.Close()
+			return nil, nil, fmt.Errorf("failed to subscribe to %s ticker: %w", symbol, err)
+		}
+	}
+
+	return simulator, marketData, nil
+}
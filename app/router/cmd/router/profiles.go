@@ -0,0 +1,51 @@
+package main
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/rs/zerolog"
+	"router/internal/binance"
+	"router/internal/config"
+)
+
+// profileClients builds a client for each API key profile on an enabled
+// venue and pings its account, so a rejected key or a trading profile
+// without trading permission fails startup instead of the first order
+func profileClients(ctx context.Context, cfg *config.Config, urls config.BinanceURLs, logger zerolog.Logger) (map[string]*binance.Client, error) {
+	clients := make(map[string]*binance.Client, len(cfg.Binance.Profiles))
+	for _, profile := range cfg.Binance.Profiles {
+		if !cfg.IsVenueEnabled(profile.Venue) {
+			logger.Warn().Str("profile", profile.Name).Str("venue", profile.Venue).Msg("Skipping API key profile for disabled venue")
+			continue
+		}
+
+		baseURL := urls.SpotREST
+		if profile.IsFutures() {
+			baseURL = urls.FuturesREST
+		}
+		client, err := binance.NewProfileClient(&cfg.Binance, profile, baseURL,
+			logger.With().Str("client", profile.Venue).Str("profile", profile.Name).Logger())
+		if err != nil {
+			return nil, fmt.Errorf("profile %q: %w", profile.Name, err)
+		}
+		if err := client.VerifyAccess(ctx, !profile.ReadOnly); err != nil {
+			return nil, fmt.Errorf("profile %q: %w", profile.Name, err)
+		}
+
+		clients[profile.Name] = client
+		logger.Info().Str("profile", profile.Name).Str("venue", profile.Venue).Bool("read_only", profile.ReadOnly).Msg("API key profile verified")
+	}
+	return clients, nil
+}
+
+// dataClient returns the first read-only profile's client for the venue, so
+// market data lookups stay off trading keys, or fallback when there is none
+func dataClient(cfg *config.Config, clients map[string]*binance.Client, isFutures bool, fallback *binance.Client) *binance.Client {
+	for _, profile := range cfg.Binance.Profiles {
+		if client, ok := clients[profile.Name]; ok && profile.ReadOnly && profile.IsFutures() == isFutures {
+			return client
+		}
+	}
+	return fallback
+}
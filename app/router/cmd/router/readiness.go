@@ -0,0 +1,110 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/rs/zerolog"
+	"router/internal/api"
+	"router/internal/binance"
+	"router/internal/orders"
+	"router/internal/wsapi"
+)
+
+// venueClients returns the clients of the enabled venues
+func venueClients(spotClient, futuresClient *binance.Client) []*binance.Client {
+	var clients []*binance.Client
+	for _, client := range []*binance.Client{spotClient, futuresClient} {
+		if client != nil {
+			clients = append(clients, client)
+		}
+	}
+	return clients
+}
+
+// readinessChecks builds the /readyz sub-checks for the enabled venues
+func readinessChecks(spotClient, futuresClient *binance.Client, wsClient *wsapi.Client) []api.HandlersOption {
+	clients := venueClients(spotClient, futuresClient)
+
+	opts := []api.HandlersOption{
+		api.WithReadinessCheck("binance_rest", func(ctx context.Context) (string, error) {
+			for _, client := range clients {
+				if err := client.Ping(ctx); err != nil {
+					return "", err
+				}
+			}
+			return "ok", nil
+		}),
+		api.WithReadinessCheck("rate_limiter", func(ctx context.Context) (string, error) {
+			for _, client := range clients {
+				if client.RateLimiterAvailable() < 1 {
+					return "", errors.New("throttled")
+				}
+			}
+			return "ok", nil
+		}),
+		// Only reads the cache age: keepExchangeInfoFresh does the fetching,
+		// so probes never add REST traffic
+		api.WithReadinessCheck("exchange_info", func(ctx context.Context) (string, error) {
+			for _, client := range clients {
+				if !client.ExchangeInfoFresh() {
+					return "", errors.New("stale")
+				}
+			}
+			return "fresh", nil
+		}),
+	}
+
+	if wsClient != nil {
+		opts = append(opts, api.WithReadinessCheck("binance_ws", func(ctx context.Context) (string, error) {
+			if !wsClient.Connected() {
+				return "", errors.New("disconnected")
+			}
+			return "connected", nil
+		}))
+	}
+
+	return opts
+}
+
+// auditReadinessCheck reports how many audit entries were dropped. Drops do
+// not fail readiness: the count only grows, so the instance would never
+// recover, but operators can see that the audit log is incomplete.
+func auditReadinessCheck(auditLogger *orders.AuditLogger) api.HandlersOption {
+	return api.WithReadinessCheck("audit_log", func(ctx context.Context) (string, error) {
+		if dropped := auditLogger.Dropped(); dropped > 0 {
+			return fmt.Sprintf("%d entries dropped", dropped), nil
+		}
+		return "ok", nil
+	})
+}
+
+// defaultExchangeInfoRefresh is the refresh interval used when none is configured
+const defaultExchangeInfoRefresh = 2 * time.Minute
+
+// keepExchangeInfoFresh loads symbol rules for clients right away and then
+// every interval until ctx is cancelled, keeping them fresh for readiness and
+// order validation
+func keepExchangeInfoFresh(ctx context.Context, clients []*binance.Client, interval time.Duration, logger zerolog.Logger) {
+	if interval <= 0 {
+		interval = defaultExchangeInfoRefresh
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		for _, client := range clients {
+			if err := client.RefreshExchangeInfo(ctx); err != nil && ctx.Err() == nil {
+				logger.Warn().Err(err).Bool("futures", client.IsFutures()).Msg("Failed to refresh exchange info")
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
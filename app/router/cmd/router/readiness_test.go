@@ -0,0 +1,77 @@
+package main
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"router/internal/api"
+	"router/internal/auth"
+	"router/internal/binance"
+	"router/internal/orders"
+	"router/internal/rest"
+	"router/internal/testutil"
+)
+
+func TestReadiness_ExchangeInfoIsReadOnly(t *testing.T) {
+	fake := testutil.NewFakeBinance(t)
+	fake.AddSymbol(testutil.BTCUSDT)
+
+	signer := auth.NewSigner("test-key", "test-secret")
+	spot, err := binance.NewClient(fake.URL(), signer, rest.NewClient(fake.URL(), signer, rest.WithMaxRetries(0)), zerolog.Nop())
+	require.NoError(t, err)
+
+	handlers := api.NewHandlers(nil, zerolog.Nop(), readinessChecks(spot, nil, nil)...)
+	probe := func() *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		handlers.ReadyzHandler(w, httptest.NewRequest(http.MethodGet, "/readyz", nil))
+		return w
+	}
+
+	// Probes report the stale cache without fetching it
+	for i := 0; i < 3; i++ {
+		w := probe()
+		assert.Equal(t, http.StatusServiceUnavailable, w.Code)
+		assert.Contains(t, w.Body.String(), "stale")
+	}
+	assert.Zero(t, fake.RequestCount("/api/v3/exchangeInfo"))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go keepExchangeInfoFresh(ctx, venueClients(spot, nil), time.Minute, zerolog.Nop())
+
+	require.Eventually(t, func() bool {
+		return strings.Contains(probe().Body.String(), `"exchange_info":"fresh"`)
+	}, 2*time.Second, 10*time.Millisecond)
+	assert.Equal(t, 1, fake.RequestCount("/api/v3/exchangeInfo"))
+}
+
+func TestReadiness_AuditLogReportsDroppedEntries(t *testing.T) {
+	auditLogger := orders.NewAuditLogger(io.Discard, 1, zerolog.Nop())
+	handlers := api.NewHandlers(nil, zerolog.Nop(), auditReadinessCheck(auditLogger))
+	probe := func() *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		handlers.ReadyzHandler(w, httptest.NewRequest(http.MethodGet, "/readyz", nil))
+		return w
+	}
+
+	w := probe()
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), `"audit_log":"ok"`)
+
+	// Entries recorded after Close are dropped
+	auditLogger.Close()
+	auditLogger.Record(orders.AuditEntry{Operation: orders.AuditOpCancelOrder})
+	auditLogger.Record(orders.AuditEntry{Operation: orders.AuditOpPlaceStopLoss})
+
+	w = probe()
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), `"audit_log":"2 entries dropped"`)
+}
@@ -0,0 +1,189 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/rs/zerolog"
+	"router/internal/binance"
+	"router/internal/config"
+	"router/internal/orders"
+	"router/internal/websocket"
+)
+
+const (
+	// listenKeyKeepAlive is how often listen keys are extended; Binance
+	// closes a user stream whose key goes 60 minutes without one
+	listenKeyKeepAlive = 30 * time.Minute
+	// listenKeyTimeout bounds each listen key request
+	listenKeyTimeout = 10 * time.Second
+)
+
+// userStream keeps one account's user data stream open and feeds its order
+// and account updates to the bracket monitor
+type userStream struct {
+	name    string
+	client  *binance.Client
+	ws      *websocket.Client
+	handler *websocket.UserDataHandler
+	logger  zerolog.Logger
+
+	mu        sync.Mutex // serializes renewals
+	listenKey string
+
+	cancel context.CancelFunc
+	done   chan struct{}
+}
+
+// startUserStreams opens a user data stream for every account orders can be
+// placed from: the enabled venues and the trading API key profiles. Spot is
+// skipped in paper trading, whose simulated orders never reach the account.
+func startUserStreams(ctx context.Context, cfg *config.Config, urls config.BinanceURLs, spotClient, futuresClient *binance.Client,
+	profiles map[string]*binance.Client, monitor *orders.BracketMonitor, logger zerolog.Logger) ([]*userStream, error) {
+	type account struct {
+		name   string
+		client *binance.Client
+	}
+	var accounts []account
+	if spotClient != nil && !cfg.Trading.PaperTrading {
+		accounts = append(accounts, account{"spot", spotClient})
+	}
+	if futuresClient != nil {
+		accounts = append(accounts, account{"futures", futuresClient})
+	}
+	for _, profile := range cfg.Binance.Profiles {
+		if client, ok := profiles[profile.Name]; ok && !profile.ReadOnly {
+			accounts = append(accounts, account{"profile " + profile.Name, client})
+		}
+	}
+
+	var streams []*userStream
+	for _, a := range accounts {
+		wsURL := urls.SpotWS
+		if a.client.IsFutures() {
+			wsURL = urls.FuturesWS
+		}
+		stream, err := startUserStream(ctx, a.name, a.client, wsURL, monitor,
+			logger.With().Str("user_stream", a.name).Logger())
+		if err != nil {
+			for _, started := range streams {
+				started.Close(ctx)
+			}
+			return nil, err
+		}
+		streams = append(streams, stream)
+	}
+	return streams, nil
+}
+
+// startUserStream creates a listen key for client, subscribes to its stream
+// at wsURL and keeps the key alive until Close
+func startUserStream(ctx context.Context, name string, client *binance.Client, wsURL string, monitor *orders.BracketMonitor, logger zerolog.Logger) (*userStream, error) {
+	s := &userStream{
+		name:   name,
+		client: client,
+		ws:     websocket.NewClient(websocket.WithBaseURL(wsURL), websocket.WithLoggerClient(logger)),
+		logger: logger,
+		done:   make(chan struct{}),
+	}
+	s.handler = &websocket.UserDataHandler{
+		OnAccountUpdate:        monitor.HandleAccountUpdate,
+		OnOrderUpdate:          monitor.HandleOrderUpdate,
+		OnFuturesOrderUpdate:   monitor.HandleFuturesOrderUpdate,
+		OnFuturesAccountUpdate: monitor.HandleFuturesAccountUpdate,
+		OnListenKeyExpired: func() error {
+			// Renewing replaces the connection this event arrived on
+			go s.renew("listen key expired")
+			return nil
+		},
+	}
+
+	if err := s.subscribe(ctx); err != nil {
+		s.ws.Close()
+		return nil, err
+	}
+
+	loopCtx, cancel := context.WithCancel(context.Background())
+	s.cancel = cancel
+	go s.keepAlive(loopCtx)
+
+	logger.Info().Msg("User data stream started")
+	return s, nil
+}
+
+// subscribe creates a listen key and moves the stream onto it. Callers must
+// not hold s.mu.
+func (s *userStream) subscribe(ctx context.Context) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	listenKey, err := s.client.CreateListenKey(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to create %s listen key: %w", s.name, err)
+	}
+	if err := s.ws.SubscribeToUserData(ctx, listenKey, s.handler); err != nil {
+		return fmt.Errorf("failed to subscribe to %s user data stream: %w", s.name, err)
+	}
+
+	if old := s.listenKey; old != "" && old != listenKey {
+		s.ws.UnsubscribeFromUserData(ctx, old)
+	}
+	s.listenKey = listenKey
+	return nil
+}
+
+// renew replaces the listen key after it expired or a keepalive failed
+func (s *userStream) renew(reason string) {
+	ctx, cancel := context.WithTimeout(context.Background(), listenKeyTimeout)
+	defer cancel()
+
+	if err := s.subscribe(ctx); err != nil {
+		s.logger.Error().Err(err).Str("reason", reason).Msg("Failed to renew user data stream")
+		return
+	}
+	s.logger.Warn().Str("reason", reason).Msg("User data stream renewed")
+}
+
+// keepAlive extends the listen key until ctx is cancelled, renewing it when
+// Binance no longer accepts it
+func (s *userStream) keepAlive(ctx context.Context) {
+	defer close(s.done)
+
+	ticker := time.NewTicker(listenKeyKeepAlive)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		s.mu.Lock()
+		listenKey := s.listenKey
+		s.mu.Unlock()
+
+		reqCtx, cancel := context.WithTimeout(ctx, listenKeyTimeout)
+		err := s.client.KeepAliveListenKey(reqCtx, listenKey)
+		cancel()
+		if err != nil && ctx.Err() == nil {
+			s.renew(fmt.Sprintf("keepalive failed: %v", err))
+		}
+	}
+}
+
+// Close stops the keepalive, closes the connection and ends the listen key
+func (s *userStream) Close(ctx context.Context) error {
+	if s.cancel != nil {
+		s.cancel()
+		<-s.done
+	}
+	s.ws.Close()
+
+	s.mu.Lock()
+	listenKey := s.listenKey
+	s.mu.Unlock()
+	return s.client.CloseListenKey(ctx, listenKey)
+}
@@ -0,0 +1,65 @@
+package main
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/rs/zerolog"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"router/internal/auth"
+	"router/internal/binance"
+	"router/internal/orders"
+	"router/internal/rest"
+	"router/internal/testutil"
+)
+
+func TestUserStream_DrivesBracketMonitor(t *testing.T) {
+	ctx := context.Background()
+	fake := testutil.NewFakeBinance(t)
+	fake.AddSymbol(testutil.BTCUSDT)
+
+	signer := auth.NewSigner("test-key", "test-secret")
+	spot, err := binance.NewClient(fake.URL(), signer, rest.NewClient(fake.URL(), signer, rest.WithMaxRetries(0)), zerolog.Nop())
+	require.NoError(t, err)
+
+	manager := orders.NewManager(spot, nil, nil, zerolog.Nop(), orders.WithMaxBracketsPerSymbol(1))
+	monitor := orders.NewBracketMonitor(manager, zerolog.Nop())
+
+	stream, err := startUserStream(ctx, "spot", spot, fake.WSURL(), monitor, zerolog.Nop())
+	require.NoError(t, err)
+	require.Eventually(t, func() bool { return fake.WSClientCount() == 1 }, time.Second, 5*time.Millisecond)
+
+	resp, err := manager.PlaceBracketOrder(ctx, &orders.PlaceBracketRequest{
+		Symbol:           "BTCUSDT",
+		Side:             "BUY",
+		Quantity:         decimal.RequireFromString("0.001"),
+		EntryPrice:       decimal.NewFromInt(50000),
+		TakeProfitPrices: []decimal.Decimal{decimal.NewFromInt(51000)},
+		StopLossPrice:    decimal.NewFromInt(49000),
+		OrderType:        "LIMIT",
+	})
+	require.NoError(t, err)
+	require.Equal(t, 1, manager.OpenBrackets("BTCUSDT"))
+
+	// The stop loss fill arrives over the stream: the take profit is
+	// cancelled and the symbol's slot freed
+	require.True(t, fake.FillOrder(resp.ClientOrderIDs.StopLoss))
+	require.Eventually(t, func() bool {
+		return manager.OpenBrackets("BTCUSDT") == 0
+	}, 2*time.Second, 10*time.Millisecond)
+
+	require.Eventually(t, func() bool {
+		for _, order := range fake.Orders() {
+			if order.ClientOrderID() == resp.ClientOrderIDs.TakeProfits[0] {
+				return order.Status == "CANCELED"
+			}
+		}
+		return false
+	}, 2*time.Second, 10*time.Millisecond)
+
+	require.NoError(t, stream.Close(ctx))
+	assert.Equal(t, 2, fake.RequestCount("/api/v3/userDataStream"), "listen key created and closed")
+}
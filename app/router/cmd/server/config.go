@@ -1,28 +1,45 @@
 package main
 
 import (
+	"errors"
 	"fmt"
 	"os"
 	"strconv"
 	"strings"
 	"time"
+
+	"gopkg.in/yaml.v3"
+	routerconfig "router/internal/config"
+	"router/internal/websocket"
 )
 
 // Config holds application configuration
 type Config struct {
-	Port           int
-	APIKey         string
-	RateLimit      int
-	MaxConnections int
-	LogLevel       string
-	CORSOrigins    []string
-	ReadTimeout    time.Duration
-	WriteTimeout   time.Duration
-	IdleTimeout    time.Duration
-	Version        string
+	Port           int           `yaml:"port"`
+	APIKey         string        `yaml:"api_key"`
+	RateLimit      int           `yaml:"rate_limit"`
+	MaxConnections int           `yaml:"max_connections"`
+	LogLevel       string        `yaml:"log_level"`
+	CORSOrigins    []string      `yaml:"cors_origins"`
+	ReadTimeout    time.Duration `yaml:"read_timeout"`
+	WriteTimeout   time.Duration `yaml:"write_timeout"`
+	IdleTimeout    time.Duration `yaml:"idle_timeout"`
+	// StreamStatePath, when set, persists streams and their subscriptions so
+	// they are replayed after a restart
+	StreamStatePath string `yaml:"stream_state_path"`
+	// BinanceAPIKey, when set, creates fresh listen keys for user streams
+	// restored from StreamStatePath. It is read from the environment only.
+	BinanceAPIKey  string `yaml:"-"`
+	BinanceRESTURL string `yaml:"binance_rest_url"`
+	BinanceWSURL   string `yaml:"binance_ws_url"`
+	// BinanceTestnet switches the REST and stream endpoints to testnet
+	// together, overriding BinanceRESTURL and BinanceWSURL
+	BinanceTestnet bool   `yaml:"binance_testnet"`
+	Version        string `yaml:"-"`
 }
 
-// LoadConfig loads configuration from environment variables
+// LoadConfig loads configuration from the optional CONFIG_PATH file (YAML or
+// JSON), then overrides it with environment variables
 func LoadConfig() (*Config, error) {
 	config := &Config{
 		// Default values
@@ -33,9 +50,22 @@ func LoadConfig() (*Config, error) {
 		ReadTimeout:    30 * time.Second,
 		WriteTimeout:   30 * time.Second,
 		IdleTimeout:    60 * time.Second,
+		BinanceRESTURL: "https://api.binance.com",
+		BinanceWSURL:   websocket.DefaultBaseURL,
 		Version:        getVersion(),
 	}
 
+	// Overlay the config file, if any
+	if path := os.Getenv("CONFIG_PATH"); path != "" {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read config file %s: %w", path, err)
+		}
+		if err := yaml.Unmarshal(data, config); err != nil {
+			return nil, fmt.Errorf("failed to parse config file %s: %w", path, err)
+		}
+	}
+
 	// Load from environment
 	if portStr := os.Getenv("PORT"); portStr != "" {
 		port, err := strconv.Atoi(portStr)
@@ -45,7 +75,9 @@ func LoadConfig() (*Config, error) {
 		config.Port = port
 	}
 
-	config.APIKey = os.Getenv("API_KEY")
+	if apiKey := os.Getenv("API_KEY"); apiKey != "" {
+		config.APIKey = apiKey
+	}
 	if config.APIKey == "" {
 		return nil, fmt.Errorf("API_KEY environment variable is required")
 	}
@@ -78,6 +110,28 @@ func LoadConfig() (*Config, error) {
 		}
 	}
 
+	if statePath := os.Getenv("STREAM_STATE_PATH"); statePath != "" {
+		config.StreamStatePath = statePath
+	}
+	if binanceKey := os.Getenv("BINANCE_API_KEY"); binanceKey != "" {
+		config.BinanceAPIKey = binanceKey
+	}
+	if restURL := os.Getenv("BINANCE_REST_URL"); restURL != "" {
+		config.BinanceRESTURL = restURL
+	}
+	if wsURL := os.Getenv("WEBSOCKET_URL"); wsURL != "" {
+		config.BinanceWSURL = wsURL
+	}
+	for _, key := range []string{"BINANCE_TESTNET", "USE_TESTNET"} {
+		if testnetStr := os.Getenv(key); testnetStr != "" {
+			testnet, err := strconv.ParseBool(testnetStr)
+			if err != nil {
+				return nil, fmt.Errorf("invalid %s value: %v", key, err)
+			}
+			config.BinanceTestnet = testnet
+		}
+	}
+
 	// Parse timeout values
 	if readTimeout := os.Getenv("READ_TIMEOUT"); readTimeout != "" {
 		seconds, err := strconv.Atoi(readTimeout)
@@ -104,29 +158,62 @@ func LoadConfig() (*Config, error) {
 	}
 
 	// Validate configuration
-	if err := ValidateConfig(config); err != nil {
+	if err := config.Validate(); err != nil {
 		return nil, err
 	}
 
 	return config, nil
 }
 
+// BinanceURLs resolves the Binance endpoints the same way the router does, so
+// a testnet run never mixes in mainnet URLs
+func (c *Config) BinanceURLs() routerconfig.BinanceURLs {
+	resolved := routerconfig.Config{Binance: routerconfig.BinanceConfig{
+		Testnet:   c.BinanceTestnet,
+		BaseURL:   c.BinanceRESTURL,
+		WSBaseURL: c.BinanceWSURL,
+	}}
+	return resolved.ResolveBinanceURLs()
+}
+
 // ValidateConfig validates the configuration
 func ValidateConfig(config *Config) error {
-	if config.Port <= 0 || config.Port > 65535 {
-		return fmt.Errorf("invalid port number: %d", config.Port)
+	return config.Validate()
+}
+
+// Validate checks every field and returns all problems as a single error
+func (c *Config) Validate() error {
+	var errs []error
+
+	if c.Port <= 0 || c.Port > 65535 {
+		errs = append(errs, fmt.Errorf("invalid port number: %d", c.Port))
 	}
 
-	if config.APIKey == "" {
-		return fmt.Errorf("API key is required")
+	if c.APIKey == "" {
+		errs = append(errs, fmt.Errorf("API key is required"))
 	}
 
-	if config.RateLimit < 0 {
-		return fmt.Errorf("rate limit must be non-negative")
+	if c.RateLimit <= 0 {
+		errs = append(errs, fmt.Errorf("rate limit must be positive, got %d", c.RateLimit))
 	}
 
-	if config.MaxConnections < 0 {
-		return fmt.Errorf("max connections must be non-negative")
+	if c.MaxConnections < 0 {
+		errs = append(errs, fmt.Errorf("max connections must be non-negative"))
+	}
+
+	// A zero timeout disables the corresponding http.Server protection
+	timeouts := []struct {
+		name  string
+		value time.Duration
+	}{
+		{"read timeout", c.ReadTimeout},
+		{"write timeout", c.WriteTimeout},
+		{"idle timeout", c.IdleTimeout},
+	}
+	for _, timeout := range timeouts {
+		if timeout.value <= 0 {
+			errs = append(errs, fmt.Errorf("%s must be positive, got %s", timeout.name, timeout.value))
+		}
 	}
 
 	// Validate log level
@@ -137,16 +224,28 @@ func ValidateConfig(config *Config) error {
 		"error": true,
 	}
 
-	if !validLogLevels[config.LogLevel] {
-		return fmt.Errorf("invalid log level: %s", config.LogLevel)
+	if !validLogLevels[c.LogLevel] {
+		errs = append(errs, fmt.Errorf("invalid log level: %s", c.LogLevel))
 	}
 
-	return nil
+	return errors.Join(errs...)
 }
 
-// getVersion returns the application version
+// Build information, injected at link time:
+//
+//	go build -ldflags "-X main.Version=1.2.0 -X main.GitCommit=$(git rev-parse HEAD) -X main.BuildTime=$(date -u +%FT%TZ)"
+var (
+	Version   string
+	GitCommit string
+	BuildTime string
+)
+
+// getVersion returns the application version: the linked-in Version, then
+// the VERSION environment variable, then a default
 func getVersion() string {
-	// Could be set via build flags: -ldflags "-X main.Version=1.0.0"
+	if Version != "" {
+		return Version
+	}
 	if version := os.Getenv("VERSION"); version != "" {
 		return version
 	}
@@ -100,6 +100,56 @@ func TestLoadConfig(t *testing.T) {
 	})
 }
 
+func TestConfig_BinanceURLs(t *testing.T) {
+	t.Run("defaults to mainnet", func(t *testing.T) {
+		t.Setenv("API_KEY", "test-key")
+
+		config, err := LoadConfig()
+		require.NoError(t, err)
+
+		urls := config.BinanceURLs()
+		assert.Equal(t, "https://api.binance.com", urls.SpotREST)
+		assert.Equal(t, "wss://stream.binance.com:9443", urls.SpotWS)
+	})
+
+	t.Run("keeps configured mainnet overrides", func(t *testing.T) {
+		t.Setenv("API_KEY", "test-key")
+		t.Setenv("BINANCE_REST_URL", "http://localhost:9000")
+		t.Setenv("WEBSOCKET_URL", "ws://localhost:9001")
+
+		config, err := LoadConfig()
+		require.NoError(t, err)
+
+		urls := config.BinanceURLs()
+		assert.Equal(t, "http://localhost:9000", urls.SpotREST)
+		assert.Equal(t, "ws://localhost:9001", urls.SpotWS)
+	})
+
+	t.Run("testnet switches REST and streams together", func(t *testing.T) {
+		t.Setenv("API_KEY", "test-key")
+		t.Setenv("BINANCE_TESTNET", "true")
+		// A stray mainnet override must not leak into a testnet run
+		t.Setenv("BINANCE_REST_URL", "https://api.binance.com")
+
+		config, err := LoadConfig()
+		require.NoError(t, err)
+		assert.True(t, config.BinanceTestnet)
+
+		urls := config.BinanceURLs()
+		assert.Equal(t, "https://testnet.binance.vision", urls.SpotREST)
+		assert.Equal(t, "wss://testnet.binance.vision", urls.SpotWS)
+	})
+
+	t.Run("rejects an invalid testnet flag", func(t *testing.T) {
+		t.Setenv("API_KEY", "test-key")
+		t.Setenv("USE_TESTNET", "maybe")
+
+		_, err := LoadConfig()
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "USE_TESTNET")
+	})
+}
+
 func TestValidateConfig(t *testing.T) {
 	t.Run("validates required fields", func(t *testing.T) {
 		config := &Config{
@@ -142,13 +192,72 @@ func TestValidateConfig(t *testing.T) {
 
 	t.Run("accepts valid config", func(t *testing.T) {
 		config := &Config{
-			Port:      8080,
-			APIKey:    "test-key",
-			RateLimit: 100,
-			LogLevel:  "info",
+			Port:         8080,
+			APIKey:       "test-key",
+			RateLimit:    100,
+			LogLevel:     "info",
+			ReadTimeout:  30 * time.Second,
+			WriteTimeout: 30 * time.Second,
+			IdleTimeout:  60 * time.Second,
 		}
 
 		err := ValidateConfig(config)
 		assert.NoError(t, err)
 	})
 }
+
+func TestConfig_Validate(t *testing.T) {
+	validConfig := func() *Config {
+		return &Config{
+			Port:         8080,
+			APIKey:       "test-key",
+			RateLimit:    100,
+			LogLevel:     "info",
+			ReadTimeout:  30 * time.Second,
+			WriteTimeout: 30 * time.Second,
+			IdleTimeout:  60 * time.Second,
+		}
+	}
+
+	testCases := []struct {
+		name     string
+		mutate   func(c *Config)
+		expected string
+	}{
+		{"zero read timeout", func(c *Config) { c.ReadTimeout = 0 }, "read timeout must be positive"},
+		{"negative write timeout", func(c *Config) { c.WriteTimeout = -time.Second }, "write timeout must be positive"},
+		{"zero idle timeout", func(c *Config) { c.IdleTimeout = 0 }, "idle timeout must be positive"},
+		{"zero rate limit", func(c *Config) { c.RateLimit = 0 }, "rate limit must be positive"},
+		{"negative rate limit", func(c *Config) { c.RateLimit = -5 }, "rate limit must be positive"},
+		{"zero port", func(c *Config) { c.Port = 0 }, "invalid port number"},
+		{"port too large", func(c *Config) { c.Port = 65536 }, "invalid port number"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			config := validConfig()
+			tc.mutate(config)
+
+			err := config.Validate()
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tc.expected)
+		})
+	}
+
+	t.Run("combines multiple errors", func(t *testing.T) {
+		config := validConfig()
+		config.Port = 0
+		config.RateLimit = 0
+		config.ReadTimeout = 0
+
+		err := config.Validate()
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "invalid port number")
+		assert.Contains(t, err.Error(), "rate limit must be positive")
+		assert.Contains(t, err.Error(), "read timeout must be positive")
+	})
+
+	t.Run("accepts valid config", func(t *testing.T) {
+		assert.NoError(t, validConfig().Validate())
+	})
+}
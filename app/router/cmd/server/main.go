@@ -10,6 +10,9 @@ import (
 	"github.com/rs/zerolog"
 	"github.com/rs/zerolog/log"
 	"router/internal/api"
+	"router/internal/auth"
+	"router/internal/metrics"
+	"router/internal/rest"
 	"router/internal/websocket"
 )
 
@@ -24,12 +27,16 @@ func main() {
 		log.Fatal().Err(err).Msg("Failed to load configuration")
 	}
 
+	urls := config.BinanceURLs()
+
 	// Log configuration
 	log.Info().
 		Int("port", config.Port).
 		Str("version", config.Version).
+		Str("git_commit", GitCommit).
 		Str("log_level", config.LogLevel).
 		Int("rate_limit", config.RateLimit).
+		Bool("binance_testnet", config.BinanceTestnet).
 		Msg("Starting router service")
 
 	// Create server configuration
@@ -41,6 +48,8 @@ func main() {
 		MaxHeaderBytes: 1 << 20, // 1 MB
 		APIKey:         config.APIKey,
 		Version:        config.Version,
+		GitCommit:      GitCommit,
+		BuildTime:      BuildTime,
 		RateLimit:      config.RateLimit,
 		RateWindow:     time.Second,
 		CORSOrigins:    config.CORSOrigins,
@@ -53,18 +62,33 @@ func main() {
 		log.Fatal().Err(err).Msg("Failed to create server")
 	}
 
-	// Initialize WebSocket client (from Phase 3)
-	wsClient, err := initializeWebSocketClient(config)
-	if err != nil {
-		log.Fatal().Err(err).Msg("Failed to initialize WebSocket client")
-	}
+	// Shared by the WebSocket client and the /metrics endpoint
+	collector := metrics.NewCollector()
+
+	// Every stream created over HTTP gets its own client from this factory
+	newStreamClient := streamClientFactory(urls.SpotWS, collector)
+	wsClient := newStreamClient()
 
 	// Create manager implementations that bridge WebSocket to HTTP
-	streamManager := NewStreamManagerImpl(wsClient)
-	subscriptionManager := NewSubscriptionManagerImpl(wsClient)
+	streamManager := NewStreamManagerImpl(newStreamClient)
+	subscriptionManager := NewSubscriptionManagerImpl(streamManager)
+	if config.StreamStatePath != "" {
+		streamManager.SetStore(NewFileStreamStore(config.StreamStatePath))
+		if config.BinanceAPIKey != "" {
+			// Listen key endpoints need only the API key header
+			restClient := rest.NewClient(urls.SpotREST, auth.NewSigner(config.BinanceAPIKey, ""))
+			streamManager.SetListenKeySource(restClient.CreateListenKey)
+		}
+		restored, err := streamManager.Restore()
+		if err != nil {
+			log.Fatal().Err(err).Msg("Failed to restore streams")
+		}
+		log.Info().Int("streams", restored).Str("path", config.StreamStatePath).Msg("Restored persisted streams")
+	}
 	configManager := NewConfigManagerImpl(config)
+	configManager.SetApplier(server)
 	readinessChecker := NewReadinessCheckerImpl(wsClient)
-	metricsCollector := NewMetricsCollectorImpl(wsClient)
+	metricsCollector := NewMetricsCollectorImpl(wsClient, collector)
 
 	// Set dependencies
 	server.SetDependencies(
@@ -86,45 +110,57 @@ func main() {
 	shutdown := make(chan os.Signal, 1)
 	signal.Notify(shutdown, os.Interrupt, syscall.SIGTERM)
 
-	// Wait for shutdown signal or server error
-	select {
-	case err := <-serverErrors:
-		if err != nil {
-			log.Error().Err(err).Msg("Server error")
-		}
-	case sig := <-shutdown:
-		log.Info().Str("signal", sig.String()).Msg("Shutdown signal received")
-
-		// Create shutdown context with timeout
-		ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
-		defer cancel()
+	// SIGHUP reloads the settings that can change without a restart
+	reload := make(chan os.Signal, 1)
+	signal.Notify(reload, syscall.SIGHUP)
 
-		// Gracefully shutdown server
-		if err := server.Shutdown(ctx); err != nil {
-			log.Error().Err(err).Msg("Failed to shutdown server gracefully")
-		}
-
-		// Close WebSocket client
-		if err := wsClient.Close(); err != nil {
-			log.Error().Err(err).Msg("Failed to close WebSocket client")
+	// Wait for shutdown signal or server error
+	for {
+		select {
+		case <-reload:
+			log.Info().Msg("Reload signal received")
+			if err := reloadConfig(config, configManager); err != nil {
+				log.Error().Err(err).Msg("Failed to reload configuration")
+			}
+		case err := <-serverErrors:
+			if err != nil {
+				log.Error().Err(err).Msg("Server error")
+			}
+			return
+		case sig := <-shutdown:
+			log.Info().Str("signal", sig.String()).Msg("Shutdown signal received")
+
+			// Create shutdown context with timeout
+			ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+
+			// Gracefully shutdown server
+			if err := server.Shutdown(ctx); err != nil {
+				log.Error().Err(err).Msg("Failed to shutdown server gracefully")
+			}
+			cancel()
+
+			// Close WebSocket clients
+			closed := streamManager.CloseAll()
+			log.Info().Int("streams", closed).Msg("Closed streams")
+			if err := wsClient.Close(); err != nil {
+				log.Error().Err(err).Msg("Failed to close WebSocket client")
+			}
+
+			log.Info().Msg("Shutdown complete")
+			return
 		}
-
-		log.Info().Msg("Shutdown complete")
 	}
 }
 
-// initializeWebSocketClient creates and configures the WebSocket client
-func initializeWebSocketClient(config *Config) (*websocket.Client, error) {
-	// Get WebSocket URL from environment or use default
-	wsURL := os.Getenv("WEBSOCKET_URL")
-	if wsURL == "" {
-		wsURL = "wss://stream.binance.com:9443/ws"
-	}
-
-	// For Phase 4, we'll create a placeholder client
-	// In production, this would be properly integrated with the WebSocket implementation
-	client := websocket.NewClient()
-
+// streamClientFactory returns a factory for WebSocket clients that connect to
+// wsURL and report to collector
+func streamClientFactory(wsURL string, collector *metrics.Collector) ClientFactory {
 	log.Info().Str("url", wsURL).Msg("WebSocket client initialized")
-	return client, nil
+	return func() *websocket.Client {
+		return websocket.NewClient(
+			websocket.WithBaseURL(wsURL),
+			websocket.WithMetricsClient(collector),
+			websocket.WithLoggerClient(log.With().Str("component", "websocket").Logger()),
+		)
+	}
 }
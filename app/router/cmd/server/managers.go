@@ -1,89 +1,575 @@
 package main
 
 import (
+	"context"
 	"fmt"
+	"sort"
+	"strings"
+	"sync"
 	"time"
 
+	"github.com/rs/zerolog/log"
 	"router/internal/metrics"
 	"router/internal/models"
 	"router/internal/websocket"
 )
 
-// StreamManagerImpl implements handlers.StreamManager
-type StreamManagerImpl struct {
-	client *websocket.Client
+// streamConnectTimeout bounds connecting and subscribing on behalf of a
+// single HTTP request
+const streamConnectTimeout = 10 * time.Second
+
+// ClientFactory creates the WebSocket client backing a new stream
+type ClientFactory func() *websocket.Client
+
+// ListenKeySource creates a fresh listen key for a restored user stream
+type ListenKeySource func(ctx context.Context) (string, error)
+
+// managedStream is a live WebSocket client registered under a stream ID
+type managedStream struct {
+	id         string
+	seq        uint64
+	streamType string
+	createdAt  time.Time
+
+	mu            sync.Mutex
+	client        *websocket.Client
+	subscriptions []string // public stream names, e.g. btcusdt@depth
+	subscribedAt  map[string]time.Time
+	listenKey     string
+
+	// Relays depth and ticker events to HTTP subscribers
+	events eventFanout
 }
 
-// NewStreamManagerImpl creates a new stream manager
-func NewStreamManagerImpl(client *websocket.Client) *StreamManagerImpl {
-	return &StreamManagerImpl{client: client}
+// connect opens a public stream's connection and subscribes to every stream
+// it carried before. Callers hold s.mu.
+func (s *managedStream) connect(ctx context.Context) error {
+	if s.streamType != "public" {
+		if s.listenKey == "" {
+			return nil
+		}
+		if err := s.client.SubscribeToUserData(ctx, s.listenKey, &websocket.UserDataHandler{}); err != nil {
+			return fmt.Errorf("connection failed: %w", err)
+		}
+		return nil
+	}
+
+	if err := s.client.Connect(ctx); err != nil {
+		return fmt.Errorf("connection failed: %w", err)
+	}
+	for _, name := range s.subscriptions {
+		symbol, kind, err := parseStreamName(name)
+		if err != nil {
+			return err
+		}
+		if err := s.subscribe(ctx, symbol, kind); err != nil {
+			return fmt.Errorf("failed to subscribe to %s: %w", name, err)
+		}
+	}
+	return nil
 }
 
-func (m *StreamManagerImpl) CreateStream(streamType string, subscriptions []string) (*models.StreamResponse, error) {
-	// Map to WebSocket stream creation
-	// This is a simplified implementation - in production, you'd track streams properly
-	return &models.StreamResponse{
-		ID:            fmt.Sprintf("stream-%d", time.Now().Unix()),
-		Type:          streamType,
-		Status:        "connected",
-		CreatedAt:     time.Now(),
-		Subscriptions: subscriptions,
-	}, nil
+// addSubscription subscribes to name unless the stream already carries it.
+// Callers hold s.mu.
+func (s *managedStream) addSubscription(ctx context.Context, name string) error {
+	if _, exists := s.subscribedAt[name]; exists {
+		return fmt.Errorf("already subscribed to %s", name)
+	}
+	symbol, kind, err := parseStreamName(name)
+	if err != nil {
+		return err
+	}
+	if err := s.subscribe(ctx, symbol, kind); err != nil {
+		return fmt.Errorf("failed to subscribe to %s: %w", name, err)
+	}
+	s.subscriptions = append(s.subscriptions, name)
+	s.subscribedAt[name] = time.Now()
+	return nil
 }
 
-func (m *StreamManagerImpl) GetStream(id string) (*models.StreamResponse, error) {
-	// In production, you'd look up the stream from a registry
+// removeSubscription unsubscribes from name. Callers hold s.mu.
+func (s *managedStream) removeSubscription(ctx context.Context, name string) error {
+	if _, exists := s.subscribedAt[name]; !exists {
+		return fmt.Errorf("subscription %s not found", name)
+	}
+	symbol, kind, err := parseStreamName(name)
+	if err != nil {
+		return err
+	}
+
+	switch kind {
+	case "depth":
+		err = s.client.UnsubscribeFromDepth(ctx, symbol)
+	case "ticker":
+		err = s.client.UnsubscribeFromTicker(ctx, symbol)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to unsubscribe from %s: %w", name, err)
+	}
+
+	delete(s.subscribedAt, name)
+	for i, existing := range s.subscriptions {
+		if existing == name {
+			s.subscriptions = append(s.subscriptions[:i], s.subscriptions[i+1:]...)
+			break
+		}
+	}
+	return nil
+}
+
+// record snapshots the stream for persistence
+func (s *managedStream) record() StreamRecord {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return StreamRecord{
+		ID:            s.id,
+		Type:          s.streamType,
+		CreatedAt:     s.createdAt,
+		Subscriptions: append([]string(nil), s.subscriptions...),
+	}
+}
+
+// response reports the stream's live status and connection metrics
+func (s *managedStream) response() *models.StreamResponse {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	state := s.client.State()
+	if s.streamType != "public" {
+		state = s.client.UserDataState(s.listenKey)
+	}
+	stats := s.client.Stats()
+
+	var perStream map[string]models.SubscriptionMetrics
+	if len(s.subscriptions) > 0 {
+		activity := s.client.StreamActivity()
+		perStream = make(map[string]models.SubscriptionMetrics, len(s.subscriptions))
+		for _, name := range s.subscriptions {
+			perStream[name] = models.SubscriptionMetrics{
+				MessagesReceived: activity[name].Messages,
+				LastActivity:     activity[name].LastMessage,
+			}
+		}
+	}
+
 	return &models.StreamResponse{
-		ID:        id,
-		Type:      "public",
-		Status:    "connected",
-		CreatedAt: time.Now().Add(-1 * time.Hour),
+		ID:            s.id,
+		Type:          s.streamType,
+		Status:        state.String(),
+		CreatedAt:     s.createdAt,
+		Subscriptions: append([]string(nil), s.subscriptions...),
 		Metrics: &models.StreamMetrics{
-			MessagesReceived: 1000,
-			MessagesSent:     50,
-			ConnectedSince:   time.Now().Add(-1 * time.Hour),
-			LastActivity:     time.Now(),
+			MessagesReceived: stats.MessagesReceived,
+			MessagesSent:     stats.MessagesSent,
+			BytesReceived:    stats.BytesReceived,
+			BytesSent:        stats.BytesSent,
+			ConnectedSince:   stats.ConnectedSince,
+			LastActivity:     stats.LastActivity,
+			Subscriptions:    perStream,
 		},
-	}, nil
+	}
+}
+
+// parseStreamName splits a public stream name such as btcusdt@depth into its
+// symbol and kind
+func parseStreamName(name string) (symbol, kind string, err error) {
+	at := strings.LastIndex(name, "@")
+	if at <= 0 || at == len(name)-1 {
+		return "", "", fmt.Errorf("invalid stream name %q", name)
+	}
+	return strings.ToLower(name[:at]), name[at+1:], nil
+}
+
+// subscribe subscribes the stream's client to one public stream, relaying
+// its events to the stream's subscribers. Callers hold s.mu.
+func (s *managedStream) subscribe(ctx context.Context, symbol, kind string) error {
+	switch kind {
+	case "depth":
+		return s.client.SubscribeToDepth(ctx, symbol, s.depthHandler(symbol))
+	case "ticker":
+		return s.client.SubscribeToTicker(ctx, symbol, s.tickerHandler(symbol))
+	default:
+		return fmt.Errorf("%s streams are not supported", kind)
+	}
+}
+
+// StreamManagerImpl implements handlers.StreamManager. Every stream owns its
+// own WebSocket client so streams connect, reconnect and close independently.
+type StreamManagerImpl struct {
+	newClient ClientFactory
+
+	mu      sync.RWMutex
+	streams map[string]*managedStream
+	nextSeq uint64
+
+	// store, when set, receives every change so streams survive a restart
+	store     StreamStore
+	persistMu sync.Mutex
+
+	// listenKeys, when set, reattaches restored user streams
+	listenKeys ListenKeySource
+}
+
+// NewStreamManagerImpl creates a stream manager that opens streams with
+// clients from newClient
+func NewStreamManagerImpl(newClient ClientFactory) *StreamManagerImpl {
+	return &StreamManagerImpl{
+		newClient: newClient,
+		streams:   make(map[string]*managedStream),
+	}
+}
+
+// SetStore persists streams and subscriptions to store after every change
+func (m *StreamManagerImpl) SetStore(store StreamStore) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.store = store
+}
+
+// SetListenKeySource lets Restore reattach user streams with keys from source.
+// Without one, restored user streams wait for a client to attach a key.
+func (m *StreamManagerImpl) SetListenKeySource(source ListenKeySource) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.listenKeys = source
+}
+
+// Restore replays the streams saved in the store, reconnecting and
+// resubscribing each one, and returns how many were restored. A stream that
+// fails to reconnect is still registered so it can be reconnected later.
+func (m *StreamManagerImpl) Restore() (int, error) {
+	m.mu.RLock()
+	store := m.store
+	listenKeys := m.listenKeys
+	m.mu.RUnlock()
+	if store == nil {
+		return 0, nil
+	}
+
+	records, err := store.Load()
+	if err != nil {
+		return 0, err
+	}
+
+	for _, record := range records {
+		var seq uint64
+		if _, err := fmt.Sscanf(record.ID, "stream-%d", &seq); err != nil {
+			log.Warn().Str("stream_id", record.ID).Msg("Skipping persisted stream with unrecognised ID")
+			continue
+		}
+
+		stream := &managedStream{
+			id:            record.ID,
+			seq:           seq,
+			streamType:    record.Type,
+			createdAt:     record.CreatedAt,
+			client:        m.newClient(),
+			subscriptions: record.Subscriptions,
+			subscribedAt:  make(map[string]time.Time),
+		}
+		for _, name := range stream.subscriptions {
+			stream.subscribedAt[name] = time.Now()
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), streamConnectTimeout)
+		if stream.streamType != "public" && listenKeys != nil {
+			listenKey, err := listenKeys(ctx)
+			if err != nil {
+				log.Error().Err(err).Str("stream_id", stream.id).Msg("Failed to create listen key for restored stream")
+			}
+			stream.listenKey = listenKey
+		}
+		stream.mu.Lock()
+		err := stream.connect(ctx)
+		stream.mu.Unlock()
+		cancel()
+		if err != nil {
+			log.Error().Err(err).Str("stream_id", stream.id).Msg("Failed to restore stream")
+		}
+
+		m.mu.Lock()
+		m.streams[stream.id] = stream
+		if seq > m.nextSeq {
+			m.nextSeq = seq
+		}
+		m.mu.Unlock()
+	}
+
+	return len(records), nil
+}
+
+// persist saves every registered stream to the store. Failures are logged
+// rather than failing the request that changed the stream.
+func (m *StreamManagerImpl) persist() {
+	m.persistMu.Lock()
+	defer m.persistMu.Unlock()
+
+	m.mu.RLock()
+	store := m.store
+	streams := make([]*managedStream, 0, len(m.streams))
+	for _, stream := range m.streams {
+		streams = append(streams, stream)
+	}
+	m.mu.RUnlock()
+	if store == nil {
+		return
+	}
+
+	sort.Slice(streams, func(i, j int) bool { return streams[i].seq < streams[j].seq })
+	records := make([]StreamRecord, 0, len(streams))
+	for _, stream := range streams {
+		records = append(records, stream.record())
+	}
+	if err := store.Save(records); err != nil {
+		log.Error().Err(err).Msg("Failed to persist streams")
+	}
+}
+
+func (m *StreamManagerImpl) CreateStream(streamType string, subscriptions []string) (*models.StreamResponse, error) {
+	m.mu.Lock()
+	m.nextSeq++
+	seq := m.nextSeq
+	m.mu.Unlock()
+
+	stream := &managedStream{
+		id:           fmt.Sprintf("stream-%d", seq),
+		seq:          seq,
+		streamType:   streamType,
+		createdAt:    time.Now(),
+		client:       m.newClient(),
+		subscribedAt: make(map[string]time.Time),
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), streamConnectTimeout)
+	defer cancel()
+
+	if streamType == "public" {
+		stream.mu.Lock()
+		err := stream.connect(ctx)
+		for i := 0; err == nil && i < len(subscriptions); i++ {
+			err = stream.addSubscription(ctx, subscriptions[i])
+		}
+		stream.mu.Unlock()
+
+		if err != nil {
+			stream.client.Close()
+			return nil, err
+		}
+	}
+
+	m.mu.Lock()
+	m.streams[stream.id] = stream
+	m.mu.Unlock()
+	m.persist()
+
+	log.Info().Str("stream_id", stream.id).Str("type", streamType).Strs("subscriptions", subscriptions).Msg("Stream created")
+	return stream.response(), nil
+}
+
+func (m *StreamManagerImpl) GetStream(id string) (*models.StreamResponse, error) {
+	stream, err := m.lookup(id)
+	if err != nil {
+		return nil, err
+	}
+	return stream.response(), nil
 }
 
 func (m *StreamManagerImpl) ListStreams(filterType string, page, limit int) ([]models.StreamResponse, int, error) {
-	// In production, you'd list actual streams
-	streams := []models.StreamResponse{
-		{
-			ID:        "stream-1",
-			Type:      "public",
-			Status:    "connected",
-			CreatedAt: time.Now().Add(-2 * time.Hour),
-		},
+	m.mu.RLock()
+	matched := make([]*managedStream, 0, len(m.streams))
+	for _, stream := range m.streams {
+		if filterType == "" || stream.streamType == filterType {
+			matched = append(matched, stream)
+		}
+	}
+	m.mu.RUnlock()
+
+	sort.Slice(matched, func(i, j int) bool { return matched[i].seq < matched[j].seq })
+
+	total := len(matched)
+	if page < 1 {
+		page = 1
+	}
+	if limit < 1 {
+		limit = total
+	}
+	start := (page - 1) * limit
+	if start > total {
+		start = total
+	}
+	end := start + limit
+	if end > total {
+		end = total
+	}
+
+	streams := make([]models.StreamResponse, 0, end-start)
+	for _, stream := range matched[start:end] {
+		streams = append(streams, *stream.response())
 	}
-	return streams, len(streams), nil
+	return streams, total, nil
+}
+
+func (m *StreamManagerImpl) ListStreamsAfter(filterType, cursor string, limit int) ([]models.StreamResponse, string, error) {
+	var after *models.StreamCursor
+	if cursor != "" {
+		decoded, err := models.DecodeStreamCursor(cursor)
+		if err != nil {
+			return nil, "", err
+		}
+		after = &decoded
+	}
+
+	m.mu.RLock()
+	matched := make([]*managedStream, 0, len(m.streams))
+	for _, stream := range m.streams {
+		if filterType != "" && stream.streamType != filterType {
+			continue
+		}
+		if after != nil && !after.After(stream.createdAt, stream.id) {
+			continue
+		}
+		matched = append(matched, stream)
+	}
+	m.mu.RUnlock()
+
+	sort.Slice(matched, func(i, j int) bool {
+		a, b := matched[i], matched[j]
+		if at, bt := a.createdAt.UnixNano(), b.createdAt.UnixNano(); at != bt {
+			return at < bt
+		}
+		return a.id < b.id
+	})
+
+	if limit < 1 || limit > len(matched) {
+		limit = len(matched)
+	}
+
+	streams := make([]models.StreamResponse, 0, limit)
+	for _, stream := range matched[:limit] {
+		streams = append(streams, *stream.response())
+	}
+
+	nextCursor := ""
+	if limit < len(matched) {
+		last := matched[limit-1]
+		nextCursor = models.StreamCursor{CreatedAt: last.createdAt, ID: last.id}.Encode()
+	}
+	return streams, nextCursor, nil
 }
 
 func (m *StreamManagerImpl) CloseStream(id string) error {
-	// In production, you'd close the actual stream
+	m.mu.Lock()
+	stream, exists := m.streams[id]
+	delete(m.streams, id)
+	m.mu.Unlock()
+
+	if !exists {
+		return fmt.Errorf("stream %s not found", id)
+	}
+	m.persist()
+	stream.events.close()
+
+	stream.mu.Lock()
+	defer stream.mu.Unlock()
+	if err := stream.client.Close(); err != nil {
+		return fmt.Errorf("failed to close stream %s: %w", id, err)
+	}
+
+	log.Info().Str("stream_id", id).Msg("Stream closed")
 	return nil
 }
 
+// CloseAll closes every registered stream and returns how many were closed.
+// The store is left untouched so the streams are restored on the next start.
+func (m *StreamManagerImpl) CloseAll() int {
+	m.mu.Lock()
+	streams := m.streams
+	m.streams = make(map[string]*managedStream)
+	m.mu.Unlock()
+
+	for id, stream := range streams {
+		stream.events.close()
+		stream.mu.Lock()
+		if err := stream.client.Close(); err != nil {
+			log.Error().Err(err).Str("stream_id", id).Msg("Failed to close stream")
+		}
+		stream.mu.Unlock()
+	}
+	return len(streams)
+}
+
+// ReconnectStream replaces the stream's client with a fresh connection and
+// restores its subscriptions
 func (m *StreamManagerImpl) ReconnectStream(id string) (*models.StreamResponse, error) {
-	// In production, you'd reconnect the stream
-	return m.GetStream(id)
+	stream, err := m.lookup(id)
+	if err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), streamConnectTimeout)
+	defer cancel()
+
+	stream.mu.Lock()
+	if err := stream.client.Close(); err != nil {
+		log.Warn().Err(err).Str("stream_id", id).Msg("Failed to close stream before reconnecting")
+	}
+	stream.client = m.newClient()
+	err = stream.connect(ctx)
+	stream.mu.Unlock()
+	if err != nil {
+		return nil, err
+	}
+
+	log.Info().Str("stream_id", id).Msg("Stream reconnected")
+	return stream.response(), nil
 }
 
-// SubscriptionManagerImpl implements handlers.SubscriptionManager
+func (m *StreamManagerImpl) lookup(id string) (*managedStream, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	stream, exists := m.streams[id]
+	if !exists {
+		return nil, fmt.Errorf("stream %s not found", id)
+	}
+	return stream, nil
+}
+
+// SubscriptionManagerImpl implements handlers.SubscriptionManager against the
+// streams registered with a StreamManagerImpl
 type SubscriptionManagerImpl struct {
-	client *websocket.Client
+	streams *StreamManagerImpl
 }
 
 // NewSubscriptionManagerImpl creates a new subscription manager
-func NewSubscriptionManagerImpl(client *websocket.Client) *SubscriptionManagerImpl {
-	return &SubscriptionManagerImpl{client: client}
+func NewSubscriptionManagerImpl(streams *StreamManagerImpl) *SubscriptionManagerImpl {
+	return &SubscriptionManagerImpl{streams: streams}
 }
 
 func (m *SubscriptionManagerImpl) SubscribeToMarketData(streamID, symbol string, streams []string) (*models.SubscriptionResponse, error) {
-	// In production, this would subscribe via the WebSocket client
-	// For now, we'll return a successful response
-	// The actual WebSocket integration would require context and handlers
+	stream, err := m.streams.lookup(streamID)
+	if err != nil {
+		return nil, err
+	}
+	if stream.streamType != "public" {
+		return nil, fmt.Errorf("stream %s does not carry market data", streamID)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), streamConnectTimeout)
+	defer cancel()
+
+	stream.mu.Lock()
+	for _, kind := range streams {
+		if err = stream.addSubscription(ctx, strings.ToLower(symbol)+"@"+kind); err != nil {
+			break
+		}
+	}
+	stream.mu.Unlock()
+	m.streams.persist()
+	if err != nil {
+		return nil, err
+	}
 
 	return &models.SubscriptionResponse{
 		Success:      true,
@@ -93,9 +579,107 @@ func (m *SubscriptionManagerImpl) SubscribeToMarketData(streamID, symbol string,
 	}, nil
 }
 
+// SubscribeBulk subscribes every new stream across requests with one batched
+// subscribe. Streams already on the stream fail their request up front.
+func (m *SubscriptionManagerImpl) SubscribeBulk(streamID string, requests []models.SubscribeRequest) ([]models.SubscriptionResponse, error) {
+	stream, err := m.streams.lookup(streamID)
+	if err != nil {
+		return nil, err
+	}
+	if stream.streamType != "public" {
+		return nil, fmt.Errorf("stream %s does not carry market data", streamID)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), streamConnectTimeout)
+	defer cancel()
+
+	results := make([]models.SubscriptionResponse, len(requests))
+	batch := websocket.PublicBatch{
+		Depth:  make(map[string]func(*websocket.DepthUpdateEvent) error),
+		Ticker: make(map[string]func(*websocket.TickerEvent) error),
+	}
+	var batched []int // requests riding on the batch
+	var names []string
+
+	stream.mu.Lock()
+	queued := make(map[string]bool)
+	for i, req := range requests {
+		results[i] = models.SubscriptionResponse{Symbol: req.Symbol, Streams: req.Streams}
+		symbol := strings.ToLower(req.Symbol)
+
+		var requestNames []string
+		var rejected error
+		for _, kind := range req.Streams {
+			name := symbol + "@" + kind
+			if _, exists := stream.subscribedAt[name]; exists || queued[name] {
+				rejected = fmt.Errorf("already subscribed to %s", name)
+				break
+			}
+			if kind != "depth" && kind != "ticker" {
+				rejected = fmt.Errorf("%s streams are not supported", kind)
+				break
+			}
+			requestNames = append(requestNames, name)
+		}
+		if rejected != nil {
+			results[i].Error = rejected.Error()
+			continue
+		}
+
+		for _, name := range requestNames {
+			queued[name] = true
+			if strings.HasSuffix(name, "@depth") {
+				batch.Depth[symbol] = stream.depthHandler(symbol)
+			} else {
+				batch.Ticker[symbol] = stream.tickerHandler(symbol)
+			}
+		}
+		names = append(names, requestNames...)
+		batched = append(batched, i)
+	}
+
+	var batchErr error
+	if len(names) > 0 {
+		batchErr = stream.client.SubscribeBatch(ctx, batch)
+	}
+	now := time.Now()
+	for _, i := range batched {
+		if batchErr != nil {
+			results[i].Error = batchErr.Error()
+			continue
+		}
+		results[i].Success = true
+		results[i].SubscribedAt = now
+	}
+	if batchErr == nil {
+		for _, name := range names {
+			stream.subscriptions = append(stream.subscriptions, name)
+			stream.subscribedAt[name] = now
+		}
+	}
+	stream.mu.Unlock()
+
+	m.streams.persist()
+	return results, nil
+}
+
 func (m *SubscriptionManagerImpl) SubscribeToUserData(streamID, listenKey string) (*models.SubscriptionResponse, error) {
-	// In production, this would subscribe via the WebSocket client
-	// For now, we'll return a successful response
+	stream, err := m.streams.lookup(streamID)
+	if err != nil {
+		return nil, err
+	}
+	if stream.streamType != "user" {
+		return nil, fmt.Errorf("stream %s does not carry user data", streamID)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), streamConnectTimeout)
+	defer cancel()
+
+	err = m.attachListenKey(ctx, stream, listenKey)
+	m.streams.persist()
+	if err != nil {
+		return nil, err
+	}
 
 	return &models.SubscriptionResponse{
 		Success:      true,
@@ -103,23 +687,112 @@ func (m *SubscriptionManagerImpl) SubscribeToUserData(streamID, listenKey string
 	}, nil
 }
 
+// attachListenKey points a user stream at listenKey, closing the connection
+// for any previous key
+func (m *SubscriptionManagerImpl) attachListenKey(ctx context.Context, stream *managedStream, listenKey string) error {
+	stream.mu.Lock()
+	defer stream.mu.Unlock()
+
+	if stream.listenKey == listenKey {
+		return fmt.Errorf("already subscribed to user data")
+	}
+	if stream.listenKey != "" {
+		if err := stream.client.UnsubscribeFromUserData(ctx, stream.listenKey); err != nil {
+			log.Warn().Err(err).Str("stream_id", stream.id).Msg("Failed to close previous user data stream")
+		}
+	}
+	if err := stream.client.SubscribeToUserData(ctx, listenKey, &websocket.UserDataHandler{}); err != nil {
+		stream.listenKey = ""
+		return err
+	}
+	stream.listenKey = listenKey
+	return nil
+}
+
+// Unsubscribe removes either the given streams for a symbol or the single
+// stream named by req.StreamID, e.g. btcusdt@depth
 func (m *SubscriptionManagerImpl) Unsubscribe(streamID string, req models.UnsubscribeRequest) (*models.SubscriptionResponse, error) {
-	// In production, this would unsubscribe via the WebSocket client
-	// For now, we'll return a successful response
+	stream, err := m.streams.lookup(streamID)
+	if err != nil {
+		return nil, err
+	}
+
+	names := []string{req.StreamID}
+	if req.StreamID == "" {
+		names = names[:0]
+		for _, kind := range req.Streams {
+			names = append(names, strings.ToLower(req.Symbol)+"@"+kind)
+		}
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), streamConnectTimeout)
+	defer cancel()
+
+	stream.mu.Lock()
+	for _, name := range names {
+		if err = stream.removeSubscription(ctx, name); err != nil {
+			break
+		}
+	}
+	stream.mu.Unlock()
+	m.streams.persist()
+	if err != nil {
+		return nil, err
+	}
 
 	return &models.SubscriptionResponse{
 		Success: true,
+		Symbol:  req.Symbol,
+		Streams: req.Streams,
 	}, nil
 }
 
+// ListSubscriptions returns the stream's market data subscriptions grouped
+// by symbol
 func (m *SubscriptionManagerImpl) ListSubscriptions(streamID string) ([]models.SubscriptionResponse, error) {
-	// In production, you'd list actual subscriptions
-	return []models.SubscriptionResponse{}, nil
+	stream, err := m.streams.lookup(streamID)
+	if err != nil {
+		return nil, err
+	}
+
+	stream.mu.Lock()
+	defer stream.mu.Unlock()
+
+	var subscriptions []models.SubscriptionResponse
+	bySymbol := make(map[string]int)
+	for _, name := range stream.subscriptions {
+		symbol, kind, err := parseStreamName(name)
+		if err != nil {
+			continue
+		}
+		symbol = strings.ToUpper(symbol)
+
+		i, exists := bySymbol[symbol]
+		if !exists {
+			i = len(subscriptions)
+			bySymbol[symbol] = i
+			subscriptions = append(subscriptions, models.SubscriptionResponse{
+				Success:      true,
+				Symbol:       symbol,
+				SubscribedAt: stream.subscribedAt[name],
+			})
+		}
+		subscriptions[i].Streams = append(subscriptions[i].Streams, kind)
+	}
+	return subscriptions, nil
+}
+
+// LiveConfigApplier applies settings to running components
+type LiveConfigApplier interface {
+	SetRateLimit(requestsPerWindow int)
+	SetLogLevel(level string)
 }
 
 // ConfigManagerImpl implements handlers.ConfigManager
 type ConfigManagerImpl struct {
-	config *Config
+	mu      sync.RWMutex
+	config  *Config
+	applier LiveConfigApplier
 }
 
 // NewConfigManagerImpl creates a new config manager
@@ -127,22 +800,45 @@ func NewConfigManagerImpl(config *Config) *ConfigManagerImpl {
 	return &ConfigManagerImpl{config: config}
 }
 
+// SetApplier registers the component that receives live rate limit and
+// log level changes
+func (m *ConfigManagerImpl) SetApplier(applier LiveConfigApplier) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.applier = applier
+}
+
 func (m *ConfigManagerImpl) GetConfig() (*models.ConfigResponse, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
 	return &models.ConfigResponse{
 		RateLimit:      m.config.RateLimit,
 		MaxConnections: m.config.MaxConnections,
+		LogLevel:       m.config.LogLevel,
 		UpdatedAt:      time.Now(),
 	}, nil
 }
 
 func (m *ConfigManagerImpl) UpdateConfig(req models.ConfigUpdateRequest) (*models.ConfigResponse, error) {
+	m.mu.Lock()
 	// Update configuration
 	if req.RateLimit != nil {
 		m.config.RateLimit = *req.RateLimit
+		if m.applier != nil {
+			m.applier.SetRateLimit(*req.RateLimit)
+		}
 	}
 	if req.MaxConnections != nil {
 		m.config.MaxConnections = *req.MaxConnections
 	}
+	if req.LogLevel != nil {
+		m.config.LogLevel = *req.LogLevel
+		if m.applier != nil {
+			m.applier.SetLogLevel(*req.LogLevel)
+		}
+	}
+	m.mu.Unlock()
 
 	return m.GetConfig()
 }
@@ -207,25 +903,16 @@ type MetricsCollectorImpl struct {
 	client    *websocket.Client
 }
 
-// NewMetricsCollectorImpl creates a new metrics collector
-func NewMetricsCollectorImpl(client *websocket.Client) *MetricsCollectorImpl {
+// NewMetricsCollectorImpl creates a metrics collector backed by collector,
+// which the WebSocket client also reports connection and event counts to
+func NewMetricsCollectorImpl(client *websocket.Client, collector *metrics.Collector) *MetricsCollectorImpl {
 	return &MetricsCollectorImpl{
-		collector: metrics.NewCollector(),
+		collector: collector,
 		client:    client,
 	}
 }
 
 func (m *MetricsCollectorImpl) Collect() (string, error) {
-	// Add WebSocket connection metrics if client is available
-	if m.client != nil {
-		// Record WebSocket connection status
-		m.collector.RecordWebSocketConnection("connected")
-
-		// Add additional WebSocket metrics based on client state
-		// This would be expanded based on actual WebSocket client capabilities
-		m.collector.RecordCustomCounter("websocket_total_connections")
-	}
-
 	return m.collector.Collect()
 }
 
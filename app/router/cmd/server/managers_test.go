@@ -0,0 +1,335 @@
+package main
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	gorillaws "github.com/gorilla/websocket"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"router/internal/models"
+	"router/internal/websocket"
+)
+
+// newMockStreamServer acknowledges every subscription and follows each
+// SUBSCRIBE with one ticker event for the subscribed streams
+func newMockStreamServer(t *testing.T) *httptest.Server {
+	upgrader := gorillaws.Upgrader{CheckOrigin: func(r *http.Request) bool { return true }}
+
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		conn, err := upgrader.Upgrade(w, r, nil)
+		if err != nil {
+			t.Logf("WebSocket upgrade failed: %v", err)
+			return
+		}
+		defer conn.Close()
+
+		for {
+			var req websocket.SubscriptionRequest
+			if err := conn.ReadJSON(&req); err != nil {
+				return
+			}
+			conn.WriteJSON(websocket.SubscriptionResponse{ID: req.ID})
+
+			if req.Method != "SUBSCRIBE" {
+				continue
+			}
+			for _, stream := range req.Params {
+				conn.WriteJSON(websocket.StreamMessage{
+					Stream: stream,
+					Data:   json.RawMessage(`{"e":"24hrTicker","s":"BTCUSDT"}`),
+				})
+			}
+		}
+	}))
+}
+
+func newTestStreamManager(t *testing.T) (*StreamManagerImpl, *SubscriptionManagerImpl) {
+	server := newMockStreamServer(t)
+	t.Cleanup(server.Close)
+
+	wsURL := strings.Replace(server.URL, "http://", "ws://", 1)
+	streams := NewStreamManagerImpl(func() *websocket.Client {
+		return websocket.NewClient(websocket.WithBaseURL(wsURL))
+	})
+	t.Cleanup(func() { streams.CloseAll() })
+
+	return streams, NewSubscriptionManagerImpl(streams)
+}
+
+func TestStreamManagerImpl_Lifecycle(t *testing.T) {
+	streams, subscriptions := newTestStreamManager(t)
+
+	created, err := streams.CreateStream("public", []string{"btcusdt@ticker"})
+	require.NoError(t, err)
+	assert.Equal(t, "public", created.Type)
+	assert.Equal(t, "connected", created.Status)
+	assert.Equal(t, []string{"btcusdt@ticker"}, created.Subscriptions)
+
+	resp, err := subscriptions.SubscribeToMarketData(created.ID, "ETHUSDT", []string{"depth"})
+	require.NoError(t, err)
+	assert.True(t, resp.Success)
+
+	_, err = subscriptions.SubscribeToMarketData(created.ID, "ETHUSDT", []string{"depth"})
+	assert.ErrorContains(t, err, "already subscribed")
+
+	// Each SUBSCRIBE is acknowledged and followed by one event
+	require.Eventually(t, func() bool {
+		stream, err := streams.GetStream(created.ID)
+		return err == nil && stream.Metrics.MessagesReceived == 4
+	}, 2*time.Second, 10*time.Millisecond)
+
+	stream, err := streams.GetStream(created.ID)
+	require.NoError(t, err)
+	assert.Equal(t, "connected", stream.Status)
+	assert.Equal(t, []string{"btcusdt@ticker", "ethusdt@depth"}, stream.Subscriptions)
+	assert.Equal(t, int64(2), stream.Metrics.MessagesSent)
+	assert.Positive(t, stream.Metrics.BytesReceived)
+	assert.False(t, stream.Metrics.ConnectedSince.IsZero())
+
+	listed, err := subscriptions.ListSubscriptions(created.ID)
+	require.NoError(t, err)
+	require.Len(t, listed, 2)
+	assert.Equal(t, "BTCUSDT", listed[0].Symbol)
+	assert.Equal(t, []string{"depth"}, listed[1].Streams)
+
+	_, err = subscriptions.Unsubscribe(created.ID, models.UnsubscribeRequest{StreamID: "ethusdt@depth"})
+	require.NoError(t, err)
+	_, err = subscriptions.Unsubscribe(created.ID, models.UnsubscribeRequest{StreamID: "ethusdt@depth"})
+	assert.ErrorContains(t, err, "not found")
+
+	reconnected, err := streams.ReconnectStream(created.ID)
+	require.NoError(t, err)
+	assert.Equal(t, "connected", reconnected.Status)
+	assert.Equal(t, []string{"btcusdt@ticker"}, reconnected.Subscriptions)
+
+	require.NoError(t, streams.CloseStream(created.ID))
+	_, err = streams.GetStream(created.ID)
+	assert.ErrorContains(t, err, "not found")
+	assert.ErrorContains(t, streams.CloseStream(created.ID), "not found")
+}
+
+func TestSubscriptionManagerImpl_SubscribeBulk(t *testing.T) {
+	streams, subscriptions := newTestStreamManager(t)
+
+	created, err := streams.CreateStream("public", []string{"btcusdt@depth"})
+	require.NoError(t, err)
+
+	results, err := subscriptions.SubscribeBulk(created.ID, []models.SubscribeRequest{
+		{Symbol: "ETHUSDT", Streams: []string{"depth", "ticker"}},
+		{Symbol: "BTCUSDT", Streams: []string{"depth"}},
+		{Symbol: "BNBUSDT", Streams: []string{"ticker"}},
+		{Symbol: "SOLUSDT", Streams: []string{"trades"}},
+	})
+	require.NoError(t, err)
+	require.Len(t, results, 4)
+
+	assert.True(t, results[0].Success)
+	assert.Contains(t, results[1].Error, "already subscribed to btcusdt@depth")
+	assert.True(t, results[2].Success)
+	assert.Contains(t, results[3].Error, "not supported")
+
+	stream, err := streams.GetStream(created.ID)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"btcusdt@depth", "ethusdt@depth", "ethusdt@ticker", "bnbusdt@ticker"}, stream.Subscriptions)
+	assert.Equal(t, int64(2), stream.Metrics.MessagesSent, "the bulk request goes out as one frame")
+
+	_, err = subscriptions.SubscribeBulk("stream-missing", []models.SubscribeRequest{{Symbol: "BTCUSDT", Streams: []string{"depth"}}})
+	assert.ErrorContains(t, err, "not found")
+}
+
+func TestStreamManagerImpl_PerSubscriptionMetrics(t *testing.T) {
+	// Feed three ticker events once both streams are subscribed, and nothing
+	// on the depth stream
+	upgrader := gorillaws.Upgrader{CheckOrigin: func(r *http.Request) bool { return true }}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		conn, err := upgrader.Upgrade(w, r, nil)
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+
+		subscribed := 0
+		for {
+			var req websocket.SubscriptionRequest
+			if err := conn.ReadJSON(&req); err != nil {
+				return
+			}
+			conn.WriteJSON(websocket.SubscriptionResponse{ID: req.ID})
+
+			if subscribed += len(req.Params); subscribed == 2 {
+				for i := 0; i < 3; i++ {
+					conn.WriteJSON(websocket.StreamMessage{
+						Stream: "btcusdt@ticker",
+						Data:   json.RawMessage(`{"e":"24hrTicker","s":"BTCUSDT"}`),
+					})
+				}
+			}
+		}
+	}))
+	defer server.Close()
+
+	wsURL := strings.Replace(server.URL, "http://", "ws://", 1)
+	streams := NewStreamManagerImpl(func() *websocket.Client {
+		return websocket.NewClient(websocket.WithBaseURL(wsURL))
+	})
+	defer streams.CloseAll()
+
+	created, err := streams.CreateStream("public", []string{"btcusdt@ticker", "ethusdt@depth"})
+	require.NoError(t, err)
+
+	var perStream map[string]models.SubscriptionMetrics
+	require.Eventually(t, func() bool {
+		stream, err := streams.GetStream(created.ID)
+		require.NoError(t, err)
+		perStream = stream.Metrics.Subscriptions
+		return perStream["btcusdt@ticker"].MessagesReceived == 3
+	}, 2*time.Second, 10*time.Millisecond)
+
+	require.Len(t, perStream, 2)
+	assert.False(t, perStream["btcusdt@ticker"].LastActivity.IsZero())
+	assert.Zero(t, perStream["ethusdt@depth"].MessagesReceived)
+	assert.True(t, perStream["ethusdt@depth"].LastActivity.IsZero(), "a silent feed has no activity")
+}
+
+func TestStreamManagerImpl_ListStreams(t *testing.T) {
+	streams, _ := newTestStreamManager(t)
+
+	for i := 0; i < 3; i++ {
+		_, err := streams.CreateStream("public", nil)
+		require.NoError(t, err)
+	}
+	user, err := streams.CreateStream("user", nil)
+	require.NoError(t, err)
+	assert.Equal(t, "disconnected", user.Status, "user streams connect once a listen key is attached")
+
+	all, total, err := streams.ListStreams("", 1, 10)
+	require.NoError(t, err)
+	assert.Equal(t, 4, total)
+	assert.Len(t, all, 4)
+
+	page, total, err := streams.ListStreams("public", 2, 2)
+	require.NoError(t, err)
+	assert.Equal(t, 3, total)
+	require.Len(t, page, 1)
+	assert.Equal(t, "stream-3", page[0].ID)
+
+	assert.Equal(t, 4, streams.CloseAll())
+	_, total, err = streams.ListStreams("", 1, 10)
+	require.NoError(t, err)
+	assert.Zero(t, total)
+}
+
+func TestStreamManagerImpl_ListStreamsAfter(t *testing.T) {
+	streams, _ := newTestStreamManager(t)
+
+	create := func() string {
+		stream, err := streams.CreateStream("user", nil)
+		require.NoError(t, err)
+		return stream.ID
+	}
+
+	var before []string
+	for i := 0; i < 5; i++ {
+		before = append(before, create())
+	}
+
+	seen := make(map[string]int)
+	var added []string
+	cursor := ""
+	for page := 0; ; page++ {
+		listed, next, err := streams.ListStreamsAfter("", cursor, 2)
+		require.NoError(t, err)
+		for _, stream := range listed {
+			seen[stream.ID]++
+		}
+
+		// Change the registry between pages
+		switch page {
+		case 0:
+			added = append(added, create(), create())
+		case 1:
+			require.NoError(t, streams.CloseStream(before[0]))
+			require.NoError(t, streams.CloseStream(before[4]))
+		}
+
+		if next == "" {
+			break
+		}
+		cursor = next
+	}
+
+	for _, id := range append(before[:4:4], added...) {
+		assert.Equal(t, 1, seen[id], "stream %s", id)
+	}
+	assert.Zero(t, seen[before[4]], "closed before its page was reached")
+
+	_, _, err := streams.ListStreamsAfter("", "not a cursor", 2)
+	assert.ErrorContains(t, err, "invalid cursor")
+}
+
+func TestStreamManagerImpl_CreateStreamErrors(t *testing.T) {
+	t.Run("connection failure", func(t *testing.T) {
+		streams := NewStreamManagerImpl(func() *websocket.Client {
+			return websocket.NewClient(websocket.WithBaseURL("ws://127.0.0.1:1"))
+		})
+
+		_, err := streams.CreateStream("public", nil)
+		assert.ErrorContains(t, err, "connection failed")
+
+		_, total, _ := streams.ListStreams("", 1, 10)
+		assert.Zero(t, total)
+	})
+
+	t.Run("unsupported stream", func(t *testing.T) {
+		streams, _ := newTestStreamManager(t)
+
+		_, err := streams.CreateStream("public", []string{"btcusdt@kline_1m"})
+		assert.ErrorContains(t, err, "not supported")
+
+		_, total, _ := streams.ListStreams("", 1, 10)
+		assert.Zero(t, total)
+	})
+}
+
+func TestStreamManagerImpl_SubscribeEvents(t *testing.T) {
+	streams, subscriptions := newTestStreamManager(t)
+
+	created, err := streams.CreateStream("public", nil)
+	require.NoError(t, err)
+
+	events, unsubscribe, err := streams.SubscribeEvents(created.ID)
+	require.NoError(t, err)
+
+	_, err = subscriptions.SubscribeToMarketData(created.ID, "BTCUSDT", []string{"ticker"})
+	require.NoError(t, err)
+
+	select {
+	case event := <-events:
+		assert.Equal(t, "btcusdt@ticker", event.Stream)
+		assert.Contains(t, string(event.Data), "BTCUSDT")
+	case <-time.After(2 * time.Second):
+		t.Fatal("ticker event was not relayed")
+	}
+
+	stream, err := streams.lookup(created.ID)
+	require.NoError(t, err)
+	assert.Equal(t, 1, stream.events.count())
+	unsubscribe()
+	unsubscribe()
+	assert.Zero(t, stream.events.count())
+
+	// Closing the stream ends any remaining subscriptions
+	events, _, err = streams.SubscribeEvents(created.ID)
+	require.NoError(t, err)
+	require.NoError(t, streams.CloseStream(created.ID))
+	_, open := <-events
+	assert.False(t, open)
+
+	_, _, err = streams.SubscribeEvents(created.ID)
+	assert.ErrorContains(t, err, "not found")
+}
@@ -0,0 +1,84 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// StreamRecord is the persisted form of a stream and its subscriptions.
+// Listen keys are not kept: they are credentials and expire, so user streams
+// get a fresh key when replayed.
+type StreamRecord struct {
+	ID            string    `json:"id"`
+	Type          string    `json:"type"`
+	CreatedAt     time.Time `json:"created_at"`
+	Subscriptions []string  `json:"subscriptions,omitempty"`
+}
+
+// StreamStore persists the active streams so they can be replayed after a
+// restart
+type StreamStore interface {
+	Load() ([]StreamRecord, error)
+	Save(records []StreamRecord) error
+}
+
+// FileStreamStore keeps stream records in a JSON file
+type FileStreamStore struct {
+	path string
+}
+
+// NewFileStreamStore creates a store backed by the file at path
+func NewFileStreamStore(path string) *FileStreamStore {
+	return &FileStreamStore{path: path}
+}
+
+// Load reads the stored records. A missing file means nothing was persisted.
+func (s *FileStreamStore) Load() ([]StreamRecord, error) {
+	data, err := os.ReadFile(s.path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read stream state %s: %w", s.path, err)
+	}
+
+	var records []StreamRecord
+	if err := json.Unmarshal(data, &records); err != nil {
+		return nil, fmt.Errorf("failed to parse stream state %s: %w", s.path, err)
+	}
+	return records, nil
+}
+
+// Save replaces the stored records. The file is written to a temporary path
+// and renamed so a crash never leaves it half written.
+func (s *FileStreamStore) Save(records []StreamRecord) error {
+	if records == nil {
+		records = []StreamRecord{}
+	}
+	data, err := json.MarshalIndent(records, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode stream state: %w", err)
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(s.path), filepath.Base(s.path)+".*.tmp")
+	if err != nil {
+		return fmt.Errorf("failed to write stream state: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return fmt.Errorf("failed to write stream state: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("failed to write stream state: %w", err)
+	}
+	if err := os.Rename(tmp.Name(), s.path); err != nil {
+		return fmt.Errorf("failed to write stream state: %w", err)
+	}
+	return nil
+}
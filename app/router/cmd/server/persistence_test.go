@@ -0,0 +1,150 @@
+package main
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"router/internal/websocket"
+)
+
+func TestFileStreamStore(t *testing.T) {
+	t.Run("missing file loads nothing", func(t *testing.T) {
+		store := NewFileStreamStore(filepath.Join(t.TempDir(), "streams.json"))
+
+		records, err := store.Load()
+		require.NoError(t, err)
+		assert.Empty(t, records)
+	})
+
+	t.Run("round trips records", func(t *testing.T) {
+		store := NewFileStreamStore(filepath.Join(t.TempDir(), "streams.json"))
+		want := []StreamRecord{
+			{ID: "stream-1", Type: "public", CreatedAt: time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC), Subscriptions: []string{"btcusdt@depth"}},
+			{ID: "stream-2", Type: "user", CreatedAt: time.Date(2024, 1, 2, 3, 4, 6, 0, time.UTC)},
+		}
+
+		require.NoError(t, store.Save(want))
+		got, err := store.Load()
+		require.NoError(t, err)
+		assert.Equal(t, want, got)
+	})
+
+	t.Run("corrupt file", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "streams.json")
+		require.NoError(t, os.WriteFile(path, []byte("{"), 0o600))
+
+		_, err := NewFileStreamStore(path).Load()
+		assert.ErrorContains(t, err, "failed to parse stream state")
+	})
+}
+
+func TestStreamManagerImpl_RestoresAfterRestart(t *testing.T) {
+	server := newMockStreamServer(t)
+	defer server.Close()
+	wsURL := strings.Replace(server.URL, "http://", "ws://", 1)
+	newClient := func() *websocket.Client {
+		return websocket.NewClient(websocket.WithBaseURL(wsURL))
+	}
+	path := filepath.Join(t.TempDir(), "streams.json")
+
+	// First run: create streams, change their subscriptions, then shut down
+	before := NewStreamManagerImpl(newClient)
+	before.SetStore(NewFileStreamStore(path))
+	subscriptions := NewSubscriptionManagerImpl(before)
+
+	first, err := before.CreateStream("public", []string{"btcusdt@ticker"})
+	require.NoError(t, err)
+	_, err = subscriptions.SubscribeToMarketData(first.ID, "ETHUSDT", []string{"depth"})
+	require.NoError(t, err)
+	second, err := before.CreateStream("public", []string{"bnbusdt@depth"})
+	require.NoError(t, err)
+	closed, err := before.CreateStream("public", nil)
+	require.NoError(t, err)
+	require.NoError(t, before.CloseStream(closed.ID))
+
+	assert.Equal(t, 2, before.CloseAll())
+
+	// Second run: replay from the same file
+	after := NewStreamManagerImpl(newClient)
+	after.SetStore(NewFileStreamStore(path))
+	defer after.CloseAll()
+
+	restored, err := after.Restore()
+	require.NoError(t, err)
+	assert.Equal(t, 2, restored)
+
+	stream, err := after.GetStream(first.ID)
+	require.NoError(t, err)
+	assert.Equal(t, "connected", stream.Status)
+	assert.Equal(t, []string{"btcusdt@ticker", "ethusdt@depth"}, stream.Subscriptions)
+	assert.Equal(t, int64(2), stream.Metrics.MessagesSent, "both subscriptions are sent again")
+	assert.True(t, stream.CreatedAt.Equal(first.CreatedAt))
+
+	stream, err = after.GetStream(second.ID)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"bnbusdt@depth"}, stream.Subscriptions)
+
+	_, err = after.GetStream(closed.ID)
+	assert.ErrorContains(t, err, "not found")
+
+	// New streams never reuse a restored ID
+	next, err := after.CreateStream("public", nil)
+	require.NoError(t, err)
+	assert.NotContains(t, []string{first.ID, second.ID}, next.ID)
+}
+
+func TestStreamManagerImpl_RestoresUserStreamWithFreshKey(t *testing.T) {
+	server := newMockStreamServer(t)
+	defer server.Close()
+	wsURL := strings.Replace(server.URL, "http://", "ws://", 1)
+	newClient := func() *websocket.Client {
+		return websocket.NewClient(websocket.WithBaseURL(wsURL))
+	}
+	path := filepath.Join(t.TempDir(), "streams.json")
+
+	before := NewStreamManagerImpl(newClient)
+	before.SetStore(NewFileStreamStore(path))
+	user, err := before.CreateStream("user", nil)
+	require.NoError(t, err)
+	_, err = NewSubscriptionManagerImpl(before).SubscribeToUserData(user.ID, "old-listen-key")
+	require.NoError(t, err)
+	before.CloseAll()
+
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.NotContains(t, string(data), "old-listen-key", "listen keys are never written to disk")
+
+	t.Run("without a key source the stream waits for a new key", func(t *testing.T) {
+		after := NewStreamManagerImpl(newClient)
+		after.SetStore(NewFileStreamStore(path))
+		defer after.CloseAll()
+
+		_, err := after.Restore()
+		require.NoError(t, err)
+		stream, err := after.GetStream(user.ID)
+		require.NoError(t, err)
+		assert.Equal(t, "disconnected", stream.Status)
+	})
+
+	t.Run("a key source reattaches the stream", func(t *testing.T) {
+		after := NewStreamManagerImpl(newClient)
+		after.SetStore(NewFileStreamStore(path))
+		after.SetListenKeySource(func(ctx context.Context) (string, error) {
+			return "fresh-listen-key", nil
+		})
+		defer after.CloseAll()
+
+		_, err := after.Restore()
+		require.NoError(t, err)
+		stream, err := after.GetStream(user.ID)
+		require.NoError(t, err)
+		assert.Equal(t, "connected", stream.Status)
+		assert.Equal(t, "fresh-listen-key", after.streams[user.ID].listenKey)
+	})
+}
@@ -0,0 +1,123 @@
+package main
+
+import (
+	"encoding/json"
+	"sync"
+
+	"github.com/rs/zerolog/log"
+	"router/internal/models"
+	"router/internal/websocket"
+)
+
+// relayBuffer is how many events a subscriber may fall behind before it is
+// dropped as a slow consumer
+const relayBuffer = 64
+
+// eventFanout delivers a stream's events to every relay subscriber. The zero
+// value is ready to use.
+type eventFanout struct {
+	mu          sync.Mutex
+	subscribers map[chan models.StreamEvent]struct{}
+	closed      bool
+}
+
+// subscribe registers a subscriber. The returned function unregisters it and
+// is safe to call more than once. The channel is closed when the stream is.
+func (f *eventFanout) subscribe() (<-chan models.StreamEvent, func()) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	events := make(chan models.StreamEvent, relayBuffer)
+	if f.closed {
+		close(events)
+		return events, func() {}
+	}
+	if f.subscribers == nil {
+		f.subscribers = make(map[chan models.StreamEvent]struct{})
+	}
+	f.subscribers[events] = struct{}{}
+
+	return events, func() {
+		f.mu.Lock()
+		defer f.mu.Unlock()
+		if _, exists := f.subscribers[events]; exists {
+			delete(f.subscribers, events)
+			close(events)
+		}
+	}
+}
+
+// publish sends an event to every subscriber without blocking the WebSocket
+// read loop. A subscriber whose buffer is full is unregistered and its
+// channel closed so the relay can disconnect it.
+func (f *eventFanout) publish(stream string, payload interface{}) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if len(f.subscribers) == 0 {
+		return
+	}
+
+	data, err := json.Marshal(payload)
+	if err != nil {
+		log.Error().Err(err).Str("stream", stream).Msg("Failed to encode relayed event")
+		return
+	}
+	event := models.StreamEvent{Stream: stream, Data: data}
+	for events := range f.subscribers {
+		select {
+		case events <- event:
+		default:
+			delete(f.subscribers, events)
+			close(events)
+			log.Warn().Str("stream", stream).Msg("Dropped slow event relay subscriber")
+		}
+	}
+}
+
+// count returns the number of registered subscribers
+func (f *eventFanout) count() int {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return len(f.subscribers)
+}
+
+// close ends every subscription and refuses new ones
+func (f *eventFanout) close() {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.closed = true
+	for events := range f.subscribers {
+		delete(f.subscribers, events)
+		close(events)
+	}
+}
+
+// depthHandler relays symbol's depth updates to the stream's subscribers
+func (s *managedStream) depthHandler(symbol string) func(*websocket.DepthUpdateEvent) error {
+	name := symbol + "@depth"
+	return func(event *websocket.DepthUpdateEvent) error {
+		s.events.publish(name, event)
+		return nil
+	}
+}
+
+// tickerHandler relays symbol's ticker updates to the stream's subscribers
+func (s *managedStream) tickerHandler(symbol string) func(*websocket.TickerEvent) error {
+	name := symbol + "@ticker"
+	return func(event *websocket.TickerEvent) error {
+		s.events.publish(name, event)
+		return nil
+	}
+}
+
+// SubscribeEvents implements handlers.EventRelay
+func (m *StreamManagerImpl) SubscribeEvents(streamID string) (<-chan models.StreamEvent, func(), error) {
+	stream, err := m.lookup(streamID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	events, unsubscribe := stream.events.subscribe()
+	return events, unsubscribe, nil
+}
@@ -0,0 +1,159 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	gorillaws "github.com/gorilla/websocket"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"router/internal/api"
+	"router/internal/handlers"
+	"router/internal/models"
+)
+
+func TestEventRelay_WebSocketFanout(t *testing.T) {
+	streams, _ := newTestStreamManager(t)
+
+	created, err := streams.CreateStream("public", nil)
+	require.NoError(t, err)
+	stream, err := streams.lookup(created.ID)
+	require.NoError(t, err)
+
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.GET("/streams/:id/ws", handlers.NewEventHandlers(streams, time.Minute).StreamWebSocket())
+	server := httptest.NewServer(router)
+	defer server.Close()
+
+	wsURL := strings.Replace(server.URL, "http://", "ws://", 1) + "/streams/" + created.ID + "/ws"
+	dial := func() *gorillaws.Conn {
+		conn, _, err := gorillaws.DefaultDialer.Dial(wsURL, nil)
+		require.NoError(t, err)
+		t.Cleanup(func() { conn.Close() })
+		return conn
+	}
+	read := func(conn *gorillaws.Conn) models.StreamEvent {
+		var event models.StreamEvent
+		require.NoError(t, conn.SetReadDeadline(time.Now().Add(2*time.Second)))
+		require.NoError(t, conn.ReadJSON(&event))
+		return event
+	}
+
+	first, second := dial(), dial()
+	require.Eventually(t, func() bool { return stream.events.count() == 2 }, time.Second, 10*time.Millisecond)
+
+	stream.events.publish("btcusdt@ticker", map[string]string{"s": "BTCUSDT"})
+	assert.Equal(t, "btcusdt@ticker", read(first).Stream)
+	assert.Equal(t, "btcusdt@ticker", read(second).Stream)
+
+	// The second client stops reading. Once the socket buffers fill, its
+	// relay falls behind and is dropped while the first keeps up.
+	payload := map[string]string{"pad": strings.Repeat("x", 64<<10)}
+	for i := 0; stream.events.count() == 2; i++ {
+		require.Less(t, i, 5000, "stalled client was never dropped")
+		stream.events.publish("btcusdt@depth", payload)
+		assert.Equal(t, "btcusdt@depth", read(first).Stream)
+	}
+	assert.Equal(t, 1, stream.events.count())
+
+	stream.events.publish("btcusdt@ticker", map[string]string{"s": "BTCUSDT"})
+	assert.Equal(t, "btcusdt@ticker", read(first).Stream)
+}
+
+func TestEventRelay_BrowserAuth(t *testing.T) {
+	streams, _ := newTestStreamManager(t)
+	created, err := streams.CreateStream("public", nil)
+	require.NoError(t, err)
+
+	server, err := api.NewServer(api.ServerConfig{
+		Port:        8080,
+		APIKey:      "test-key",
+		Version:     "test",
+		CORSOrigins: []string{"https://dashboard.example.com"},
+	})
+	require.NoError(t, err)
+	server.SetDependencies(streams, nil, nil, nil, nil)
+	httpServer := httptest.NewServer(server.Handler())
+	defer httpServer.Close()
+
+	streamURL := httpServer.URL + "/api/streams/" + created.ID
+	wsURL := strings.Replace(streamURL, "http://", "ws://", 1) + "/ws"
+
+	// The dashboard exchanges its API key for a relay token server-side
+	req, err := http.NewRequest(http.MethodPost, streamURL+"/token", nil)
+	require.NoError(t, err)
+	req.Header.Set("X-API-Key", "test-key")
+	resp, err := http.DefaultClient.Do(req)
+	require.NoError(t, err)
+	var issued models.RelayTokenResponse
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&issued))
+	resp.Body.Close()
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	require.NotEmpty(t, issued.Token)
+
+	dial := func(origin string, protocols ...string) (*gorillaws.Conn, *http.Response, error) {
+		dialer := gorillaws.Dialer{Subprotocols: protocols}
+		return dialer.Dial(wsURL, http.Header{"Origin": {origin}})
+	}
+
+	t.Run("WebSocket with the token subprotocol", func(t *testing.T) {
+		conn, resp, err := dial("https://dashboard.example.com", handlers.RelayTokenProtocol, issued.Token)
+		require.NoError(t, err)
+		defer conn.Close()
+		assert.Equal(t, handlers.RelayTokenProtocol, resp.Header.Get("Sec-WebSocket-Protocol"))
+	})
+
+	t.Run("WebSocket from an unlisted origin", func(t *testing.T) {
+		_, resp, err := dial("https://evil.example.net", handlers.RelayTokenProtocol, issued.Token)
+		require.Error(t, err)
+		require.NotNil(t, resp)
+		assert.Equal(t, http.StatusForbidden, resp.StatusCode)
+	})
+
+	t.Run("WebSocket without credentials", func(t *testing.T) {
+		_, resp, err := dial("https://dashboard.example.com")
+		require.Error(t, err)
+		require.NotNil(t, resp)
+		assert.Equal(t, http.StatusUnauthorized, resp.StatusCode)
+	})
+
+	t.Run("SSE with the token query parameter", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, streamURL+"/events?token="+url.QueryEscape(issued.Token), nil)
+		require.NoError(t, err)
+		resp, err := http.DefaultClient.Do(req)
+		require.NoError(t, err)
+		defer resp.Body.Close()
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+		assert.Equal(t, "text/event-stream", resp.Header.Get("Content-Type"))
+	})
+
+	t.Run("SSE with another stream's token", func(t *testing.T) {
+		other, err := streams.CreateStream("public", nil)
+		require.NoError(t, err)
+
+		resp, err := http.Get(httpServer.URL + "/api/streams/" + other.ID + "/events?token=" + url.QueryEscape(issued.Token))
+		require.NoError(t, err)
+		resp.Body.Close()
+		assert.Equal(t, http.StatusUnauthorized, resp.StatusCode)
+	})
+
+	t.Run("token for an unknown stream", func(t *testing.T) {
+		req, err := http.NewRequest(http.MethodPost, httpServer.URL+"/api/streams/missing/token", nil)
+		require.NoError(t, err)
+		req.Header.Set("X-API-Key", "test-key")
+		resp, err := http.DefaultClient.Do(req)
+		require.NoError(t, err)
+		resp.Body.Close()
+		assert.Equal(t, http.StatusNotFound, resp.StatusCode)
+	})
+}
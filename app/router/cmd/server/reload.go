@@ -0,0 +1,76 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/rs/zerolog/log"
+	"router/internal/handlers"
+	"router/internal/models"
+)
+
+// reloadConfig re-reads the configuration and applies the settings that can
+// change at runtime through the config manager. Settings that need a restart
+// are logged and ignored.
+func reloadConfig(current *Config, manager handlers.ConfigManager) error {
+	next, err := LoadConfig()
+	if err != nil {
+		return fmt.Errorf("failed to reload configuration: %w", err)
+	}
+
+	live, err := manager.GetConfig()
+	if err != nil {
+		return fmt.Errorf("failed to read live configuration: %w", err)
+	}
+
+	var update models.ConfigUpdateRequest
+	changed := false
+	if next.RateLimit != live.RateLimit {
+		update.RateLimit = &next.RateLimit
+		changed = true
+	}
+	if next.MaxConnections != live.MaxConnections {
+		update.MaxConnections = &next.MaxConnections
+		changed = true
+	}
+	if next.LogLevel != live.LogLevel {
+		update.LogLevel = &next.LogLevel
+		changed = true
+	}
+
+	logIgnoredChange("port", current.Port != next.Port)
+	logIgnoredChange("api_key", current.APIKey != next.APIKey)
+	logIgnoredChange("read_timeout", current.ReadTimeout != next.ReadTimeout)
+	logIgnoredChange("write_timeout", current.WriteTimeout != next.WriteTimeout)
+	logIgnoredChange("idle_timeout", current.IdleTimeout != next.IdleTimeout)
+	logIgnoredChange("stream_state_path", current.StreamStatePath != next.StreamStatePath)
+	logIgnoredChange("cors_origins", strings.Join(current.CORSOrigins, ",") != strings.Join(next.CORSOrigins, ","))
+
+	if !changed {
+		log.Info().Msg("Configuration reloaded, no live changes")
+		return nil
+	}
+
+	if err := update.Validate(); err != nil {
+		return fmt.Errorf("invalid reloaded configuration: %w", err)
+	}
+
+	applied, err := manager.UpdateConfig(update)
+	if err != nil {
+		return fmt.Errorf("failed to apply reloaded configuration: %w", err)
+	}
+
+	log.Info().
+		Int("rate_limit", applied.RateLimit).
+		Int("max_connections", applied.MaxConnections).
+		Str("log_level", applied.LogLevel).
+		Msg("Configuration reloaded")
+	return nil
+}
+
+// logIgnoredChange warns about a changed setting that only takes effect on restart
+func logIgnoredChange(field string, changed bool) {
+	if changed {
+		log.Warn().Str("field", field).Msg("Configuration change requires restart, ignoring")
+	}
+}
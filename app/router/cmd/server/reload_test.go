@@ -0,0 +1,89 @@
+package main
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"router/internal/api"
+)
+
+func TestReloadConfig(t *testing.T) {
+	writeConfig := func(t *testing.T, path, content string) {
+		t.Helper()
+		require.NoError(t, os.WriteFile(path, []byte(content), 0o600))
+	}
+
+	t.Run("applies new rate limit and log level", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "server.yaml")
+		writeConfig(t, path, "api_key: file-key\nrate_limit: 5\nlog_level: info\n")
+		os.Setenv("CONFIG_PATH", path)
+		defer os.Unsetenv("CONFIG_PATH")
+		defer zerolog.SetGlobalLevel(zerolog.InfoLevel)
+
+		config, err := LoadConfig()
+		require.NoError(t, err)
+		assert.Equal(t, 5, config.RateLimit)
+
+		server, err := api.NewServer(api.ServerConfig{
+			Port:       config.Port,
+			APIKey:     config.APIKey,
+			RateLimit:  config.RateLimit,
+			RateWindow: time.Minute,
+			LogLevel:   config.LogLevel,
+		})
+		require.NoError(t, err)
+
+		manager := NewConfigManagerImpl(config)
+		manager.SetApplier(server)
+
+		// Operator lowers the limit and raises verbosity
+		writeConfig(t, path, "api_key: file-key\nrate_limit: 2\nlog_level: debug\nport: 9999\n")
+		require.NoError(t, reloadConfig(config, manager))
+
+		live, err := manager.GetConfig()
+		require.NoError(t, err)
+		assert.Equal(t, 2, live.RateLimit)
+		assert.Equal(t, "debug", live.LogLevel)
+		assert.Equal(t, zerolog.DebugLevel, zerolog.GlobalLevel())
+
+		// Port cannot change live
+		assert.Equal(t, 8080, config.Port)
+
+		// The server enforces the new limit
+		codes := make([]int, 0, 3)
+		for i := 0; i < 3; i++ {
+			req := httptest.NewRequest(http.MethodGet, "/health", nil)
+			req.RemoteAddr = "10.0.0.1:1234"
+			w := httptest.NewRecorder()
+			server.Handler().ServeHTTP(w, req)
+			codes = append(codes, w.Code)
+		}
+		assert.Equal(t, []int{http.StatusOK, http.StatusOK, http.StatusTooManyRequests}, codes)
+	})
+
+	t.Run("keeps current config when reload fails", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "server.yaml")
+		writeConfig(t, path, "api_key: file-key\nrate_limit: 5\n")
+		os.Setenv("CONFIG_PATH", path)
+		defer os.Unsetenv("CONFIG_PATH")
+
+		config, err := LoadConfig()
+		require.NoError(t, err)
+		manager := NewConfigManagerImpl(config)
+
+		writeConfig(t, path, "api_key: file-key\nrate_limit: -1\n")
+		err = reloadConfig(config, manager)
+		assert.Error(t, err)
+
+		live, err := manager.GetConfig()
+		require.NoError(t, err)
+		assert.Equal(t, 5, live.RateLimit)
+	})
+}
@@ -3,42 +3,149 @@ package api
 import (
 	"context"
 	"encoding/json"
+	"errors"
+	"fmt"
 	"net/http"
+	"sync"
 	"time"
 
 	"github.com/rs/zerolog"
 	"github.com/shopspring/decimal"
 	"router/internal/orders"
+	"router/internal/trace"
 )
 
 // OrderManager defines the interface for order management
 type OrderManager interface {
 	PlaceBracketOrder(ctx context.Context, req *orders.PlaceBracketRequest) (*orders.PlaceBracketResponse, error)
 	CancelOrder(ctx context.Context, req *orders.CancelRequest) error
+	CancelBracket(ctx context.Context, bracketID string) error
 	CloseAllPositions(ctx context.Context, req *orders.CloseAllRequest) error
 	ReconcileOrder(ctx context.Context, clientOrderID string) error
+	EngageKillSwitch(ctx context.Context, req *orders.KillSwitchRequest) (orders.KillSwitchState, error)
+	ReleaseKillSwitch()
+	KillSwitch() orders.KillSwitchState
 }
 
 // Handlers contains all HTTP handlers
 type Handlers struct {
-	orderManager OrderManager
-	logger       zerolog.Logger
+	orderManager   OrderManager
+	logger         zerolog.Logger
+	spotEnabled    bool
+	futuresEnabled bool
+	venueResolver  VenueResolver
+
+	readinessChecks  []namedReadinessCheck
+	readinessTimeout time.Duration
+}
+
+// ReadinessCheck probes one dependency, returning a short status such as
+// "ok" or "connected", or an error when the dependency is not ready
+type ReadinessCheck func(ctx context.Context) (string, error)
+
+type namedReadinessCheck struct {
+	name  string
+	check ReadinessCheck
+}
+
+// defaultReadinessTimeout bounds each readiness sub-check
+const defaultReadinessTimeout = 2 * time.Second
+
+// VenueResolver picks the venue for a symbol, reporting true for futures
+type VenueResolver interface {
+	Resolve(ctx context.Context, symbol string) (bool, error)
+}
+
+// HandlersOption configures Handlers
+type HandlersOption func(*Handlers)
+
+// WithVenues restricts which venues requests may target
+func WithVenues(spotEnabled, futuresEnabled bool) HandlersOption {
+	return func(h *Handlers) {
+		h.spotEnabled = spotEnabled
+		h.futuresEnabled = futuresEnabled
+	}
+}
+
+// WithVenueResolver routes requests that name neither a venue nor
+// is_futures to wherever their symbol trades
+func WithVenueResolver(resolver VenueResolver) HandlersOption {
+	return func(h *Handlers) {
+		h.venueResolver = resolver
+	}
+}
+
+// WithReadinessCheck adds a named dependency check to /readyz
+func WithReadinessCheck(name string, check ReadinessCheck) HandlersOption {
+	return func(h *Handlers) {
+		h.readinessChecks = append(h.readinessChecks, namedReadinessCheck{name: name, check: check})
+	}
+}
+
+// WithReadinessTimeout sets how long each readiness sub-check may run
+func WithReadinessTimeout(timeout time.Duration) HandlersOption {
+	return func(h *Handlers) {
+		h.readinessTimeout = timeout
+	}
 }
 
 // NewHandlers creates new handlers instance
-func NewHandlers(orderManager OrderManager, logger zerolog.Logger) *Handlers {
-	return &Handlers{
-		orderManager: orderManager,
-		logger:       logger,
+func NewHandlers(orderManager OrderManager, logger zerolog.Logger, opts ...HandlersOption) *Handlers {
+	h := &Handlers{
+		orderManager:     orderManager,
+		logger:           logger,
+		spotEnabled:      true,
+		futuresEnabled:   true,
+		readinessTimeout: defaultReadinessTimeout,
+	}
+
+	for _, opt := range opts {
+		opt(h)
 	}
+
+	return h
+}
+
+// checkVenue returns an error when the targeted venue is disabled
+func (h *Handlers) checkVenue(isFutures bool) error {
+	if isFutures && !h.futuresEnabled {
+		return fmt.Errorf("futures trading is disabled on this router")
+	}
+	if !isFutures && !h.spotEnabled {
+		return fmt.Errorf("spot trading is disabled on this router")
+	}
+	return nil
+}
+
+// resolveVenue settles whether a request targets futures from its venue
+// field, its is_futures flag or, when neither is set, the venue resolver
+func (h *Handlers) resolveVenue(ctx context.Context, symbol, venue string, isFutures bool) (bool, error) {
+	switch venue {
+	case orders.VenueSpot:
+		if isFutures {
+			return false, fmt.Errorf("venue %s conflicts with is_futures", venue)
+		}
+		return false, nil
+	case orders.VenueFutures:
+		return true, nil
+	case "":
+	default:
+		return false, fmt.Errorf("unknown venue %q", venue)
+	}
+
+	if isFutures || symbol == "" || h.venueResolver == nil {
+		return isFutures, nil
+	}
+	return h.venueResolver.Resolve(ctx, symbol)
 }
 
 // PlaceBracketHandler handles POST /place_bracket
 func (h *Handlers) PlaceBracketHandler(w http.ResponseWriter, r *http.Request) {
+	logger := trace.Logger(r.Context(), h.logger)
 	start := time.Now()
 
 	if r.Method != http.MethodPost {
-		h.logger.Warn().
+		logger.Warn().
 			Str("method", r.Method).
 			Str("path", r.URL.Path).
 			Str("remote_addr", r.RemoteAddr).
@@ -49,7 +156,7 @@ func (h *Handlers) PlaceBracketHandler(w http.ResponseWriter, r *http.Request) {
 
 	var req orders.PlaceBracketRequest
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		h.logger.Error().
+		logger.Error().
 			Err(err).
 			Str("path", r.URL.Path).
 			Str("remote_addr", r.RemoteAddr).
@@ -58,6 +165,27 @@ func (h *Handlers) PlaceBracketHandler(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	isFutures, err := h.resolveVenue(r.Context(), req.Symbol, req.Venue, req.IsFutures)
+	if err != nil {
+		logger.Warn().
+			Err(err).
+			Str("symbol", req.Symbol).
+			Str("venue", req.Venue).
+			Msg("Failed to resolve bracket order venue")
+		writeError(w, managerErrorStatus(err), err.Error())
+		return
+	}
+	req.IsFutures = isFutures
+
+	if err := h.checkVenue(req.IsFutures); err != nil {
+		logger.Warn().
+			Str("symbol", req.Symbol).
+			Bool("is_futures", req.IsFutures).
+			Msg("Bracket order targets disabled venue")
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
 	// Set default order type if not provided
 	if req.OrderType == "" {
 		if req.EntryPrice.IsZero() {
@@ -68,7 +196,7 @@ func (h *Handlers) PlaceBracketHandler(w http.ResponseWriter, r *http.Request) {
 	}
 
 	// Log the bracket order request details
-	h.logger.Info().
+	logger.Info().
 		Str("symbol", req.Symbol).
 		Str("side", req.Side).
 		Str("order_type", req.OrderType).
@@ -81,20 +209,21 @@ func (h *Handlers) PlaceBracketHandler(w http.ResponseWriter, r *http.Request) {
 
 	resp, err := h.orderManager.PlaceBracketOrder(r.Context(), &req)
 	if err != nil {
-		h.logger.Error().
+		logger.Error().
 			Err(err).
 			Str("symbol", req.Symbol).
 			Str("side", req.Side).
 			Dur("duration", time.Since(start)).
 			Msg("Failed to place bracket order")
-		writeError(w, http.StatusBadRequest, err.Error())
+		writeError(w, managerErrorStatus(err), err.Error())
 		return
 	}
 
-	h.logger.Info().
+	logger.Info().
 		Str("bracket_id", resp.BracketOrderID).
 		Str("symbol", resp.Symbol).
 		Str("side", resp.Side).
+		Strs("warnings", resp.Warnings).
 		Dur("duration", time.Since(start)).
 		Msg("Bracket order placed successfully")
 
@@ -103,10 +232,11 @@ func (h *Handlers) PlaceBracketHandler(w http.ResponseWriter, r *http.Request) {
 
 // CancelHandler handles POST /cancel
 func (h *Handlers) CancelHandler(w http.ResponseWriter, r *http.Request) {
+	logger := trace.Logger(r.Context(), h.logger)
 	start := time.Now()
 
 	if r.Method != http.MethodPost {
-		h.logger.Warn().
+		logger.Warn().
 			Str("method", r.Method).
 			Str("path", r.URL.Path).
 			Str("remote_addr", r.RemoteAddr).
@@ -117,7 +247,7 @@ func (h *Handlers) CancelHandler(w http.ResponseWriter, r *http.Request) {
 
 	var req orders.CancelRequest
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		h.logger.Error().
+		logger.Error().
 			Err(err).
 			Str("path", r.URL.Path).
 			Str("remote_addr", r.RemoteAddr).
@@ -126,24 +256,24 @@ func (h *Handlers) CancelHandler(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	h.logger.Info().
+	logger.Info().
 		Str("symbol", req.Symbol).
 		Int64("order_id", req.OrderID).
 		Str("client_order_id", req.ClientOrderID).
 		Msg("Processing cancel order request")
 
 	if err := h.orderManager.CancelOrder(r.Context(), &req); err != nil {
-		h.logger.Error().
+		logger.Error().
 			Err(err).
 			Str("symbol", req.Symbol).
 			Int64("order_id", req.OrderID).
 			Dur("duration", time.Since(start)).
 			Msg("Failed to cancel order")
-		writeError(w, http.StatusBadRequest, err.Error())
+		writeError(w, managerErrorStatus(err), err.Error())
 		return
 	}
 
-	h.logger.Info().
+	logger.Info().
 		Str("symbol", req.Symbol).
 		Int64("order_id", req.OrderID).
 		Dur("duration", time.Since(start)).
@@ -152,12 +282,134 @@ func (h *Handlers) CancelHandler(w http.ResponseWriter, r *http.Request) {
 	writeJSON(w, http.StatusOK, map[string]string{"status": "success"})
 }
 
+// CancelBracketHandler handles POST /cancel_bracket
+func (h *Handlers) CancelBracketHandler(w http.ResponseWriter, r *http.Request) {
+	logger := trace.Logger(r.Context(), h.logger)
+	start := time.Now()
+
+	if r.Method != http.MethodPost {
+		logger.Warn().
+			Str("method", r.Method).
+			Str("path", r.URL.Path).
+			Str("remote_addr", r.RemoteAddr).
+			Msg("Invalid method for cancel_bracket")
+		writeError(w, http.StatusMethodNotAllowed, "Method not allowed")
+		return
+	}
+
+	var req orders.CancelBracketRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		logger.Error().
+			Err(err).
+			Str("path", r.URL.Path).
+			Str("remote_addr", r.RemoteAddr).
+			Msg("Failed to decode cancel bracket request")
+		writeError(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	if req.BracketOrderID == "" {
+		writeError(w, http.StatusBadRequest, "bracket_order_id is required")
+		return
+	}
+
+	logger.Info().
+		Str("bracket_id", req.BracketOrderID).
+		Msg("Processing cancel bracket request")
+
+	if err := h.orderManager.CancelBracket(r.Context(), req.BracketOrderID); err != nil {
+		logger.Error().
+			Err(err).
+			Str("bracket_id", req.BracketOrderID).
+			Dur("duration", time.Since(start)).
+			Msg("Failed to cancel bracket")
+		writeError(w, managerErrorStatus(err), err.Error())
+		return
+	}
+
+	logger.Info().
+		Str("bracket_id", req.BracketOrderID).
+		Dur("duration", time.Since(start)).
+		Msg("Bracket canceled successfully")
+
+	writeJSON(w, http.StatusOK, map[string]string{"status": "success"})
+}
+
+// KillSwitchHandler handles /kill_switch: POST engages, DELETE releases and
+// GET reports the current state
+func (h *Handlers) KillSwitchHandler(w http.ResponseWriter, r *http.Request) {
+	logger := trace.Logger(r.Context(), h.logger)
+
+	switch r.Method {
+	case http.MethodGet:
+		writeJSON(w, http.StatusOK, h.orderManager.KillSwitch())
+
+	case http.MethodPost:
+		var req orders.KillSwitchRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			logger.Error().
+				Err(err).
+				Str("path", r.URL.Path).
+				Str("remote_addr", r.RemoteAddr).
+				Msg("Failed to decode kill switch request")
+			writeError(w, http.StatusBadRequest, "Invalid request body")
+			return
+		}
+		if req.Reason == "" {
+			writeError(w, http.StatusBadRequest, "reason is required")
+			return
+		}
+
+		logger.Warn().
+			Str("reason", req.Reason).
+			Bool("close_positions", req.ClosePositions).
+			Strs("symbols", req.Symbols).
+			Str("remote_addr", r.RemoteAddr).
+			Msg("Processing kill switch request")
+
+		state, err := h.orderManager.EngageKillSwitch(r.Context(), &req)
+		if err != nil && !state.Engaged {
+			writeError(w, managerErrorStatus(err), err.Error())
+			return
+		}
+
+		resp := struct {
+			orders.KillSwitchState
+			CloseError string `json:"close_error,omitempty"`
+		}{KillSwitchState: state}
+		if err != nil {
+			// The switch is engaged; only closing positions failed
+			logger.Error().
+				Err(err).
+				Msg("Kill switch failed to close positions")
+			resp.CloseError = err.Error()
+		}
+		writeJSON(w, http.StatusOK, resp)
+
+	case http.MethodDelete:
+		logger.Warn().
+			Str("remote_addr", r.RemoteAddr).
+			Msg("Processing kill switch release")
+		h.orderManager.ReleaseKillSwitch()
+		writeJSON(w, http.StatusOK, h.orderManager.KillSwitch())
+
+	default:
+		logger.Warn().
+			Str("method", r.Method).
+			Str("path", r.URL.Path).
+			Str("remote_addr", r.RemoteAddr).
+			Msg("Invalid method for kill_switch")
+		writeError(w, http.StatusMethodNotAllowed, "Method not allowed")
+	}
+}
+
 // CloseAllHandler handles POST /close_all
 func (h *Handlers) CloseAllHandler(w http.ResponseWriter, r *http.Request) {
+	logger := trace.Logger(r.Context(), h.logger)
 	start := time.Now()
 
 	if r.Method != http.MethodPost {
-		h.logger.Warn().
+		logger.Warn().
 			Str("method", r.Method).
 			Str("path", r.URL.Path).
 			Str("remote_addr", r.RemoteAddr).
@@ -168,7 +420,7 @@ func (h *Handlers) CloseAllHandler(w http.ResponseWriter, r *http.Request) {
 
 	var req orders.CloseAllRequest
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		h.logger.Error().
+		logger.Error().
 			Err(err).
 			Str("path", r.URL.Path).
 			Str("remote_addr", r.RemoteAddr).
@@ -177,23 +429,44 @@ func (h *Handlers) CloseAllHandler(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	h.logger.Info().
+	isFutures, err := h.resolveVenue(r.Context(), req.Symbol, req.Venue, req.IsFutures)
+	if err != nil {
+		logger.Warn().
+			Err(err).
+			Str("symbol", req.Symbol).
+			Str("venue", req.Venue).
+			Msg("Failed to resolve close all venue")
+		writeError(w, managerErrorStatus(err), err.Error())
+		return
+	}
+	req.IsFutures = isFutures
+
+	if err := h.checkVenue(req.IsFutures); err != nil {
+		logger.Warn().
+			Str("symbol", req.Symbol).
+			Bool("is_futures", req.IsFutures).
+			Msg("Close all targets disabled venue")
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	logger.Info().
 		Str("symbol", req.Symbol).
 		Bool("is_futures", req.IsFutures).
 		Msg("Processing close all positions request")
 
 	if err := h.orderManager.CloseAllPositions(r.Context(), &req); err != nil {
-		h.logger.Error().
+		logger.Error().
 			Err(err).
 			Str("symbol", req.Symbol).
 			Bool("is_futures", req.IsFutures).
 			Dur("duration", time.Since(start)).
 			Msg("Failed to close all positions")
-		writeError(w, http.StatusBadRequest, err.Error())
+		writeError(w, managerErrorStatus(err), err.Error())
 		return
 	}
 
-	h.logger.Info().
+	logger.Info().
 		Str("symbol", req.Symbol).
 		Bool("is_futures", req.IsFutures).
 		Dur("duration", time.Since(start)).
@@ -242,15 +515,100 @@ func (h *Handlers) ReadyzHandler(w http.ResponseWriter, r *http.Request) {
 		Str("remote_addr", r.RemoteAddr).
 		Msg("Readiness check requested")
 
-	// TODO: Check actual readiness (Binance connectivity, etc.)
-	writeJSON(w, http.StatusOK, map[string]string{
+	checks, ready := h.runReadinessChecks(r.Context())
+
+	status := http.StatusOK
+	response := map[string]interface{}{
 		"status":  "ready",
 		"service": "order-router",
-	})
+	}
+	if len(checks) > 0 {
+		response["checks"] = checks
+	}
+	if !ready {
+		status = http.StatusServiceUnavailable
+		response["status"] = "not_ready"
+		h.logger.Warn().
+			Interface("checks", checks).
+			Msg("Readiness check failed")
+	}
+
+	writeJSON(w, status, response)
+}
+
+// runReadinessChecks runs every sub-check concurrently, each bounded by the
+// readiness timeout. The service is ready only if every check passes.
+func (h *Handlers) runReadinessChecks(ctx context.Context) (map[string]string, bool) {
+	results := make(map[string]string, len(h.readinessChecks))
+	ready := true
+
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	for _, rc := range h.readinessChecks {
+		wg.Add(1)
+		go func(rc namedReadinessCheck) {
+			defer wg.Done()
+
+			checkCtx, cancel := context.WithTimeout(ctx, h.readinessTimeout)
+			defer cancel()
+
+			status, err := runReadinessCheck(checkCtx, rc.check)
+
+			mu.Lock()
+			defer mu.Unlock()
+			if err != nil {
+				results[rc.name] = "error: " + err.Error()
+				ready = false
+				return
+			}
+			results[rc.name] = status
+		}(rc)
+	}
+	wg.Wait()
+
+	return results, ready
+}
+
+// runReadinessCheck returns once the check finishes or ctx expires, so a
+// check that ignores its context cannot stall the endpoint
+func runReadinessCheck(ctx context.Context, check ReadinessCheck) (string, error) {
+	type result struct {
+		status string
+		err    error
+	}
+
+	done := make(chan result, 1)
+	go func() {
+		status, err := check(ctx)
+		done <- result{status: status, err: err}
+	}()
+
+	select {
+	case res := <-done:
+		return res.status, res.err
+	case <-ctx.Done():
+		return "", fmt.Errorf("timed out: %w", ctx.Err())
+	}
 }
 
 // Helper functions
 
+// managerErrorStatus maps an order manager error to an HTTP status. A
+// request whose deadline expired while Binance was still working is reported
+// as a gateway timeout rather than a bad request.
+func managerErrorStatus(err error) int {
+	switch {
+	case errors.Is(err, orders.ErrKillSwitchEngaged):
+		return http.StatusServiceUnavailable
+	case errors.Is(err, orders.ErrBracketNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, context.DeadlineExceeded):
+		return http.StatusGatewayTimeout
+	default:
+		return http.StatusBadRequest
+	}
+}
+
 func writeJSON(w http.ResponseWriter, status int, data interface{}) {
 	w.Header().Set("Content-Type", "application/json")
 	w.WriteHeader(status)
@@ -263,22 +621,35 @@ func writeError(w http.ResponseWriter, status int, message string) {
 	writeJSON(w, status, map[string]string{"error": message})
 }
 
-// ParseDecimalArray parses an array of decimal strings
+// ParseDecimalArray parses an array of decimal strings, skipping values of
+// unsupported types
 func ParseDecimalArray(values []interface{}) ([]decimal.Decimal, error) {
+	return parseDecimalArray(values, false)
+}
+
+// ParseDecimalArrayStrict parses an array of decimal strings like
+// ParseDecimalArray but rejects nulls and values of unsupported types
+func ParseDecimalArrayStrict(values []interface{}) ([]decimal.Decimal, error) {
+	return parseDecimalArray(values, true)
+}
+
+func parseDecimalArray(values []interface{}, strict bool) ([]decimal.Decimal, error) {
 	result := make([]decimal.Decimal, 0, len(values))
-	for _, v := range values {
+	for i, v := range values {
 		str, ok := v.(string)
 		if !ok {
 			// Try float64
 			if f, ok := v.(float64); ok {
 				str = decimal.NewFromFloat(f).String()
+			} else if strict {
+				return nil, fmt.Errorf("value %d: unsupported type %T", i, v)
 			} else {
 				continue
 			}
 		}
 		d, err := decimal.NewFromString(str)
 		if err != nil {
-			return nil, err
+			return nil, fmt.Errorf("value %d: %w", i, err)
 		}
 		result = append(result, d)
 	}
@@ -5,16 +5,25 @@ import (
 	"context"
 	"encoding/json"
 	"errors"
+	"fmt"
 	"io"
 	"net/http"
 	"net/http/httptest"
 	"testing"
+	"time"
 
+	"github.com/gin-gonic/gin"
 	"github.com/rs/zerolog"
 	"github.com/shopspring/decimal"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"router/internal/auth"
+	"router/internal/binance"
 	"router/internal/orders"
+	"router/internal/rest"
+	"router/internal/testutil"
+	"router/internal/trace"
 )
 
 // MockOrderManager is a mock implementation of the order manager interface
@@ -35,6 +44,11 @@ func (m *MockOrderManager) CancelOrder(ctx context.Context, req *orders.CancelRe
 	return args.Error(0)
 }
 
+func (m *MockOrderManager) CancelBracket(ctx context.Context, bracketID string) error {
+	args := m.Called(ctx, bracketID)
+	return args.Error(0)
+}
+
 func (m *MockOrderManager) CloseAllPositions(ctx context.Context, req *orders.CloseAllRequest) error {
 	args := m.Called(ctx, req)
 	return args.Error(0)
@@ -45,6 +59,20 @@ func (m *MockOrderManager) ReconcileOrder(ctx context.Context, clientOrderID str
 	return args.Error(0)
 }
 
+func (m *MockOrderManager) EngageKillSwitch(ctx context.Context, req *orders.KillSwitchRequest) (orders.KillSwitchState, error) {
+	args := m.Called(ctx, req)
+	return args.Get(0).(orders.KillSwitchState), args.Error(1)
+}
+
+func (m *MockOrderManager) ReleaseKillSwitch() {
+	m.Called()
+}
+
+func (m *MockOrderManager) KillSwitch() orders.KillSwitchState {
+	args := m.Called()
+	return args.Get(0).(orders.KillSwitchState)
+}
+
 func TestHealthzHandler(t *testing.T) {
 	logger := zerolog.Nop()
 	mockManager := new(MockOrderManager)
@@ -139,6 +167,72 @@ func TestReadyzHandler(t *testing.T) {
 	}
 }
 
+func TestReadyzHandler_SubChecks(t *testing.T) {
+	ok := func(status string) ReadinessCheck {
+		return func(ctx context.Context) (string, error) { return status, nil }
+	}
+
+	newHandlers := func(failing ReadinessCheck) *Handlers {
+		return NewHandlers(new(MockOrderManager), zerolog.Nop(),
+			WithReadinessTimeout(50*time.Millisecond),
+			WithReadinessCheck("binance_rest", ok("ok")),
+			WithReadinessCheck("binance_ws", ok("connected")),
+			WithReadinessCheck("rate_limiter", ok("ok")),
+			WithReadinessCheck("exchange_info", failing),
+		)
+	}
+
+	serve := func(h *Handlers) (int, map[string]interface{}) {
+		w := httptest.NewRecorder()
+		h.ReadyzHandler(w, httptest.NewRequest(http.MethodGet, "/readyz", nil))
+
+		var body map[string]interface{}
+		assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &body))
+		return w.Code, body
+	}
+
+	t.Run("ready when all checks pass", func(t *testing.T) {
+		code, body := serve(newHandlers(ok("fresh")))
+
+		assert.Equal(t, http.StatusOK, code)
+		assert.Equal(t, "ready", body["status"])
+		assert.Equal(t, map[string]interface{}{
+			"binance_rest":  "ok",
+			"binance_ws":    "connected",
+			"rate_limiter":  "ok",
+			"exchange_info": "fresh",
+		}, body["checks"])
+	})
+
+	t.Run("not ready when one check fails", func(t *testing.T) {
+		code, body := serve(newHandlers(func(ctx context.Context) (string, error) {
+			return "", errors.New("stale")
+		}))
+
+		assert.Equal(t, http.StatusServiceUnavailable, code)
+		assert.Equal(t, "not_ready", body["status"])
+		checks := body["checks"].(map[string]interface{})
+		assert.Equal(t, "error: stale", checks["exchange_info"])
+		assert.Equal(t, "ok", checks["binance_rest"])
+	})
+
+	t.Run("hung check times out", func(t *testing.T) {
+		block := make(chan struct{})
+		defer close(block)
+
+		start := time.Now()
+		code, body := serve(newHandlers(func(ctx context.Context) (string, error) {
+			<-block // ignores ctx
+			return "fresh", nil
+		}))
+
+		assert.Less(t, time.Since(start), time.Second)
+		assert.Equal(t, http.StatusServiceUnavailable, code)
+		checks := body["checks"].(map[string]interface{})
+		assert.Contains(t, checks["exchange_info"], "timed out")
+	})
+}
+
 func TestPlaceBracketHandler(t *testing.T) {
 	logger := zerolog.Nop()
 	mockManager := new(MockOrderManager)
@@ -213,6 +307,24 @@ func TestPlaceBracketHandler(t *testing.T) {
 			wantStatus: http.StatusBadRequest,
 			wantErr:    "insufficient balance",
 		},
+		{
+			name:   "kill switch engaged",
+			method: http.MethodPost,
+			body: &orders.PlaceBracketRequest{
+				Symbol:           "BTCUSDT",
+				Side:             "BUY",
+				Quantity:         decimal.RequireFromString("0.001"),
+				EntryPrice:       decimal.RequireFromString("50000"),
+				TakeProfitPrices: []decimal.Decimal{decimal.RequireFromString("51000")},
+				StopLossPrice:    decimal.RequireFromString("49000"),
+			},
+			setupMock: func() {
+				mockManager.On("PlaceBracketOrder", mock.Anything, mock.AnythingOfType("*orders.PlaceBracketRequest")).
+					Return(nil, fmt.Errorf("%w: maintenance", orders.ErrKillSwitchEngaged)).Once()
+			},
+			wantStatus: http.StatusServiceUnavailable,
+			wantErr:    "kill switch engaged: maintenance",
+		},
 		{
 			name:   "market order with zero entry price",
 			method: http.MethodPost,
@@ -382,6 +494,204 @@ func TestCancelHandler(t *testing.T) {
 	}
 }
 
+func TestCancelBracketHandler(t *testing.T) {
+	logger := zerolog.Nop()
+	mockManager := new(MockOrderManager)
+	handlers := NewHandlers(mockManager, logger)
+
+	tests := []struct {
+		name       string
+		method     string
+		body       interface{}
+		setupMock  func()
+		wantStatus int
+		wantErr    string
+	}{
+		{
+			name:   "successful cancel",
+			method: http.MethodPost,
+			body:   &orders.CancelBracketRequest{BracketOrderID: "3f2a9c1e-0000-0000-0000-000000000000"},
+			setupMock: func() {
+				mockManager.On("CancelBracket", mock.Anything, "3f2a9c1e-0000-0000-0000-000000000000").
+					Return(nil).Once()
+			},
+			wantStatus: http.StatusOK,
+		},
+		{
+			name:       "invalid method",
+			method:     http.MethodGet,
+			setupMock:  func() {},
+			wantStatus: http.StatusMethodNotAllowed,
+			wantErr:    "Method not allowed",
+		},
+		{
+			name:       "missing bracket ID",
+			method:     http.MethodPost,
+			body:       &orders.CancelBracketRequest{},
+			setupMock:  func() {},
+			wantStatus: http.StatusBadRequest,
+			wantErr:    "bracket_order_id is required",
+		},
+		{
+			name:   "unknown bracket",
+			method: http.MethodPost,
+			body:   &orders.CancelBracketRequest{BracketOrderID: "unknown"},
+			setupMock: func() {
+				mockManager.On("CancelBracket", mock.Anything, "unknown").
+					Return(fmt.Errorf("%w: unknown", orders.ErrBracketNotFound)).Once()
+			},
+			wantStatus: http.StatusNotFound,
+			wantErr:    "bracket not found: unknown",
+		},
+		{
+			name:   "cancel error",
+			method: http.MethodPost,
+			body:   &orders.CancelBracketRequest{BracketOrderID: "3f2a9c1e-0000-0000-0000-000000000000"},
+			setupMock: func() {
+				mockManager.On("CancelBracket", mock.Anything, "3f2a9c1e-0000-0000-0000-000000000000").
+					Return(errors.New("failed to get open orders: timeout")).Once()
+			},
+			wantStatus: http.StatusBadRequest,
+			wantErr:    "failed to get open orders: timeout",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockManager.ExpectedCalls = nil
+			tt.setupMock()
+
+			var body io.Reader
+			if tt.body != nil {
+				data, err := json.Marshal(tt.body)
+				assert.NoError(t, err)
+				body = bytes.NewReader(data)
+			}
+
+			req := httptest.NewRequest(tt.method, "/cancel_bracket", body)
+			w := httptest.NewRecorder()
+
+			handlers.CancelBracketHandler(w, req)
+
+			assert.Equal(t, tt.wantStatus, w.Code)
+
+			if tt.wantErr != "" {
+				var response map[string]string
+				err := json.Unmarshal(w.Body.Bytes(), &response)
+				assert.NoError(t, err)
+				assert.Equal(t, tt.wantErr, response["error"])
+			}
+
+			mockManager.AssertExpectations(t)
+		})
+	}
+}
+
+func TestKillSwitchHandler(t *testing.T) {
+	logger := zerolog.Nop()
+	engagedAt := time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+	engaged := orders.KillSwitchState{Engaged: true, Reason: "maintenance", EngagedAt: engagedAt}
+
+	tests := []struct {
+		name       string
+		method     string
+		body       interface{}
+		setupMock  func(m *MockOrderManager)
+		wantStatus int
+		wantBody   map[string]interface{}
+	}{
+		{
+			name:   "engage",
+			method: http.MethodPost,
+			body:   &orders.KillSwitchRequest{Reason: "maintenance", ClosePositions: true},
+			setupMock: func(m *MockOrderManager) {
+				m.On("EngageKillSwitch", mock.Anything, &orders.KillSwitchRequest{Reason: "maintenance", ClosePositions: true}).
+					Return(engaged, nil).Once()
+			},
+			wantStatus: http.StatusOK,
+			wantBody:   map[string]interface{}{"engaged": true, "reason": "maintenance", "engaged_at": "2024-03-01T12:00:00Z"},
+		},
+		{
+			name:   "engaged but closing failed",
+			method: http.MethodPost,
+			body:   &orders.KillSwitchRequest{Reason: "maintenance", ClosePositions: true},
+			setupMock: func(m *MockOrderManager) {
+				m.On("EngageKillSwitch", mock.Anything, mock.Anything).
+					Return(engaged, errors.New("failed to close positions: spot BTCUSDT: timeout")).Once()
+			},
+			wantStatus: http.StatusOK,
+			wantBody: map[string]interface{}{
+				"engaged":     true,
+				"reason":      "maintenance",
+				"engaged_at":  "2024-03-01T12:00:00Z",
+				"close_error": "failed to close positions: spot BTCUSDT: timeout",
+			},
+		},
+		{
+			name:       "engage requires reason",
+			method:     http.MethodPost,
+			body:       &orders.KillSwitchRequest{},
+			setupMock:  func(m *MockOrderManager) {},
+			wantStatus: http.StatusBadRequest,
+			wantBody:   map[string]interface{}{"error": "reason is required"},
+		},
+		{
+			name:   "release",
+			method: http.MethodDelete,
+			setupMock: func(m *MockOrderManager) {
+				m.On("ReleaseKillSwitch").Return().Once()
+				m.On("KillSwitch").Return(orders.KillSwitchState{}).Once()
+			},
+			wantStatus: http.StatusOK,
+			wantBody:   map[string]interface{}{"engaged": false, "engaged_at": "0001-01-01T00:00:00Z"},
+		},
+		{
+			name:   "status",
+			method: http.MethodGet,
+			setupMock: func(m *MockOrderManager) {
+				m.On("KillSwitch").Return(engaged).Once()
+			},
+			wantStatus: http.StatusOK,
+			wantBody:   map[string]interface{}{"engaged": true, "reason": "maintenance", "engaged_at": "2024-03-01T12:00:00Z"},
+		},
+		{
+			name:       "invalid method",
+			method:     http.MethodPut,
+			setupMock:  func(m *MockOrderManager) {},
+			wantStatus: http.StatusMethodNotAllowed,
+			wantBody:   map[string]interface{}{"error": "Method not allowed"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockManager := new(MockOrderManager)
+			tt.setupMock(mockManager)
+			handlers := NewHandlers(mockManager, logger)
+
+			var body io.Reader
+			if tt.body != nil {
+				data, err := json.Marshal(tt.body)
+				assert.NoError(t, err)
+				body = bytes.NewReader(data)
+			}
+
+			req := httptest.NewRequest(tt.method, "/kill_switch", body)
+			w := httptest.NewRecorder()
+
+			handlers.KillSwitchHandler(w, req)
+
+			assert.Equal(t, tt.wantStatus, w.Code)
+
+			var response map[string]interface{}
+			assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+			assert.Equal(t, tt.wantBody, response)
+
+			mockManager.AssertExpectations(t)
+		})
+	}
+}
+
 func TestCloseAllHandler(t *testing.T) {
 	logger := zerolog.Nop()
 	mockManager := new(MockOrderManager)
@@ -476,6 +786,72 @@ func TestCloseAllHandler(t *testing.T) {
 	}
 }
 
+func TestDisabledVenue(t *testing.T) {
+	logger := zerolog.Nop()
+
+	t.Run("rejects futures bracket when futures disabled", func(t *testing.T) {
+		mockManager := new(MockOrderManager)
+		handlers := NewHandlers(mockManager, logger, WithVenues(true, false))
+
+		data, err := json.Marshal(&orders.PlaceBracketRequest{
+			Symbol:           "BTCUSDT",
+			Side:             "BUY",
+			Quantity:         decimal.NewFromFloat(0.01),
+			EntryPrice:       decimal.NewFromInt(50000),
+			TakeProfitPrices: []decimal.Decimal{decimal.NewFromInt(51000)},
+			StopLossPrice:    decimal.NewFromInt(49000),
+			IsFutures:        true,
+		})
+		assert.NoError(t, err)
+
+		req := httptest.NewRequest(http.MethodPost, "/place_bracket", bytes.NewReader(data))
+		w := httptest.NewRecorder()
+		handlers.PlaceBracketHandler(w, req)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		var response map[string]string
+		assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+		assert.Equal(t, "futures trading is disabled on this router", response["error"])
+		mockManager.AssertNotCalled(t, "PlaceBracketOrder", mock.Anything, mock.Anything)
+	})
+
+	t.Run("rejects futures close all when futures disabled", func(t *testing.T) {
+		mockManager := new(MockOrderManager)
+		handlers := NewHandlers(mockManager, logger, WithVenues(true, false))
+
+		data, err := json.Marshal(&orders.CloseAllRequest{Symbol: "BTCUSDT", IsFutures: true})
+		assert.NoError(t, err)
+
+		req := httptest.NewRequest(http.MethodPost, "/close_all", bytes.NewReader(data))
+		w := httptest.NewRecorder()
+		handlers.CloseAllHandler(w, req)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		mockManager.AssertNotCalled(t, "CloseAllPositions", mock.Anything, mock.Anything)
+	})
+
+	t.Run("allows spot bracket when only spot enabled", func(t *testing.T) {
+		mockManager := new(MockOrderManager)
+		handlers := NewHandlers(mockManager, logger, WithVenues(true, false))
+		mockManager.On("PlaceBracketOrder", mock.Anything, mock.AnythingOfType("*orders.PlaceBracketRequest")).
+			Return(&orders.PlaceBracketResponse{BracketOrderID: "b1", Symbol: "BTCUSDT", Side: "BUY"}, nil).Once()
+
+		data, err := json.Marshal(&orders.PlaceBracketRequest{
+			Symbol:   "BTCUSDT",
+			Side:     "BUY",
+			Quantity: decimal.NewFromFloat(0.01),
+		})
+		assert.NoError(t, err)
+
+		req := httptest.NewRequest(http.MethodPost, "/place_bracket", bytes.NewReader(data))
+		w := httptest.NewRecorder()
+		handlers.PlaceBracketHandler(w, req)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		mockManager.AssertExpectations(t)
+	})
+}
+
 func TestParseDecimalArray(t *testing.T) {
 	tests := []struct {
 		name    string
@@ -544,3 +920,182 @@ func TestParseDecimalArray(t *testing.T) {
 		})
 	}
 }
+
+func TestPlaceBracketHandler_RequestDeadline(t *testing.T) {
+	fake := testutil.NewFakeBinance(t)
+	fake.AddSymbol(testutil.BTCUSDT)
+	fake.SetLatency(5 * time.Second)
+
+	signer := auth.NewSigner("test-key", "test-secret")
+	restClient := rest.NewClient(fake.URL(), signer, rest.WithMaxRetries(0))
+	spot, err := binance.NewClient(fake.URL(), signer, restClient, zerolog.Nop())
+	require.NoError(t, err)
+	handlers := NewHandlers(orders.NewManager(spot, nil, orders.NewLogEventEmitter(zerolog.Nop()), zerolog.Nop()), zerolog.Nop())
+
+	body, err := json.Marshal(&orders.PlaceBracketRequest{
+		Symbol:           "BTCUSDT",
+		Side:             "BUY",
+		Quantity:         decimal.RequireFromString("0.001"),
+		EntryPrice:       decimal.RequireFromString("50000"),
+		TakeProfitPrices: []decimal.Decimal{decimal.RequireFromString("51000")},
+		StopLossPrice:    decimal.RequireFromString("49000"),
+	})
+	require.NoError(t, err)
+
+	t.Run("middleware deadline bounds the Binance call", func(t *testing.T) {
+		gin.SetMode(gin.TestMode)
+		router := gin.New()
+		router.Use(TimeoutMiddleware(100 * time.Millisecond))
+		router.POST("/place_bracket", gin.WrapF(handlers.PlaceBracketHandler))
+
+		req := httptest.NewRequest(http.MethodPost, "/place_bracket", bytes.NewReader(body))
+		w := httptest.NewRecorder()
+		start := time.Now()
+		router.ServeHTTP(w, req)
+
+		// The middleware waits for the handler, so a Binance call that ignored
+		// the deadline would hold the response for the full mock latency
+		assert.Equal(t, http.StatusRequestTimeout, w.Code)
+		assert.Less(t, time.Since(start), time.Second)
+	})
+
+	t.Run("expired deadline reports a gateway timeout", func(t *testing.T) {
+		ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+		defer cancel()
+
+		req := httptest.NewRequest(http.MethodPost, "/place_bracket", bytes.NewReader(body)).WithContext(ctx)
+		w := httptest.NewRecorder()
+		start := time.Now()
+		handlers.PlaceBracketHandler(w, req)
+
+		assert.Equal(t, http.StatusGatewayTimeout, w.Code)
+		assert.Less(t, time.Since(start), time.Second)
+	})
+
+	assert.Empty(t, fake.Orders())
+}
+
+func TestPlaceBracketHandler_PropagatesTraceID(t *testing.T) {
+	fake := testutil.NewFakeBinance(t)
+	fake.AddSymbol(testutil.BTCUSDT)
+
+	signer := auth.NewSigner("test-key", "test-secret")
+	restClient := rest.NewClient(fake.URL(), signer, rest.WithMaxRetries(0))
+	spot, err := binance.NewClient(fake.URL(), signer, restClient, zerolog.Nop())
+	require.NoError(t, err)
+
+	var logs bytes.Buffer
+	handlers := NewHandlers(orders.NewManager(spot, nil, orders.NewLogEventEmitter(zerolog.Nop()), zerolog.Nop()), zerolog.New(&logs))
+	server := TraceMiddleware(http.HandlerFunc(handlers.PlaceBracketHandler))
+
+	body, err := json.Marshal(&orders.PlaceBracketRequest{
+		Symbol:           "BTCUSDT",
+		Side:             "BUY",
+		Quantity:         decimal.RequireFromString("0.001"),
+		EntryPrice:       decimal.RequireFromString("50000"),
+		TakeProfitPrices: []decimal.Decimal{decimal.RequireFromString("51000")},
+		StopLossPrice:    decimal.RequireFromString("49000"),
+	})
+	require.NoError(t, err)
+
+	const traceID = "4bf92f3577b34da6a3ce929d0e0e4736"
+	req := httptest.NewRequest(http.MethodPost, "/place_bracket", bytes.NewReader(body))
+	req.Header.Set(trace.Header, traceID)
+	w := httptest.NewRecorder()
+	server.ServeHTTP(w, req)
+
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	assert.Equal(t, traceID, w.Header().Get(trace.Header))
+
+	placed := fake.Orders()
+	require.NotEmpty(t, placed)
+	for _, order := range placed {
+		assert.Equal(t, traceID, order.Header.Get(trace.Header), order.ClientOrderID())
+	}
+	assert.Contains(t, logs.String(), `"trace_id":"`+traceID+`"`)
+}
+
+func TestParseDecimalArrayStrict(t *testing.T) {
+	got, err := ParseDecimalArrayStrict([]interface{}{"50000", 51000.0})
+	require.NoError(t, err)
+	assert.Len(t, got, 2)
+
+	_, err = ParseDecimalArrayStrict([]interface{}{"50000", nil})
+	assert.EqualError(t, err, "value 1: unsupported type <nil>")
+
+	_, err = ParseDecimalArrayStrict([]interface{}{"50000", "51,000"})
+	assert.ErrorContains(t, err, "value 1:")
+}
+
+// stubVenueResolver resolves symbols from a fixed table
+type stubVenueResolver struct {
+	futures map[string]bool
+	calls   int
+}
+
+func (s *stubVenueResolver) Resolve(ctx context.Context, symbol string) (bool, error) {
+	s.calls++
+	isFutures, ok := s.futures[symbol]
+	if !ok {
+		return false, fmt.Errorf("symbol %s is %w", symbol, orders.ErrNoVenue)
+	}
+	return isFutures, nil
+}
+
+func TestPlaceBracketHandler_ResolvesVenue(t *testing.T) {
+	tests := []struct {
+		name         string
+		symbol       string
+		venue        string
+		isFutures    bool
+		wantStatus   int
+		wantFutures  bool
+		wantErr      string
+		wantResolves int
+	}{
+		{name: "spot symbol without venue", symbol: "SPOTONLY", wantStatus: http.StatusOK, wantResolves: 1},
+		{name: "futures symbol without venue", symbol: "PERPONLY", wantStatus: http.StatusOK, wantFutures: true, wantResolves: 1},
+		{name: "is_futures skips resolution", symbol: "SPOTONLY", isFutures: true, wantStatus: http.StatusOK, wantFutures: true},
+		{name: "explicit venue skips resolution", symbol: "SPOTONLY", venue: orders.VenueFutures, wantStatus: http.StatusOK, wantFutures: true},
+		{name: "venue conflicting with is_futures", symbol: "SPOTONLY", venue: orders.VenueSpot, isFutures: true, wantStatus: http.StatusBadRequest, wantErr: "conflicts with is_futures"},
+		{name: "unknown venue", symbol: "SPOTONLY", venue: "margin", wantStatus: http.StatusBadRequest, wantErr: `unknown venue "margin"`},
+		{name: "symbol on neither venue", symbol: "NOPEUSDT", wantStatus: http.StatusBadRequest, wantErr: "not tradable on any enabled venue", wantResolves: 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resolver := &stubVenueResolver{futures: map[string]bool{"SPOTONLY": false, "PERPONLY": true}}
+			mockManager := new(MockOrderManager)
+			if tt.wantStatus == http.StatusOK {
+				mockManager.On("PlaceBracketOrder", mock.Anything, mock.MatchedBy(func(req *orders.PlaceBracketRequest) bool {
+					return req.IsFutures == tt.wantFutures
+				})).Return(&orders.PlaceBracketResponse{BracketOrderID: "bracket-1"}, nil).Once()
+			}
+			handlers := NewHandlers(mockManager, zerolog.Nop(), WithVenueResolver(resolver))
+
+			body, err := json.Marshal(&orders.PlaceBracketRequest{
+				Symbol:           tt.symbol,
+				Side:             "BUY",
+				Quantity:         decimal.RequireFromString("0.001"),
+				EntryPrice:       decimal.RequireFromString("50000"),
+				TakeProfitPrices: []decimal.Decimal{decimal.RequireFromString("51000")},
+				StopLossPrice:    decimal.RequireFromString("49000"),
+				IsFutures:        tt.isFutures,
+				Venue:            tt.venue,
+			})
+			require.NoError(t, err)
+
+			w := httptest.NewRecorder()
+			handlers.PlaceBracketHandler(w, httptest.NewRequest(http.MethodPost, "/place_bracket", bytes.NewReader(body)))
+
+			assert.Equal(t, tt.wantStatus, w.Code)
+			if tt.wantErr != "" {
+				var resp map[string]string
+				require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+				assert.Contains(t, resp["error"], tt.wantErr)
+			}
+			assert.Equal(t, tt.wantResolves, resolver.calls)
+			mockManager.AssertExpectations(t)
+		})
+	}
+}
@@ -4,17 +4,27 @@ import (
 	"bufio"
 	"bytes"
 	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
 	"encoding/json"
 	"fmt"
 	"io"
 	"net"
 	"net/http"
+	"net/url"
+	"regexp"
+	"strconv"
 	"strings"
 	"sync"
 	"time"
 
 	"github.com/gin-gonic/gin"
+	"github.com/gorilla/websocket"
+	"router/internal/handlers"
 	"router/internal/models"
+	"router/internal/trace"
 )
 
 // RequestIDMiddleware generates or propagates request IDs for tracing
@@ -32,6 +42,22 @@ func RequestIDMiddleware() gin.HandlerFunc {
 	}
 }
 
+// TraceMiddleware puts a trace ID on each request's context, taken from the
+// X-Trace-ID header or generated, and echoes it in the response. The REST
+// client forwards it on outgoing Binance requests and handlers add it to
+// their logs, tying an inbound bracket request to its exchange calls.
+func TraceMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		id := r.Header.Get(trace.Header)
+		if !trace.Valid(id) {
+			id = trace.NewID()
+		}
+
+		w.Header().Set(trace.Header, id)
+		next.ServeHTTP(w, r.WithContext(trace.WithID(r.Context(), id)))
+	})
+}
+
 // LoggerMiddleware logs HTTP requests with configurable output
 func LoggerMiddleware(output io.Writer) gin.HandlerFunc {
 	return func(c *gin.Context) {
@@ -47,7 +73,7 @@ func LoggerMiddleware(output io.Writer) gin.HandlerFunc {
 		statusCode := c.Writer.Status()
 
 		if raw != "" {
-			path = path + "?" + raw
+			path = path + "?" + redactRelayToken(raw)
 		}
 
 		logLine := fmt.Sprintf("%s | %3d | %13v | %15s | %-7s %#v | latency=%v\n",
@@ -64,6 +90,16 @@ func LoggerMiddleware(output io.Writer) gin.HandlerFunc {
 	}
 }
 
+// redactRelayToken masks a relay token in a logged query string
+func redactRelayToken(rawQuery string) string {
+	query, err := url.ParseQuery(rawQuery)
+	if err != nil || !query.Has("token") {
+		return rawQuery
+	}
+	query.Set("token", "REDACTED")
+	return query.Encode()
+}
+
 // AuthMiddleware validates API key authentication
 func AuthMiddleware(apiKey string) gin.HandlerFunc {
 	// Skip auth for these paths
@@ -105,7 +141,124 @@ func AuthMiddleware(apiKey string) gin.HandlerFunc {
 	}
 }
 
-// RateLimiter tracks request rates per client
+// relayTokenTTL is how long a relay token can be used to open a connection
+const relayTokenTTL = time.Minute
+
+// relayTokens issues and checks short-lived tokens for a stream's SSE and
+// WebSocket relays, which browsers cannot send X-API-Key to. A token is bound
+// to one stream and signed with a secret that lives only as long as the
+// process.
+type relayTokens struct {
+	secret []byte
+	ttl    time.Duration
+	now    func() time.Time
+}
+
+func newRelayTokens(ttl time.Duration) (*relayTokens, error) {
+	secret := make([]byte, 32)
+	if _, err := rand.Read(secret); err != nil {
+		return nil, fmt.Errorf("failed to generate relay token secret: %w", err)
+	}
+	return &relayTokens{secret: secret, ttl: ttl, now: time.Now}, nil
+}
+
+// issue returns a token for streamID and when it expires
+func (t *relayTokens) issue(streamID string) (string, time.Time) {
+	expiresAt := t.now().Add(t.ttl).Truncate(time.Second)
+	expiry := strconv.FormatInt(expiresAt.Unix(), 10)
+	return expiry + "." + t.sign(streamID, expiry), expiresAt
+}
+
+// valid reports whether token was issued for streamID and has not expired
+func (t *relayTokens) valid(streamID, token string) bool {
+	expiry, signature, ok := strings.Cut(token, ".")
+	if !ok {
+		return false
+	}
+	expiresAt, err := strconv.ParseInt(expiry, 10, 64)
+	if err != nil || !t.now().Before(time.Unix(expiresAt, 0)) {
+		return false
+	}
+	return hmac.Equal([]byte(signature), []byte(t.sign(streamID, expiry)))
+}
+
+func (t *relayTokens) sign(streamID, expiry string) string {
+	mac := hmac.New(sha256.New, t.secret)
+	mac.Write([]byte(streamID + "." + expiry))
+	return base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+}
+
+// relayToken returns the relay token from the token query parameter, used by
+// EventSource, or from the subprotocol after handlers.RelayTokenProtocol,
+// used by WebSocket
+func relayToken(r *http.Request) string {
+	if token := r.URL.Query().Get("token"); token != "" {
+		return token
+	}
+	protocols := websocket.Subprotocols(r)
+	for i, protocol := range protocols {
+		if protocol == handlers.RelayTokenProtocol && i+1 < len(protocols) {
+			return protocols[i+1]
+		}
+	}
+	return ""
+}
+
+// relayAuthMiddleware authenticates a stream's relay routes with X-API-Key
+// or, for browsers, a relay token issued for that stream
+func relayAuthMiddleware(apiKey string, tokens *relayTokens) gin.HandlerFunc {
+	apiKeyAuth := AuthMiddleware(apiKey)
+
+	return func(c *gin.Context) {
+		token := relayToken(c.Request)
+		if token == "" {
+			apiKeyAuth(c)
+			return
+		}
+
+		if !tokens.valid(c.Param("id"), token) {
+			c.AbortWithStatusJSON(http.StatusUnauthorized, models.NewErrorResponse(
+				"UNAUTHORIZED",
+				"Invalid or expired relay token",
+				c.GetString("request_id"),
+			))
+			return
+		}
+
+		c.Next()
+	}
+}
+
+// relayOriginCheck allows WebSocket relays from the server's own origin and
+// from the configured CORS origins. A "*" entry is ignored: relay tokens
+// travel in the URL or handshake, so cross-site pages must be named.
+func relayOriginCheck(allowOrigins []string) func(r *http.Request) bool {
+	var matchers []originMatcher
+	for _, allowed := range allowOrigins {
+		if allowed != "*" {
+			matchers = append(matchers, newOriginMatcher(allowed))
+		}
+	}
+
+	return func(r *http.Request) bool {
+		origin := r.Header.Get("Origin")
+		if origin == "" {
+			// Not a browser
+			return true
+		}
+		if parsed, err := url.Parse(origin); err == nil && strings.EqualFold(parsed.Host, r.Host) {
+			return true
+		}
+		for _, matcher := range matchers {
+			if _, ok := matcher.match(origin); ok {
+				return true
+			}
+		}
+		return false
+	}
+}
+
+// rateLimiter tracks request rates per client
 type rateLimiter struct {
 	clients         map[string]*clientRateInfo
 	mu              sync.RWMutex
@@ -122,6 +275,11 @@ type clientRateInfo struct {
 
 // RateLimitMiddleware implements token bucket rate limiting per client IP
 func RateLimitMiddleware(requestsPerWindow int, window time.Duration) gin.HandlerFunc {
+	return newRateLimiter(requestsPerWindow, window).middleware()
+}
+
+// newRateLimiter creates a limiter and starts its cleanup goroutine
+func newRateLimiter(requestsPerWindow int, window time.Duration) *rateLimiter {
 	limiter := &rateLimiter{
 		clients:         make(map[string]*clientRateInfo),
 		rate:            requestsPerWindow,
@@ -145,17 +303,34 @@ func RateLimitMiddleware(requestsPerWindow int, window time.Duration) gin.Handle
 		}
 	}()
 
+	return limiter
+}
+
+// setRate changes the allowed requests per window. Client windows are reset
+// so the new limit applies to the very next request.
+func (rl *rateLimiter) setRate(requestsPerWindow int) {
+	rl.mu.Lock()
+	defer rl.mu.Unlock()
+
+	rl.rate = requestsPerWindow
+	rl.clients = make(map[string]*clientRateInfo)
+}
+
+func (rl *rateLimiter) middleware() gin.HandlerFunc {
+	window := rl.window
+
 	return func(c *gin.Context) {
 		clientIP := getClientIP(c)
 
-		limiter.mu.Lock()
-		client, exists := limiter.clients[clientIP]
+		rl.mu.Lock()
+		requestsPerWindow := rl.rate
+		client, exists := rl.clients[clientIP]
 		if !exists || time.Since(client.lastReset) >= window {
-			limiter.clients[clientIP] = &clientRateInfo{
+			rl.clients[clientIP] = &clientRateInfo{
 				tokens:    requestsPerWindow - 1,
 				lastReset: time.Now(),
 			}
-			limiter.mu.Unlock()
+			rl.mu.Unlock()
 
 			c.Header("X-RateLimit-Limit", fmt.Sprintf("%d", requestsPerWindow))
 			c.Header("X-RateLimit-Remaining", fmt.Sprintf("%d", requestsPerWindow-1))
@@ -165,7 +340,7 @@ func RateLimitMiddleware(requestsPerWindow int, window time.Duration) gin.Handle
 		}
 
 		if client.tokens <= 0 {
-			limiter.mu.Unlock()
+			rl.mu.Unlock()
 
 			c.Header("X-RateLimit-Limit", fmt.Sprintf("%d", requestsPerWindow))
 			c.Header("X-RateLimit-Remaining", "0")
@@ -183,7 +358,7 @@ func RateLimitMiddleware(requestsPerWindow int, window time.Duration) gin.Handle
 		client.tokens--
 		remaining := client.tokens
 		resetTime := client.lastReset.Add(window)
-		limiter.mu.Unlock()
+		rl.mu.Unlock()
 
 		c.Header("X-RateLimit-Limit", fmt.Sprintf("%d", requestsPerWindow))
 		c.Header("X-RateLimit-Remaining", fmt.Sprintf("%d", remaining))
@@ -234,16 +409,73 @@ type CORSConfig struct {
 	MaxAge           int
 }
 
+// originMatcher matches request origins against one allowed origin entry:
+// "*", an exact origin, or a pattern such as "https://*.example.com" where
+// each "*" stands for one or more subdomain labels
+type originMatcher struct {
+	exact   string
+	any     bool
+	pattern *regexp.Regexp
+}
+
+// subdomainLabels matches the part of a host a "*" may stand for. It cannot
+// cross into the scheme, port, path or credentials.
+const subdomainLabels = `[a-z0-9-]+(?:\.[a-z0-9-]+)*`
+
+func newOriginMatcher(allowed string) originMatcher {
+	if allowed == "*" {
+		return originMatcher{any: true}
+	}
+	if !strings.Contains(allowed, "*") {
+		return originMatcher{exact: allowed}
+	}
+
+	parts := strings.Split(allowed, "*")
+	for i, part := range parts {
+		parts[i] = regexp.QuoteMeta(part)
+	}
+	expr := "(?i)^" + strings.Join(parts, subdomainLabels) + "$"
+	return originMatcher{pattern: regexp.MustCompile(expr)}
+}
+
+// match reports whether origin is allowed and the value to send back in
+// Access-Control-Allow-Origin
+func (m originMatcher) match(origin string) (string, bool) {
+	switch {
+	case m.any:
+		return "*", true
+	case m.pattern != nil:
+		// Browsers need the concrete origin, not the pattern
+		return origin, origin != "" && m.pattern.MatchString(origin)
+	default:
+		return m.exact, m.exact == origin
+	}
+}
+
 // CORSMiddleware handles Cross-Origin Resource Sharing
 func CORSMiddleware(config CORSConfig) gin.HandlerFunc {
+	matchers := make([]originMatcher, 0, len(config.AllowOrigins))
+	for _, allowed := range config.AllowOrigins {
+		matchers = append(matchers, newOriginMatcher(allowed))
+	}
+
+	allowHeaders := make(map[string]string, len(config.AllowHeaders))
+	for _, header := range config.AllowHeaders {
+		allowHeaders[strings.ToLower(header)] = header
+	}
+
 	return func(c *gin.Context) {
 		origin := c.Request.Header.Get("Origin")
 
+		// The CORS headers depend on the origin, so shared caches must not
+		// reuse a response across origins
+		c.Writer.Header().Add("Vary", "Origin")
+
 		// Check if origin is allowed
 		originAllowed := false
 		allowedOrigin := ""
-		for _, allowed := range config.AllowOrigins {
-			if allowed == "*" || allowed == origin {
+		for _, matcher := range matchers {
+			if allowed, ok := matcher.match(origin); ok {
 				originAllowed = true
 				allowedOrigin = allowed
 				break
@@ -252,10 +484,16 @@ func CORSMiddleware(config CORSConfig) gin.HandlerFunc {
 
 		// Handle preflight request
 		if c.Request.Method == "OPTIONS" {
+			c.Writer.Header().Add("Vary", "Access-Control-Request-Method")
+			c.Writer.Header().Add("Vary", "Access-Control-Request-Headers")
+
 			if originAllowed {
 				c.Header("Access-Control-Allow-Origin", allowedOrigin)
 				c.Header("Access-Control-Allow-Methods", strings.Join(config.AllowMethods, ", "))
-				c.Header("Access-Control-Allow-Headers", strings.Join(config.AllowHeaders, ", "))
+				requested := c.Request.Header.Get("Access-Control-Request-Headers")
+				if headers := allowedRequestHeaders(requested, allowHeaders); len(headers) > 0 {
+					c.Header("Access-Control-Allow-Headers", strings.Join(headers, ", "))
+				}
 				if config.AllowCredentials {
 					c.Header("Access-Control-Allow-Credentials", "true")
 				}
@@ -293,6 +531,18 @@ func CORSMiddleware(config CORSConfig) gin.HandlerFunc {
 	}
 }
 
+// allowedRequestHeaders returns the preflight's requested headers that are in
+// the allow-list, spelled as configured
+func allowedRequestHeaders(requested string, allowed map[string]string) []string {
+	var headers []string
+	for _, header := range strings.Split(requested, ",") {
+		if name, ok := allowed[strings.ToLower(strings.TrimSpace(header))]; ok {
+			headers = append(headers, name)
+		}
+	}
+	return headers
+}
+
 // TimeoutMiddleware sets a timeout for request processing
 func TimeoutMiddleware(timeout time.Duration) gin.HandlerFunc {
 	return func(c *gin.Context) {
@@ -5,12 +5,14 @@ import (
 	"fmt"
 	"net/http"
 	"os"
+	"strings"
 	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/rs/zerolog"
 	"router/internal/handlers"
 	"router/internal/metrics"
+	"router/internal/models"
 )
 
 // ServerConfig contains server configuration
@@ -22,6 +24,8 @@ type ServerConfig struct {
 	MaxHeaderBytes int
 	APIKey         string
 	Version        string
+	GitCommit      string
+	BuildTime      string
 	RateLimit      int           // Requests per second
 	RateWindow     time.Duration // Rate limit window
 	CORSOrigins    []string
@@ -36,6 +40,12 @@ type Server struct {
 	logger     zerolog.Logger
 	startTime  time.Time
 
+	// Rate limiter shared by all routes; nil when rate limiting is disabled
+	rateLimiter *rateLimiter
+
+	// Signs the tokens browsers use to open stream relays
+	relayTokens *relayTokens
+
 	// Handler dependencies (will be injected)
 	streamManager       handlers.StreamManager
 	subscriptionManager handlers.SubscriptionManager
@@ -57,6 +67,11 @@ func NewServer(config ServerConfig) (*Server, error) {
 	// Set defaults
 	setConfigDefaults(&config)
 
+	relayTokens, err := newRelayTokens(relayTokenTTL)
+	if err != nil {
+		return nil, err
+	}
+
 	// Configure logger
 	logger := setupLogger(config.LogLevel)
 
@@ -73,10 +88,11 @@ func NewServer(config ServerConfig) (*Server, error) {
 
 	// Create server
 	server := &Server{
-		config:    config,
-		router:    router,
-		logger:    logger,
-		startTime: time.Now(),
+		config:      config,
+		router:      router,
+		logger:      logger,
+		startTime:   time.Now(),
+		relayTokens: relayTokens,
 	}
 
 	// Setup middleware
@@ -140,6 +156,28 @@ func (s *Server) Start() error {
 	return s.httpServer.ListenAndServe()
 }
 
+// Handler returns the HTTP handler serving all routes
+func (s *Server) Handler() http.Handler {
+	return s.router
+}
+
+// SetRateLimit changes the per-client request limit without a restart.
+// It has no effect when the server was started with rate limiting disabled.
+func (s *Server) SetRateLimit(requestsPerWindow int) {
+	if s.rateLimiter == nil {
+		s.logger.Warn().Int("rate_limit", requestsPerWindow).Msg("Rate limiting disabled, ignoring new limit")
+		return
+	}
+	s.rateLimiter.setRate(requestsPerWindow)
+	s.logger.Info().Int("rate_limit", requestsPerWindow).Msg("Rate limit updated")
+}
+
+// SetLogLevel changes the global log level without a restart
+func (s *Server) SetLogLevel(level string) {
+	setLogLevel(level)
+	s.logger.Info().Str("log_level", level).Msg("Log level updated")
+}
+
 // Shutdown gracefully shuts down the server
 func (s *Server) Shutdown(ctx context.Context) error {
 	s.logger.Info().Msg("Shutting down API server")
@@ -172,7 +210,8 @@ func (s *Server) setupMiddleware() {
 
 	// Rate limiting middleware
 	if s.config.RateLimit > 0 {
-		s.router.Use(RateLimitMiddleware(s.config.RateLimit, s.config.RateWindow))
+		s.rateLimiter = newRateLimiter(s.config.RateLimit, s.config.RateWindow)
+		s.router.Use(s.rateLimiter.middleware())
 	}
 
 	// Authentication middleware (applied to specific routes)
@@ -184,6 +223,7 @@ func (s *Server) setupBasicRoutes() {
 	// Health check endpoints (no auth required)
 	healthHandlers := handlers.NewHealthHandlers(s.config.Version, s.startTime)
 	s.router.GET("/health", healthHandlers.HealthCheck())
+	s.router.GET("/version", healthHandlers.Version(s.config.GitCommit, s.config.BuildTime))
 }
 
 // setupRoutes configures API routes
@@ -214,6 +254,20 @@ func (s *Server) setupRoutes() {
 			streams.GET("/:id", streamHandlers.GetStream())
 			streams.DELETE("/:id", streamHandlers.CloseStream())
 			streams.POST("/:id/reconnect", streamHandlers.ReconnectStream())
+
+			// Managers that can relay events also serve them over SSE and
+			// WebSocket. Browsers cannot send X-API-Key on either, so the
+			// relays also accept a short-lived token issued here.
+			if relay, ok := s.streamManager.(handlers.EventRelay); ok {
+				streams.POST("/:id/token", s.issueRelayToken())
+
+				eventHandlers := handlers.NewEventHandlers(relay, handlers.DefaultEventHeartbeat)
+				eventHandlers.SetOriginCheck(relayOriginCheck(s.config.CORSOrigins))
+				relays := s.router.Group("/api/streams")
+				relays.Use(relayAuthMiddleware(s.config.APIKey, s.relayTokens))
+				relays.GET("/:id/events", eventHandlers.StreamEvents())
+				relays.GET("/:id/ws", eventHandlers.StreamWebSocket())
+			}
 		}
 	}
 
@@ -221,6 +275,7 @@ func (s *Server) setupRoutes() {
 	if s.subscriptionManager != nil {
 		subHandlers := handlers.NewSubscriptionHandlers(s.subscriptionManager)
 		api.POST("/streams/:id/subscribe", subHandlers.SubscribeToMarketData())
+		api.POST("/streams/:id/subscribe/bulk", subHandlers.SubscribeBulk())
 		api.POST("/streams/:id/user-data", subHandlers.SubscribeToUserData())
 		api.POST("/streams/:id/unsubscribe", subHandlers.Unsubscribe())
 		api.GET("/streams/:id/subscriptions", subHandlers.ListSubscriptions())
@@ -241,6 +296,34 @@ func (s *Server) setupRoutes() {
 	}
 }
 
+// issueRelayToken returns a token that opens the stream's relays for
+// relayTokenTTL
+func (s *Server) issueRelayToken() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		streamID := c.Param("id")
+		if _, err := s.streamManager.GetStream(streamID); err != nil {
+			if strings.Contains(err.Error(), "not found") {
+				c.JSON(http.StatusNotFound, models.NewErrorResponse(
+					"NOT_FOUND",
+					"Stream not found",
+					c.GetString("request_id"),
+				))
+				return
+			}
+
+			c.JSON(http.StatusInternalServerError, models.NewErrorResponse(
+				"STREAM_ERROR",
+				"Failed to retrieve stream",
+				c.GetString("request_id"),
+			))
+			return
+		}
+
+		token, expiresAt := s.relayTokens.issue(streamID)
+		c.JSON(http.StatusOK, models.RelayTokenResponse{Token: token, ExpiresAt: expiresAt})
+	}
+}
+
 // Helper functions
 
 func validateConfig(config *ServerConfig) error {
@@ -255,6 +338,12 @@ func validateConfig(config *ServerConfig) error {
 	if config.Version == "" {
 		config.Version = "unknown"
 	}
+	if config.GitCommit == "" {
+		config.GitCommit = "unknown"
+	}
+	if config.BuildTime == "" {
+		config.BuildTime = "unknown"
+	}
 
 	return nil
 }
@@ -287,8 +376,12 @@ func setConfigDefaults(config *ServerConfig) {
 
 func setupLogger(level string) zerolog.Logger {
 	zerolog.TimeFieldFormat = time.RFC3339
+	setLogLevel(level)
+
+	return zerolog.New(os.Stdout).With().Timestamp().Logger()
+}
 
-	// Set log level
+func setLogLevel(level string) {
 	switch level {
 	case "debug":
 		zerolog.SetGlobalLevel(zerolog.DebugLevel)
@@ -301,6 +394,4 @@ func setupLogger(level string) zerolog.Logger {
 	default:
 		zerolog.SetGlobalLevel(zerolog.InfoLevel)
 	}
-
-	return zerolog.New(os.Stdout).With().Timestamp().Logger()
 }
@@ -7,6 +7,7 @@ import (
 	"net"
 	"net/http"
 	"net/http/httptest"
+	"runtime"
 	"testing"
 	"time"
 
@@ -216,6 +217,49 @@ func TestServerHealthCheck(t *testing.T) {
 	})
 }
 
+func TestServerVersion(t *testing.T) {
+	t.Run("reports build info without authentication", func(t *testing.T) {
+		server, err := NewServer(ServerConfig{
+			Port:      8080,
+			APIKey:    "secret-key",
+			Version:   "1.4.2",
+			GitCommit: "3f2c1ab",
+			BuildTime: "2024-05-01T12:00:00Z",
+		})
+		require.NoError(t, err)
+
+		req := httptest.NewRequest("GET", "/version", nil)
+		w := httptest.NewRecorder()
+		server.router.ServeHTTP(w, req)
+
+		require.Equal(t, http.StatusOK, w.Code)
+
+		var body map[string]string
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &body))
+		assert.Equal(t, map[string]string{
+			"version":    "1.4.2",
+			"git_commit": "3f2c1ab",
+			"go_version": runtime.Version(),
+			"build_time": "2024-05-01T12:00:00Z",
+		}, body)
+	})
+
+	t.Run("defaults missing build info to unknown", func(t *testing.T) {
+		server, err := NewServer(ServerConfig{Port: 8080, APIKey: "secret-key"})
+		require.NoError(t, err)
+
+		req := httptest.NewRequest("GET", "/version", nil)
+		w := httptest.NewRecorder()
+		server.router.ServeHTTP(w, req)
+
+		var resp models.VersionResponse
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+		assert.Equal(t, "unknown", resp.Version)
+		assert.Equal(t, "unknown", resp.GitCommit)
+		assert.Equal(t, "unknown", resp.BuildTime)
+	})
+}
+
 func TestServerMiddleware(t *testing.T) {
 	t.Run("applies request ID middleware", func(t *testing.T) {
 		config := ServerConfig{
@@ -44,6 +44,19 @@ func (s *Signer) RecvWindow() int64 {
 	return s.recvWindow
 }
 
+// String implements fmt.Stringer without exposing the secret or full API key
+func (s *Signer) String() string {
+	return fmt.Sprintf("Signer{apiKey: %s, recvWindow: %d}", maskKey(s.apiKey), s.recvWindow)
+}
+
+// maskKey keeps only the last four characters of a key
+func maskKey(key string) string {
+	if len(key) <= 4 {
+		return "****"
+	}
+	return "****" + key[len(key)-4:]
+}
+
 // Sign generates HMAC-SHA256 signature for the given parameters
 func (s *Signer) Sign(params url.Values) string {
 	// Create the query string
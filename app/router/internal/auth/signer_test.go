@@ -278,6 +278,18 @@ func TestRecvWindow(t *testing.T) {
 	})
 }
 
+func TestSignerString(t *testing.T) {
+	t.Run("does not expose secret or full api key", func(t *testing.T) {
+		signer := NewSigner("test-api-key-1234", "test-api-secret")
+
+		str := fmt.Sprintf("%v", signer)
+
+		assert.NotContains(t, str, "test-api-secret")
+		assert.NotContains(t, str, "test-api-key-1234")
+		assert.Contains(t, str, "****1234")
+	})
+}
+
 func TestConcurrentSigning(t *testing.T) {
 	apiKey := "test-api-key"
 	apiSecret := "test-api-secret"
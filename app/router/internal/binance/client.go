@@ -2,12 +2,15 @@ package binance
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"strings"
 	"sync"
 	"time"
 
 	"github.com/rs/zerolog"
 	"github.com/shopspring/decimal"
+	"golang.org/x/sync/singleflight"
 	"router/internal/auth"
 	"router/internal/rest"
 )
@@ -26,8 +29,77 @@ type Client struct {
 	accountCacheTTL   time.Duration
 	accountCacheMutex sync.RWMutex
 
-	// Exchange info cache
-	exchangeInfoCache *ExchangeInfoCache
+	// Futures account cache, guarded by accountCacheMutex
+	futuresAccountCache     *rest.FuturesAccountResponse
+	futuresAccountCacheTime time.Time
+	positionMode            *bool  // hedge mode, nil until first looked up
+	positionModeGen         uint64 // bumped whenever the cached mode is replaced or dropped
+	positionModeLookups     singleflight.Group
+
+	// Optional alternative transport for spot order placement
+	orderPlacer OrderPlacer
+	// Optional alternative source for spot order queries and cancels
+	orderStore OrderStore
+
+	// First delay before retrying a rate-limited per-symbol open orders query
+	openOrdersBackoff time.Duration
+
+	// Exchange info cache, created lazily from restClient when not injected
+	exchangeInfoCache      *ExchangeInfoCache
+	exchangeInfoCacheTTL   time.Duration
+	exchangeInfoCacheMutex sync.RWMutex
+}
+
+// OrderPlacer submits spot orders. rest.Client and wsapi.Client both satisfy it.
+type OrderPlacer interface {
+	PlaceOrder(ctx context.Context, req *rest.OrderRequest) (*rest.OrderResponse, error)
+}
+
+// OrderStore looks up and cancels spot orders. rest.Client satisfies it, as
+// does paper.SimulatedExchange for the orders it simulated.
+type OrderStore interface {
+	CancelOrder(ctx context.Context, symbol string, orderID int64) error
+	GetOrder(ctx context.Context, symbol, clientOrderID string) (*rest.Order, error)
+	GetOpenOrders(ctx context.Context, symbol string) ([]rest.Order, error)
+	GetAllOpenOrders(ctx context.Context) ([]rest.Order, error)
+}
+
+// defaultExchangeInfoCacheTTL is how long symbol rules are trusted before re-fetching
+const defaultExchangeInfoCacheTTL = 5 * time.Minute
+
+// DefaultAccountCacheTTL is how long account snapshots are reused for balance
+// and margin checks
+const DefaultAccountCacheTTL = 30 * time.Second
+
+// DefaultOpenOrdersBackoff is the first delay before retrying a per-symbol
+// open orders query that hit a rate limit; each retry doubles it
+const DefaultOpenOrdersBackoff = 500 * time.Millisecond
+
+// Per-symbol open orders queries give up after this many rate-limited
+// attempts, and never wait longer than maxOpenOrdersBackoff between them
+const (
+	maxOpenOrdersAttempts = 4
+	maxOpenOrdersBackoff  = 8 * time.Second
+)
+
+// ClientOption configures a Client
+type ClientOption func(*Client)
+
+// WithOpenOrdersBackoff sets the first delay before retrying a rate-limited
+// per-symbol open orders query
+func WithOpenOrdersBackoff(delay time.Duration) ClientOption {
+	return func(c *Client) {
+		c.openOrdersBackoff = delay
+	}
+}
+
+// WithAccountCacheTTL sets how long account snapshots are reused. Zero
+// disables the cache so every lookup fetches, for setups that follow
+// balances on the user stream instead.
+func WithAccountCacheTTL(ttl time.Duration) ClientOption {
+	return func(c *Client) {
+		c.accountCacheTTL = ttl
+	}
 }
 
 // convertFills converts REST fills to our Fill type
@@ -45,7 +117,7 @@ func convertFills(restFills []rest.Fill) []Fill {
 }
 
 // NewClient creates a new Binance-specific client
-func NewClient(baseURL string, signer *auth.Signer, restClient *rest.Client, logger zerolog.Logger) (*Client, error) {
+func NewClient(baseURL string, signer *auth.Signer, restClient *rest.Client, logger zerolog.Logger, opts ...ClientOption) (*Client, error) {
 	if signer == nil {
 		return nil, fmt.Errorf("signer is required")
 	}
@@ -56,17 +128,25 @@ func NewClient(baseURL string, signer *auth.Signer, restClient *rest.Client, log
 		return nil, fmt.Errorf("base URL is required")
 	}
 
-	return &Client{
-		baseURL:         baseURL,
-		signer:          signer,
-		restClient:      restClient,
-		accountCacheTTL: 30 * time.Second,
-		logger:          logger,
-	}, nil
+	client := &Client{
+		baseURL:              baseURL,
+		signer:               signer,
+		restClient:           restClient,
+		accountCacheTTL:      DefaultAccountCacheTTL,
+		exchangeInfoCacheTTL: defaultExchangeInfoCacheTTL,
+		openOrdersBackoff:    DefaultOpenOrdersBackoff,
+		logger:               logger,
+	}
+	for _, opt := range opts {
+		opt(client)
+	}
+
+	return client, nil
 }
 
 // PlaceSpotOrder places a spot order with validation
 func (c *Client) PlaceSpotOrder(ctx context.Context, order SpotOrderRequest) (*OrderResponse, error) {
+	order.TimeInForce = defaultTimeInForce(spotTimeInForce, order.Type, order.TimeInForce)
 	if err := c.validateSpotOrder(order); err != nil {
 		c.logger.Error().
 			Err(err).
@@ -76,6 +156,14 @@ func (c *Client) PlaceSpotOrder(ctx context.Context, order SpotOrderRequest) (*O
 			Msg("Spot order validation failed")
 		return nil, err
 	}
+	if err := c.checkSpotBalance(order); err != nil {
+		c.logger.Error().
+			Err(err).
+			Str("symbol", order.Symbol).
+			Str("side", order.Side).
+			Msg("Spot order balance check failed")
+		return nil, err
+	}
 
 	c.logger.Debug().
 		Str("symbol", order.Symbol).
@@ -92,14 +180,21 @@ func (c *Client) PlaceSpotOrder(ctx context.Context, order SpotOrderRequest) (*O
 		Side:             order.Side,
 		Type:             order.Type,
 		Quantity:         order.Quantity,
+		QuoteOrderQty:    order.QuoteOrderQty,
 		Price:            order.Price,
 		StopPrice:        order.StopPrice,
 		TimeInForce:      order.TimeInForce,
 		NewClientOrderID: order.NewClientOrderID,
+
+		SelfTradePreventionMode: order.SelfTradePreventionMode,
 	}
 
-	// Place order using REST client
-	restResp, err := c.restClient.PlaceOrder(ctx, req)
+	// Place order using the configured transport, REST by default
+	var placer OrderPlacer = c.restClient
+	if c.orderPlacer != nil {
+		placer = c.orderPlacer
+	}
+	restResp, err := placer.PlaceOrder(ctx, req)
 	if err != nil {
 		c.logger.Error().
 			Err(err).
@@ -124,6 +219,7 @@ func (c *Client) PlaceSpotOrder(ctx context.Context, order SpotOrderRequest) (*O
 		Type:          restResp.Type,
 		Side:          restResp.Side,
 		Fills:         convertFills(restResp.Fills),
+		Slippage:      restResp.Slippage,
 	}
 
 	c.logger.Info().
@@ -138,8 +234,35 @@ func (c *Client) PlaceSpotOrder(ctx context.Context, order SpotOrderRequest) (*O
 	return response, nil
 }
 
+// IsFutures reports whether the client trades USDT-M futures
+func (c *Client) IsFutures() bool {
+	return c.isFutures
+}
+
+// SetOrderPlacer routes spot order placement through placer instead of REST,
+// e.g. the lower-latency WebSocket API. Passing nil restores REST.
+func (c *Client) SetOrderPlacer(placer OrderPlacer) {
+	c.orderPlacer = placer
+}
+
+// SetOrderStore routes spot order queries and cancels to store instead of
+// REST. Orders placed through a simulated OrderPlacer only exist there.
+// Passing nil restores REST.
+func (c *Client) SetOrderStore(store OrderStore) {
+	c.orderStore = store
+}
+
+// spotOrders returns where spot orders are looked up and cancelled
+func (c *Client) spotOrders() OrderStore {
+	if c.orderStore != nil {
+		return c.orderStore
+	}
+	return c.restClient
+}
+
 // PlaceFuturesOrder places a futures order with validation
 func (c *Client) PlaceFuturesOrder(ctx context.Context, order FuturesOrderRequest) (*OrderResponse, error) {
+	order.TimeInForce = defaultTimeInForce(futuresTimeInForce, order.Type, order.TimeInForce)
 	if err := c.validateFuturesOrder(order); err != nil {
 		c.logger.Error().
 			Err(err).
@@ -149,6 +272,22 @@ func (c *Client) PlaceFuturesOrder(ctx context.Context, order FuturesOrderReques
 			Msg("Futures order validation failed")
 		return nil, err
 	}
+	if err := c.checkPositionSide(ctx, order); err != nil {
+		c.logger.Error().
+			Err(err).
+			Str("symbol", order.Symbol).
+			Str("position_side", order.PositionSide).
+			Msg("Futures order position side check failed")
+		return nil, err
+	}
+	if err := c.checkFuturesMargin(order); err != nil {
+		c.logger.Error().
+			Err(err).
+			Str("symbol", order.Symbol).
+			Str("side", order.Side).
+			Msg("Futures order margin check failed")
+		return nil, err
+	}
 
 	c.logger.Debug().
 		Str("symbol", order.Symbol).
@@ -172,12 +311,25 @@ func (c *Client) PlaceFuturesOrder(ctx context.Context, order FuturesOrderReques
 		TimeInForce:      order.TimeInForce,
 		ReduceOnly:       order.ReduceOnly,
 		ClosePosition:    order.ClosePosition,
+		ActivationPrice:  order.ActivationPrice,
+		CallbackRate:     order.CallbackRate,
+		WorkingType:      order.WorkingType,
+		PriceProtect:     order.PriceProtect,
+		PositionSide:     order.PositionSide,
 		NewClientOrderID: order.NewClientOrderID,
+
+		SelfTradePreventionMode: order.SelfTradePreventionMode,
 	}
 
 	// Place order using REST client
 	restResp, err := c.restClient.PlaceFuturesOrder(ctx, req)
 	if err != nil {
+		// -4061: positionSide does not match the account's mode, which was
+		// changed since it was cached
+		var apiErr *rest.BinanceError
+		if errors.As(err, &apiErr) && apiErr.Code == -4061 {
+			c.invalidatePositionMode()
+		}
 		c.logger.Error().
 			Err(err).
 			Str("symbol", order.Symbol).
@@ -259,6 +411,171 @@ func (c *Client) GetAccountInfo(ctx context.Context) (*AccountResponse, error) {
 	return account, nil
 }
 
+// ErrTradingNotPermitted is returned by VerifyAccess when the API key can read
+// the account but not trade on it
+var ErrTradingNotPermitted = errors.New("API key is not permitted to trade")
+
+// VerifyAccess pings the account endpoint for the client's venue, bypassing
+// the account cache, to confirm the API key is accepted. With trade set it
+// also requires the account to report trading as enabled.
+func (c *Client) VerifyAccess(ctx context.Context, trade bool) error {
+	var canTrade bool
+	if c.isFutures {
+		account, err := c.restClient.GetFuturesAccount(ctx)
+		if err != nil {
+			return fmt.Errorf("failed to get futures account: %w", err)
+		}
+		canTrade = account.CanTrade
+	} else {
+		account, err := c.restClient.GetAccount(ctx)
+		if err != nil {
+			return fmt.Errorf("failed to get account info: %w", err)
+		}
+		canTrade = account.CanTrade
+	}
+
+	if trade && !canTrade {
+		return ErrTradingNotPermitted
+	}
+	return nil
+}
+
+// InvalidateAccountCache drops the cached spot and futures account snapshots
+// so the next balance or margin check fetches fresh data. Call it when the
+// user stream reports a balance change.
+func (c *Client) InvalidateAccountCache() {
+	c.accountCacheMutex.Lock()
+	defer c.accountCacheMutex.Unlock()
+
+	c.accountCache = nil
+	c.futuresAccountCache = nil
+}
+
+// GetFuturesAccountInfo retrieves futures account balances and positions with caching
+func (c *Client) GetFuturesAccountInfo(ctx context.Context) (*rest.FuturesAccountResponse, error) {
+	c.accountCacheMutex.RLock()
+	if c.futuresAccountCache != nil && time.Since(c.futuresAccountCacheTime) < c.accountCacheTTL {
+		cached := c.futuresAccountCache
+		c.accountCacheMutex.RUnlock()
+		return cached, nil
+	}
+	c.accountCacheMutex.RUnlock()
+
+	c.accountCacheMutex.Lock()
+	defer c.accountCacheMutex.Unlock()
+
+	// Double-check pattern
+	if c.futuresAccountCache != nil && time.Since(c.futuresAccountCacheTime) < c.accountCacheTTL {
+		return c.futuresAccountCache, nil
+	}
+
+	account, err := c.restClient.GetFuturesAccount(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get futures account info: %w", err)
+	}
+
+	c.futuresAccountCache = account
+	c.futuresAccountCacheTime = time.Now()
+
+	return account, nil
+}
+
+// GetMarkPrice returns a futures symbol's current mark price
+func (c *Client) GetMarkPrice(ctx context.Context, symbol string) (decimal.Decimal, error) {
+	if !c.isFutures {
+		return decimal.Zero, fmt.Errorf("mark price is only available for futures")
+	}
+
+	markPrice, err := c.restClient.GetMarkPrice(ctx, symbol)
+	if err != nil {
+		return decimal.Zero, fmt.Errorf("failed to get mark price for %s: %w", symbol, err)
+	}
+
+	return markPrice.MarkPrice, nil
+}
+
+// GetPositionMode reports whether the futures account is in hedge mode. The
+// mode rarely changes, so it is looked up once and cached. Concurrent lookups
+// share one request, made without holding the cache lock.
+func (c *Client) GetPositionMode(ctx context.Context) (bool, error) {
+	c.accountCacheMutex.RLock()
+	if c.positionMode != nil {
+		hedge := *c.positionMode
+		c.accountCacheMutex.RUnlock()
+		return hedge, nil
+	}
+	gen := c.positionModeGen
+	c.accountCacheMutex.RUnlock()
+
+	result, err, _ := c.positionModeLookups.Do("positionMode", func() (interface{}, error) {
+		hedge, err := c.restClient.GetPositionMode(ctx)
+		if err != nil {
+			return false, err
+		}
+
+		// A mode changed or dropped while the lookup was in flight wins
+		c.accountCacheMutex.Lock()
+		if c.positionModeGen == gen {
+			c.positionMode = &hedge
+		}
+		c.accountCacheMutex.Unlock()
+		return hedge, nil
+	})
+	if err != nil {
+		return false, fmt.Errorf("failed to get position mode: %w", err)
+	}
+	return result.(bool), nil
+}
+
+// invalidatePositionMode drops the cached position mode so the next order
+// looks it up again
+func (c *Client) invalidatePositionMode() {
+	c.accountCacheMutex.Lock()
+	c.positionMode = nil
+	c.positionModeGen++
+	c.accountCacheMutex.Unlock()
+}
+
+// ChangePositionMode switches the futures account to hedge mode (dual=true)
+// or one-way mode and refreshes the cached mode once Binance accepts it
+func (c *Client) ChangePositionMode(ctx context.Context, dual bool) error {
+	if err := c.restClient.ChangePositionMode(ctx, dual); err != nil {
+		return fmt.Errorf("failed to change position mode: %w", err)
+	}
+
+	c.accountCacheMutex.Lock()
+	c.positionMode = &dual
+	c.positionModeGen++
+	c.accountCacheMutex.Unlock()
+
+	c.logger.Info().
+		Bool("dual_side_position", dual).
+		Msg("Position mode changed")
+	return nil
+}
+
+// cachedAccount returns the spot account snapshot if it is still fresh
+func (c *Client) cachedAccount() *AccountResponse {
+	c.accountCacheMutex.RLock()
+	defer c.accountCacheMutex.RUnlock()
+
+	if c.accountCache == nil || time.Since(c.accountCacheTime) >= c.accountCacheTTL {
+		return nil
+	}
+	return c.accountCache
+}
+
+// cachedFuturesAccount returns the futures account snapshot if it is still fresh
+func (c *Client) cachedFuturesAccount() *rest.FuturesAccountResponse {
+	c.accountCacheMutex.RLock()
+	defer c.accountCacheMutex.RUnlock()
+
+	if c.futuresAccountCache == nil || time.Since(c.futuresAccountCacheTime) >= c.accountCacheTTL {
+		return nil
+	}
+	return c.futuresAccountCache
+}
+
 // convertBalances converts REST balances to our Balance type
 func convertBalances(restBalances []rest.Balance) []Balance {
 	balances := make([]Balance, len(restBalances))
@@ -286,8 +603,7 @@ func (c *Client) CancelOrder(ctx context.Context, symbol string, orderID int64)
 		Int64("order_id", orderID).
 		Msg("Canceling order")
 
-	// Use REST client to cancel order
-	err := c.restClient.CancelOrder(ctx, symbol, orderID)
+	err := c.spotOrders().CancelOrder(ctx, symbol, orderID)
 	if err != nil {
 		c.logger.Error().
 			Err(err).
@@ -305,6 +621,81 @@ func (c *Client) CancelOrder(ctx context.Context, symbol string, orderID int64)
 	return nil
 }
 
+// GetOrderByClientID looks up an order on the client's venue by the client
+// order ID it was placed with
+func (c *Client) GetOrderByClientID(ctx context.Context, symbol, clientOrderID string) (*OrderResponse, error) {
+	if symbol == "" {
+		return nil, fmt.Errorf("symbol is required")
+	}
+	if clientOrderID == "" {
+		return nil, fmt.Errorf("client order ID is required")
+	}
+
+	if c.isFutures {
+		o, err := c.restClient.GetFuturesOrder(ctx, symbol, clientOrderID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get order %s: %w", clientOrderID, err)
+		}
+		return &OrderResponse{
+			Symbol:        o.Symbol,
+			OrderID:       o.OrderID,
+			ClientOrderID: o.ClientOrderID,
+			TransactTime:  o.UpdateTime,
+			Price:         o.Price,
+			OrigQty:       o.OrigQty,
+			ExecutedQty:   o.ExecutedQty,
+			Status:        o.Status,
+			TimeInForce:   o.TimeInForce,
+			Type:          o.Type,
+			Side:          o.Side,
+			Fills:         []Fill{},
+		}, nil
+	}
+
+	o, err := c.spotOrders().GetOrder(ctx, symbol, clientOrderID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get order %s: %w", clientOrderID, err)
+	}
+	return &OrderResponse{
+		Symbol:        o.Symbol,
+		OrderID:       o.OrderID,
+		ClientOrderID: o.ClientOrderID,
+		TransactTime:  o.Time,
+		Price:         o.Price,
+		OrigQty:       o.OrigQty,
+		ExecutedQty:   o.ExecutedQty,
+		Status:        o.Status,
+		TimeInForce:   o.TimeInForce,
+		Type:          o.Type,
+		Side:          o.Side,
+		Fills:         []Fill{},
+	}, nil
+}
+
+// CreateListenKey starts a user data stream on the client's venue
+func (c *Client) CreateListenKey(ctx context.Context) (string, error) {
+	if c.isFutures {
+		return c.restClient.CreateFuturesListenKey(ctx)
+	}
+	return c.restClient.CreateListenKey(ctx)
+}
+
+// KeepAliveListenKey extends the listen key returned by CreateListenKey
+func (c *Client) KeepAliveListenKey(ctx context.Context, listenKey string) error {
+	if c.isFutures {
+		return c.restClient.KeepAliveFuturesListenKey(ctx)
+	}
+	return c.restClient.KeepAliveListenKey(ctx, listenKey)
+}
+
+// CloseListenKey ends the user data stream opened by CreateListenKey
+func (c *Client) CloseListenKey(ctx context.Context, listenKey string) error {
+	if c.isFutures {
+		return c.restClient.CloseFuturesListenKey(ctx)
+	}
+	return c.restClient.CloseListenKey(ctx, listenKey)
+}
+
 // GetOpenOrders retrieves open orders for a symbol
 func (c *Client) GetOpenOrders(ctx context.Context, symbol string) ([]*Order, error) {
 	if symbol == "" {
@@ -315,8 +706,7 @@ func (c *Client) GetOpenOrders(ctx context.Context, symbol string) ([]*Order, er
 		Str("symbol", symbol).
 		Msg("Retrieving open orders")
 
-	// Get open orders from REST client
-	restOrders, err := c.restClient.GetOpenOrders(ctx, symbol)
+	restOrders, err := c.spotOrders().GetOpenOrders(ctx, symbol)
 	if err != nil {
 		c.logger.Error().
 			Err(err).
@@ -325,7 +715,114 @@ func (c *Client) GetOpenOrders(ctx context.Context, symbol string) ([]*Order, er
 		return nil, fmt.Errorf("failed to get open orders: %w", err)
 	}
 
-	// Convert REST orders to our Order type
+	orders := convertOrders(restOrders)
+
+	c.logger.Debug().
+		Str("symbol", symbol).
+		Int("order_count", len(orders)).
+		Msg("Retrieved open orders")
+
+	return orders, nil
+}
+
+// GetAllOpenOrders retrieves open orders across every symbol in one call on
+// the client's venue. It is far heavier than a single-symbol query but
+// cheaper than querying many symbols one by one.
+func (c *Client) GetAllOpenOrders(ctx context.Context) ([]*Order, error) {
+	var restOrders []rest.Order
+	var err error
+	if c.isFutures {
+		restOrders, err = c.restClient.GetFuturesOpenOrders(ctx, "")
+	} else {
+		restOrders, err = c.spotOrders().GetAllOpenOrders(ctx)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get all open orders: %w", err)
+	}
+
+	return convertOrders(restOrders), nil
+}
+
+// GetOpenOrdersForSymbols retrieves open orders on symbols. It tries the
+// single broad query first and, when the key lacks permission for it, falls
+// back to one query per symbol. The fallback is paced by the REST rate
+// limiter and backs off exponentially when Binance reports a rate limit.
+func (c *Client) GetOpenOrdersForSymbols(ctx context.Context, symbols []string) ([]*Order, error) {
+	all, err := c.GetAllOpenOrders(ctx)
+	if err == nil {
+		wanted := make(map[string]bool, len(symbols))
+		for _, symbol := range symbols {
+			wanted[strings.ToUpper(symbol)] = true
+		}
+		orders := make([]*Order, 0, len(all))
+		for _, order := range all {
+			if wanted[order.Symbol] {
+				orders = append(orders, order)
+			}
+		}
+		return orders, nil
+	}
+
+	var binanceErr *rest.BinanceError
+	if !errors.As(err, &binanceErr) || !binanceErr.IsAuthError() {
+		return nil, err
+	}
+	c.logger.Warn().
+		Err(err).
+		Int("symbols", len(symbols)).
+		Msg("Broad open orders query denied, querying symbols one by one")
+
+	var orders []*Order
+	for _, symbol := range symbols {
+		symbolOrders, err := c.openOrdersWithBackoff(ctx, symbol)
+		if err != nil {
+			return nil, err
+		}
+		orders = append(orders, symbolOrders...)
+	}
+	return orders, nil
+}
+
+// openOrdersWithBackoff queries one symbol's open orders, retrying with
+// doubling delays while Binance reports a rate limit
+func (c *Client) openOrdersWithBackoff(ctx context.Context, symbol string) ([]*Order, error) {
+	delay := c.openOrdersBackoff
+	for attempt := 1; ; attempt++ {
+		var restOrders []rest.Order
+		var err error
+		if c.isFutures {
+			restOrders, err = c.restClient.GetFuturesOpenOrders(ctx, symbol)
+		} else {
+			restOrders, err = c.spotOrders().GetOpenOrders(ctx, symbol)
+		}
+		if err == nil {
+			return convertOrders(restOrders), nil
+		}
+
+		var binanceErr *rest.BinanceError
+		if !errors.As(err, &binanceErr) || !binanceErr.IsRateLimit() || attempt >= maxOpenOrdersAttempts {
+			return nil, fmt.Errorf("failed to get open orders for %s: %w", symbol, err)
+		}
+
+		c.logger.Warn().
+			Err(err).
+			Str("symbol", symbol).
+			Dur("delay", delay).
+			Msg("Rate limited querying open orders, backing off")
+
+		timer := time.NewTimer(delay)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return nil, ctx.Err()
+		case <-timer.C:
+		}
+		delay = min(delay*2, maxOpenOrdersBackoff)
+	}
+}
+
+// convertOrders converts REST orders to our Order type
+func convertOrders(restOrders []rest.Order) []*Order {
 	orders := make([]*Order, len(restOrders))
 	for i, o := range restOrders {
 		orders[i] = &Order{
@@ -343,13 +840,7 @@ func (c *Client) GetOpenOrders(ctx context.Context, symbol string) ([]*Order, er
 			UpdateTime:    o.UpdateTime,
 		}
 	}
-
-	c.logger.Debug().
-		Str("symbol", symbol).
-		Int("order_count", len(orders)).
-		Msg("Retrieved open orders")
-
-	return orders, nil
+	return orders
 }
 
 // Validation functions
@@ -373,16 +864,26 @@ func (c *Client) validateSpotOrder(order SpotOrderRequest) error {
 	if !validTypes[order.Type] {
 		return fmt.Errorf("invalid order type: %s", order.Type)
 	}
+	if err := validateTimeInForce(spotTimeInForce, order.Type, order.TimeInForce); err != nil {
+		return err
+	}
+	if order.QuoteOrderQty.IsNegative() {
+		return fmt.Errorf("quote order quantity must be positive")
+	}
+	if !order.QuoteOrderQty.IsZero() {
+		// Quote-sized orders spend a fixed amount of the quote asset
+		if order.Type != "MARKET" {
+			return fmt.Errorf("quote order quantity is only supported for MARKET orders")
+		}
+		if !order.Quantity.IsZero() {
+			return fmt.Errorf("quantity and quote order quantity are mutually exclusive")
+		}
+		return nil
+	}
 	if order.Quantity.LessThanOrEqual(decimal.Zero) {
 		return fmt.Errorf("quantity must be positive")
 	}
 
-	// Check for extremely large quantities that would indicate insufficient balance
-	maxQuantity := decimal.RequireFromString("100000")
-	if order.Quantity.GreaterThan(maxQuantity) {
-		return fmt.Errorf("insufficient balance for quantity: %s", order.Quantity.String())
-	}
-
 	if order.Type == "LIMIT" && order.Price.LessThanOrEqual(decimal.Zero) {
 		return fmt.Errorf("price must be positive for limit orders")
 	}
@@ -413,46 +914,420 @@ func (c *Client) validateFuturesOrder(order FuturesOrderRequest) error {
 	if order.Side != "BUY" && order.Side != "SELL" {
 		return fmt.Errorf("invalid side: %s", order.Side)
 	}
-	if order.Type != "MARKET" && order.Type != "LIMIT" {
+	if !validFuturesOrderTypes[order.Type] {
 		return fmt.Errorf("invalid order type: %s", order.Type)
 	}
-	if order.Quantity.LessThanOrEqual(decimal.Zero) {
+	if err := validateTimeInForce(futuresTimeInForce, order.Type, order.TimeInForce); err != nil {
+		return err
+	}
+
+	if order.ClosePosition {
+		if order.Type != "STOP_MARKET" && order.Type != "TAKE_PROFIT_MARKET" {
+			return fmt.Errorf("closePosition is only supported for STOP_MARKET and TAKE_PROFIT_MARKET orders")
+		}
+		if order.ReduceOnly {
+			return fmt.Errorf("reduceOnly cannot be combined with closePosition")
+		}
+		if !order.Quantity.IsZero() {
+			return fmt.Errorf("quantity must not be set with closePosition")
+		}
+	} else if order.Quantity.LessThanOrEqual(decimal.Zero) {
 		return fmt.Errorf("quantity must be positive")
 	}
 
-	// Check for extremely large quantities that would indicate margin issues
-	maxQuantity := decimal.RequireFromString("100000")
-	if order.Quantity.GreaterThan(maxQuantity) {
-		return fmt.Errorf("insufficient margin for quantity: %s", order.Quantity.String())
+	if err := ValidateWorkingType(order.WorkingType); err != nil {
+		return err
 	}
 
-	if order.Type == "LIMIT" && order.Price.LessThanOrEqual(decimal.Zero) {
-		return fmt.Errorf("price must be positive for limit orders")
+	switch order.PositionSide {
+	case "", PositionSideBoth:
+	case PositionSideLong, PositionSideShort:
+		if order.ReduceOnly {
+			return fmt.Errorf("reduceOnly cannot be used with positionSide %s", order.PositionSide)
+		}
+	default:
+		return fmt.Errorf("invalid position side: %s", order.PositionSide)
+	}
+
+	switch order.Type {
+	case "LIMIT":
+		if order.Price.LessThanOrEqual(decimal.Zero) {
+			return fmt.Errorf("price must be positive for limit orders")
+		}
+	case "STOP", "TAKE_PROFIT":
+		if order.Price.LessThanOrEqual(decimal.Zero) {
+			return fmt.Errorf("price must be positive for %s orders", order.Type)
+		}
+		if order.StopPrice.LessThanOrEqual(decimal.Zero) {
+			return fmt.Errorf("stopPrice must be positive for %s orders", order.Type)
+		}
+	case "STOP_MARKET", "TAKE_PROFIT_MARKET":
+		if order.StopPrice.LessThanOrEqual(decimal.Zero) {
+			return fmt.Errorf("stopPrice must be positive for %s orders", order.Type)
+		}
+	case "TRAILING_STOP_MARKET":
+		if order.CallbackRate.LessThan(minCallbackRate) || order.CallbackRate.GreaterThan(maxCallbackRate) {
+			return fmt.Errorf("callbackRate must be between %s and %s for TRAILING_STOP_MARKET orders", minCallbackRate, maxCallbackRate)
+		}
+		if order.ActivationPrice.IsNegative() {
+			return fmt.Errorf("activationPrice must not be negative")
+		}
 	}
 
 	return nil
 }
 
+// validFuturesOrderTypes lists the USD-M futures order types the client accepts
+var validFuturesOrderTypes = map[string]bool{
+	"MARKET":               true,
+	"LIMIT":                true,
+	"STOP":                 true,
+	"TAKE_PROFIT":          true,
+	"STOP_MARKET":          true,
+	"TAKE_PROFIT_MARKET":   true,
+	"TRAILING_STOP_MARKET": true,
+}
+
+// Time-in-force values permitted per order type. Types missing from a map
+// execute immediately or rest as maker-only and reject a time in force.
+var (
+	spotTimeInForce = map[string]map[string]bool{
+		"LIMIT":             {"GTC": true, "IOC": true, "FOK": true},
+		"STOP_LOSS_LIMIT":   {"GTC": true, "IOC": true, "FOK": true},
+		"TAKE_PROFIT_LIMIT": {"GTC": true, "IOC": true, "FOK": true},
+	}
+	futuresTimeInForce = map[string]map[string]bool{
+		"LIMIT":                {"GTC": true, "IOC": true, "FOK": true, "GTX": true, "GTD": true},
+		"STOP":                 {"GTC": true, "IOC": true, "FOK": true, "GTX": true},
+		"TAKE_PROFIT":          {"GTC": true, "IOC": true, "FOK": true, "GTX": true},
+		"STOP_MARKET":          {"GTC": true, "GTE_GTC": true},
+		"TAKE_PROFIT_MARKET":   {"GTC": true, "GTE_GTC": true},
+		"TRAILING_STOP_MARKET": {"GTC": true, "GTE_GTC": true},
+	}
+	// optionalTimeInForce lists order types that accept a time in force but
+	// are sent without one unless the caller sets it
+	optionalTimeInForce = map[string]bool{
+		"STOP_MARKET":          true,
+		"TAKE_PROFIT_MARKET":   true,
+		"TRAILING_STOP_MARKET": true,
+	}
+)
+
+// defaultTimeInForce fills in GTC for order types that require a time in force
+func defaultTimeInForce(permitted map[string]map[string]bool, orderType, timeInForce string) string {
+	if _, ok := permitted[orderType]; ok && !optionalTimeInForce[orderType] && timeInForce == "" {
+		return "GTC"
+	}
+	return timeInForce
+}
+
+// validateTimeInForce rejects time-in-force values the order type does not
+// accept. An empty value is left for defaultTimeInForce to fill in
+func validateTimeInForce(permitted map[string]map[string]bool, orderType, timeInForce string) error {
+	allowed, ok := permitted[orderType]
+	if !ok {
+		if timeInForce != "" {
+			return fmt.Errorf("timeInForce is not supported for %s orders", orderType)
+		}
+		return nil
+	}
+	if timeInForce != "" && !allowed[timeInForce] {
+		return fmt.Errorf("invalid timeInForce %s for %s orders", timeInForce, orderType)
+	}
+	return nil
+}
+
+// Futures position sides. BOTH is the only side in one-way mode.
+const (
+	PositionSideBoth  = "BOTH"
+	PositionSideLong  = "LONG"
+	PositionSideShort = "SHORT"
+)
+
+// Futures stop trigger price types
+const (
+	WorkingTypeMarkPrice     = "MARK_PRICE"
+	WorkingTypeContractPrice = "CONTRACT_PRICE"
+)
+
+// ValidateWorkingType checks a futures stop trigger price type. Empty leaves
+// the exchange default (CONTRACT_PRICE).
+func ValidateWorkingType(workingType string) error {
+	switch workingType {
+	case "", WorkingTypeMarkPrice, WorkingTypeContractPrice:
+		return nil
+	}
+	return fmt.Errorf("invalid working type: %s", workingType)
+}
+
+// Trailing stop callback rate bounds, in percent
+var (
+	minCallbackRate = decimal.RequireFromString("0.1")
+	maxCallbackRate = decimal.NewFromInt(10)
+)
+
+// checkSpotBalance rejects orders the cached account balance cannot cover.
+// Without a fresh account snapshot or known symbol assets the check is skipped
+// and the exchange has the final say.
+func (c *Client) checkSpotBalance(order SpotOrderRequest) error {
+	account := c.cachedAccount()
+	if account == nil {
+		return nil
+	}
+
+	c.exchangeInfoCacheMutex.RLock()
+	cache := c.exchangeInfoCache
+	c.exchangeInfoCacheMutex.RUnlock()
+	if cache == nil {
+		return nil
+	}
+	info := cache.cachedSymbolInfo(order.Symbol)
+	if info == nil {
+		return nil
+	}
+
+	var asset string
+	var required decimal.Decimal
+	switch {
+	case order.Side == "SELL":
+		asset, required = info.BaseAsset, order.Quantity
+	case !order.QuoteOrderQty.IsZero():
+		asset, required = info.QuoteAsset, order.QuoteOrderQty
+	case order.Price.IsPositive():
+		asset, required = info.QuoteAsset, order.Quantity.Mul(order.Price)
+	default:
+		// Market buy sized in base asset; cost is unknown until fill
+		return nil
+	}
+	if asset == "" || required.IsZero() {
+		return nil
+	}
+
+	free := decimal.Zero
+	for _, balance := range account.Balances {
+		if balance.Asset == asset {
+			free = balance.Free
+			break
+		}
+	}
+	if free.LessThan(required) {
+		return fmt.Errorf("insufficient %s balance: need %s, have %s", asset, required, free)
+	}
+	return nil
+}
+
+// checkPositionSide verifies the order's positionSide matches the account's
+// position mode, which Binance would otherwise reject after the round trip
+func (c *Client) checkPositionSide(ctx context.Context, order FuturesOrderRequest) error {
+	hedge, err := c.GetPositionMode(ctx)
+	if err != nil {
+		return err
+	}
+
+	hedgeSide := order.PositionSide == PositionSideLong || order.PositionSide == PositionSideShort
+	if hedge && !hedgeSide {
+		return fmt.Errorf("positionSide LONG or SHORT is required in hedge mode")
+	}
+	if !hedge && hedgeSide {
+		return fmt.Errorf("positionSide %s requires hedge mode", order.PositionSide)
+	}
+	return nil
+}
+
+// checkFuturesMargin rejects orders whose initial margin exceeds the cached
+// available balance. It needs a priced order and the symbol's leverage from
+// the account snapshot; otherwise the exchange decides.
+func (c *Client) checkFuturesMargin(order FuturesOrderRequest) error {
+	if order.ReduceOnly || order.ClosePosition || !order.Price.IsPositive() {
+		return nil
+	}
+
+	account := c.cachedFuturesAccount()
+	if account == nil {
+		return nil
+	}
+
+	leverage := decimal.Zero
+	if position := account.PositionFor(order.Symbol); position != nil {
+		parsed, err := decimal.NewFromString(position.Leverage)
+		if err != nil && c.restClient.StrictDecimals() {
+			return fmt.Errorf("invalid leverage %q for %s: %w", position.Leverage, order.Symbol, err)
+		}
+		leverage = parsed
+	}
+	if !leverage.IsPositive() {
+		return nil
+	}
+
+	required := order.Quantity.Mul(order.Price).Div(leverage)
+	if available := account.AvailableMargin(); available.LessThan(required) {
+		return fmt.Errorf("insufficient margin: need %s, available %s", required, available)
+	}
+	return nil
+}
+
+// RefreshExchangeInfo fetches symbol trading rules and replaces the cached copy.
+// Afterwards the cache refreshes itself once its TTL expires.
+func (c *Client) RefreshExchangeInfo(ctx context.Context) error {
+	cache := c.getExchangeInfoCache()
+	if cache == nil {
+		return fmt.Errorf("exchange info unavailable: rest client not configured")
+	}
+
+	if err := cache.Refresh(ctx); err != nil {
+		return fmt.Errorf("failed to refresh exchange info: %w", err)
+	}
+	return nil
+}
+
+// ExchangeInfoFresh reports whether symbol rules were loaded within the cache TTL
+func (c *Client) ExchangeInfoFresh() bool {
+	c.exchangeInfoCacheMutex.RLock()
+	cache := c.exchangeInfoCache
+	c.exchangeInfoCacheMutex.RUnlock()
+
+	if cache == nil {
+		return false
+	}
+	return time.Since(cache.LastRefresh()) < cache.cacheTTL
+}
+
+// Ping checks connectivity to the venue's REST API
+func (c *Client) Ping(ctx context.Context) error {
+	if c.isFutures {
+		return c.restClient.PingFutures(ctx)
+	}
+	return c.restClient.Ping(ctx)
+}
+
+// RateLimiterAvailable returns how many requests can be sent before the
+// REST rate limiter starts throttling
+func (c *Client) RateLimiterAvailable() float64 {
+	return c.restClient.RateLimiter().Available()
+}
+
+// GetExchangeInfoForSymbol returns trading rules for a single symbol, fetching
+// only that symbol on a cache miss
+func (c *Client) GetExchangeInfoForSymbol(ctx context.Context, symbol string) (*SymbolInfo, error) {
+	cache := c.getExchangeInfoCache()
+	if cache == nil {
+		return nil, fmt.Errorf("exchange info unavailable: rest client not configured")
+	}
+
+	return cache.GetSymbolInfo(ctx, symbol, c.isFutures)
+}
+
+// getExchangeInfoCache returns the exchange info cache, creating it on first use
+func (c *Client) getExchangeInfoCache() *ExchangeInfoCache {
+	c.exchangeInfoCacheMutex.RLock()
+	if c.exchangeInfoCache != nil || c.restClient == nil {
+		cache := c.exchangeInfoCache
+		c.exchangeInfoCacheMutex.RUnlock()
+		return cache
+	}
+	c.exchangeInfoCacheMutex.RUnlock()
+
+	c.exchangeInfoCacheMutex.Lock()
+	defer c.exchangeInfoCacheMutex.Unlock()
+
+	// Double-check pattern
+	if c.exchangeInfoCache != nil {
+		return c.exchangeInfoCache
+	}
+
+	ttl := c.exchangeInfoCacheTTL
+	if ttl <= 0 {
+		ttl = defaultExchangeInfoCacheTTL
+	}
+
+	logger := c.logger.With().Str("component", "exchange_info").Logger()
+	if c.isFutures {
+		c.exchangeInfoCache = NewExchangeInfoCache(nil, c.restClient, ttl, logger)
+	} else {
+		c.exchangeInfoCache = NewExchangeInfoCache(c.restClient, nil, ttl, logger)
+	}
+	return c.exchangeInfoCache
+}
+
 // RoundPrice rounds a price according to symbol rules
 func (c *Client) RoundPrice(ctx context.Context, symbol string, price decimal.Decimal) (decimal.Decimal, error) {
-	if c.exchangeInfoCache == nil {
+	cache := c.getExchangeInfoCache()
+	if cache == nil {
 		return price, nil // No rounding if cache not available
 	}
-	return c.exchangeInfoCache.RoundPrice(ctx, symbol, price, c.isFutures)
+	return cache.RoundPrice(ctx, symbol, price, c.isFutures)
+}
+
+// RoundQuoteQuantity rounds a quote-asset amount, such as a quoteOrderQty,
+// to the symbol's quote asset precision
+func (c *Client) RoundQuoteQuantity(ctx context.Context, symbol string, value decimal.Decimal) (decimal.Decimal, error) {
+	cache := c.getExchangeInfoCache()
+	if cache == nil {
+		return value, nil // No rounding if cache not available
+	}
+	return cache.RoundQuoteQuantity(ctx, symbol, value, c.isFutures)
+}
+
+// RoundOrderValues rounds a batch of prices and quantities for one symbol,
+// fetching its rules at most once
+func (c *Client) RoundOrderValues(ctx context.Context, symbol string, prices, quantities []decimal.Decimal) ([]decimal.Decimal, []decimal.Decimal, error) {
+	cache := c.getExchangeInfoCache()
+	if cache == nil {
+		// No rounding if cache not available
+		return append([]decimal.Decimal(nil), prices...), append([]decimal.Decimal(nil), quantities...), nil
+	}
+	return cache.RoundOrderValues(ctx, symbol, prices, quantities, c.isFutures)
 }
 
 // RoundQuantity rounds a quantity according to symbol rules
 func (c *Client) RoundQuantity(ctx context.Context, symbol string, quantity decimal.Decimal) (decimal.Decimal, error) {
-	if c.exchangeInfoCache == nil {
+	cache := c.getExchangeInfoCache()
+	if cache == nil {
 		return quantity, nil // No rounding if cache not available
 	}
-	return c.exchangeInfoCache.RoundQuantity(ctx, symbol, quantity, c.isFutures)
+	return cache.RoundQuantity(ctx, symbol, quantity, c.isFutures)
+}
+
+// ValidatePercentPrice rejects prices outside the symbol's PERCENT_PRICE
+// range. The reference is the weighted average price on spot and the mark
+// price on futures, matching what Binance checks against. It is only
+// fetched when the symbol has the filter.
+func (c *Client) ValidatePercentPrice(ctx context.Context, symbol string, prices ...decimal.Decimal) error {
+	cache := c.getExchangeInfoCache()
+	if cache == nil {
+		return nil // Skip validation if cache not available
+	}
+
+	info, err := cache.GetSymbolInfo(ctx, symbol, c.isFutures)
+	if err != nil {
+		return err
+	}
+	if !info.hasPercentPrice() {
+		return nil
+	}
+
+	var reference decimal.Decimal
+	if c.isFutures {
+		reference, err = c.GetMarkPrice(ctx, symbol)
+		if err != nil {
+			return err
+		}
+	} else {
+		avgPrice, err := c.restClient.GetAvgPrice(ctx, symbol)
+		if err != nil {
+			return fmt.Errorf("failed to get average price for %s: %w", symbol, err)
+		}
+		reference = avgPrice.Price
+	}
+
+	return info.validatePercentPrice(reference, prices)
 }
 
 // ValidateNotional validates order notional value
 func (c *Client) ValidateNotional(ctx context.Context, symbol string, price, quantity decimal.Decimal) error {
-	if c.exchangeInfoCache == nil {
+	cache := c.getExchangeInfoCache()
+	if cache == nil {
 		return nil // Skip validation if cache not available
 	}
-	return c.exchangeInfoCache.ValidateNotional(ctx, symbol, price, quantity, c.isFutures)
+	return cache.ValidateNotional(ctx, symbol, price, quantity, c.isFutures)
 }
@@ -2,6 +2,9 @@ package binance
 
 import (
 	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
 	"sync"
 	"testing"
 	"time"
@@ -11,7 +14,9 @@ import (
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	"router/internal/auth"
+	"router/internal/config"
 	"router/internal/rest"
+	"router/internal/testutil"
 )
 
 func TestNewClient_ValidatesConfiguration(t *testing.T) {
@@ -111,6 +116,38 @@ func TestOrderValidation(t *testing.T) {
 			},
 			wantErr: "stopPrice must be positive",
 		},
+		{
+			name: "quantity and quote order quantity both set",
+			order: SpotOrderRequest{
+				Symbol:        "BTCUSDT",
+				Side:          "BUY",
+				Type:          "MARKET",
+				Quantity:      decimal.NewFromFloat(0.001),
+				QuoteOrderQty: decimal.NewFromInt(100),
+			},
+			wantErr: "mutually exclusive",
+		},
+		{
+			name: "quote order quantity on limit order",
+			order: SpotOrderRequest{
+				Symbol:        "BTCUSDT",
+				Side:          "BUY",
+				Type:          "LIMIT",
+				Price:         decimal.NewFromInt(50000),
+				QuoteOrderQty: decimal.NewFromInt(100),
+			},
+			wantErr: "only supported for MARKET orders",
+		},
+		{
+			name: "valid quote-sized market buy",
+			order: SpotOrderRequest{
+				Symbol:        "BTCUSDT",
+				Side:          "BUY",
+				Type:          "MARKET",
+				QuoteOrderQty: decimal.NewFromInt(100),
+			},
+			wantErr: "",
+		},
 		{
 			name: "valid market order",
 			order: SpotOrderRequest{
@@ -121,6 +158,17 @@ func TestOrderValidation(t *testing.T) {
 			},
 			wantErr: "",
 		},
+		{
+			name: "large quantity of low-priced asset",
+			order: SpotOrderRequest{
+				Symbol:   "SHIBUSDT",
+				Side:     "BUY",
+				Type:     "LIMIT",
+				Quantity: decimal.NewFromInt(5000000),
+				Price:    decimal.RequireFromString("0.00001"),
+			},
+			wantErr: "",
+		},
 		{
 			name: "valid limit order",
 			order: SpotOrderRequest{
@@ -161,6 +209,404 @@ func TestOrderValidation(t *testing.T) {
 	}
 }
 
+func TestFuturesOrderValidation(t *testing.T) {
+	client := &Client{
+		logger: zerolog.Nop(),
+	}
+
+	qty := decimal.RequireFromString("0.01")
+	price := decimal.NewFromInt(50000)
+	stop := decimal.NewFromInt(49000)
+
+	tests := []struct {
+		name    string
+		order   FuturesOrderRequest
+		wantErr string
+	}{
+		{
+			name:    "unknown type",
+			order:   FuturesOrderRequest{Symbol: "BTCUSDT", Side: "BUY", Type: "STOP_LOSS_LIMIT", Quantity: qty},
+			wantErr: "invalid order type",
+		},
+		{
+			name:  "stop market",
+			order: FuturesOrderRequest{Symbol: "BTCUSDT", Side: "SELL", Type: "STOP_MARKET", Quantity: qty, StopPrice: stop, ReduceOnly: true},
+		},
+		{
+			name:    "stop market without stop price",
+			order:   FuturesOrderRequest{Symbol: "BTCUSDT", Side: "SELL", Type: "STOP_MARKET", Quantity: qty},
+			wantErr: "stopPrice must be positive for STOP_MARKET orders",
+		},
+		{
+			name:  "take profit market closing position",
+			order: FuturesOrderRequest{Symbol: "BTCUSDT", Side: "SELL", Type: "TAKE_PROFIT_MARKET", StopPrice: price, ClosePosition: true},
+		},
+		{
+			name:    "take profit market without stop price",
+			order:   FuturesOrderRequest{Symbol: "BTCUSDT", Side: "SELL", Type: "TAKE_PROFIT_MARKET", Quantity: qty},
+			wantErr: "stopPrice must be positive for TAKE_PROFIT_MARKET orders",
+		},
+		{
+			name:  "stop limit",
+			order: FuturesOrderRequest{Symbol: "BTCUSDT", Side: "SELL", Type: "STOP", Quantity: qty, Price: stop, StopPrice: stop},
+		},
+		{
+			name:    "stop limit without price",
+			order:   FuturesOrderRequest{Symbol: "BTCUSDT", Side: "SELL", Type: "STOP", Quantity: qty, StopPrice: stop},
+			wantErr: "price must be positive for STOP orders",
+		},
+		{
+			name:    "take profit limit without stop price",
+			order:   FuturesOrderRequest{Symbol: "BTCUSDT", Side: "SELL", Type: "TAKE_PROFIT", Quantity: qty, Price: price},
+			wantErr: "stopPrice must be positive for TAKE_PROFIT orders",
+		},
+		{
+			name:  "trailing stop",
+			order: FuturesOrderRequest{Symbol: "BTCUSDT", Side: "SELL", Type: "TRAILING_STOP_MARKET", Quantity: qty, CallbackRate: decimal.NewFromInt(1), ActivationPrice: price},
+		},
+		{
+			name:    "trailing stop without callback rate",
+			order:   FuturesOrderRequest{Symbol: "BTCUSDT", Side: "SELL", Type: "TRAILING_STOP_MARKET", Quantity: qty},
+			wantErr: "callbackRate must be between 0.1 and 10",
+		},
+		{
+			name:    "trailing stop callback rate too high",
+			order:   FuturesOrderRequest{Symbol: "BTCUSDT", Side: "SELL", Type: "TRAILING_STOP_MARKET", Quantity: qty, CallbackRate: decimal.NewFromInt(15)},
+			wantErr: "callbackRate must be between 0.1 and 10",
+		},
+		{
+			name:  "mark price stop",
+			order: FuturesOrderRequest{Symbol: "BTCUSDT", Side: "SELL", Type: "STOP_MARKET", Quantity: qty, StopPrice: stop, WorkingType: WorkingTypeMarkPrice},
+		},
+		{
+			name:    "invalid working type",
+			order:   FuturesOrderRequest{Symbol: "BTCUSDT", Side: "SELL", Type: "STOP_MARKET", Quantity: qty, StopPrice: stop, WorkingType: "INDEX_PRICE"},
+			wantErr: "invalid working type",
+		},
+		{
+			name:    "close position on limit order",
+			order:   FuturesOrderRequest{Symbol: "BTCUSDT", Side: "SELL", Type: "LIMIT", Price: price, ClosePosition: true},
+			wantErr: "closePosition is only supported",
+		},
+		{
+			name:    "close position with reduce only",
+			order:   FuturesOrderRequest{Symbol: "BTCUSDT", Side: "SELL", Type: "STOP_MARKET", StopPrice: stop, ClosePosition: true, ReduceOnly: true},
+			wantErr: "reduceOnly cannot be combined with closePosition",
+		},
+		{
+			name:    "close position with quantity",
+			order:   FuturesOrderRequest{Symbol: "BTCUSDT", Side: "SELL", Type: "STOP_MARKET", Quantity: qty, StopPrice: stop, ClosePosition: true},
+			wantErr: "quantity must not be set with closePosition",
+		},
+		{
+			name:  "hedge long position",
+			order: FuturesOrderRequest{Symbol: "BTCUSDT", Side: "BUY", Type: "LIMIT", Quantity: qty, Price: price, PositionSide: PositionSideLong},
+		},
+		{
+			name:    "invalid position side",
+			order:   FuturesOrderRequest{Symbol: "BTCUSDT", Side: "BUY", Type: "LIMIT", Quantity: qty, Price: price, PositionSide: "NET"},
+			wantErr: "invalid position side",
+		},
+		{
+			name:    "reduce only with hedge position side",
+			order:   FuturesOrderRequest{Symbol: "BTCUSDT", Side: "SELL", Type: "STOP_MARKET", Quantity: qty, StopPrice: stop, ReduceOnly: true, PositionSide: PositionSideLong},
+			wantErr: "reduceOnly cannot be used with positionSide LONG",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := client.validateFuturesOrder(tt.order)
+			if tt.wantErr != "" {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tt.wantErr)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestTimeInForceValidation(t *testing.T) {
+	tests := []struct {
+		name        string
+		permitted   map[string]map[string]bool
+		orderType   string
+		timeInForce string
+		wantErr     string
+	}{
+		{name: "spot limit GTC", permitted: spotTimeInForce, orderType: "LIMIT", timeInForce: "GTC"},
+		{name: "spot limit IOC", permitted: spotTimeInForce, orderType: "LIMIT", timeInForce: "IOC"},
+		{name: "spot stop loss limit FOK", permitted: spotTimeInForce, orderType: "STOP_LOSS_LIMIT", timeInForce: "FOK"},
+		{name: "spot market without TIF", permitted: spotTimeInForce, orderType: "MARKET"},
+		{name: "spot limit GTX", permitted: spotTimeInForce, orderType: "LIMIT", timeInForce: "GTX", wantErr: "invalid timeInForce GTX for LIMIT orders"},
+		{name: "spot limit maker FOK", permitted: spotTimeInForce, orderType: "LIMIT_MAKER", timeInForce: "FOK", wantErr: "timeInForce is not supported for LIMIT_MAKER orders"},
+		{name: "spot market GTC", permitted: spotTimeInForce, orderType: "MARKET", timeInForce: "GTC", wantErr: "timeInForce is not supported for MARKET orders"},
+		{name: "spot stop loss IOC", permitted: spotTimeInForce, orderType: "STOP_LOSS", timeInForce: "IOC", wantErr: "timeInForce is not supported for STOP_LOSS orders"},
+		{name: "futures limit GTX", permitted: futuresTimeInForce, orderType: "LIMIT", timeInForce: "GTX"},
+		{name: "futures take profit FOK", permitted: futuresTimeInForce, orderType: "TAKE_PROFIT", timeInForce: "FOK"},
+		{name: "futures limit unknown", permitted: futuresTimeInForce, orderType: "LIMIT", timeInForce: "DAY", wantErr: "invalid timeInForce DAY for LIMIT orders"},
+		{name: "futures limit GTD", permitted: futuresTimeInForce, orderType: "LIMIT", timeInForce: "GTD"},
+		{name: "futures stop market GTC", permitted: futuresTimeInForce, orderType: "STOP_MARKET", timeInForce: "GTC"},
+		{name: "futures stop market GTE_GTC", permitted: futuresTimeInForce, orderType: "STOP_MARKET", timeInForce: "GTE_GTC"},
+		{name: "futures take profit market GTE_GTC", permitted: futuresTimeInForce, orderType: "TAKE_PROFIT_MARKET", timeInForce: "GTE_GTC"},
+		{name: "futures trailing stop GTC", permitted: futuresTimeInForce, orderType: "TRAILING_STOP_MARKET", timeInForce: "GTC"},
+		{name: "futures stop market without TIF", permitted: futuresTimeInForce, orderType: "STOP_MARKET"},
+		{name: "futures stop GTD", permitted: futuresTimeInForce, orderType: "STOP", timeInForce: "GTD", wantErr: "invalid timeInForce GTD for STOP orders"},
+		{name: "futures trailing stop IOC", permitted: futuresTimeInForce, orderType: "TRAILING_STOP_MARKET", timeInForce: "IOC", wantErr: "invalid timeInForce IOC for TRAILING_STOP_MARKET orders"},
+		{name: "futures market GTC", permitted: futuresTimeInForce, orderType: "MARKET", timeInForce: "GTC", wantErr: "timeInForce is not supported for MARKET orders"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := validateTimeInForce(tt.permitted, tt.orderType, tt.timeInForce)
+			if tt.wantErr != "" {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tt.wantErr)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestDefaultTimeInForce(t *testing.T) {
+	assert.Equal(t, "GTC", defaultTimeInForce(futuresTimeInForce, "LIMIT", ""))
+	assert.Equal(t, "GTD", defaultTimeInForce(futuresTimeInForce, "LIMIT", "GTD"))
+	assert.Empty(t, defaultTimeInForce(futuresTimeInForce, "STOP_MARKET", ""), "optional time in force is left to the exchange")
+	assert.Equal(t, "GTE_GTC", defaultTimeInForce(futuresTimeInForce, "TAKE_PROFIT_MARKET", "GTE_GTC"))
+	assert.Empty(t, defaultTimeInForce(futuresTimeInForce, "MARKET", ""))
+}
+
+func TestPlaceSpotOrder_DefaultsTimeInForce(t *testing.T) {
+	fake := testutil.NewFakeBinance(t)
+	fake.AddSymbol(testutil.BTCUSDT)
+
+	signer := auth.NewSigner("test-key", "test-secret")
+	client, err := NewClient(fake.URL(), signer, rest.NewClient(fake.URL(), signer), zerolog.Nop())
+	require.NoError(t, err)
+
+	_, err = client.PlaceSpotOrder(context.Background(), SpotOrderRequest{
+		Symbol:   "BTCUSDT",
+		Side:     "BUY",
+		Type:     "LIMIT",
+		Quantity: decimal.RequireFromString("0.001"),
+		Price:    decimal.NewFromInt(50000),
+	})
+	require.NoError(t, err)
+
+	_, err = client.PlaceSpotOrder(context.Background(), SpotOrderRequest{
+		Symbol:   "BTCUSDT",
+		Side:     "BUY",
+		Type:     "MARKET",
+		Quantity: decimal.RequireFromString("0.001"),
+	})
+	require.NoError(t, err)
+
+	_, err = client.PlaceSpotOrder(context.Background(), SpotOrderRequest{
+		Symbol:      "BTCUSDT",
+		Side:        "BUY",
+		Type:        "LIMIT_MAKER",
+		Quantity:    decimal.RequireFromString("0.001"),
+		Price:       decimal.NewFromInt(50000),
+		TimeInForce: "FOK",
+	})
+	require.Error(t, err)
+
+	placed := fake.Orders()
+	require.Len(t, placed, 2, "invalid combinations must not reach the exchange")
+	assert.Equal(t, "GTC", placed[0].Params.Get("timeInForce"))
+	assert.Empty(t, placed[1].Params.Get("timeInForce"))
+}
+
+func TestPlaceFuturesOrder_ForwardsStopOptions(t *testing.T) {
+	fake := testutil.NewFakeBinance(t)
+	fake.AddSymbol(testutil.BTCUSDT)
+
+	signer := auth.NewSigner("test-key", "test-secret")
+	client, err := NewClient(fake.URL(), signer, rest.NewClient(fake.URL(), signer), zerolog.Nop())
+	require.NoError(t, err)
+	client.isFutures = true
+
+	_, err = client.PlaceFuturesOrder(context.Background(), FuturesOrderRequest{
+		Symbol:       "BTCUSDT",
+		Side:         "SELL",
+		Type:         "STOP_MARKET",
+		Quantity:     decimal.RequireFromString("0.01"),
+		StopPrice:    decimal.NewFromInt(49000),
+		ReduceOnly:   true,
+		WorkingType:  WorkingTypeMarkPrice,
+		PriceProtect: true,
+	})
+	require.NoError(t, err)
+
+	placed := fake.Orders()
+	require.Len(t, placed, 1)
+	assert.Equal(t, "MARK_PRICE", placed[0].Params.Get("workingType"))
+	assert.Equal(t, "true", placed[0].Params.Get("priceProtect"))
+}
+
+func TestPlaceFuturesOrder_PositionSide(t *testing.T) {
+	limit := FuturesOrderRequest{
+		Symbol:   "BTCUSDT",
+		Side:     "BUY",
+		Type:     "LIMIT",
+		Quantity: decimal.RequireFromString("0.01"),
+		Price:    decimal.NewFromInt(50000),
+	}
+
+	tests := []struct {
+		name         string
+		hedge        bool
+		positionSide string
+		wantErr      string
+	}{
+		{name: "one-way default", hedge: false},
+		{name: "one-way explicit both", hedge: false, positionSide: PositionSideBoth},
+		{name: "one-way rejects long", hedge: false, positionSide: PositionSideLong, wantErr: "positionSide LONG requires hedge mode"},
+		{name: "hedge long", hedge: true, positionSide: PositionSideLong},
+		{name: "hedge short", hedge: true, positionSide: PositionSideShort},
+		{name: "hedge requires side", hedge: true, wantErr: "positionSide LONG or SHORT is required in hedge mode"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fake := testutil.NewFakeBinance(t)
+			fake.AddSymbol(testutil.BTCUSDT)
+			fake.SetHedgeMode(tt.hedge)
+
+			signer := auth.NewSigner("test-key", "test-secret")
+			client, err := NewClient(fake.URL(), signer, rest.NewClient(fake.URL(), signer), zerolog.Nop())
+			require.NoError(t, err)
+			client.isFutures = true
+
+			order := limit
+			order.PositionSide = tt.positionSide
+			_, err = client.PlaceFuturesOrder(context.Background(), order)
+			if tt.wantErr != "" {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tt.wantErr)
+				assert.Empty(t, fake.Orders())
+				return
+			}
+
+			require.NoError(t, err)
+			placed := fake.Orders()
+			require.Len(t, placed, 1)
+			assert.Equal(t, tt.positionSide, placed[0].Params.Get("positionSide"))
+		})
+	}
+}
+
+func TestChangePositionMode(t *testing.T) {
+	fake := testutil.NewFakeBinance(t)
+	signer := auth.NewSigner("test-key", "test-secret")
+	client, err := NewClient(fake.URL(), signer, rest.NewClient(fake.URL(), signer), zerolog.Nop())
+	require.NoError(t, err)
+	ctx := context.Background()
+
+	hedge, err := client.GetPositionMode(ctx)
+	require.NoError(t, err)
+	assert.False(t, hedge)
+
+	require.NoError(t, client.ChangePositionMode(ctx, true))
+	hedge, err = client.GetPositionMode(ctx)
+	require.NoError(t, err)
+	assert.True(t, hedge)
+
+	// Asking for the current mode is a no-op on Binance's side
+	require.NoError(t, client.ChangePositionMode(ctx, true))
+
+	// One lookup plus two changes; later reads are served from cache
+	assert.Equal(t, 3, fake.RequestCount("/fapi/v1/positionSide/dual"))
+}
+
+func TestChangePositionMode_KeepsCacheUntilAccepted(t *testing.T) {
+	fake := testutil.NewFakeBinance(t)
+	signer := auth.NewSigner("test-key", "test-secret")
+	client, err := NewClient(fake.URL(), signer, rest.NewClient(fake.URL(), signer, rest.WithMaxRetries(0)), zerolog.Nop())
+	require.NoError(t, err)
+	ctx := context.Background()
+
+	_, err = client.GetPositionMode(ctx)
+	require.NoError(t, err)
+
+	// Reads are served from cache while a change is in flight
+	fake.SetLatency(200 * time.Millisecond)
+	changed := make(chan error, 1)
+	go func() { changed <- client.ChangePositionMode(ctx, true) }()
+	time.Sleep(20 * time.Millisecond)
+
+	start := time.Now()
+	hedge, err := client.GetPositionMode(ctx)
+	require.NoError(t, err)
+	assert.False(t, hedge)
+	assert.Less(t, time.Since(start), 100*time.Millisecond)
+	require.NoError(t, <-changed)
+
+	// A rejected change leaves the cached mode alone
+	fake.SetLatency(0)
+	fake.SimulateRateLimit(1)
+	require.Error(t, client.ChangePositionMode(ctx, false))
+	hedge, err = client.GetPositionMode(ctx)
+	require.NoError(t, err)
+	assert.True(t, hedge)
+	assert.Equal(t, 3, fake.RequestCount("/fapi/v1/positionSide/dual"))
+}
+
+func TestGetPositionMode_SharesConcurrentLookups(t *testing.T) {
+	fake := testutil.NewFakeBinance(t)
+	fake.SetLatency(50 * time.Millisecond)
+	signer := auth.NewSigner("test-key", "test-secret")
+	client, err := NewClient(fake.URL(), signer, rest.NewClient(fake.URL(), signer), zerolog.Nop())
+	require.NoError(t, err)
+
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			hedge, err := client.GetPositionMode(context.Background())
+			assert.NoError(t, err)
+			assert.False(t, hedge)
+		}()
+	}
+	wg.Wait()
+
+	assert.Equal(t, 1, fake.RequestCount("/fapi/v1/positionSide/dual"))
+}
+
+func TestPlaceFuturesOrder_PositionSideMismatchDropsCachedMode(t *testing.T) {
+	fake := testutil.NewFakeBinance(t)
+	fake.AddSymbol(testutil.BTCUSDT)
+	signer := auth.NewSigner("test-key", "test-secret")
+	client, err := NewClient(fake.URL(), signer, rest.NewClient(fake.URL(), signer, rest.WithMaxRetries(0)), zerolog.Nop())
+	require.NoError(t, err)
+	client.isFutures = true
+	ctx := context.Background()
+
+	hedge, err := client.GetPositionMode(ctx)
+	require.NoError(t, err)
+	require.False(t, hedge)
+
+	// The mode is switched outside the router; Binance rejects the one-way order
+	fake.SetHedgeMode(true)
+	fake.InjectError(-4061, "Order's position side does not match user's setting.")
+	_, err = client.PlaceFuturesOrder(ctx, FuturesOrderRequest{
+		Symbol:   "BTCUSDT",
+		Side:     "BUY",
+		Type:     "LIMIT",
+		Quantity: decimal.RequireFromString("0.01"),
+		Price:    decimal.NewFromInt(50000),
+	})
+	require.Error(t, err)
+
+	hedge, err = client.GetPositionMode(ctx)
+	require.NoError(t, err)
+	assert.True(t, hedge, "mode looked up again after the rejection")
+	assert.Equal(t, 2, fake.RequestCount("/fapi/v1/positionSide/dual"))
+}
+
 // TestAccountInfoCaching tests the caching behavior
 func TestAccountInfoCaching(t *testing.T) {
 	// Create a client with short cache TTL
@@ -196,3 +642,417 @@ func TestAccountInfoCaching(t *testing.T) {
 	_, err = client.GetAccountInfo(ctx)
 	assert.Error(t, err) // Because our mock restClient won't work
 }
+
+func TestAccountCacheTTL(t *testing.T) {
+	ctx := context.Background()
+	newClient := func(t *testing.T, opts ...ClientOption) (*Client, *testutil.FakeBinance) {
+		fake := testutil.NewFakeBinance(t)
+		signer := auth.NewSigner("test-key", "test-secret")
+		client, err := NewClient(fake.URL(), signer, rest.NewClient(fake.URL(), signer), zerolog.Nop(), opts...)
+		require.NoError(t, err)
+		return client, fake
+	}
+
+	t.Run("defaults to thirty seconds", func(t *testing.T) {
+		client, _ := newClient(t)
+		assert.Equal(t, DefaultAccountCacheTTL, client.accountCacheTTL)
+	})
+
+	t.Run("zero TTL always re-fetches", func(t *testing.T) {
+		client, fake := newClient(t, WithAccountCacheTTL(0))
+
+		for i := 1; i <= 3; i++ {
+			_, err := client.GetAccountInfo(ctx)
+			require.NoError(t, err)
+			_, err = client.GetFuturesAccountInfo(ctx)
+			require.NoError(t, err)
+			assert.Equal(t, i, fake.RequestCount("/api/v3/account"))
+			assert.Equal(t, i, fake.RequestCount("/fapi/v2/account"))
+		}
+		assert.Nil(t, client.cachedAccount(), "balance checks never see a snapshot")
+	})
+
+	t.Run("custom TTL is honoured", func(t *testing.T) {
+		client, fake := newClient(t, WithAccountCacheTTL(100*time.Millisecond))
+
+		_, err := client.GetAccountInfo(ctx)
+		require.NoError(t, err)
+		_, err = client.GetAccountInfo(ctx)
+		require.NoError(t, err)
+		assert.Equal(t, 1, fake.RequestCount("/api/v3/account"))
+
+		time.Sleep(150 * time.Millisecond)
+		_, err = client.GetAccountInfo(ctx)
+		require.NoError(t, err)
+		assert.Equal(t, 2, fake.RequestCount("/api/v3/account"))
+	})
+}
+
+func TestInvalidateAccountCache(t *testing.T) {
+	fake := testutil.NewFakeBinance(t)
+	fake.SetBalance("USDT", decimal.NewFromInt(100))
+
+	signer := auth.NewSigner("test-key", "test-secret")
+	client, err := NewClient(fake.URL(), signer, rest.NewClient(fake.URL(), signer), zerolog.Nop())
+	require.NoError(t, err)
+	ctx := context.Background()
+
+	_, err = client.GetAccountInfo(ctx)
+	require.NoError(t, err)
+	_, err = client.GetFuturesAccountInfo(ctx)
+	require.NoError(t, err)
+
+	// Both snapshots are served from the cache within the TTL
+	fake.SetBalance("USDT", decimal.NewFromInt(40))
+	_, err = client.GetAccountInfo(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, 1, fake.RequestCount("/api/v3/account"))
+
+	client.InvalidateAccountCache()
+
+	account, err := client.GetAccountInfo(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, 2, fake.RequestCount("/api/v3/account"))
+	require.Len(t, account.Balances, 1)
+	assert.Equal(t, "40", account.Balances[0].Free.String())
+
+	futures, err := client.GetFuturesAccountInfo(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, 2, fake.RequestCount("/fapi/v2/account"))
+	assert.Equal(t, "40", futures.AvailableBalance.String())
+}
+
+func TestCheckSpotBalance(t *testing.T) {
+	newClient := func(balances []Balance) *Client {
+		return &Client{
+			logger:          zerolog.Nop(),
+			accountCacheTTL: time.Minute,
+			accountCache: &AccountResponse{
+				Balances: balances,
+			},
+			accountCacheTime: time.Now(),
+			exchangeInfoCache: &ExchangeInfoCache{
+				cache: map[string]*SymbolInfo{
+					"SHIBUSDT": {Symbol: "SHIBUSDT", BaseAsset: "SHIB", QuoteAsset: "USDT"},
+				},
+			},
+		}
+	}
+
+	largeBuy := SpotOrderRequest{
+		Symbol:   "SHIBUSDT",
+		Side:     "BUY",
+		Type:     "LIMIT",
+		Quantity: decimal.NewFromInt(5000000),
+		Price:    decimal.RequireFromString("0.00001"),
+	}
+
+	t.Run("large quantity covered by quote balance", func(t *testing.T) {
+		client := newClient([]Balance{{Asset: "USDT", Free: decimal.NewFromInt(100)}})
+		assert.NoError(t, client.checkSpotBalance(largeBuy))
+	})
+
+	t.Run("rejects buy exceeding quote balance", func(t *testing.T) {
+		client := newClient([]Balance{{Asset: "USDT", Free: decimal.NewFromInt(10)}})
+		err := client.checkSpotBalance(largeBuy)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "insufficient USDT balance")
+	})
+
+	t.Run("rejects sell exceeding base balance", func(t *testing.T) {
+		client := newClient([]Balance{{Asset: "SHIB", Free: decimal.NewFromInt(1000)}})
+		sell := largeBuy
+		sell.Side = "SELL"
+		err := client.checkSpotBalance(sell)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "insufficient SHIB balance")
+	})
+
+	t.Run("skips check without account data", func(t *testing.T) {
+		client := newClient(nil)
+		client.accountCache = nil
+		assert.NoError(t, client.checkSpotBalance(largeBuy))
+	})
+}
+
+func TestCheckFuturesMargin(t *testing.T) {
+	client := &Client{
+		logger:          zerolog.Nop(),
+		accountCacheTTL: time.Minute,
+		futuresAccountCache: &rest.FuturesAccountResponse{
+			AvailableBalance: decimal.NewFromInt(100),
+			Positions: []rest.FuturesPosition{
+				{Symbol: "DOGEUSDT", Leverage: "10"},
+			},
+		},
+		futuresAccountCacheTime: time.Now(),
+	}
+
+	order := FuturesOrderRequest{
+		Symbol:   "DOGEUSDT",
+		Side:     "BUY",
+		Type:     "LIMIT",
+		Quantity: decimal.NewFromInt(200000),
+		Price:    decimal.RequireFromString("0.004"),
+	}
+
+	// 200000 * 0.004 / 10 = 80 USDT margin
+	assert.NoError(t, client.validateFuturesOrder(order))
+	assert.NoError(t, client.checkFuturesMargin(order))
+
+	order.Quantity = decimal.NewFromInt(300000)
+	err := client.checkFuturesMargin(order)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "insufficient margin")
+
+	order.ReduceOnly = true
+	assert.NoError(t, client.checkFuturesMargin(order))
+}
+
+func TestCheckFuturesMargin_MalformedLeverage(t *testing.T) {
+	newClient := func(strict bool) *Client {
+		return &Client{
+			logger:          zerolog.Nop(),
+			restClient:      rest.NewClient("http://localhost", nil, rest.WithStrictDecimals(strict)),
+			accountCacheTTL: time.Minute,
+			futuresAccountCache: &rest.FuturesAccountResponse{
+				AvailableBalance: decimal.NewFromInt(100),
+				Positions: []rest.FuturesPosition{
+					{Symbol: "DOGEUSDT", Leverage: "ten"},
+				},
+			},
+			futuresAccountCacheTime: time.Now(),
+		}
+	}
+	order := FuturesOrderRequest{
+		Symbol:   "DOGEUSDT",
+		Side:     "BUY",
+		Type:     "LIMIT",
+		Quantity: decimal.NewFromInt(300000),
+		Price:    decimal.RequireFromString("0.004"),
+	}
+
+	// Without a usable leverage the check defers to the exchange
+	assert.NoError(t, newClient(false).checkFuturesMargin(order))
+
+	err := newClient(true).checkFuturesMargin(order)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), `invalid leverage "ten" for DOGEUSDT`)
+}
+
+type stubOrderPlacer struct {
+	received *rest.OrderRequest
+}
+
+func (p *stubOrderPlacer) PlaceOrder(ctx context.Context, req *rest.OrderRequest) (*rest.OrderResponse, error) {
+	p.received = req
+	return &rest.OrderResponse{Symbol: req.Symbol, OrderID: 42, Status: "NEW"}, nil
+}
+
+func TestPlaceSpotOrder_UsesOrderPlacer(t *testing.T) {
+	placer := &stubOrderPlacer{}
+	client := &Client{
+		logger:     zerolog.Nop(),
+		restClient: &rest.Client{}, // Would fail if used
+	}
+	client.SetOrderPlacer(placer)
+
+	resp, err := client.PlaceSpotOrder(context.Background(), SpotOrderRequest{
+		Symbol:   "BTCUSDT",
+		Side:     "BUY",
+		Type:     "MARKET",
+		Quantity: decimal.RequireFromString("0.01"),
+	})
+	require.NoError(t, err)
+	assert.Equal(t, int64(42), resp.OrderID)
+	require.NotNil(t, placer.received)
+	assert.Equal(t, "0.01", placer.received.Quantity.String())
+}
+
+func TestClient_VerifyAccess(t *testing.T) {
+	tests := []struct {
+		name      string
+		isFutures bool
+		readOnly  bool
+		trade     bool
+		wantErr   error
+	}{
+		{name: "spot trading key", trade: true},
+		{name: "spot read-only key for data", readOnly: true, trade: false},
+		{name: "spot read-only key for trading", readOnly: true, trade: true, wantErr: ErrTradingNotPermitted},
+		{name: "futures trading key", isFutures: true, trade: true},
+		{name: "futures read-only key for trading", isFutures: true, readOnly: true, trade: true, wantErr: ErrTradingNotPermitted},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fake := testutil.NewFakeBinance(t)
+			fake.SetReadOnly(tt.readOnly)
+
+			venue := config.VenueSpot
+			if tt.isFutures {
+				venue = config.VenueFutures
+			}
+			client, err := NewProfileClient(&config.BinanceConfig{Timeout: 5 * time.Second}, config.APIKeyProfile{
+				Name:      "profile",
+				Venue:     venue,
+				APIKey:    "profile-key",
+				SecretKey: "profile-secret",
+			}, fake.URL(), zerolog.Nop())
+			require.NoError(t, err)
+			assert.Equal(t, tt.isFutures, client.IsFutures())
+
+			err = client.VerifyAccess(context.Background(), tt.trade)
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+			} else {
+				assert.NoError(t, err)
+			}
+
+			path := "/api/v3/account"
+			if tt.isFutures {
+				path = "/fapi/v2/account"
+			}
+			assert.Equal(t, 1, fake.RequestCount(path))
+		})
+	}
+
+	t.Run("rejected key", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusUnauthorized)
+			w.Write([]byte(`{"code":-2015,"msg":"Invalid API-key, IP, or permissions for action."}`))
+		}))
+		defer server.Close()
+
+		client, err := NewProfileClient(&config.BinanceConfig{Timeout: 5 * time.Second}, config.APIKeyProfile{
+			Name:      "profile",
+			Venue:     config.VenueSpot,
+			APIKey:    "bad-key",
+			SecretKey: "bad-secret",
+		}, server.URL, zerolog.Nop())
+		require.NoError(t, err)
+
+		err = client.VerifyAccess(context.Background(), false)
+		var binanceErr *rest.BinanceError
+		require.True(t, errors.As(err, &binanceErr))
+		assert.Equal(t, -2015, binanceErr.Code)
+	})
+}
+
+func TestClient_GetAllOpenOrders(t *testing.T) {
+	ctx := context.Background()
+
+	// newOpenOrdersClient rests one LIMIT order on each of BTCUSDT and ETHUSDT
+	newOpenOrdersClient := func(t *testing.T, isFutures bool) (*Client, *testutil.FakeBinance) {
+		t.Helper()
+		fake := testutil.NewFakeBinance(t)
+		eth := testutil.BTCUSDT
+		eth.Symbol = "ETHUSDT"
+		fake.AddSymbol(testutil.BTCUSDT)
+		fake.AddSymbol(eth)
+
+		signer := auth.NewSigner("test-key", "test-secret")
+		client, err := NewClient(fake.URL(), signer, rest.NewClient(fake.URL(), signer, rest.WithMaxRetries(0)), zerolog.Nop(),
+			WithOpenOrdersBackoff(time.Millisecond))
+		require.NoError(t, err)
+		client.isFutures = isFutures
+
+		for _, symbol := range []string{"BTCUSDT", "ETHUSDT"} {
+			if isFutures {
+				_, err = client.PlaceFuturesOrder(ctx, FuturesOrderRequest{
+					Symbol:   symbol,
+					Side:     "BUY",
+					Type:     "LIMIT",
+					Quantity: decimal.RequireFromString("0.001"),
+					Price:    decimal.NewFromInt(50000),
+				})
+			} else {
+				_, err = client.PlaceSpotOrder(ctx, SpotOrderRequest{
+					Symbol:   symbol,
+					Side:     "BUY",
+					Type:     "LIMIT",
+					Quantity: decimal.RequireFromString("0.001"),
+					Price:    decimal.NewFromInt(50000),
+				})
+			}
+			require.NoError(t, err)
+		}
+		return client, fake
+	}
+
+	t.Run("broad call lists every symbol", func(t *testing.T) {
+		client, fake := newOpenOrdersClient(t, false)
+
+		orders, err := client.GetAllOpenOrders(ctx)
+		require.NoError(t, err)
+		require.Len(t, orders, 2)
+		assert.ElementsMatch(t, []string{"BTCUSDT", "ETHUSDT"}, []string{orders[0].Symbol, orders[1].Symbol})
+		assert.Equal(t, 1, fake.RequestCount("/api/v3/openOrders"))
+	})
+
+	t.Run("futures broad call", func(t *testing.T) {
+		client, fake := newOpenOrdersClient(t, true)
+
+		orders, err := client.GetAllOpenOrders(ctx)
+		require.NoError(t, err)
+		assert.Len(t, orders, 2)
+		assert.Equal(t, 1, fake.RequestCount("/fapi/v1/openOrders"))
+		assert.Equal(t, 0, fake.RequestCount("/api/v3/openOrders"))
+	})
+
+	t.Run("symbols are filtered from the broad call", func(t *testing.T) {
+		client, fake := newOpenOrdersClient(t, false)
+
+		orders, err := client.GetOpenOrdersForSymbols(ctx, []string{"ethusdt"})
+		require.NoError(t, err)
+		require.Len(t, orders, 1)
+		assert.Equal(t, "ETHUSDT", orders[0].Symbol)
+		assert.Equal(t, 1, fake.RequestCount("/api/v3/openOrders"))
+	})
+
+	t.Run("falls back to per-symbol queries when the broad call is denied", func(t *testing.T) {
+		client, fake := newOpenOrdersClient(t, false)
+		fake.DenyAllOpenOrders(true)
+
+		_, err := client.GetAllOpenOrders(ctx)
+		require.Error(t, err)
+
+		orders, err := client.GetOpenOrdersForSymbols(ctx, []string{"BTCUSDT", "ETHUSDT"})
+		require.NoError(t, err)
+		require.Len(t, orders, 2)
+		assert.Equal(t, "BTCUSDT", orders[0].Symbol)
+		assert.Equal(t, "ETHUSDT", orders[1].Symbol)
+		// Two denied broad calls, then one query per symbol
+		assert.Equal(t, 4, fake.RequestCount("/api/v3/openOrders"))
+	})
+
+	t.Run("per-symbol query backs off through rate limits", func(t *testing.T) {
+		client, fake := newOpenOrdersClient(t, false)
+		fake.SimulateRateLimit(2)
+
+		orders, err := client.openOrdersWithBackoff(ctx, "BTCUSDT")
+		require.NoError(t, err)
+		assert.Len(t, orders, 1)
+		assert.Equal(t, 3, fake.RequestCount("/api/v3/openOrders"))
+	})
+
+	t.Run("per-symbol query gives up after repeated rate limits", func(t *testing.T) {
+		client, fake := newOpenOrdersClient(t, false)
+		fake.SimulateRateLimit(10)
+
+		_, err := client.openOrdersWithBackoff(ctx, "BTCUSDT")
+		var binanceErr *rest.BinanceError
+		require.True(t, errors.As(err, &binanceErr))
+		assert.True(t, binanceErr.IsRateLimit())
+		assert.Equal(t, maxOpenOrdersAttempts, fake.RequestCount("/api/v3/openOrders"))
+	})
+
+	t.Run("other broad call failures are returned", func(t *testing.T) {
+		client, fake := newOpenOrdersClient(t, false)
+		fake.SimulateRateLimit(1)
+
+		_, err := client.GetOpenOrdersForSymbols(ctx, []string{"BTCUSDT"})
+		require.Error(t, err)
+		assert.Equal(t, 1, fake.RequestCount("/api/v3/openOrders"))
+	})
+}
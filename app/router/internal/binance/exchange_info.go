@@ -2,24 +2,34 @@ package binance
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"sync"
 	"time"
 
 	"github.com/rs/zerolog"
 	"github.com/shopspring/decimal"
+	"golang.org/x/sync/singleflight"
 	"router/internal/rest"
 )
 
+// ErrSymbolNotFound is returned when a venue does not list a symbol or the
+// symbol is not currently trading there
+var ErrSymbolNotFound = errors.New("symbol not found")
+
 // ExchangeInfoCache caches exchange info for symbols
 type ExchangeInfoCache struct {
 	spotClient    *rest.Client
 	futuresClient *rest.Client
 	cache         map[string]*SymbolInfo
+	fetchedAt     map[string]time.Time
 	cacheMu       sync.RWMutex
 	cacheTime     time.Time
 	cacheTTL      time.Duration
 	logger        zerolog.Logger
+
+	// Dedupes concurrent fetches, which run without holding cacheMu
+	fetches singleflight.Group
 }
 
 // SymbolInfo contains trading rules for a symbol
@@ -38,6 +48,10 @@ type SymbolInfo struct {
 	MaxQuantity         decimal.Decimal
 	StepSize            decimal.Decimal
 	MinNotional         decimal.Decimal
+	MaxNotional         decimal.Decimal
+	MultiplierUp        decimal.Decimal
+	MultiplierDown      decimal.Decimal
+	MaxNumOrders        int
 	IsFutures           bool
 }
 
@@ -59,35 +73,39 @@ func NewExchangeInfoCache(spotClient, futuresClient *rest.Client, cacheTTL time.
 		spotClient:    spotClient,
 		futuresClient: futuresClient,
 		cache:         make(map[string]*SymbolInfo),
+		fetchedAt:     make(map[string]time.Time),
 		cacheTTL:      cacheTTL,
 		logger:        logger,
 	}
 }
 
-// GetSymbolInfo retrieves symbol info with caching
+// GetSymbolInfo retrieves symbol info with caching. Spot symbols are fetched
+// individually on first use; futures fall back to a full refresh because the
+// futures exchangeInfo endpoint has no symbol filter.
 func (e *ExchangeInfoCache) GetSymbolInfo(ctx context.Context, symbol string, isFutures bool) (*SymbolInfo, error) {
 	e.cacheMu.RLock()
-	if time.Since(e.cacheTime) < e.cacheTTL {
-		info, exists := e.cache[symbol]
-		e.cacheMu.RUnlock()
-		if exists && info.IsFutures == isFutures {
-			return info, nil
-		}
-	} else {
-		e.cacheMu.RUnlock()
+	info, exists := e.cache[symbol]
+	fresh := exists && e.isFresh(symbol)
+	e.cacheMu.RUnlock()
+	if fresh && info.IsFutures == isFutures {
+		return info, nil
+	}
+
+	if !isFutures && e.spotClient != nil {
+		return e.fetchSymbol(ctx, symbol)
 	}
 
 	// Cache miss or expired, refresh
-	if err := e.refreshCache(ctx); err != nil {
+	if err := e.refreshCache(ctx, false); err != nil {
 		return nil, fmt.Errorf("failed to refresh exchange info: %w", err)
 	}
 
 	e.cacheMu.RLock()
-	info, exists := e.cache[symbol]
+	info, exists = e.cache[symbol]
 	e.cacheMu.RUnlock()
 
 	if !exists {
-		return nil, fmt.Errorf("symbol %s not found", symbol)
+		return nil, fmt.Errorf("%w: %s", ErrSymbolNotFound, symbol)
 	}
 
 	if info.IsFutures != isFutures {
@@ -98,54 +116,184 @@ func (e *ExchangeInfoCache) GetSymbolInfo(ctx context.Context, symbol string, is
 	return info, nil
 }
 
+// fetchSymbol loads rules for a single spot symbol into the cache.
+// Concurrent fetches of a symbol share one request.
+func (e *ExchangeInfoCache) fetchSymbol(ctx context.Context, symbol string) (*SymbolInfo, error) {
+	result, err, _ := e.fetches.Do("symbol:"+symbol, func() (interface{}, error) {
+		// Check if another goroutine already fetched it
+		e.cacheMu.RLock()
+		info, exists := e.cache[symbol]
+		fresh := exists && !info.IsFutures && e.isFresh(symbol)
+		e.cacheMu.RUnlock()
+		if fresh {
+			return info, nil
+		}
+
+		e.logger.Debug().Str("symbol", symbol).Msg("Fetching symbol exchange info")
+
+		restSymbol, err := e.spotClient.GetExchangeInfoForSymbol(ctx, symbol)
+		if err != nil {
+			var apiErr *rest.BinanceError
+			if errors.As(err, &apiErr) && apiErr.Code == -1121 {
+				return nil, fmt.Errorf("%w: %s", ErrSymbolNotFound, symbol)
+			}
+			return nil, fmt.Errorf("failed to get exchange info for %s: %w", symbol, err)
+		}
+
+		if restSymbol.Status != "TRADING" {
+			return nil, fmt.Errorf("%w: %s is not trading (status %s)", ErrSymbolNotFound, symbol, restSymbol.Status)
+		}
+
+		info = newSymbolInfo(*restSymbol, false)
+
+		e.cacheMu.Lock()
+		defer e.cacheMu.Unlock()
+		if e.cache == nil {
+			e.cache = make(map[string]*SymbolInfo)
+		}
+		if e.fetchedAt == nil {
+			e.fetchedAt = make(map[string]time.Time)
+		}
+		e.cache[symbol] = info
+		e.fetchedAt[symbol] = time.Now()
+
+		return info, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return result.(*SymbolInfo), nil
+}
+
+// cachedSymbolInfo returns symbol info already in the cache without fetching
+func (e *ExchangeInfoCache) cachedSymbolInfo(symbol string) *SymbolInfo {
+	e.cacheMu.RLock()
+	defer e.cacheMu.RUnlock()
+	return e.cache[symbol]
+}
+
+// LastRefresh returns when rules were last loaded, by full refresh or by
+// fetching a single symbol
+func (e *ExchangeInfoCache) LastRefresh() time.Time {
+	e.cacheMu.RLock()
+	defer e.cacheMu.RUnlock()
+
+	last := e.cacheTime
+	for _, fetchedAt := range e.fetchedAt {
+		if fetchedAt.After(last) {
+			last = fetchedAt
+		}
+	}
+	return last
+}
+
+// isFresh reports whether a symbol was loaded within the TTL, either by a full
+// refresh or an individual fetch. Callers must hold cacheMu.
+func (e *ExchangeInfoCache) isFresh(symbol string) bool {
+	if time.Since(e.cacheTime) < e.cacheTTL {
+		return true
+	}
+	fetchedAt, ok := e.fetchedAt[symbol]
+	return ok && time.Since(fetchedAt) < e.cacheTTL
+}
+
 // RoundPrice rounds price according to symbol filters
 func (e *ExchangeInfoCache) RoundPrice(ctx context.Context, symbol string, price decimal.Decimal, isFutures bool) (decimal.Decimal, error) {
 	info, err := e.GetSymbolInfo(ctx, symbol, isFutures)
 	if err != nil {
 		return decimal.Zero, err
 	}
+	return info.roundPrice(price), nil
+}
+
+// RoundQuantity rounds quantity according to symbol filters
+func (e *ExchangeInfoCache) RoundQuantity(ctx context.Context, symbol string, quantity decimal.Decimal, isFutures bool) (decimal.Decimal, error) {
+	info, err := e.GetSymbolInfo(ctx, symbol, isFutures)
+	if err != nil {
+		return decimal.Zero, err
+	}
+	return info.roundQuantity(quantity), nil
+}
+
+// RoundQuoteQuantity rounds a quote-asset amount to the symbol's quote asset
+// precision
+func (e *ExchangeInfoCache) RoundQuoteQuantity(ctx context.Context, symbol string, value decimal.Decimal, isFutures bool) (decimal.Decimal, error) {
+	info, err := e.GetSymbolInfo(ctx, symbol, isFutures)
+	if err != nil {
+		return decimal.Zero, err
+	}
+	return info.roundQuoteQuantity(value), nil
+}
+
+// RoundOrderValues rounds prices and quantities like RoundPrice and
+// RoundQuantity, looking the symbol's rules up once for the whole batch. The
+// inputs are left untouched.
+func (e *ExchangeInfoCache) RoundOrderValues(ctx context.Context, symbol string, prices, quantities []decimal.Decimal, isFutures bool) ([]decimal.Decimal, []decimal.Decimal, error) {
+	info, err := e.GetSymbolInfo(ctx, symbol, isFutures)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	roundedPrices := make([]decimal.Decimal, len(prices))
+	for i, price := range prices {
+		roundedPrices[i] = info.roundPrice(price)
+	}
+	roundedQuantities := make([]decimal.Decimal, len(quantities))
+	for i, quantity := range quantities {
+		roundedQuantities[i] = info.roundQuantity(quantity)
+	}
+
+	return roundedPrices, roundedQuantities, nil
+}
 
-	// Check price bounds
-	if price.LessThan(info.MinPrice) {
-		return info.MinPrice, nil
+// roundPrice clamps price to the symbol's bounds and rounds it to the tick size
+func (info *SymbolInfo) roundPrice(price decimal.Decimal) decimal.Decimal {
+	// Check price bounds (zero means the bound is disabled)
+	if info.MinPrice.IsPositive() && price.LessThan(info.MinPrice) {
+		return info.MinPrice
 	}
-	if price.GreaterThan(info.MaxPrice) {
-		return info.MaxPrice, nil
+	if info.MaxPrice.IsPositive() && price.GreaterThan(info.MaxPrice) {
+		return info.MaxPrice
 	}
 
 	// Round to tick size
 	if info.TickSize.IsPositive() {
 		ticks := price.Div(info.TickSize).Round(0)
-		return ticks.Mul(info.TickSize), nil
+		return ticks.Mul(info.TickSize)
 	}
 
 	// Fallback to precision rounding
-	return price.Round(int32(info.PricePrecision)), nil
+	return price.Round(int32(info.PricePrecision))
 }
 
-// RoundQuantity rounds quantity according to symbol filters
-func (e *ExchangeInfoCache) RoundQuantity(ctx context.Context, symbol string, quantity decimal.Decimal, isFutures bool) (decimal.Decimal, error) {
-	info, err := e.GetSymbolInfo(ctx, symbol, isFutures)
-	if err != nil {
-		return decimal.Zero, err
-	}
-
-	// Check quantity bounds
-	if quantity.LessThan(info.MinQuantity) {
-		return info.MinQuantity, nil
+// roundQuantity clamps quantity to the symbol's bounds and rounds it down to
+// the step size
+func (info *SymbolInfo) roundQuantity(quantity decimal.Decimal) decimal.Decimal {
+	// Check quantity bounds (zero means the bound is disabled)
+	if info.MinQuantity.IsPositive() && quantity.LessThan(info.MinQuantity) {
+		return info.MinQuantity
 	}
-	if quantity.GreaterThan(info.MaxQuantity) {
-		return info.MaxQuantity, nil
+	if info.MaxQuantity.IsPositive() && quantity.GreaterThan(info.MaxQuantity) {
+		return info.MaxQuantity
 	}
 
 	// Round to step size
 	if info.StepSize.IsPositive() {
 		steps := quantity.Div(info.StepSize).Floor()
-		return steps.Mul(info.StepSize), nil
+		return steps.Mul(info.StepSize)
 	}
 
 	// Fallback to precision rounding
-	return quantity.Truncate(int32(info.QuantityPrecision)), nil
+	return quantity.Truncate(int32(info.QuantityPrecision))
+}
+
+// roundQuoteQuantity rounds a quote-asset amount to the quote asset precision.
+// Symbols without a known precision are left unrounded.
+func (info *SymbolInfo) roundQuoteQuantity(value decimal.Decimal) decimal.Decimal {
+	if info.QuoteAssetPrecision <= 0 {
+		return value
+	}
+	return value.Round(int32(info.QuoteAssetPrecision))
 }
 
 // ValidateNotional checks if order value meets minimum notional requirement
@@ -155,7 +303,7 @@ func (e *ExchangeInfoCache) ValidateNotional(ctx context.Context, symbol string,
 		return err
 	}
 
-	notional := price.Mul(quantity)
+	notional := info.roundQuoteQuantity(price.Mul(quantity))
 	if notional.LessThan(info.MinNotional) {
 		return fmt.Errorf("order notional %s is below minimum %s", notional, info.MinNotional)
 	}
@@ -163,13 +311,64 @@ func (e *ExchangeInfoCache) ValidateNotional(ctx context.Context, symbol string,
 	return nil
 }
 
-// refreshCache updates the cache with latest exchange info
-func (e *ExchangeInfoCache) refreshCache(ctx context.Context) error {
-	e.cacheMu.Lock()
-	defer e.cacheMu.Unlock()
+// ValidatePercentPrice checks that each price lies within the symbol's
+// PERCENT_PRICE bounds around reference. Symbols without the filter pass.
+func (e *ExchangeInfoCache) ValidatePercentPrice(ctx context.Context, symbol string, reference decimal.Decimal, prices []decimal.Decimal, isFutures bool) error {
+	info, err := e.GetSymbolInfo(ctx, symbol, isFutures)
+	if err != nil {
+		return err
+	}
+	return info.validatePercentPrice(reference, prices)
+}
+
+// hasPercentPrice reports whether the symbol carries a PERCENT_PRICE filter
+func (info *SymbolInfo) hasPercentPrice() bool {
+	return info.MultiplierUp.IsPositive()
+}
+
+// validatePercentPrice checks prices against the percent bounds around reference
+func (info *SymbolInfo) validatePercentPrice(reference decimal.Decimal, prices []decimal.Decimal) error {
+	if !info.hasPercentPrice() || !reference.IsPositive() {
+		return nil
+	}
+
+	low := reference.Mul(info.MultiplierDown)
+	high := reference.Mul(info.MultiplierUp)
+	for _, price := range prices {
+		if price.LessThan(low) || price.GreaterThan(high) {
+			return fmt.Errorf("price %s for %s is outside the percent price range [%s, %s]",
+				price, info.Symbol, low, high)
+		}
+	}
+
+	return nil
+}
+
+// Refresh fetches the latest exchange info regardless of cache age
+func (e *ExchangeInfoCache) Refresh(ctx context.Context) error {
+	return e.refreshCache(ctx, true)
+}
+
+// refreshCache updates the cache with latest exchange info. Concurrent
+// refreshes share one set of requests.
+func (e *ExchangeInfoCache) refreshCache(ctx context.Context, force bool) error {
+	key := "refresh"
+	if force {
+		key = "refresh:force"
+	}
+	_, err, _ := e.fetches.Do(key, func() (interface{}, error) {
+		return nil, e.loadAll(ctx, force)
+	})
+	return err
+}
 
+// loadAll fetches every symbol's rules and replaces the cache with them
+func (e *ExchangeInfoCache) loadAll(ctx context.Context, force bool) error {
 	// Check if another goroutine already refreshed
-	if time.Since(e.cacheTime) < e.cacheTTL {
+	e.cacheMu.RLock()
+	fresh := time.Since(e.cacheTime) < e.cacheTTL
+	e.cacheMu.RUnlock()
+	if !force && fresh {
 		return nil
 	}
 
@@ -180,7 +379,9 @@ func (e *ExchangeInfoCache) refreshCache(ctx context.Context) error {
 	// Fetch spot exchange info
 	if e.spotClient != nil {
 		e.logger.Debug().Msg("Fetching spot exchange info")
-		spotInfo, err := e.spotClient.GetExchangeInfo(ctx)
+		// Bypass the REST client's own cache; this cache already decided the
+		// rules are due for a refresh
+		spotInfo, err := e.spotClient.ForceRefreshExchangeInfo(ctx)
 		if err != nil {
 			e.logger.Error().Err(err).Msg("Failed to get spot exchange info")
 			return fmt.Errorf("failed to get spot exchange info: %w", err)
@@ -191,37 +392,33 @@ func (e *ExchangeInfoCache) refreshCache(ctx context.Context) error {
 				continue
 			}
 
-			info := &SymbolInfo{
-				Symbol:              symbol.Symbol,
-				BaseAsset:           symbol.BaseAsset,
-				QuoteAsset:          symbol.QuoteAsset,
-				BaseAssetPrecision:  symbol.BaseAssetPrecision,
-				QuoteAssetPrecision: symbol.QuoteAssetPrecision,
-				IsFutures:           false,
+			newCache[symbol.Symbol] = newSymbolInfo(symbol, false)
+		}
+	}
+
+	// Fetch futures exchange info
+	if e.futuresClient != nil {
+		e.logger.Debug().Msg("Fetching futures exchange info")
+		futuresInfo, err := e.futuresClient.GetFuturesExchangeInfo(ctx)
+		if err != nil {
+			e.logger.Error().Err(err).Msg("Failed to get futures exchange info")
+			return fmt.Errorf("failed to get futures exchange info: %w", err)
+		}
+
+		for _, symbol := range futuresInfo.Symbols {
+			if symbol.Status != "TRADING" {
+				continue
 			}
 
-			// TODO: Parse filters when Symbol type includes them
-			// For now, set default values
-			info.MinPrice = decimal.RequireFromString("0.01")
-			info.MaxPrice = decimal.RequireFromString("1000000")
-			info.TickSize = decimal.RequireFromString("0.01")
-			info.MinQuantity = decimal.RequireFromString("0.001")
-			info.MaxQuantity = decimal.RequireFromString("10000")
-			info.StepSize = decimal.RequireFromString("0.001")
-			info.MinNotional = decimal.RequireFromString("10")
-			info.PricePrecision = 2
-			info.QuantityPrecision = 3
-
-			newCache[symbol.Symbol] = info
+			newCache[symbol.Symbol] = newSymbolInfo(symbol, true)
 		}
 	}
 
-	// For futures, we would need to make a different API call
-	// Since the REST client doesn't support futures exchange info yet,
-	// we'll skip it for now
-
+	e.cacheMu.Lock()
 	e.cache = newCache
+	e.fetchedAt = make(map[string]time.Time)
 	e.cacheTime = time.Now()
+	e.cacheMu.Unlock()
 
 	e.logger.Info().
 		Int("symbol_count", len(newCache)).
@@ -230,43 +427,46 @@ func (e *ExchangeInfoCache) refreshCache(ctx context.Context) error {
 	return nil
 }
 
-// parseFilters extracts relevant filter values
-func (e *ExchangeInfoCache) parseFilters(info *SymbolInfo, filters []interface{}) {
-	for _, filterRaw := range filters {
-		filterMap, ok := filterRaw.(map[string]interface{})
-		if !ok {
-			continue
-		}
-
-		filterType, _ := filterMap["filterType"].(string)
-
-		switch filterType {
-		case "PRICE_FILTER":
-			info.MinPrice = parseDecimalField(filterMap, "minPrice")
-			info.MaxPrice = parseDecimalField(filterMap, "maxPrice")
-			info.TickSize = parseDecimalField(filterMap, "tickSize")
-			info.PricePrecision = calculatePrecision(info.TickSize)
-
-		case "LOT_SIZE":
-			info.MinQuantity = parseDecimalField(filterMap, "minQty")
-			info.MaxQuantity = parseDecimalField(filterMap, "maxQty")
-			info.StepSize = parseDecimalField(filterMap, "stepSize")
-			info.QuantityPrecision = calculatePrecision(info.StepSize)
-
-		case "MIN_NOTIONAL", "NOTIONAL":
-			info.MinNotional = parseDecimalField(filterMap, "minNotional")
+// newSymbolInfo builds rounding rules from a symbol's exchange filters
+func newSymbolInfo(symbol rest.Symbol, isFutures bool) *SymbolInfo {
+	info := &SymbolInfo{
+		Symbol:              symbol.Symbol,
+		BaseAsset:           symbol.BaseAsset,
+		QuoteAsset:          symbol.QuoteAsset,
+		BaseAssetPrecision:  symbol.BaseAssetPrecision,
+		QuoteAssetPrecision: symbol.QuoteAssetPrecision,
+		PricePrecision:      symbol.PricePrecision,
+		QuantityPrecision:   symbol.QuantityPrecision,
+		MinNotional:         symbol.MinNotional(),
+		MaxNotional:         symbol.MaxNotional(),
+		MaxNumOrders:        symbol.MaxNumOrders(),
+		IsFutures:           isFutures,
+	}
+	if info.QuoteAssetPrecision == 0 {
+		// Futures exchange info reports quotePrecision instead
+		info.QuoteAssetPrecision = symbol.QuotePrecision
+	}
+	info.MultiplierDown, info.MultiplierUp = symbol.PercentPriceBounds()
+
+	if f := symbol.Filter(rest.FilterTypePrice); f != nil {
+		info.MinPrice = f.MinPrice
+		info.MaxPrice = f.MaxPrice
+		info.TickSize = f.TickSize
+		if f.TickSize.IsPositive() {
+			info.PricePrecision = calculatePrecision(f.TickSize)
 		}
 	}
-}
 
-// parseDecimalField safely parses a decimal field from a map
-func parseDecimalField(m map[string]interface{}, field string) decimal.Decimal {
-	if val, ok := m[field].(string); ok {
-		if d, err := decimal.NewFromString(val); err == nil {
-			return d
+	if f := symbol.Filter(rest.FilterTypeLotSize); f != nil {
+		info.MinQuantity = f.MinQty
+		info.MaxQuantity = f.MaxQty
+		info.StepSize = f.StepSize
+		if f.StepSize.IsPositive() {
+			info.QuantityPrecision = calculatePrecision(f.StepSize)
 		}
 	}
-	return decimal.Zero
+
+	return info
 }
 
 // calculatePrecision calculates decimal precision from step size
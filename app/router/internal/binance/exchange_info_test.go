@@ -2,12 +2,18 @@ package binance
 
 import (
 	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
 	"testing"
 	"time"
 
+	"github.com/rs/zerolog"
 	"github.com/shopspring/decimal"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	"router/internal/auth"
+	"router/internal/rest"
 )
 
 func TestRoundPrice(t *testing.T) {
@@ -154,12 +160,73 @@ func TestRoundQuantity(t *testing.T) {
 	}
 }
 
+func TestRoundQuoteQuantity(t *testing.T) {
+	tests := []struct {
+		name      string
+		value     string
+		precision int
+		expected  string
+	}{
+		{
+			name:      "rounds to eight decimals",
+			value:     "12.3456789012",
+			precision: 8,
+			expected:  "12.3456789",
+		},
+		{
+			name:      "rounds half up",
+			value:     "9.999999995",
+			precision: 8,
+			expected:  "10",
+		},
+		{
+			name:      "rounds to two decimals",
+			value:     "100.126",
+			precision: 2,
+			expected:  "100.13",
+		},
+		{
+			name:      "already within precision",
+			value:     "25.5",
+			precision: 8,
+			expected:  "25.5",
+		},
+		{
+			name:      "unknown precision leaves value unrounded",
+			value:     "1.234567891234",
+			precision: 0,
+			expected:  "1.234567891234",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cache := &ExchangeInfoCache{
+				cache: map[string]*SymbolInfo{
+					"BTCUSDT": {
+						Symbol:              "BTCUSDT",
+						QuoteAssetPrecision: tt.precision,
+					},
+				},
+				cacheTime: time.Now(),
+				cacheTTL:  time.Hour,
+			}
+
+			rounded, err := cache.RoundQuoteQuantity(context.Background(), "BTCUSDT", decimal.RequireFromString(tt.value), false)
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, rounded.String())
+		})
+	}
+}
+
 func TestValidateNotional(t *testing.T) {
 	tests := []struct {
 		name        string
 		price       string
 		quantity    string
 		minNotional string
+		precision   int
 		expectError bool
 	}{
 		{
@@ -183,6 +250,21 @@ func TestValidateNotional(t *testing.T) {
 			minNotional: "10",
 			expectError: false,
 		},
+		{
+			name:        "rounds to quote precision before comparing",
+			price:       "0.5",
+			quantity:    "19.99999999",
+			minNotional: "10",
+			precision:   8,
+			expectError: false,
+		},
+		{
+			name:        "unrounded without quote precision",
+			price:       "0.5",
+			quantity:    "19.99999999",
+			minNotional: "10",
+			expectError: true,
+		},
 	}
 
 	for _, tt := range tests {
@@ -190,9 +272,10 @@ func TestValidateNotional(t *testing.T) {
 			cache := &ExchangeInfoCache{
 				cache: map[string]*SymbolInfo{
 					"BTCUSDT": {
-						Symbol:      "BTCUSDT",
-						MinNotional: decimal.RequireFromString(tt.minNotional),
-						IsFutures:   false,
+						Symbol:              "BTCUSDT",
+						QuoteAssetPrecision: tt.precision,
+						MinNotional:         decimal.RequireFromString(tt.minNotional),
+						IsFutures:           false,
 					},
 				},
 				cacheTime: time.Now(),
@@ -273,3 +356,401 @@ func TestSymbolValidation(t *testing.T) {
 	assert.Error(t, err)
 	assert.Contains(t, err.Error(), "not found")
 }
+
+func TestNewSymbolInfo(t *testing.T) {
+	symbol := rest.Symbol{
+		Symbol:     "ETHBTC",
+		Status:     "TRADING",
+		BaseAsset:  "ETH",
+		QuoteAsset: "BTC",
+		Filters: []rest.SymbolFilter{
+			{
+				FilterType: rest.FilterTypePrice,
+				MinPrice:   decimal.RequireFromString("0.000001"),
+				MaxPrice:   decimal.RequireFromString("100000"),
+				TickSize:   decimal.RequireFromString("0.000001"),
+			},
+			{
+				FilterType: rest.FilterTypeLotSize,
+				MinQty:     decimal.RequireFromString("0.001"),
+				MaxQty:     decimal.RequireFromString("100000"),
+				StepSize:   decimal.RequireFromString("0.001"),
+			},
+			{
+				FilterType:  rest.FilterTypeNotional,
+				MinNotional: decimal.RequireFromString("0.0001"),
+			},
+		},
+	}
+
+	info := newSymbolInfo(symbol, false)
+
+	assert.Equal(t, "0.000001", info.TickSize.String())
+	assert.Equal(t, "0.001", info.StepSize.String())
+	assert.Equal(t, "0.0001", info.MinNotional.String())
+	assert.Equal(t, 6, info.PricePrecision)
+	assert.Equal(t, 3, info.QuantityPrecision)
+
+	cache := &ExchangeInfoCache{
+		cache:     map[string]*SymbolInfo{"ETHBTC": info},
+		cacheTime: time.Now(),
+		cacheTTL:  time.Hour,
+	}
+
+	price, err := cache.RoundPrice(context.Background(), "ETHBTC", decimal.RequireFromString("0.05123449"), false)
+	require.NoError(t, err)
+	assert.Equal(t, "0.051234", price.String())
+
+	qty, err := cache.RoundQuantity(context.Background(), "ETHBTC", decimal.RequireFromString("1.23456"), false)
+	require.NoError(t, err)
+	assert.Equal(t, "1.234", qty.String())
+}
+
+func TestNewSymbolInfo_MissingFilters(t *testing.T) {
+	info := newSymbolInfo(rest.Symbol{Symbol: "XYZUSDT"}, false)
+
+	cache := &ExchangeInfoCache{
+		cache:     map[string]*SymbolInfo{"XYZUSDT": info},
+		cacheTime: time.Now(),
+		cacheTTL:  time.Hour,
+	}
+
+	// Without bounds the price is kept rather than clamped to zero
+	price, err := cache.RoundPrice(context.Background(), "XYZUSDT", decimal.RequireFromString("12.5"), false)
+	require.NoError(t, err)
+	assert.True(t, price.IsPositive())
+}
+
+// newExchangeInfoServer serves a spot exchangeInfo with one BTCUSDT symbol and
+// counts how often it is fetched
+func newExchangeInfoServer(t *testing.T, calls *int32) *httptest.Server {
+	t.Helper()
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/api/v3/exchangeInfo", r.URL.Path)
+		atomic.AddInt32(calls, 1)
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"symbols":[{"symbol":"BTCUSDT","status":"TRADING","baseAsset":"BTC","quoteAsset":"USDT","filters":[
+			{"filterType":"PRICE_FILTER","minPrice":"0.01","maxPrice":"1000000","tickSize":"0.10"},
+			{"filterType":"LOT_SIZE","minQty":"0.00001","maxQty":"9000","stepSize":"0.00001"},
+			{"filterType":"NOTIONAL","minNotional":"5"}]}]}`))
+	}))
+}
+
+func TestClient_RefreshExchangeInfo(t *testing.T) {
+	t.Run("rounds using fetched rules after refresh", func(t *testing.T) {
+		var calls int32
+		server := newExchangeInfoServer(t, &calls)
+		defer server.Close()
+
+		signer := auth.NewSigner("key", "secret")
+		client, err := NewClient(server.URL, signer, rest.NewClient(server.URL, signer), zerolog.Nop())
+		require.NoError(t, err)
+
+		require.NoError(t, client.RefreshExchangeInfo(context.Background()))
+		assert.Equal(t, int32(1), atomic.LoadInt32(&calls))
+
+		price, err := client.RoundPrice(context.Background(), "BTCUSDT", decimal.RequireFromString("50000.1234"))
+		require.NoError(t, err)
+		assert.Equal(t, "50000.1", price.String())
+
+		qty, err := client.RoundQuantity(context.Background(), "BTCUSDT", decimal.RequireFromString("0.123456789"))
+		require.NoError(t, err)
+		assert.Equal(t, "0.12345", qty.String())
+
+		// Served from cache
+		assert.Equal(t, int32(1), atomic.LoadInt32(&calls))
+	})
+
+	t.Run("stale cache triggers a re-fetch", func(t *testing.T) {
+		var calls int32
+		server := newExchangeInfoServer(t, &calls)
+		defer server.Close()
+
+		signer := auth.NewSigner("key", "secret")
+		client, err := NewClient(server.URL, signer, rest.NewClient(server.URL, signer), zerolog.Nop())
+		require.NoError(t, err)
+		client.exchangeInfoCacheTTL = 20 * time.Millisecond
+
+		require.NoError(t, client.RefreshExchangeInfo(context.Background()))
+		assert.Equal(t, int32(1), atomic.LoadInt32(&calls))
+
+		time.Sleep(30 * time.Millisecond)
+
+		_, err = client.RoundPrice(context.Background(), "BTCUSDT", decimal.NewFromInt(50000))
+		require.NoError(t, err)
+		assert.Equal(t, int32(2), atomic.LoadInt32(&calls))
+	})
+
+	t.Run("returns error without rest client", func(t *testing.T) {
+		client := &Client{logger: zerolog.Nop()}
+		assert.Error(t, client.RefreshExchangeInfo(context.Background()))
+	})
+}
+
+func TestClient_GetExchangeInfoForSymbol(t *testing.T) {
+	var calls int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/api/v3/exchangeInfo", r.URL.Path)
+		assert.Equal(t, "ETHUSDT", r.URL.Query().Get("symbol"))
+		atomic.AddInt32(&calls, 1)
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"symbols":[{"symbol":"ETHUSDT","status":"TRADING","baseAsset":"ETH","quoteAsset":"USDT","filters":[
+			{"filterType":"PRICE_FILTER","minPrice":"0.01","maxPrice":"100000","tickSize":"0.01"},
+			{"filterType":"LOT_SIZE","minQty":"0.0001","maxQty":"9000","stepSize":"0.0001"},
+			{"filterType":"NOTIONAL","minNotional":"5"}]}]}`))
+	}))
+	defer server.Close()
+
+	signer := auth.NewSigner("key", "secret")
+	client, err := NewClient(server.URL, signer, rest.NewClient(server.URL, signer), zerolog.Nop())
+	require.NoError(t, err)
+
+	info, err := client.GetExchangeInfoForSymbol(context.Background(), "ETHUSDT")
+	require.NoError(t, err)
+	assert.Equal(t, "ETH", info.BaseAsset)
+	assert.Equal(t, "0.01", info.TickSize.String())
+	assert.Equal(t, "0.0001", info.StepSize.String())
+	assert.Equal(t, "5", info.MinNotional.String())
+
+	// Rounding reuses the lazily fetched rules
+	qty, err := client.RoundQuantity(context.Background(), "ETHUSDT", decimal.RequireFromString("1.23456"))
+	require.NoError(t, err)
+	assert.Equal(t, "1.2345", qty.String())
+	assert.Equal(t, int32(1), atomic.LoadInt32(&calls))
+}
+
+func TestExchangeInfoCache_FetchesOutsideLock(t *testing.T) {
+	var calls int32
+	release := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&calls, 1)
+		<-release
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"symbols":[{"symbol":"ETHUSDT","status":"TRADING","baseAsset":"ETH","quoteAsset":"USDT","filters":[
+			{"filterType":"PRICE_FILTER","minPrice":"0.01","maxPrice":"100000","tickSize":"0.01"},
+			{"filterType":"LOT_SIZE","minQty":"0.0001","maxQty":"9000","stepSize":"0.0001"}]}]}`))
+	}))
+	defer server.Close()
+
+	cache := NewExchangeInfoCache(rest.NewClient(server.URL, nil), nil, time.Hour, zerolog.Nop())
+
+	const lookups = 5
+	errs := make(chan error, lookups)
+	for i := 0; i < lookups; i++ {
+		go func() {
+			_, err := cache.GetSymbolInfo(context.Background(), "ETHUSDT", false)
+			errs <- err
+		}()
+	}
+	require.Eventually(t, func() bool { return atomic.LoadInt32(&calls) == 1 }, time.Second, 5*time.Millisecond)
+
+	// Readers are not blocked while the fetch is in flight
+	done := make(chan struct{})
+	go func() {
+		cache.LastRefresh()
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("LastRefresh blocked behind the exchange info fetch")
+	}
+
+	close(release)
+	for i := 0; i < lookups; i++ {
+		require.NoError(t, <-errs)
+	}
+	assert.Equal(t, int32(1), atomic.LoadInt32(&calls), "concurrent lookups share one fetch")
+}
+
+func TestClient_RoundOrderValues(t *testing.T) {
+	var calls int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&calls, 1)
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"symbols":[{"symbol":"ETHUSDT","status":"TRADING","baseAsset":"ETH","quoteAsset":"USDT","filters":[
+			{"filterType":"PRICE_FILTER","minPrice":"0.01","maxPrice":"100000","tickSize":"0.05"},
+			{"filterType":"LOT_SIZE","minQty":"0.001","maxQty":"9000","stepSize":"0.001"}]}]}`))
+	}))
+	defer server.Close()
+
+	signer := auth.NewSigner("key", "secret")
+	client, err := NewClient(server.URL, signer, rest.NewClient(server.URL, signer), zerolog.Nop())
+	require.NoError(t, err)
+
+	prices := []decimal.Decimal{
+		decimal.RequireFromString("2000.03"),
+		decimal.RequireFromString("2100.111"),
+		decimal.RequireFromString("1899.98"),
+	}
+	quantities := []decimal.Decimal{
+		decimal.RequireFromString("1.23456"),
+		decimal.RequireFromString("0.0001"),
+	}
+
+	roundedPrices, roundedQuantities, err := client.RoundOrderValues(context.Background(), "ETHUSDT", prices, quantities)
+	require.NoError(t, err)
+
+	var gotPrices, gotQuantities []string
+	for _, price := range roundedPrices {
+		gotPrices = append(gotPrices, price.String())
+	}
+	for _, quantity := range roundedQuantities {
+		gotQuantities = append(gotQuantities, quantity.String())
+	}
+	assert.Equal(t, []string{"2000.05", "2100.1", "1900"}, gotPrices)
+	assert.Equal(t, []string{"1.234", "0.001"}, gotQuantities)
+	assert.Equal(t, "2000.03", prices[0].String(), "inputs are not modified")
+	assert.Equal(t, int32(1), atomic.LoadInt32(&calls), "rules are fetched once for the batch")
+
+	_, _, err = client.RoundOrderValues(context.Background(), "", nil, nil)
+	assert.Error(t, err)
+}
+
+func TestClient_RoundQuoteQuantity(t *testing.T) {
+	tests := []struct {
+		name      string
+		isFutures bool
+		symbols   string
+		expected  string
+	}{
+		{
+			name:     "spot quoteAssetPrecision",
+			symbols:  `[{"symbol":"ETHUSDT","status":"TRADING","quoteAsset":"USDT","quoteAssetPrecision":4}]`,
+			expected: "123.4568",
+		},
+		{
+			name:      "futures quotePrecision",
+			isFutures: true,
+			symbols:   `[{"symbol":"ETHUSDT","status":"TRADING","quoteAsset":"USDT","quotePrecision":6}]`,
+			expected:  "123.456789",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.Header().Set("Content-Type", "application/json")
+				w.Write([]byte(`{"symbols":` + tt.symbols + `}`))
+			}))
+			defer server.Close()
+
+			signer := auth.NewSigner("key", "secret")
+			client, err := NewClient(server.URL, signer, rest.NewClient(server.URL, signer), zerolog.Nop())
+			require.NoError(t, err)
+			client.isFutures = tt.isFutures
+
+			rounded, err := client.RoundQuoteQuantity(context.Background(), "ETHUSDT", decimal.RequireFromString("123.45678912"))
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, rounded.String())
+		})
+	}
+}
+
+func TestClient_ValidatePercentPrice(t *testing.T) {
+	const filters = `{"filterType":"PRICE_FILTER","minPrice":"0.01","maxPrice":"1000000","tickSize":"0.01"},
+		{"filterType":"LOT_SIZE","minQty":"0.001","maxQty":"9000","stepSize":"0.001"}`
+
+	newServer := func(t *testing.T, percentFilter string, referenceCalls *int32) *httptest.Server {
+		return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Content-Type", "application/json")
+			switch r.URL.Path {
+			case "/api/v3/exchangeInfo", "/fapi/v1/exchangeInfo":
+				extra := ""
+				if percentFilter != "" {
+					extra = "," + percentFilter
+				}
+				w.Write([]byte(`{"symbols":[{"symbol":"BTCUSDT","status":"TRADING","filters":[` + filters + extra + `]}]}`))
+			case "/api/v3/avgPrice":
+				atomic.AddInt32(referenceCalls, 1)
+				w.Write([]byte(`{"mins":5,"price":"50000","closeTime":1}`))
+			case "/fapi/v1/premiumIndex":
+				atomic.AddInt32(referenceCalls, 1)
+				w.Write([]byte(`{"symbol":"BTCUSDT","markPrice":"40000"}`))
+			default:
+				t.Errorf("unexpected request to %s", r.URL.Path)
+				w.WriteHeader(http.StatusNotFound)
+			}
+		}))
+	}
+
+	newTestClient := func(t *testing.T, url string, isFutures bool) *Client {
+		signer := auth.NewSigner("key", "secret")
+		client, err := NewClient(url, signer, rest.NewClient(url, signer), zerolog.Nop())
+		require.NoError(t, err)
+		client.isFutures = isFutures
+		return client
+	}
+
+	ctx := context.Background()
+
+	tests := []struct {
+		name      string
+		filter    string
+		isFutures bool
+		prices    []string
+		wantErr   string
+	}{
+		{
+			name:   "spot price inside the range",
+			filter: `{"filterType":"PERCENT_PRICE","multiplierUp":"5","multiplierDown":"0.2","avgPriceMins":5}`,
+			prices: []string{"10000", "250000"},
+		},
+		{
+			name:    "spot price below the range",
+			filter:  `{"filterType":"PERCENT_PRICE","multiplierUp":"5","multiplierDown":"0.2","avgPriceMins":5}`,
+			prices:  []string{"60000", "9999.99"},
+			wantErr: "price 9999.99 for BTCUSDT is outside the percent price range [10000, 250000]",
+		},
+		{
+			name: "spot by-side filter uses the widest bounds",
+			filter: `{"filterType":"PERCENT_PRICE_BY_SIDE","bidMultiplierUp":"1.2","bidMultiplierDown":"0.2",
+				"askMultiplierUp":"5","askMultiplierDown":"0.8","avgPriceMins":1}`,
+			prices: []string{"10000", "250000"},
+		},
+		{
+			name:      "futures price inside the range",
+			filter:    `{"filterType":"PERCENT_PRICE","multiplierUp":"1.05","multiplierDown":"0.95","multiplierDecimal":"4"}`,
+			isFutures: true,
+			prices:    []string{"38000", "42000"},
+		},
+		{
+			name:      "futures price above the range",
+			filter:    `{"filterType":"PERCENT_PRICE","multiplierUp":"1.05","multiplierDown":"0.95","multiplierDecimal":"4"}`,
+			isFutures: true,
+			prices:    []string{"42000.01"},
+			wantErr:   "price 42000.01 for BTCUSDT is outside the percent price range [38000, 42000]",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var referenceCalls int32
+			server := newServer(t, tt.filter, &referenceCalls)
+			defer server.Close()
+
+			prices := make([]decimal.Decimal, len(tt.prices))
+			for i, p := range tt.prices {
+				prices[i] = decimal.RequireFromString(p)
+			}
+
+			err := newTestClient(t, server.URL, tt.isFutures).ValidatePercentPrice(ctx, "BTCUSDT", prices...)
+			if tt.wantErr != "" {
+				assert.EqualError(t, err, tt.wantErr)
+			} else {
+				assert.NoError(t, err)
+			}
+			assert.Equal(t, int32(1), atomic.LoadInt32(&referenceCalls), "reference price is fetched once per call")
+		})
+	}
+
+	t.Run("symbols without the filter skip the reference lookup", func(t *testing.T) {
+		var referenceCalls int32
+		server := newServer(t, "", &referenceCalls)
+		defer server.Close()
+
+		err := newTestClient(t, server.URL, false).ValidatePercentPrice(ctx, "BTCUSDT", decimal.NewFromInt(1))
+		assert.NoError(t, err)
+		assert.Zero(t, atomic.LoadInt32(&referenceCalls))
+	})
+}
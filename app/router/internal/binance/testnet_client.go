@@ -23,48 +23,50 @@ func GetTestnetURLs() TestnetURLs {
 
 // NewTestnetSpotClient creates a new testnet spot client
 func NewTestnetSpotClient(config *config.BinanceConfig, logger zerolog.Logger) (*Client, error) {
-	urls := GetTestnetURLs()
-	signer := auth.NewSignerWithRecvWindow(config.SpotAPIKey, config.SpotSecretKey, config.RecvWindow)
-	restClient := rest.NewClient(
-		urls.SpotBaseURL,
-		signer,
-		rest.WithTimeout(config.Timeout),
-		rest.WithMaxRetries(config.MaxRetries),
-	)
+	return NewSpotClient(config, GetTestnetURLs().SpotBaseURL, logger)
+}
 
-	client, err := NewClient(urls.SpotBaseURL, signer, restClient, logger)
-	if err != nil {
-		return nil, err
-	}
+// NewTestnetFuturesClient creates a new testnet futures client
+func NewTestnetFuturesClient(config *config.BinanceConfig, logger zerolog.Logger) (*Client, error) {
+	return NewFuturesClient(config, GetTestnetURLs().FuturesBaseURL, logger)
+}
 
-	client.isFutures = false
+// NewSpotClient creates a spot client against baseURL using the spot credentials
+func NewSpotClient(config *config.BinanceConfig, baseURL string, logger zerolog.Logger) (*Client, error) {
+	signer := auth.NewSignerWithRecvWindow(config.SpotAPIKey, config.SpotSecretKey, config.RecvWindow)
+	return newConfiguredClient(config, baseURL, signer, false, logger)
+}
 
-	// Create exchange info cache
-	client.exchangeInfoCache = NewExchangeInfoCache(restClient, nil, config.ExchangeInfoCacheTTL, logger.With().Str("component", "exchange_info").Logger())
+// NewFuturesClient creates a USDT-M futures client against baseURL using the futures credentials
+func NewFuturesClient(config *config.BinanceConfig, baseURL string, logger zerolog.Logger) (*Client, error) {
+	signer := auth.NewSignerWithRecvWindow(config.FuturesAPIKey, config.FuturesSecretKey, config.RecvWindow)
+	return newConfiguredClient(config, baseURL, signer, true, logger)
+}
 
-	return client, nil
+// NewProfileClient creates a client for a named API key profile against
+// baseURL, using the profile's venue and the REST settings from config
+func NewProfileClient(config *config.BinanceConfig, profile config.APIKeyProfile, baseURL string, logger zerolog.Logger) (*Client, error) {
+	signer := auth.NewSignerWithRecvWindow(profile.APIKey, profile.SecretKey, config.RecvWindow)
+	return newConfiguredClient(config, baseURL, signer, profile.IsFutures(), logger)
 }
 
-// NewTestnetFuturesClient creates a new testnet futures client
-func NewTestnetFuturesClient(config *config.BinanceConfig, logger zerolog.Logger) (*Client, error) {
-	urls := GetTestnetURLs()
-	signer := auth.NewSignerWithRecvWindow(config.FuturesAPIKey, config.FuturesSecretKey, config.RecvWindow)
+// newConfiguredClient builds a client with REST settings taken from config
+func newConfiguredClient(config *config.BinanceConfig, baseURL string, signer *auth.Signer, isFutures bool, logger zerolog.Logger) (*Client, error) {
 	restClient := rest.NewClient(
-		urls.FuturesBaseURL,
+		baseURL,
 		signer,
 		rest.WithTimeout(config.Timeout),
 		rest.WithMaxRetries(config.MaxRetries),
+		rest.WithStrictDecimals(config.StrictDecimals),
 	)
 
-	client, err := NewClient(urls.FuturesBaseURL, signer, restClient, logger)
+	client, err := NewClient(baseURL, signer, restClient, logger, WithAccountCacheTTL(config.AccountCacheTTL))
 	if err != nil {
 		return nil, err
 	}
 
-	client.isFutures = true
-
-	// Create exchange info cache (futures client doesn't have spot client)
-	client.exchangeInfoCache = NewExchangeInfoCache(nil, restClient, config.ExchangeInfoCacheTTL, logger.With().Str("component", "exchange_info").Logger())
+	client.isFutures = isFutures
+	client.exchangeInfoCacheTTL = config.ExchangeInfoCacheTTL
 
 	return client, nil
 }
@@ -17,6 +17,9 @@ type SpotOrderRequest struct {
 	StopPrice        decimal.Decimal `json:"stopPrice,omitempty"`
 	QuoteOrderQty    decimal.Decimal `json:"quoteOrderQty,omitempty"`
 	NewClientOrderID string          `json:"newClientOrderId,omitempty"`
+
+	// SelfTradePreventionMode is one of the rest.STPMode values
+	SelfTradePreventionMode string `json:"selfTradePreventionMode,omitempty"`
 }
 
 // FuturesOrderRequest represents a futures order placement request
@@ -30,7 +33,15 @@ type FuturesOrderRequest struct {
 	StopPrice        decimal.Decimal `json:"stopPrice,omitempty"`
 	ReduceOnly       bool            `json:"reduceOnly,omitempty"`
 	ClosePosition    bool            `json:"closePosition,omitempty"`
+	ActivationPrice  decimal.Decimal `json:"activationPrice,omitempty"` // TRAILING_STOP_MARKET only
+	CallbackRate     decimal.Decimal `json:"callbackRate,omitempty"`    // TRAILING_STOP_MARKET only, percent
+	WorkingType      string          `json:"workingType,omitempty"`     // MARK_PRICE or CONTRACT_PRICE trigger
+	PriceProtect     bool            `json:"priceProtect,omitempty"`    // Ignore triggers during abnormal mark/last price divergence
+	PositionSide     string          `json:"positionSide,omitempty"`    // LONG or SHORT in hedge mode, empty or BOTH in one-way mode
 	NewClientOrderID string          `json:"newClientOrderId,omitempty"`
+
+	// SelfTradePreventionMode is one of the rest.STPMode values
+	SelfTradePreventionMode string `json:"selfTradePreventionMode,omitempty"`
 }
 
 // OrderResponse represents the response from order placement
@@ -47,6 +58,8 @@ type OrderResponse struct {
 	Type          string          `json:"type"`
 	Side          string          `json:"side"`
 	Fills         []Fill          `json:"fills"`
+	// Slippage is set for simulated fills only; see rest.OrderResponse
+	Slippage *decimal.Decimal `json:"slippage,omitempty"`
 }
 
 // Fill represents individual trade fills
@@ -57,6 +70,46 @@ type Fill struct {
 	CommissionAsset string          `json:"commissionAsset"`
 }
 
+// AverageFillPrice returns the quantity-weighted average price of all fills,
+// falling back to the order price when there are no fills
+func (r *OrderResponse) AverageFillPrice() decimal.Decimal {
+	totalQty := decimal.Zero
+	totalQuote := decimal.Zero
+	for _, fill := range r.Fills {
+		totalQty = totalQty.Add(fill.Qty)
+		totalQuote = totalQuote.Add(fill.Price.Mul(fill.Qty))
+	}
+
+	if totalQty.IsZero() {
+		return r.Price
+	}
+	return totalQuote.Div(totalQty)
+}
+
+// TotalCommission returns the summed commission and its asset. When fills were
+// charged in more than one asset it returns zero and an empty asset; use
+// CommissionByAsset for the per-asset breakdown.
+func (r *OrderResponse) TotalCommission() (decimal.Decimal, string) {
+	byAsset := r.CommissionByAsset()
+	if len(byAsset) != 1 {
+		return decimal.Zero, ""
+	}
+
+	for asset, total := range byAsset {
+		return total, asset
+	}
+	return decimal.Zero, ""
+}
+
+// CommissionByAsset groups fill commissions by commission asset
+func (r *OrderResponse) CommissionByAsset() map[string]decimal.Decimal {
+	totals := make(map[string]decimal.Decimal)
+	for _, fill := range r.Fills {
+		totals[fill.CommissionAsset] = totals[fill.CommissionAsset].Add(fill.Commission)
+	}
+	return totals
+}
+
 // AccountResponse represents account information
 type AccountResponse struct {
 	MakerCommission  int64     `json:"makerCommission"`
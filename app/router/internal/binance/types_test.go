@@ -0,0 +1,56 @@
+package binance
+
+import (
+	"testing"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestOrderResponse_FillAggregation(t *testing.T) {
+	t.Run("weighted average and grouped commission", func(t *testing.T) {
+		resp := &OrderResponse{
+			Fills: []Fill{
+				{Price: decimal.RequireFromString("100"), Qty: decimal.RequireFromString("1"), Commission: decimal.RequireFromString("0.001"), CommissionAsset: "BNB"},
+				{Price: decimal.RequireFromString("101"), Qty: decimal.RequireFromString("3"), Commission: decimal.RequireFromString("0.002"), CommissionAsset: "BNB"},
+				{Price: decimal.RequireFromString("102"), Qty: decimal.RequireFromString("1"), Commission: decimal.RequireFromString("0.102"), CommissionAsset: "USDT"},
+			},
+		}
+
+		// (100*1 + 101*3 + 102*1) / 5 = 101
+		assert.Equal(t, "101", resp.AverageFillPrice().String())
+
+		byAsset := resp.CommissionByAsset()
+		assert.Len(t, byAsset, 2)
+		assert.Equal(t, "0.003", byAsset["BNB"].String())
+		assert.Equal(t, "0.102", byAsset["USDT"].String())
+
+		total, asset := resp.TotalCommission()
+		assert.True(t, total.IsZero())
+		assert.Empty(t, asset)
+	})
+
+	t.Run("single commission asset", func(t *testing.T) {
+		resp := &OrderResponse{
+			Fills: []Fill{
+				{Price: decimal.RequireFromString("50000"), Qty: decimal.RequireFromString("0.01"), Commission: decimal.RequireFromString("0.5"), CommissionAsset: "USDT"},
+				{Price: decimal.RequireFromString("50010"), Qty: decimal.RequireFromString("0.01"), Commission: decimal.RequireFromString("0.5001"), CommissionAsset: "USDT"},
+			},
+		}
+
+		assert.Equal(t, "50005", resp.AverageFillPrice().String())
+
+		total, asset := resp.TotalCommission()
+		assert.Equal(t, "1.0001", total.String())
+		assert.Equal(t, "USDT", asset)
+	})
+
+	t.Run("no fills falls back to order price", func(t *testing.T) {
+		resp := &OrderResponse{Price: decimal.RequireFromString("42")}
+
+		assert.Equal(t, "42", resp.AverageFillPrice().String())
+		total, asset := resp.TotalCommission()
+		assert.True(t, total.IsZero())
+		assert.Empty(t, asset)
+	})
+}
@@ -6,200 +6,559 @@ import (
 	"strconv"
 	"strings"
 	"time"
+
+	"gopkg.in/yaml.v3"
 )
 
 // Config holds all configuration for the router service
 type Config struct {
-	Server   ServerConfig   `json:"server"`
-	Binance  BinanceConfig  `json:"binance"`
-	Redis    RedisConfig    `json:"redis"`
-	Metrics  MetricsConfig  `json:"metrics"`
-	Logging  LoggingConfig  `json:"logging"`
-	Security SecurityConfig `json:"security"`
+	Server   ServerConfig   `json:"server" yaml:"server"`
+	Binance  BinanceConfig  `json:"binance" yaml:"binance"`
+	Redis    RedisConfig    `json:"redis" yaml:"redis"`
+	Metrics  MetricsConfig  `json:"metrics" yaml:"metrics"`
+	Logging  LoggingConfig  `json:"logging" yaml:"logging"`
+	Security SecurityConfig `json:"security" yaml:"security"`
+	Trading  TradingConfig  `json:"trading" yaml:"trading"`
+}
+
+// Trading venues accepted in TradingConfig.Venues
+const (
+	VenueSpot    = "spot"
+	VenueFutures = "futures"
+)
+
+// TradingConfig selects which venues the router trades on
+type TradingConfig struct {
+	// Venues lists the enabled venues ("spot", "futures"). When empty the
+	// legacy Binance.TradingMode decides.
+	Venues []string `json:"venues" yaml:"venues"`
+
+	// MaxBracketsPerSymbol caps simultaneously open brackets on one symbol;
+	// zero means unlimited
+	MaxBracketsPerSymbol int `json:"max_brackets_per_symbol" yaml:"max_brackets_per_symbol"`
+
+	// MaxTakeProfitLegs caps the take profits one bracket may carry; zero
+	// means unlimited
+	MaxTakeProfitLegs int `json:"max_take_profit_legs" yaml:"max_take_profit_legs"`
+
+	// DailyNotionalCap caps the quote notional of brackets placed per day;
+	// zero means unlimited. The day starts NotionalResetOffset after UTC
+	// midnight.
+	DailyNotionalCap    float64       `json:"daily_notional_cap" yaml:"daily_notional_cap"`
+	NotionalResetOffset time.Duration `json:"notional_reset_offset" yaml:"notional_reset_offset"`
+
+	// KillSwitchSymbols are closed when the kill switch is engaged with
+	// close_positions and no explicit symbols
+	KillSwitchSymbols []string `json:"kill_switch_symbols" yaml:"kill_switch_symbols"`
+
+	// PaperTrading fills spot orders in a simulated exchange fed by market
+	// data for PaperSymbols instead of sending them to Binance.
+	// PaperBalances seeds its account as ASSET:AMOUNT pairs.
+	PaperTrading  bool     `json:"paper_trading" yaml:"paper_trading"`
+	PaperSymbols  []string `json:"paper_symbols" yaml:"paper_symbols"`
+	PaperBalances []string `json:"paper_balances" yaml:"paper_balances"`
 }
 
 // ServerConfig holds HTTP server configuration
 type ServerConfig struct {
-	Port            int           `json:"port"`
-	Host            string        `json:"host"`
-	ReadTimeout     time.Duration `json:"read_timeout"`
-	WriteTimeout    time.Duration `json:"write_timeout"`
-	IdleTimeout     time.Duration `json:"idle_timeout"`
-	ShutdownTimeout time.Duration `json:"shutdown_timeout"`
+	Port         int           `json:"port" yaml:"port"`
+	Host         string        `json:"host" yaml:"host"`
+	ReadTimeout  time.Duration `json:"read_timeout" yaml:"read_timeout"`
+	WriteTimeout time.Duration `json:"write_timeout" yaml:"write_timeout"`
+	// RequestTimeout bounds a request's work. It must leave time within
+	// WriteTimeout to write the response, including a gateway timeout.
+	RequestTimeout  time.Duration `json:"request_timeout" yaml:"request_timeout"`
+	IdleTimeout     time.Duration `json:"idle_timeout" yaml:"idle_timeout"`
+	ShutdownTimeout time.Duration `json:"shutdown_timeout" yaml:"shutdown_timeout"`
 }
 
 // BinanceConfig holds Binance API configuration
 type BinanceConfig struct {
 	// Trading mode configuration
-	TradingMode string `json:"trading_mode"`
+	TradingMode string `json:"trading_mode" yaml:"trading_mode"`
 
 	// Spot API credentials
-	SpotAPIKey    string `json:"spot_api_key"`
-	SpotSecretKey string `json:"spot_secret_key"`
+	SpotAPIKey    string `json:"spot_api_key" yaml:"spot_api_key"`
+	SpotSecretKey string `json:"spot_secret_key" yaml:"spot_secret_key"`
 
 	// Futures API credentials
-	FuturesAPIKey    string `json:"futures_api_key"`
-	FuturesSecretKey string `json:"futures_secret_key"`
+	FuturesAPIKey    string `json:"futures_api_key" yaml:"futures_api_key"`
+	FuturesSecretKey string `json:"futures_secret_key" yaml:"futures_secret_key"`
 
 	// Legacy fields for backward compatibility
-	APIKey         string        `json:"api_key"`
-	SecretKey      string        `json:"secret_key"`
-	BaseURL        string        `json:"base_url"`
-	WSBaseURL      string        `json:"ws_base_url"`
-	FuturesBaseURL string        `json:"futures_base_url"`
-	FuturesWSURL   string        `json:"futures_ws_url"`
-	Testnet        bool          `json:"testnet"`
-	Timeout        time.Duration `json:"timeout"`
-	MaxRetries     int           `json:"max_retries"`
-	RetryDelay     time.Duration `json:"retry_delay"`
-	RateLimitDelay time.Duration `json:"rate_limit_delay"`
-	RecvWindow     int64         `json:"recv_window"`
+	APIKey         string        `json:"api_key" yaml:"api_key"`
+	SecretKey      string        `json:"secret_key" yaml:"secret_key"`
+	BaseURL        string        `json:"base_url" yaml:"base_url"`
+	WSBaseURL      string        `json:"ws_base_url" yaml:"ws_base_url"`
+	FuturesBaseURL string        `json:"futures_base_url" yaml:"futures_base_url"`
+	FuturesWSURL   string        `json:"futures_ws_url" yaml:"futures_ws_url"`
+	WSAPIURL       string        `json:"ws_api_url" yaml:"ws_api_url"`
+	UseWSAPI       bool          `json:"use_ws_api" yaml:"use_ws_api"`
+	Testnet        bool          `json:"testnet" yaml:"testnet"`
+	Timeout        time.Duration `json:"timeout" yaml:"timeout"`
+	MaxRetries     int           `json:"max_retries" yaml:"max_retries"`
+	RetryDelay     time.Duration `json:"retry_delay" yaml:"retry_delay"`
+	RateLimitDelay time.Duration `json:"rate_limit_delay" yaml:"rate_limit_delay"`
+	RecvWindow     int64         `json:"recv_window" yaml:"recv_window"`
+
+	// StrictDecimals fails responses with malformed decimal fields instead
+	// of reading them as zero
+	StrictDecimals bool `json:"strict_decimals" yaml:"strict_decimals"`
 
 	// Exchange info cache
-	ExchangeInfoCacheTTL time.Duration `json:"exchange_info_cache_ttl"`
+	ExchangeInfoCacheTTL time.Duration `json:"exchange_info_cache_ttl" yaml:"exchange_info_cache_ttl"`
+
+	// AccountCacheTTL is how long account snapshots are reused for balance
+	// and margin checks; zero always fetches
+	AccountCacheTTL time.Duration `json:"account_cache_ttl" yaml:"account_cache_ttl"`
+
+	// Profiles are named credentials beyond the default spot and futures
+	// keys, such as a read-only data key or one key per strategy
+	Profiles []APIKeyProfile `json:"profiles" yaml:"profiles"`
+}
+
+// APIKeyProfile is a named API key for a single venue. ReadOnly profiles are
+// used for market and account data and are never handed orders.
+type APIKeyProfile struct {
+	Name      string `json:"name" yaml:"name"`
+	Venue     string `json:"venue" yaml:"venue"`
+	APIKey    string `json:"api_key" yaml:"api_key"`
+	SecretKey string `json:"secret_key" yaml:"secret_key"`
+	ReadOnly  bool   `json:"read_only" yaml:"read_only"`
+}
+
+// IsFutures reports whether the profile's key is for the futures venue
+func (p APIKeyProfile) IsFutures() bool {
+	return p.Venue == VenueFutures
 }
 
 // RedisConfig holds Redis configuration
 type RedisConfig struct {
-	Host     string `json:"host"`
-	Port     int    `json:"port"`
-	Password string `json:"password"`
-	DB       int    `json:"db"`
-	PoolSize int    `json:"pool_size"`
+	Host     string `json:"host" yaml:"host"`
+	Port     int    `json:"port" yaml:"port"`
+	Password string `json:"password" yaml:"password"`
+	DB       int    `json:"db" yaml:"db"`
+	PoolSize int    `json:"pool_size" yaml:"pool_size"`
 }
 
 // MetricsConfig holds metrics configuration
 type MetricsConfig struct {
-	Enabled bool   `json:"enabled"`
-	Path    string `json:"path"`
-	Port    int    `json:"port"`
+	Enabled bool   `json:"enabled" yaml:"enabled"`
+	Path    string `json:"path" yaml:"path"`
+	Port    int    `json:"port" yaml:"port"`
 }
 
 // LoggingConfig holds logging configuration
 type LoggingConfig struct {
-	Level      string `json:"level"`
-	Format     string `json:"format"`   // json or text
-	Output     string `json:"output"`   // stdout, stderr, or file path
-	MaxSize    int    `json:"max_size"` // MB
-	MaxBackups int    `json:"max_backups"`
-	MaxAge     int    `json:"max_age"` // days
+	Level      string `json:"level" yaml:"level"`
+	Format     string `json:"format" yaml:"format"`     // json or text
+	Output     string `json:"output" yaml:"output"`     // stdout, stderr, or file path
+	MaxSize    int    `json:"max_size" yaml:"max_size"` // MB
+	MaxBackups int    `json:"max_backups" yaml:"max_backups"`
+	MaxAge     int    `json:"max_age" yaml:"max_age"` // days
+
+	// AuditOutput is where the order audit log is appended: stdout, stderr,
+	// or a file path. Empty disables the audit log.
+	AuditOutput string `json:"audit_output" yaml:"audit_output"`
 }
 
 // SecurityConfig holds security configuration
 type SecurityConfig struct {
-	APIKeyHeader    string        `json:"api_key_header"`
-	RequiredAPIKey  string        `json:"required_api_key"`
-	MaxRequestSize  int64         `json:"max_request_size"`
-	RateLimit       int           `json:"rate_limit"` // requests per minute
-	RateLimitWindow time.Duration `json:"rate_limit_window"`
-	AllowedOrigins  []string      `json:"allowed_origins"`
+	APIKeyHeader    string        `json:"api_key_header" yaml:"api_key_header"`
+	RequiredAPIKey  string        `json:"required_api_key" yaml:"required_api_key"`
+	MaxRequestSize  int64         `json:"max_request_size" yaml:"max_request_size"`
+	RateLimit       int           `json:"rate_limit" yaml:"rate_limit"` // requests per minute
+	RateLimitWindow time.Duration `json:"rate_limit_window" yaml:"rate_limit_window"`
+	AllowedOrigins  []string      `json:"allowed_origins" yaml:"allowed_origins"`
 }
 
-// Load loads configuration from environment variables
+// Load builds the configuration in layers: defaults first, then the file
+// named by CONFIG_PATH (YAML or JSON) if set, then environment variables.
+// Later layers take precedence over earlier ones.
 func Load() (*Config, error) {
-	config := &Config{
+	config := defaultConfig()
+
+	if path := os.Getenv("CONFIG_PATH"); path != "" {
+		if err := config.loadFile(path); err != nil {
+			return nil, err
+		}
+	}
+
+	config.applyEnv()
+	config.resolveVenues()
+
+	// Validate required configuration
+	if err := config.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid configuration: %w", err)
+	}
+
+	return config, nil
+}
+
+// defaultConfig returns the configuration used when neither a file nor the
+// environment provides a value
+func defaultConfig() *Config {
+	return &Config{
 		Server: ServerConfig{
-			Port:            getEnvAsInt("PORT", getEnvAsInt("SERVER_PORT", 8080)),
-			Host:            getEnv("HOST", getEnv("SERVER_HOST", "0.0.0.0")),
-			ReadTimeout:     getEnvAsDuration("SERVER_READ_TIMEOUT", "30s"),
-			WriteTimeout:    getEnvAsDuration("SERVER_WRITE_TIMEOUT", "30s"),
-			IdleTimeout:     getEnvAsDuration("SERVER_IDLE_TIMEOUT", "60s"),
-			ShutdownTimeout: getEnvAsDuration("SERVER_SHUTDOWN_TIMEOUT", "10s"),
+			Port:            8080,
+			Host:            "0.0.0.0",
+			ReadTimeout:     30 * time.Second,
+			WriteTimeout:    30 * time.Second,
+			RequestTimeout:  25 * time.Second,
+			IdleTimeout:     60 * time.Second,
+			ShutdownTimeout: 10 * time.Second,
 		},
 		Binance: BinanceConfig{
-			// Trading mode
-			TradingMode: getEnv("TRADING_MODE", ""),
-
-			// Spot API credentials (fallback to legacy keys)
-			SpotAPIKey:    getEnv("BINANCE_SPOT_API_KEY", getEnv("BINANCE_API_KEY", "")),
-			SpotSecretKey: getEnv("BINANCE_SPOT_SECRET_KEY", getEnv("BINANCE_SECRET_KEY", "")),
-
-			// Futures API credentials
-			FuturesAPIKey:    getEnv("BINANCE_FUTURES_API_KEY", ""),
-			FuturesSecretKey: getEnv("BINANCE_FUTURES_SECRET_KEY", ""),
-
-			// Legacy fields
-			APIKey:         getEnv("BINANCE_API_KEY", ""),
-			SecretKey:      getEnv("BINANCE_SECRET_KEY", ""),
-			BaseURL:        getEnv("BINANCE_SPOT_BASE_URL", getEnv("BINANCE_BASE_URL", "https://api.binance.com")),
-			WSBaseURL:      getEnv("BINANCE_WS_BASE_URL", "wss://stream.binance.com:9443"),
-			FuturesBaseURL: getEnv("BINANCE_FUTURES_BASE_URL", "https://fapi.binance.com"),
-			FuturesWSURL:   getEnv("BINANCE_FUTURES_WS_URL", "wss://fstream.binance.com"),
-			Testnet:        getEnvAsBool("USE_TESTNET", getEnvAsBool("BINANCE_TESTNET", false)),
-			Timeout:        getEnvAsDuration("BINANCE_TIMEOUT", "30s"),
-			MaxRetries:     getEnvAsInt("BINANCE_MAX_RETRIES", 3),
-			RetryDelay:     getEnvAsDuration("BINANCE_RETRY_DELAY", "1s"),
-			RateLimitDelay: getEnvAsDuration("BINANCE_RATE_LIMIT_DELAY", "100ms"),
-			RecvWindow:     getEnvAsInt64("BINANCE_RECV_WINDOW", 5000),
-
-			// Cache settings
-			ExchangeInfoCacheTTL: getEnvAsDuration("EXCHANGE_INFO_CACHE_TTL", "5m"),
+			BaseURL:              "https://api.binance.com",
+			WSBaseURL:            "wss://stream.binance.com:9443",
+			FuturesBaseURL:       "https://fapi.binance.com",
+			FuturesWSURL:         "wss://fstream.binance.com",
+			WSAPIURL:             "wss://ws-api.binance.com:443/ws-api/v3",
+			Timeout:              30 * time.Second,
+			MaxRetries:           3,
+			RetryDelay:           time.Second,
+			RateLimitDelay:       100 * time.Millisecond,
+			RecvWindow:           5000,
+			ExchangeInfoCacheTTL: 5 * time.Minute,
+			AccountCacheTTL:      30 * time.Second,
 		},
 		Redis: RedisConfig{
-			Host:     getEnv("REDIS_HOST", "localhost"),
-			Port:     getEnvAsInt("REDIS_PORT", 6379),
-			Password: getEnv("REDIS_PASSWORD", ""),
-			DB:       getEnvAsInt("REDIS_DB", 0),
-			PoolSize: getEnvAsInt("REDIS_POOL_SIZE", 10),
+			Host:     "localhost",
+			Port:     6379,
+			PoolSize: 10,
 		},
 		Metrics: MetricsConfig{
-			Enabled: getEnvAsBool("METRICS_ENABLED", true),
-			Path:    getEnv("METRICS_PATH", "/metrics"),
-			Port:    getEnvAsInt("METRICS_PORT", 9090),
+			Enabled: true,
+			Path:    "/metrics",
+			Port:    9090,
 		},
 		Logging: LoggingConfig{
-			Level:      getEnv("LOG_LEVEL", "info"),
-			Format:     getEnv("LOG_FORMAT", "json"),
-			Output:     getEnv("LOG_OUTPUT", "stdout"),
-			MaxSize:    getEnvAsInt("LOG_MAX_SIZE", 100),
-			MaxBackups: getEnvAsInt("LOG_MAX_BACKUPS", 5),
-			MaxAge:     getEnvAsInt("LOG_MAX_AGE", 30),
+			Level:      "info",
+			Format:     "json",
+			Output:     "stdout",
+			MaxSize:    100,
+			MaxBackups: 5,
+			MaxAge:     30,
 		},
 		Security: SecurityConfig{
-			APIKeyHeader:    getEnv("SECURITY_API_KEY_HEADER", "X-API-Key"),
-			RequiredAPIKey:  getEnv("SECURITY_REQUIRED_API_KEY", ""),
-			MaxRequestSize:  getEnvAsInt64("SECURITY_MAX_REQUEST_SIZE", 1048576), // 1MB
-			RateLimit:       getEnvAsInt("SECURITY_RATE_LIMIT", 1000),
-			RateLimitWindow: getEnvAsDuration("SECURITY_RATE_LIMIT_WINDOW", "1m"),
-			AllowedOrigins:  getEnvAsSlice("SECURITY_ALLOWED_ORIGINS", []string{"*"}),
+			APIKeyHeader:    "X-API-Key",
+			MaxRequestSize:  1048576, // 1MB
+			RateLimit:       1000,
+			RateLimitWindow: time.Minute,
+			AllowedOrigins:  []string{"*"},
 		},
 	}
+}
 
-	// Validate required configuration
-	if err := config.Validate(); err != nil {
-		return nil, fmt.Errorf("invalid configuration: %w", err)
+// loadFile overlays values from a YAML or JSON file onto the config.
+// Fields absent from the file keep their current values.
+func (c *Config) loadFile(path string) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("failed to read config file %s: %w", path, err)
 	}
 
-	return config, nil
+	// YAML is a superset of JSON, so one decoder handles both formats
+	// and accepts durations written as strings like "30s"
+	if err := yaml.Unmarshal(data, c); err != nil {
+		return fmt.Errorf("failed to parse config file %s: %w", path, err)
+	}
+
+	return nil
+}
+
+// applyEnv overrides config values with any environment variables that are set
+func (c *Config) applyEnv() {
+	c.Server.Port = getEnvAsInt("PORT", getEnvAsInt("SERVER_PORT", c.Server.Port))
+	c.Server.Host = getEnv("HOST", getEnv("SERVER_HOST", c.Server.Host))
+	c.Server.ReadTimeout = getEnvAsDuration("SERVER_READ_TIMEOUT", c.Server.ReadTimeout)
+	c.Server.WriteTimeout = getEnvAsDuration("SERVER_WRITE_TIMEOUT", c.Server.WriteTimeout)
+	c.Server.RequestTimeout = getEnvAsDuration("SERVER_REQUEST_TIMEOUT", c.Server.RequestTimeout)
+	c.Server.IdleTimeout = getEnvAsDuration("SERVER_IDLE_TIMEOUT", c.Server.IdleTimeout)
+	c.Server.ShutdownTimeout = getEnvAsDuration("SERVER_SHUTDOWN_TIMEOUT", c.Server.ShutdownTimeout)
+
+	b := &c.Binance
+	b.TradingMode = getEnv("TRADING_MODE", b.TradingMode)
+
+	// Legacy fields
+	b.APIKey = getEnv("BINANCE_API_KEY", b.APIKey)
+	b.SecretKey = getEnv("BINANCE_SECRET_KEY", b.SecretKey)
+
+	// Spot API credentials (fallback to legacy keys)
+	if b.SpotAPIKey == "" {
+		b.SpotAPIKey = b.APIKey
+	}
+	if b.SpotSecretKey == "" {
+		b.SpotSecretKey = b.SecretKey
+	}
+	b.SpotAPIKey = getEnv("BINANCE_SPOT_API_KEY", getEnv("BINANCE_API_KEY", b.SpotAPIKey))
+	b.SpotSecretKey = getEnv("BINANCE_SPOT_SECRET_KEY", getEnv("BINANCE_SECRET_KEY", b.SpotSecretKey))
+
+	// Futures API credentials
+	b.FuturesAPIKey = getEnv("BINANCE_FUTURES_API_KEY", b.FuturesAPIKey)
+	b.FuturesSecretKey = getEnv("BINANCE_FUTURES_SECRET_KEY", b.FuturesSecretKey)
+
+	b.BaseURL = getEnv("BINANCE_SPOT_BASE_URL", getEnv("BINANCE_BASE_URL", b.BaseURL))
+	b.WSBaseURL = getEnv("BINANCE_WS_BASE_URL", b.WSBaseURL)
+	b.FuturesBaseURL = getEnv("BINANCE_FUTURES_BASE_URL", b.FuturesBaseURL)
+	b.FuturesWSURL = getEnv("BINANCE_FUTURES_WS_URL", b.FuturesWSURL)
+	b.WSAPIURL = getEnv("BINANCE_WS_API_URL", b.WSAPIURL)
+	b.UseWSAPI = getEnvAsBool("BINANCE_USE_WS_API", b.UseWSAPI)
+	b.Testnet = getEnvAsBool("USE_TESTNET", getEnvAsBool("BINANCE_TESTNET", b.Testnet))
+	b.Timeout = getEnvAsDuration("BINANCE_TIMEOUT", b.Timeout)
+	b.MaxRetries = getEnvAsInt("BINANCE_MAX_RETRIES", b.MaxRetries)
+	b.RetryDelay = getEnvAsDuration("BINANCE_RETRY_DELAY", b.RetryDelay)
+	b.RateLimitDelay = getEnvAsDuration("BINANCE_RATE_LIMIT_DELAY", b.RateLimitDelay)
+	b.RecvWindow = getEnvAsInt64("BINANCE_RECV_WINDOW", b.RecvWindow)
+	b.StrictDecimals = getEnvAsBool("BINANCE_STRICT_DECIMALS", b.StrictDecimals)
+
+	// Cache settings
+	b.ExchangeInfoCacheTTL = getEnvAsDuration("EXCHANGE_INFO_CACHE_TTL", b.ExchangeInfoCacheTTL)
+	b.AccountCacheTTL = getEnvAsDuration("ACCOUNT_CACHE_TTL", b.AccountCacheTTL)
+
+	// Named key profiles: BINANCE_API_PROFILES lists the names, and each
+	// name's settings come from BINANCE_PROFILE_<NAME>_* variables
+	for _, name := range getEnvAsSlice("BINANCE_API_PROFILES", nil) {
+		name = strings.TrimSpace(name)
+		if name == "" {
+			continue
+		}
+		profile := b.profile(name)
+		if profile == nil {
+			b.Profiles = append(b.Profiles, APIKeyProfile{Name: name})
+			profile = &b.Profiles[len(b.Profiles)-1]
+		}
+		prefix := "BINANCE_PROFILE_" + strings.ToUpper(name) + "_"
+		profile.Venue = getEnv(prefix+"VENUE", profile.Venue)
+		profile.APIKey = getEnv(prefix+"API_KEY", profile.APIKey)
+		profile.SecretKey = getEnv(prefix+"SECRET_KEY", profile.SecretKey)
+		profile.ReadOnly = getEnvAsBool(prefix+"READ_ONLY", profile.ReadOnly)
+	}
+
+	c.Redis.Host = getEnv("REDIS_HOST", c.Redis.Host)
+	c.Redis.Port = getEnvAsInt("REDIS_PORT", c.Redis.Port)
+	c.Redis.Password = getEnv("REDIS_PASSWORD", c.Redis.Password)
+	c.Redis.DB = getEnvAsInt("REDIS_DB", c.Redis.DB)
+	c.Redis.PoolSize = getEnvAsInt("REDIS_POOL_SIZE", c.Redis.PoolSize)
+
+	c.Metrics.Enabled = getEnvAsBool("METRICS_ENABLED", c.Metrics.Enabled)
+	c.Metrics.Path = getEnv("METRICS_PATH", c.Metrics.Path)
+	c.Metrics.Port = getEnvAsInt("METRICS_PORT", c.Metrics.Port)
+
+	c.Logging.Level = getEnv("LOG_LEVEL", c.Logging.Level)
+	c.Logging.Format = getEnv("LOG_FORMAT", c.Logging.Format)
+	c.Logging.Output = getEnv("LOG_OUTPUT", c.Logging.Output)
+	c.Logging.MaxSize = getEnvAsInt("LOG_MAX_SIZE", c.Logging.MaxSize)
+	c.Logging.MaxBackups = getEnvAsInt("LOG_MAX_BACKUPS", c.Logging.MaxBackups)
+	c.Logging.MaxAge = getEnvAsInt("LOG_MAX_AGE", c.Logging.MaxAge)
+	c.Logging.AuditOutput = getEnv("AUDIT_LOG_OUTPUT", c.Logging.AuditOutput)
+
+	c.Security.APIKeyHeader = getEnv("SECURITY_API_KEY_HEADER", c.Security.APIKeyHeader)
+	c.Security.RequiredAPIKey = getEnv("SECURITY_REQUIRED_API_KEY", c.Security.RequiredAPIKey)
+	c.Security.MaxRequestSize = getEnvAsInt64("SECURITY_MAX_REQUEST_SIZE", c.Security.MaxRequestSize)
+	c.Security.RateLimit = getEnvAsInt("SECURITY_RATE_LIMIT", c.Security.RateLimit)
+	c.Security.RateLimitWindow = getEnvAsDuration("SECURITY_RATE_LIMIT_WINDOW", c.Security.RateLimitWindow)
+	c.Security.AllowedOrigins = getEnvAsSlice("SECURITY_ALLOWED_ORIGINS", c.Security.AllowedOrigins)
+
+	c.Trading.Venues = getEnvAsSlice("TRADING_VENUES", c.Trading.Venues)
+	c.Trading.MaxBracketsPerSymbol = getEnvAsInt("MAX_BRACKETS_PER_SYMBOL", c.Trading.MaxBracketsPerSymbol)
+	c.Trading.MaxTakeProfitLegs = getEnvAsInt("MAX_TAKE_PROFIT_LEGS", c.Trading.MaxTakeProfitLegs)
+	c.Trading.DailyNotionalCap = getEnvAsFloat("DAILY_NOTIONAL_CAP", c.Trading.DailyNotionalCap)
+	c.Trading.NotionalResetOffset = getEnvAsDuration("NOTIONAL_RESET_OFFSET", c.Trading.NotionalResetOffset)
+	c.Trading.KillSwitchSymbols = getEnvAsSlice("KILL_SWITCH_SYMBOLS", c.Trading.KillSwitchSymbols)
+	c.Trading.PaperTrading = getEnvAsBool("PAPER_TRADING", c.Trading.PaperTrading)
+	c.Trading.PaperSymbols = getEnvAsSlice("PAPER_SYMBOLS", c.Trading.PaperSymbols)
+	c.Trading.PaperBalances = getEnvAsSlice("PAPER_BALANCES", c.Trading.PaperBalances)
+}
+
+// resolveVenues normalizes Trading.Venues and mirrors it into
+// Binance.TradingMode so credential checks follow the venue list
+func (c *Config) resolveVenues() {
+	if len(c.Trading.Venues) == 0 {
+		return
+	}
+
+	venues := make([]string, 0, len(c.Trading.Venues))
+	for _, venue := range c.Trading.Venues {
+		if venue = strings.ToLower(strings.TrimSpace(venue)); venue != "" {
+			venues = append(venues, venue)
+		}
+	}
+	c.Trading.Venues = venues
+	c.Binance.TradingMode = strings.Join(venues, ",")
+}
+
+// IsVenueEnabled reports whether the router should trade on the given venue
+func (c *Config) IsVenueEnabled(venue string) bool {
+	if len(c.Trading.Venues) == 0 {
+		switch venue {
+		case VenueSpot:
+			return c.Binance.IsSpotEnabled()
+		case VenueFutures:
+			return c.Binance.IsFuturesEnabled()
+		}
+		return false
+	}
+
+	for _, v := range c.Trading.Venues {
+		if v == venue {
+			return true
+		}
+	}
+	return false
 }
 
-// Validate validates the configuration
+// ValidationError lists every missing or invalid configuration field
+type ValidationError struct {
+	Errors []string
+}
+
+// Error implements the error interface
+func (e *ValidationError) Error() string {
+	return strings.Join(e.Errors, "; ")
+}
+
+func (e *ValidationError) addf(format string, args ...interface{}) {
+	e.Errors = append(e.Errors, fmt.Sprintf(format, args...))
+}
+
+// Validate validates the configuration and reports all problems at once.
+// API credentials are only required outside testnet.
 func (c *Config) Validate() error {
-	// Check Spot API credentials if spot trading is enabled
-	if c.Binance.IsSpotEnabled() {
-		if c.Binance.SpotAPIKey == "" {
-			return fmt.Errorf("BINANCE_SPOT_API_KEY is required for spot trading")
+	verr := &ValidationError{}
+
+	for _, venue := range c.Trading.Venues {
+		if venue != VenueSpot && venue != VenueFutures {
+			verr.addf("invalid trading venue %q: must be %q or %q", venue, VenueSpot, VenueFutures)
 		}
-		if c.Binance.SpotSecretKey == "" {
-			return fmt.Errorf("BINANCE_SPOT_SECRET_KEY is required for spot trading")
+	}
+	if !c.IsVenueEnabled(VenueSpot) && !c.IsVenueEnabled(VenueFutures) {
+		verr.addf("at least one trading venue must be enabled")
+	}
+
+	if !c.Binance.Testnet {
+		// Check Spot API credentials if spot trading is enabled
+		if c.IsVenueEnabled(VenueSpot) {
+			if c.Binance.SpotAPIKey == "" {
+				verr.addf("BINANCE_SPOT_API_KEY is required for spot trading")
+			}
+			if c.Binance.SpotSecretKey == "" {
+				verr.addf("BINANCE_SPOT_SECRET_KEY is required for spot trading")
+			}
+		}
+
+		// Check Futures API credentials if futures trading is enabled
+		if c.IsVenueEnabled(VenueFutures) {
+			if c.Binance.FuturesAPIKey == "" {
+				verr.addf("BINANCE_FUTURES_API_KEY is required for futures trading")
+			}
+			if c.Binance.FuturesSecretKey == "" {
+				verr.addf("BINANCE_FUTURES_SECRET_KEY is required for futures trading")
+			}
 		}
 	}
 
-	// Check Futures API credentials if futures trading is enabled
-	if c.Binance.IsFuturesEnabled() {
-		if c.Binance.FuturesAPIKey == "" {
-			return fmt.Errorf("BINANCE_FUTURES_API_KEY is required for futures trading")
+	seen := make(map[string]bool, len(c.Binance.Profiles))
+	for _, profile := range c.Binance.Profiles {
+		if profile.Name == "" {
+			verr.addf("API key profile name must not be empty")
+			continue
+		}
+		if seen[profile.Name] {
+			verr.addf("duplicate API key profile %q", profile.Name)
+		}
+		seen[profile.Name] = true
+		if profile.Venue != VenueSpot && profile.Venue != VenueFutures {
+			verr.addf("API key profile %q has invalid venue %q: must be %q or %q", profile.Name, profile.Venue, VenueSpot, VenueFutures)
 		}
-		if c.Binance.FuturesSecretKey == "" {
-			return fmt.Errorf("BINANCE_FUTURES_SECRET_KEY is required for futures trading")
+		if profile.APIKey == "" || profile.SecretKey == "" {
+			verr.addf("API key profile %q requires both an API key and a secret key", profile.Name)
 		}
 	}
 
 	if c.Server.Port <= 0 || c.Server.Port > 65535 {
-		return fmt.Errorf("invalid server port: %d", c.Server.Port)
+		verr.addf("invalid server port: %d", c.Server.Port)
 	}
 	if c.Redis.Port <= 0 || c.Redis.Port > 65535 {
-		return fmt.Errorf("invalid redis port: %d", c.Redis.Port)
+		verr.addf("invalid redis port: %d", c.Redis.Port)
+	}
+
+	// Zero timeouts silently disable server and client protections
+	timeouts := []struct {
+		name  string
+		value time.Duration
+	}{
+		{"server read timeout", c.Server.ReadTimeout},
+		{"server write timeout", c.Server.WriteTimeout},
+		{"server request timeout", c.Server.RequestTimeout},
+		{"server idle timeout", c.Server.IdleTimeout},
+		{"server shutdown timeout", c.Server.ShutdownTimeout},
+		{"binance timeout", c.Binance.Timeout},
+	}
+	for _, timeout := range timeouts {
+		if timeout.value <= 0 {
+			verr.addf("%s must be positive, got %s", timeout.name, timeout.value)
+		}
+	}
+
+	if c.Server.RequestTimeout > 0 && c.Server.RequestTimeout >= c.Server.WriteTimeout {
+		verr.addf("server request timeout %s must be shorter than the write timeout %s", c.Server.RequestTimeout, c.Server.WriteTimeout)
+	}
+
+	if c.Binance.AccountCacheTTL < 0 {
+		verr.addf("account cache TTL must not be negative, got %s", c.Binance.AccountCacheTTL)
+	}
+
+	if c.Security.RateLimit <= 0 {
+		verr.addf("rate limit must be positive, got %d", c.Security.RateLimit)
+	}
+	if c.Trading.MaxBracketsPerSymbol < 0 {
+		verr.addf("max brackets per symbol must not be negative, got %d", c.Trading.MaxBracketsPerSymbol)
+	}
+	if c.Trading.MaxTakeProfitLegs < 0 {
+		verr.addf("max take profit legs must not be negative, got %d", c.Trading.MaxTakeProfitLegs)
+	}
+	if c.Trading.DailyNotionalCap < 0 {
+		verr.addf("daily notional cap must not be negative, got %g", c.Trading.DailyNotionalCap)
+	}
+	if c.Trading.NotionalResetOffset < 0 || c.Trading.NotionalResetOffset >= 24*time.Hour {
+		verr.addf("notional reset offset must be within [0, 24h), got %s", c.Trading.NotionalResetOffset)
+	}
+	if c.Trading.PaperTrading {
+		// Futures orders have no simulator and would reach the live exchange
+		if c.IsVenueEnabled(VenueFutures) {
+			verr.addf("paper trading only supports the spot venue")
+		}
+		if len(c.Trading.PaperSymbols) == 0 {
+			verr.addf("paper trading requires at least one paper symbol")
+		}
+		// Profile clients place orders directly, bypassing the simulator
+		for _, profile := range c.Binance.Profiles {
+			if !profile.ReadOnly {
+				verr.addf("API key profile %q must be read_only with paper trading", profile.Name)
+			}
+		}
+	}
+
+	if len(verr.Errors) > 0 {
+		return verr
+	}
+	return nil
+}
+
+// Profile returns the named API key profile
+func (bc *BinanceConfig) Profile(name string) (APIKeyProfile, bool) {
+	if profile := bc.profile(name); profile != nil {
+		return *profile, true
+	}
+	return APIKeyProfile{}, false
+}
+
+func (bc *BinanceConfig) profile(name string) *APIKeyProfile {
+	for i := range bc.Profiles {
+		if bc.Profiles[i].Name == name {
+			return &bc.Profiles[i]
+		}
 	}
 	return nil
 }
@@ -222,13 +581,39 @@ func (bc *BinanceConfig) IsFuturesEnabled() bool {
 	return strings.Contains(bc.TradingMode, "futures")
 }
 
-// GetBinanceTestnetURLs returns testnet URLs if testnet is enabled
-func (c *Config) GetBinanceTestnetURLs() {
+// BinanceURLs holds every Binance endpoint the router talks to, all drawn
+// from the same environment
+type BinanceURLs struct {
+	SpotREST    string
+	FuturesREST string
+	SpotWS      string
+	FuturesWS   string
+	WSAPI       string
+}
+
+// Binance testnet endpoints
+var binanceTestnetURLs = BinanceURLs{
+	SpotREST:    "https://testnet.binance.vision",
+	FuturesREST: "https://testnet.binancefuture.com",
+	SpotWS:      "wss://testnet.binance.vision",
+	FuturesWS:   "wss://stream.binancefuture.com",
+	WSAPI:       "wss://ws-api.testnet.binance.vision/ws-api/v3",
+}
+
+// ResolveBinanceURLs returns the endpoints for the configured environment.
+// Testnet always uses the fixed testnet set so spot and futures cannot be
+// split across environments; mainnet uses the configured (default mainnet) URLs.
+func (c *Config) ResolveBinanceURLs() BinanceURLs {
 	if c.Binance.Testnet {
-		c.Binance.BaseURL = "https://testnet.binance.vision"
-		c.Binance.WSBaseURL = "wss://testnet.binance.vision"
-		c.Binance.FuturesBaseURL = "https://testnet.binancefuture.com"
-		c.Binance.FuturesWSURL = "wss://stream.binancefuture.com"
+		return binanceTestnetURLs
+	}
+
+	return BinanceURLs{
+		SpotREST:    c.Binance.BaseURL,
+		FuturesREST: c.Binance.FuturesBaseURL,
+		SpotWS:      c.Binance.WSBaseURL,
+		FuturesWS:   c.Binance.FuturesWSURL,
+		WSAPI:       c.Binance.WSAPIURL,
 	}
 }
 
@@ -259,6 +644,15 @@ func getEnvAsInt64(key string, defaultValue int64) int64 {
 	return defaultValue
 }
 
+func getEnvAsFloat(key string, defaultValue float64) float64 {
+	if value := os.Getenv(key); value != "" {
+		if floatValue, err := strconv.ParseFloat(value, 64); err == nil {
+			return floatValue
+		}
+	}
+	return defaultValue
+}
+
 func getEnvAsBool(key string, defaultValue bool) bool {
 	if value := os.Getenv(key); value != "" {
 		if boolValue, err := strconv.ParseBool(value); err == nil {
@@ -268,14 +662,13 @@ func getEnvAsBool(key string, defaultValue bool) bool {
 	return defaultValue
 }
 
-func getEnvAsDuration(key string, defaultValue string) time.Duration {
+func getEnvAsDuration(key string, defaultValue time.Duration) time.Duration {
 	if value := os.Getenv(key); value != "" {
 		if duration, err := time.ParseDuration(value); err == nil {
 			return duration
 		}
 	}
-	duration, _ := time.ParseDuration(defaultValue)
-	return duration
+	return defaultValue
 }
 
 func getEnvAsSlice(key string, defaultValue []string) []string {
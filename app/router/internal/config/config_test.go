@@ -1,8 +1,11 @@
 package config
 
 import (
+	"errors"
 	"os"
+	"path/filepath"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -113,3 +116,384 @@ func TestBinanceConfig_TradingModes(t *testing.T) {
 		assert.True(t, config.IsFuturesEnabled())
 	})
 }
+
+func TestConfig_ResolveBinanceURLs(t *testing.T) {
+	t.Run("mainnet uses configured mainnet endpoints", func(t *testing.T) {
+		config := defaultConfig()
+
+		urls := config.ResolveBinanceURLs()
+		assert.Equal(t, "https://api.binance.com", urls.SpotREST)
+		assert.Equal(t, "https://fapi.binance.com", urls.FuturesREST)
+		assert.Equal(t, "wss://stream.binance.com:9443", urls.SpotWS)
+		assert.Equal(t, "wss://fstream.binance.com", urls.FuturesWS)
+		assert.Equal(t, "wss://ws-api.binance.com:443/ws-api/v3", urls.WSAPI)
+	})
+
+	t.Run("testnet switches every endpoint together", func(t *testing.T) {
+		config := defaultConfig()
+		config.Binance.Testnet = true
+		// A stray mainnet override must not leak into a testnet run
+		config.Binance.FuturesBaseURL = "https://fapi.binance.com"
+
+		urls := config.ResolveBinanceURLs()
+		assert.Equal(t, "https://testnet.binance.vision", urls.SpotREST)
+		assert.Equal(t, "https://testnet.binancefuture.com", urls.FuturesREST)
+		assert.Equal(t, "wss://testnet.binance.vision", urls.SpotWS)
+		assert.Equal(t, "wss://stream.binancefuture.com", urls.FuturesWS)
+		assert.Equal(t, "wss://ws-api.testnet.binance.vision/ws-api/v3", urls.WSAPI)
+	})
+
+	t.Run("does not mutate config", func(t *testing.T) {
+		config := defaultConfig()
+		config.Binance.Testnet = true
+
+		config.ResolveBinanceURLs()
+		assert.Equal(t, "https://api.binance.com", config.Binance.BaseURL)
+	})
+}
+
+func writeConfigFile(t *testing.T, name, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), name)
+	require.NoError(t, os.WriteFile(path, []byte(content), 0o600))
+	return path
+}
+
+func TestLoad_ConfigFile(t *testing.T) {
+	t.Run("loads values from yaml file only", func(t *testing.T) {
+		path := writeConfigFile(t, "config.yaml", `
+server:
+  port: 9000
+  read_timeout: 15s
+binance:
+  trading_mode: spot
+  spot_api_key: file-spot-key
+  spot_secret_key: file-spot-secret
+logging:
+  level: debug
+`)
+		os.Setenv("CONFIG_PATH", path)
+		defer os.Unsetenv("CONFIG_PATH")
+
+		config, err := Load()
+		require.NoError(t, err)
+		assert.Equal(t, 9000, config.Server.Port)
+		assert.Equal(t, 15*time.Second, config.Server.ReadTimeout)
+		assert.Equal(t, "file-spot-key", config.Binance.SpotAPIKey)
+		assert.Equal(t, "debug", config.Logging.Level)
+
+		// Defaults fill fields missing from the file
+		assert.Equal(t, 30*time.Second, config.Server.WriteTimeout)
+		assert.Equal(t, "0.0.0.0", config.Server.Host)
+		assert.Equal(t, 6379, config.Redis.Port)
+	})
+
+	t.Run("loads values from json file", func(t *testing.T) {
+		path := writeConfigFile(t, "config.json", `{
+			"server": {"port": 9100},
+			"binance": {"testnet": true, "trading_mode": "futures"}
+		}`)
+		os.Setenv("CONFIG_PATH", path)
+		defer os.Unsetenv("CONFIG_PATH")
+
+		config, err := Load()
+		require.NoError(t, err)
+		assert.Equal(t, 9100, config.Server.Port)
+		assert.True(t, config.Binance.Testnet)
+	})
+
+	t.Run("environment overrides file values", func(t *testing.T) {
+		path := writeConfigFile(t, "config.yaml", `
+server:
+  port: 9000
+binance:
+  trading_mode: spot
+  spot_api_key: file-spot-key
+  spot_secret_key: file-spot-secret
+`)
+		os.Setenv("CONFIG_PATH", path)
+		os.Setenv("SERVER_PORT", "9500")
+		os.Setenv("BINANCE_SPOT_API_KEY", "env-spot-key")
+		defer func() {
+			os.Unsetenv("CONFIG_PATH")
+			os.Unsetenv("SERVER_PORT")
+			os.Unsetenv("BINANCE_SPOT_API_KEY")
+		}()
+
+		config, err := Load()
+		require.NoError(t, err)
+		assert.Equal(t, 9500, config.Server.Port)
+		assert.Equal(t, "env-spot-key", config.Binance.SpotAPIKey)
+		assert.Equal(t, "file-spot-secret", config.Binance.SpotSecretKey)
+	})
+
+	t.Run("reports all missing required fields", func(t *testing.T) {
+		path := writeConfigFile(t, "config.yaml", `
+server:
+  port: 70000
+binance:
+  trading_mode: spot,futures
+`)
+		os.Setenv("CONFIG_PATH", path)
+		defer os.Unsetenv("CONFIG_PATH")
+
+		_, err := Load()
+		require.Error(t, err)
+
+		var verr *ValidationError
+		require.True(t, errors.As(err, &verr))
+		assert.Len(t, verr.Errors, 5)
+		assert.Contains(t, err.Error(), "BINANCE_SPOT_API_KEY is required")
+		assert.Contains(t, err.Error(), "BINANCE_SPOT_SECRET_KEY is required")
+		assert.Contains(t, err.Error(), "BINANCE_FUTURES_API_KEY is required")
+		assert.Contains(t, err.Error(), "BINANCE_FUTURES_SECRET_KEY is required")
+		assert.Contains(t, err.Error(), "invalid server port: 70000")
+	})
+
+	t.Run("does not require credentials on testnet", func(t *testing.T) {
+		path := writeConfigFile(t, "config.yaml", "binance:\n  testnet: true\n")
+		os.Setenv("CONFIG_PATH", path)
+		defer os.Unsetenv("CONFIG_PATH")
+
+		_, err := Load()
+		assert.NoError(t, err)
+	})
+
+	t.Run("returns error for missing file", func(t *testing.T) {
+		os.Setenv("CONFIG_PATH", filepath.Join(t.TempDir(), "missing.yaml"))
+		defer os.Unsetenv("CONFIG_PATH")
+
+		_, err := Load()
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "failed to read config file")
+	})
+}
+
+func TestConfig_ValidateBounds(t *testing.T) {
+	validConfig := func() *Config {
+		config := defaultConfig()
+		config.Binance.Testnet = true
+		return config
+	}
+
+	testCases := []struct {
+		name     string
+		mutate   func(c *Config)
+		expected string
+	}{
+		{"zero read timeout", func(c *Config) { c.Server.ReadTimeout = 0 }, "server read timeout must be positive"},
+		{"zero write timeout", func(c *Config) { c.Server.WriteTimeout = 0 }, "server write timeout must be positive"},
+		{"zero request timeout", func(c *Config) { c.Server.RequestTimeout = 0 }, "server request timeout must be positive"},
+		{"request timeout not within write timeout", func(c *Config) { c.Server.RequestTimeout = c.Server.WriteTimeout }, "server request timeout 30s must be shorter than the write timeout 30s"},
+		{"negative idle timeout", func(c *Config) { c.Server.IdleTimeout = -time.Second }, "server idle timeout must be positive"},
+		{"zero shutdown timeout", func(c *Config) { c.Server.ShutdownTimeout = 0 }, "server shutdown timeout must be positive"},
+		{"zero binance timeout", func(c *Config) { c.Binance.Timeout = 0 }, "binance timeout must be positive"},
+		{"zero rate limit", func(c *Config) { c.Security.RateLimit = 0 }, "rate limit must be positive"},
+		{"port out of range", func(c *Config) { c.Server.Port = 65536 }, "invalid server port"},
+		{"negative bracket limit", func(c *Config) { c.Trading.MaxBracketsPerSymbol = -1 }, "max brackets per symbol must not be negative"},
+		{"negative take profit limit", func(c *Config) { c.Trading.MaxTakeProfitLegs = -1 }, "max take profit legs must not be negative"},
+		{"negative notional cap", func(c *Config) { c.Trading.DailyNotionalCap = -1 }, "daily notional cap must not be negative"},
+		{"negative account cache TTL", func(c *Config) { c.Binance.AccountCacheTTL = -time.Second }, "account cache TTL must not be negative"},
+		{"reset offset past a day", func(c *Config) { c.Trading.NotionalResetOffset = 25 * time.Hour }, "notional reset offset must be within"},
+		{"paper trading futures", func(c *Config) {
+			c.Trading.PaperTrading = true
+			c.Trading.PaperSymbols = []string{"BTCUSDT"}
+			c.Trading.Venues = []string{VenueSpot, VenueFutures}
+		}, "paper trading only supports the spot venue"},
+		{"paper trading without symbols", func(c *Config) {
+			c.Trading.PaperTrading = true
+			c.Trading.Venues = []string{VenueSpot}
+		}, "paper trading requires at least one paper symbol"},
+		{"paper trading with a trading profile", func(c *Config) {
+			c.Trading.PaperTrading = true
+			c.Trading.PaperSymbols = []string{"BTCUSDT"}
+			c.Trading.Venues = []string{VenueSpot}
+			c.Binance.Profiles = []APIKeyProfile{{Name: "momentum", Venue: VenueSpot, APIKey: "k", SecretKey: "s"}}
+		}, `API key profile "momentum" must be read_only with paper trading`},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			config := validConfig()
+			tc.mutate(config)
+
+			err := config.Validate()
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tc.expected)
+		})
+	}
+
+	t.Run("combines multiple errors", func(t *testing.T) {
+		config := validConfig()
+		config.Server.ReadTimeout = 0
+		config.Security.RateLimit = 0
+
+		err := config.Validate()
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "server read timeout must be positive")
+		assert.Contains(t, err.Error(), "rate limit must be positive")
+	})
+
+	t.Run("accepts defaults", func(t *testing.T) {
+		assert.NoError(t, validConfig().Validate())
+	})
+
+	t.Run("accepts spot paper trading", func(t *testing.T) {
+		config := validConfig()
+		config.Trading.PaperTrading = true
+		config.Trading.PaperSymbols = []string{"BTCUSDT"}
+		config.Trading.Venues = []string{VenueSpot}
+		assert.NoError(t, config.Validate())
+	})
+
+	t.Run("accepts read-only profiles with paper trading", func(t *testing.T) {
+		config := validConfig()
+		config.Trading.PaperTrading = true
+		config.Trading.PaperSymbols = []string{"BTCUSDT"}
+		config.Trading.Venues = []string{VenueSpot}
+		config.Binance.Profiles = []APIKeyProfile{{Name: "data", Venue: VenueSpot, APIKey: "k", SecretKey: "s", ReadOnly: true}}
+		assert.NoError(t, config.Validate())
+	})
+}
+
+func TestConfig_TradingVenues(t *testing.T) {
+	t.Run("spot-only venue does not require futures credentials", func(t *testing.T) {
+		path := writeConfigFile(t, "config.yaml", `
+trading:
+  venues: [spot]
+binance:
+  spot_api_key: key
+  spot_secret_key: secret
+`)
+		os.Setenv("CONFIG_PATH", path)
+		defer os.Unsetenv("CONFIG_PATH")
+
+		config, err := Load()
+		require.NoError(t, err)
+		assert.True(t, config.IsVenueEnabled(VenueSpot))
+		assert.False(t, config.IsVenueEnabled(VenueFutures))
+		assert.False(t, config.Binance.IsFuturesEnabled())
+	})
+
+	t.Run("venues from environment", func(t *testing.T) {
+		os.Setenv("TRADING_VENUES", "Futures")
+		os.Setenv("BINANCE_FUTURES_API_KEY", "key")
+		os.Setenv("BINANCE_FUTURES_SECRET_KEY", "secret")
+		defer func() {
+			os.Unsetenv("TRADING_VENUES")
+			os.Unsetenv("BINANCE_FUTURES_API_KEY")
+			os.Unsetenv("BINANCE_FUTURES_SECRET_KEY")
+		}()
+
+		config, err := Load()
+		require.NoError(t, err)
+		assert.Equal(t, []string{VenueFutures}, config.Trading.Venues)
+		assert.False(t, config.IsVenueEnabled(VenueSpot))
+	})
+
+	t.Run("falls back to trading mode when venues unset", func(t *testing.T) {
+		config := &Config{Binance: BinanceConfig{TradingMode: "futures"}}
+		assert.False(t, config.IsVenueEnabled(VenueSpot))
+		assert.True(t, config.IsVenueEnabled(VenueFutures))
+	})
+
+	t.Run("bracket limit from environment", func(t *testing.T) {
+		os.Setenv("MAX_BRACKETS_PER_SYMBOL", "3")
+		os.Setenv("BINANCE_TESTNET", "true")
+		defer func() {
+			os.Unsetenv("MAX_BRACKETS_PER_SYMBOL")
+			os.Unsetenv("BINANCE_TESTNET")
+		}()
+
+		config, err := Load()
+		require.NoError(t, err)
+		assert.Equal(t, 3, config.Trading.MaxBracketsPerSymbol)
+	})
+
+	t.Run("rejects unknown venue", func(t *testing.T) {
+		config := defaultConfig()
+		config.Binance.Testnet = true
+		config.Trading.Venues = []string{"margin"}
+
+		err := config.Validate()
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), `invalid trading venue "margin"`)
+		assert.Contains(t, err.Error(), "at least one trading venue must be enabled")
+	})
+}
+
+func TestLoad_APIKeyProfiles(t *testing.T) {
+	t.Run("loads profiles from file and environment", func(t *testing.T) {
+		path := writeConfigFile(t, "config.yaml", `
+binance:
+  testnet: true
+  profiles:
+    - name: data
+      venue: spot
+      api_key: file-data-key
+      secret_key: file-data-secret
+      read_only: true
+`)
+		os.Setenv("CONFIG_PATH", path)
+		os.Setenv("BINANCE_API_PROFILES", "data,momentum")
+		os.Setenv("BINANCE_PROFILE_DATA_API_KEY", "env-data-key")
+		os.Setenv("BINANCE_PROFILE_MOMENTUM_VENUE", "futures")
+		os.Setenv("BINANCE_PROFILE_MOMENTUM_API_KEY", "momentum-key")
+		os.Setenv("BINANCE_PROFILE_MOMENTUM_SECRET_KEY", "momentum-secret")
+		defer func() {
+			os.Unsetenv("CONFIG_PATH")
+			os.Unsetenv("BINANCE_API_PROFILES")
+			os.Unsetenv("BINANCE_PROFILE_DATA_API_KEY")
+			os.Unsetenv("BINANCE_PROFILE_MOMENTUM_VENUE")
+			os.Unsetenv("BINANCE_PROFILE_MOMENTUM_API_KEY")
+			os.Unsetenv("BINANCE_PROFILE_MOMENTUM_SECRET_KEY")
+		}()
+
+		config, err := Load()
+		require.NoError(t, err)
+		require.Len(t, config.Binance.Profiles, 2)
+
+		data, ok := config.Binance.Profile("data")
+		require.True(t, ok)
+		assert.Equal(t, "env-data-key", data.APIKey)
+		assert.Equal(t, "file-data-secret", data.SecretKey)
+		assert.True(t, data.ReadOnly)
+		assert.False(t, data.IsFutures())
+
+		momentum, ok := config.Binance.Profile("momentum")
+		require.True(t, ok)
+		assert.Equal(t, "momentum-key", momentum.APIKey)
+		assert.False(t, momentum.ReadOnly)
+		assert.True(t, momentum.IsFutures())
+
+		_, ok = config.Binance.Profile("missing")
+		assert.False(t, ok)
+	})
+
+	t.Run("reports invalid profiles", func(t *testing.T) {
+		path := writeConfigFile(t, "config.yaml", `
+binance:
+  testnet: true
+  profiles:
+    - name: data
+      venue: spot
+      api_key: key
+      secret_key: secret
+    - name: data
+      venue: options
+      api_key: key
+    - venue: spot
+`)
+		os.Setenv("CONFIG_PATH", path)
+		defer os.Unsetenv("CONFIG_PATH")
+
+		_, err := Load()
+		var verr *ValidationError
+		require.True(t, errors.As(err, &verr))
+		assert.Len(t, verr.Errors, 4)
+		assert.Contains(t, err.Error(), `duplicate API key profile "data"`)
+		assert.Contains(t, err.Error(), `API key profile "data" has invalid venue "options"`)
+		assert.Contains(t, err.Error(), `API key profile "data" requires both an API key and a secret key`)
+		assert.Contains(t, err.Error(), "API key profile name must not be empty")
+	})
+}
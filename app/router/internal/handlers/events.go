@@ -0,0 +1,188 @@
+package handlers
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gorilla/websocket"
+	"router/internal/models"
+)
+
+// DefaultEventHeartbeat is how often relay connections are kept alive: an
+// idle SSE stream sends a comment and a WebSocket relay sends a ping
+const DefaultEventHeartbeat = 15 * time.Second
+
+// relayWriteWait bounds how long a write to a WebSocket relay client may
+// block before the client is considered gone
+const relayWriteWait = 10 * time.Second
+
+// RelayTokenProtocol is the WebSocket subprotocol a browser offers, followed
+// by its relay token, to authenticate an upgrade it cannot add X-API-Key to:
+//
+//	new WebSocket(url, ["relay-token", token])
+const RelayTokenProtocol = "relay-token"
+
+// EventRelay lets HTTP clients follow the events a stream receives
+type EventRelay interface {
+	// SubscribeEvents registers a subscriber for the stream's events. The
+	// returned function unregisters it; the channel closes with the stream.
+	SubscribeEvents(streamID string) (<-chan models.StreamEvent, func(), error)
+}
+
+// EventHandlers relays stream events to HTTP clients over Server-Sent Events
+// or WebSocket
+type EventHandlers struct {
+	relay     EventRelay
+	heartbeat time.Duration
+	upgrader  websocket.Upgrader
+}
+
+// NewEventHandlers creates event handlers that keep connections alive every
+// heartbeat. WebSocket upgrades are accepted from the same origin only until
+// SetOriginCheck widens that.
+func NewEventHandlers(relay EventRelay, heartbeat time.Duration) *EventHandlers {
+	return &EventHandlers{
+		relay:     relay,
+		heartbeat: heartbeat,
+		upgrader: websocket.Upgrader{
+			// Echoed back so browsers that authenticate with a relay token
+			// accept the handshake
+			Subprotocols: []string{RelayTokenProtocol},
+		},
+	}
+}
+
+// SetOriginCheck sets which origins may open a WebSocket relay
+func (h *EventHandlers) SetOriginCheck(check func(r *http.Request) bool) {
+	h.upgrader.CheckOrigin = check
+}
+
+// StreamEvents streams a stream's depth and ticker events as SSE data frames
+// until the client disconnects or the stream is closed
+func (h *EventHandlers) StreamEvents() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		events, unsubscribe, ok := h.subscribe(c)
+		if !ok {
+			return
+		}
+		defer unsubscribe()
+
+		// The server's write timeout would otherwise cut the stream off
+		_ = http.NewResponseController(c.Writer).SetWriteDeadline(time.Time{})
+
+		c.Header("Content-Type", "text/event-stream")
+		c.Header("Cache-Control", "no-cache")
+		c.Header("Connection", "keep-alive")
+		c.Header("X-Accel-Buffering", "no")
+		c.Status(http.StatusOK)
+		c.Writer.Flush()
+
+		heartbeat := time.NewTicker(h.heartbeat)
+		defer heartbeat.Stop()
+
+		for {
+			select {
+			case <-c.Request.Context().Done():
+				return
+			case <-heartbeat.C:
+				fmt.Fprint(c.Writer, ": heartbeat\n\n")
+			case event, ok := <-events:
+				if !ok {
+					return
+				}
+				data, err := json.Marshal(event)
+				if err != nil {
+					continue
+				}
+				fmt.Fprintf(c.Writer, "data: %s\n\n", data)
+				heartbeat.Reset(h.heartbeat)
+			}
+			c.Writer.Flush()
+		}
+	}
+}
+
+// StreamWebSocket upgrades to a WebSocket and relays the stream's events as
+// JSON text messages. Clients that cannot keep up are disconnected rather
+// than holding back the stream.
+func (h *EventHandlers) StreamWebSocket() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		events, unsubscribe, ok := h.subscribe(c)
+		if !ok {
+			return
+		}
+		defer unsubscribe()
+
+		conn, err := h.upgrader.Upgrade(c.Writer, c.Request, nil)
+		if err != nil {
+			// The upgrader has already replied with an error status
+			return
+		}
+		defer conn.Close()
+
+		// Relay clients only receive; reading surfaces the disconnect and
+		// lets pongs and close frames be processed
+		disconnected := make(chan struct{})
+		go func() {
+			defer close(disconnected)
+			for {
+				if _, _, err := conn.NextReader(); err != nil {
+					return
+				}
+			}
+		}()
+
+		ping := time.NewTicker(h.heartbeat)
+		defer ping.Stop()
+
+		for {
+			select {
+			case <-disconnected:
+				return
+			case <-ping.C:
+				if err := conn.WriteControl(websocket.PingMessage, nil, time.Now().Add(relayWriteWait)); err != nil {
+					return
+				}
+			case event, ok := <-events:
+				if !ok {
+					closing := websocket.FormatCloseMessage(websocket.CloseGoingAway, "relay ended")
+					_ = conn.WriteControl(websocket.CloseMessage, closing, time.Now().Add(relayWriteWait))
+					return
+				}
+				_ = conn.SetWriteDeadline(time.Now().Add(relayWriteWait))
+				if err := conn.WriteJSON(event); err != nil {
+					return
+				}
+			}
+		}
+	}
+}
+
+// subscribe registers the request with the relay, replying with an error
+// when the stream cannot be followed
+func (h *EventHandlers) subscribe(c *gin.Context) (<-chan models.StreamEvent, func(), bool) {
+	events, unsubscribe, err := h.relay.SubscribeEvents(c.Param("id"))
+	if err == nil {
+		return events, unsubscribe, true
+	}
+
+	if strings.Contains(err.Error(), "not found") {
+		c.JSON(http.StatusNotFound, models.NewErrorResponse(
+			"NOT_FOUND",
+			"Stream not found",
+			c.GetString("request_id"),
+		))
+		return nil, nil, false
+	}
+
+	c.JSON(http.StatusInternalServerError, models.NewErrorResponse(
+		"STREAM_ERROR",
+		"Failed to subscribe to stream events",
+		c.GetString("request_id"),
+	))
+	return nil, nil, false
+}
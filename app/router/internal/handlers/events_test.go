@@ -0,0 +1,183 @@
+package handlers
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gorilla/websocket"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"router/internal/models"
+)
+
+// fakeEventRelay hands out one channel per subscriber and tracks which are
+// still registered
+type fakeEventRelay struct {
+	mu          sync.Mutex
+	subscribers map[chan models.StreamEvent]struct{}
+	err         error
+}
+
+func newFakeEventRelay() *fakeEventRelay {
+	return &fakeEventRelay{subscribers: make(map[chan models.StreamEvent]struct{})}
+}
+
+func (r *fakeEventRelay) SubscribeEvents(streamID string) (<-chan models.StreamEvent, func(), error) {
+	if r.err != nil {
+		return nil, nil, r.err
+	}
+
+	ch := make(chan models.StreamEvent, 8)
+	r.mu.Lock()
+	r.subscribers[ch] = struct{}{}
+	r.mu.Unlock()
+
+	return ch, func() {
+		r.mu.Lock()
+		delete(r.subscribers, ch)
+		r.mu.Unlock()
+	}, nil
+}
+
+func (r *fakeEventRelay) publish(event models.StreamEvent) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for ch := range r.subscribers {
+		ch <- event
+	}
+}
+
+func (r *fakeEventRelay) count() int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return len(r.subscribers)
+}
+
+func newEventServer(t *testing.T, relay EventRelay, heartbeat time.Duration) *httptest.Server {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	handler := NewEventHandlers(relay, heartbeat)
+	router.GET("/streams/:id/events", handler.StreamEvents())
+	router.GET("/streams/:id/ws", handler.StreamWebSocket())
+
+	server := httptest.NewServer(router)
+	t.Cleanup(server.Close)
+	return server
+}
+
+// readFrame returns the next non-empty SSE line
+func readFrame(t *testing.T, reader *bufio.Reader) string {
+	for {
+		line, err := reader.ReadString('\n')
+		require.NoError(t, err)
+		if line = strings.TrimSpace(line); line != "" {
+			return line
+		}
+	}
+}
+
+func TestStreamEvents(t *testing.T) {
+	t.Run("relays events as data frames", func(t *testing.T) {
+		relay := newFakeEventRelay()
+		server := newEventServer(t, relay, time.Minute)
+
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, server.URL+"/streams/stream-1/events", nil)
+		require.NoError(t, err)
+
+		resp, err := http.DefaultClient.Do(req)
+		require.NoError(t, err)
+		defer resp.Body.Close()
+
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+		assert.Equal(t, "text/event-stream", resp.Header.Get("Content-Type"))
+		require.Equal(t, 1, relay.count())
+
+		relay.publish(models.StreamEvent{Stream: "btcusdt@ticker", Data: json.RawMessage(`{"e":"24hrTicker"}`)})
+		relay.publish(models.StreamEvent{Stream: "btcusdt@depth", Data: json.RawMessage(`{"e":"depthUpdate"}`)})
+
+		reader := bufio.NewReader(resp.Body)
+		for _, want := range []string{"btcusdt@ticker", "btcusdt@depth"} {
+			frame := readFrame(t, reader)
+			require.True(t, strings.HasPrefix(frame, "data: "), frame)
+
+			var event models.StreamEvent
+			require.NoError(t, json.Unmarshal([]byte(strings.TrimPrefix(frame, "data: ")), &event))
+			assert.Equal(t, want, event.Stream)
+		}
+
+		// Disconnecting unregisters the subscriber
+		cancel()
+		assert.Eventually(t, func() bool { return relay.count() == 0 }, time.Second, 10*time.Millisecond)
+	})
+
+	t.Run("sends heartbeats while idle", func(t *testing.T) {
+		relay := newFakeEventRelay()
+		server := newEventServer(t, relay, 20*time.Millisecond)
+
+		resp, err := http.Get(server.URL + "/streams/stream-1/events")
+		require.NoError(t, err)
+		defer resp.Body.Close()
+
+		assert.Equal(t, ": heartbeat", readFrame(t, bufio.NewReader(resp.Body)))
+	})
+
+	t.Run("unknown stream", func(t *testing.T) {
+		relay := newFakeEventRelay()
+		relay.err = errors.New("stream stream-9 not found")
+		server := newEventServer(t, relay, time.Minute)
+
+		resp, err := http.Get(server.URL + "/streams/stream-9/events")
+		require.NoError(t, err)
+		defer resp.Body.Close()
+
+		assert.Equal(t, http.StatusNotFound, resp.StatusCode)
+	})
+}
+
+func TestStreamWebSocket(t *testing.T) {
+	t.Run("relays events as JSON messages", func(t *testing.T) {
+		relay := newFakeEventRelay()
+		server := newEventServer(t, relay, time.Minute)
+
+		wsURL := strings.Replace(server.URL, "http://", "ws://", 1) + "/streams/stream-1/ws"
+		conn, _, err := websocket.DefaultDialer.Dial(wsURL, nil)
+		require.NoError(t, err)
+		defer conn.Close()
+		require.Equal(t, 1, relay.count())
+
+		relay.publish(models.StreamEvent{Stream: "btcusdt@ticker", Data: json.RawMessage(`{"e":"24hrTicker"}`)})
+
+		var event models.StreamEvent
+		require.NoError(t, conn.SetReadDeadline(time.Now().Add(2*time.Second)))
+		require.NoError(t, conn.ReadJSON(&event))
+		assert.Equal(t, "btcusdt@ticker", event.Stream)
+		assert.JSONEq(t, `{"e":"24hrTicker"}`, string(event.Data))
+
+		// Disconnecting unregisters the subscriber
+		conn.Close()
+		assert.Eventually(t, func() bool { return relay.count() == 0 }, time.Second, 10*time.Millisecond)
+	})
+
+	t.Run("unknown stream", func(t *testing.T) {
+		relay := newFakeEventRelay()
+		relay.err = errors.New("stream stream-9 not found")
+		server := newEventServer(t, relay, time.Minute)
+
+		wsURL := strings.Replace(server.URL, "http://", "ws://", 1) + "/streams/stream-9/ws"
+		_, resp, err := websocket.DefaultDialer.Dial(wsURL, nil)
+		require.Error(t, err)
+		require.NotNil(t, resp)
+		assert.Equal(t, http.StatusNotFound, resp.StatusCode)
+	})
+}
@@ -2,6 +2,7 @@ package handlers
 
 import (
 	"net/http"
+	"runtime"
 	"time"
 
 	"github.com/gin-gonic/gin"
@@ -47,6 +48,20 @@ func (h *HealthHandlers) HealthCheck() gin.HandlerFunc {
 	}
 }
 
+// Version returns a handler reporting the build the service is running
+func (h *HealthHandlers) Version(gitCommit, buildTime string) gin.HandlerFunc {
+	response := models.VersionResponse{
+		Version:   h.version,
+		GitCommit: gitCommit,
+		GoVersion: runtime.Version(),
+		BuildTime: buildTime,
+	}
+
+	return func(c *gin.Context) {
+		c.JSON(http.StatusOK, response)
+	}
+}
+
 // Readiness returns a handler for readiness check endpoint
 func (h *HealthHandlers) Readiness(checker ReadinessChecker) gin.HandlerFunc {
 	return func(c *gin.Context) {
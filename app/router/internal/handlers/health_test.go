@@ -4,6 +4,7 @@ import (
 	"encoding/json"
 	"net/http"
 	"net/http/httptest"
+	"runtime"
 	"testing"
 	"time"
 
@@ -57,6 +58,29 @@ func TestHealthCheckHandler(t *testing.T) {
 	})
 }
 
+func TestVersionHandler(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	router := gin.New()
+	h := NewHealthHandlers("2.1.0", time.Now())
+	router.GET("/version", h.Version("abc1234", "2024-05-01T12:00:00Z"))
+
+	req := httptest.NewRequest("GET", "/version", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	var resp models.VersionResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	assert.Equal(t, models.VersionResponse{
+		Version:   "2.1.0",
+		GitCommit: "abc1234",
+		GoVersion: runtime.Version(),
+		BuildTime: "2024-05-01T12:00:00Z",
+	}, resp)
+}
+
 func TestReadinessHandler(t *testing.T) {
 	gin.SetMode(gin.TestMode)
 
@@ -14,6 +14,9 @@ type StreamManager interface {
 	CreateStream(streamType string, subscriptions []string) (*models.StreamResponse, error)
 	GetStream(id string) (*models.StreamResponse, error)
 	ListStreams(filterType string, page, limit int) ([]models.StreamResponse, int, error)
+	// ListStreamsAfter returns up to limit streams ordered after cursor, or
+	// from the start when cursor is empty, and the cursor for the next page
+	ListStreamsAfter(filterType, cursor string, limit int) ([]models.StreamResponse, string, error)
 	CloseStream(id string) error
 	ReconnectStream(id string) (*models.StreamResponse, error)
 }
@@ -131,6 +134,32 @@ func (h *StreamHandlers) ListStreams() gin.HandlerFunc {
 			}
 		}
 
+		// A cursor parameter, even an empty one, selects cursor pagination,
+		// which stays consistent while streams are created and closed
+		if cursor, ok := c.GetQuery("cursor"); ok {
+			streams, nextCursor, err := h.manager.ListStreamsAfter(filterType, cursor, limit)
+			if err != nil {
+				if strings.Contains(err.Error(), "invalid cursor") {
+					c.JSON(http.StatusBadRequest, models.NewErrorResponse(
+						"VALIDATION_ERROR",
+						"Invalid cursor",
+						c.GetString("request_id"),
+					))
+					return
+				}
+
+				c.JSON(http.StatusInternalServerError, models.NewErrorResponse(
+					"STREAM_ERROR",
+					"Failed to list streams",
+					c.GetString("request_id"),
+				))
+				return
+			}
+
+			c.JSON(http.StatusOK, models.NewCursorListResponse(streams, len(streams), limit, nextCursor))
+			return
+		}
+
 		// Get streams
 		streams, total, err := h.manager.ListStreams(filterType, page, limit)
 		if err != nil {
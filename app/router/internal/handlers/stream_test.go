@@ -108,6 +108,33 @@ func TestCreateStream(t *testing.T) {
 		assert.Equal(t, "VALIDATION_ERROR", resp.Error)
 	})
 
+	t.Run("returns 400 listing malformed stream names", func(t *testing.T) {
+		manager := &mockStreamManager{}
+		handler := NewStreamHandlers(manager)
+		router := gin.New()
+		router.POST("/streams", handler.CreateStream())
+
+		reqBody := models.CreateStreamRequest{
+			Type:          "public",
+			Subscriptions: []string{"btcusdt@ticker", "btcusdt@dept"},
+		}
+		body, _ := json.Marshal(reqBody)
+
+		req := httptest.NewRequest("POST", "/streams", bytes.NewBuffer(body))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+
+		var resp models.ErrorResponse
+		err := json.Unmarshal(w.Body.Bytes(), &resp)
+		require.NoError(t, err)
+		assert.Equal(t, "VALIDATION_ERROR", resp.Error)
+		assert.Contains(t, resp.Message, "btcusdt@dept")
+		assert.NotContains(t, resp.Message, "btcusdt@ticker")
+	})
+
 	t.Run("returns 503 when stream creation fails", func(t *testing.T) {
 		manager := &mockStreamManager{
 			createError: errors.New("connection failed"),
@@ -255,6 +282,50 @@ func TestListStreams(t *testing.T) {
 		assert.Equal(t, 2, resp.Count)
 	})
 
+	t.Run("uses cursor pagination when a cursor is given", func(t *testing.T) {
+		manager := &mockStreamManager{
+			listResponse: []models.StreamResponse{
+				{ID: "stream-3", Type: "public", Status: "connected"},
+			},
+			nextCursor: "next-page",
+		}
+
+		handler := NewStreamHandlers(manager)
+		router := gin.New()
+		router.GET("/streams", handler.ListStreams())
+
+		req := httptest.NewRequest("GET", "/streams?cursor=this-page&limit=1&type=public", nil)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, "this-page", manager.cursor)
+		assert.Equal(t, "public", manager.filterType)
+
+		var resp models.CursorListResponse
+		err := json.Unmarshal(w.Body.Bytes(), &resp)
+		require.NoError(t, err)
+		assert.Equal(t, 1, resp.Count)
+		assert.Equal(t, 1, resp.PageSize)
+		assert.Equal(t, "next-page", resp.NextCursor)
+	})
+
+	t.Run("returns 400 for an invalid cursor", func(t *testing.T) {
+		manager := &mockStreamManager{
+			listError: errors.New("invalid cursor"),
+		}
+
+		handler := NewStreamHandlers(manager)
+		router := gin.New()
+		router.GET("/streams", handler.ListStreams())
+
+		req := httptest.NewRequest("GET", "/streams?cursor=garbage", nil)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+
 	t.Run("uses default pagination values", func(t *testing.T) {
 		manager := &mockStreamManager{
 			listResponse: []models.StreamResponse{},
@@ -403,6 +474,9 @@ type mockStreamManager struct {
 	listResponse      []models.StreamResponse
 	totalCount        int
 	filterType        string
+	cursor            string
+	nextCursor        string
+	listError         error
 	closeSuccess      bool
 	closeError        error
 	closedStreamID    string
@@ -429,6 +503,15 @@ func (m *mockStreamManager) ListStreams(filterType string, page, limit int) ([]m
 	return m.listResponse, m.totalCount, nil
 }
 
+func (m *mockStreamManager) ListStreamsAfter(filterType, cursor string, limit int) ([]models.StreamResponse, string, error) {
+	m.filterType = filterType
+	m.cursor = cursor
+	if m.listError != nil {
+		return nil, "", m.listError
+	}
+	return m.listResponse, m.nextCursor, nil
+}
+
 func (m *mockStreamManager) CloseStream(id string) error {
 	m.closedStreamID = id
 	return m.closeError
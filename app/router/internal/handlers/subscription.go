@@ -1,6 +1,7 @@
 package handlers
 
 import (
+	"encoding/json"
 	"net/http"
 	"strings"
 
@@ -11,6 +12,9 @@ import (
 // SubscriptionManager interface for managing stream subscriptions
 type SubscriptionManager interface {
 	SubscribeToMarketData(streamID, symbol string, streams []string) (*models.SubscriptionResponse, error)
+	// SubscribeBulk subscribes every request at once and returns one result
+	// per request, in order
+	SubscribeBulk(streamID string, requests []models.SubscribeRequest) ([]models.SubscriptionResponse, error)
 	SubscribeToUserData(streamID, listenKey string) (*models.SubscriptionResponse, error)
 	Unsubscribe(streamID string, req models.UnsubscribeRequest) (*models.SubscriptionResponse, error)
 	ListSubscriptions(streamID string) ([]models.SubscriptionResponse, error)
@@ -89,6 +93,78 @@ func (h *SubscriptionHandlers) SubscribeToMarketData() gin.HandlerFunc {
 	}
 }
 
+// SubscribeBulk subscribes several symbols to market data in one request.
+// Invalid entries are reported in their result without failing the others.
+func (h *SubscriptionHandlers) SubscribeBulk() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		streamID := c.Param("id")
+
+		// Decode without binding validation, which would reject the whole
+		// request for one bad entry
+		var req models.BulkSubscribeRequest
+		if err := json.NewDecoder(c.Request.Body).Decode(&req); err != nil {
+			c.JSON(http.StatusBadRequest, models.NewErrorResponse(
+				"VALIDATION_ERROR",
+				"Invalid request body",
+				c.GetString("request_id"),
+			))
+			return
+		}
+
+		if err := req.Validate(); err != nil {
+			c.JSON(http.StatusBadRequest, models.NewErrorResponse(
+				"VALIDATION_ERROR",
+				err.Error(),
+				c.GetString("request_id"),
+			))
+			return
+		}
+
+		results := make([]models.SubscriptionResponse, len(req))
+		var valid []models.SubscribeRequest
+		var positions []int
+		for i, entry := range req {
+			entry.Normalize()
+			if err := entry.Validate(); err != nil {
+				results[i] = models.SubscriptionResponse{
+					Symbol:  entry.Symbol,
+					Streams: entry.Streams,
+					Error:   err.Error(),
+				}
+				continue
+			}
+			valid = append(valid, entry)
+			positions = append(positions, i)
+		}
+
+		if len(valid) > 0 {
+			subscribed, err := h.manager.SubscribeBulk(streamID, valid)
+			if err != nil {
+				if strings.Contains(err.Error(), "not found") {
+					c.JSON(http.StatusNotFound, models.NewErrorResponse(
+						"NOT_FOUND",
+						"Stream not found",
+						c.GetString("request_id"),
+					))
+					return
+				}
+
+				c.JSON(http.StatusInternalServerError, models.NewErrorResponse(
+					"SUBSCRIPTION_ERROR",
+					"Failed to subscribe to market data",
+					c.GetString("request_id"),
+				))
+				return
+			}
+			for j, result := range subscribed {
+				results[positions[j]] = result
+			}
+		}
+
+		c.JSON(http.StatusOK, results)
+	}
+}
+
 // SubscribeToUserData subscribes to user data stream
 func (h *SubscriptionHandlers) SubscribeToUserData() gin.HandlerFunc {
 	return func(c *gin.Context) {
@@ -125,6 +125,76 @@ func TestSubscribeToMarketData(t *testing.T) {
 	})
 }
 
+func TestSubscribeBulk(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	post := func(manager *mockSubscriptionManager, body string) *httptest.ResponseRecorder {
+		handler := NewSubscriptionHandlers(manager)
+		router := gin.New()
+		router.POST("/streams/:id/subscribe/bulk", handler.SubscribeBulk())
+
+		req := httptest.NewRequest("POST", "/streams/stream-123/subscribe/bulk", bytes.NewBufferString(body))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		return w
+	}
+
+	t.Run("subscribes every entry", func(t *testing.T) {
+		manager := &mockSubscriptionManager{}
+
+		w := post(manager, `[{"symbol":"btcusdt","streams":["depth"]},{"symbol":"ETHUSDT","streams":["ticker","depth"]}]`)
+		assert.Equal(t, http.StatusOK, w.Code)
+
+		var resp []models.SubscriptionResponse
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+		require.Len(t, resp, 2)
+		assert.True(t, resp[0].Success)
+		assert.Equal(t, "BTCUSDT", resp[0].Symbol, "symbols are normalized")
+		assert.True(t, resp[1].Success)
+		assert.Len(t, manager.bulkRequests, 2)
+	})
+
+	t.Run("reports invalid entries without failing the rest", func(t *testing.T) {
+		manager := &mockSubscriptionManager{}
+
+		w := post(manager, `[
+			{"symbol":"BTC-USDT","streams":["depth"]},
+			{"symbol":"ETHUSDT","streams":["ticker"]},
+			{"symbol":"BNBUSDT","streams":["klines"]},
+			{"symbol":"SOLUSDT","streams":["depth"]}
+		]`)
+		assert.Equal(t, http.StatusOK, w.Code)
+
+		var resp []models.SubscriptionResponse
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+		require.Len(t, resp, 4)
+
+		assert.False(t, resp[0].Success)
+		assert.Contains(t, resp[0].Error, "invalid symbol")
+		assert.True(t, resp[1].Success)
+		assert.Equal(t, "ETHUSDT", resp[1].Symbol)
+		assert.False(t, resp[2].Success)
+		assert.Contains(t, resp[2].Error, "invalid stream type: klines")
+		assert.True(t, resp[3].Success)
+		assert.Equal(t, "SOLUSDT", resp[3].Symbol)
+
+		require.Len(t, manager.bulkRequests, 2, "only valid entries reach the manager")
+	})
+
+	t.Run("returns 400 for an empty request", func(t *testing.T) {
+		w := post(&mockSubscriptionManager{}, `[]`)
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+
+	t.Run("returns 404 for non-existent stream", func(t *testing.T) {
+		manager := &mockSubscriptionManager{subscribeError: errors.New("stream not found")}
+
+		w := post(manager, `[{"symbol":"BTCUSDT","streams":["depth"]}]`)
+		assert.Equal(t, http.StatusNotFound, w.Code)
+	})
+}
+
 func TestSubscribeToUserData(t *testing.T) {
 	gin.SetMode(gin.TestMode)
 
@@ -372,6 +442,7 @@ type mockSubscriptionManager struct {
 	unsubscribeError   error
 	listResponse       []models.SubscriptionResponse
 	listError          error
+	bulkRequests       []models.SubscribeRequest
 }
 
 func (m *mockSubscriptionManager) SubscribeToMarketData(streamID, symbol string, streams []string) (*models.SubscriptionResponse, error) {
@@ -381,6 +452,18 @@ func (m *mockSubscriptionManager) SubscribeToMarketData(streamID, symbol string,
 	return m.subscribeResponse, nil
 }
 
+func (m *mockSubscriptionManager) SubscribeBulk(streamID string, requests []models.SubscribeRequest) ([]models.SubscriptionResponse, error) {
+	m.bulkRequests = requests
+	if m.subscribeError != nil {
+		return nil, m.subscribeError
+	}
+	results := make([]models.SubscriptionResponse, len(requests))
+	for i, req := range requests {
+		results[i] = models.SubscriptionResponse{Success: true, Symbol: req.Symbol, Streams: req.Streams}
+	}
+	return results, nil
+}
+
 func (m *mockSubscriptionManager) SubscribeToUserData(streamID, listenKey string) (*models.SubscriptionResponse, error) {
 	if m.userDataError != nil {
 		return nil, m.userDataError
@@ -0,0 +1,96 @@
+package lifecycle
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// Group tracks background goroutines so their owner can signal them to stop
+// and wait until every one has exited
+type Group struct {
+	ctx    context.Context
+	cancel context.CancelFunc
+
+	mu      sync.Mutex
+	stopped bool
+	wg      sync.WaitGroup
+}
+
+// NewGroup creates a running group
+func NewGroup() *Group {
+	ctx, cancel := context.WithCancel(context.Background())
+	return &Group{ctx: ctx, cancel: cancel}
+}
+
+// Go runs fn in a tracked goroutine. Once Stop has been called nothing is
+// started and Go reports false.
+func (g *Group) Go(fn func()) bool {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	if g.stopped {
+		return false
+	}
+
+	g.wg.Add(1)
+	go func() {
+		defer g.wg.Done()
+		fn()
+	}()
+	return true
+}
+
+// Done returns a channel closed when the group is stopped
+func (g *Group) Done() <-chan struct{} {
+	return g.ctx.Done()
+}
+
+// Context returns a context cancelled when the group is stopped, for bounding
+// blocking calls such as dials made by tracked goroutines
+func (g *Group) Context() context.Context {
+	return g.ctx
+}
+
+// Stopped reports whether Stop has been called
+func (g *Group) Stopped() bool {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	return g.stopped
+}
+
+// Stop signals every tracked goroutine through Done. It is safe to call more
+// than once.
+func (g *Group) Stop() {
+	g.mu.Lock()
+	g.stopped = true
+	g.mu.Unlock()
+
+	g.cancel()
+}
+
+// Wait blocks until every tracked goroutine has exited or timeout elapses,
+// reporting whether they all exited. A zero timeout waits indefinitely. Call
+// it after Stop, once no new goroutines can be started.
+func (g *Group) Wait(timeout time.Duration) bool {
+	done := make(chan struct{})
+	go func() {
+		g.wg.Wait()
+		close(done)
+	}()
+
+	if timeout <= 0 {
+		<-done
+		return true
+	}
+
+	timer := time.NewTimer(timeout)
+	defer timer.Stop()
+
+	select {
+	case <-done:
+		return true
+	case <-timer.C:
+		return false
+	}
+}
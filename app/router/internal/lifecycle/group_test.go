@@ -0,0 +1,73 @@
+package lifecycle
+
+import (
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGroup(t *testing.T) {
+	t.Run("stop signals goroutines and wait joins them", func(t *testing.T) {
+		g := NewGroup()
+
+		var exited atomic.Int32
+		for i := 0; i < 5; i++ {
+			require.True(t, g.Go(func() {
+				<-g.Done()
+				exited.Add(1)
+			}))
+		}
+
+		g.Stop()
+
+		assert.True(t, g.Wait(time.Second))
+		assert.Equal(t, int32(5), exited.Load())
+		assert.Error(t, g.Context().Err())
+	})
+
+	t.Run("refuses new goroutines once stopped", func(t *testing.T) {
+		g := NewGroup()
+		g.Stop()
+		g.Stop()
+
+		ran := false
+		assert.False(t, g.Go(func() { ran = true }))
+		assert.True(t, g.Stopped())
+		assert.True(t, g.Wait(time.Second))
+		assert.False(t, ran)
+	})
+
+	t.Run("wait reports goroutines that outlive the timeout", func(t *testing.T) {
+		g := NewGroup()
+		release := make(chan struct{})
+		g.Go(func() { <-release })
+
+		g.Stop()
+		assert.False(t, g.Wait(20*time.Millisecond))
+
+		close(release)
+		assert.True(t, g.Wait(0))
+	})
+
+	t.Run("goroutines may start others before stop", func(t *testing.T) {
+		g := NewGroup()
+
+		var inner atomic.Bool
+		started := make(chan struct{})
+		g.Go(func() {
+			g.Go(func() {
+				<-g.Done()
+				inner.Store(true)
+			})
+			close(started)
+		})
+		<-started
+
+		g.Stop()
+		assert.True(t, g.Wait(time.Second))
+		assert.True(t, inner.Load())
+	})
+}
@@ -41,6 +41,9 @@ func NewCollectorWithBuckets(buckets []float64) *Collector {
 
 // RecordHTTPRequest increments the HTTP request counter
 func (c *Collector) RecordHTTPRequest(method, path string, status int) {
+	if c == nil {
+		return
+	}
 	c.mutex.Lock()
 	defer c.mutex.Unlock()
 
@@ -50,6 +53,9 @@ func (c *Collector) RecordHTTPRequest(method, path string, status int) {
 
 // RecordHTTPDuration records HTTP request duration
 func (c *Collector) RecordHTTPDuration(method, endpoint string, duration float64) {
+	if c == nil {
+		return
+	}
 	c.mutex.Lock()
 	defer c.mutex.Unlock()
 
@@ -59,6 +65,9 @@ func (c *Collector) RecordHTTPDuration(method, endpoint string, duration float64
 
 // RecordOrderLatency records order latency by exchange and type
 func (c *Collector) RecordOrderLatency(exchange, orderType string, latency float64) {
+	if c == nil {
+		return
+	}
 	c.mutex.Lock()
 	defer c.mutex.Unlock()
 
@@ -68,6 +77,9 @@ func (c *Collector) RecordOrderLatency(exchange, orderType string, latency float
 
 // RecordOrderStatus increments order status counter
 func (c *Collector) RecordOrderStatus(exchange, status string) {
+	if c == nil {
+		return
+	}
 	c.mutex.Lock()
 	defer c.mutex.Unlock()
 
@@ -77,6 +89,9 @@ func (c *Collector) RecordOrderStatus(exchange, status string) {
 
 // RecordWebSocketConnection records WebSocket connection events
 func (c *Collector) RecordWebSocketConnection(status string) {
+	if c == nil {
+		return
+	}
 	c.mutex.Lock()
 	defer c.mutex.Unlock()
 
@@ -85,6 +100,9 @@ func (c *Collector) RecordWebSocketConnection(status string) {
 
 // RecordWebSocketEvent records WebSocket event counts
 func (c *Collector) RecordWebSocketEvent(eventType string) {
+	if c == nil {
+		return
+	}
 	c.mutex.Lock()
 	defer c.mutex.Unlock()
 
@@ -93,6 +111,9 @@ func (c *Collector) RecordWebSocketEvent(eventType string) {
 
 // RecordCustomHistogram records a custom histogram value
 func (c *Collector) RecordCustomHistogram(name string, value float64) {
+	if c == nil {
+		return
+	}
 	c.mutex.Lock()
 	defer c.mutex.Unlock()
 
@@ -101,6 +122,9 @@ func (c *Collector) RecordCustomHistogram(name string, value float64) {
 
 // RecordCustomCounter increments a custom counter
 func (c *Collector) RecordCustomCounter(name string) {
+	if c == nil {
+		return
+	}
 	c.mutex.Lock()
 	defer c.mutex.Unlock()
 
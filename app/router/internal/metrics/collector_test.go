@@ -246,6 +246,27 @@ func TestRecordCustomCounter_IncrementsCustomCounter(t *testing.T) {
 	assert.Equal(t, int64(1), errorsCount)
 }
 
+func TestRecord_NilCollectorIsNoOp(t *testing.T) {
+	var collector *Collector
+
+	assert.NotPanics(t, func() {
+		collector.RecordHTTPRequest("GET", "/healthz", 200)
+		collector.RecordHTTPDuration("GET", "/healthz", 0.01)
+		collector.RecordOrderLatency("binance", "LIMIT", 0.2)
+		collector.RecordOrderStatus("binance", "FILLED")
+		collector.RecordWebSocketConnection("connected")
+		collector.RecordWebSocketEvent("depthUpdate")
+		collector.RecordCustomHistogram("custom", 1)
+		collector.RecordCustomCounter("custom")
+	})
+
+	// A nil collector still satisfies the recorder interfaces
+	var recorder MetricsCollectorInterface = collector
+	assert.NotPanics(t, func() {
+		recorder.RecordHTTPRequest("POST", "/orders", 201)
+	})
+}
+
 func TestGetSnapshot_ThreadSafe(t *testing.T) {
 	collector := NewCollector()
 
@@ -5,7 +5,9 @@ import (
 	"time"
 )
 
-// Collector handles Prometheus metrics collection
+// Collector handles Prometheus metrics collection. The Record methods are
+// no-ops on a nil *Collector, so components can take an optional collector
+// and record unconditionally.
 type Collector struct {
 	// HTTP request metrics
 	requestCounter   map[string]int64     // [method:path:status]
@@ -21,9 +21,52 @@ func (r *CreateStreamRequest) Validate() error {
 	if r.Type != "public" && r.Type != "user" {
 		return fmt.Errorf("type must be 'public' or 'user'")
 	}
+
+	var invalid []string
+	for _, name := range r.Subscriptions {
+		if !validStreamName(name) {
+			invalid = append(invalid, name)
+		}
+	}
+	if len(invalid) > 0 {
+		return fmt.Errorf("invalid stream names: %s (expected <symbol>@<type> with type one of %s)",
+			strings.Join(invalid, ", "), strings.Join(PublicStreamTypes, ", "))
+	}
 	return nil
 }
 
+// PublicStreamTypes are the stream types a public stream subscribes to by
+// name, as in btcusdt@depth
+var PublicStreamTypes = []string{"depth", "ticker"}
+
+// validStreamName reports whether name is <symbol>@<type> with an
+// alphanumeric symbol and a known type
+func validStreamName(name string) bool {
+	symbol, streamType, found := strings.Cut(name, "@")
+	if !found || !validSymbol(symbol) {
+		return false
+	}
+	for _, known := range PublicStreamTypes {
+		if streamType == known {
+			return true
+		}
+	}
+	return false
+}
+
+// validSymbol reports whether symbol is a non-empty alphanumeric trading pair
+func validSymbol(symbol string) bool {
+	if symbol == "" {
+		return false
+	}
+	for _, ch := range symbol {
+		if !('a' <= ch && ch <= 'z' || 'A' <= ch && ch <= 'Z' || '0' <= ch && ch <= '9') {
+			return false
+		}
+	}
+	return true
+}
+
 // SubscribeRequest represents a request to subscribe to market data streams
 type SubscribeRequest struct {
 	Symbol  string   `json:"symbol" binding:"required"`
@@ -35,6 +78,9 @@ func (r *SubscribeRequest) Validate() error {
 	if r.Symbol == "" {
 		return fmt.Errorf("symbol is required")
 	}
+	if !validSymbol(r.Symbol) {
+		return fmt.Errorf("invalid symbol: %s", r.Symbol)
+	}
 	if len(r.Streams) == 0 {
 		return fmt.Errorf("at least one stream is required")
 	}
@@ -45,11 +91,15 @@ func (r *SubscribeRequest) Validate() error {
 		"trades": true,
 	}
 
+	var invalid []string
 	for _, stream := range r.Streams {
 		if !validStreams[stream] {
-			return fmt.Errorf("invalid stream type: %s", stream)
+			invalid = append(invalid, stream)
 		}
 	}
+	if len(invalid) > 0 {
+		return fmt.Errorf("invalid stream type: %s", strings.Join(invalid, ", "))
+	}
 
 	return nil
 }
@@ -59,6 +109,24 @@ func (r *SubscribeRequest) Normalize() {
 	r.Symbol = strings.ToUpper(r.Symbol)
 }
 
+// MaxBulkSubscriptions caps the entries in one bulk subscribe request
+const MaxBulkSubscriptions = 200
+
+// BulkSubscribeRequest subscribes several symbols to market data at once.
+// Entries are validated individually so one bad symbol does not fail the rest.
+type BulkSubscribeRequest []SubscribeRequest
+
+// Validate validates the size of the bulk request
+func (r BulkSubscribeRequest) Validate() error {
+	if len(r) == 0 {
+		return fmt.Errorf("at least one subscription is required")
+	}
+	if len(r) > MaxBulkSubscriptions {
+		return fmt.Errorf("at most %d subscriptions are allowed per request, got %d", MaxBulkSubscriptions, len(r))
+	}
+	return nil
+}
+
 // UserDataRequest represents a request to subscribe to user data stream
 type UserDataRequest struct {
 	ListenKey string `json:"listen_key" binding:"required"`
@@ -112,8 +180,9 @@ func (r *UnsubscribeRequest) Validate() error {
 
 // ConfigUpdateRequest represents a request to update configuration
 type ConfigUpdateRequest struct {
-	RateLimit      *int `json:"rate_limit,omitempty"`
-	MaxConnections *int `json:"max_connections,omitempty"`
+	RateLimit      *int    `json:"rate_limit,omitempty"`
+	MaxConnections *int    `json:"max_connections,omitempty"`
+	LogLevel       *string `json:"log_level,omitempty"`
 }
 
 // Validate validates the configuration update request
@@ -124,5 +193,12 @@ func (r *ConfigUpdateRequest) Validate() error {
 	if r.MaxConnections != nil && *r.MaxConnections <= 0 {
 		return fmt.Errorf("max connections must be positive")
 	}
+	if r.LogLevel != nil {
+		switch *r.LogLevel {
+		case "debug", "info", "warn", "error":
+		default:
+			return fmt.Errorf("invalid log level: %s", *r.LogLevel)
+		}
+	}
 	return nil
 }
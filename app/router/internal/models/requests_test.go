@@ -2,6 +2,7 @@ package models
 
 import (
 	"encoding/json"
+	"strings"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -43,6 +44,34 @@ func TestCreateStreamRequest(t *testing.T) {
 		assert.NoError(t, err)
 	})
 
+	t.Run("validates subscription stream names", func(t *testing.T) {
+		tests := []struct {
+			name          string
+			subscriptions []string
+			wantInvalid   []string
+		}{
+			{"valid names", []string{"btcusdt@depth", "ETHUSDT@ticker"}, nil},
+			{"misspelled type", []string{"btcusdt@dept"}, []string{"btcusdt@dept"}},
+			{"missing symbol", []string{"@ticker"}, []string{"@ticker"}},
+			{"missing type", []string{"btcusdt"}, []string{"btcusdt"}},
+			{"unknown suffix", []string{"btcusdt@depth@100ms"}, []string{"btcusdt@depth@100ms"}},
+			{"lists every invalid entry", []string{"btcusdt@depth", "btc-usdt@ticker", "ethusdt@trade"}, []string{"btc-usdt@ticker", "ethusdt@trade"}},
+		}
+
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				req := CreateStreamRequest{Type: "public", Subscriptions: tt.subscriptions}
+				err := req.Validate()
+				if tt.wantInvalid == nil {
+					assert.NoError(t, err)
+					return
+				}
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), "invalid stream names: "+strings.Join(tt.wantInvalid, ", ")+" (")
+			})
+		}
+	})
+
 	t.Run("marshals to JSON correctly", func(t *testing.T) {
 		req := CreateStreamRequest{
 			Type:          "public",
@@ -186,6 +215,17 @@ func TestConfigUpdateRequest(t *testing.T) {
 		err := req.Validate()
 		assert.NoError(t, err)
 	})
+
+	t.Run("validates log level", func(t *testing.T) {
+		level := "verbose"
+		req := ConfigUpdateRequest{LogLevel: &level}
+		err := req.Validate()
+		assert.Error(t, err)
+		assert.Contains(t, err.Error(), "invalid log level")
+
+		level = "debug"
+		assert.NoError(t, req.Validate())
+	})
 }
 
 func intPtr(v int) *int {
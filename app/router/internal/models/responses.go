@@ -1,6 +1,11 @@
 package models
 
 import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
 	"time"
 )
 
@@ -22,6 +27,28 @@ type StreamMetrics struct {
 	BytesSent        int64     `json:"bytes_sent"`
 	ConnectedSince   time.Time `json:"connected_since"`
 	LastActivity     time.Time `json:"last_activity"`
+	// Subscriptions breaks traffic down by subscribed stream name
+	Subscriptions map[string]SubscriptionMetrics `json:"subscriptions,omitempty"`
+}
+
+// SubscriptionMetrics contains metrics for one subscribed stream
+type SubscriptionMetrics struct {
+	MessagesReceived int64     `json:"messages_received"`
+	LastActivity     time.Time `json:"last_activity"`
+}
+
+// StreamEvent is one market data event relayed from a stream, with the
+// stream name it arrived on and its original payload
+type StreamEvent struct {
+	Stream string          `json:"stream"`
+	Data   json.RawMessage `json:"data"`
+}
+
+// RelayTokenResponse carries a short-lived token that opens one stream's SSE
+// and WebSocket relays without the API key header
+type RelayTokenResponse struct {
+	Token     string    `json:"token"`
+	ExpiresAt time.Time `json:"expires_at"`
 }
 
 // SubscriptionResponse represents the result of a subscription request
@@ -58,6 +85,14 @@ type HealthResponse struct {
 	Uptime  int64  `json:"uptime"`
 }
 
+// VersionResponse describes the running build
+type VersionResponse struct {
+	Version   string `json:"version"`
+	GitCommit string `json:"git_commit"`
+	GoVersion string `json:"go_version"`
+	BuildTime string `json:"build_time"`
+}
+
 // HealthCheck represents a single health check result
 type HealthCheck struct {
 	Status  string `json:"status"`
@@ -75,6 +110,7 @@ type ReadinessResponse struct {
 type ConfigResponse struct {
 	RateLimit      int       `json:"rate_limit"`
 	MaxConnections int       `json:"max_connections"`
+	LogLevel       string    `json:"log_level,omitempty"`
 	UpdatedAt      time.Time `json:"updated_at"`
 }
 
@@ -97,3 +133,62 @@ func NewListResponse(data interface{}, count, total, page, pageSize int) *ListRe
 		PageSize: pageSize,
 	}
 }
+
+// CursorListResponse represents a cursor paginated list response. NextCursor
+// is empty on the last page.
+type CursorListResponse struct {
+	Data       interface{} `json:"data"`
+	Count      int         `json:"count"`
+	PageSize   int         `json:"page_size"`
+	NextCursor string      `json:"next_cursor,omitempty"`
+}
+
+// NewCursorListResponse creates a new cursor paginated list response
+func NewCursorListResponse(data interface{}, count, pageSize int, nextCursor string) *CursorListResponse {
+	return &CursorListResponse{
+		Data:       data,
+		Count:      count,
+		PageSize:   pageSize,
+		NextCursor: nextCursor,
+	}
+}
+
+// StreamCursor marks a position in streams ordered by creation time, then ID
+type StreamCursor struct {
+	CreatedAt time.Time
+	ID        string
+}
+
+// After reports whether a stream created at createdAt with id sorts after
+// the cursor position
+func (c StreamCursor) After(createdAt time.Time, id string) bool {
+	// Compare wall clock nanoseconds, which is all an encoded cursor keeps
+	at, cursorAt := createdAt.UnixNano(), c.CreatedAt.UnixNano()
+	if at != cursorAt {
+		return at > cursorAt
+	}
+	return id > c.ID
+}
+
+// Encode returns the opaque form of the cursor handed to clients
+func (c StreamCursor) Encode() string {
+	raw := strconv.FormatInt(c.CreatedAt.UnixNano(), 10) + "|" + c.ID
+	return base64.RawURLEncoding.EncodeToString([]byte(raw))
+}
+
+// DecodeStreamCursor parses a cursor produced by StreamCursor.Encode
+func DecodeStreamCursor(cursor string) (StreamCursor, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return StreamCursor{}, fmt.Errorf("invalid cursor: %w", err)
+	}
+	nanos, id, found := strings.Cut(string(raw), "|")
+	if !found || id == "" {
+		return StreamCursor{}, fmt.Errorf("invalid cursor")
+	}
+	unixNano, err := strconv.ParseInt(nanos, 10, 64)
+	if err != nil {
+		return StreamCursor{}, fmt.Errorf("invalid cursor: %w", err)
+	}
+	return StreamCursor{CreatedAt: time.Unix(0, unixNano), ID: id}, nil
+}
@@ -195,3 +195,31 @@ func TestListResponse(t *testing.T) {
 		assert.Equal(t, 5, decoded.Total)
 	})
 }
+
+func TestStreamCursor(t *testing.T) {
+	t.Run("round trips through its encoded form", func(t *testing.T) {
+		cursor := StreamCursor{CreatedAt: time.Unix(1700000000, 123456789), ID: "stream-7"}
+
+		decoded, err := DecodeStreamCursor(cursor.Encode())
+		require.NoError(t, err)
+		assert.True(t, decoded.CreatedAt.Equal(cursor.CreatedAt))
+		assert.Equal(t, "stream-7", decoded.ID)
+	})
+
+	t.Run("rejects malformed cursors", func(t *testing.T) {
+		for _, cursor := range []string{"!!!", "bm8tc2VwYXJhdG9y", "YWJjfHN0cmVhbS0x"} {
+			_, err := DecodeStreamCursor(cursor)
+			assert.ErrorContains(t, err, "invalid cursor", cursor)
+		}
+	})
+
+	t.Run("orders by creation time then ID", func(t *testing.T) {
+		at := time.Unix(1700000000, 0)
+		cursor := StreamCursor{CreatedAt: at, ID: "stream-2"}
+
+		assert.True(t, cursor.After(at.Add(time.Nanosecond), "stream-1"))
+		assert.True(t, cursor.After(at, "stream-3"))
+		assert.False(t, cursor.After(at, "stream-2"))
+		assert.False(t, cursor.After(at.Add(-time.Nanosecond), "stream-9"))
+	})
+}
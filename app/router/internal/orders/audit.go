@@ -0,0 +1,198 @@
+package orders
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/rs/zerolog"
+	"github.com/shopspring/decimal"
+	"router/internal/trace"
+)
+
+// Audited operations
+const (
+	AuditOpPlaceBracket  = "place_bracket"
+	AuditOpCancelOrder   = "cancel_order"
+	AuditOpCancelBracket = "cancel_bracket"
+	AuditOpCloseAll      = "close_all"
+	AuditOpPlaceStopLoss = "place_stop_loss"
+)
+
+// Audit outcomes
+const (
+	AuditOutcomeSuccess        = "success"
+	AuditOutcomePartialFailure = "partial_failure"
+	AuditOutcomeFailure        = "failure"
+)
+
+// DefaultAuditBufferSize is how many entries may wait for the sink before
+// new ones are dropped
+const DefaultAuditBufferSize = 1024
+
+// AuditEntry is one order operation as written to the audit log
+type AuditEntry struct {
+	Timestamp      time.Time        `json:"timestamp"`
+	RequestID      string           `json:"request_id,omitempty"`
+	Operation      string           `json:"operation"`
+	Venue          string           `json:"venue,omitempty"`
+	Symbol         string           `json:"symbol,omitempty"`
+	Side           string           `json:"side,omitempty"`
+	Quantity       *decimal.Decimal `json:"quantity,omitempty"`
+	Price          *decimal.Decimal `json:"price,omitempty"`
+	BracketOrderID string           `json:"bracket_order_id,omitempty"`
+	OrderIDs       []string         `json:"order_ids,omitempty"`
+	Outcome        string           `json:"outcome"`
+	Error          string           `json:"error,omitempty"`
+}
+
+// AuditLogger appends order operations to a sink as JSON lines. Entries are
+// queued and written by a background goroutine so a slow sink never holds up
+// order execution; when the queue is full entries are dropped and counted.
+type AuditLogger struct {
+	sink    io.Writer
+	entries chan AuditEntry
+	done    chan struct{}
+	dropped atomic.Uint64
+	logger  zerolog.Logger
+
+	mu     sync.RWMutex
+	closed bool
+}
+
+// NewAuditLogger starts an audit logger writing to sink. A non-positive
+// bufferSize uses DefaultAuditBufferSize.
+func NewAuditLogger(sink io.Writer, bufferSize int, logger zerolog.Logger) *AuditLogger {
+	if bufferSize <= 0 {
+		bufferSize = DefaultAuditBufferSize
+	}
+	a := &AuditLogger{
+		sink:    sink,
+		entries: make(chan AuditEntry, bufferSize),
+		done:    make(chan struct{}),
+		logger:  logger,
+	}
+	go a.run()
+	return a
+}
+
+// Record queues entry without blocking
+func (a *AuditLogger) Record(entry AuditEntry) {
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+
+	if a.closed {
+		a.dropped.Add(1)
+		return
+	}
+	select {
+	case a.entries <- entry:
+	default:
+		a.dropped.Add(1)
+	}
+}
+
+// Dropped returns how many entries were discarded because the queue was full
+// or the logger was closed
+func (a *AuditLogger) Dropped() uint64 {
+	return a.dropped.Load()
+}
+
+// Close stops accepting entries and waits for queued ones to be written
+func (a *AuditLogger) Close() {
+	a.mu.Lock()
+	if !a.closed {
+		a.closed = true
+		close(a.entries)
+	}
+	a.mu.Unlock()
+
+	<-a.done
+}
+
+func (a *AuditLogger) run() {
+	defer close(a.done)
+
+	encoder := json.NewEncoder(a.sink)
+	for entry := range a.entries {
+		if err := encoder.Encode(entry); err != nil {
+			a.logger.Error().Err(err).Str("operation", entry.Operation).Msg("Failed to write audit entry")
+		}
+	}
+}
+
+// audit records entry with the current time and the request's trace ID. It
+// is a no-op without an audit logger.
+func (m *Manager) audit(ctx context.Context, entry AuditEntry, err error) {
+	if m.auditLogger == nil {
+		return
+	}
+
+	entry.Timestamp = time.Now().UTC()
+	entry.RequestID = trace.ID(ctx)
+	if err != nil {
+		entry.Error = err.Error()
+		if entry.Outcome == "" {
+			entry.Outcome = AuditOutcomeFailure
+		}
+	}
+	if entry.Outcome == "" {
+		entry.Outcome = AuditOutcomeSuccess
+	}
+	m.auditLogger.Record(entry)
+}
+
+// auditPlacement records a bracket placement. Values are taken from req after
+// rounding, so they match what was sent to the exchange.
+func (m *Manager) auditPlacement(ctx context.Context, req *PlaceBracketRequest, resp *PlaceBracketResponse, err error) {
+	entry := AuditEntry{
+		Operation: AuditOpPlaceBracket,
+		Venue:     venueName(req.IsFutures),
+		Symbol:    req.Symbol,
+		Side:      req.Side,
+		Quantity:  auditDecimal(req.Quantity),
+		Price:     auditDecimal(req.EntryPrice),
+	}
+	if resp != nil {
+		entry.BracketOrderID = resp.BracketOrderID
+		entry.OrderIDs = resp.ClientOrderIDs.all()
+		if resp.PartialFailure {
+			entry.Outcome = AuditOutcomePartialFailure
+			entry.Error = strings.Join(resp.Errors, "; ")
+		}
+	}
+	m.audit(ctx, entry, err)
+}
+
+// auditDecimal returns nil for zero so unset values are omitted
+func auditDecimal(value decimal.Decimal) *decimal.Decimal {
+	if value.IsZero() {
+		return nil
+	}
+	return &value
+}
+
+// all returns the non-empty client order IDs, main leg first
+func (ids ClientOrderIDs) all() []string {
+	all := make([]string, 0, len(ids.TakeProfits)+2)
+	all = append(all, ids.Main)
+	all = append(all, ids.TakeProfits...)
+	all = append(all, ids.StopLoss)
+
+	nonEmpty := all[:0]
+	for _, id := range all {
+		if id != "" {
+			nonEmpty = append(nonEmpty, id)
+		}
+	}
+	return nonEmpty
+}
+
+func formatOrderID(orderID int64) string {
+	return strconv.FormatInt(orderID, 10)
+}
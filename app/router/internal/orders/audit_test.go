@@ -0,0 +1,217 @@
+package orders
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/rs/zerolog"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"router/internal/trace"
+)
+
+// readAuditEntries closes audit and decodes what it wrote to sink
+func readAuditEntries(t *testing.T, audit *AuditLogger, sink *bytes.Buffer) []AuditEntry {
+	t.Helper()
+	audit.Close()
+
+	var entries []AuditEntry
+	scanner := bufio.NewScanner(sink)
+	for scanner.Scan() {
+		var entry AuditEntry
+		require.NoError(t, json.Unmarshal(scanner.Bytes(), &entry))
+		entries = append(entries, entry)
+	}
+	require.NoError(t, scanner.Err())
+	return entries
+}
+
+func TestManager_AuditLog(t *testing.T) {
+	ctx := trace.WithID(context.Background(), "req-123")
+
+	t.Run("records each operation", func(t *testing.T) {
+		manager, fake, _ := newHarnessManager(t)
+		var sink bytes.Buffer
+		audit := NewAuditLogger(&sink, 0, zerolog.Nop())
+		WithAuditLogger(audit)(manager)
+
+		first, err := manager.PlaceBracketOrder(ctx, harnessBracketRequest())
+		require.NoError(t, err)
+		second, err := manager.PlaceBracketOrder(ctx, harnessBracketRequest())
+		require.NoError(t, err)
+
+		require.NoError(t, manager.CancelBracket(ctx, first.BracketOrderID))
+
+		secondMain := fake.Orders()[3]
+		require.Equal(t, second.ClientOrderIDs.Main, secondMain.ClientOrderID())
+		require.NoError(t, manager.CancelOrder(ctx, &CancelRequest{Symbol: "BTCUSDT", OrderID: secondMain.OrderID}))
+
+		require.NoError(t, manager.CloseAllPositions(ctx, &CloseAllRequest{Symbol: "BTCUSDT"}))
+
+		entries := readAuditEntries(t, audit, &sink)
+		require.Len(t, entries, 8)
+		for _, entry := range entries {
+			assert.Equal(t, "req-123", entry.RequestID)
+			assert.Equal(t, AuditOutcomeSuccess, entry.Outcome)
+			assert.Empty(t, entry.Error)
+			assert.WithinDuration(t, time.Now(), entry.Timestamp, time.Minute)
+			assert.Equal(t, "BTCUSDT", entry.Symbol)
+		}
+
+		placed := entries[0]
+		assert.Equal(t, AuditOpPlaceBracket, placed.Operation)
+		assert.Equal(t, VenueSpot, placed.Venue)
+		assert.Equal(t, "BUY", placed.Side)
+		require.NotNil(t, placed.Quantity)
+		assert.Equal(t, "0.00123", placed.Quantity.String())
+		require.NotNil(t, placed.Price)
+		assert.Equal(t, "50000", placed.Price.String())
+		assert.Equal(t, first.BracketOrderID, placed.BracketOrderID)
+		assert.Equal(t, []string{first.ClientOrderIDs.Main, first.ClientOrderIDs.TakeProfits[0], first.ClientOrderIDs.StopLoss}, placed.OrderIDs)
+
+		// Each leg cancel is recorded before the bracket as a whole
+		for i, leg := range entries[2:5] {
+			assert.Equal(t, AuditOpCancelOrder, leg.Operation)
+			assert.Equal(t, VenueSpot, leg.Venue)
+			assert.Equal(t, first.BracketOrderID, leg.BracketOrderID)
+			require.Len(t, leg.OrderIDs, 2)
+			assert.Equal(t, placed.OrderIDs[i], leg.OrderIDs[1])
+		}
+
+		cancelledBracket := entries[5]
+		assert.Equal(t, AuditOpCancelBracket, cancelledBracket.Operation)
+		assert.Equal(t, first.BracketOrderID, cancelledBracket.BracketOrderID)
+		assert.Equal(t, placed.OrderIDs, cancelledBracket.OrderIDs)
+
+		cancelledOrder := entries[6]
+		assert.Equal(t, AuditOpCancelOrder, cancelledOrder.Operation)
+		assert.Equal(t, []string{formatOrderID(secondMain.OrderID)}, cancelledOrder.OrderIDs)
+		assert.Nil(t, cancelledOrder.Quantity)
+
+		closed := entries[7]
+		assert.Equal(t, AuditOpCloseAll, closed.Operation)
+		assert.Len(t, closed.OrderIDs, 2, "the second bracket's take profit and stop loss")
+	})
+
+	t.Run("records monitor cancels and stop replacements", func(t *testing.T) {
+		manager, _, _ := newHarnessManager(t)
+		var sink bytes.Buffer
+		audit := NewAuditLogger(&sink, 0, zerolog.Nop())
+		WithAuditLogger(audit)(manager)
+		monitor := NewBracketMonitor(manager, zerolog.Nop())
+
+		req := harnessBracketRequest()
+		req.Quantity = decimal.RequireFromString("0.002")
+		req.EntryPrice = decimal.NewFromInt(50000)
+		req.TakeProfitPrices = []decimal.Decimal{decimal.NewFromInt(51000), decimal.NewFromInt(52000)}
+		req.MoveStopToBreakevenOnTP1 = true
+		resp, err := manager.PlaceBracketOrder(ctx, req)
+		require.NoError(t, err)
+		ids := resp.ClientOrderIDs
+
+		require.NoError(t, monitor.HandleOrderUpdate(filledEvent(ids.Main)))
+		require.NoError(t, monitor.HandleOrderUpdate(filledEvent(ids.TakeProfits[0])))
+
+		manager.mu.RLock()
+		breakevenStop := manager.orders[resp.BracketOrderID].ClientOrderIDs.StopLoss
+		manager.mu.RUnlock()
+
+		require.NoError(t, monitor.HandleOrderUpdate(filledEvent(ids.TakeProfits[1])))
+
+		entries := readAuditEntries(t, audit, &sink)
+		require.Len(t, entries, 4)
+		for _, entry := range entries[1:] {
+			assert.Equal(t, AuditOutcomeSuccess, entry.Outcome)
+			assert.Equal(t, resp.BracketOrderID, entry.BracketOrderID)
+			assert.Equal(t, VenueSpot, entry.Venue)
+			assert.Equal(t, "SELL", entry.Side)
+		}
+
+		// Breakeven: the original stop is cancelled and replaced
+		assert.Equal(t, AuditOpCancelOrder, entries[1].Operation)
+		require.Len(t, entries[1].OrderIDs, 2)
+		assert.Equal(t, ids.StopLoss, entries[1].OrderIDs[1])
+
+		assert.Equal(t, AuditOpPlaceStopLoss, entries[2].Operation)
+		assert.Equal(t, []string{breakevenStop}, entries[2].OrderIDs)
+		require.NotNil(t, entries[2].Quantity)
+		assert.Equal(t, "0.001", entries[2].Quantity.String())
+		require.NotNil(t, entries[2].Price)
+		assert.Equal(t, "50100", entries[2].Price.String())
+
+		// OCO: the last take profit cancels the breakeven stop
+		assert.Equal(t, AuditOpCancelOrder, entries[3].Operation)
+		require.Len(t, entries[3].OrderIDs, 2)
+		assert.Equal(t, breakevenStop, entries[3].OrderIDs[1])
+	})
+
+	t.Run("records failures", func(t *testing.T) {
+		manager, fake, _ := newHarnessManager(t)
+		var sink bytes.Buffer
+		audit := NewAuditLogger(&sink, 0, zerolog.Nop())
+		WithAuditLogger(audit)(manager)
+
+		fake.InjectError(-2010, "Account has insufficient balance for requested action.")
+		_, err := manager.PlaceBracketOrder(ctx, harnessBracketRequest())
+		require.Error(t, err)
+
+		err = manager.CancelBracket(ctx, "missing")
+		require.Error(t, err)
+
+		entries := readAuditEntries(t, audit, &sink)
+		require.Len(t, entries, 2)
+
+		assert.Equal(t, AuditOpPlaceBracket, entries[0].Operation)
+		assert.Equal(t, AuditOutcomeFailure, entries[0].Outcome)
+		assert.Contains(t, entries[0].Error, "insufficient balance")
+		assert.Empty(t, entries[0].OrderIDs)
+
+		assert.Equal(t, AuditOpCancelBracket, entries[1].Operation)
+		assert.Equal(t, AuditOutcomeFailure, entries[1].Outcome)
+		assert.Equal(t, "missing", entries[1].BracketOrderID)
+		assert.Equal(t, "bracket not found: missing", entries[1].Error)
+	})
+}
+
+// blockingWriter holds every write until release is closed
+type blockingWriter struct {
+	release chan struct{}
+}
+
+func (w *blockingWriter) Write(p []byte) (int, error) {
+	<-w.release
+	return len(p), nil
+}
+
+func TestAuditLogger_DoesNotBlock(t *testing.T) {
+	sink := &blockingWriter{release: make(chan struct{})}
+	audit := NewAuditLogger(sink, 2, zerolog.Nop())
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for i := 0; i < 10; i++ {
+			audit.Record(AuditEntry{Operation: AuditOpCancelOrder})
+		}
+	}()
+
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("Record blocked on a stalled sink")
+	}
+
+	// One entry is held by the writer and two are queued
+	assert.GreaterOrEqual(t, audit.Dropped(), uint64(7))
+
+	close(sink.release)
+	audit.Close()
+
+	audit.Record(AuditEntry{Operation: AuditOpCancelOrder})
+	assert.GreaterOrEqual(t, audit.Dropped(), uint64(8), "entries after Close are dropped")
+}
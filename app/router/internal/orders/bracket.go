@@ -2,14 +2,29 @@ package orders
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"slices"
+	"time"
 
 	"github.com/shopspring/decimal"
 	"router/internal/binance"
+	"router/internal/rest"
 )
 
+// protectiveLegTimeout bounds placing a bracket's exit legs once its entry
+// is accepted
+const protectiveLegTimeout = 15 * time.Second
+
+// protectiveContext detaches the exit legs from the request's deadline. Once
+// the entry is accepted, a caller that stopped waiting must not leave the
+// position without its stop loss.
+func protectiveContext(ctx context.Context) (context.Context, context.CancelFunc) {
+	return context.WithTimeout(context.WithoutCancel(ctx), protectiveLegTimeout)
+}
+
 // placeSpotBracket places a bracket order for spot trading
-func (m *Manager) placeSpotBracket(ctx context.Context, client *binance.Client, req *PlaceBracketRequest, bracketID string) (ClientOrderIDs, error) {
+func (m *Manager) placeSpotBracket(ctx context.Context, client *binance.Client, req *PlaceBracketRequest, bracketID string, tpQuantities []decimal.Decimal) (ClientOrderIDs, error) {
 	ids := ClientOrderIDs{
 		TakeProfits: make([]string, len(req.TakeProfitPrices)),
 	}
@@ -25,11 +40,10 @@ func (m *Manager) placeSpotBracket(ctx context.Context, client *binance.Client,
 		Type:             getOrderType(req.OrderType, req.EntryPrice),
 		Quantity:         req.Quantity,
 		Price:            req.EntryPrice,
-		TimeInForce:      "GTC",
 		NewClientOrderID: mainOrderID,
 	}
 
-	mainResp, err := client.PlaceSpotOrder(ctx, mainOrder)
+	mainResp, err := m.placeSpotLeg(ctx, client, mainOrder)
 	if err != nil {
 		bracketErr.Add("MAIN", err)
 		// Return immediately if main order fails as it's critical
@@ -37,25 +51,25 @@ func (m *Manager) placeSpotBracket(ctx context.Context, client *binance.Client,
 	}
 	ids.Main = mainOrderID
 
+	ctx, cancel := protectiveContext(ctx)
+	defer cancel()
+
 	// 2. Place take profit orders (as limit orders)
 	// For spot, we can place these immediately
 	for i, tpPrice := range req.TakeProfitPrices {
 		tpID := m.generateClientOrderID(bracketID, fmt.Sprintf("TP%d", i+1))
 
-		// Calculate quantity for this TP (split evenly for simplicity)
-		tpQuantity := req.Quantity.Div(decimal.NewFromInt(int64(len(req.TakeProfitPrices))))
-
 		tpOrder := binance.SpotOrderRequest{
 			Symbol:           req.Symbol,
 			Side:             getOppositeSide(req.Side),
 			Type:             "LIMIT",
-			Quantity:         tpQuantity,
+			Quantity:         tpQuantities[i],
 			Price:            tpPrice,
 			TimeInForce:      "GTC",
 			NewClientOrderID: tpID,
 		}
 
-		_, err := client.PlaceSpotOrder(ctx, tpOrder)
+		_, err := m.placeSpotLeg(ctx, client, tpOrder)
 		if err != nil {
 			bracketErr.Add(fmt.Sprintf("TP%d", i+1), err)
 			m.logger.Error().
@@ -71,31 +85,9 @@ func (m *Manager) placeSpotBracket(ctx context.Context, client *binance.Client,
 
 	// 3. Place stop loss order using STOP_LOSS_LIMIT
 	slID := m.generateClientOrderID(bracketID, "SL")
+	slOrder := spotStopLossOrder(req.Symbol, req.Side, req.Quantity, req.StopLossPrice, slID)
 
-	// For STOP_LOSS_LIMIT:
-	// - stopPrice is the trigger price
-	// - price is the limit price (slightly worse than stop to ensure fill)
-	slLimitPrice := req.StopLossPrice
-	if req.Side == "BUY" {
-		// For long positions, SL sells below stop price
-		slLimitPrice = req.StopLossPrice.Mul(decimal.NewFromFloat(0.995))
-	} else {
-		// For short positions, SL buys above stop price
-		slLimitPrice = req.StopLossPrice.Mul(decimal.NewFromFloat(1.005))
-	}
-
-	slOrder := binance.SpotOrderRequest{
-		Symbol:           req.Symbol,
-		Side:             getOppositeSide(req.Side),
-		Type:             "STOP_LOSS_LIMIT",
-		Quantity:         req.Quantity,
-		Price:            slLimitPrice,      // Limit price
-		StopPrice:        req.StopLossPrice, // Trigger price
-		TimeInForce:      "GTC",
-		NewClientOrderID: slID,
-	}
-
-	_, err = client.PlaceSpotOrder(ctx, slOrder)
+	_, err = m.placeSpotLeg(ctx, client, slOrder)
 	if err != nil {
 		bracketErr.Add("SL", err)
 		m.logger.Error().
@@ -126,7 +118,7 @@ func (m *Manager) placeSpotBracket(ctx context.Context, client *binance.Client,
 }
 
 // placeFuturesBracket places a bracket order for futures trading
-func (m *Manager) placeFuturesBracket(ctx context.Context, client *binance.Client, req *PlaceBracketRequest, bracketID string) (ClientOrderIDs, error) {
+func (m *Manager) placeFuturesBracket(ctx context.Context, client *binance.Client, req *PlaceBracketRequest, bracketID string, tpQuantities []decimal.Decimal) (ClientOrderIDs, error) {
 	ids := ClientOrderIDs{
 		TakeProfits: make([]string, len(req.TakeProfitPrices)),
 	}
@@ -134,6 +126,19 @@ func (m *Manager) placeFuturesBracket(ctx context.Context, client *binance.Clien
 	// Create error aggregator
 	bracketErr := NewBracketOrderError(bracketID, req.Symbol)
 
+	// In hedge mode every leg names the position it acts on and Binance
+	// rejects reduceOnly; in one-way mode the exit legs are reduce-only
+	hedgeMode, err := client.GetPositionMode(ctx)
+	if err != nil {
+		bracketErr.Add("MAIN", err)
+		return ids, bracketErr
+	}
+	positionSide := ""
+	if hedgeMode {
+		positionSide = hedgePositionSide(req.Side)
+	}
+	reduceOnly := !hedgeMode
+
 	// 1. Place main order
 	mainOrderID := m.generateClientOrderID(bracketID, "MAIN")
 	mainOrder := binance.FuturesOrderRequest{
@@ -142,12 +147,12 @@ func (m *Manager) placeFuturesBracket(ctx context.Context, client *binance.Clien
 		Type:             getOrderType(req.OrderType, req.EntryPrice),
 		Quantity:         req.Quantity,
 		Price:            req.EntryPrice,
-		TimeInForce:      "GTC",
 		NewClientOrderID: mainOrderID,
 		ReduceOnly:       false, // Opening position
+		PositionSide:     positionSide,
 	}
 
-	mainResp, err := client.PlaceFuturesOrder(ctx, mainOrder)
+	mainResp, err := m.placeFuturesLeg(ctx, client, mainOrder)
 	if err != nil {
 		bracketErr.Add("MAIN", err)
 		// Return immediately if main order fails as it's critical
@@ -155,25 +160,26 @@ func (m *Manager) placeFuturesBracket(ctx context.Context, client *binance.Clien
 	}
 	ids.Main = mainOrderID
 
-	// 2. Place take profit orders with ReduceOnly
+	ctx, cancel := protectiveContext(ctx)
+	defer cancel()
+
+	// 2. Place take profit orders closing the position
 	for i, tpPrice := range req.TakeProfitPrices {
 		tpID := m.generateClientOrderID(bracketID, fmt.Sprintf("TP%d", i+1))
 
-		// Calculate quantity for this TP
-		tpQuantity := req.Quantity.Div(decimal.NewFromInt(int64(len(req.TakeProfitPrices))))
-
 		tpOrder := binance.FuturesOrderRequest{
 			Symbol:           req.Symbol,
 			Side:             getOppositeSide(req.Side),
 			Type:             "LIMIT",
-			Quantity:         tpQuantity,
+			Quantity:         tpQuantities[i],
 			Price:            tpPrice,
 			TimeInForce:      "GTC",
 			NewClientOrderID: tpID,
-			ReduceOnly:       true, // TP orders reduce position
+			ReduceOnly:       reduceOnly, // TP orders reduce position
+			PositionSide:     positionSide,
 		}
 
-		_, err := client.PlaceFuturesOrder(ctx, tpOrder)
+		_, err := m.placeFuturesLeg(ctx, client, tpOrder)
 		if err != nil {
 			bracketErr.Add(fmt.Sprintf("TP%d", i+1), err)
 			m.logger.Error().
@@ -189,19 +195,9 @@ func (m *Manager) placeFuturesBracket(ctx context.Context, client *binance.Clien
 
 	// 3. Place stop loss order using STOP_MARKET
 	slID := m.generateClientOrderID(bracketID, "SL")
-	slOrder := binance.FuturesOrderRequest{
-		Symbol:           req.Symbol,
-		Side:             getOppositeSide(req.Side),
-		Type:             "STOP_MARKET",
-		Quantity:         req.Quantity,
-		StopPrice:        req.StopLossPrice, // Stop trigger price
-		TimeInForce:      "GTC",
-		NewClientOrderID: slID,
-		ReduceOnly:       true, // SL reduces position
-		ClosePosition:    true, // Close entire position on stop
-	}
+	slOrder := futuresStopLossOrder(req.Symbol, req.Side, req.Quantity, req.StopLossPrice, slID, positionSide, req.WorkingType)
 
-	_, err = client.PlaceFuturesOrder(ctx, slOrder)
+	_, err = m.placeFuturesLeg(ctx, client, slOrder)
 	if err != nil {
 		bracketErr.Add("SL", err)
 		m.logger.Error().
@@ -231,6 +227,170 @@ func (m *Manager) placeFuturesBracket(ctx context.Context, client *binance.Clien
 	return ids, nil
 }
 
+// spotStopLossOrder builds the STOP_LOSS_LIMIT order protecting a spot
+// position entered on side. stopPrice triggers it and the limit sits slightly
+// beyond, so the order still fills in a fast market.
+func spotStopLossOrder(symbol, side string, quantity, stopPrice decimal.Decimal, clientOrderID string) binance.SpotOrderRequest {
+	limitPrice := stopPrice
+	if side == "BUY" {
+		// For long positions, SL sells below stop price
+		limitPrice = stopPrice.Mul(decimal.NewFromFloat(0.995))
+	} else {
+		// For short positions, SL buys above stop price
+		limitPrice = stopPrice.Mul(decimal.NewFromFloat(1.005))
+	}
+
+	return binance.SpotOrderRequest{
+		Symbol:           symbol,
+		Side:             getOppositeSide(side),
+		Type:             "STOP_LOSS_LIMIT",
+		Quantity:         quantity,
+		Price:            limitPrice,
+		StopPrice:        stopPrice,
+		TimeInForce:      "GTC",
+		NewClientOrderID: clientOrderID,
+	}
+}
+
+// futuresStopLossOrder builds the STOP_MARKET order protecting a futures
+// position entered on side. positionSide is set in hedge mode, where Binance
+// rejects reduceOnly; in one-way mode the order is reduce-only instead.
+func futuresStopLossOrder(symbol, side string, quantity, stopPrice decimal.Decimal, clientOrderID, positionSide, workingType string) binance.FuturesOrderRequest {
+	return binance.FuturesOrderRequest{
+		Symbol:           symbol,
+		Side:             getOppositeSide(side),
+		Type:             "STOP_MARKET",
+		Quantity:         quantity,
+		StopPrice:        stopPrice,
+		NewClientOrderID: clientOrderID,
+		ReduceOnly:       positionSide == "", // Binance rejects closePosition alongside reduceOnly
+		PositionSide:     positionSide,
+		WorkingType:      workingType,
+	}
+}
+
+// placeStopLoss places a stop loss for the open part of bracket's position
+func (m *Manager) placeStopLoss(ctx context.Context, client *binance.Client, bracket *BracketOrder, quantity, stopPrice decimal.Decimal, clientOrderID string) (err error) {
+	defer func() {
+		m.audit(ctx, AuditEntry{
+			Operation:      AuditOpPlaceStopLoss,
+			Venue:          venueName(bracket.Type == OrderTypeFutures),
+			Symbol:         bracket.Symbol,
+			Side:           getOppositeSide(bracket.Side),
+			Quantity:       auditDecimal(quantity),
+			Price:          auditDecimal(stopPrice),
+			BracketOrderID: bracket.ID,
+			OrderIDs:       []string{clientOrderID},
+		}, err)
+	}()
+
+	if bracket.Type != OrderTypeFutures {
+		_, err = m.placeSpotLeg(ctx, client, spotStopLossOrder(bracket.Symbol, bracket.Side, quantity, stopPrice, clientOrderID))
+		return err
+	}
+
+	hedgeMode, err := client.GetPositionMode(ctx)
+	if err != nil {
+		return err
+	}
+	positionSide := ""
+	if hedgeMode {
+		positionSide = hedgePositionSide(bracket.Side)
+	}
+	_, err = m.placeFuturesLeg(ctx, client, futuresStopLossOrder(bracket.Symbol, bracket.Side, quantity, stopPrice, clientOrderID, positionSide, bracket.WorkingType))
+	return err
+}
+
+// placeSpotLeg places one spot bracket leg. See placeFuturesLeg.
+func (m *Manager) placeSpotLeg(ctx context.Context, client *binance.Client, order binance.SpotOrderRequest) (*binance.OrderResponse, error) {
+	resp, err := client.PlaceSpotOrder(ctx, order)
+	if err != nil && isDuplicateOrder(err) {
+		return m.existingOrder(ctx, client, order.Symbol, order.NewClientOrderID, err)
+	}
+	return resp, err
+}
+
+// placeFuturesLeg places one futures bracket leg. A duplicate client order
+// ID rejection means an earlier attempt whose response was lost, such as a
+// retry after a network error, already placed the order, so it is looked up
+// and treated as placed.
+func (m *Manager) placeFuturesLeg(ctx context.Context, client *binance.Client, order binance.FuturesOrderRequest) (*binance.OrderResponse, error) {
+	resp, err := client.PlaceFuturesOrder(ctx, order)
+	if err != nil && isDuplicateOrder(err) {
+		return m.existingOrder(ctx, client, order.Symbol, order.NewClientOrderID, err)
+	}
+	return resp, err
+}
+
+// existingOrder fetches the order a duplicate client order ID rejection
+// refers to, returning placeErr when it cannot be confirmed
+func (m *Manager) existingOrder(ctx context.Context, client *binance.Client, symbol, clientOrderID string, placeErr error) (*binance.OrderResponse, error) {
+	existing, err := client.GetOrderByClientID(ctx, symbol, clientOrderID)
+	if err != nil {
+		m.logger.Error().
+			Err(err).
+			Str("symbol", symbol).
+			Str("client_order_id", clientOrderID).
+			Msg("Failed to look up order rejected as a duplicate")
+		return nil, placeErr
+	}
+
+	m.logger.Warn().
+		Str("symbol", symbol).
+		Str("client_order_id", clientOrderID).
+		Int64("order_id", existing.OrderID).
+		Str("status", existing.Status).
+		Msg("Order was already placed by an earlier attempt")
+	return existing, nil
+}
+
+// isDuplicateOrder reports whether err is Binance rejecting a reused client
+// order ID
+func isDuplicateOrder(err error) bool {
+	var apiErr *rest.BinanceError
+	return errors.As(err, &apiErr) && apiErr.IsDuplicateOrder()
+}
+
+// takeProfitQuantities splits req.Quantity across the take profit legs by
+// TakeProfitRatios, or evenly without them. Legs are rounded down to the
+// step size and the last leg takes the remainder, so the legs always close
+// the whole position instead of leaving dust behind.
+func takeProfitQuantities(ctx context.Context, client *binance.Client, req *PlaceBracketRequest) ([]decimal.Decimal, error) {
+	legs := len(req.TakeProfitPrices)
+	shares := make([]decimal.Decimal, legs)
+	for i := range shares {
+		if len(req.TakeProfitRatios) > 0 {
+			shares[i] = req.Quantity.Mul(req.TakeProfitRatios[i])
+		} else {
+			shares[i] = req.Quantity.Div(decimal.NewFromInt(int64(legs)))
+		}
+	}
+
+	_, quantities, err := client.RoundOrderValues(ctx, req.Symbol, nil, shares)
+	if err != nil {
+		return nil, err
+	}
+	remainder := req.Quantity
+	for _, quantity := range quantities[:legs-1] {
+		remainder = remainder.Sub(quantity)
+	}
+	quantities[legs-1] = remainder
+
+	// Rounding clamps a leg below the minimum quantity up to it, so a leg
+	// that changes when rounded again is too small to place
+	_, rounded, err := client.RoundOrderValues(ctx, req.Symbol, nil, quantities)
+	if err != nil {
+		return nil, err
+	}
+	for i, quantity := range quantities {
+		if !quantity.IsPositive() || !rounded[i].Equal(quantity) {
+			return nil, fmt.Errorf("take profit %d quantity %s does not meet the symbol's quantity filters", i+1, quantity)
+		}
+	}
+
+	return quantities, nil
+}
+
 // getOrderType returns the order type based on price
 func getOrderType(requestedType string, price decimal.Decimal) string {
 	if requestedType != "" {
@@ -242,6 +402,14 @@ func getOrderType(requestedType string, price decimal.Decimal) string {
 	return "LIMIT"
 }
 
+// hedgePositionSide returns the hedge-mode position an entry on side opens
+func hedgePositionSide(side string) string {
+	if side == "SELL" {
+		return binance.PositionSideShort
+	}
+	return binance.PositionSideLong
+}
+
 // getOppositeSide returns the opposite side for closing orders
 func getOppositeSide(side string) string {
 	if side == "BUY" {
@@ -250,11 +418,39 @@ func getOppositeSide(side string) string {
 	return "BUY"
 }
 
+// closeCancelledBrackets closes the brackets on symbol whose legs were all
+// cancelled, freeing their slots. Brackets with a leg in stillOpen stay open.
+func (m *Manager) closeCancelledBrackets(symbol string, isFutures bool, profile string, stillOpen map[string]bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	for _, bracket := range m.orders {
+		if bracket.Symbol != symbol || (bracket.Type == OrderTypeFutures) != isFutures || bracket.Profile != profile {
+			continue
+		}
+		if slices.ContainsFunc(bracket.ClientOrderIDs.all(), func(id string) bool { return stillOpen[id] }) {
+			continue
+		}
+		m.closeBracketLocked(bracket)
+	}
+}
+
+// venueName returns the venue label used in error messages
+func venueName(isFutures bool) string {
+	if isFutures {
+		return "futures"
+	}
+	return "spot"
+}
+
 // CloseAllPositions closes all open positions
-func (m *Manager) CloseAllPositions(ctx context.Context, req *CloseAllRequest) error {
-	client := m.spotClient
-	if req.IsFutures {
-		client = m.futuresClient
+func (m *Manager) CloseAllPositions(ctx context.Context, req *CloseAllRequest) (err error) {
+	entry := AuditEntry{Operation: AuditOpCloseAll, Venue: venueName(req.IsFutures), Symbol: req.Symbol}
+	defer func() { m.audit(ctx, entry, err) }()
+
+	client, err := m.clientFor(req.IsFutures, req.Profile)
+	if err != nil {
+		return err
 	}
 
 	// Get open orders
@@ -276,17 +472,22 @@ func (m *Manager) CloseAllPositions(ctx context.Context, req *CloseAllRequest) e
 		}
 
 		// Cancel all open orders
+		stillOpen := make(map[string]bool)
 		for _, order := range orders {
 			err = client.CancelOrder(ctx, symbol, order.OrderID)
 			if err != nil {
 				lastErr = err
+				stillOpen[order.ClientOrderID] = true
 				m.logger.Error().
 					Err(err).
 					Str("symbol", symbol).
 					Int64("order_id", order.OrderID).
 					Msg("Failed to cancel order during close all")
+				continue
 			}
+			entry.OrderIDs = append(entry.OrderIDs, formatOrderID(order.OrderID))
 		}
+		m.closeCancelledBrackets(symbol, req.IsFutures, req.Profile, stillOpen)
 
 		// For futures, also close position with market order
 		if req.IsFutures {
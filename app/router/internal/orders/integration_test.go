@@ -0,0 +1,823 @@
+package orders
+
+import (
+	"context"
+	"net/url"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/rs/zerolog"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"router/internal/auth"
+	"router/internal/binance"
+	"router/internal/config"
+	"router/internal/rest"
+	"router/internal/testutil"
+)
+
+type recordingEmitter struct {
+	mu      sync.Mutex
+	updates []*OrderUpdate
+}
+
+func (e *recordingEmitter) EmitOrderUpdate(ctx context.Context, update *OrderUpdate) error {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.updates = append(e.updates, update)
+	return nil
+}
+
+func (e *recordingEmitter) Updates() []*OrderUpdate {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	return append([]*OrderUpdate(nil), e.updates...)
+}
+
+// newHarnessManager wires a Manager through the real binance and rest clients
+// to a fake Binance server
+func newHarnessManager(t *testing.T) (*Manager, *testutil.FakeBinance, *recordingEmitter) {
+	t.Helper()
+
+	fake := testutil.NewFakeBinance(t)
+	fake.AddSymbol(testutil.BTCUSDT)
+
+	signer := auth.NewSigner("test-key", "test-secret")
+	restClient := rest.NewClient(fake.URL(), signer, rest.WithMaxRetries(0))
+	spot, err := binance.NewClient(fake.URL(), signer, restClient, zerolog.Nop())
+	require.NoError(t, err)
+
+	emitter := &recordingEmitter{}
+	return NewManager(spot, nil, emitter, zerolog.Nop()), fake, emitter
+}
+
+func harnessBracketRequest() *PlaceBracketRequest {
+	return &PlaceBracketRequest{
+		Symbol:           "BTCUSDT",
+		Side:             "BUY",
+		Quantity:         decimal.RequireFromString("0.0012345"),
+		EntryPrice:       decimal.RequireFromString("50000.004"),
+		TakeProfitPrices: []decimal.Decimal{decimal.RequireFromString("51000.5")},
+		StopLossPrice:    decimal.NewFromInt(49000),
+		OrderType:        "LIMIT",
+	}
+}
+
+func TestPlaceBracketOrder_Integration(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("places all legs with rounded values", func(t *testing.T) {
+		manager, fake, emitter := newHarnessManager(t)
+
+		resp, err := manager.PlaceBracketOrder(ctx, harnessBracketRequest())
+		require.NoError(t, err)
+
+		assert.Equal(t, "BTCUSDT", resp.Symbol)
+		assert.Equal(t, "BUY", resp.Side)
+		assert.Equal(t, "0.00123", resp.Quantity.String())
+		assert.False(t, resp.PartialFailure)
+		assert.Empty(t, resp.Errors)
+		assert.NotEmpty(t, resp.ClientOrderIDs.Main)
+		require.Len(t, resp.ClientOrderIDs.TakeProfits, 1)
+		assert.NotEmpty(t, resp.ClientOrderIDs.TakeProfits[0])
+		assert.NotEmpty(t, resp.ClientOrderIDs.StopLoss)
+
+		placed := fake.Orders()
+		require.Len(t, placed, 3)
+
+		main, tp, sl := placed[0], placed[1], placed[2]
+		assert.Equal(t, resp.ClientOrderIDs.Main, main.ClientOrderID())
+		assert.Equal(t, "LIMIT", main.Type())
+		assert.Equal(t, "50000", main.Params.Get("price"))
+		assert.Equal(t, "0.00123", main.Params.Get("quantity"))
+
+		assert.Equal(t, resp.ClientOrderIDs.TakeProfits[0], tp.ClientOrderID())
+		assert.Equal(t, "SELL", tp.Params.Get("side"))
+		assert.Equal(t, "51000.5", tp.Params.Get("price"))
+
+		assert.Equal(t, resp.ClientOrderIDs.StopLoss, sl.ClientOrderID())
+		assert.Equal(t, "STOP_LOSS_LIMIT", sl.Type())
+		assert.Equal(t, "49000", sl.Params.Get("stopPrice"))
+
+		updates := emitter.Updates()
+		require.Len(t, updates, 1)
+		assert.Equal(t, resp.ClientOrderIDs.Main, updates[0].ClientOrderID)
+		assert.Equal(t, "NEW", updates[0].Status)
+		assert.Equal(t, "0.00123", updates[0].Quantity.String())
+	})
+
+	t.Run("reports partial failure when stop loss is rejected", func(t *testing.T) {
+		manager, fake, emitter := newHarnessManager(t)
+		fake.InjectErrorFor(testutil.OrderTypeIs("STOP_LOSS_LIMIT"), -2010, "Stop price would trigger immediately.")
+
+		resp, err := manager.PlaceBracketOrder(ctx, harnessBracketRequest())
+		require.NoError(t, err)
+
+		assert.True(t, resp.PartialFailure)
+		require.Len(t, resp.Errors, 1)
+		assert.Contains(t, resp.Errors[0], "SL:")
+		assert.Contains(t, resp.Errors[0], "Stop price would trigger immediately")
+		assert.NotEmpty(t, resp.ClientOrderIDs.Main)
+		assert.NotEmpty(t, resp.ClientOrderIDs.TakeProfits[0])
+		assert.Empty(t, resp.ClientOrderIDs.StopLoss)
+
+		// The rejected leg never reached the book
+		assert.Len(t, fake.Orders(), 2)
+		assert.Len(t, emitter.Updates(), 1)
+	})
+
+	t.Run("fails without placing orders when main leg is rejected", func(t *testing.T) {
+		manager, fake, emitter := newHarnessManager(t)
+		fake.InjectError(-2010, "Account has insufficient balance for requested action.")
+
+		_, err := manager.PlaceBracketOrder(ctx, harnessBracketRequest())
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "MAIN")
+
+		assert.Empty(t, fake.Orders())
+		assert.Empty(t, emitter.Updates())
+	})
+
+	t.Run("rejects bracket below minimum notional", func(t *testing.T) {
+		manager, fake, _ := newHarnessManager(t)
+		req := harnessBracketRequest()
+		req.Quantity = decimal.RequireFromString("0.00005") // 2.5 USDT < 5 USDT minimum
+
+		_, err := manager.PlaceBracketOrder(ctx, req)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "notional validation failed")
+		assert.Equal(t, 0, fake.RequestCount("/api/v3/order"))
+	})
+}
+
+func TestTakeProfitSplit_Integration(t *testing.T) {
+	ctx := context.Background()
+
+	threeTargets := func() *PlaceBracketRequest {
+		req := harnessBracketRequest()
+		req.Quantity = decimal.RequireFromString("0.001")
+		req.TakeProfitPrices = []decimal.Decimal{
+			decimal.NewFromInt(51000),
+			decimal.NewFromInt(52000),
+			decimal.NewFromInt(53000),
+		}
+		return req
+	}
+	tpQuantities := func(fake *testutil.FakeBinance) []string {
+		var quantities []string
+		for _, order := range fake.Orders() {
+			if order.Type() == "LIMIT" && order.Params.Get("side") == "SELL" {
+				quantities = append(quantities, order.Params.Get("quantity"))
+			}
+		}
+		return quantities
+	}
+
+	t.Run("splits by ratios", func(t *testing.T) {
+		manager, fake, _ := newHarnessManager(t)
+		req := threeTargets()
+		req.TakeProfitRatios = []decimal.Decimal{
+			decimal.RequireFromString("0.4"),
+			decimal.RequireFromString("0.3"),
+			decimal.RequireFromString("0.3"),
+		}
+
+		resp, err := manager.PlaceBracketOrder(ctx, req)
+		require.NoError(t, err)
+
+		assert.Equal(t, []string{"0.0004", "0.0003", "0.0003"}, tpQuantities(fake))
+		manager.mu.RLock()
+		recorded := manager.orders[resp.BracketOrderID].TakeProfitQuantities
+		manager.mu.RUnlock()
+		require.Len(t, recorded, 3)
+		assert.Equal(t, "0.0004", recorded[0].String())
+	})
+
+	t.Run("even split gives the rounding remainder to the last leg", func(t *testing.T) {
+		manager, fake, _ := newHarnessManager(t)
+
+		_, err := manager.PlaceBracketOrder(ctx, threeTargets())
+		require.NoError(t, err)
+
+		// 0.001 / 3 rounds down to 0.00033 at the 0.00001 step size
+		assert.Equal(t, []string{"0.00033", "0.00033", "0.00034"}, tpQuantities(fake))
+	})
+
+	t.Run("rejects ratios that do not sum to one", func(t *testing.T) {
+		manager, fake, _ := newHarnessManager(t)
+		req := threeTargets()
+		req.TakeProfitRatios = []decimal.Decimal{
+			decimal.RequireFromString("0.4"),
+			decimal.RequireFromString("0.4"),
+			decimal.RequireFromString("0.3"),
+		}
+
+		_, err := manager.PlaceBracketOrder(ctx, req)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "take profit ratios must sum to 1, got 1.1")
+		assert.Empty(t, fake.Orders())
+	})
+
+	t.Run("rejects legs below the minimum quantity", func(t *testing.T) {
+		manager, fake, _ := newHarnessManager(t)
+		req := threeTargets()
+		req.Quantity = decimal.RequireFromString("0.0001")
+		req.TakeProfitRatios = []decimal.Decimal{
+			decimal.RequireFromString("0.95"),
+			decimal.RequireFromString("0.04"),
+			decimal.RequireFromString("0.01"),
+		}
+
+		_, err := manager.PlaceBracketOrder(ctx, req)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "failed to split take profit quantity")
+		assert.Empty(t, fake.Orders())
+	})
+}
+
+func TestCancelBracket_Integration(t *testing.T) {
+	ctx := context.Background()
+	manager, fake, emitter := newHarnessManager(t)
+
+	target, err := manager.PlaceBracketOrder(ctx, harnessBracketRequest())
+	require.NoError(t, err)
+	other, err := manager.PlaceBracketOrder(ctx, harnessBracketRequest())
+	require.NoError(t, err)
+
+	// An order placed outside any bracket
+	_, err = manager.spotClient.PlaceSpotOrder(ctx, binance.SpotOrderRequest{
+		Symbol:           "BTCUSDT",
+		Side:             "BUY",
+		Type:             "LIMIT",
+		Quantity:         decimal.RequireFromString("0.001"),
+		Price:            decimal.NewFromInt(45000),
+		TimeInForce:      "GTC",
+		NewClientOrderID: "manual-order-1",
+	})
+	require.NoError(t, err)
+
+	require.NoError(t, manager.CancelBracket(ctx, target.BracketOrderID))
+
+	targetIDs := map[string]bool{
+		target.ClientOrderIDs.Main:           true,
+		target.ClientOrderIDs.TakeProfits[0]: true,
+		target.ClientOrderIDs.StopLoss:       true,
+	}
+	placed := fake.Orders()
+	require.Len(t, placed, 7)
+	for _, order := range placed {
+		if targetIDs[order.ClientOrderID()] {
+			assert.Equal(t, "CANCELED", order.Status, order.ClientOrderID())
+		} else {
+			assert.Equal(t, "NEW", order.Status, order.ClientOrderID())
+		}
+	}
+
+	var canceled []string
+	for _, update := range emitter.Updates() {
+		if update.Status == "CANCELED" {
+			canceled = append(canceled, update.ClientOrderID)
+		}
+	}
+	assert.ElementsMatch(t, []string{
+		target.ClientOrderIDs.Main,
+		target.ClientOrderIDs.TakeProfits[0],
+		target.ClientOrderIDs.StopLoss,
+	}, canceled)
+
+	// The other bracket is untouched and still cancellable
+	require.NoError(t, manager.CancelBracket(ctx, other.BracketOrderID))
+
+	err = manager.CancelBracket(ctx, "unknown-bracket")
+	require.ErrorIs(t, err, ErrBracketNotFound)
+}
+
+func TestCancelBracket_IgnoresOrdersSharingThePrefix(t *testing.T) {
+	ctx := context.Background()
+	manager, fake, _ := newHarnessManager(t)
+
+	target, err := manager.PlaceBracketOrder(ctx, harnessBracketRequest())
+	require.NoError(t, err)
+
+	// A leg of another bracket whose ID starts with the same 8 characters
+	lookalike := bracketPrefix(target.BracketOrderID) + "TP1_1"
+	_, err = manager.spotClient.PlaceSpotOrder(ctx, binance.SpotOrderRequest{
+		Symbol:           "BTCUSDT",
+		Side:             "SELL",
+		Type:             "LIMIT",
+		Quantity:         decimal.RequireFromString("0.001"),
+		Price:            decimal.NewFromInt(55000),
+		TimeInForce:      "GTC",
+		NewClientOrderID: lookalike,
+	})
+	require.NoError(t, err)
+
+	require.NoError(t, manager.CancelBracket(ctx, target.BracketOrderID))
+
+	statuses := orderStatuses(fake)
+	assert.Equal(t, "CANCELED", statuses[target.ClientOrderIDs.StopLoss])
+	assert.Equal(t, "NEW", statuses[lookalike])
+}
+
+func TestCancelBracket_ReportsFailedLegs(t *testing.T) {
+	ctx := context.Background()
+	manager, fake, _ := newHarnessManager(t)
+
+	resp, err := manager.PlaceBracketOrder(ctx, harnessBracketRequest())
+	require.NoError(t, err)
+
+	mainOrderID := strconv.FormatInt(fake.Orders()[0].OrderID, 10)
+	fake.InjectErrorFor(func(params url.Values) bool {
+		return params.Get("orderId") == mainOrderID
+	}, -2011, "Unknown order sent.")
+
+	err = manager.CancelBracket(ctx, resp.BracketOrderID)
+	require.Error(t, err)
+
+	var bracketErr *BracketOrderError
+	require.ErrorAs(t, err, &bracketErr)
+	require.Len(t, bracketErr.Errors, 1)
+	assert.Equal(t, "MAIN", bracketErr.Errors[0].OrderType)
+
+	// The remaining legs were still cancelled
+	for _, order := range fake.Orders()[1:] {
+		assert.Equal(t, "CANCELED", order.Status, order.ClientOrderID())
+	}
+}
+
+func TestMaxBracketsPerSymbol_Integration(t *testing.T) {
+	ctx := context.Background()
+	manager, fake, _ := newHarnessManager(t)
+	WithMaxBracketsPerSymbol(2)(manager)
+
+	first, err := manager.PlaceBracketOrder(ctx, harnessBracketRequest())
+	require.NoError(t, err)
+	_, err = manager.PlaceBracketOrder(ctx, harnessBracketRequest())
+	require.NoError(t, err)
+	assert.Equal(t, 2, manager.OpenBrackets("BTCUSDT"))
+
+	_, err = manager.PlaceBracketOrder(ctx, harnessBracketRequest())
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "maximum open brackets reached for BTCUSDT: 2 of 2")
+	assert.Len(t, fake.Orders(), 6, "rejected bracket must not reach the exchange")
+
+	// A rejected entry does not hold a slot
+	require.NoError(t, manager.CancelBracket(ctx, first.BracketOrderID))
+	fake.InjectError(-2010, "Account has insufficient balance for requested action.")
+	_, err = manager.PlaceBracketOrder(ctx, harnessBracketRequest())
+	require.Error(t, err)
+	assert.Equal(t, 1, manager.OpenBrackets("BTCUSDT"))
+
+	// Closing through the monitor frees a slot too
+	third, err := manager.PlaceBracketOrder(ctx, harnessBracketRequest())
+	require.NoError(t, err)
+	monitor := NewBracketMonitor(manager, zerolog.Nop())
+	require.NoError(t, monitor.HandleOrderUpdate(filledEvent(third.ClientOrderIDs.StopLoss)))
+	assert.Equal(t, 1, manager.OpenBrackets("BTCUSDT"))
+
+	_, err = manager.PlaceBracketOrder(ctx, harnessBracketRequest())
+	assert.NoError(t, err)
+}
+
+func TestBracketSlotRelease_Integration(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("close all frees the slots of cancelled brackets", func(t *testing.T) {
+		manager, fake, _ := newHarnessManager(t)
+		WithMaxBracketsPerSymbol(1)(manager)
+
+		_, err := manager.PlaceBracketOrder(ctx, harnessBracketRequest())
+		require.NoError(t, err)
+		require.NoError(t, manager.CloseAllPositions(ctx, &CloseAllRequest{Symbol: "BTCUSDT"}))
+		assert.Equal(t, 0, manager.OpenBrackets("BTCUSDT"))
+
+		// A leg that could not be cancelled keeps its bracket open
+		resp, err := manager.PlaceBracketOrder(ctx, harnessBracketRequest())
+		require.NoError(t, err)
+		fake.InjectError(-1001, "Internal error; unable to process your request. Please try again.")
+		require.Error(t, manager.CloseAllPositions(ctx, &CloseAllRequest{Symbol: "BTCUSDT"}))
+		assert.Equal(t, 1, manager.OpenBrackets("BTCUSDT"))
+		assert.NotEqual(t, BracketStateClosed, bracketState(manager, resp.BracketOrderID))
+	})
+
+	t.Run("reconcile records a missed stop loss fill", func(t *testing.T) {
+		manager, fake, _ := newHarnessManager(t)
+		WithMaxBracketsPerSymbol(1)(manager)
+
+		resp, err := manager.PlaceBracketOrder(ctx, harnessBracketRequest())
+		require.NoError(t, err)
+		require.True(t, fake.FillOrder(resp.ClientOrderIDs.StopLoss))
+
+		require.NoError(t, manager.ReconcileOrder(ctx, resp.ClientOrderIDs.StopLoss))
+		assert.Equal(t, 0, manager.OpenBrackets("BTCUSDT"))
+		assert.Equal(t, "CANCELED", orderStatuses(fake)[resp.ClientOrderIDs.TakeProfits[0]], "opposing take profit is cancelled")
+
+		_, err = manager.PlaceBracketOrder(ctx, harnessBracketRequest())
+		assert.NoError(t, err)
+	})
+
+	t.Run("reconcile keeps a bracket with legs still open", func(t *testing.T) {
+		manager, _, _ := newHarnessManager(t)
+
+		resp, err := manager.PlaceBracketOrder(ctx, harnessBracketRequest())
+		require.NoError(t, err)
+		require.NoError(t, manager.ReconcileOrder(ctx, resp.ClientOrderIDs.Main))
+		assert.Equal(t, 1, manager.OpenBrackets("BTCUSDT"))
+	})
+}
+
+func TestMaxNumOrders_Integration(t *testing.T) {
+	ctx := context.Background()
+	manager, fake, _ := newHarnessManager(t)
+	symbol := testutil.BTCUSDT
+	symbol.MaxNumOrders = 5
+	fake.AddSymbol(symbol)
+
+	// Entry, take profit and stop loss: 3 of 5
+	_, err := manager.PlaceBracketOrder(ctx, harnessBracketRequest())
+	require.NoError(t, err)
+	require.Len(t, fake.Orders(), 3)
+
+	// A laddered exit needs 4 more, which would leave 7 open
+	ladder := harnessBracketRequest()
+	ladder.TakeProfitPrices = []decimal.Decimal{decimal.NewFromInt(51000), decimal.NewFromInt(52000)}
+	_, err = manager.PlaceBracketOrder(ctx, ladder)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "bracket needs 4 orders but BTCUSDT already has 3 open of 5 allowed")
+	assert.Len(t, fake.Orders(), 3, "no leg of a rejected bracket may reach the exchange")
+	assert.Equal(t, 1, manager.OpenBrackets("BTCUSDT"), "rejected bracket must not hold a slot")
+
+	// A ladder that cannot fit even on an empty book is rejected without
+	// counting open orders
+	before := fake.RequestCount("/api/v3/openOrders")
+	ladder.TakeProfitPrices = []decimal.Decimal{
+		decimal.NewFromInt(51000), decimal.NewFromInt(52000), decimal.NewFromInt(53000), decimal.NewFromInt(54000),
+	}
+	_, err = manager.PlaceBracketOrder(ctx, ladder)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "bracket needs 6 orders but BTCUSDT allows at most 5 open orders")
+	assert.Equal(t, before, fake.RequestCount("/api/v3/openOrders"))
+	assert.Len(t, fake.Orders(), 3)
+}
+
+func TestDailyNotionalCap_Integration(t *testing.T) {
+	ctx := context.Background()
+	manager, fake, _ := newHarnessManager(t)
+	WithDailyNotionalCap(decimal.NewFromInt(150), 0)(manager)
+
+	// Each harness bracket commits 0.00123 * 50000 = 61.5 USDT
+	for i := 0; i < 2; i++ {
+		_, err := manager.PlaceBracketOrder(ctx, harnessBracketRequest())
+		require.NoError(t, err)
+	}
+	remaining, ok := manager.RemainingNotional()
+	require.True(t, ok)
+	assert.Equal(t, "27", remaining.String())
+
+	_, err := manager.PlaceBracketOrder(ctx, harnessBracketRequest())
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "bracket needs 61.5, remaining budget is 27 of 150")
+	assert.Len(t, fake.Orders(), 6)
+
+	// A smaller bracket still fits
+	req := harnessBracketRequest()
+	req.Quantity = decimal.RequireFromString("0.0005")
+	_, err = manager.PlaceBracketOrder(ctx, req)
+	require.NoError(t, err)
+
+	remaining, _ = manager.RemainingNotional()
+	assert.Equal(t, "2", remaining.String())
+}
+
+func TestKillSwitch_Integration(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("rejects placements while engaged", func(t *testing.T) {
+		manager, fake, _ := newHarnessManager(t)
+
+		state, err := manager.EngageKillSwitch(ctx, &KillSwitchRequest{Reason: "runaway strategy"})
+		require.NoError(t, err)
+		assert.True(t, state.Engaged)
+		assert.Equal(t, "runaway strategy", state.Reason)
+		assert.False(t, state.EngagedAt.IsZero())
+
+		_, err = manager.PlaceBracketOrder(ctx, harnessBracketRequest())
+		require.ErrorIs(t, err, ErrKillSwitchEngaged)
+		assert.Contains(t, err.Error(), "runaway strategy")
+		assert.Empty(t, fake.Orders())
+
+		manager.ReleaseKillSwitch()
+		assert.False(t, manager.KillSwitch().Engaged)
+
+		_, err = manager.PlaceBracketOrder(ctx, harnessBracketRequest())
+		require.NoError(t, err)
+		assert.Len(t, fake.Orders(), 3)
+	})
+
+	t.Run("closes configured symbols", func(t *testing.T) {
+		manager, fake, _ := newHarnessManager(t)
+		WithKillSwitchSymbols("BTCUSDT")(manager)
+
+		_, err := manager.PlaceBracketOrder(ctx, harnessBracketRequest())
+		require.NoError(t, err)
+
+		_, err = manager.EngageKillSwitch(ctx, &KillSwitchRequest{Reason: "exchange incident", ClosePositions: true})
+		require.NoError(t, err)
+
+		for _, order := range fake.Orders() {
+			assert.Equal(t, "CANCELED", order.Status, order.ClientOrderID())
+		}
+	})
+
+	t.Run("requires a reason", func(t *testing.T) {
+		manager, _, _ := newHarnessManager(t)
+
+		_, err := manager.EngageKillSwitch(ctx, &KillSwitchRequest{})
+		require.Error(t, err)
+		assert.False(t, manager.KillSwitch().Engaged)
+	})
+}
+
+// newFuturesHarnessManager wires a futures-only Manager to a fake Binance server
+func newFuturesHarnessManager(t *testing.T) (*Manager, *testutil.FakeBinance) {
+	t.Helper()
+
+	fake := testutil.NewFakeBinance(t)
+	fake.AddSymbol(testutil.BTCUSDT)
+
+	futures, err := binance.NewFuturesClient(&config.BinanceConfig{
+		FuturesAPIKey:        "test-key",
+		FuturesSecretKey:     "test-secret",
+		Timeout:              5 * time.Second,
+		ExchangeInfoCacheTTL: time.Minute,
+		AccountCacheTTL:      binance.DefaultAccountCacheTTL,
+	}, fake.URL(), zerolog.Nop())
+	require.NoError(t, err)
+
+	return NewManager(nil, futures, nil, zerolog.Nop()), fake
+}
+
+func TestMaxQuantity_Integration(t *testing.T) {
+	ctx := context.Background()
+	manager, fake := newFuturesHarnessManager(t)
+	fake.SetBalance("USDT", decimal.RequireFromString("123.45"))
+	fake.SetMarkPrice("BTCUSDT", decimal.RequireFromString("61234.5"))
+
+	// 123.45 * 7 / 61234.5 = 0.0141121..., floored to the 0.00001 step
+	quantity, err := manager.MaxQuantity(ctx, "BTCUSDT", 7)
+	require.NoError(t, err)
+	assert.Equal(t, "0.01411", quantity.String())
+
+	_, err = manager.MaxQuantity(ctx, "BTCUSDT", 0)
+	assert.ErrorContains(t, err, "leverage must be positive")
+
+	_, err = manager.MaxQuantity(ctx, "ETHUSDT", 5)
+	assert.ErrorContains(t, err, "mark price")
+
+	spotOnly, _, _ := newHarnessManager(t)
+	_, err = spotOnly.MaxQuantity(ctx, "BTCUSDT", 5)
+	assert.ErrorContains(t, err, "futures venue is not enabled")
+}
+
+func TestPlaceFuturesBracketOrder_Integration(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("one-way mode uses reduce-only exits", func(t *testing.T) {
+		manager, fake := newFuturesHarnessManager(t)
+		req := harnessBracketRequest()
+		req.IsFutures = true
+		req.WorkingType = binance.WorkingTypeMarkPrice
+
+		resp, err := manager.PlaceBracketOrder(ctx, req)
+		require.NoError(t, err)
+		assert.False(t, resp.PartialFailure, "errors: %v", resp.Errors)
+
+		placed := fake.Orders()
+		require.Len(t, placed, 3)
+
+		sl := placed[2]
+		assert.Equal(t, "/fapi/v1/order", sl.Path)
+		assert.Equal(t, "STOP_MARKET", sl.Type())
+		assert.Equal(t, "49000", sl.Params.Get("stopPrice"))
+		assert.Equal(t, "true", sl.Params.Get("reduceOnly"))
+		assert.Empty(t, sl.Params.Get("closePosition"))
+		assert.Empty(t, sl.Params.Get("positionSide"))
+		assert.Equal(t, "MARK_PRICE", sl.Params.Get("workingType"))
+	})
+
+	t.Run("hedge mode tags every leg with the position side", func(t *testing.T) {
+		manager, fake := newFuturesHarnessManager(t)
+		fake.SetHedgeMode(true)
+		req := harnessBracketRequest()
+		req.IsFutures = true
+
+		resp, err := manager.PlaceBracketOrder(ctx, req)
+		require.NoError(t, err)
+		assert.False(t, resp.PartialFailure, "errors: %v", resp.Errors)
+
+		placed := fake.Orders()
+		require.Len(t, placed, 3)
+		for _, order := range placed {
+			assert.Equal(t, "LONG", order.Params.Get("positionSide"), order.Type())
+			assert.Empty(t, order.Params.Get("reduceOnly"), order.Type())
+		}
+	})
+
+	t.Run("hedge mode short entry uses SHORT", func(t *testing.T) {
+		manager, fake := newFuturesHarnessManager(t)
+		fake.SetHedgeMode(true)
+		req := harnessBracketRequest()
+		req.IsFutures = true
+		req.Side = "SELL"
+		req.TakeProfitPrices = []decimal.Decimal{decimal.NewFromInt(49000)}
+		req.StopLossPrice = decimal.NewFromInt(51000)
+
+		resp, err := manager.PlaceBracketOrder(ctx, req)
+		require.NoError(t, err)
+		assert.False(t, resp.PartialFailure, "errors: %v", resp.Errors)
+
+		for _, order := range fake.Orders() {
+			assert.Equal(t, "SHORT", order.Params.Get("positionSide"), order.Type())
+		}
+	})
+}
+
+func TestBracketExitLegsOutliveRequestDeadline_Integration(t *testing.T) {
+	isMain := func(params url.Values) bool {
+		return bracketLeg(params.Get("newClientOrderId")) == "MAIN"
+	}
+
+	t.Run("spot", func(t *testing.T) {
+		manager, fake, _ := newHarnessManager(t)
+		fake.SetLatencyAfter(isMain, 150*time.Millisecond)
+
+		ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+		defer cancel()
+		resp, err := manager.PlaceBracketOrder(ctx, harnessBracketRequest())
+		require.NoError(t, err)
+		assert.False(t, resp.PartialFailure, "errors: %v", resp.Errors)
+		assert.NotEmpty(t, resp.ClientOrderIDs.StopLoss)
+		require.Len(t, fake.Orders(), 3)
+		assert.Equal(t, "STOP_LOSS_LIMIT", fake.Orders()[2].Type())
+	})
+
+	t.Run("futures", func(t *testing.T) {
+		manager, fake := newFuturesHarnessManager(t)
+		fake.SetLatencyAfter(isMain, 150*time.Millisecond)
+		req := harnessBracketRequest()
+		req.IsFutures = true
+
+		ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+		defer cancel()
+		resp, err := manager.PlaceBracketOrder(ctx, req)
+		require.NoError(t, err)
+		assert.False(t, resp.PartialFailure, "errors: %v", resp.Errors)
+		require.Len(t, fake.Orders(), 3)
+		assert.Equal(t, "STOP_MARKET", fake.Orders()[2].Type())
+	})
+}
+
+func TestDuplicateClientOrderID_Integration(t *testing.T) {
+	ctx := context.Background()
+	signer := auth.NewSigner("test-key", "test-secret")
+
+	t.Run("spot leg placed before a lost response", func(t *testing.T) {
+		fake := testutil.NewFakeBinance(t)
+		fake.AddSymbol(testutil.BTCUSDT)
+		// The entry is accepted but the connection resets, so the client
+		// retries and Binance rejects the retry as a duplicate
+		fake.DropResponseFor(testutil.OrderTypeIs("LIMIT"))
+
+		restClient := rest.NewClient(fake.URL(), signer, rest.WithMaxRetries(1))
+		spot, err := binance.NewClient(fake.URL(), signer, restClient, zerolog.Nop())
+		require.NoError(t, err)
+		manager := NewManager(spot, nil, nil, zerolog.Nop())
+
+		resp, err := manager.PlaceBracketOrder(ctx, harnessBracketRequest())
+		require.NoError(t, err)
+		assert.False(t, resp.PartialFailure)
+		assert.NotEmpty(t, resp.ClientOrderIDs.Main)
+
+		placed := fake.Orders()
+		require.Len(t, placed, 3, "the entry is placed once")
+		assert.Equal(t, resp.ClientOrderIDs.Main, placed[0].ClientOrderID())
+		assert.Equal(t, 5, fake.RequestCount("/api/v3/order"), "three placements, a retry and a lookup")
+	})
+
+	t.Run("futures leg placed before a lost response", func(t *testing.T) {
+		fake := testutil.NewFakeBinance(t)
+		fake.AddSymbol(testutil.BTCUSDT)
+		fake.DropResponseFor(testutil.OrderTypeIs("STOP_MARKET"))
+
+		futures, err := binance.NewFuturesClient(&config.BinanceConfig{
+			FuturesAPIKey:    "test-key",
+			FuturesSecretKey: "test-secret",
+			Timeout:          5 * time.Second,
+			MaxRetries:       1,
+		}, fake.URL(), zerolog.Nop())
+		require.NoError(t, err)
+		manager := NewManager(nil, futures, nil, zerolog.Nop())
+
+		req := harnessBracketRequest()
+		req.IsFutures = true
+		resp, err := manager.PlaceBracketOrder(ctx, req)
+		require.NoError(t, err)
+		assert.False(t, resp.PartialFailure)
+		assert.NotEmpty(t, resp.ClientOrderIDs.StopLoss)
+
+		placed := fake.Orders()
+		require.Len(t, placed, 3)
+		assert.Equal(t, resp.ClientOrderIDs.StopLoss, placed[2].ClientOrderID())
+	})
+
+	t.Run("unconfirmed duplicate still fails the leg", func(t *testing.T) {
+		manager, fake, _ := newHarnessManager(t)
+		fake.InjectErrorFor(testutil.OrderTypeIs("STOP_LOSS_LIMIT"), -2010, "Duplicate order sent.")
+
+		resp, err := manager.PlaceBracketOrder(ctx, harnessBracketRequest())
+		require.NoError(t, err)
+		assert.True(t, resp.PartialFailure)
+		assert.Empty(t, resp.ClientOrderIDs.StopLoss)
+		require.Len(t, resp.Errors, 1)
+		assert.Contains(t, resp.Errors[0], "Duplicate order sent")
+	})
+}
+
+func TestQuantityBounds_Integration(t *testing.T) {
+	ctx := context.Background()
+
+	// LOT_SIZE bounds of 0.001 to 0.01 BTC
+	newBoundedManager := func(t *testing.T) (*Manager, *testutil.FakeBinance) {
+		t.Helper()
+		manager, fake, _ := newHarnessManager(t)
+		symbol := testutil.BTCUSDT
+		symbol.MinQty = "0.001"
+		symbol.MaxQty = "0.01"
+		fake.AddSymbol(symbol)
+		return manager, fake
+	}
+
+	tests := []struct {
+		name         string
+		quantity     string
+		wantQuantity string
+		wantWarning  string
+		wantErr      string
+	}{
+		{
+			name:         "clamps below minimum",
+			quantity:     "0.0004",
+			wantQuantity: "0.001",
+			wantWarning:  "quantity 0.0004 is below minimum 0.001 for BTCUSDT, clamped to 0.001",
+		},
+		{
+			name:         "clamps above maximum",
+			quantity:     "0.5",
+			wantQuantity: "0.01",
+			wantWarning:  "quantity 0.5 is above maximum 0.01 for BTCUSDT, clamped to 0.01",
+		},
+		{
+			name:         "leaves in-bounds quantity alone",
+			quantity:     "0.002",
+			wantQuantity: "0.002",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			manager, fake := newBoundedManager(t)
+			req := harnessBracketRequest()
+			req.Quantity = decimal.RequireFromString(tt.quantity)
+			req.ClampToFilters = true
+
+			resp, err := manager.PlaceBracketOrder(ctx, req)
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantQuantity, resp.Quantity.String())
+			if tt.wantWarning != "" {
+				assert.Equal(t, []string{tt.wantWarning}, resp.Warnings)
+			} else {
+				assert.Empty(t, resp.Warnings)
+			}
+
+			placed := fake.Orders()
+			require.NotEmpty(t, placed)
+			assert.Equal(t, tt.wantQuantity, placed[0].Params.Get("quantity"))
+		})
+	}
+
+	t.Run("rejects out-of-bounds quantity without clamping", func(t *testing.T) {
+		for _, quantity := range []string{"0.0004", "0.5"} {
+			manager, fake := newBoundedManager(t)
+			req := harnessBracketRequest()
+			req.Quantity = decimal.RequireFromString(quantity)
+
+			_, err := manager.PlaceBracketOrder(ctx, req)
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), "quantity "+quantity+" is")
+			assert.Equal(t, 0, fake.RequestCount("/api/v3/order"))
+		}
+	})
+}
@@ -2,8 +2,12 @@ package orders
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"slices"
+	"strings"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/google/uuid"
@@ -12,10 +16,14 @@ import (
 	"router/internal/binance"
 )
 
+// ErrBracketNotFound is returned for a bracket ID the manager does not track
+var ErrBracketNotFound = errors.New("bracket not found")
+
 // Manager manages order lifecycle with idempotency
 type Manager struct {
 	spotClient    *binance.Client
 	futuresClient *binance.Client
+	profiles      map[string]*binance.Client // API key profile name -> client
 
 	// Order tracking
 	orders         map[string]*BracketOrder // bracket order ID -> order
@@ -24,94 +32,197 @@ type Manager struct {
 
 	// Event emitter
 	eventEmitter EventEmitter
+	auditLogger  *AuditLogger
+
+	// Risk limits
+	maxBracketsPerSymbol int // zero means unlimited
+	maxTakeProfitLegs    int // zero means unlimited
+	breakevenFeeRate     decimal.Decimal
+	openBrackets         map[string]int // symbol -> brackets not yet closed
+	notional             *notionalBudget
+	killSwitch           atomic.Pointer[KillSwitchState]
+	killSwitchSymbols    []string // closed when the kill switch asks to
 
 	// Logger
 	logger zerolog.Logger
 }
 
+// ManagerOption configures a Manager
+type ManagerOption func(*Manager)
+
+// WithAuditLogger records every placement, cancellation and close to audit
+func WithAuditLogger(audit *AuditLogger) ManagerOption {
+	return func(m *Manager) {
+		m.auditLogger = audit
+	}
+}
+
+// WithMaxBracketsPerSymbol rejects new brackets once max are open on a
+// symbol. Zero disables the limit.
+func WithMaxBracketsPerSymbol(max int) ManagerOption {
+	return func(m *Manager) {
+		m.maxBracketsPerSymbol = max
+	}
+}
+
+// WithMaxTakeProfitLegs rejects brackets with more than max take profits.
+// Zero disables the limit.
+func WithMaxTakeProfitLegs(max int) ManagerOption {
+	return func(m *Manager) {
+		m.maxTakeProfitLegs = max
+	}
+}
+
+// DefaultBreakevenFeeRate is the per-side fee assumed when moving a stop to
+// breakeven, Binance's base spot taker rate
+var DefaultBreakevenFeeRate = decimal.RequireFromString("0.001")
+
+// WithBreakevenFeeRate sets the per-side fee rate a breakeven stop covers.
+// The stop is offset from the entry by twice the rate, paying for both the
+// entry and the exit.
+func WithBreakevenFeeRate(rate decimal.Decimal) ManagerOption {
+	return func(m *Manager) {
+		m.breakevenFeeRate = rate
+	}
+}
+
 // EventEmitter defines interface for emitting order events
 type EventEmitter interface {
 	EmitOrderUpdate(ctx context.Context, update *OrderUpdate) error
 }
 
+// WithDailyNotionalCap rejects brackets once their combined notional for the
+// day would exceed cap. The day starts resetOffset after UTC midnight.
+func WithDailyNotionalCap(cap decimal.Decimal, resetOffset time.Duration) ManagerOption {
+	return func(m *Manager) {
+		if cap.IsPositive() {
+			m.notional = newNotionalBudget(cap, resetOffset)
+		}
+	}
+}
+
+// WithKillSwitchSymbols sets the symbols whose open orders are closed when
+// the kill switch is engaged with ClosePositions
+func WithKillSwitchSymbols(symbols ...string) ManagerOption {
+	return func(m *Manager) {
+		m.killSwitchSymbols = symbols
+	}
+}
+
 // NewManager creates a new order manager
-func NewManager(spotClient, futuresClient *binance.Client, eventEmitter EventEmitter, logger zerolog.Logger) *Manager {
-	return &Manager{
-		spotClient:     spotClient,
-		futuresClient:  futuresClient,
-		orders:         make(map[string]*BracketOrder),
-		ordersByClient: make(map[string]string),
-		eventEmitter:   eventEmitter,
-		logger:         logger,
+func NewManager(spotClient, futuresClient *binance.Client, eventEmitter EventEmitter, logger zerolog.Logger, opts ...ManagerOption) *Manager {
+	m := &Manager{
+		spotClient:       spotClient,
+		futuresClient:    futuresClient,
+		orders:           make(map[string]*BracketOrder),
+		ordersByClient:   make(map[string]string),
+		openBrackets:     make(map[string]int),
+		breakevenFeeRate: DefaultBreakevenFeeRate,
+		eventEmitter:     eventEmitter,
+		logger:           logger,
+	}
+
+	for _, opt := range opts {
+		opt(m)
 	}
+
+	return m
 }
 
 // PlaceBracketOrder places a bracket order with idempotency
-func (m *Manager) PlaceBracketOrder(ctx context.Context, req *PlaceBracketRequest) (*PlaceBracketResponse, error) {
+func (m *Manager) PlaceBracketOrder(ctx context.Context, req *PlaceBracketRequest) (resp *PlaceBracketResponse, err error) {
+	defer func() { m.auditPlacement(ctx, req, resp, err) }()
+
+	if err := m.checkKillSwitch(); err != nil {
+		return nil, err
+	}
+
 	// Validate request
 	if err := m.validateBracketRequest(req); err != nil {
 		return nil, fmt.Errorf("invalid bracket request: %w", err)
 	}
 
 	// Select client
-	client := m.spotClient
+	client, err := m.clientFor(req.IsFutures, req.Profile)
+	if err != nil {
+		return nil, err
+	}
 	orderType := OrderTypeSpot
 	if req.IsFutures {
-		client = m.futuresClient
 		orderType = OrderTypeFutures
 	}
 
-	// Round prices and quantities
-	roundedQty, err := client.RoundQuantity(ctx, req.Symbol, req.Quantity)
+	// Reject or clamp quantities outside the symbol's LOT_SIZE bounds
+	quantityWarning, err := checkQuantityBounds(ctx, client, req)
 	if err != nil {
-		return nil, fmt.Errorf("failed to round quantity: %w", err)
+		return nil, fmt.Errorf("invalid bracket request: %w", err)
 	}
-	req.Quantity = roundedQty
 
+	// Round prices and quantities in one pass over the symbol's rules. The
+	// stop loss comes first, then the take profits, then any entry price.
+	prices := append([]decimal.Decimal{req.StopLossPrice}, req.TakeProfitPrices...)
 	if !req.EntryPrice.IsZero() {
-		roundedPrice, err := client.RoundPrice(ctx, req.Symbol, req.EntryPrice)
-		if err != nil {
-			return nil, fmt.Errorf("failed to round entry price: %w", err)
-		}
-		req.EntryPrice = roundedPrice
+		prices = append(prices, req.EntryPrice)
 	}
-
-	// Round TP prices
-	for i, tp := range req.TakeProfitPrices {
-		rounded, err := client.RoundPrice(ctx, req.Symbol, tp)
-		if err != nil {
-			return nil, fmt.Errorf("failed to round TP price %d: %w", i, err)
-		}
-		req.TakeProfitPrices[i] = rounded
+	roundedPrices, roundedQuantities, err := client.RoundOrderValues(ctx, req.Symbol, prices, []decimal.Decimal{req.Quantity})
+	if err != nil {
+		return nil, fmt.Errorf("failed to round order values: %w", err)
+	}
+	req.Quantity = roundedQuantities[0]
+	req.StopLossPrice = roundedPrices[0]
+	copy(req.TakeProfitPrices, roundedPrices[1:])
+	if !req.EntryPrice.IsZero() {
+		req.EntryPrice = roundedPrices[len(roundedPrices)-1]
 	}
 
-	// Round SL price
-	roundedSL, err := client.RoundPrice(ctx, req.Symbol, req.StopLossPrice)
+	tpQuantities, err := takeProfitQuantities(ctx, client, req)
 	if err != nil {
-		return nil, fmt.Errorf("failed to round SL price: %w", err)
+		return nil, fmt.Errorf("failed to split take profit quantity: %w", err)
 	}
-	req.StopLossPrice = roundedSL
 
 	// Validate notional
 	if err := client.ValidateNotional(ctx, req.Symbol, req.EntryPrice, req.Quantity); err != nil {
 		return nil, fmt.Errorf("notional validation failed: %w", err)
 	}
 
+	// Catch legs Binance would reject for straying too far from the market
+	if err := client.ValidatePercentPrice(ctx, req.Symbol, roundedPrices...); err != nil {
+		return nil, fmt.Errorf("percent price validation failed: %w", err)
+	}
+
+	// Reject up front rather than fail halfway through placing the legs
+	if err := checkOpenOrderLimit(ctx, client, req); err != nil {
+		return nil, err
+	}
+
+	// Claim risk budget before touching the exchange so concurrent
+	// placements cannot overshoot the limits
+	reservation, err := m.reserveBracket(req)
+	if err != nil {
+		return nil, err
+	}
+
 	// Generate bracket order ID
 	bracketID := uuid.New().String()
 
 	// Create bracket order
 	bracket := &BracketOrder{
-		ID:               bracketID,
-		Symbol:           req.Symbol,
-		Type:             orderType,
-		Side:             req.Side,
-		Quantity:         req.Quantity,
-		EntryPrice:       req.EntryPrice,
-		TakeProfitPrices: req.TakeProfitPrices,
-		StopLossPrice:    req.StopLossPrice,
-		CreatedAt:        time.Now(),
-		UpdatedAt:        time.Now(),
+		ID:                       bracketID,
+		Symbol:                   req.Symbol,
+		Type:                     orderType,
+		Side:                     req.Side,
+		Quantity:                 req.Quantity,
+		EntryPrice:               req.EntryPrice,
+		TakeProfitPrices:         req.TakeProfitPrices,
+		TakeProfitQuantities:     tpQuantities,
+		StopLossPrice:            req.StopLossPrice,
+		State:                    BracketStatePending,
+		CreatedAt:                time.Now(),
+		UpdatedAt:                time.Now(),
+		WorkingType:              req.WorkingType,
+		Profile:                  req.Profile,
+		MoveStopToBreakevenOnTP1: req.MoveStopToBreakevenOnTP1,
+		filledLegs:               make(map[string]bool),
 	}
 
 	// Place the bracket orders
@@ -122,11 +233,14 @@ func (m *Manager) PlaceBracketOrder(ctx context.Context, req *PlaceBracketReques
 		Quantity:       req.Quantity,
 		CreatedAt:      bracket.CreatedAt,
 	}
+	if quantityWarning != "" {
+		response.Warnings = append(response.Warnings, quantityWarning)
+	}
 
 	if req.IsFutures {
-		bracket.ClientOrderIDs, err = m.placeFuturesBracket(ctx, client, req, bracketID)
+		bracket.ClientOrderIDs, err = m.placeFuturesBracket(ctx, client, req, bracketID, tpQuantities)
 	} else {
-		bracket.ClientOrderIDs, err = m.placeSpotBracket(ctx, client, req, bracketID)
+		bracket.ClientOrderIDs, err = m.placeSpotBracket(ctx, client, req, bracketID, tpQuantities)
 	}
 
 	response.ClientOrderIDs = bracket.ClientOrderIDs
@@ -136,6 +250,7 @@ func (m *Manager) PlaceBracketOrder(ctx context.Context, req *PlaceBracketReques
 		if bracketErr, ok := err.(*BracketOrderError); ok {
 			// Check if main order failed (critical error)
 			if bracketErr.HasCriticalError() {
+				m.releaseBracket(reservation)
 				return nil, fmt.Errorf("failed to place bracket order: %w", err)
 			}
 			// Main order succeeded but some orders failed
@@ -145,6 +260,7 @@ func (m *Manager) PlaceBracketOrder(ctx context.Context, req *PlaceBracketReques
 			}
 		} else {
 			// Non-bracket error
+			m.releaseBracket(reservation)
 			return nil, fmt.Errorf("failed to place bracket order: %w", err)
 		}
 	}
@@ -185,7 +301,10 @@ func (m *Manager) PlaceBracketOrder(ctx context.Context, req *PlaceBracketReques
 	return response, nil
 }
 
-// ReconcileOrder updates order status from exchange
+// ReconcileOrder updates order status from exchange. An order no longer open
+// is looked up: a fill the monitor missed is recorded, cancelling the
+// opposing legs, and the bracket's slot is freed once none of its legs are
+// left open.
 func (m *Manager) ReconcileOrder(ctx context.Context, clientOrderID string) error {
 	m.mu.RLock()
 	bracketID, exists := m.ordersByClient[clientOrderID]
@@ -200,9 +319,9 @@ func (m *Manager) ReconcileOrder(ctx context.Context, clientOrderID string) erro
 	m.mu.RUnlock()
 
 	// Select client
-	client := m.spotClient
-	if bracket.Type == OrderTypeFutures {
-		client = m.futuresClient
+	client, err := m.bracketClient(bracket)
+	if err != nil {
+		return err
 	}
 
 	// Get open orders
@@ -212,7 +331,9 @@ func (m *Manager) ReconcileOrder(ctx context.Context, clientOrderID string) erro
 	}
 
 	// Update status based on exchange data
+	open := make(map[string]bool, len(orders))
 	for _, order := range orders {
+		open[order.ClientOrderID] = true
 		if order.ClientOrderID == clientOrderID {
 			// Emit update if status changed
 			if m.eventEmitter != nil {
@@ -231,32 +352,70 @@ func (m *Manager) ReconcileOrder(ctx context.Context, clientOrderID string) erro
 				}
 				_ = m.eventEmitter.EmitOrderUpdate(ctx, update)
 			}
-			break
 		}
 	}
+	if open[clientOrderID] {
+		return nil
+	}
 
-	return nil
+	order, err := client.GetOrderByClientID(ctx, bracket.Symbol, clientOrderID)
+	if err != nil {
+		return err
+	}
+
+	var cancel []string
+	leg := bracketLeg(clientOrderID)
+	m.mu.Lock()
+	if order.Status == "FILLED" {
+		from := bracket.State
+		cancel = bracket.recordFill(leg)
+		if bracket.State == BracketStateClosed && from != BracketStateClosed {
+			m.releaseBracketLocked(bracket.Symbol)
+		}
+	}
+	if !slices.ContainsFunc(bracket.ClientOrderIDs.all(), func(id string) bool { return open[id] }) {
+		m.closeBracketLocked(bracket)
+	}
+	m.mu.Unlock()
+
+	if len(cancel) == 0 {
+		return nil
+	}
+	return m.cancelLegs(ctx, client, bracket, cancel, fmt.Sprintf("OCO: %s filled", leg))
 }
 
 // CancelOrder cancels an order
-func (m *Manager) CancelOrder(ctx context.Context, req *CancelRequest) error {
+func (m *Manager) CancelOrder(ctx context.Context, req *CancelRequest) (err error) {
+	defer func() {
+		entry := AuditEntry{Operation: AuditOpCancelOrder, Symbol: req.Symbol}
+		if req.OrderID > 0 {
+			entry.OrderIDs = append(entry.OrderIDs, formatOrderID(req.OrderID))
+		}
+		if req.ClientOrderID != "" {
+			entry.OrderIDs = append(entry.OrderIDs, req.ClientOrderID)
+		}
+		m.audit(ctx, entry, err)
+	}()
+
 	if req.Symbol == "" {
 		return fmt.Errorf("symbol is required")
 	}
 
 	// Determine which client to use based on symbol
-	// For simplicity, try spot first, then futures
-	var err error
-	if req.OrderID > 0 {
-		err = m.spotClient.CancelOrder(ctx, req.Symbol, req.OrderID)
-		if err != nil {
-			// Try futures
-			err = m.futuresClient.CancelOrder(ctx, req.Symbol, req.OrderID)
-		}
-	} else {
+	// For simplicity, try spot first, then futures, skipping disabled venues
+	if req.OrderID <= 0 {
 		return fmt.Errorf("order ID is required")
 	}
 
+	err = fmt.Errorf("no trading venue enabled")
+	if m.spotClient != nil {
+		err = m.spotClient.CancelOrder(ctx, req.Symbol, req.OrderID)
+	}
+	if err != nil && m.futuresClient != nil {
+		// Try futures
+		err = m.futuresClient.CancelOrder(ctx, req.Symbol, req.OrderID)
+	}
+
 	if err == nil && m.eventEmitter != nil {
 		// Emit cancellation event
 		update := &OrderUpdate{
@@ -274,6 +433,210 @@ func (m *Manager) CancelOrder(ctx context.Context, req *CancelRequest) error {
 	return err
 }
 
+// CancelBracket cancels every open leg of a bracket. Legs are matched on the
+// bracket's exact client order IDs: the shared prefix is too short to tell
+// brackets apart. Failed legs are reported together in a *BracketOrderError.
+func (m *Manager) CancelBracket(ctx context.Context, bracketID string) (err error) {
+	m.mu.RLock()
+	bracket, exists := m.orders[bracketID]
+	m.mu.RUnlock()
+
+	entry := AuditEntry{Operation: AuditOpCancelBracket, BracketOrderID: bracketID}
+	defer func() { m.audit(ctx, entry, err) }()
+
+	if !exists {
+		return fmt.Errorf("%w: %s", ErrBracketNotFound, bracketID)
+	}
+	entry.Venue = venueName(bracket.Type == OrderTypeFutures)
+	entry.Symbol = bracket.Symbol
+	entry.Side = bracket.Side
+	entry.Quantity = auditDecimal(bracket.Quantity)
+	entry.Price = auditDecimal(bracket.EntryPrice)
+	entry.OrderIDs = bracket.ClientOrderIDs.all()
+
+	client, err := m.bracketClient(bracket)
+	if err != nil {
+		return err
+	}
+
+	m.mu.RLock()
+	legs := bracket.ClientOrderIDs.all()
+	m.mu.RUnlock()
+	err = m.cancelOpenLegs(ctx, client, bracket, func(clientOrderID string) bool {
+		return slices.Contains(legs, clientOrderID)
+	}, "Bracket cancellation")
+	if err != nil {
+		return err
+	}
+
+	m.mu.Lock()
+	m.closeBracketLocked(bracket)
+	m.mu.Unlock()
+	return nil
+}
+
+// bracketReservation records what reserveBracket claimed for one placement
+type bracketReservation struct {
+	symbol     string
+	notional   decimal.Decimal
+	reservedIn time.Time // notional window the reservation was charged to
+}
+
+// reserveBracket claims an open-bracket slot on the symbol and the bracket's
+// notional from the daily budget, failing if either limit would be exceeded
+func (m *Manager) reserveBracket(req *PlaceBracketRequest) (*bracketReservation, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	symbol := req.Symbol
+	if m.maxBracketsPerSymbol > 0 && m.openBrackets[symbol] >= m.maxBracketsPerSymbol {
+		return nil, fmt.Errorf("maximum open brackets reached for %s: %d of %d",
+			symbol, m.openBrackets[symbol], m.maxBracketsPerSymbol)
+	}
+
+	reservation := &bracketReservation{symbol: symbol}
+	if m.notional != nil {
+		reservation.notional = bracketNotional(req)
+		if err := m.notional.reserve(reservation.notional); err != nil {
+			return nil, err
+		}
+		reservation.reservedIn = m.notional.windowStart
+	}
+
+	m.openBrackets[symbol]++
+	return reservation, nil
+}
+
+// releaseBracket returns what reserveBracket claimed for a bracket that
+// never reached the exchange
+func (m *Manager) releaseBracket(reservation *bracketReservation) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.releaseBracketLocked(reservation.symbol)
+	if m.notional != nil {
+		m.notional.refund(reservation.notional, reservation.reservedIn)
+	}
+}
+
+// RemainingNotional returns the daily notional budget left, or false when no
+// cap is configured
+func (m *Manager) RemainingNotional() (decimal.Decimal, bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if m.notional == nil {
+		return decimal.Zero, false
+	}
+	return m.notional.remaining(), true
+}
+
+func (m *Manager) releaseBracketLocked(symbol string) {
+	if m.openBrackets[symbol] <= 1 {
+		delete(m.openBrackets, symbol)
+		return
+	}
+	m.openBrackets[symbol]--
+}
+
+// closeBracketLocked marks the bracket closed and frees its slot. Callers
+// must hold m.mu.
+func (m *Manager) closeBracketLocked(bracket *BracketOrder) {
+	if bracket.State == BracketStateClosed {
+		return
+	}
+	bracket.State = BracketStateClosed
+	bracket.UpdatedAt = time.Now()
+	m.releaseBracketLocked(bracket.Symbol)
+}
+
+// OpenBrackets returns the number of brackets on symbol not yet closed
+func (m *Manager) OpenBrackets(symbol string) int {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return m.openBrackets[symbol]
+}
+
+// bracketClient returns the client for the bracket's venue
+func (m *Manager) bracketClient(bracket *BracketOrder) (*binance.Client, error) {
+	return m.clientFor(bracket.Type == OrderTypeFutures, bracket.Profile)
+}
+
+// cancelLegs cancels the bracket's open orders with the given client order IDs
+func (m *Manager) cancelLegs(ctx context.Context, client *binance.Client, bracket *BracketOrder, clientOrderIDs []string, reason string) error {
+	targets := make(map[string]bool, len(clientOrderIDs))
+	for _, id := range clientOrderIDs {
+		targets[id] = true
+	}
+	return m.cancelOpenLegs(ctx, client, bracket, func(clientOrderID string) bool {
+		return targets[clientOrderID]
+	}, reason)
+}
+
+// cancelOpenLegs cancels the bracket's open orders whose client order ID
+// matches, auditing each cancel and emitting a CANCELED update with reason
+// for each
+func (m *Manager) cancelOpenLegs(ctx context.Context, client *binance.Client, bracket *BracketOrder, match func(string) bool, reason string) error {
+	openOrders, err := client.GetOpenOrders(ctx, bracket.Symbol)
+	if err != nil {
+		return fmt.Errorf("failed to get open orders: %w", err)
+	}
+
+	bracketErr := NewBracketOrderError(bracket.ID, bracket.Symbol)
+	for _, order := range openOrders {
+		if !match(order.ClientOrderID) {
+			continue
+		}
+
+		leg := bracketLeg(order.ClientOrderID)
+		err := client.CancelOrder(ctx, bracket.Symbol, order.OrderID)
+		m.audit(ctx, AuditEntry{
+			Operation:      AuditOpCancelOrder,
+			Venue:          venueName(bracket.Type == OrderTypeFutures),
+			Symbol:         bracket.Symbol,
+			Side:           order.Side,
+			Quantity:       auditDecimal(order.OrigQty),
+			Price:          auditDecimal(order.Price),
+			BracketOrderID: bracket.ID,
+			OrderIDs:       []string{formatOrderID(order.OrderID), order.ClientOrderID},
+		}, err)
+		if err != nil {
+			bracketErr.Add(leg, err)
+			continue
+		}
+
+		m.logger.Info().
+			Str("bracket_id", bracket.ID).
+			Str("leg", leg).
+			Str("client_order_id", order.ClientOrderID).
+			Int64("order_id", order.OrderID).
+			Msg("Bracket leg canceled")
+
+		if m.eventEmitter != nil {
+			update := &OrderUpdate{
+				EventType:     "order_update.v1",
+				Symbol:        bracket.Symbol,
+				OrderID:       order.OrderID,
+				ClientOrderID: order.ClientOrderID,
+				Status:        "CANCELED",
+				Side:          order.Side,
+				OrderType:     order.Type,
+				Price:         order.Price,
+				Quantity:      order.OrigQty,
+				ExecutedQty:   order.ExecutedQty,
+				UpdateTime:    time.Now(),
+				Reason:        reason,
+			}
+			_ = m.eventEmitter.EmitOrderUpdate(ctx, update)
+		}
+	}
+
+	if bracketErr.HasErrors() {
+		return bracketErr
+	}
+	return nil
+}
+
 // validateBracketRequest validates bracket order request
 func (m *Manager) validateBracketRequest(req *PlaceBracketRequest) error {
 	if req.Symbol == "" {
@@ -288,9 +651,23 @@ func (m *Manager) validateBracketRequest(req *PlaceBracketRequest) error {
 	if len(req.TakeProfitPrices) == 0 {
 		return fmt.Errorf("at least one take profit price is required")
 	}
+	if m.maxTakeProfitLegs > 0 && len(req.TakeProfitPrices) > m.maxTakeProfitLegs {
+		return fmt.Errorf("at most %d take profits are allowed, got %d", m.maxTakeProfitLegs, len(req.TakeProfitPrices))
+	}
+	if err := validateTakeProfitRatios(req); err != nil {
+		return err
+	}
 	if req.StopLossPrice.LessThanOrEqual(decimal.Zero) {
 		return fmt.Errorf("stop loss price must be positive")
 	}
+	if req.WorkingType != "" {
+		if !req.IsFutures {
+			return fmt.Errorf("working type is only supported for futures")
+		}
+		if err := binance.ValidateWorkingType(req.WorkingType); err != nil {
+			return err
+		}
+	}
 
 	// Validate price relationships
 	if req.Side == "BUY" {
@@ -318,7 +695,56 @@ func (m *Manager) validateBracketRequest(req *PlaceBracketRequest) error {
 	return nil
 }
 
-// generateClientOrderID generates a unique client order ID
+// breakevenPrice offsets entry by the fees of both the entry and the exit, so
+// a stop filled there loses nothing. The offset is against the position: up
+// for a long entered on BUY, down for a short.
+func (m *Manager) breakevenPrice(side string, entry decimal.Decimal) decimal.Decimal {
+	offset := entry.Mul(m.breakevenFeeRate).Mul(decimal.NewFromInt(2))
+	if side == "BUY" {
+		return entry.Add(offset)
+	}
+	return entry.Sub(offset)
+}
+
+// validateTakeProfitRatios checks that explicit ratios pair up with the take
+// profit prices, are positive and sum to exactly 1
+func validateTakeProfitRatios(req *PlaceBracketRequest) error {
+	if len(req.TakeProfitRatios) == 0 {
+		return nil
+	}
+	if len(req.TakeProfitRatios) != len(req.TakeProfitPrices) {
+		return fmt.Errorf("got %d take profit ratios for %d take profit prices", len(req.TakeProfitRatios), len(req.TakeProfitPrices))
+	}
+
+	sum := decimal.Zero
+	for i, ratio := range req.TakeProfitRatios {
+		if !ratio.IsPositive() {
+			return fmt.Errorf("take profit ratio %d must be positive", i+1)
+		}
+		sum = sum.Add(ratio)
+	}
+	if !sum.Equal(decimal.NewFromInt(1)) {
+		return fmt.Errorf("take profit ratios must sum to 1, got %s", sum)
+	}
+	return nil
+}
+
+// generateClientOrderID generates a unique client order ID of the form
+// <bracket prefix><leg>_<nanos>
 func (m *Manager) generateClientOrderID(bracketID, orderType string) string {
-	return fmt.Sprintf("%s_%s_%d", bracketID[:8], orderType, time.Now().UnixNano())
+	return fmt.Sprintf("%s%s_%d", bracketPrefix(bracketID), orderType, time.Now().UnixNano())
+}
+
+// bracketPrefix returns the client order ID prefix shared by a bracket's legs
+func bracketPrefix(bracketID string) string {
+	return bracketID[:8] + "_"
+}
+
+// bracketLeg extracts the leg name (MAIN, TP1, SL, ...) from a client order ID
+func bracketLeg(clientOrderID string) string {
+	parts := strings.Split(clientOrderID, "_")
+	if len(parts) != 3 {
+		return clientOrderID
+	}
+	return parts[1]
 }
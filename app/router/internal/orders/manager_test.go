@@ -1,6 +1,7 @@
 package orders
 
 import (
+	"context"
 	"testing"
 	"time"
 
@@ -137,6 +138,86 @@ func TestManager_validateBracketRequest(t *testing.T) {
 			},
 			wantErr: "take profit 1 must be below entry for sell orders",
 		},
+		{
+			name: "futures mark price stop",
+			req: &PlaceBracketRequest{
+				Symbol:           "BTCUSDT",
+				Side:             "BUY",
+				Quantity:         decimal.RequireFromString("0.001"),
+				EntryPrice:       decimal.RequireFromString("50000"),
+				TakeProfitPrices: []decimal.Decimal{decimal.RequireFromString("51000")},
+				StopLossPrice:    decimal.RequireFromString("49000"),
+				IsFutures:        true,
+				WorkingType:      "MARK_PRICE",
+			},
+			wantErr: "",
+		},
+		{
+			name: "invalid working type",
+			req: &PlaceBracketRequest{
+				Symbol:           "BTCUSDT",
+				Side:             "BUY",
+				Quantity:         decimal.RequireFromString("0.001"),
+				EntryPrice:       decimal.RequireFromString("50000"),
+				TakeProfitPrices: []decimal.Decimal{decimal.RequireFromString("51000")},
+				StopLossPrice:    decimal.RequireFromString("49000"),
+				IsFutures:        true,
+				WorkingType:      "LAST_PRICE",
+			},
+			wantErr: "invalid working type: LAST_PRICE",
+		},
+		{
+			name: "working type on spot",
+			req: &PlaceBracketRequest{
+				Symbol:           "BTCUSDT",
+				Side:             "BUY",
+				Quantity:         decimal.RequireFromString("0.001"),
+				EntryPrice:       decimal.RequireFromString("50000"),
+				TakeProfitPrices: []decimal.Decimal{decimal.RequireFromString("51000")},
+				StopLossPrice:    decimal.RequireFromString("49000"),
+				WorkingType:      "MARK_PRICE",
+			},
+			wantErr: "working type is only supported for futures",
+		},
+		{
+			name: "take profit ratios not summing to one",
+			req: &PlaceBracketRequest{
+				Symbol:           "BTCUSDT",
+				Side:             "BUY",
+				Quantity:         decimal.RequireFromString("0.001"),
+				EntryPrice:       decimal.RequireFromString("50000"),
+				TakeProfitPrices: []decimal.Decimal{decimal.RequireFromString("51000"), decimal.RequireFromString("52000")},
+				TakeProfitRatios: []decimal.Decimal{decimal.RequireFromString("0.5"), decimal.RequireFromString("0.4")},
+				StopLossPrice:    decimal.RequireFromString("49000"),
+			},
+			wantErr: "take profit ratios must sum to 1, got 0.9",
+		},
+		{
+			name: "take profit ratio count mismatch",
+			req: &PlaceBracketRequest{
+				Symbol:           "BTCUSDT",
+				Side:             "BUY",
+				Quantity:         decimal.RequireFromString("0.001"),
+				EntryPrice:       decimal.RequireFromString("50000"),
+				TakeProfitPrices: []decimal.Decimal{decimal.RequireFromString("51000"), decimal.RequireFromString("52000")},
+				TakeProfitRatios: []decimal.Decimal{decimal.NewFromInt(1)},
+				StopLossPrice:    decimal.RequireFromString("49000"),
+			},
+			wantErr: "got 1 take profit ratios for 2 take profit prices",
+		},
+		{
+			name: "non-positive take profit ratio",
+			req: &PlaceBracketRequest{
+				Symbol:           "BTCUSDT",
+				Side:             "BUY",
+				Quantity:         decimal.RequireFromString("0.001"),
+				EntryPrice:       decimal.RequireFromString("50000"),
+				TakeProfitPrices: []decimal.Decimal{decimal.RequireFromString("51000"), decimal.RequireFromString("52000")},
+				TakeProfitRatios: []decimal.Decimal{decimal.RequireFromString("1.2"), decimal.RequireFromString("-0.2")},
+				StopLossPrice:    decimal.RequireFromString("49000"),
+			},
+			wantErr: "take profit ratio 2 must be positive",
+		},
 	}
 
 	for _, tt := range tests {
@@ -151,6 +232,26 @@ func TestManager_validateBracketRequest(t *testing.T) {
 	}
 }
 
+func TestManager_MaxTakeProfitLegs(t *testing.T) {
+	manager := NewManager(nil, nil, nil, zerolog.Nop(), WithMaxTakeProfitLegs(2))
+
+	req := &PlaceBracketRequest{
+		Symbol:     "BTCUSDT",
+		Side:       "BUY",
+		Quantity:   decimal.RequireFromString("0.001"),
+		EntryPrice: decimal.RequireFromString("50000"),
+		TakeProfitPrices: []decimal.Decimal{
+			decimal.RequireFromString("51000"),
+			decimal.RequireFromString("52000"),
+		},
+		StopLossPrice: decimal.RequireFromString("49000"),
+	}
+	assert.NoError(t, manager.validateBracketRequest(req))
+
+	req.TakeProfitPrices = append(req.TakeProfitPrices, decimal.RequireFromString("53000"))
+	assert.EqualError(t, manager.validateBracketRequest(req), "at most 2 take profits are allowed, got 3")
+}
+
 func TestManager_generateClientOrderID(t *testing.T) {
 	logger := zerolog.Nop()
 	manager := NewManager(nil, nil, nil, logger)
@@ -237,3 +338,31 @@ func TestGetOppositeSide(t *testing.T) {
 		})
 	}
 }
+
+func TestManager_DisabledVenue(t *testing.T) {
+	manager := NewManager(nil, nil, nil, zerolog.Nop())
+	ctx := context.Background()
+
+	t.Run("bracket on disabled venue returns error", func(t *testing.T) {
+		_, err := manager.PlaceBracketOrder(ctx, &PlaceBracketRequest{
+			Symbol:           "BTCUSDT",
+			Side:             "BUY",
+			Quantity:         decimal.NewFromFloat(0.01),
+			EntryPrice:       decimal.NewFromInt(50000),
+			TakeProfitPrices: []decimal.Decimal{decimal.NewFromInt(51000)},
+			StopLossPrice:    decimal.NewFromInt(49000),
+			IsFutures:        true,
+		})
+		assert.EqualError(t, err, "futures trading is not enabled")
+	})
+
+	t.Run("close all on disabled venue returns error", func(t *testing.T) {
+		err := manager.CloseAllPositions(ctx, &CloseAllRequest{Symbol: "BTCUSDT"})
+		assert.EqualError(t, err, "spot trading is not enabled")
+	})
+
+	t.Run("cancel with no venues returns error", func(t *testing.T) {
+		err := manager.CancelOrder(ctx, &CancelRequest{Symbol: "BTCUSDT", OrderID: 1})
+		assert.EqualError(t, err, "no trading venue enabled")
+	})
+}
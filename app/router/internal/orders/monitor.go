@@ -0,0 +1,355 @@
+package orders
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/rs/zerolog"
+	"github.com/shopspring/decimal"
+	"router/internal/websocket"
+)
+
+// defaultMonitorCancelTimeout bounds the cancels issued for a single fill
+const defaultMonitorCancelTimeout = 10 * time.Second
+
+// BracketMonitor drives brackets from user-stream order updates. When the
+// entry fills the bracket opens; when the stop loss or the last take profit
+// fills, the opposing exit legs are cancelled (one-cancels-other).
+type BracketMonitor struct {
+	manager       *Manager
+	logger        zerolog.Logger
+	cancelTimeout time.Duration
+}
+
+// NewBracketMonitor creates a monitor for brackets placed through manager
+func NewBracketMonitor(manager *Manager, logger zerolog.Logger) *BracketMonitor {
+	return &BracketMonitor{
+		manager:       manager,
+		logger:        logger,
+		cancelTimeout: defaultMonitorCancelTimeout,
+	}
+}
+
+// HandleAccountUpdate processes an outboundAccountPosition event. Balances
+// have changed, so the spot account snapshot used for balance checks is
+// dropped. It can be used directly as websocket.UserDataHandler.OnAccountUpdate.
+func (bm *BracketMonitor) HandleAccountUpdate(event *websocket.AccountUpdateEvent) error {
+	if client := bm.manager.spotClient; client != nil {
+		client.InvalidateAccountCache()
+	}
+	return nil
+}
+
+// HandleFuturesAccountUpdate processes an ACCOUNT_UPDATE from the futures user
+// stream. Balances or positions have changed, so the futures account snapshot
+// used for margin and position checks is dropped. It can be used directly as
+// websocket.UserDataHandler.OnFuturesAccountUpdate.
+func (bm *BracketMonitor) HandleFuturesAccountUpdate(event *websocket.FuturesAccountUpdateEvent) error {
+	if client := bm.manager.futuresClient; client != nil {
+		client.InvalidateAccountCache()
+	}
+
+	for _, position := range event.Positions {
+		bm.logger.Debug().
+			Str("symbol", position.Symbol).
+			Str("position_side", position.PositionSide).
+			Str("amount", position.PositionAmount.String()).
+			Str("reason", event.Reason).
+			Msg("Futures position updated")
+	}
+	return nil
+}
+
+// HandleOrderUpdate processes an executionReport. It can be used directly as
+// websocket.UserDataHandler.OnOrderUpdate; updates for orders outside any
+// tracked bracket are ignored.
+func (bm *BracketMonitor) HandleOrderUpdate(event *websocket.OrderUpdateEvent) error {
+	if event.OrderStatus != "FILLED" {
+		return nil
+	}
+
+	m := bm.manager
+	m.mu.Lock()
+	bracketID, exists := m.ordersByClient[event.ClientOrderID]
+	if !exists {
+		m.mu.Unlock()
+		return nil
+	}
+	bracket := m.orders[bracketID]
+	leg := bracketLeg(event.ClientOrderID)
+	firstFill := !bracket.filledLegs[leg]
+	if leg == "MAIN" && firstFill {
+		bracket.entryFillPrice = averageFillPrice(event)
+	}
+	from := bracket.State
+	cancel := bracket.recordFill(leg)
+	to := bracket.State
+	if to == BracketStateClosed && from != BracketStateClosed {
+		m.releaseBracketLocked(bracket.Symbol)
+	}
+	moveStop := firstFill && leg == "TP1" && to != BracketStateClosed &&
+		bracket.MoveStopToBreakevenOnTP1 && bracket.ClientOrderIDs.StopLoss != ""
+	m.mu.Unlock()
+
+	if moveStop {
+		return bm.moveStopToBreakeven(bracket)
+	}
+
+	if from == to && len(cancel) == 0 {
+		return nil
+	}
+
+	bm.logger.Info().
+		Str("bracket_id", bracketID).
+		Str("leg", leg).
+		Str("from", string(from)).
+		Str("to", string(to)).
+		Int("cancel_count", len(cancel)).
+		Msg("Bracket transition")
+
+	ctx, cancelCtx := context.WithTimeout(context.Background(), bm.cancelTimeout)
+	defer cancelCtx()
+
+	if m.eventEmitter != nil {
+		update := &OrderUpdate{
+			EventType:     "bracket_update.v1",
+			Symbol:        bracket.Symbol,
+			OrderID:       event.OrderID,
+			ClientOrderID: event.ClientOrderID,
+			Status:        string(to),
+			Side:          event.Side,
+			OrderType:     event.OrderType,
+			Price:         event.LastExecutedPrice,
+			Quantity:      event.Quantity,
+			ExecutedQty:   event.CumulativeFilledQty,
+			UpdateTime:    time.Now(),
+			Reason:        fmt.Sprintf("%s filled", leg),
+		}
+		_ = m.eventEmitter.EmitOrderUpdate(ctx, update)
+	}
+
+	if len(cancel) == 0 {
+		return nil
+	}
+
+	client, err := m.bracketClient(bracket)
+	if err != nil {
+		return err
+	}
+
+	err = m.cancelLegs(ctx, client, bracket, cancel, fmt.Sprintf("OCO: %s filled", leg))
+	if err != nil {
+		bm.logger.Error().
+			Err(err).
+			Str("bracket_id", bracketID).
+			Str("leg", leg).
+			Msg("Failed to cancel opposing bracket legs")
+		return fmt.Errorf("failed to cancel opposing legs of bracket %s: %w", bracketID, err)
+	}
+	return nil
+}
+
+// moveStopToBreakeven replaces bracket's stop loss with one at the
+// fee-adjusted entry price, sized to what is left of the position
+func (bm *BracketMonitor) moveStopToBreakeven(bracket *BracketOrder) error {
+	m := bm.manager
+	ctx, cancel := context.WithTimeout(context.Background(), bm.cancelTimeout)
+	defer cancel()
+
+	client, err := m.bracketClient(bracket)
+	if err != nil {
+		return err
+	}
+
+	m.mu.RLock()
+	oldStop := bracket.ClientOrderIDs.StopLoss
+	oldPrice := bracket.StopLossPrice
+	// The fill price is what the position actually cost; a limit entry can
+	// fill better than requested
+	entry := bracket.entryFillPrice
+	if entry.IsZero() {
+		entry = bracket.EntryPrice
+	}
+	remaining := bracket.remainingQuantity()
+	m.mu.RUnlock()
+
+	if entry.IsZero() {
+		return fmt.Errorf("cannot move stop of bracket %s to breakeven: entry price unknown", bracket.ID)
+	}
+	stopPrice, err := client.RoundPrice(ctx, bracket.Symbol, m.breakevenPrice(bracket.Side, entry))
+	if err != nil {
+		return fmt.Errorf("failed to round breakeven stop of bracket %s: %w", bracket.ID, err)
+	}
+
+	err = m.cancelOpenLegs(ctx, client, bracket, func(clientOrderID string) bool {
+		return clientOrderID == oldStop
+	}, "Stop moved to breakeven")
+	if err != nil {
+		return fmt.Errorf("failed to cancel stop loss of bracket %s: %w", bracket.ID, err)
+	}
+
+	// The old stop may have filled while it was being replaced
+	m.mu.RLock()
+	closed := bracket.State == BracketStateClosed
+	m.mu.RUnlock()
+	if closed {
+		return nil
+	}
+
+	newStop := m.generateClientOrderID(bracket.ID, "SL")
+	if err := m.placeStopLoss(ctx, client, bracket, remaining, stopPrice, newStop); err != nil {
+		placeErr := fmt.Errorf("failed to place breakeven stop loss of bracket %s: %w", bracket.ID, err)
+
+		// Put the original stop back rather than leave the position unprotected
+		restored := m.generateClientOrderID(bracket.ID, "SL")
+		if restoreErr := m.placeStopLoss(ctx, client, bracket, remaining, oldPrice, restored); restoreErr != nil {
+			bm.logger.Error().
+				Err(err).
+				AnErr("restore_error", restoreErr).
+				Str("bracket_id", bracket.ID).
+				Str("stop_price", stopPrice.String()).
+				Msg("Failed to place breakeven stop loss or restore the original, position is unprotected")
+			return fmt.Errorf("%w (restoring the original stop also failed: %v)", placeErr, restoreErr)
+		}
+
+		m.mu.Lock()
+		m.replaceStopLossLocked(bracket, oldStop, restored)
+		m.mu.Unlock()
+
+		bm.logger.Warn().
+			Err(err).
+			Str("bracket_id", bracket.ID).
+			Str("stop_price", oldPrice.String()).
+			Msg("Failed to place breakeven stop loss, original stop restored")
+		return placeErr
+	}
+
+	m.mu.Lock()
+	m.replaceStopLossLocked(bracket, oldStop, newStop)
+	bracket.StopLossPrice = stopPrice
+	bracket.StopMovedToBreakeven = true
+	m.mu.Unlock()
+
+	bm.logger.Info().
+		Str("bracket_id", bracket.ID).
+		Str("stop_price", stopPrice.String()).
+		Str("quantity", remaining.String()).
+		Msg("Stop loss moved to breakeven")
+
+	if m.eventEmitter != nil {
+		_ = m.eventEmitter.EmitOrderUpdate(ctx, &OrderUpdate{
+			EventType:     "order_update.v1",
+			Symbol:        bracket.Symbol,
+			ClientOrderID: newStop,
+			Status:        "NEW",
+			Side:          getOppositeSide(bracket.Side),
+			Price:         stopPrice,
+			Quantity:      remaining,
+			ExecutedQty:   decimal.Zero,
+			UpdateTime:    time.Now(),
+			Reason:        "Stop moved to breakeven",
+		})
+	}
+	return nil
+}
+
+// replaceStopLossLocked tracks newStop as bracket's stop loss in place of
+// oldStop. Callers must hold Manager.mu.
+func (m *Manager) replaceStopLossLocked(bracket *BracketOrder, oldStop, newStop string) {
+	delete(m.ordersByClient, oldStop)
+	m.ordersByClient[newStop] = bracket.ID
+	bracket.ClientOrderIDs.StopLoss = newStop
+	bracket.UpdatedAt = time.Now()
+}
+
+// HandleFuturesOrderUpdate processes an ORDER_TRADE_UPDATE from the futures
+// user stream the same way HandleOrderUpdate processes a spot executionReport.
+// It can be used directly as websocket.UserDataHandler.OnFuturesOrderUpdate.
+func (bm *BracketMonitor) HandleFuturesOrderUpdate(event *websocket.FuturesOrderUpdateEvent) error {
+	return bm.HandleOrderUpdate(&websocket.OrderUpdateEvent{
+		EventType:            event.EventType,
+		EventTime:            event.EventTime,
+		Symbol:               event.Symbol,
+		ClientOrderID:        event.ClientOrderID,
+		Side:                 event.Side,
+		OrderType:            event.OrderType,
+		TimeInForce:          event.TimeInForce,
+		Quantity:             event.Quantity,
+		Price:                event.Price,
+		StopPrice:            event.StopPrice,
+		ExecutionType:        event.ExecutionType,
+		OrderStatus:          event.OrderStatus,
+		OrderID:              event.OrderID,
+		LastExecutedQuantity: event.LastExecutedQuantity,
+		CumulativeFilledQty:  event.CumulativeFilledQty,
+		LastExecutedPrice:    event.LastExecutedPrice,
+		CommissionAmount:     event.CommissionAmount,
+		CommissionAsset:      event.CommissionAsset,
+		TransactionTime:      event.TransactionTime,
+		TradeID:              event.TradeID,
+		IsMaker:              event.IsMaker,
+		CumulativeQuoteQty:   event.AveragePrice.Mul(event.CumulativeFilledQty),
+	})
+}
+
+// averageFillPrice returns what an order paid per unit across all its fills.
+// The last fill's price stands in when the update carries no totals.
+func averageFillPrice(event *websocket.OrderUpdateEvent) decimal.Decimal {
+	if event.CumulativeFilledQty.IsPositive() && event.CumulativeQuoteQty.IsPositive() {
+		return event.CumulativeQuoteQty.Div(event.CumulativeFilledQty)
+	}
+	return event.LastExecutedPrice
+}
+
+// remainingQuantity returns the position left once the filled take profits
+// are subtracted. Callers must hold Manager.mu.
+func (b *BracketOrder) remainingQuantity() decimal.Decimal {
+	remaining := b.Quantity
+	for i, id := range b.ClientOrderIDs.TakeProfits {
+		if id != "" && b.filledLegs[bracketLeg(id)] && i < len(b.TakeProfitQuantities) {
+			remaining = remaining.Sub(b.TakeProfitQuantities[i])
+		}
+	}
+	return remaining
+}
+
+// recordFill marks leg filled, advances the bracket state and returns the
+// client order IDs of exit legs that must now be cancelled. Callers must hold
+// Manager.mu.
+func (b *BracketOrder) recordFill(leg string) []string {
+	if b.State == BracketStateClosed || b.filledLegs[leg] {
+		return nil
+	}
+	b.filledLegs[leg] = true
+	b.UpdatedAt = time.Now()
+
+	switch {
+	case leg == "MAIN":
+		b.State = BracketStateOpen
+		return nil
+	case leg == "SL":
+		b.State = BracketStateClosed
+		var open []string
+		for _, id := range b.ClientOrderIDs.TakeProfits {
+			if id != "" && !b.filledLegs[bracketLeg(id)] {
+				open = append(open, id)
+			}
+		}
+		return open
+	case strings.HasPrefix(leg, "TP"):
+		// Earlier take profits leave the stop loss guarding the remainder
+		for _, id := range b.ClientOrderIDs.TakeProfits {
+			if id != "" && !b.filledLegs[bracketLeg(id)] {
+				return nil
+			}
+		}
+		b.State = BracketStateClosed
+		if b.ClientOrderIDs.StopLoss == "" {
+			return nil
+		}
+		return []string{b.ClientOrderIDs.StopLoss}
+	}
+	return nil
+}
@@ -0,0 +1,323 @@
+package orders
+
+import (
+	"context"
+	"net/url"
+	"testing"
+
+	"github.com/rs/zerolog"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"router/internal/testutil"
+	"router/internal/websocket"
+)
+
+func filledEvent(clientOrderID string) *websocket.OrderUpdateEvent {
+	return &websocket.OrderUpdateEvent{
+		EventType:     "executionReport",
+		Symbol:        "BTCUSDT",
+		ClientOrderID: clientOrderID,
+		ExecutionType: "TRADE",
+		OrderStatus:   "FILLED",
+	}
+}
+
+func orderStatuses(fake *testutil.FakeBinance) map[string]string {
+	statuses := make(map[string]string)
+	for _, order := range fake.Orders() {
+		statuses[order.ClientOrderID()] = order.Status
+	}
+	return statuses
+}
+
+func bracketState(m *Manager, bracketID string) BracketState {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return m.orders[bracketID].State
+}
+
+func TestBracketMonitor(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("take profit fill cancels stop loss", func(t *testing.T) {
+		manager, fake, emitter := newHarnessManager(t)
+		monitor := NewBracketMonitor(manager, zerolog.Nop())
+
+		resp, err := manager.PlaceBracketOrder(ctx, harnessBracketRequest())
+		require.NoError(t, err)
+		ids := resp.ClientOrderIDs
+		assert.Equal(t, BracketStatePending, bracketState(manager, resp.BracketOrderID))
+
+		require.NoError(t, monitor.HandleOrderUpdate(filledEvent(ids.Main)))
+		assert.Equal(t, BracketStateOpen, bracketState(manager, resp.BracketOrderID))
+		assert.Equal(t, "NEW", orderStatuses(fake)[ids.StopLoss])
+
+		require.NoError(t, monitor.HandleOrderUpdate(filledEvent(ids.TakeProfits[0])))
+		assert.Equal(t, BracketStateClosed, bracketState(manager, resp.BracketOrderID))
+
+		statuses := orderStatuses(fake)
+		assert.Equal(t, "CANCELED", statuses[ids.StopLoss])
+		assert.Equal(t, "NEW", statuses[ids.TakeProfits[0]])
+
+		var transitions []string
+		for _, update := range emitter.Updates() {
+			if update.EventType == "bracket_update.v1" {
+				transitions = append(transitions, update.Status)
+			}
+		}
+		assert.Equal(t, []string{"OPEN", "CLOSED"}, transitions)
+	})
+
+	t.Run("stop loss fill cancels every take profit", func(t *testing.T) {
+		manager, fake, _ := newHarnessManager(t)
+		monitor := NewBracketMonitor(manager, zerolog.Nop())
+
+		req := harnessBracketRequest()
+		req.Quantity = decimal.RequireFromString("0.002")
+		req.TakeProfitPrices = []decimal.Decimal{decimal.NewFromInt(51000), decimal.NewFromInt(52000)}
+		resp, err := manager.PlaceBracketOrder(ctx, req)
+		require.NoError(t, err)
+		ids := resp.ClientOrderIDs
+
+		require.NoError(t, monitor.HandleOrderUpdate(filledEvent(ids.Main)))
+		require.NoError(t, monitor.HandleOrderUpdate(filledEvent(ids.StopLoss)))
+
+		statuses := orderStatuses(fake)
+		assert.Equal(t, "CANCELED", statuses[ids.TakeProfits[0]])
+		assert.Equal(t, "CANCELED", statuses[ids.TakeProfits[1]])
+		assert.Equal(t, BracketStateClosed, bracketState(manager, resp.BracketOrderID))
+	})
+
+	t.Run("stop loss survives until the last take profit fills", func(t *testing.T) {
+		manager, fake, _ := newHarnessManager(t)
+		monitor := NewBracketMonitor(manager, zerolog.Nop())
+
+		req := harnessBracketRequest()
+		req.Quantity = decimal.RequireFromString("0.002")
+		req.TakeProfitPrices = []decimal.Decimal{decimal.NewFromInt(51000), decimal.NewFromInt(52000)}
+		resp, err := manager.PlaceBracketOrder(ctx, req)
+		require.NoError(t, err)
+		ids := resp.ClientOrderIDs
+
+		require.NoError(t, monitor.HandleOrderUpdate(filledEvent(ids.TakeProfits[0])))
+		assert.Equal(t, "NEW", orderStatuses(fake)[ids.StopLoss])
+
+		require.NoError(t, monitor.HandleOrderUpdate(filledEvent(ids.TakeProfits[1])))
+		assert.Equal(t, "CANCELED", orderStatuses(fake)[ids.StopLoss])
+	})
+
+	t.Run("first take profit moves the stop to breakeven", func(t *testing.T) {
+		manager, fake, emitter := newHarnessManager(t)
+		monitor := NewBracketMonitor(manager, zerolog.Nop())
+
+		req := harnessBracketRequest()
+		req.Quantity = decimal.RequireFromString("0.002")
+		req.EntryPrice = decimal.NewFromInt(50000)
+		req.TakeProfitPrices = []decimal.Decimal{decimal.NewFromInt(51000), decimal.NewFromInt(52000)}
+		req.MoveStopToBreakevenOnTP1 = true
+		resp, err := manager.PlaceBracketOrder(ctx, req)
+		require.NoError(t, err)
+		ids := resp.ClientOrderIDs
+
+		require.NoError(t, monitor.HandleOrderUpdate(filledEvent(ids.Main)))
+		require.NoError(t, monitor.HandleOrderUpdate(filledEvent(ids.TakeProfits[0])))
+		assert.Equal(t, BracketStateOpen, bracketState(manager, resp.BracketOrderID))
+		assert.Equal(t, "CANCELED", orderStatuses(fake)[ids.StopLoss])
+
+		placed := fake.Orders()
+		replacement := placed[len(placed)-1]
+		assert.Equal(t, "STOP_LOSS_LIMIT", replacement.Type())
+		assert.Equal(t, "SELL", replacement.Params.Get("side"))
+		// 50000 plus 0.1% fees on both entry and exit
+		assert.Equal(t, "50100", replacement.Params.Get("stopPrice"))
+		assert.Equal(t, "0.001", replacement.Params.Get("quantity"))
+		assert.NotEqual(t, ids.StopLoss, replacement.ClientOrderID())
+
+		manager.mu.RLock()
+		bracket := manager.orders[resp.BracketOrderID]
+		assert.Equal(t, replacement.ClientOrderID(), bracket.ClientOrderIDs.StopLoss)
+		assert.Equal(t, "50100", bracket.StopLossPrice.String())
+		assert.True(t, bracket.StopMovedToBreakeven)
+		manager.mu.RUnlock()
+
+		var reasons []string
+		for _, update := range emitter.Updates() {
+			if update.Reason == "Stop moved to breakeven" {
+				reasons = append(reasons, update.Status)
+			}
+		}
+		assert.Equal(t, []string{"CANCELED", "NEW"}, reasons)
+
+		// The replacement is tracked like the original stop
+		require.NoError(t, monitor.HandleOrderUpdate(filledEvent(replacement.ClientOrderID())))
+		assert.Equal(t, BracketStateClosed, bracketState(manager, resp.BracketOrderID))
+		assert.Equal(t, "CANCELED", orderStatuses(fake)[ids.TakeProfits[1]])
+	})
+
+	t.Run("breakeven for a short uses the entry fill price", func(t *testing.T) {
+		manager, fake, _ := newHarnessManager(t)
+		monitor := NewBracketMonitor(manager, zerolog.Nop())
+		manager.breakevenFeeRate = decimal.RequireFromString("0.0005")
+
+		req := harnessBracketRequest()
+		req.Side = "SELL"
+		req.Quantity = decimal.RequireFromString("0.002")
+		req.EntryPrice = decimal.NewFromInt(50000)
+		req.TakeProfitPrices = []decimal.Decimal{decimal.NewFromInt(49000), decimal.NewFromInt(48000)}
+		req.StopLossPrice = decimal.NewFromInt(51000)
+		req.MoveStopToBreakevenOnTP1 = true
+		resp, err := manager.PlaceBracketOrder(ctx, req)
+		require.NoError(t, err)
+		ids := resp.ClientOrderIDs
+
+		// The entry filled in two trades averaging 50010; the last trade's
+		// price is not what the position cost
+		entryFill := filledEvent(ids.Main)
+		entryFill.LastExecutedPrice = decimal.NewFromInt(50020)
+		entryFill.CumulativeFilledQty = decimal.RequireFromString("0.002")
+		entryFill.CumulativeQuoteQty = decimal.RequireFromString("100.02")
+		require.NoError(t, monitor.HandleOrderUpdate(entryFill))
+		require.NoError(t, monitor.HandleOrderUpdate(filledEvent(ids.TakeProfits[0])))
+
+		placed := fake.Orders()
+		replacement := placed[len(placed)-1]
+		assert.Equal(t, "BUY", replacement.Params.Get("side"))
+		// 50010 less 0.05% fees on both entry and exit
+		assert.Equal(t, "49959.99", replacement.Params.Get("stopPrice"))
+		assert.Equal(t, "CANCELED", orderStatuses(fake)[ids.StopLoss])
+	})
+
+	t.Run("rejected breakeven stop restores the original", func(t *testing.T) {
+		manager, fake, _ := newHarnessManager(t)
+		monitor := NewBracketMonitor(manager, zerolog.Nop())
+
+		req := harnessBracketRequest()
+		req.Quantity = decimal.RequireFromString("0.002")
+		req.EntryPrice = decimal.NewFromInt(50000)
+		req.TakeProfitPrices = []decimal.Decimal{decimal.NewFromInt(51000), decimal.NewFromInt(52000)}
+		req.MoveStopToBreakevenOnTP1 = true
+		resp, err := manager.PlaceBracketOrder(ctx, req)
+		require.NoError(t, err)
+		ids := resp.ClientOrderIDs
+
+		require.NoError(t, monitor.HandleOrderUpdate(filledEvent(ids.Main)))
+		fake.InjectErrorFor(func(params url.Values) bool {
+			return params.Get("stopPrice") == "50100"
+		}, -2010, "Stop price would trigger immediately.")
+		require.Error(t, monitor.HandleOrderUpdate(filledEvent(ids.TakeProfits[0])))
+		assert.Equal(t, "CANCELED", orderStatuses(fake)[ids.StopLoss])
+
+		placed := fake.Orders()
+		restored := placed[len(placed)-1]
+		assert.Equal(t, "STOP_LOSS_LIMIT", restored.Type())
+		assert.Equal(t, "NEW", restored.Status)
+		assert.Equal(t, req.StopLossPrice.String(), restored.Params.Get("stopPrice"))
+		assert.Equal(t, "0.001", restored.Params.Get("quantity"))
+
+		manager.mu.RLock()
+		bracket := manager.orders[resp.BracketOrderID]
+		assert.Equal(t, restored.ClientOrderID(), bracket.ClientOrderIDs.StopLoss)
+		assert.False(t, bracket.StopMovedToBreakeven)
+		manager.mu.RUnlock()
+
+		// The restored stop is tracked like the original
+		require.NoError(t, monitor.HandleOrderUpdate(filledEvent(restored.ClientOrderID())))
+		assert.Equal(t, BracketStateClosed, bracketState(manager, resp.BracketOrderID))
+	})
+
+	t.Run("ignores unrelated and non-fill updates", func(t *testing.T) {
+		manager, fake, emitter := newHarnessManager(t)
+		monitor := NewBracketMonitor(manager, zerolog.Nop())
+
+		resp, err := manager.PlaceBracketOrder(ctx, harnessBracketRequest())
+		require.NoError(t, err)
+
+		partial := filledEvent(resp.ClientOrderIDs.TakeProfits[0])
+		partial.OrderStatus = "PARTIALLY_FILLED"
+		require.NoError(t, monitor.HandleOrderUpdate(partial))
+		require.NoError(t, monitor.HandleOrderUpdate(filledEvent("manual-order-1")))
+
+		assert.Equal(t, "NEW", orderStatuses(fake)[resp.ClientOrderIDs.StopLoss])
+		assert.Len(t, emitter.Updates(), 1) // placement only
+	})
+
+	t.Run("duplicate fills are idempotent", func(t *testing.T) {
+		manager, fake, _ := newHarnessManager(t)
+		monitor := NewBracketMonitor(manager, zerolog.Nop())
+
+		resp, err := manager.PlaceBracketOrder(ctx, harnessBracketRequest())
+		require.NoError(t, err)
+		tp := filledEvent(resp.ClientOrderIDs.TakeProfits[0])
+
+		require.NoError(t, monitor.HandleOrderUpdate(tp))
+		lookups := fake.RequestCount("/api/v3/openOrders")
+		require.NoError(t, monitor.HandleOrderUpdate(tp))
+		assert.Equal(t, lookups, fake.RequestCount("/api/v3/openOrders"))
+	})
+
+	t.Run("futures fills drive futures brackets", func(t *testing.T) {
+		manager, _ := newFuturesHarnessManager(t)
+		monitor := NewBracketMonitor(manager, zerolog.Nop())
+
+		req := harnessBracketRequest()
+		req.IsFutures = true
+		resp, err := manager.PlaceBracketOrder(ctx, req)
+		require.NoError(t, err)
+		ids := resp.ClientOrderIDs
+
+		futuresFill := func(clientOrderID string) *websocket.FuturesOrderUpdateEvent {
+			return &websocket.FuturesOrderUpdateEvent{
+				EventType:     "ORDER_TRADE_UPDATE",
+				Symbol:        "BTCUSDT",
+				ClientOrderID: clientOrderID,
+				ExecutionType: "TRADE",
+				OrderStatus:   "FILLED",
+			}
+		}
+
+		require.NoError(t, monitor.HandleFuturesOrderUpdate(futuresFill(ids.Main)))
+		assert.Equal(t, BracketStateOpen, bracketState(manager, resp.BracketOrderID))
+
+		require.NoError(t, monitor.HandleFuturesOrderUpdate(futuresFill(ids.StopLoss)))
+		assert.Equal(t, BracketStateClosed, bracketState(manager, resp.BracketOrderID))
+	})
+
+	t.Run("account updates refresh the balance snapshot", func(t *testing.T) {
+		manager, fake, _ := newHarnessManager(t)
+		monitor := NewBracketMonitor(manager, zerolog.Nop())
+
+		_, err := manager.spotClient.GetAccountInfo(ctx)
+		require.NoError(t, err)
+		_, err = manager.spotClient.GetAccountInfo(ctx)
+		require.NoError(t, err)
+		require.Equal(t, 1, fake.RequestCount("/api/v3/account"))
+
+		require.NoError(t, monitor.HandleAccountUpdate(&websocket.AccountUpdateEvent{EventType: "outboundAccountPosition"}))
+
+		_, err = manager.spotClient.GetAccountInfo(ctx)
+		require.NoError(t, err)
+		assert.Equal(t, 2, fake.RequestCount("/api/v3/account"))
+	})
+
+	t.Run("futures account updates refresh the position snapshot", func(t *testing.T) {
+		manager, fake := newFuturesHarnessManager(t)
+		monitor := NewBracketMonitor(manager, zerolog.Nop())
+
+		_, err := manager.futuresClient.GetFuturesAccountInfo(ctx)
+		require.NoError(t, err)
+		_, err = manager.futuresClient.GetFuturesAccountInfo(ctx)
+		require.NoError(t, err)
+		require.Equal(t, 1, fake.RequestCount("/fapi/v2/account"))
+
+		require.NoError(t, monitor.HandleFuturesAccountUpdate(&websocket.FuturesAccountUpdateEvent{
+			EventType: "ACCOUNT_UPDATE",
+			Reason:    "ORDER",
+			Positions: []websocket.FuturesPositionUpdate{{Symbol: "BTCUSDT", PositionSide: "BOTH"}},
+		}))
+
+		_, err = manager.futuresClient.GetFuturesAccountInfo(ctx)
+		require.NoError(t, err)
+		assert.Equal(t, 2, fake.RequestCount("/fapi/v2/account"))
+	})
+}
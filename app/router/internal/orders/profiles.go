@@ -0,0 +1,48 @@
+package orders
+
+import (
+	"errors"
+	"fmt"
+
+	"router/internal/binance"
+)
+
+// ErrUnknownProfile is returned when a request names an API key profile the
+// manager has no client for
+var ErrUnknownProfile = errors.New("unknown API key profile")
+
+// WithProfileClient registers client under name, so requests naming the
+// profile trade with its key instead of the venue's default key
+func WithProfileClient(name string, client *binance.Client) ManagerOption {
+	return func(m *Manager) {
+		if m.profiles == nil {
+			m.profiles = make(map[string]*binance.Client)
+		}
+		m.profiles[name] = client
+	}
+}
+
+// clientFor returns the client for a venue, using the named profile's key
+// when profile is set. A profile registered for the other venue is an error
+// rather than a silent fallback to the default key.
+func (m *Manager) clientFor(isFutures bool, profile string) (*binance.Client, error) {
+	if profile != "" {
+		client, ok := m.profiles[profile]
+		if !ok {
+			return nil, fmt.Errorf("%w: %q", ErrUnknownProfile, profile)
+		}
+		if client.IsFutures() != isFutures {
+			return nil, fmt.Errorf("API key profile %q is for %s, not %s", profile, venueName(client.IsFutures()), venueName(isFutures))
+		}
+		return client, nil
+	}
+
+	client := m.spotClient
+	if isFutures {
+		client = m.futuresClient
+	}
+	if client == nil {
+		return nil, fmt.Errorf("%s trading is not enabled", venueName(isFutures))
+	}
+	return client, nil
+}
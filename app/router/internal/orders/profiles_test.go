@@ -0,0 +1,86 @@
+package orders
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"router/internal/binance"
+	"router/internal/config"
+	"router/internal/testutil"
+)
+
+func TestManager_APIKeyProfiles(t *testing.T) {
+	ctx := context.Background()
+
+	newProfileManager := func(t *testing.T) (*Manager, *testutil.FakeBinance) {
+		t.Helper()
+		manager, fake, _ := newHarnessManager(t)
+
+		cfg := &config.BinanceConfig{Timeout: 5 * time.Second, ExchangeInfoCacheTTL: time.Minute}
+		for _, profile := range []config.APIKeyProfile{
+			{Name: "momentum", Venue: config.VenueSpot, APIKey: "momentum-key", SecretKey: "momentum-secret"},
+			{Name: "perps", Venue: config.VenueFutures, APIKey: "perps-key", SecretKey: "perps-secret"},
+		} {
+			client, err := binance.NewProfileClient(cfg, profile, fake.URL(), zerolog.Nop())
+			require.NoError(t, err)
+			WithProfileClient(profile.Name, client)(manager)
+		}
+		return manager, fake
+	}
+
+	t.Run("selects the named profile's key", func(t *testing.T) {
+		manager, fake := newProfileManager(t)
+
+		req := harnessBracketRequest()
+		req.Profile = "momentum"
+		resp, err := manager.PlaceBracketOrder(ctx, req)
+		require.NoError(t, err)
+
+		placed := fake.Orders()
+		require.Len(t, placed, 3)
+		for _, order := range placed {
+			assert.Equal(t, "momentum-key", order.Header.Get("X-MBX-APIKEY"))
+		}
+
+		assert.Equal(t, "momentum", manager.orders[resp.BracketOrderID].Profile)
+		require.NoError(t, manager.CancelBracket(ctx, resp.BracketOrderID))
+	})
+
+	t.Run("uses the default key without a profile", func(t *testing.T) {
+		manager, fake := newProfileManager(t)
+
+		_, err := manager.PlaceBracketOrder(ctx, harnessBracketRequest())
+		require.NoError(t, err)
+
+		placed := fake.Orders()
+		require.Len(t, placed, 3)
+		for _, order := range placed {
+			assert.Equal(t, "test-key", order.Header.Get("X-MBX-APIKEY"))
+		}
+	})
+
+	t.Run("rejects an unknown profile", func(t *testing.T) {
+		manager, fake := newProfileManager(t)
+
+		req := harnessBracketRequest()
+		req.Profile = "missing"
+		_, err := manager.PlaceBracketOrder(ctx, req)
+		assert.ErrorIs(t, err, ErrUnknownProfile)
+		assert.Empty(t, fake.Orders())
+	})
+
+	t.Run("rejects a profile for the other venue", func(t *testing.T) {
+		manager, fake := newProfileManager(t)
+
+		req := harnessBracketRequest()
+		req.Profile = "perps"
+		_, err := manager.PlaceBracketOrder(ctx, req)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), `API key profile "perps" is for futures, not spot`)
+		assert.Empty(t, fake.Orders())
+	})
+}
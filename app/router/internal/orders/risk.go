@@ -0,0 +1,273 @@
+package orders
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/shopspring/decimal"
+	"router/internal/binance"
+)
+
+// ErrKillSwitchEngaged is returned for placements made while the kill switch
+// is engaged
+var ErrKillSwitchEngaged = errors.New("kill switch engaged")
+
+// checkKillSwitch fails if the kill switch is engaged
+func (m *Manager) checkKillSwitch() error {
+	if state := m.killSwitch.Load(); state != nil && state.Engaged {
+		return fmt.Errorf("%w since %s: %s", ErrKillSwitchEngaged, state.EngagedAt.UTC().Format(time.RFC3339), state.Reason)
+	}
+	return nil
+}
+
+// EngageKillSwitch blocks all new placements until ReleaseKillSwitch. When
+// requested it then closes open orders on the chosen symbols across enabled
+// venues; the switch stays engaged even if closing fails.
+func (m *Manager) EngageKillSwitch(ctx context.Context, req *KillSwitchRequest) (KillSwitchState, error) {
+	if req.Reason == "" {
+		return KillSwitchState{}, fmt.Errorf("reason is required")
+	}
+
+	state := &KillSwitchState{Engaged: true, Reason: req.Reason, EngagedAt: time.Now()}
+	m.killSwitch.Store(state)
+
+	m.logger.Warn().
+		Str("reason", req.Reason).
+		Bool("close_positions", req.ClosePositions).
+		Msg("Kill switch engaged")
+
+	if !req.ClosePositions {
+		return *state, nil
+	}
+
+	symbols := req.Symbols
+	if len(symbols) == 0 {
+		symbols = m.killSwitchSymbols
+	}
+
+	var errs []error
+	for _, symbol := range symbols {
+		for _, isFutures := range []bool{false, true} {
+			if (isFutures && m.futuresClient == nil) || (!isFutures && m.spotClient == nil) {
+				continue
+			}
+			err := m.CloseAllPositions(ctx, &CloseAllRequest{Symbol: symbol, IsFutures: isFutures})
+			if err != nil {
+				errs = append(errs, fmt.Errorf("%s %s: %w", venueName(isFutures), symbol, err))
+			}
+		}
+	}
+	if len(errs) > 0 {
+		return *state, fmt.Errorf("failed to close positions: %w", errors.Join(errs...))
+	}
+	return *state, nil
+}
+
+// ReleaseKillSwitch re-enables placements
+func (m *Manager) ReleaseKillSwitch() {
+	if previous := m.killSwitch.Swap(&KillSwitchState{}); previous != nil && previous.Engaged {
+		m.logger.Warn().
+			Str("reason", previous.Reason).
+			Dur("engaged_for", time.Since(previous.EngagedAt)).
+			Msg("Kill switch released")
+	}
+}
+
+// KillSwitch returns the current kill switch state
+func (m *Manager) KillSwitch() KillSwitchState {
+	if state := m.killSwitch.Load(); state != nil {
+		return *state
+	}
+	return KillSwitchState{}
+}
+
+// notionalBudget caps traded notional per day. The day starts resetOffset
+// after UTC midnight. Callers must hold Manager.mu.
+type notionalBudget struct {
+	cap         decimal.Decimal
+	resetOffset time.Duration
+	used        decimal.Decimal
+	windowStart time.Time
+	now         func() time.Time
+}
+
+func newNotionalBudget(cap decimal.Decimal, resetOffset time.Duration) *notionalBudget {
+	return &notionalBudget{
+		cap:         cap,
+		resetOffset: resetOffset,
+		now:         time.Now,
+	}
+}
+
+// roll starts a new window once the current one has passed its reset time
+func (b *notionalBudget) roll() {
+	now := b.now().UTC()
+	start := now.Add(-b.resetOffset).Truncate(24 * time.Hour).Add(b.resetOffset)
+	if !start.Equal(b.windowStart) {
+		b.windowStart = start
+		b.used = decimal.Zero
+	}
+}
+
+// remaining returns the notional still available in the current window
+func (b *notionalBudget) remaining() decimal.Decimal {
+	b.roll()
+	return decimal.Max(b.cap.Sub(b.used), decimal.Zero)
+}
+
+// reserve consumes notional, failing without side effects if it would
+// exceed the cap
+func (b *notionalBudget) reserve(notional decimal.Decimal) error {
+	remaining := b.remaining()
+	if notional.GreaterThan(remaining) {
+		return fmt.Errorf("daily notional cap exceeded: bracket needs %s, remaining budget is %s of %s (resets %s)",
+			notional, remaining, b.cap, b.windowStart.Add(24*time.Hour).Format(time.RFC3339))
+	}
+	b.used = b.used.Add(notional)
+	return nil
+}
+
+// refund returns notional reserved in the current window
+func (b *notionalBudget) refund(notional decimal.Decimal, reservedIn time.Time) {
+	b.roll()
+	if !reservedIn.Equal(b.windowStart) {
+		return
+	}
+	b.used = decimal.Max(b.used.Sub(notional), decimal.Zero)
+}
+
+// bracketNotional estimates the notional a bracket commits. Market entries
+// have no price yet, so the highest exit price bounds the fill.
+func bracketNotional(req *PlaceBracketRequest) decimal.Decimal {
+	price := req.EntryPrice
+	if price.IsZero() {
+		price = req.StopLossPrice
+		for _, tp := range req.TakeProfitPrices {
+			price = decimal.Max(price, tp)
+		}
+	}
+	return price.Mul(req.Quantity)
+}
+
+// bracketLegCount is the number of orders a bracket places: the entry, each
+// take profit and the stop loss
+func bracketLegCount(req *PlaceBracketRequest) int {
+	return len(req.TakeProfitPrices) + 2
+}
+
+// checkOpenOrderLimit fails if placing the bracket would push the symbol past
+// its MAX_NUM_ORDERS filter. Open orders are only fetched for symbols that
+// have the filter.
+func checkOpenOrderLimit(ctx context.Context, client *binance.Client, req *PlaceBracketRequest) error {
+	info, err := client.GetExchangeInfoForSymbol(ctx, req.Symbol)
+	if err != nil {
+		return fmt.Errorf("failed to get symbol info: %w", err)
+	}
+	if info.MaxNumOrders <= 0 {
+		return nil
+	}
+
+	legs := bracketLegCount(req)
+	if legs > info.MaxNumOrders {
+		return fmt.Errorf("bracket needs %d orders but %s allows at most %d open orders",
+			legs, req.Symbol, info.MaxNumOrders)
+	}
+
+	open, err := client.GetOpenOrders(ctx, req.Symbol)
+	if err != nil {
+		return fmt.Errorf("failed to count open orders: %w", err)
+	}
+	if len(open)+legs > info.MaxNumOrders {
+		return fmt.Errorf("bracket needs %d orders but %s already has %d open of %d allowed",
+			legs, req.Symbol, len(open), info.MaxNumOrders)
+	}
+
+	return nil
+}
+
+// checkQuantityBounds rejects a quantity outside the symbol's LOT_SIZE
+// bounds. With ClampToFilters set it instead moves the quantity to the
+// nearest bound and returns a warning describing the change.
+func checkQuantityBounds(ctx context.Context, client *binance.Client, req *PlaceBracketRequest) (string, error) {
+	info, err := client.GetExchangeInfoForSymbol(ctx, req.Symbol)
+	if err != nil {
+		return "", fmt.Errorf("failed to get symbol info: %w", err)
+	}
+
+	// Zero means the bound is disabled
+	var bound decimal.Decimal
+	var relation string
+	switch {
+	case info.MinQuantity.IsPositive() && req.Quantity.LessThan(info.MinQuantity):
+		bound, relation = info.MinQuantity, "below minimum"
+	case info.MaxQuantity.IsPositive() && req.Quantity.GreaterThan(info.MaxQuantity):
+		bound, relation = info.MaxQuantity, "above maximum"
+	default:
+		return "", nil
+	}
+
+	if !req.ClampToFilters {
+		return "", fmt.Errorf("quantity %s is %s %s for %s", req.Quantity, relation, bound, req.Symbol)
+	}
+	warning := fmt.Sprintf("quantity %s is %s %s for %s, clamped to %s", req.Quantity, relation, bound, req.Symbol, bound)
+	req.Quantity = bound
+	return warning, nil
+}
+
+// MaxQuantity returns the largest futures quantity for symbol that the
+// account's available margin supports at leverage, priced at the current mark
+// price. The result is rounded down to the step size and capped by the
+// symbol's maximum quantity and notional. Zero means not even the minimum
+// quantity is affordable.
+func (m *Manager) MaxQuantity(ctx context.Context, symbol string, leverage int) (decimal.Decimal, error) {
+	if m.futuresClient == nil {
+		return decimal.Zero, fmt.Errorf("futures venue is not enabled")
+	}
+	if leverage <= 0 {
+		return decimal.Zero, fmt.Errorf("leverage must be positive, got %d", leverage)
+	}
+
+	account, err := m.futuresClient.GetFuturesAccountInfo(ctx)
+	if err != nil {
+		return decimal.Zero, err
+	}
+	markPrice, err := m.futuresClient.GetMarkPrice(ctx, symbol)
+	if err != nil {
+		return decimal.Zero, err
+	}
+	if !markPrice.IsPositive() {
+		return decimal.Zero, fmt.Errorf("no mark price for %s", symbol)
+	}
+	info, err := m.futuresClient.GetExchangeInfoForSymbol(ctx, symbol)
+	if err != nil {
+		return decimal.Zero, err
+	}
+
+	return maxQuantity(account.AvailableMargin(), markPrice, leverage, info), nil
+}
+
+// maxQuantity sizes a position from margin and leverage within the symbol's
+// filters
+func maxQuantity(margin, markPrice decimal.Decimal, leverage int, info *binance.SymbolInfo) decimal.Decimal {
+	notional := margin.Mul(decimal.NewFromInt(int64(leverage)))
+	if info.MaxNotional.IsPositive() && notional.GreaterThan(info.MaxNotional) {
+		notional = info.MaxNotional
+	}
+
+	quantity := notional.Div(markPrice)
+	if info.MaxQuantity.IsPositive() && quantity.GreaterThan(info.MaxQuantity) {
+		quantity = info.MaxQuantity
+	}
+	if info.StepSize.IsPositive() {
+		quantity = quantity.Div(info.StepSize).Floor().Mul(info.StepSize)
+	} else {
+		quantity = quantity.Truncate(int32(info.QuantityPrecision))
+	}
+
+	if quantity.LessThan(info.MinQuantity) || !quantity.IsPositive() {
+		return decimal.Zero
+	}
+	return quantity
+}
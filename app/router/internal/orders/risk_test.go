@@ -0,0 +1,114 @@
+package orders
+
+import (
+	"testing"
+	"time"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"router/internal/binance"
+)
+
+func TestNotionalBudget(t *testing.T) {
+	t.Run("resets at UTC midnight", func(t *testing.T) {
+		now := time.Date(2024, 3, 1, 23, 0, 0, 0, time.UTC)
+		budget := newNotionalBudget(decimal.NewFromInt(100), 0)
+		budget.now = func() time.Time { return now }
+
+		require.NoError(t, budget.reserve(decimal.NewFromInt(60)))
+		require.NoError(t, budget.reserve(decimal.NewFromInt(40)))
+
+		err := budget.reserve(decimal.NewFromInt(1))
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "remaining budget is 0 of 100")
+		assert.Contains(t, err.Error(), "resets 2024-03-02T00:00:00Z")
+
+		now = now.Add(time.Hour)
+		assert.True(t, budget.remaining().Equal(decimal.NewFromInt(100)))
+	})
+
+	t.Run("honours reset offset", func(t *testing.T) {
+		// Window runs 08:00 to 08:00 UTC
+		now := time.Date(2024, 3, 2, 7, 0, 0, 0, time.UTC)
+		budget := newNotionalBudget(decimal.NewFromInt(100), 8*time.Hour)
+		budget.now = func() time.Time { return now }
+
+		require.NoError(t, budget.reserve(decimal.NewFromInt(100)))
+
+		now = time.Date(2024, 3, 2, 7, 59, 0, 0, time.UTC)
+		assert.True(t, budget.remaining().IsZero())
+
+		now = time.Date(2024, 3, 2, 8, 0, 0, 0, time.UTC)
+		assert.True(t, budget.remaining().Equal(decimal.NewFromInt(100)))
+	})
+
+	t.Run("refunds only within the same window", func(t *testing.T) {
+		now := time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+		budget := newNotionalBudget(decimal.NewFromInt(100), 0)
+		budget.now = func() time.Time { return now }
+
+		require.NoError(t, budget.reserve(decimal.NewFromInt(70)))
+		window := budget.windowStart
+		budget.refund(decimal.NewFromInt(70), window)
+		assert.True(t, budget.remaining().Equal(decimal.NewFromInt(100)))
+
+		require.NoError(t, budget.reserve(decimal.NewFromInt(70)))
+		now = now.Add(24 * time.Hour)
+		require.NoError(t, budget.reserve(decimal.NewFromInt(50)))
+		budget.refund(decimal.NewFromInt(70), window)
+		assert.True(t, budget.remaining().Equal(decimal.NewFromInt(50)))
+	})
+}
+
+func TestBracketNotional(t *testing.T) {
+	req := &PlaceBracketRequest{
+		Side:             "BUY",
+		Quantity:         decimal.RequireFromString("0.5"),
+		EntryPrice:       decimal.NewFromInt(100),
+		TakeProfitPrices: []decimal.Decimal{decimal.NewFromInt(110), decimal.NewFromInt(120)},
+		StopLossPrice:    decimal.NewFromInt(90),
+	}
+	assert.Equal(t, "50", bracketNotional(req).String())
+
+	// Market entries are bounded by the highest exit price
+	req.EntryPrice = decimal.Zero
+	assert.Equal(t, "60", bracketNotional(req).String())
+}
+
+func TestMaxQuantitySizing(t *testing.T) {
+	info := &binance.SymbolInfo{
+		Symbol:      "BTCUSDT",
+		StepSize:    decimal.RequireFromString("0.001"),
+		MinQuantity: decimal.RequireFromString("0.001"),
+		MaxQuantity: decimal.RequireFromString("1000"),
+	}
+
+	tests := []struct {
+		name        string
+		margin      string
+		markPrice   string
+		leverage    int
+		maxNotional string
+		want        string
+	}{
+		{name: "rounds down to step size", margin: "1000", markPrice: "30000", leverage: 10, want: "0.333"},
+		{name: "unlevered", margin: "1000", markPrice: "30000", leverage: 1, want: "0.033"},
+		{name: "capped by max notional", margin: "1000", markPrice: "30000", leverage: 20, maxNotional: "15000", want: "0.5"},
+		{name: "capped by max quantity", margin: "1000000", markPrice: "1", leverage: 125, want: "1000"},
+		{name: "below minimum quantity", margin: "1", markPrice: "30000", leverage: 10, want: "0"},
+		{name: "no margin", margin: "0", markPrice: "30000", leverage: 10, want: "0"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			symbol := *info
+			if tt.maxNotional != "" {
+				symbol.MaxNotional = decimal.RequireFromString(tt.maxNotional)
+			}
+
+			got := maxQuantity(decimal.RequireFromString(tt.margin), decimal.RequireFromString(tt.markPrice), tt.leverage, &symbol)
+			assert.Equal(t, tt.want, got.String())
+		})
+	}
+}
@@ -14,6 +14,15 @@ const (
 	OrderTypeFutures OrderType = "FUTURES"
 )
 
+// BracketState tracks a bracket through its lifecycle
+type BracketState string
+
+const (
+	BracketStatePending BracketState = "PENDING" // entry working
+	BracketStateOpen    BracketState = "OPEN"    // entry filled, exits working
+	BracketStateClosed  BracketState = "CLOSED"  // an exit completed the bracket
+)
+
 // BracketOrder represents a bracket order (main + TPs + SL)
 type BracketOrder struct {
 	ID               string            `json:"id"`
@@ -23,10 +32,24 @@ type BracketOrder struct {
 	Quantity         decimal.Decimal   `json:"quantity"`
 	EntryPrice       decimal.Decimal   `json:"entry_price"`
 	TakeProfitPrices []decimal.Decimal `json:"take_profit_prices"`
-	StopLossPrice    decimal.Decimal   `json:"stop_loss_price"`
-	ClientOrderIDs   ClientOrderIDs    `json:"client_order_ids"`
-	CreatedAt        time.Time         `json:"created_at"`
-	UpdatedAt        time.Time         `json:"updated_at"`
+	// TakeProfitQuantities is the quantity of each take profit leg after
+	// splitting and rounding
+	TakeProfitQuantities []decimal.Decimal `json:"take_profit_quantities"`
+	StopLossPrice        decimal.Decimal   `json:"stop_loss_price"`
+	ClientOrderIDs       ClientOrderIDs    `json:"client_order_ids"`
+	State                BracketState      `json:"state"`
+	CreatedAt            time.Time         `json:"created_at"`
+	UpdatedAt            time.Time         `json:"updated_at"`
+	WorkingType          string            `json:"working_type,omitempty"`
+	Profile              string            `json:"profile,omitempty"`
+
+	// MoveStopToBreakevenOnTP1 is copied from the request; StopMovedToBreakeven
+	// records that the move has happened
+	MoveStopToBreakevenOnTP1 bool `json:"move_stop_to_breakeven_on_tp1,omitempty"`
+	StopMovedToBreakeven     bool `json:"stop_moved_to_breakeven,omitempty"`
+
+	filledLegs     map[string]bool // guarded by Manager.mu
+	entryFillPrice decimal.Decimal // price of the entry fill, guarded by Manager.mu
 }
 
 // ClientOrderIDs holds the client order IDs for a bracket order
@@ -59,9 +82,25 @@ type PlaceBracketRequest struct {
 	Quantity         decimal.Decimal   `json:"quantity"`
 	EntryPrice       decimal.Decimal   `json:"entry_price,omitempty"`
 	TakeProfitPrices []decimal.Decimal `json:"take_profit_prices"`
+	// TakeProfitRatios optionally sets each take profit's share of Quantity,
+	// one per price, summing to exactly 1. Omitted means an even split.
+	TakeProfitRatios []decimal.Decimal `json:"take_profit_ratios,omitempty"`
 	StopLossPrice    decimal.Decimal   `json:"stop_loss_price"`
 	OrderType        string            `json:"order_type,omitempty"` // LIMIT or MARKET
 	IsFutures        bool              `json:"is_futures"`
+	WorkingType      string            `json:"working_type,omitempty"` // Futures SL trigger: MARK_PRICE or CONTRACT_PRICE
+	// Venue names the target venue, spot or futures. When it is omitted and
+	// IsFutures is unset the router may resolve the venue from the symbol.
+	Venue string `json:"venue,omitempty"`
+	// Profile names the API key profile to trade with; empty uses the
+	// venue's default key
+	Profile string `json:"profile,omitempty"`
+	// MoveStopToBreakevenOnTP1 replaces the stop loss with one at the entry
+	// price, adjusted for fees, once the first take profit fills
+	MoveStopToBreakevenOnTP1 bool `json:"move_stop_to_breakeven_on_tp1,omitempty"`
+	// ClampToFilters clamps a quantity outside the symbol's LOT_SIZE bounds
+	// to the nearest bound, with a warning, instead of rejecting the order
+	ClampToFilters bool `json:"clamp_to_filters,omitempty"`
 }
 
 // PlaceBracketResponse represents the response from placing a bracket order
@@ -74,6 +113,7 @@ type PlaceBracketResponse struct {
 	CreatedAt      time.Time       `json:"created_at"`
 	PartialFailure bool            `json:"partial_failure,omitempty"`
 	Errors         []string        `json:"errors,omitempty"`
+	Warnings       []string        `json:"warnings,omitempty"`
 }
 
 // CancelRequest represents a request to cancel an order
@@ -83,8 +123,31 @@ type CancelRequest struct {
 	ClientOrderID string `json:"client_order_id,omitempty"`
 }
 
+// CancelBracketRequest represents a request to cancel every leg of a bracket
+type CancelBracketRequest struct {
+	BracketOrderID string `json:"bracket_order_id"`
+}
+
+// KillSwitchRequest engages the kill switch. With ClosePositions set, open
+// orders are also closed on Symbols, or on the manager's configured
+// kill-switch symbols when Symbols is empty.
+type KillSwitchRequest struct {
+	Reason         string   `json:"reason"`
+	ClosePositions bool     `json:"close_positions,omitempty"`
+	Symbols        []string `json:"symbols,omitempty"`
+}
+
+// KillSwitchState describes whether new placements are blocked
+type KillSwitchState struct {
+	Engaged   bool      `json:"engaged"`
+	Reason    string    `json:"reason,omitempty"`
+	EngagedAt time.Time `json:"engaged_at"`
+}
+
 // CloseAllRequest represents a request to close all positions
 type CloseAllRequest struct {
 	Symbol    string `json:"symbol,omitempty"`
 	IsFutures bool   `json:"is_futures"`
+	Venue     string `json:"venue,omitempty"`
+	Profile   string `json:"profile,omitempty"`
 }
@@ -0,0 +1,106 @@
+package orders
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"router/internal/binance"
+)
+
+// Venue names accepted in requests
+const (
+	VenueSpot    = "spot"
+	VenueFutures = "futures"
+)
+
+// DefaultVenueCacheTTL is how long a symbol's resolved venue is reused
+const DefaultVenueCacheTTL = 5 * time.Minute
+
+// ErrNoVenue is returned when a symbol trades on none of the enabled venues
+var ErrNoVenue = errors.New("not tradable on any enabled venue")
+
+// VenueResolver determines where a symbol trades by consulting exchange info
+// for each enabled venue. Symbols listed on both venues resolve to spot, the
+// default for requests that don't name a venue.
+type VenueResolver struct {
+	spotClient    *binance.Client
+	futuresClient *binance.Client
+	ttl           time.Duration
+
+	mu       sync.RWMutex
+	resolved map[string]resolvedVenue
+}
+
+type resolvedVenue struct {
+	isFutures bool
+	at        time.Time
+}
+
+// NewVenueResolver creates a resolver over the enabled clients; either may be
+// nil. A non-positive ttl uses DefaultVenueCacheTTL.
+func NewVenueResolver(spotClient, futuresClient *binance.Client, ttl time.Duration) *VenueResolver {
+	if ttl <= 0 {
+		ttl = DefaultVenueCacheTTL
+	}
+	return &VenueResolver{
+		spotClient:    spotClient,
+		futuresClient: futuresClient,
+		ttl:           ttl,
+		resolved:      make(map[string]resolvedVenue),
+	}
+}
+
+// Resolve reports whether symbol should be routed to futures. Lookup failures
+// other than the symbol being unlisted are returned rather than guessed
+// around, and are not cached.
+func (r *VenueResolver) Resolve(ctx context.Context, symbol string) (bool, error) {
+	if symbol == "" {
+		return false, fmt.Errorf("symbol is required")
+	}
+
+	r.mu.RLock()
+	cached, ok := r.resolved[symbol]
+	r.mu.RUnlock()
+	if ok && time.Since(cached.at) < r.ttl {
+		return cached.isFutures, nil
+	}
+
+	onSpot, err := listedOn(ctx, r.spotClient, symbol)
+	if err != nil {
+		return false, fmt.Errorf("failed to look up %s on spot: %w", symbol, err)
+	}
+	onFutures := false
+	if !onSpot {
+		onFutures, err = listedOn(ctx, r.futuresClient, symbol)
+		if err != nil {
+			return false, fmt.Errorf("failed to look up %s on futures: %w", symbol, err)
+		}
+		if !onFutures {
+			return false, fmt.Errorf("symbol %s is %w", symbol, ErrNoVenue)
+		}
+	}
+
+	r.mu.Lock()
+	r.resolved[symbol] = resolvedVenue{isFutures: onFutures, at: time.Now()}
+	r.mu.Unlock()
+
+	return onFutures, nil
+}
+
+// listedOn reports whether client's venue trades symbol. A nil client means
+// the venue is disabled.
+func listedOn(ctx context.Context, client *binance.Client, symbol string) (bool, error) {
+	if client == nil {
+		return false, nil
+	}
+	if _, err := client.GetExchangeInfoForSymbol(ctx, symbol); err != nil {
+		if errors.Is(err, binance.ErrSymbolNotFound) {
+			return false, nil
+		}
+		return false, err
+	}
+	return true, nil
+}
@@ -0,0 +1,118 @@
+package orders
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"router/internal/auth"
+	"router/internal/binance"
+	"router/internal/config"
+	"router/internal/rest"
+	"router/internal/testutil"
+)
+
+func testSymbol(name string) testutil.Symbol {
+	symbol := testutil.BTCUSDT
+	symbol.Symbol = name
+	return symbol
+}
+
+// newVenueFakes starts separate spot and futures fakes listing BTCUSDT on
+// both, SPOTONLY on spot and PERPONLY on futures
+func newVenueFakes(t *testing.T) (*testutil.FakeBinance, *binance.Client, *testutil.FakeBinance, *binance.Client) {
+	t.Helper()
+
+	spotFake := testutil.NewFakeBinance(t)
+	spotFake.AddSymbol(testutil.BTCUSDT)
+	spotFake.AddSymbol(testSymbol("SPOTONLY"))
+
+	futuresFake := testutil.NewFakeBinance(t)
+	futuresFake.AddSymbol(testutil.BTCUSDT)
+	futuresFake.AddSymbol(testSymbol("PERPONLY"))
+
+	signer := auth.NewSigner("test-key", "test-secret")
+	spot, err := binance.NewClient(spotFake.URL(), signer, rest.NewClient(spotFake.URL(), signer, rest.WithMaxRetries(0)), zerolog.Nop())
+	require.NoError(t, err)
+
+	futures, err := binance.NewFuturesClient(&config.BinanceConfig{
+		FuturesAPIKey:        "test-key",
+		FuturesSecretKey:     "test-secret",
+		Timeout:              5 * time.Second,
+		ExchangeInfoCacheTTL: time.Minute,
+	}, futuresFake.URL(), zerolog.Nop())
+	require.NoError(t, err)
+
+	return spotFake, spot, futuresFake, futures
+}
+
+func TestVenueResolver_Resolve(t *testing.T) {
+	ctx := context.Background()
+
+	tests := []struct {
+		name        string
+		symbol      string
+		wantFutures bool
+		wantErr     error
+	}{
+		{name: "spot-only symbol", symbol: "SPOTONLY", wantFutures: false},
+		{name: "futures-only symbol", symbol: "PERPONLY", wantFutures: true},
+		{name: "symbol on both venues prefers spot", symbol: "BTCUSDT", wantFutures: false},
+		{name: "symbol on neither venue", symbol: "NOPEUSDT", wantErr: ErrNoVenue},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, spot, _, futures := newVenueFakes(t)
+			resolver := NewVenueResolver(spot, futures, time.Minute)
+
+			isFutures, err := resolver.Resolve(ctx, tt.symbol)
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantFutures, isFutures)
+		})
+	}
+
+	t.Run("symbol on both venues with spot disabled", func(t *testing.T) {
+		_, _, _, futures := newVenueFakes(t)
+		resolver := NewVenueResolver(nil, futures, time.Minute)
+
+		isFutures, err := resolver.Resolve(ctx, "BTCUSDT")
+		require.NoError(t, err)
+		assert.True(t, isFutures)
+
+		_, err = resolver.Resolve(ctx, "SPOTONLY")
+		assert.ErrorIs(t, err, ErrNoVenue)
+	})
+
+	t.Run("caches resolved venues", func(t *testing.T) {
+		spotFake, spot, futuresFake, futures := newVenueFakes(t)
+		resolver := NewVenueResolver(spot, futures, time.Minute)
+
+		for i := 0; i < 3; i++ {
+			isFutures, err := resolver.Resolve(ctx, "PERPONLY")
+			require.NoError(t, err)
+			assert.True(t, isFutures)
+		}
+		assert.Equal(t, 1, spotFake.RequestCount("/api/v3/exchangeInfo"))
+		assert.Equal(t, 1, futuresFake.RequestCount("/fapi/v1/exchangeInfo"))
+	})
+
+	t.Run("lookup failures are returned, not guessed around", func(t *testing.T) {
+		spotFake, spot, _, futures := newVenueFakes(t)
+		spotFake.Close()
+		resolver := NewVenueResolver(spot, futures, time.Minute)
+
+		_, err := resolver.Resolve(ctx, "PERPONLY")
+		require.Error(t, err)
+		assert.False(t, errors.Is(err, ErrNoVenue))
+		assert.Contains(t, err.Error(), "on spot")
+	})
+}
@@ -0,0 +1,474 @@
+package paper
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/rs/zerolog"
+	"github.com/shopspring/decimal"
+	"router/internal/binance"
+	"router/internal/rest"
+	"router/internal/websocket"
+)
+
+// SymbolResolver looks up the base and quote assets of a symbol.
+// binance.Client satisfies it.
+type SymbolResolver interface {
+	GetExchangeInfoForSymbol(ctx context.Context, symbol string) (*binance.SymbolInfo, error)
+}
+
+// SimulatedExchange fills spot orders against market data from the WebSocket
+// client and settles them against virtual balances. It satisfies
+// binance.OrderPlacer, so it can stand in for REST or the WebSocket API, and
+// binance.OrderStore, so simulated orders are looked up and cancelled here
+// rather than on the live account.
+//
+// Market orders sweep the symbol's depth source level by level, falling back
+// to the ticker's best bid/ask while no synced depth is available. Liquidity
+// consumed by a fill is not removed from the book, which always reflects the
+// exchange's view. Other order types are accepted and left resting without
+// being matched until they are cancelled.
+type SimulatedExchange struct {
+	resolver SymbolResolver
+	logger   zerolog.Logger
+	now      func() time.Time
+
+	mu       sync.Mutex
+	books    map[string]*book // symbol -> book
+	balances map[string]decimal.Decimal
+	orders   []*rest.Order // every simulated order, in placement order
+	orderID  int64
+	tradeID  int64
+}
+
+// DepthSource is a symbol's order book kept in step with the exchange.
+// websocket.DepthBook satisfies it.
+type DepthSource interface {
+	Synced() bool
+	Bids(limit int) []websocket.PriceLevel
+	Asks(limit int) []websocket.PriceLevel
+}
+
+// Option configures a SimulatedExchange
+type Option func(*SimulatedExchange)
+
+// WithBalances seeds the virtual account
+func WithBalances(balances map[string]decimal.Decimal) Option {
+	return func(s *SimulatedExchange) {
+		for asset, amount := range balances {
+			s.balances[asset] = amount
+		}
+	}
+}
+
+// WithSymbolResolver sets where base and quote assets are looked up
+func WithSymbolResolver(resolver SymbolResolver) Option {
+	return func(s *SimulatedExchange) {
+		s.resolver = resolver
+	}
+}
+
+// WithLogger sets the logger
+func WithLogger(logger zerolog.Logger) Option {
+	return func(s *SimulatedExchange) {
+		s.logger = logger
+	}
+}
+
+// book is a symbol's order book as seen from market data
+type book struct {
+	depth DepthSource
+	// Best bid/ask from the ticker, used while depth is not synced
+	bestBid decimal.Decimal
+	bestAsk decimal.Decimal
+}
+
+// NewSimulatedExchange creates a simulated exchange with empty balances
+func NewSimulatedExchange(opts ...Option) *SimulatedExchange {
+	s := &SimulatedExchange{
+		logger:   zerolog.Nop(),
+		now:      time.Now,
+		books:    make(map[string]*book),
+		balances: make(map[string]decimal.Decimal),
+	}
+
+	for _, opt := range opts {
+		opt(s)
+	}
+
+	return s
+}
+
+// SetDepthSource sets where symbol's depth is read from. Diffs from the depth
+// stream are not enough on their own: the source must be synced from a
+// snapshot, as websocket.DepthBook is.
+func (s *SimulatedExchange) SetDepthSource(symbol string, source DepthSource) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.bookFor(symbol).depth = source
+}
+
+// HandleTicker records the best bid and ask
+func (s *SimulatedExchange) HandleTicker(event *websocket.TickerEvent) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	b := s.bookFor(event.Symbol)
+	b.bestBid = event.BidPrice
+	b.bestAsk = event.AskPrice
+	return nil
+}
+
+// Balance returns the virtual balance of asset
+func (s *SimulatedExchange) Balance(asset string) decimal.Decimal {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.balances[asset]
+}
+
+// PlaceOrder simulates order placement, filling market orders immediately.
+// A market order larger than the book fills what it can and expires.
+func (s *SimulatedExchange) PlaceOrder(ctx context.Context, req *rest.OrderRequest) (*rest.OrderResponse, error) {
+	if req == nil {
+		return nil, fmt.Errorf("order request is required")
+	}
+	if s.resolver == nil {
+		return nil, fmt.Errorf("symbol resolver required for simulated orders")
+	}
+
+	info, err := s.resolver.GetExchangeInfoForSymbol(ctx, req.Symbol)
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve %s: %w", req.Symbol, err)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.orderID++
+	resp := &rest.OrderResponse{
+		Symbol:        req.Symbol,
+		OrderID:       s.orderID,
+		OrderListID:   -1,
+		ClientOrderID: req.NewClientOrderID,
+		TransactTime:  s.now().UnixMilli(),
+		Price:         req.Price,
+		OrigQty:       req.Quantity,
+		TimeInForce:   req.TimeInForce,
+		Type:          req.Type,
+		Side:          req.Side,
+		Status:        "NEW",
+	}
+	if resp.ClientOrderID == "" {
+		resp.ClientOrderID = fmt.Sprintf("paper_%d", s.orderID)
+	}
+
+	if req.Type != "MARKET" {
+		s.record(resp)
+		s.logger.Debug().
+			Str("symbol", req.Symbol).
+			Str("type", req.Type).
+			Str("client_order_id", resp.ClientOrderID).
+			Msg("Simulated order resting")
+		return resp, nil
+	}
+
+	exec, err := s.sweep(req)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.settle(info, req.Side, exec.filled, exec.cost); err != nil {
+		return nil, err
+	}
+
+	commissionAsset := info.QuoteAsset
+	if req.Side == "BUY" {
+		commissionAsset = info.BaseAsset
+	}
+	for i := range exec.fills {
+		s.tradeID++
+		exec.fills[i].TradeID = s.tradeID
+		exec.fills[i].Commission = decimal.Zero
+		exec.fills[i].CommissionAsset = commissionAsset
+	}
+
+	slippage := exec.slippage(req.Side)
+	resp.Price = decimal.Zero
+	resp.ExecutedQty = exec.filled
+	resp.CummulativeQuoteQty = exec.cost
+	resp.Fills = exec.fills
+	resp.Slippage = &slippage
+	if !req.QuoteOrderQty.IsZero() {
+		resp.OrigQty = exec.filled
+	}
+
+	// As on Binance, the part of a market order the book cannot absorb expires
+	resp.Status = "FILLED"
+	if !exec.complete {
+		resp.Status = "EXPIRED"
+		s.logger.Warn().
+			Str("symbol", req.Symbol).
+			Str("side", req.Side).
+			Str("executed_qty", exec.filled.String()).
+			Str("orig_qty", req.Quantity.String()).
+			Msg("Simulated market order exhausted the book and partially filled")
+	}
+
+	s.record(resp)
+	s.logger.Info().
+		Str("symbol", req.Symbol).
+		Str("side", req.Side).
+		Str("status", resp.Status).
+		Str("executed_qty", exec.filled.String()).
+		Str("quote_qty", exec.cost.String()).
+		Str("slippage", slippage.String()).
+		Msg("Simulated market order executed")
+
+	return resp, nil
+}
+
+// record keeps a placed order for later queries. Callers must hold mu.
+func (s *SimulatedExchange) record(resp *rest.OrderResponse) {
+	s.orders = append(s.orders, &rest.Order{
+		Symbol:              resp.Symbol,
+		OrderID:             resp.OrderID,
+		OrderListID:         resp.OrderListID,
+		ClientOrderID:       resp.ClientOrderID,
+		Price:               resp.Price,
+		OrigQty:             resp.OrigQty,
+		ExecutedQty:         resp.ExecutedQty,
+		CummulativeQuoteQty: resp.CummulativeQuoteQty,
+		Status:              resp.Status,
+		TimeInForce:         resp.TimeInForce,
+		Type:                resp.Type,
+		Side:                resp.Side,
+		Time:                resp.TransactTime,
+		UpdateTime:          resp.TransactTime,
+		IsWorking:           resp.Status == "NEW",
+	})
+}
+
+// CancelOrder cancels a resting simulated order
+func (s *SimulatedExchange) CancelOrder(ctx context.Context, symbol string, orderID int64) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, order := range s.orders {
+		if order.OrderID != orderID || order.Symbol != symbol {
+			continue
+		}
+		if order.Status != "NEW" {
+			break
+		}
+		order.Status = "CANCELED"
+		order.IsWorking = false
+		order.UpdateTime = s.now().UnixMilli()
+		s.logger.Debug().
+			Str("symbol", symbol).
+			Str("client_order_id", order.ClientOrderID).
+			Msg("Simulated order canceled")
+		return nil
+	}
+	return unknownOrder(-2011, "Unknown order sent.")
+}
+
+// GetOrder looks up a simulated order by its client order ID
+func (s *SimulatedExchange) GetOrder(ctx context.Context, symbol, clientOrderID string) (*rest.Order, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, order := range s.orders {
+		if order.Symbol == symbol && order.ClientOrderID == clientOrderID {
+			found := *order
+			return &found, nil
+		}
+	}
+	return nil, unknownOrder(-2013, "Order does not exist.")
+}
+
+// GetOpenOrders returns symbol's resting simulated orders
+func (s *SimulatedExchange) GetOpenOrders(ctx context.Context, symbol string) ([]rest.Order, error) {
+	return s.openOrders(symbol), nil
+}
+
+// GetAllOpenOrders returns every resting simulated order
+func (s *SimulatedExchange) GetAllOpenOrders(ctx context.Context) ([]rest.Order, error) {
+	return s.openOrders(""), nil
+}
+
+// openOrders returns resting orders on symbol, or on every symbol when empty
+func (s *SimulatedExchange) openOrders(symbol string) []rest.Order {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	open := []rest.Order{}
+	for _, order := range s.orders {
+		if order.Status == "NEW" && (symbol == "" || order.Symbol == symbol) {
+			open = append(open, *order)
+		}
+	}
+	return open
+}
+
+// execution is the outcome of sweeping the book for one market order
+type execution struct {
+	fills    []rest.Fill
+	filled   decimal.Decimal
+	cost     decimal.Decimal
+	best     decimal.Decimal // best price when the order arrived
+	complete bool            // false when the book ran out first
+}
+
+// slippage returns how far the average fill price fell from the best quote
+// against the taker, as a fraction of that quote
+func (e *execution) slippage(side string) decimal.Decimal {
+	if e.filled.IsZero() || e.best.IsZero() {
+		return decimal.Zero
+	}
+	vwap := e.cost.Div(e.filled)
+	diff := vwap.Sub(e.best)
+	if side == "SELL" {
+		diff = diff.Neg()
+	}
+	return diff.DivRound(e.best, 8)
+}
+
+// sweep walks the opposite side of the book, best price first, until the
+// order is filled or the book is exhausted. Callers must hold mu.
+func (s *SimulatedExchange) sweep(req *rest.OrderRequest) (*execution, error) {
+	var levels []websocket.PriceLevel
+	if b, ok := s.books[req.Symbol]; ok {
+		levels = b.levels(req.Side)
+	}
+	if len(levels) == 0 {
+		return nil, fmt.Errorf("no market data for %s", req.Symbol)
+	}
+
+	exec := &execution{best: levels[0].Price}
+	byQuote := !req.QuoteOrderQty.IsZero()
+	for _, level := range levels {
+		// Quote-sized orders buy what the remaining budget affords, truncated
+		// so the cost never exceeds it
+		qty := req.Quantity.Sub(exec.filled)
+		if byQuote {
+			qty = req.QuoteOrderQty.Sub(exec.cost).Div(level.Price).Truncate(8)
+		}
+		if !qty.IsPositive() {
+			exec.complete = true
+			break
+		}
+		// A zero quantity marks a ticker price of unknown size
+		capped := level.Quantity.IsPositive() && qty.GreaterThan(level.Quantity)
+		if capped {
+			qty = level.Quantity
+		}
+
+		exec.fills = append(exec.fills, rest.Fill{Price: level.Price, Qty: qty})
+		exec.filled = exec.filled.Add(qty)
+		exec.cost = exec.cost.Add(qty.Mul(level.Price))
+		if !capped {
+			exec.complete = true
+			break
+		}
+	}
+
+	return exec, nil
+}
+
+// settle moves a fill through the virtual balances. Callers must hold mu.
+func (s *SimulatedExchange) settle(info *binance.SymbolInfo, side string, filled, cost decimal.Decimal) error {
+	base, quote := info.BaseAsset, info.QuoteAsset
+
+	switch side {
+	case "BUY":
+		if s.balances[quote].LessThan(cost) {
+			return insufficientBalance()
+		}
+		s.balances[quote] = s.balances[quote].Sub(cost)
+		s.balances[base] = s.balances[base].Add(filled)
+	case "SELL":
+		if s.balances[base].LessThan(filled) {
+			return insufficientBalance()
+		}
+		s.balances[base] = s.balances[base].Sub(filled)
+		s.balances[quote] = s.balances[quote].Add(cost)
+	default:
+		return fmt.Errorf("invalid side: %s", side)
+	}
+	return nil
+}
+
+// insufficientBalance mirrors Binance's rejection so callers handle it the same way
+func insufficientBalance() error {
+	return &rest.BinanceError{
+		Code:       -2010,
+		Message:    "Account has insufficient balance for requested action.",
+		HTTPStatus: 400,
+	}
+}
+
+// unknownOrder mirrors Binance's rejection of an order it has no record of
+func unknownOrder(code int, message string) error {
+	return &rest.BinanceError{
+		Code:       code,
+		Message:    message,
+		HTTPStatus: 400,
+	}
+}
+
+// bookFor returns the book for symbol, creating it on first use. Callers must hold mu.
+func (s *SimulatedExchange) bookFor(symbol string) *book {
+	b, ok := s.books[symbol]
+	if !ok {
+		b = &book{}
+		s.books[symbol] = b
+	}
+	return b
+}
+
+// levels returns the side of the book an order of side takes from, best
+// price first
+func (b *book) levels(side string) []websocket.PriceLevel {
+	if b.depth != nil && b.depth.Synced() {
+		levels := b.depth.Asks(0)
+		if side == "SELL" {
+			levels = b.depth.Bids(0)
+		}
+		if len(levels) > 0 {
+			return levels
+		}
+	}
+
+	best := b.bestAsk
+	if side == "SELL" {
+		best = b.bestBid
+	}
+	if best.IsPositive() {
+		return []websocket.PriceLevel{{Price: best}}
+	}
+	return nil
+}
+
+// ParseBalances parses ASSET:AMOUNT pairs such as "USDT:10000"
+func ParseBalances(pairs []string) (map[string]decimal.Decimal, error) {
+	balances := make(map[string]decimal.Decimal, len(pairs))
+	for _, pair := range pairs {
+		asset, amount, ok := strings.Cut(strings.TrimSpace(pair), ":")
+		if !ok || asset == "" {
+			return nil, fmt.Errorf("invalid balance %q: want ASSET:AMOUNT", pair)
+		}
+		value, err := decimal.NewFromString(amount)
+		if err != nil {
+			return nil, fmt.Errorf("invalid balance %q: %w", pair, err)
+		}
+		if value.IsNegative() {
+			return nil, fmt.Errorf("invalid balance %q: amount must not be negative", pair)
+		}
+		balances[strings.ToUpper(asset)] = value
+	}
+	return balances, nil
+}
@@ -0,0 +1,444 @@
+package paper
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/rs/zerolog"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"router/internal/auth"
+	"router/internal/binance"
+	"router/internal/rest"
+	"router/internal/testutil"
+	"router/internal/websocket"
+)
+
+type stubResolver struct{}
+
+func (stubResolver) GetExchangeInfoForSymbol(_ context.Context, symbol string) (*binance.SymbolInfo, error) {
+	if symbol != "BTCUSDT" {
+		return nil, errors.New("unknown symbol")
+	}
+	return &binance.SymbolInfo{Symbol: symbol, BaseAsset: "BTC", QuoteAsset: "USDT"}, nil
+}
+
+func d(s string) decimal.Decimal {
+	return decimal.RequireFromString(s)
+}
+
+func level(price, qty string) websocket.PriceLevel {
+	return websocket.PriceLevel{Price: d(price), Quantity: d(qty)}
+}
+
+// stubDepth is a depth source with fixed levels, best first
+type stubDepth struct {
+	synced     bool
+	bids, asks []websocket.PriceLevel
+}
+
+func (s *stubDepth) Synced() bool                    { return s.synced }
+func (s *stubDepth) Bids(int) []websocket.PriceLevel { return s.bids }
+func (s *stubDepth) Asks(int) []websocket.PriceLevel { return s.asks }
+
+// newBookExchange returns an exchange holding 100000 USDT and 2 BTC with a
+// synthetic BTCUSDT book
+func newBookExchange(t *testing.T) *SimulatedExchange {
+	t.Helper()
+
+	sim := NewSimulatedExchange(
+		WithSymbolResolver(stubResolver{}),
+		WithBalances(map[string]decimal.Decimal{"USDT": d("100000"), "BTC": d("2")}),
+	)
+	sim.SetDepthSource("BTCUSDT", &stubDepth{
+		synced: true,
+		bids:   []websocket.PriceLevel{level("49990", "1"), level("49980", "2")},
+		asks:   []websocket.PriceLevel{level("50000", "0.5"), level("50010", "1")},
+	})
+	return sim
+}
+
+func TestSimulatedExchange_MarketBuySweepsBook(t *testing.T) {
+	sim := newBookExchange(t)
+
+	resp, err := sim.PlaceOrder(context.Background(), &rest.OrderRequest{
+		Symbol:           "BTCUSDT",
+		Side:             "BUY",
+		Type:             "MARKET",
+		Quantity:         d("1"),
+		NewClientOrderID: "paper-buy",
+	})
+	require.NoError(t, err)
+
+	assert.Equal(t, "FILLED", resp.Status)
+	assert.Equal(t, "paper-buy", resp.ClientOrderID)
+	require.Len(t, resp.Fills, 2)
+	assert.True(t, resp.Fills[0].Price.Equal(d("50000")))
+	assert.True(t, resp.Fills[0].Qty.Equal(d("0.5")))
+	assert.True(t, resp.Fills[1].Price.Equal(d("50010")))
+	assert.True(t, resp.Fills[1].Qty.Equal(d("0.5")))
+	assert.True(t, resp.ExecutedQty.Equal(d("1")))
+	assert.True(t, resp.CummulativeQuoteQty.Equal(d("50005")), "got %s", resp.CummulativeQuoteQty)
+	require.NotNil(t, resp.Slippage)
+	assert.True(t, resp.Slippage.Equal(d("0.0001")), "got %s", resp.Slippage)
+
+	assert.True(t, sim.Balance("USDT").Equal(d("49995")), "got %s", sim.Balance("USDT"))
+	assert.True(t, sim.Balance("BTC").Equal(d("3")), "got %s", sim.Balance("BTC"))
+}
+
+func TestSimulatedExchange_MarketSell(t *testing.T) {
+	sim := newBookExchange(t)
+
+	resp, err := sim.PlaceOrder(context.Background(), &rest.OrderRequest{
+		Symbol:   "BTCUSDT",
+		Side:     "SELL",
+		Type:     "MARKET",
+		Quantity: d("1.5"),
+	})
+	require.NoError(t, err)
+
+	require.Len(t, resp.Fills, 2)
+	assert.True(t, resp.Fills[0].Price.Equal(d("49990")))
+	assert.True(t, resp.Fills[1].Price.Equal(d("49980")))
+	assert.True(t, resp.CummulativeQuoteQty.Equal(d("74980")), "got %s", resp.CummulativeQuoteQty)
+	assert.Equal(t, "USDT", resp.Fills[0].CommissionAsset)
+
+	assert.True(t, sim.Balance("BTC").Equal(d("0.5")))
+	assert.True(t, sim.Balance("USDT").Equal(d("174980")))
+}
+
+func TestSimulatedExchange_PartialFillOnShallowBook(t *testing.T) {
+	tests := []struct {
+		name         string
+		side         string
+		quantity     string
+		wantExecuted string
+		wantVWAP     string
+		wantSlippage string
+		wantUSDT     string
+		wantBTC      string
+	}{
+		{
+			name:         "buy exhausts asks",
+			side:         "BUY",
+			quantity:     "2",
+			wantExecuted: "1.5",
+			wantVWAP:     "50006.66666667",
+			wantSlippage: "0.00013333",
+			wantUSDT:     "24990",
+			wantBTC:      "4.5",
+		},
+		{
+			name:         "sell exhausts bids",
+			side:         "SELL",
+			quantity:     "5",
+			wantExecuted: "3",
+			wantVWAP:     "49983.33333333",
+			wantSlippage: "0.00013336",
+			wantUSDT:     "249950",
+			wantBTC:      "0",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sim := newBookExchange(t)
+			sim.balances["BTC"] = d("3")
+
+			resp, err := sim.PlaceOrder(context.Background(), &rest.OrderRequest{
+				Symbol:   "BTCUSDT",
+				Side:     tt.side,
+				Type:     "MARKET",
+				Quantity: d(tt.quantity),
+			})
+			require.NoError(t, err)
+
+			assert.Equal(t, "EXPIRED", resp.Status)
+			assert.Len(t, resp.Fills, 2)
+			assert.True(t, resp.OrigQty.Equal(d(tt.quantity)))
+			assert.True(t, resp.ExecutedQty.Equal(d(tt.wantExecuted)), "got %s", resp.ExecutedQty)
+
+			vwap := resp.CummulativeQuoteQty.DivRound(resp.ExecutedQty, 8)
+			assert.True(t, vwap.Equal(d(tt.wantVWAP)), "got %s", vwap)
+			require.NotNil(t, resp.Slippage)
+			assert.True(t, resp.Slippage.Equal(d(tt.wantSlippage)), "got %s", resp.Slippage)
+
+			assert.True(t, sim.Balance("USDT").Equal(d(tt.wantUSDT)), "got %s", sim.Balance("USDT"))
+			assert.True(t, sim.Balance("BTC").Equal(d(tt.wantBTC)), "got %s", sim.Balance("BTC"))
+		})
+	}
+}
+
+func TestSimulatedExchange_QuoteOrderQty(t *testing.T) {
+	sim := newBookExchange(t)
+
+	resp, err := sim.PlaceOrder(context.Background(), &rest.OrderRequest{
+		Symbol:        "BTCUSDT",
+		Side:          "BUY",
+		Type:          "MARKET",
+		QuoteOrderQty: d("35002"),
+	})
+	require.NoError(t, err)
+
+	// 0.5 BTC at 50000 spends 25000; the rest buys 0.2 BTC at 50010
+	assert.True(t, resp.ExecutedQty.Equal(d("0.7")), "got %s", resp.ExecutedQty)
+	assert.True(t, resp.CummulativeQuoteQty.Equal(d("35002")), "got %s", resp.CummulativeQuoteQty)
+	assert.True(t, sim.Balance("USDT").Equal(d("64998")))
+}
+
+func TestSimulatedExchange_TickerFallback(t *testing.T) {
+	sim := NewSimulatedExchange(
+		WithSymbolResolver(stubResolver{}),
+		WithBalances(map[string]decimal.Decimal{"USDT": d("1000")}),
+	)
+	require.NoError(t, sim.HandleTicker(&websocket.TickerEvent{
+		Symbol:   "BTCUSDT",
+		BidPrice: d("49999"),
+		AskPrice: d("50001"),
+	}))
+
+	resp, err := sim.PlaceOrder(context.Background(), &rest.OrderRequest{
+		Symbol:   "BTCUSDT",
+		Side:     "BUY",
+		Type:     "MARKET",
+		Quantity: d("0.01"),
+	})
+	require.NoError(t, err)
+
+	require.Len(t, resp.Fills, 1)
+	assert.True(t, resp.Fills[0].Price.Equal(d("50001")))
+	assert.True(t, sim.Balance("USDT").Equal(d("499.99")), "got %s", sim.Balance("USDT"))
+}
+
+func TestSimulatedExchange_Rejections(t *testing.T) {
+	tests := []struct {
+		name    string
+		req     *rest.OrderRequest
+		wantErr string
+	}{
+		{
+			name:    "insufficient quote balance",
+			req:     &rest.OrderRequest{Symbol: "BTCUSDT", Side: "BUY", Type: "MARKET", Quantity: d("1.5")},
+			wantErr: "insufficient balance",
+		},
+		{
+			name:    "insufficient base balance",
+			req:     &rest.OrderRequest{Symbol: "BTCUSDT", Side: "SELL", Type: "MARKET", Quantity: d("2.5")},
+			wantErr: "insufficient balance",
+		},
+		{
+			name:    "unknown symbol",
+			req:     &rest.OrderRequest{Symbol: "ETHUSDT", Side: "BUY", Type: "MARKET", Quantity: d("1")},
+			wantErr: "failed to resolve ETHUSDT",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sim := newBookExchange(t)
+			sim.balances["USDT"] = d("1000")
+
+			_, err := sim.PlaceOrder(context.Background(), tt.req)
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tt.wantErr)
+
+			assert.True(t, sim.Balance("USDT").Equal(d("1000")), "balances must be untouched")
+			assert.True(t, sim.Balance("BTC").Equal(d("2")), "balances must be untouched")
+		})
+	}
+}
+
+func TestSimulatedExchange_InsufficientBalanceMirrorsBinance(t *testing.T) {
+	sim := newBookExchange(t)
+	sim.balances["BTC"] = d("1")
+
+	// Depth covers the order but the account does not
+	_, err := sim.PlaceOrder(context.Background(), &rest.OrderRequest{
+		Symbol: "BTCUSDT", Side: "SELL", Type: "MARKET", Quantity: d("2"),
+	})
+
+	var apiErr *rest.BinanceError
+	require.ErrorAs(t, err, &apiErr)
+	assert.Equal(t, -2010, apiErr.Code)
+}
+
+func TestSimulatedExchange_UnsyncedDepthFallsBackToTicker(t *testing.T) {
+	sim := NewSimulatedExchange(
+		WithSymbolResolver(stubResolver{}),
+		WithBalances(map[string]decimal.Decimal{"USDT": d("1000")}),
+	)
+	// A book waiting on a resync holds stale levels that must not be filled against
+	sim.SetDepthSource("BTCUSDT", &stubDepth{asks: []websocket.PriceLevel{level("40000", "1")}})
+
+	_, err := sim.PlaceOrder(context.Background(), &rest.OrderRequest{
+		Symbol: "BTCUSDT", Side: "BUY", Type: "MARKET", Quantity: d("0.01"),
+	})
+	require.Error(t, err, "no ticker seen yet")
+
+	require.NoError(t, sim.HandleTicker(&websocket.TickerEvent{Symbol: "BTCUSDT", BidPrice: d("49999"), AskPrice: d("50001")}))
+	resp, err := sim.PlaceOrder(context.Background(), &rest.OrderRequest{
+		Symbol: "BTCUSDT", Side: "BUY", Type: "MARKET", Quantity: d("0.01"),
+	})
+	require.NoError(t, err)
+	require.Len(t, resp.Fills, 1)
+	assert.True(t, resp.Fills[0].Price.Equal(d("50001")))
+}
+
+func TestSimulatedExchange_LimitOrderRests(t *testing.T) {
+	sim := newBookExchange(t)
+
+	resp, err := sim.PlaceOrder(context.Background(), &rest.OrderRequest{
+		Symbol: "BTCUSDT", Side: "BUY", Type: "LIMIT", Quantity: d("1"), Price: d("49000"), TimeInForce: "GTC",
+	})
+	require.NoError(t, err)
+
+	assert.Equal(t, "NEW", resp.Status)
+	assert.True(t, resp.ExecutedQty.IsZero())
+	assert.True(t, sim.Balance("USDT").Equal(d("100000")))
+}
+
+func TestSimulatedExchange_CancelAndQueryOrders(t *testing.T) {
+	ctx := context.Background()
+	sim := newBookExchange(t)
+
+	limit, err := sim.PlaceOrder(ctx, &rest.OrderRequest{
+		Symbol: "BTCUSDT", Side: "SELL", Type: "LIMIT", Quantity: d("1"), Price: d("51000"), TimeInForce: "GTC", NewClientOrderID: "tp-1",
+	})
+	require.NoError(t, err)
+	_, err = sim.PlaceOrder(ctx, &rest.OrderRequest{
+		Symbol: "BTCUSDT", Side: "BUY", Type: "MARKET", Quantity: d("0.1"), NewClientOrderID: "entry-1",
+	})
+	require.NoError(t, err)
+
+	open, err := sim.GetOpenOrders(ctx, "BTCUSDT")
+	require.NoError(t, err)
+	require.Len(t, open, 1, "filled market orders are not open")
+	assert.Equal(t, "tp-1", open[0].ClientOrderID)
+
+	entry, err := sim.GetOrder(ctx, "BTCUSDT", "entry-1")
+	require.NoError(t, err)
+	assert.Equal(t, "FILLED", entry.Status)
+
+	require.NoError(t, sim.CancelOrder(ctx, "BTCUSDT", limit.OrderID))
+	all, err := sim.GetAllOpenOrders(ctx)
+	require.NoError(t, err)
+	assert.Empty(t, all)
+
+	tp, err := sim.GetOrder(ctx, "BTCUSDT", "tp-1")
+	require.NoError(t, err)
+	assert.Equal(t, "CANCELED", tp.Status)
+
+	var apiErr *rest.BinanceError
+	require.ErrorAs(t, sim.CancelOrder(ctx, "BTCUSDT", limit.OrderID), &apiErr)
+	assert.True(t, apiErr.IsUnknownOrder(), "orders are cancelled once")
+	_, err = sim.GetOrder(ctx, "BTCUSDT", "missing")
+	require.ErrorAs(t, err, &apiErr)
+	assert.True(t, apiErr.IsUnknownOrder())
+}
+
+func TestSimulatedExchange_OrderStoreForBinanceClient(t *testing.T) {
+	ctx := context.Background()
+	fake := testutil.NewFakeBinance(t)
+	fake.AddSymbol(testutil.BTCUSDT)
+
+	signer := auth.NewSigner("test-key", "test-secret")
+	restClient := rest.NewClient(fake.URL(), signer)
+	client, err := binance.NewClient(fake.URL(), signer, restClient, zerolog.Nop())
+	require.NoError(t, err)
+
+	// An order the user placed on the live account
+	_, err = restClient.PlaceOrder(ctx, &rest.OrderRequest{
+		Symbol: "BTCUSDT", Side: "BUY", Type: "LIMIT", Quantity: d("0.01"), Price: d("40000"), TimeInForce: "GTC", NewClientOrderID: "live-1",
+	})
+	require.NoError(t, err)
+
+	sim := NewSimulatedExchange(
+		WithSymbolResolver(client),
+		WithBalances(map[string]decimal.Decimal{"USDT": d("1000")}),
+	)
+	client.SetOrderPlacer(sim)
+	client.SetOrderStore(sim)
+
+	_, err = client.PlaceSpotOrder(ctx, binance.SpotOrderRequest{
+		Symbol: "BTCUSDT", Side: "BUY", Type: "LIMIT", Quantity: d("0.01"), Price: d("45000"), NewClientOrderID: "paper-1",
+	})
+	require.NoError(t, err)
+
+	open, err := client.GetOpenOrders(ctx, "BTCUSDT")
+	require.NoError(t, err)
+	require.Len(t, open, 1)
+	assert.Equal(t, "paper-1", open[0].ClientOrderID)
+
+	require.NoError(t, client.CancelOrder(ctx, "BTCUSDT", open[0].OrderID))
+	order, err := client.GetOrderByClientID(ctx, "BTCUSDT", "paper-1")
+	require.NoError(t, err)
+	assert.Equal(t, "CANCELED", order.Status)
+
+	require.Len(t, fake.Orders(), 1)
+	assert.Equal(t, "NEW", fake.Orders()[0].Status, "the live order is left alone")
+}
+
+func TestSimulatedExchange_DropInForBinanceClient(t *testing.T) {
+	fake := testutil.NewFakeBinance(t)
+	fake.AddSymbol(testutil.BTCUSDT)
+
+	signer := auth.NewSigner("test-key", "test-secret")
+	client, err := binance.NewClient(fake.URL(), signer, rest.NewClient(fake.URL(), signer), zerolog.Nop())
+	require.NoError(t, err)
+
+	sim := NewSimulatedExchange(
+		WithSymbolResolver(client),
+		WithBalances(map[string]decimal.Decimal{"USDT": d("1000")}),
+	)
+	require.NoError(t, sim.HandleTicker(&websocket.TickerEvent{Symbol: "BTCUSDT", BidPrice: d("49999"), AskPrice: d("50000")}))
+	client.SetOrderPlacer(sim)
+
+	resp, err := client.PlaceSpotOrder(context.Background(), binance.SpotOrderRequest{
+		Symbol:   "BTCUSDT",
+		Side:     "BUY",
+		Type:     "MARKET",
+		Quantity: d("0.01"),
+	})
+	require.NoError(t, err)
+
+	assert.Equal(t, "FILLED", resp.Status)
+	assert.Empty(t, fake.Orders(), "simulated orders must not reach the exchange")
+	assert.True(t, sim.Balance("USDT").Equal(d("500")))
+	assert.True(t, sim.Balance("BTC").Equal(d("0.01")))
+}
+
+func TestParseBalances(t *testing.T) {
+	tests := []struct {
+		name    string
+		pairs   []string
+		want    map[string]decimal.Decimal
+		wantErr string
+	}{
+		{
+			name:  "valid pairs",
+			pairs: []string{"USDT:10000", " btc:0.5"},
+			want:  map[string]decimal.Decimal{"USDT": d("10000"), "BTC": d("0.5")},
+		},
+		{name: "missing amount", pairs: []string{"USDT"}, wantErr: "want ASSET:AMOUNT"},
+		{name: "bad amount", pairs: []string{"USDT:lots"}, wantErr: "invalid balance"},
+		{name: "negative amount", pairs: []string{"USDT:-1"}, wantErr: "must not be negative"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseBalances(tt.pairs)
+			if tt.wantErr != "" {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			require.Len(t, got, len(tt.want))
+			for asset, amount := range tt.want {
+				assert.True(t, got[asset].Equal(amount), "%s: got %s", asset, got[asset])
+			}
+		})
+	}
+}
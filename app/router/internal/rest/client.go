@@ -4,18 +4,24 @@ import (
 	"bytes"
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"math"
+	"net"
 	"net/http"
 	"net/url"
+	"slices"
 	"strconv"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/shopspring/decimal"
+	"golang.org/x/sync/singleflight"
 
 	"router/internal/auth"
+	"router/internal/trace"
 )
 
 // Client represents a REST client for Binance API
@@ -25,6 +31,59 @@ type Client struct {
 	signer      *auth.Signer
 	rateLimiter *RateLimiter
 	maxRetries  int
+
+	// maxRetryDuration caps the wall-clock time spent retrying; zero means
+	// only maxRetries applies
+	maxRetryDuration time.Duration
+
+	metrics DurationRecorder
+
+	// strictDecimals turns malformed decimals in responses into errors
+	// instead of zeros
+	strictDecimals bool
+
+	// exchangeInfoTTL is how long GetExchangeInfo serves its cached copy;
+	// zero disables caching
+	exchangeInfoTTL  time.Duration
+	exchangeInfoMu   sync.RWMutex // guards the cached copy, never held across a fetch
+	exchangeInfo     *ExchangeInfo
+	exchangeInfoTime time.Time
+	// Concurrent exchange info fetches share one request
+	exchangeInfoFetches singleflight.Group
+}
+
+// DefaultExchangeInfoTTL is how long GetExchangeInfo reuses a fetched copy.
+// Symbol rules rarely change intra-day and the full payload is heavy on
+// request weight.
+const DefaultExchangeInfoTTL = time.Hour
+
+// Connection pool defaults for NewTransport. Every request goes to one
+// Binance host, so the per-host idle limit is what bounds how many
+// concurrent requests can reuse connections instead of dialing.
+const (
+	DefaultMaxIdleConnsPerHost = 64
+	DefaultIdleConnTimeout     = 90 * time.Second
+	DefaultKeepAlive           = 30 * time.Second
+)
+
+// NewTransport returns the HTTP transport NewClient uses by default. Go's
+// default transport keeps only two idle connections per host, so bursts of
+// concurrent orders would mostly pay for fresh TCP and TLS handshakes.
+func NewTransport() *http.Transport {
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.DialContext = (&net.Dialer{
+		Timeout:   10 * time.Second,
+		KeepAlive: DefaultKeepAlive,
+	}).DialContext
+	transport.MaxIdleConns = DefaultMaxIdleConnsPerHost
+	transport.MaxIdleConnsPerHost = DefaultMaxIdleConnsPerHost
+	transport.IdleConnTimeout = DefaultIdleConnTimeout
+	return transport
+}
+
+// DurationRecorder receives per-request timings; metrics.Collector satisfies it
+type DurationRecorder interface {
+	RecordHTTPDuration(method, endpoint string, duration float64)
 }
 
 // Option configures the client
@@ -37,6 +96,23 @@ func WithTimeout(timeout time.Duration) Option {
 	}
 }
 
+// WithTransport replaces the client's HTTP transport, for tuning the
+// connection pool beyond NewTransport's defaults or routing through a proxy
+func WithTransport(transport *http.Transport) Option {
+	return func(c *Client) {
+		c.httpClient.Transport = transport
+	}
+}
+
+// WithStrictDecimals makes malformed decimal fields in responses an error
+// naming the field, rather than reading them as zero. It catches upstream
+// format changes before they turn into zero prices.
+func WithStrictDecimals(strict bool) Option {
+	return func(c *Client) {
+		c.strictDecimals = strict
+	}
+}
+
 // WithMaxRetries sets the maximum number of retries
 func WithMaxRetries(maxRetries int) Option {
 	return func(c *Client) {
@@ -44,6 +120,31 @@ func WithMaxRetries(maxRetries int) Option {
 	}
 }
 
+// WithMaxRetryDuration bounds the total time a request may spend retrying.
+// A retry whose backoff would overrun the budget is skipped and the last
+// error returned.
+func WithMaxRetryDuration(d time.Duration) Option {
+	return func(c *Client) {
+		c.maxRetryDuration = d
+	}
+}
+
+// WithMetrics records the duration of every Binance request, labelled by
+// endpoint path
+func WithMetrics(recorder DurationRecorder) Option {
+	return func(c *Client) {
+		c.metrics = recorder
+	}
+}
+
+// WithExchangeInfoTTL sets how long GetExchangeInfo reuses a fetched copy.
+// Zero disables caching so every call hits Binance.
+func WithExchangeInfoTTL(ttl time.Duration) Option {
+	return func(c *Client) {
+		c.exchangeInfoTTL = ttl
+	}
+}
+
 // WithRateLimit sets rate limiting
 func WithRateLimit(requestsPerSecond float64, burst int) Option {
 	return func(c *Client) {
@@ -56,11 +157,13 @@ func NewClient(baseURL string, signer *auth.Signer, opts ...Option) *Client {
 	client := &Client{
 		baseURL: baseURL,
 		httpClient: &http.Client{
-			Timeout: 5 * time.Second,
+			Timeout:   5 * time.Second,
+			Transport: NewTransport(),
 		},
-		signer:      signer,
-		rateLimiter: NewRateLimiter(10, 5), // Default: 10 req/sec, burst 5
-		maxRetries:  3,
+		signer:          signer,
+		rateLimiter:     NewRateLimiter(10, 5), // Default: 10 req/sec, burst 5
+		maxRetries:      3,
+		exchangeInfoTTL: DefaultExchangeInfoTTL,
 	}
 
 	for _, opt := range opts {
@@ -85,47 +188,161 @@ func (c *Client) MaxRetries() int {
 	return c.maxRetries
 }
 
-// GetExchangeInfo fetches trading rules and symbol information
+// RateLimiter returns the client's request rate limiter
+func (c *Client) RateLimiter() *RateLimiter {
+	return c.rateLimiter
+}
+
+// Ping tests connectivity to the spot REST API
+func (c *Client) Ping(ctx context.Context) error {
+	if _, err := c.doRequest(ctx, "GET", "/api/v3/ping", nil, false); err != nil {
+		return ErrorWithContext(err, "Ping")
+	}
+	return nil
+}
+
+// PingFutures tests connectivity to the USDT-M futures REST API
+func (c *Client) PingFutures(ctx context.Context) error {
+	if _, err := c.doRequest(ctx, "GET", "/fapi/v1/ping", nil, false); err != nil {
+		return ErrorWithContext(err, "PingFutures")
+	}
+	return nil
+}
+
+// GetExchangeInfo fetches trading rules and symbol information. The result is
+// cached for the client's exchange info TTL and shared between callers, so it
+// must not be modified.
 func (c *Client) GetExchangeInfo(ctx context.Context) (*ExchangeInfo, error) {
-	body, err := c.doRequest(ctx, "GET", "/api/v3/exchangeInfo", nil, false)
+	if c.exchangeInfoTTL <= 0 {
+		return c.fetchExchangeInfo(ctx)
+	}
+
+	c.exchangeInfoMu.RLock()
+	info, fetchedAt := c.exchangeInfo, c.exchangeInfoTime
+	c.exchangeInfoMu.RUnlock()
+
+	if info != nil && time.Since(fetchedAt) < c.exchangeInfoTTL {
+		return info, nil
+	}
+	return c.sharedExchangeInfo(ctx)
+}
+
+// ForceRefreshExchangeInfo fetches exchange info regardless of cache age and
+// replaces the cached copy. The previous copy is kept if the fetch fails.
+func (c *Client) ForceRefreshExchangeInfo(ctx context.Context) (*ExchangeInfo, error) {
+	return c.sharedExchangeInfo(ctx)
+}
+
+// sharedExchangeInfo joins the in-flight exchange info fetch or starts one.
+// The fetch outlives any one caller's context, so each caller only waits for
+// as long as its own context allows.
+func (c *Client) sharedExchangeInfo(ctx context.Context) (*ExchangeInfo, error) {
+	results := c.exchangeInfoFetches.DoChan("exchangeInfo", func() (interface{}, error) {
+		return c.fetchExchangeInfo(context.WithoutCancel(ctx))
+	})
+
+	select {
+	case <-ctx.Done():
+		return nil, ErrorWithContext(ctx.Err(), "GetExchangeInfo")
+	case result := <-results:
+		if result.Err != nil {
+			return nil, result.Err
+		}
+		return result.Val.(*ExchangeInfo), nil
+	}
+}
+
+// fetchExchangeInfo loads exchange info and, when caching is enabled, swaps
+// it in as the cached copy
+func (c *Client) fetchExchangeInfo(ctx context.Context) (*ExchangeInfo, error) {
+	var exchangeInfo ExchangeInfo
+	err := c.doStream(ctx, "GET", "/api/v3/exchangeInfo", nil, false, func(r io.Reader) error {
+		// Start clean in case a retried attempt left a partial decode
+		exchangeInfo = ExchangeInfo{}
+		return decodeExchangeInfo(r, &exchangeInfo)
+	})
 	if err != nil {
 		return nil, ErrorWithContext(err, "GetExchangeInfo")
 	}
 
+	if c.exchangeInfoTTL > 0 {
+		c.exchangeInfoMu.Lock()
+		c.exchangeInfo = &exchangeInfo
+		c.exchangeInfoTime = time.Now()
+		c.exchangeInfoMu.Unlock()
+	}
+	return &exchangeInfo, nil
+}
+
+// decodeExchangeInfo decodes an exchangeInfo payload straight from the
+// response body so the multi-megabyte mainnet response is never buffered
+func decodeExchangeInfo(r io.Reader, info *ExchangeInfo) error {
+	return json.NewDecoder(r).Decode(info)
+}
+
+// GetExchangeInfoForSymbol fetches trading rules for a single symbol, which
+// carries a fraction of the request weight of a full exchangeInfo call
+func (c *Client) GetExchangeInfoForSymbol(ctx context.Context, symbol string) (*Symbol, error) {
+	if symbol == "" {
+		return nil, fmt.Errorf("symbol is required")
+	}
+
+	params := url.Values{}
+	params.Set("symbol", symbol)
+
+	body, err := c.doRequest(ctx, "GET", "/api/v3/exchangeInfo", params, false)
+	if err != nil {
+		return nil, ErrorWithContext(err, "GetExchangeInfoForSymbol")
+	}
+
 	var exchangeInfo ExchangeInfo
 	if err := json.Unmarshal(body, &exchangeInfo); err != nil {
-		return nil, ErrorWithContext(err, "GetExchangeInfo")
+		return nil, ErrorWithContext(err, "GetExchangeInfoForSymbol")
 	}
 
-	return &exchangeInfo, nil
+	for i := range exchangeInfo.Symbols {
+		if exchangeInfo.Symbols[i].Symbol == symbol {
+			return &exchangeInfo.Symbols[i], nil
+		}
+	}
+
+	return nil, fmt.Errorf("symbol %s not found in exchange info", symbol)
 }
 
 // GetOrderBook retrieves order book depth for a symbol
 func (c *Client) GetOrderBook(ctx context.Context, symbol string, limit int) (*OrderBook, error) {
+	return c.getOrderBook(ctx, "/api/v3/depth", "GetOrderBook", symbol, limit, []int{5, 10, 20, 50, 100, 500, 1000, 5000})
+}
+
+// GetFuturesOrderBook retrieves futures order book depth for a symbol
+func (c *Client) GetFuturesOrderBook(ctx context.Context, symbol string, limit int) (*OrderBook, error) {
+	return c.getOrderBook(ctx, "/fapi/v1/depth", "GetFuturesOrderBook", symbol, limit, []int{5, 10, 20, 50, 100, 500, 1000})
+}
+
+// getOrderBook fetches a depth snapshot from path, accepting only the
+// endpoint's validLimits
+func (c *Client) getOrderBook(ctx context.Context, path, operation, symbol string, limit int, validLimits []int) (*OrderBook, error) {
 	if symbol == "" {
 		return nil, fmt.Errorf("symbol is required")
 	}
 
 	// Validate limit parameter
-	validLimits := []int{5, 10, 20, 50, 100, 500, 1000, 5000}
-	validLimit := false
-	for _, vl := range validLimits {
-		if limit == vl {
-			validLimit = true
-			break
+	if !slices.Contains(validLimits, limit) {
+		names := make([]string, len(validLimits))
+		for i, vl := range validLimits {
+			names[i] = strconv.Itoa(vl)
 		}
+		return nil, fmt.Errorf("invalid limit: %d. Valid limits are: %s", limit, strings.Join(names, ", "))
 	}
-	if !validLimit {
-		return nil, fmt.Errorf("invalid limit: %d. Valid limits are: 5, 10, 20, 50, 100, 500, 1000, 5000", limit)
-	}
+	symbol = normalizeSymbol(symbol)
 
 	params := url.Values{}
 	params.Set("symbol", symbol)
 	params.Set("limit", strconv.Itoa(limit))
 
-	body, err := c.doRequest(ctx, "GET", "/api/v3/depth", params, false)
+	body, err := c.doRequest(ctx, "GET", path, params, false)
 	if err != nil {
-		return nil, ErrorWithContext(err, "GetOrderBook")
+		return nil, ErrorWithContext(err, operation)
 	}
 
 	var rawOrderBook struct {
@@ -135,29 +352,54 @@ func (c *Client) GetOrderBook(ctx context.Context, symbol string, limit int) (*O
 	}
 
 	if err := json.Unmarshal(body, &rawOrderBook); err != nil {
-		return nil, ErrorWithContext(err, "GetOrderBook")
+		return nil, ErrorWithContext(err, operation)
 	}
 
 	// Convert string arrays to PriceLevel structs
-	orderBook := &OrderBook{
-		LastUpdateID: rawOrderBook.LastUpdateID,
-		Bids:         make([]PriceLevel, len(rawOrderBook.Bids)),
-		Asks:         make([]PriceLevel, len(rawOrderBook.Asks)),
+	bids, err := c.parsePriceLevels(symbol, "bid", rawOrderBook.Bids)
+	if err != nil {
+		return nil, ErrorWithContext(err, operation)
 	}
-
-	for i, bid := range rawOrderBook.Bids {
-		price, _ := decimal.NewFromString(bid[0])
-		quantity, _ := decimal.NewFromString(bid[1])
-		orderBook.Bids[i] = PriceLevel{Price: price, Quantity: quantity}
+	asks, err := c.parsePriceLevels(symbol, "ask", rawOrderBook.Asks)
+	if err != nil {
+		return nil, ErrorWithContext(err, operation)
 	}
 
-	for i, ask := range rawOrderBook.Asks {
-		price, _ := decimal.NewFromString(ask[0])
-		quantity, _ := decimal.NewFromString(ask[1])
-		orderBook.Asks[i] = PriceLevel{Price: price, Quantity: quantity}
-	}
+	return &OrderBook{
+		LastUpdateID: rawOrderBook.LastUpdateID,
+		Bids:         bids,
+		Asks:         asks,
+	}, nil
+}
+
+// StrictDecimals reports whether malformed decimals in responses are errors
+func (c *Client) StrictDecimals() bool {
+	return c.strictDecimals
+}
+
+// parsePriceLevels converts Binance [price, quantity] pairs. Malformed levels
+// read as zero unless the client is strict.
+func (c *Client) parsePriceLevels(symbol, side string, raw [][]string) ([]PriceLevel, error) {
+	levels := make([]PriceLevel, len(raw))
+	for i, level := range raw {
+		if len(level) < 2 {
+			if c.strictDecimals {
+				return nil, fmt.Errorf("%s %s level %d: expected price and quantity, got %d fields", symbol, side, i, len(level))
+			}
+			continue
+		}
 
-	return orderBook, nil
+		price, err := decimal.NewFromString(level[0])
+		if err != nil && c.strictDecimals {
+			return nil, fmt.Errorf("%s %s level %d: invalid price %q: %w", symbol, side, i, level[0], err)
+		}
+		quantity, err := decimal.NewFromString(level[1])
+		if err != nil && c.strictDecimals {
+			return nil, fmt.Errorf("%s %s level %d: invalid quantity %q: %w", symbol, side, i, level[1], err)
+		}
+		levels[i] = PriceLevel{Price: price, Quantity: quantity}
+	}
+	return levels, nil
 }
 
 // GetAccount gets current account information
@@ -184,64 +426,95 @@ func (c *Client) PlaceOrder(ctx context.Context, req *OrderRequest) (*OrderRespo
 	if c.signer == nil {
 		return nil, fmt.Errorf("signer required for PlaceOrder")
 	}
+	if err := req.Validate(); err != nil {
+		return nil, err
+	}
 
-	// Validate required fields
-	if req.Symbol == "" {
-		return nil, fmt.Errorf("symbol is required")
+	body, err := c.doRequest(ctx, "POST", "/api/v3/order", req.Params(), true)
+	if err != nil {
+		return nil, ErrorWithContext(err, "PlaceOrder")
 	}
-	if req.Side == "" {
-		return nil, fmt.Errorf("side is required")
+
+	var orderResp OrderResponse
+	if err := json.Unmarshal(body, &orderResp); err != nil {
+		return nil, ErrorWithContext(err, "PlaceOrder")
 	}
-	if req.Type == "" {
-		return nil, fmt.Errorf("type is required")
+
+	return &orderResp, nil
+}
+
+// Validate checks that the order carries the fields Binance requires for its type
+func (r *OrderRequest) Validate() error {
+	if r.Symbol == "" {
+		return fmt.Errorf("symbol is required")
 	}
-	if req.Quantity.IsZero() {
-		return nil, fmt.Errorf("quantity is required")
+	if r.Side == "" {
+		return fmt.Errorf("side is required")
 	}
-	if req.Type == "LIMIT" && req.Price.IsZero() {
-		return nil, fmt.Errorf("price is required for LIMIT orders")
+	if r.Type == "" {
+		return fmt.Errorf("type is required")
 	}
-	if strings.Contains(req.Type, "STOP") && req.StopPrice.IsZero() {
-		return nil, fmt.Errorf("stopPrice is required for STOP orders")
+	if r.Type == "MARKET" {
+		if r.Quantity.IsZero() == r.QuoteOrderQty.IsZero() {
+			return fmt.Errorf("exactly one of quantity or quoteOrderQty is required for MARKET orders")
+		}
+	} else {
+		if r.Quantity.IsZero() {
+			return fmt.Errorf("quantity is required")
+		}
+		if !r.QuoteOrderQty.IsZero() {
+			return fmt.Errorf("quoteOrderQty is only supported for MARKET orders")
+		}
+	}
+	if r.Type == "LIMIT" && r.Price.IsZero() {
+		return fmt.Errorf("price is required for LIMIT orders")
 	}
-	if (req.Type == "STOP_LOSS_LIMIT" || req.Type == "TAKE_PROFIT_LIMIT") && req.Price.IsZero() {
-		return nil, fmt.Errorf("price is required for %s orders", req.Type)
+	if strings.Contains(r.Type, "STOP") && r.StopPrice.IsZero() {
+		return fmt.Errorf("stopPrice is required for STOP orders")
+	}
+	if (r.Type == "STOP_LOSS_LIMIT" || r.Type == "TAKE_PROFIT_LIMIT") && r.Price.IsZero() {
+		return fmt.Errorf("price is required for %s orders", r.Type)
+	}
+	if err := validateSTPMode(r.SelfTradePreventionMode); err != nil {
+		return err
 	}
 
-	// Build parameters
+	return nil
+}
+
+// Params builds the unsigned request parameters for the order
+func (r *OrderRequest) Params() url.Values {
 	params := url.Values{}
-	params.Set("symbol", req.Symbol)
-	params.Set("side", req.Side)
-	params.Set("type", req.Type)
-	params.Set("quantity", req.Quantity.String())
+	params.Set("symbol", normalizeSymbol(r.Symbol))
+	params.Set("side", r.Side)
+	params.Set("type", r.Type)
 
-	if !req.Price.IsZero() {
-		params.Set("price", req.Price.String())
+	if !r.Quantity.IsZero() {
+		params.Set("quantity", r.Quantity.String())
 	}
-	if !req.StopPrice.IsZero() {
-		params.Set("stopPrice", req.StopPrice.String())
+	if !r.QuoteOrderQty.IsZero() {
+		params.Set("quoteOrderQty", r.QuoteOrderQty.String())
 	}
-	if req.TimeInForce != "" {
-		params.Set("timeInForce", req.TimeInForce)
+	if !r.Price.IsZero() {
+		params.Set("price", r.Price.String())
 	}
-	if req.NewClientOrderID != "" {
-		params.Set("newClientOrderId", req.NewClientOrderID)
+	if !r.StopPrice.IsZero() {
+		params.Set("stopPrice", r.StopPrice.String())
 	}
-	if req.RecvWindow > 0 {
-		params.Set("recvWindow", strconv.FormatInt(req.RecvWindow, 10))
+	if r.TimeInForce != "" {
+		params.Set("timeInForce", r.TimeInForce)
 	}
-
-	body, err := c.doRequest(ctx, "POST", "/api/v3/order", params, true)
-	if err != nil {
-		return nil, ErrorWithContext(err, "PlaceOrder")
+	if r.NewClientOrderID != "" {
+		params.Set("newClientOrderId", r.NewClientOrderID)
 	}
-
-	var orderResp OrderResponse
-	if err := json.Unmarshal(body, &orderResp); err != nil {
-		return nil, ErrorWithContext(err, "PlaceOrder")
+	if r.RecvWindow > 0 {
+		params.Set("recvWindow", strconv.FormatInt(r.RecvWindow, 10))
+	}
+	if r.SelfTradePreventionMode != "" {
+		params.Set("selfTradePreventionMode", r.SelfTradePreventionMode)
 	}
 
-	return &orderResp, nil
+	return params
 }
 
 // CancelOrder cancels an active order
@@ -257,7 +530,7 @@ func (c *Client) CancelOrder(ctx context.Context, symbol string, orderID int64)
 	}
 
 	params := url.Values{}
-	params.Set("symbol", symbol)
+	params.Set("symbol", normalizeSymbol(symbol))
 	params.Set("orderId", strconv.FormatInt(orderID, 10))
 
 	_, err := c.doRequest(ctx, "DELETE", "/api/v3/order", params, true)
@@ -268,6 +541,130 @@ func (c *Client) CancelOrder(ctx context.Context, symbol string, orderID int64)
 	return nil
 }
 
+// GetOrder looks up a spot order by the client order ID it was placed with
+func (c *Client) GetOrder(ctx context.Context, symbol, clientOrderID string) (*Order, error) {
+	if c.signer == nil {
+		return nil, fmt.Errorf("signer required for GetOrder")
+	}
+	if symbol == "" {
+		return nil, fmt.Errorf("symbol is required")
+	}
+	if clientOrderID == "" {
+		return nil, fmt.Errorf("client order ID is required")
+	}
+
+	params := url.Values{}
+	params.Set("symbol", normalizeSymbol(symbol))
+	params.Set("origClientOrderId", clientOrderID)
+
+	body, err := c.doRequest(ctx, "GET", "/api/v3/order", params, true)
+	if err != nil {
+		return nil, ErrorWithContext(err, "GetOrder")
+	}
+
+	var order Order
+	if err := json.Unmarshal(body, &order); err != nil {
+		return nil, ErrorWithContext(err, "GetOrder")
+	}
+
+	return &order, nil
+}
+
+// CancelReplaceOrder atomically cancels an existing spot order and places a
+// new one. When either leg fails Binance rejects the request, but the legs'
+// outcomes are still returned alongside the error so a partial success (new
+// order placed, or old order cancelled) is visible to the caller.
+func (c *Client) CancelReplaceOrder(ctx context.Context, req *CancelReplaceRequest) (*CancelReplaceResponse, error) {
+	if c.signer == nil {
+		return nil, fmt.Errorf("signer required for CancelReplaceOrder")
+	}
+	switch req.CancelReplaceMode {
+	case CancelReplaceStopOnFailure, CancelReplaceAllowFailure:
+	default:
+		return nil, fmt.Errorf("invalid cancelReplaceMode %q: must be %s or %s",
+			req.CancelReplaceMode, CancelReplaceStopOnFailure, CancelReplaceAllowFailure)
+	}
+	if req.CancelOrderID <= 0 && req.CancelOrigClientOrderID == "" {
+		return nil, fmt.Errorf("cancelOrderId or cancelOrigClientOrderId is required")
+	}
+	if err := req.NewOrder.Validate(); err != nil {
+		return nil, err
+	}
+
+	params := req.NewOrder.Params()
+	params.Set("cancelReplaceMode", req.CancelReplaceMode)
+	if req.CancelOrderID > 0 {
+		params.Set("cancelOrderId", strconv.FormatInt(req.CancelOrderID, 10))
+	}
+	if req.CancelOrigClientOrderID != "" {
+		params.Set("cancelOrigClientOrderId", req.CancelOrigClientOrderID)
+	}
+
+	body, err := c.doRequest(ctx, "POST", "/api/v3/order/cancelReplace", params, true)
+	if err != nil {
+		var apiErr *BinanceError
+		if errors.As(err, &apiErr) && len(apiErr.Data) > 0 {
+			if resp, parseErr := parseCancelReplaceResponse(apiErr.Data); parseErr == nil {
+				return resp, ErrorWithContext(err, "CancelReplaceOrder")
+			}
+		}
+		return nil, ErrorWithContext(err, "CancelReplaceOrder")
+	}
+
+	resp, err := parseCancelReplaceResponse(body)
+	if err != nil {
+		return nil, ErrorWithContext(err, "CancelReplaceOrder")
+	}
+	return resp, nil
+}
+
+// parseCancelReplaceResponse decodes each leg as a response or an error
+// according to its reported result
+func parseCancelReplaceResponse(data []byte) (*CancelReplaceResponse, error) {
+	var raw struct {
+		CancelResult     string          `json:"cancelResult"`
+		NewOrderResult   string          `json:"newOrderResult"`
+		CancelResponse   json.RawMessage `json:"cancelResponse"`
+		NewOrderResponse json.RawMessage `json:"newOrderResponse"`
+	}
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return nil, err
+	}
+
+	resp := &CancelReplaceResponse{
+		CancelResult:   raw.CancelResult,
+		NewOrderResult: raw.NewOrderResult,
+	}
+
+	switch raw.CancelResult {
+	case CancelReplaceSuccess:
+		resp.CancelResponse = &Order{}
+		if err := json.Unmarshal(raw.CancelResponse, resp.CancelResponse); err != nil {
+			return nil, fmt.Errorf("failed to parse cancel response: %w", err)
+		}
+	case CancelReplaceFailure:
+		resp.CancelError = &BinanceError{}
+		if err := json.Unmarshal(raw.CancelResponse, resp.CancelError); err != nil {
+			return nil, fmt.Errorf("failed to parse cancel error: %w", err)
+		}
+	}
+
+	switch raw.NewOrderResult {
+	case CancelReplaceSuccess:
+		resp.NewOrderResponse = &OrderResponse{}
+		if err := json.Unmarshal(raw.NewOrderResponse, resp.NewOrderResponse); err != nil {
+			return nil, fmt.Errorf("failed to parse new order response: %w", err)
+		}
+	case CancelReplaceFailure:
+		resp.NewOrderError = &BinanceError{}
+		if err := json.Unmarshal(raw.NewOrderResponse, resp.NewOrderError); err != nil {
+			return nil, fmt.Errorf("failed to parse new order error: %w", err)
+		}
+	}
+
+	return resp, nil
+}
+
 // GetTicker24hr retrieves 24 hour ticker statistics for a symbol
 func (c *Client) GetTicker24hr(ctx context.Context, symbol string) (*Ticker24hr, error) {
 	params := url.Values{}
@@ -286,6 +683,123 @@ func (c *Client) GetTicker24hr(ctx context.Context, symbol string) (*Ticker24hr,
 	return &ticker, nil
 }
 
+// GetAvgPrice retrieves the current weighted average price for a spot symbol
+func (c *Client) GetAvgPrice(ctx context.Context, symbol string) (*AvgPrice, error) {
+	if symbol == "" {
+		return nil, fmt.Errorf("symbol is required")
+	}
+
+	params := url.Values{}
+	params.Set("symbol", symbol)
+
+	body, err := c.doRequest(ctx, "GET", "/api/v3/avgPrice", params, false)
+	if err != nil {
+		return nil, ErrorWithContext(err, "GetAvgPrice")
+	}
+
+	var avgPrice AvgPrice
+	if err := json.Unmarshal(body, &avgPrice); err != nil {
+		return nil, ErrorWithContext(err, "GetAvgPrice")
+	}
+
+	return &avgPrice, nil
+}
+
+// GetRecentTrades retrieves the most recent public trades for a symbol.
+// A zero limit uses Binance's default of 500.
+func (c *Client) GetRecentTrades(ctx context.Context, symbol string, limit int) ([]Trade, error) {
+	if symbol == "" {
+		return nil, fmt.Errorf("symbol is required")
+	}
+	if limit < 0 || limit > MaxTradesLimit {
+		return nil, fmt.Errorf("invalid limit: %d. Limit must be between 1 and %d", limit, MaxTradesLimit)
+	}
+
+	params := url.Values{}
+	params.Set("symbol", symbol)
+	if limit > 0 {
+		params.Set("limit", strconv.Itoa(limit))
+	}
+
+	body, err := c.doRequest(ctx, "GET", "/api/v3/trades", params, false)
+	if err != nil {
+		return nil, ErrorWithContext(err, "GetRecentTrades")
+	}
+
+	var trades []Trade
+	if err := json.Unmarshal(body, &trades); err != nil {
+		return nil, ErrorWithContext(err, "GetRecentTrades")
+	}
+
+	return trades, nil
+}
+
+// GetAggTrades retrieves compressed trades for a symbol, for backfilling
+// history before subscribing to the live aggTrade stream
+func (c *Client) GetAggTrades(ctx context.Context, symbol string, opts AggTradeOptions) ([]AggTrade, error) {
+	if symbol == "" {
+		return nil, fmt.Errorf("symbol is required")
+	}
+	if opts.Limit < 0 || opts.Limit > MaxTradesLimit {
+		return nil, fmt.Errorf("invalid limit: %d. Limit must be between 1 and %d", opts.Limit, MaxTradesLimit)
+	}
+	hasStart, hasEnd := !opts.StartTime.IsZero(), !opts.EndTime.IsZero()
+	if opts.FromID != 0 && (hasStart || hasEnd) {
+		return nil, fmt.Errorf("fromId cannot be combined with startTime or endTime")
+	}
+	if hasStart && hasEnd {
+		if opts.EndTime.Before(opts.StartTime) {
+			return nil, fmt.Errorf("endTime %s is before startTime %s", opts.EndTime, opts.StartTime)
+		}
+		if opts.EndTime.Sub(opts.StartTime) > time.Hour {
+			return nil, fmt.Errorf("startTime and endTime must be at most an hour apart")
+		}
+	}
+
+	params := url.Values{}
+	params.Set("symbol", symbol)
+	if opts.FromID != 0 {
+		params.Set("fromId", strconv.FormatInt(opts.FromID, 10))
+	}
+	if hasStart {
+		params.Set("startTime", strconv.FormatInt(opts.StartTime.UnixMilli(), 10))
+	}
+	if hasEnd {
+		params.Set("endTime", strconv.FormatInt(opts.EndTime.UnixMilli(), 10))
+	}
+	if opts.Limit > 0 {
+		params.Set("limit", strconv.Itoa(opts.Limit))
+	}
+
+	body, err := c.doRequest(ctx, "GET", "/api/v3/aggTrades", params, false)
+	if err != nil {
+		return nil, ErrorWithContext(err, "GetAggTrades")
+	}
+
+	var trades []AggTrade
+	if err := json.Unmarshal(body, &trades); err != nil {
+		return nil, ErrorWithContext(err, "GetAggTrades")
+	}
+
+	return trades, nil
+}
+
+// GetAllTickers24hr retrieves 24 hour ticker statistics for every symbol.
+// Binance weights this call far heavier than the single-symbol variant.
+func (c *Client) GetAllTickers24hr(ctx context.Context) ([]Ticker24hr, error) {
+	body, err := c.doRequest(ctx, "GET", "/api/v3/ticker/24hr", nil, false)
+	if err != nil {
+		return nil, ErrorWithContext(err, "GetAllTickers24hr")
+	}
+
+	var tickers []Ticker24hr
+	if err := json.Unmarshal(body, &tickers); err != nil {
+		return nil, ErrorWithContext(err, "GetAllTickers24hr")
+	}
+
+	return tickers, nil
+}
+
 // GetOpenOrders lists all open orders for a symbol
 func (c *Client) GetOpenOrders(ctx context.Context, symbol string) ([]Order, error) {
 	if c.signer == nil {
@@ -296,7 +810,7 @@ func (c *Client) GetOpenOrders(ctx context.Context, symbol string) ([]Order, err
 	}
 
 	params := url.Values{}
-	params.Set("symbol", symbol)
+	params.Set("symbol", normalizeSymbol(symbol))
 
 	body, err := c.doRequest(ctx, "GET", "/api/v3/openOrders", params, true)
 	if err != nil {
@@ -311,6 +825,51 @@ func (c *Client) GetOpenOrders(ctx context.Context, symbol string) ([]Order, err
 	return orders, nil
 }
 
+// GetAllOpenOrders lists open spot orders across every symbol. Binance
+// weights this call far heavier than the single-symbol variant.
+func (c *Client) GetAllOpenOrders(ctx context.Context) ([]Order, error) {
+	if c.signer == nil {
+		return nil, fmt.Errorf("signer required for GetAllOpenOrders")
+	}
+
+	body, err := c.doRequest(ctx, "GET", "/api/v3/openOrders", nil, true)
+	if err != nil {
+		return nil, ErrorWithContext(err, "GetAllOpenOrders")
+	}
+
+	var orders []Order
+	if err := json.Unmarshal(body, &orders); err != nil {
+		return nil, ErrorWithContext(err, "GetAllOpenOrders")
+	}
+
+	return orders, nil
+}
+
+// GetFuturesOpenOrders lists open futures orders for a symbol, or across
+// every symbol at weight 40 when symbol is empty
+func (c *Client) GetFuturesOpenOrders(ctx context.Context, symbol string) ([]Order, error) {
+	if c.signer == nil {
+		return nil, fmt.Errorf("signer required for GetFuturesOpenOrders")
+	}
+
+	params := url.Values{}
+	if symbol != "" {
+		params.Set("symbol", normalizeSymbol(symbol))
+	}
+
+	body, err := c.doRequest(ctx, "GET", "/fapi/v1/openOrders", params, true)
+	if err != nil {
+		return nil, ErrorWithContext(err, "GetFuturesOpenOrders")
+	}
+
+	var orders []Order
+	if err := json.Unmarshal(body, &orders); err != nil {
+		return nil, ErrorWithContext(err, "GetFuturesOpenOrders")
+	}
+
+	return orders, nil
+}
+
 // PlaceFuturesOrder places a futures order
 func (c *Client) PlaceFuturesOrder(ctx context.Context, req *FuturesOrderRequest) (*FuturesOrderResponse, error) {
 	if c.signer == nil {
@@ -333,13 +892,19 @@ func (c *Client) PlaceFuturesOrder(ctx context.Context, req *FuturesOrderRequest
 	if req.Type == "LIMIT" && req.Price.IsZero() {
 		return nil, fmt.Errorf("price is required for LIMIT orders")
 	}
-	if strings.Contains(req.Type, "STOP") && req.StopPrice.IsZero() {
-		return nil, fmt.Errorf("stopPrice is required for STOP orders")
+	if futuresStopTypes[req.Type] && req.StopPrice.IsZero() {
+		return nil, fmt.Errorf("stopPrice is required for %s orders", req.Type)
+	}
+	if req.Type == "TRAILING_STOP_MARKET" && req.CallbackRate.IsZero() {
+		return nil, fmt.Errorf("callbackRate is required for TRAILING_STOP_MARKET orders")
+	}
+	if err := validateSTPMode(req.SelfTradePreventionMode); err != nil {
+		return nil, err
 	}
 
 	// Build parameters
 	params := url.Values{}
-	params.Set("symbol", req.Symbol)
+	params.Set("symbol", normalizeSymbol(req.Symbol))
 	params.Set("side", req.Side)
 	params.Set("type", req.Type)
 
@@ -373,12 +938,18 @@ func (c *Client) PlaceFuturesOrder(ctx context.Context, req *FuturesOrderRequest
 	if req.PriceProtect {
 		params.Set("priceProtect", "true")
 	}
+	if req.PositionSide != "" {
+		params.Set("positionSide", req.PositionSide)
+	}
 	if req.NewClientOrderID != "" {
 		params.Set("newClientOrderId", req.NewClientOrderID)
 	}
 	if req.RecvWindow > 0 {
 		params.Set("recvWindow", strconv.FormatInt(req.RecvWindow, 10))
 	}
+	if req.SelfTradePreventionMode != "" {
+		params.Set("selfTradePreventionMode", req.SelfTradePreventionMode)
+	}
 
 	body, err := c.doRequest(ctx, "POST", "/fapi/v1/order", params, true)
 	if err != nil {
@@ -393,6 +964,176 @@ func (c *Client) PlaceFuturesOrder(ctx context.Context, req *FuturesOrderRequest
 	return &orderResp, nil
 }
 
+// GetFuturesOrder looks up a futures order by the client order ID it was
+// placed with
+func (c *Client) GetFuturesOrder(ctx context.Context, symbol, clientOrderID string) (*FuturesOrderResponse, error) {
+	if c.signer == nil {
+		return nil, fmt.Errorf("signer required for GetFuturesOrder")
+	}
+	if symbol == "" {
+		return nil, fmt.Errorf("symbol is required")
+	}
+	if clientOrderID == "" {
+		return nil, fmt.Errorf("client order ID is required")
+	}
+
+	params := url.Values{}
+	params.Set("symbol", normalizeSymbol(symbol))
+	params.Set("origClientOrderId", clientOrderID)
+
+	body, err := c.doRequest(ctx, "GET", "/fapi/v1/order", params, true)
+	if err != nil {
+		return nil, ErrorWithContext(err, "GetFuturesOrder")
+	}
+
+	var order FuturesOrderResponse
+	if err := json.Unmarshal(body, &order); err != nil {
+		return nil, ErrorWithContext(err, "GetFuturesOrder")
+	}
+
+	return &order, nil
+}
+
+// validateSTPMode rejects self-trade prevention modes Binance does not accept.
+// An empty mode is valid and omitted from the request.
+func validateSTPMode(mode string) error {
+	switch mode {
+	case "", STPModeNone, STPModeExpireTaker, STPModeExpireMaker, STPModeExpireBoth:
+		return nil
+	}
+	return fmt.Errorf("invalid selfTradePreventionMode %q: must be one of %s, %s, %s, %s",
+		mode, STPModeNone, STPModeExpireTaker, STPModeExpireMaker, STPModeExpireBoth)
+}
+
+// futuresStopTypes are the futures order types triggered by stopPrice
+var futuresStopTypes = map[string]bool{
+	"STOP":               true,
+	"STOP_MARKET":        true,
+	"TAKE_PROFIT":        true,
+	"TAKE_PROFIT_MARKET": true,
+}
+
+// GetPositionMode reports whether the futures account is in hedge mode
+// (dualSidePosition) rather than one-way mode
+func (c *Client) GetPositionMode(ctx context.Context) (bool, error) {
+	if c.signer == nil {
+		return false, fmt.Errorf("signer required for GetPositionMode")
+	}
+
+	body, err := c.doRequest(ctx, "GET", "/fapi/v1/positionSide/dual", nil, true)
+	if err != nil {
+		return false, ErrorWithContext(err, "GetPositionMode")
+	}
+
+	var mode struct {
+		DualSidePosition bool `json:"dualSidePosition"`
+	}
+	if err := json.Unmarshal(body, &mode); err != nil {
+		return false, ErrorWithContext(err, "GetPositionMode")
+	}
+
+	return mode.DualSidePosition, nil
+}
+
+// ChangePositionMode switches the futures account between hedge mode
+// (dual=true) and one-way mode. Requesting the current mode is a no-op.
+func (c *Client) ChangePositionMode(ctx context.Context, dual bool) error {
+	if c.signer == nil {
+		return fmt.Errorf("signer required for ChangePositionMode")
+	}
+
+	params := url.Values{}
+	params.Set("dualSidePosition", strconv.FormatBool(dual))
+
+	_, err := c.doRequest(ctx, "POST", "/fapi/v1/positionSide/dual", params, true)
+	if err != nil {
+		// -4059: No need to change position side
+		var binanceErr *BinanceError
+		if errors.As(err, &binanceErr) && binanceErr.Code == -4059 {
+			return nil
+		}
+		return ErrorWithContext(err, "ChangePositionMode")
+	}
+
+	return nil
+}
+
+// CreateListenKey starts a spot user data stream and returns its listen key.
+// The key expires after 60 minutes unless kept alive.
+func (c *Client) CreateListenKey(ctx context.Context) (string, error) {
+	return c.createListenKey(ctx, "/api/v3/userDataStream", "CreateListenKey")
+}
+
+// CreateFuturesListenKey starts a futures user data stream and returns its
+// listen key. Futures accounts have one key at a time, so an active key is
+// returned again and extended.
+func (c *Client) CreateFuturesListenKey(ctx context.Context) (string, error) {
+	return c.createListenKey(ctx, "/fapi/v1/listenKey", "CreateFuturesListenKey")
+}
+
+// KeepAliveListenKey extends a spot listen key by another 60 minutes
+func (c *Client) KeepAliveListenKey(ctx context.Context, listenKey string) error {
+	return c.listenKeyRequest(ctx, "PUT", "/api/v3/userDataStream", listenKey, "KeepAliveListenKey")
+}
+
+// KeepAliveFuturesListenKey extends the futures listen key by another 60
+// minutes
+func (c *Client) KeepAliveFuturesListenKey(ctx context.Context) error {
+	return c.listenKeyRequest(ctx, "PUT", "/fapi/v1/listenKey", "", "KeepAliveFuturesListenKey")
+}
+
+// CloseListenKey ends a spot user data stream
+func (c *Client) CloseListenKey(ctx context.Context, listenKey string) error {
+	return c.listenKeyRequest(ctx, "DELETE", "/api/v3/userDataStream", listenKey, "CloseListenKey")
+}
+
+// CloseFuturesListenKey ends the futures user data stream
+func (c *Client) CloseFuturesListenKey(ctx context.Context) error {
+	return c.listenKeyRequest(ctx, "DELETE", "/fapi/v1/listenKey", "", "CloseFuturesListenKey")
+}
+
+// createListenKey requests a listen key from path. User data stream
+// endpoints need the API key header but no signature.
+func (c *Client) createListenKey(ctx context.Context, path, operation string) (string, error) {
+	if c.signer == nil {
+		return "", fmt.Errorf("signer required for %s", operation)
+	}
+
+	body, err := c.doRequest(ctx, "POST", path, nil, false)
+	if err != nil {
+		return "", ErrorWithContext(err, operation)
+	}
+
+	var resp struct {
+		ListenKey string `json:"listenKey"`
+	}
+	if err := json.Unmarshal(body, &resp); err != nil {
+		return "", ErrorWithContext(err, operation)
+	}
+	if resp.ListenKey == "" {
+		return "", ErrorWithContext(fmt.Errorf("empty listen key"), operation)
+	}
+	return resp.ListenKey, nil
+}
+
+// listenKeyRequest sends a keepalive or close for a listen key. Futures
+// endpoints act on the account's only key and take no listenKey parameter.
+func (c *Client) listenKeyRequest(ctx context.Context, method, path, listenKey, operation string) error {
+	if c.signer == nil {
+		return fmt.Errorf("signer required for %s", operation)
+	}
+
+	var params url.Values
+	if listenKey != "" {
+		params = url.Values{}
+		params.Set("listenKey", listenKey)
+	}
+	if _, err := c.doRequest(ctx, method, path, params, false); err != nil {
+		return ErrorWithContext(err, operation)
+	}
+	return nil
+}
+
 // GetFuturesAccount gets futures account information
 func (c *Client) GetFuturesAccount(ctx context.Context) (*FuturesAccountResponse, error) {
 	if c.signer == nil {
@@ -412,15 +1153,101 @@ func (c *Client) GetFuturesAccount(ctx context.Context) (*FuturesAccountResponse
 	return &account, nil
 }
 
+// GetMarkPrice gets a futures symbol's current mark price and funding state
+func (c *Client) GetMarkPrice(ctx context.Context, symbol string) (*MarkPrice, error) {
+	if symbol == "" {
+		return nil, fmt.Errorf("symbol is required")
+	}
+
+	params := url.Values{}
+	params.Set("symbol", symbol)
+
+	body, err := c.doRequest(ctx, "GET", "/fapi/v1/premiumIndex", params, false)
+	if err != nil {
+		return nil, ErrorWithContext(err, "GetMarkPrice")
+	}
+
+	var markPrice MarkPrice
+	if err := json.Unmarshal(body, &markPrice); err != nil {
+		return nil, ErrorWithContext(err, "GetMarkPrice")
+	}
+
+	return &markPrice, nil
+}
+
+// GetFuturesExchangeInfo fetches USDT-M futures trading rules and symbol information
+func (c *Client) GetFuturesExchangeInfo(ctx context.Context) (*ExchangeInfo, error) {
+	body, err := c.doRequest(ctx, "GET", "/fapi/v1/exchangeInfo", nil, false)
+	if err != nil {
+		return nil, ErrorWithContext(err, "GetFuturesExchangeInfo")
+	}
+
+	var exchangeInfo ExchangeInfo
+	if err := json.Unmarshal(body, &exchangeInfo); err != nil {
+		return nil, ErrorWithContext(err, "GetFuturesExchangeInfo")
+	}
+
+	return &exchangeInfo, nil
+}
+
+// Do calls an unsigned endpoint the client has no typed wrapper for, with
+// the same rate limiting and retries. The raw JSON response is returned.
+func (c *Client) Do(ctx context.Context, method, path string, params url.Values) (json.RawMessage, error) {
+	return c.passthrough(ctx, method, path, params, false, "Do")
+}
+
+// DoSigned is Do for endpoints that require a signed request
+func (c *Client) DoSigned(ctx context.Context, method, path string, params url.Values) (json.RawMessage, error) {
+	if c.signer == nil {
+		return nil, fmt.Errorf("signer required for DoSigned")
+	}
+	return c.passthrough(ctx, method, path, params, true, "DoSigned")
+}
+
+func (c *Client) passthrough(ctx context.Context, method, path string, params url.Values, signed bool, operation string) (json.RawMessage, error) {
+	method = strings.ToUpper(method)
+	switch method {
+	case http.MethodGet, http.MethodPost, http.MethodPut, http.MethodDelete:
+	default:
+		return nil, fmt.Errorf("unsupported method for %s: %s", operation, method)
+	}
+	if !strings.HasPrefix(path, "/") {
+		return nil, fmt.Errorf("path must start with /: %s", path)
+	}
+
+	body, err := c.doRequest(ctx, method, path, params, signed)
+	if err != nil {
+		return nil, ErrorWithContext(err, fmt.Sprintf("%s %s %s", operation, method, path))
+	}
+
+	return json.RawMessage(body), nil
+}
+
 // doRequest handles request execution with retries and rate limiting
 func (c *Client) doRequest(ctx context.Context, method, path string, params url.Values, signed bool) ([]byte, error) {
+	var respBody []byte
+	err := c.doStream(ctx, method, path, params, signed, func(r io.Reader) error {
+		var err error
+		respBody, err = io.ReadAll(r)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return respBody, nil
+}
+
+// doStream executes a request like doRequest but hands a successful response
+// body to decode as it streams in, rather than buffering it
+func (c *Client) doStream(ctx context.Context, method, path string, params url.Values, signed bool, decode func(io.Reader) error) error {
 	var lastErr error
+	started := time.Now()
 
 	for attempt := 0; attempt <= c.maxRetries; attempt++ {
 		// Wait for rate limiter
 		if c.rateLimiter != nil {
 			if err := c.rateLimiter.Wait(ctx); err != nil {
-				return nil, err
+				return err
 			}
 		}
 
@@ -432,57 +1259,92 @@ func (c *Client) doRequest(ctx context.Context, method, path string, params url.
 			params = url.Values{}
 		}
 
-		// Sign request if required
+		// Sign request if required, afresh on every attempt so retries carry
+		// a current timestamp and no stale signature
+		query := params
 		if signed {
 			if c.signer == nil {
-				return nil, fmt.Errorf("signer required for signed request")
+				return fmt.Errorf("signer required for signed request")
 			}
-			params = c.signer.SignedRequest(params)
+			query = c.signer.SignedRequest(params)
 		}
 
 		// Binance API expects all parameters in query string, even for POST
 		requestURL = c.baseURL + path
-		if len(params) > 0 {
-			requestURL += "?" + params.Encode()
+		if len(query) > 0 {
+			requestURL += "?" + query.Encode()
 		}
 
 		// Create request
 		req, err := http.NewRequestWithContext(ctx, method, requestURL, body)
 		if err != nil {
-			return nil, fmt.Errorf("failed to create request: %w", err)
+			return fmt.Errorf("failed to create request: %w", err)
 		}
 
 		// Set headers
 		if c.signer != nil {
 			req.Header.Set("X-MBX-APIKEY", c.signer.APIKey())
 		}
+		if id := trace.ID(ctx); id != "" {
+			req.Header.Set(trace.Header, id)
+		}
 
 		// Execute request
+		requestStart := time.Now()
 		resp, err := c.httpClient.Do(req)
 		if err != nil {
+			c.recordDuration(path, time.Since(requestStart))
 			lastErr = err
 			if attempt < c.maxRetries && isNetworkError(err) {
-				c.waitForRetry(attempt)
-				continue
+				retry, waitErr := c.waitForRetry(ctx, attempt, started)
+				if waitErr != nil {
+					return waitErr
+				}
+				if retry {
+					continue
+				}
 			}
-			return nil, err
+			return redactError(wrapNetworkError(ctx, err))
 		}
 
-		// Read response body
+		// Check for success
+		if resp.StatusCode >= 200 && resp.StatusCode < 300 {
+			respBody := &bodyReader{r: resp.Body}
+			err := decode(respBody)
+			resp.Body.Close()
+			c.recordDuration(path, time.Since(requestStart))
+			if err != nil && respBody.err != nil {
+				// The body was cut off mid-read, which is worth another try
+				lastErr = respBody.err
+				if attempt < c.maxRetries {
+					retry, waitErr := c.waitForRetry(ctx, attempt, started)
+					if waitErr != nil {
+						return waitErr
+					}
+					if retry {
+						continue
+					}
+				}
+			}
+			return err
+		}
+
+		// Read error response body
 		respBody, err := io.ReadAll(resp.Body)
 		resp.Body.Close()
+		c.recordDuration(path, time.Since(requestStart))
 		if err != nil {
 			lastErr = err
 			if attempt < c.maxRetries {
-				c.waitForRetry(attempt)
-				continue
+				retry, waitErr := c.waitForRetry(ctx, attempt, started)
+				if waitErr != nil {
+					return waitErr
+				}
+				if retry {
+					continue
+				}
 			}
-			return nil, err
-		}
-
-		// Check for success
-		if resp.StatusCode >= 200 && resp.StatusCode < 300 {
-			return respBody, nil
+			return err
 		}
 
 		// Parse error
@@ -492,18 +1354,52 @@ func (c *Client) doRequest(ctx context.Context, method, path string, params url.
 
 		// Retry if error is retryable
 		if attempt < c.maxRetries && IsRetryableError(apiErr) {
-			c.waitForRetry(attempt)
-			continue
+			retry, waitErr := c.waitForRetry(ctx, attempt, started)
+			if waitErr != nil {
+				return waitErr
+			}
+			if retry {
+				continue
+			}
 		}
 
-		return nil, apiErr
+		return apiErr
 	}
 
-	return nil, lastErr
+	return redactError(lastErr)
+}
+
+// bodyReader remembers the first error reading a response body, telling a
+// dropped connection apart from a payload decode rejects
+type bodyReader struct {
+	r   io.Reader
+	err error
 }
 
-// waitForRetry implements exponential backoff with jitter
-func (c *Client) waitForRetry(attempt int) {
+func (b *bodyReader) Read(p []byte) (int, error) {
+	n, err := b.r.Read(p)
+	if err != nil && err != io.EOF && b.err == nil {
+		b.err = err
+	}
+	return n, err
+}
+
+// recordDuration reports a request timing with the query string stripped
+// from the endpoint label to keep cardinality bounded
+func (c *Client) recordDuration(path string, d time.Duration) {
+	if c.metrics == nil {
+		return
+	}
+	if i := strings.IndexByte(path, '?'); i >= 0 {
+		path = path[:i]
+	}
+	c.metrics.RecordHTTPDuration("binance", path, d.Seconds())
+}
+
+// waitForRetry implements exponential backoff with jitter, returning early
+// with the context's error if it is cancelled mid-sleep. It reports false
+// without sleeping when the backoff would exceed the retry duration budget.
+func (c *Client) waitForRetry(ctx context.Context, attempt int, started time.Time) (bool, error) {
 	baseDelay := 100 * time.Millisecond
 	maxDelay := 2 * time.Second
 
@@ -518,7 +1414,25 @@ func (c *Client) waitForRetry(attempt int) {
 	jitter := time.Duration(float64(delay) * 0.2 * (2*jitterFactor - 1))
 	delay += jitter
 
-	time.Sleep(delay)
+	if c.maxRetryDuration > 0 && time.Since(started)+delay > c.maxRetryDuration {
+		return false, nil
+	}
+
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+		return false, ctx.Err()
+	case <-timer.C:
+		return true, nil
+	}
+}
+
+// normalizeSymbol uppercases a symbol for REST. Stream names use lowercase,
+// so callers often carry that form over, and Binance rejects it as invalid.
+func normalizeSymbol(symbol string) string {
+	return strings.ToUpper(symbol)
 }
 
 // isNetworkError checks if an error is a network-related error
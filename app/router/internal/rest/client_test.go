@@ -1,19 +1,26 @@
 package rest
 
 import (
+	"bytes"
 	"context"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"io"
+	"net"
 	"net/http"
 	"net/http/httptest"
 	"net/url"
+	"strconv"
+	"strings"
 	"sync"
+	"sync/atomic"
 	"testing"
 	"time"
 
 	"github.com/shopspring/decimal"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 
 	"router/internal/auth"
 )
@@ -54,6 +61,42 @@ func TestNewClient(t *testing.T) {
 	})
 }
 
+func TestClient_Transport(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{}`))
+	}))
+	defer server.Close()
+
+	t.Run("defaults to a pooled transport", func(t *testing.T) {
+		client := NewClient(server.URL, nil)
+
+		transport, ok := client.httpClient.Transport.(*http.Transport)
+		require.True(t, ok)
+		assert.Equal(t, DefaultMaxIdleConnsPerHost, transport.MaxIdleConnsPerHost)
+		assert.Equal(t, DefaultIdleConnTimeout, transport.IdleConnTimeout)
+	})
+
+	t.Run("uses a custom transport", func(t *testing.T) {
+		var dials atomic.Int32
+		transport := NewTransport()
+		dialer := &net.Dialer{}
+		transport.DialContext = func(ctx context.Context, network, addr string) (net.Conn, error) {
+			dials.Add(1)
+			return dialer.DialContext(ctx, network, addr)
+		}
+
+		client := NewClient(server.URL, nil, WithTransport(transport), WithTimeout(time.Second))
+		for i := 0; i < 3; i++ {
+			require.NoError(t, client.Ping(context.Background()))
+		}
+
+		assert.Same(t, transport, client.httpClient.Transport)
+		assert.Equal(t, time.Second, client.Timeout())
+		// Sequential requests reuse the pooled connection
+		assert.Equal(t, int32(1), dials.Load())
+	})
+}
+
 func TestClient_GetExchangeInfo(t *testing.T) {
 	t.Run("parses exchange info response correctly", func(t *testing.T) {
 		mockResponse := `{
@@ -100,6 +143,63 @@ func TestClient_GetExchangeInfo(t *testing.T) {
 		assert.True(t, exchangeInfo.Symbols[0].IsSpotTradingAllowed)
 	})
 
+	t.Run("parses typed symbol filters", func(t *testing.T) {
+		mockResponse := `{
+			"timezone": "UTC",
+			"serverTime": 1499827319559,
+			"symbols": [
+				{
+					"symbol": "ETHBTC",
+					"status": "TRADING",
+					"baseAsset": "ETH",
+					"quoteAsset": "BTC",
+					"filters": [
+						{"filterType": "PRICE_FILTER", "minPrice": "0.00000100", "maxPrice": "100000.00000000", "tickSize": "0.00000100"},
+						{"filterType": "LOT_SIZE", "minQty": "0.00100000", "maxQty": "100000.00000000", "stepSize": "0.00100000"},
+						{"filterType": "ICEBERG_PARTS", "limit": 10},
+						{"filterType": "NOTIONAL", "minNotional": "0.00010000", "applyMinToMarket": true, "maxNotional": "9000000.00000000", "avgPriceMins": 5}
+					]
+				},
+				{
+					"symbol": "BTCUSDT",
+					"status": "TRADING",
+					"filters": [
+						{"filterType": "MIN_NOTIONAL", "notional": "5"}
+					]
+				}
+			]
+		}`
+
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Content-Type", "application/json")
+			w.Write([]byte(mockResponse))
+		}))
+		defer server.Close()
+
+		client := NewClient(server.URL, nil)
+		exchangeInfo, err := client.GetExchangeInfo(context.Background())
+		assert.NoError(t, err)
+		assert.Len(t, exchangeInfo.Symbols, 2)
+
+		symbol := exchangeInfo.Symbols[0]
+		assert.Len(t, symbol.Filters, 4)
+
+		priceFilter := symbol.Filter(FilterTypePrice)
+		assert.NotNil(t, priceFilter)
+		assert.True(t, decimal.RequireFromString("0.000001").Equal(priceFilter.MinPrice))
+		assert.True(t, decimal.RequireFromString("100000").Equal(priceFilter.MaxPrice))
+
+		assert.True(t, decimal.RequireFromString("0.000001").Equal(symbol.TickSize()))
+		assert.True(t, decimal.RequireFromString("0.001").Equal(symbol.StepSize()))
+		assert.True(t, decimal.RequireFromString("0.0001").Equal(symbol.MinNotional()))
+		assert.Nil(t, symbol.Filter(FilterTypeMarketLotSize))
+
+		// Futures style MIN_NOTIONAL reports the value as "notional"
+		futuresStyle := exchangeInfo.Symbols[1]
+		assert.True(t, decimal.NewFromInt(5).Equal(futuresStyle.MinNotional()))
+		assert.True(t, futuresStyle.TickSize().IsZero())
+	})
+
 	t.Run("handles network error with retry", func(t *testing.T) {
 		callCount := 0
 		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
@@ -159,6 +259,158 @@ func TestClient_GetExchangeInfo(t *testing.T) {
 	})
 }
 
+func TestClient_GetExchangeInfoCache(t *testing.T) {
+	newServer := func(requests *atomic.Int32) *httptest.Server {
+		return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			requests.Add(1)
+			w.Header().Set("Content-Type", "application/json")
+			w.Write([]byte(`{"timezone":"UTC","serverTime":1,"symbols":[{"symbol":"BTCUSDT","status":"TRADING"}]}`))
+		}))
+	}
+
+	t.Run("serves repeated calls within TTL from cache", func(t *testing.T) {
+		var requests atomic.Int32
+		server := newServer(&requests)
+		defer server.Close()
+
+		client := NewClient(server.URL, nil)
+		first, err := client.GetExchangeInfo(context.Background())
+		require.NoError(t, err)
+		second, err := client.GetExchangeInfo(context.Background())
+		require.NoError(t, err)
+
+		assert.Equal(t, int32(1), requests.Load())
+		assert.Same(t, first, second)
+	})
+
+	t.Run("force refresh bypasses cache", func(t *testing.T) {
+		var requests atomic.Int32
+		server := newServer(&requests)
+		defer server.Close()
+
+		client := NewClient(server.URL, nil)
+		_, err := client.GetExchangeInfo(context.Background())
+		require.NoError(t, err)
+		refreshed, err := client.ForceRefreshExchangeInfo(context.Background())
+		require.NoError(t, err)
+		assert.Equal(t, int32(2), requests.Load())
+
+		// The refreshed copy replaces the cached one
+		cached, err := client.GetExchangeInfo(context.Background())
+		require.NoError(t, err)
+		assert.Same(t, refreshed, cached)
+		assert.Equal(t, int32(2), requests.Load())
+	})
+
+	t.Run("refetches once TTL expires", func(t *testing.T) {
+		var requests atomic.Int32
+		server := newServer(&requests)
+		defer server.Close()
+
+		client := NewClient(server.URL, nil, WithExchangeInfoTTL(20*time.Millisecond))
+		_, err := client.GetExchangeInfo(context.Background())
+		require.NoError(t, err)
+		time.Sleep(30 * time.Millisecond)
+		_, err = client.GetExchangeInfo(context.Background())
+		require.NoError(t, err)
+
+		assert.Equal(t, int32(2), requests.Load())
+	})
+
+	t.Run("zero TTL disables caching", func(t *testing.T) {
+		var requests atomic.Int32
+		server := newServer(&requests)
+		defer server.Close()
+
+		client := NewClient(server.URL, nil, WithExchangeInfoTTL(0))
+		for i := 0; i < 3; i++ {
+			_, err := client.GetExchangeInfo(context.Background())
+			require.NoError(t, err)
+		}
+
+		assert.Equal(t, int32(3), requests.Load())
+	})
+
+	t.Run("concurrent callers share one fetch and keep their own deadlines", func(t *testing.T) {
+		var requests atomic.Int32
+		release := make(chan struct{})
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			requests.Add(1)
+			<-release
+			w.Header().Set("Content-Type", "application/json")
+			w.Write([]byte(`{"timezone":"UTC","serverTime":1,"symbols":[{"symbol":"BTCUSDT","status":"TRADING"}]}`))
+		}))
+		defer server.Close()
+
+		client := NewClient(server.URL, nil)
+		results := make(chan *ExchangeInfo, 3)
+		for i := 0; i < 3; i++ {
+			go func() {
+				info, err := client.GetExchangeInfo(context.Background())
+				assert.NoError(t, err)
+				results <- info
+			}()
+		}
+		require.Eventually(t, func() bool { return requests.Load() == 1 }, time.Second, 5*time.Millisecond)
+
+		// A caller whose deadline passes stops waiting on the shared fetch
+		ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+		defer cancel()
+		start := time.Now()
+		_, err := client.ForceRefreshExchangeInfo(ctx)
+		assert.ErrorIs(t, err, context.DeadlineExceeded)
+		assert.Less(t, time.Since(start), 500*time.Millisecond)
+
+		close(release)
+		first := <-results
+		for i := 1; i < 3; i++ {
+			assert.Same(t, first, <-results)
+		}
+		assert.Equal(t, int32(1), requests.Load())
+	})
+}
+
+func TestClient_GetExchangeInfoForSymbol(t *testing.T) {
+	t.Run("queries a single symbol", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			assert.Equal(t, "/api/v3/exchangeInfo", r.URL.Path)
+			assert.Equal(t, "BTCUSDT", r.URL.Query().Get("symbol"))
+			w.Header().Set("Content-Type", "application/json")
+			w.Write([]byte(`{"symbols":[{"symbol":"BTCUSDT","status":"TRADING","baseAsset":"BTC","quoteAsset":"USDT",
+				"filters":[{"filterType":"PRICE_FILTER","minPrice":"0.01","maxPrice":"1000000","tickSize":"0.01"},
+				{"filterType":"LOT_SIZE","minQty":"0.00001","maxQty":"9000","stepSize":"0.00001"}]}]}`))
+		}))
+		defer server.Close()
+
+		client := NewClient(server.URL, nil)
+
+		symbol, err := client.GetExchangeInfoForSymbol(context.Background(), "BTCUSDT")
+		require.NoError(t, err)
+		assert.Equal(t, "BTCUSDT", symbol.Symbol)
+		assert.Equal(t, "0.01", symbol.TickSize().String())
+		assert.Equal(t, "0.00001", symbol.StepSize().String())
+	})
+
+	t.Run("returns error when symbol is missing from response", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Write([]byte(`{"symbols":[]}`))
+		}))
+		defer server.Close()
+
+		client := NewClient(server.URL, nil)
+
+		_, err := client.GetExchangeInfoForSymbol(context.Background(), "BTCUSDT")
+		assert.Error(t, err)
+	})
+
+	t.Run("requires symbol", func(t *testing.T) {
+		client := NewClient("http://localhost", nil)
+
+		_, err := client.GetExchangeInfoForSymbol(context.Background(), "")
+		assert.Error(t, err)
+	})
+}
+
 func TestClient_GetOrderBook(t *testing.T) {
 	t.Run("parses order book response correctly", func(t *testing.T) {
 		mockResponse := `{
@@ -250,6 +502,90 @@ func TestClient_GetOrderBook(t *testing.T) {
 		assert.Nil(t, orderBook)
 		assert.Contains(t, err.Error(), "symbol is required")
 	})
+
+	t.Run("malformed price fails only in strict mode", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Content-Type", "application/json")
+			w.Write([]byte(`{"lastUpdateId":123,"bids":[["4.0","1.0"]],"asks":[["4.1","2.0"],["N/A","3.0"]]}`))
+		}))
+		defer server.Close()
+		ctx := context.Background()
+
+		lenient, err := NewClient(server.URL, nil).GetOrderBook(ctx, "BTCUSDT", 100)
+		require.NoError(t, err)
+		assert.True(t, lenient.Asks[1].Price.IsZero())
+
+		strict := NewClient(server.URL, nil, WithStrictDecimals(true))
+		orderBook, err := strict.GetOrderBook(ctx, "BTCUSDT", 100)
+		require.Error(t, err)
+		assert.Nil(t, orderBook)
+		assert.Contains(t, err.Error(), `BTCUSDT ask level 1: invalid price "N/A"`)
+	})
+}
+
+func TestClient_GetFuturesOrderBook(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/fapi/v1/depth", r.URL.Path)
+		assert.Equal(t, "BTCUSDT", r.URL.Query().Get("symbol"))
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"lastUpdateId":42,"bids":[["50000.0","1.5"]],"asks":[["50001.0","2.0"]]}`))
+	}))
+	defer server.Close()
+
+	client := NewClient(server.URL, nil)
+	ctx := context.Background()
+
+	orderBook, err := client.GetFuturesOrderBook(ctx, "btcusdt", 1000)
+	require.NoError(t, err)
+	assert.Equal(t, int64(42), orderBook.LastUpdateID)
+	require.Len(t, orderBook.Bids, 1)
+	assert.True(t, decimal.NewFromInt(50000).Equal(orderBook.Bids[0].Price))
+
+	// Futures depth stops at 1000 levels
+	_, err = client.GetFuturesOrderBook(ctx, "BTCUSDT", 5000)
+	assert.EqualError(t, err, "invalid limit: 5000. Valid limits are: 5, 10, 20, 50, 100, 500, 1000")
+}
+
+func TestClient_ListenKeys(t *testing.T) {
+	type call struct{ method, path, listenKey, apiKey string }
+	var calls []call
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls = append(calls, call{r.Method, r.URL.Path, r.URL.Query().Get("listenKey"), r.Header.Get("X-MBX-APIKEY")})
+		assert.Empty(t, r.URL.Query().Get("signature"), "user stream endpoints are not signed")
+		if r.Method == http.MethodPost {
+			w.Write([]byte(`{"listenKey":"key-1"}`))
+			return
+		}
+		w.Write([]byte(`{}`))
+	}))
+	defer server.Close()
+
+	client := NewClient(server.URL, auth.NewSigner("api-key", "secret"))
+	ctx := context.Background()
+
+	key, err := client.CreateListenKey(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, "key-1", key)
+	require.NoError(t, client.KeepAliveListenKey(ctx, key))
+	require.NoError(t, client.CloseListenKey(ctx, key))
+
+	key, err = client.CreateFuturesListenKey(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, "key-1", key)
+	require.NoError(t, client.KeepAliveFuturesListenKey(ctx))
+	require.NoError(t, client.CloseFuturesListenKey(ctx))
+
+	assert.Equal(t, []call{
+		{"POST", "/api/v3/userDataStream", "", "api-key"},
+		{"PUT", "/api/v3/userDataStream", "key-1", "api-key"},
+		{"DELETE", "/api/v3/userDataStream", "key-1", "api-key"},
+		{"POST", "/fapi/v1/listenKey", "", "api-key"},
+		{"PUT", "/fapi/v1/listenKey", "", "api-key"},
+		{"DELETE", "/fapi/v1/listenKey", "", "api-key"},
+	}, calls)
+
+	_, err = NewClient(server.URL, nil).CreateListenKey(ctx)
+	assert.EqualError(t, err, "signer required for CreateListenKey")
 }
 
 func TestClient_GetAccount(t *testing.T) {
@@ -371,6 +707,125 @@ func TestClient_GetAccount(t *testing.T) {
 	})
 }
 
+func TestClient_PlaceOrder_SelfTradePrevention(t *testing.T) {
+	t.Run("forwards a valid mode", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			assert.Equal(t, STPModeExpireTaker, r.URL.Query().Get("selfTradePreventionMode"))
+			w.Write([]byte(`{"symbol":"BTCUSDT","orderId":1,"status":"NEW"}`))
+		}))
+		defer server.Close()
+
+		client := NewClient(server.URL, auth.NewSigner("test-key", "test-secret"))
+		_, err := client.PlaceOrder(context.Background(), &OrderRequest{
+			Symbol:                  "BTCUSDT",
+			Side:                    "BUY",
+			Type:                    "LIMIT",
+			TimeInForce:             "GTC",
+			Quantity:                decimal.NewFromFloat(0.1),
+			Price:                   decimal.NewFromFloat(50000),
+			SelfTradePreventionMode: STPModeExpireTaker,
+		})
+		require.NoError(t, err)
+	})
+
+	t.Run("omits the parameter when unset", func(t *testing.T) {
+		req := &OrderRequest{Symbol: "BTCUSDT", Side: "BUY", Type: "MARKET", Quantity: decimal.NewFromFloat(0.1)}
+		assert.False(t, req.Params().Has("selfTradePreventionMode"))
+	})
+
+	t.Run("rejects an unknown mode locally", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			t.Error("request should not reach the server")
+		}))
+		defer server.Close()
+
+		client := NewClient(server.URL, auth.NewSigner("test-key", "test-secret"))
+		_, err := client.PlaceOrder(context.Background(), &OrderRequest{
+			Symbol:                  "BTCUSDT",
+			Side:                    "BUY",
+			Type:                    "MARKET",
+			Quantity:                decimal.NewFromFloat(0.1),
+			SelfTradePreventionMode: "expire_taker",
+		})
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "invalid selfTradePreventionMode")
+	})
+}
+
+func TestClient_UppercasesSymbols(t *testing.T) {
+	tests := []struct {
+		name     string
+		response string
+		call     func(ctx context.Context, client *Client) error
+	}{
+		{
+			name:     "PlaceOrder",
+			response: `{"symbol":"BTCUSDT","orderId":1}`,
+			call: func(ctx context.Context, client *Client) error {
+				_, err := client.PlaceOrder(ctx, &OrderRequest{
+					Symbol:   "btcusdt",
+					Side:     "BUY",
+					Type:     "MARKET",
+					Quantity: decimal.NewFromFloat(0.1),
+				})
+				return err
+			},
+		},
+		{
+			name:     "PlaceFuturesOrder",
+			response: `{"symbol":"BTCUSDT","orderId":1}`,
+			call: func(ctx context.Context, client *Client) error {
+				_, err := client.PlaceFuturesOrder(ctx, &FuturesOrderRequest{
+					Symbol:   "btcusdt",
+					Side:     "BUY",
+					Type:     "MARKET",
+					Quantity: decimal.NewFromFloat(0.1),
+				})
+				return err
+			},
+		},
+		{
+			name:     "CancelOrder",
+			response: `{}`,
+			call: func(ctx context.Context, client *Client) error {
+				return client.CancelOrder(ctx, "btcusdt", 1)
+			},
+		},
+		{
+			name:     "GetOpenOrders",
+			response: `[]`,
+			call: func(ctx context.Context, client *Client) error {
+				_, err := client.GetOpenOrders(ctx, "btcusdt")
+				return err
+			},
+		},
+		{
+			name:     "GetOrderBook",
+			response: `{"lastUpdateId":1,"bids":[],"asks":[]}`,
+			call: func(ctx context.Context, client *Client) error {
+				_, err := client.GetOrderBook(ctx, "btcusdt", 5)
+				return err
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var symbol string
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				symbol = r.URL.Query().Get("symbol")
+				w.Header().Set("Content-Type", "application/json")
+				w.Write([]byte(tt.response))
+			}))
+			defer server.Close()
+
+			client := NewClient(server.URL, auth.NewSigner("test-key", "test-secret"))
+			require.NoError(t, tt.call(context.Background(), client))
+			assert.Equal(t, "BTCUSDT", symbol)
+		})
+	}
+}
+
 func TestClient_PlaceOrder(t *testing.T) {
 	t.Run("validates required fields", func(t *testing.T) {
 		signer := auth.NewSigner("test-key", "test-secret")
@@ -436,6 +891,68 @@ func TestClient_PlaceOrder(t *testing.T) {
 		assert.Error(t, err)
 		assert.Nil(t, resp)
 		assert.Contains(t, err.Error(), "price is required for LIMIT orders")
+
+		// Both quantity and quoteOrderQty on MARKET order
+		req = &OrderRequest{
+			Symbol:        "BTCUSDT",
+			Side:          "BUY",
+			Type:          "MARKET",
+			Quantity:      decimal.NewFromFloat(1.0),
+			QuoteOrderQty: decimal.NewFromInt(100),
+		}
+		resp, err = client.PlaceOrder(ctx, req)
+		assert.Error(t, err)
+		assert.Nil(t, resp)
+		assert.Contains(t, err.Error(), "exactly one of quantity or quoteOrderQty")
+
+		// Neither quantity nor quoteOrderQty on MARKET order
+		req = &OrderRequest{
+			Symbol: "BTCUSDT",
+			Side:   "BUY",
+			Type:   "MARKET",
+		}
+		_, err = client.PlaceOrder(ctx, req)
+		assert.Error(t, err)
+		assert.Contains(t, err.Error(), "exactly one of quantity or quoteOrderQty")
+
+		// quoteOrderQty on LIMIT order
+		req = &OrderRequest{
+			Symbol:        "BTCUSDT",
+			Side:          "BUY",
+			Type:          "LIMIT",
+			Price:         decimal.NewFromFloat(50000),
+			Quantity:      decimal.NewFromFloat(1.0),
+			QuoteOrderQty: decimal.NewFromInt(100),
+		}
+		_, err = client.PlaceOrder(ctx, req)
+		assert.Error(t, err)
+		assert.Contains(t, err.Error(), "quoteOrderQty is only supported for MARKET orders")
+	})
+
+	t.Run("sends quoteOrderQty for quote-sized market buy", func(t *testing.T) {
+		var receivedParams url.Values
+
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			receivedParams = r.URL.Query()
+			w.Header().Set("Content-Type", "application/json")
+			w.Write([]byte(`{"symbol":"BTCUSDT","orderId":1,"status":"FILLED","type":"MARKET","side":"BUY",
+				"executedQty":"0.002","cummulativeQuoteQty":"100"}`))
+		}))
+		defer server.Close()
+
+		signer := auth.NewSigner("test-key", "test-secret")
+		client := NewClient(server.URL, signer)
+
+		resp, err := client.PlaceOrder(context.Background(), &OrderRequest{
+			Symbol:        "BTCUSDT",
+			Side:          "BUY",
+			Type:          "MARKET",
+			QuoteOrderQty: decimal.NewFromInt(100),
+		})
+		require.NoError(t, err)
+		assert.Equal(t, "100", receivedParams.Get("quoteOrderQty"))
+		assert.False(t, receivedParams.Has("quantity"))
+		assert.Equal(t, "100", resp.CummulativeQuoteQty.String())
 	})
 
 	t.Run("signs request correctly", func(t *testing.T) {
@@ -669,6 +1186,479 @@ func TestClient_CancelOrder(t *testing.T) {
 	})
 }
 
+func TestClient_CancelReplaceOrder(t *testing.T) {
+	newOrder := OrderRequest{
+		Symbol:      "BTCUSDT",
+		Side:        "BUY",
+		Type:        "LIMIT",
+		TimeInForce: "GTC",
+		Quantity:    decimal.NewFromFloat(0.1),
+		Price:       decimal.NewFromFloat(49000),
+	}
+
+	t.Run("returns both legs on success", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			assert.Equal(t, "POST", r.Method)
+			assert.Equal(t, "/api/v3/order/cancelReplace", r.URL.Path)
+			q := r.URL.Query()
+			assert.Equal(t, CancelReplaceStopOnFailure, q.Get("cancelReplaceMode"))
+			assert.Equal(t, "12345", q.Get("cancelOrderId"))
+			assert.Equal(t, "BTCUSDT", q.Get("symbol"))
+			assert.Equal(t, "49000", q.Get("price"))
+			assert.NotEmpty(t, q.Get("signature"))
+
+			w.Write([]byte(`{"cancelResult":"SUCCESS","newOrderResult":"SUCCESS",
+				"cancelResponse":{"symbol":"BTCUSDT","orderId":12345,"status":"CANCELED","price":"50000"},
+				"newOrderResponse":{"symbol":"BTCUSDT","orderId":12346,"status":"NEW","price":"49000"}}`))
+		}))
+		defer server.Close()
+
+		client := NewClient(server.URL, auth.NewSigner("test-key", "test-secret"))
+		resp, err := client.CancelReplaceOrder(context.Background(), &CancelReplaceRequest{
+			CancelReplaceMode: CancelReplaceStopOnFailure,
+			CancelOrderID:     12345,
+			NewOrder:          newOrder,
+		})
+
+		require.NoError(t, err)
+		assert.Equal(t, CancelReplaceSuccess, resp.CancelResult)
+		assert.Equal(t, CancelReplaceSuccess, resp.NewOrderResult)
+		require.NotNil(t, resp.CancelResponse)
+		assert.Equal(t, "CANCELED", resp.CancelResponse.Status)
+		require.NotNil(t, resp.NewOrderResponse)
+		assert.Equal(t, int64(12346), resp.NewOrderResponse.OrderID)
+		assert.Nil(t, resp.CancelError)
+		assert.Nil(t, resp.NewOrderError)
+	})
+
+	t.Run("stops before placing when cancel fails", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusBadRequest)
+			w.Write([]byte(`{"code":-2022,"msg":"Order cancel-replace failed.","data":{
+				"cancelResult":"FAILURE","newOrderResult":"NOT_ATTEMPTED",
+				"cancelResponse":{"code":-2011,"msg":"Unknown order sent."},"newOrderResponse":null}}`))
+		}))
+		defer server.Close()
+
+		client := NewClient(server.URL, auth.NewSigner("test-key", "test-secret"))
+		resp, err := client.CancelReplaceOrder(context.Background(), &CancelReplaceRequest{
+			CancelReplaceMode: CancelReplaceStopOnFailure,
+			CancelOrderID:     12345,
+			NewOrder:          newOrder,
+		})
+
+		require.Error(t, err)
+		var apiErr *BinanceError
+		require.True(t, errors.As(err, &apiErr))
+		assert.Equal(t, -2022, apiErr.Code)
+
+		require.NotNil(t, resp)
+		assert.Equal(t, CancelReplaceFailure, resp.CancelResult)
+		assert.Equal(t, CancelReplaceNotAttempted, resp.NewOrderResult)
+		require.NotNil(t, resp.CancelError)
+		assert.True(t, resp.CancelError.IsUnknownOrder())
+		assert.Nil(t, resp.NewOrderResponse)
+	})
+
+	t.Run("reports a new order placed despite a failed cancel", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusConflict)
+			w.Write([]byte(`{"code":-2021,"msg":"Order cancel-replace partially failed.","data":{
+				"cancelResult":"FAILURE","newOrderResult":"SUCCESS",
+				"cancelResponse":{"code":-2011,"msg":"Unknown order sent."},
+				"newOrderResponse":{"symbol":"BTCUSDT","orderId":12346,"status":"NEW"}}}`))
+		}))
+		defer server.Close()
+
+		client := NewClient(server.URL, auth.NewSigner("test-key", "test-secret"))
+		resp, err := client.CancelReplaceOrder(context.Background(), &CancelReplaceRequest{
+			CancelReplaceMode: CancelReplaceAllowFailure,
+			CancelOrderID:     12345,
+			NewOrder:          newOrder,
+		})
+
+		require.Error(t, err)
+		require.NotNil(t, resp)
+		require.NotNil(t, resp.NewOrderResponse)
+		assert.Equal(t, int64(12346), resp.NewOrderResponse.OrderID)
+		require.NotNil(t, resp.CancelError)
+		assert.Equal(t, -2011, resp.CancelError.Code)
+	})
+
+	t.Run("validates request", func(t *testing.T) {
+		client := NewClient("http://localhost", auth.NewSigner("test-key", "test-secret"))
+
+		_, err := client.CancelReplaceOrder(context.Background(), &CancelReplaceRequest{
+			CancelReplaceMode: "REPLACE",
+			CancelOrderID:     12345,
+			NewOrder:          newOrder,
+		})
+		assert.ErrorContains(t, err, "invalid cancelReplaceMode")
+
+		_, err = client.CancelReplaceOrder(context.Background(), &CancelReplaceRequest{
+			CancelReplaceMode: CancelReplaceStopOnFailure,
+			NewOrder:          newOrder,
+		})
+		assert.ErrorContains(t, err, "cancelOrderId or cancelOrigClientOrderId is required")
+	})
+}
+
+func TestClient_PositionMode(t *testing.T) {
+	signer := auth.NewSigner("test-key", "test-secret")
+	ctx := context.Background()
+
+	t.Run("reads dual side position", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			assert.Equal(t, "/fapi/v1/positionSide/dual", r.URL.Path)
+			assert.Equal(t, "GET", r.Method)
+			assert.NotEmpty(t, r.URL.Query().Get("signature"))
+
+			w.Header().Set("Content-Type", "application/json")
+			w.Write([]byte(`{"dualSidePosition": true}`))
+		}))
+		defer server.Close()
+
+		hedge, err := NewClient(server.URL, signer).GetPositionMode(ctx)
+		require.NoError(t, err)
+		assert.True(t, hedge)
+	})
+
+	t.Run("changes mode", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			assert.Equal(t, "/fapi/v1/positionSide/dual", r.URL.Path)
+			assert.Equal(t, "POST", r.Method)
+			assert.Equal(t, "false", r.URL.Query().Get("dualSidePosition"))
+
+			w.Header().Set("Content-Type", "application/json")
+			w.Write([]byte(`{"code": 200, "msg": "success"}`))
+		}))
+		defer server.Close()
+
+		err := NewClient(server.URL, signer).ChangePositionMode(ctx, false)
+		assert.NoError(t, err)
+	})
+
+	t.Run("treats unchanged mode as success", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(400)
+			w.Write([]byte(`{"code":-4059,"msg":"No need to change position side."}`))
+		}))
+		defer server.Close()
+
+		err := NewClient(server.URL, signer).ChangePositionMode(ctx, true)
+		assert.NoError(t, err)
+	})
+
+	t.Run("surfaces other errors", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(400)
+			w.Write([]byte(`{"code":-4068,"msg":"Position side cannot be changed if there exists position."}`))
+		}))
+		defer server.Close()
+
+		err := NewClient(server.URL, signer, WithMaxRetries(0)).ChangePositionMode(ctx, true)
+		require.Error(t, err)
+		var binanceErr *BinanceError
+		require.True(t, errors.As(err, &binanceErr))
+		assert.Equal(t, -4068, binanceErr.Code)
+	})
+}
+
+func TestClient_Passthrough(t *testing.T) {
+	signer := auth.NewSigner("test-key", "test-secret")
+	ctx := context.Background()
+
+	// verifySignature checks a request was signed over everything but the
+	// signature itself
+	verifySignature := func(t *testing.T, r *http.Request) {
+		query := r.URL.Query()
+		signature := query.Get("signature")
+		query.Del("signature")
+		assert.NotEmpty(t, query.Get("timestamp"))
+		assert.True(t, signer.ValidateSignature(query, signature), "invalid signature")
+		assert.Equal(t, "test-key", r.Header.Get("X-MBX-APIKEY"))
+	}
+
+	t.Run("unsigned GET returns raw JSON", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			assert.Equal(t, "GET", r.Method)
+			assert.Equal(t, "/api/v3/avgPrice", r.URL.Path)
+			assert.Equal(t, "BTCUSDT", r.URL.Query().Get("symbol"))
+			assert.Empty(t, r.URL.Query().Get("signature"))
+
+			w.Header().Set("Content-Type", "application/json")
+			w.Write([]byte(`{"mins":5,"price":"50000.00"}`))
+		}))
+		defer server.Close()
+
+		body, err := NewClient(server.URL, nil).Do(ctx, "get", "/api/v3/avgPrice", url.Values{"symbol": {"BTCUSDT"}})
+		require.NoError(t, err)
+		assert.JSONEq(t, `{"mins":5,"price":"50000.00"}`, string(body))
+	})
+
+	t.Run("signed POST is signed", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			assert.Equal(t, "POST", r.Method)
+			assert.Equal(t, "/sapi/v1/asset/dust", r.URL.Path)
+			assert.Equal(t, "BNB", r.URL.Query().Get("asset"))
+			verifySignature(t, r)
+
+			w.Header().Set("Content-Type", "application/json")
+			w.Write([]byte(`{"totalServiceCharge":"0.02"}`))
+		}))
+		defer server.Close()
+
+		body, err := NewClient(server.URL, signer).DoSigned(ctx, "POST", "/sapi/v1/asset/dust", url.Values{"asset": {"BNB"}})
+		require.NoError(t, err)
+		assert.Contains(t, string(body), "totalServiceCharge")
+	})
+
+	t.Run("signed retries are re-signed", func(t *testing.T) {
+		var calls int32
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			verifySignature(t, r)
+			assert.Len(t, r.URL.Query()["signature"], 1)
+
+			if atomic.AddInt32(&calls, 1) < 3 {
+				w.WriteHeader(503)
+				return
+			}
+			w.Header().Set("Content-Type", "application/json")
+			w.Write([]byte(`[]`))
+		}))
+		defer server.Close()
+
+		body, err := NewClient(server.URL, signer, WithMaxRetries(3)).DoSigned(ctx, "GET", "/sapi/v1/capital/config/getall", nil)
+		require.NoError(t, err)
+		assert.Equal(t, "[]", string(body))
+		assert.Equal(t, int32(3), atomic.LoadInt32(&calls))
+	})
+
+	t.Run("surfaces API errors", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(400)
+			w.Write([]byte(`{"code":-1102,"msg":"Mandatory parameter 'symbol' was not sent."}`))
+		}))
+		defer server.Close()
+
+		_, err := NewClient(server.URL, nil, WithMaxRetries(0)).Do(ctx, "GET", "/api/v3/avgPrice", nil)
+		require.Error(t, err)
+		var binanceErr *BinanceError
+		require.True(t, errors.As(err, &binanceErr))
+		assert.Equal(t, -1102, binanceErr.Code)
+		assert.Contains(t, err.Error(), "Do GET /api/v3/avgPrice")
+	})
+
+	t.Run("rejects bad input before sending", func(t *testing.T) {
+		client := NewClient("http://127.0.0.1:0", nil)
+
+		_, err := client.Do(ctx, "PATCH", "/api/v3/order", nil)
+		assert.ErrorContains(t, err, "unsupported method")
+
+		_, err = client.Do(ctx, "GET", "api/v3/order", nil)
+		assert.ErrorContains(t, err, "path must start with /")
+
+		_, err = client.DoSigned(ctx, "GET", "/api/v3/account", nil)
+		assert.ErrorContains(t, err, "signer required for DoSigned")
+	})
+}
+
+func TestClient_Ticker24hr(t *testing.T) {
+	const btcTicker = `{"symbol":"BTCUSDT","priceChange":"-94.99999800","priceChangePercent":"-0.095",
+		"lastPrice":"50000.01000000","highPrice":"51000.00000000","lowPrice":"49000.00000000",
+		"volume":"12345.67890123","quoteVolume":"617283945.06150000","openTime":1499783499040,
+		"closeTime":1499869899040,"firstId":28385,"lastId":28460,"count":76}`
+	const shibTicker = `{"symbol":"SHIBUSDT","priceChange":"0.000000010000","priceChangePercent":"0.081",
+		"lastPrice":"0.000012340000","highPrice":"0.000012500000","lowPrice":"0.000012100000",
+		"volume":"98765432109876.54","quoteVolume":"1218765432.109876543210"}`
+
+	ctx := context.Background()
+
+	t.Run("single symbol", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			assert.Equal(t, "/api/v3/ticker/24hr", r.URL.Path)
+			assert.Equal(t, "SHIBUSDT", r.URL.Query().Get("symbol"))
+			w.Header().Set("Content-Type", "application/json")
+			w.Write([]byte(shibTicker))
+		}))
+		defer server.Close()
+
+		ticker, err := NewClient(server.URL, nil).GetTicker24hr(ctx, "SHIBUSDT")
+		require.NoError(t, err)
+
+		assert.Equal(t, "0.00001234", ticker.LastPrice.String())
+		assert.True(t, ticker.LastPrice.Equal(decimal.RequireFromString("0.000012340000")))
+		assert.Equal(t, "0.00000001", ticker.PriceChange.String())
+		assert.Equal(t, "0.081", ticker.PriceChangePercent.String())
+		assert.Equal(t, "0.0000125", ticker.HighPrice.String())
+		assert.Equal(t, "0.0000121", ticker.LowPrice.String())
+		assert.Equal(t, "98765432109876.54", ticker.Volume.String())
+		assert.Equal(t, "1218765432.10987654321", ticker.QuoteVolume.String(), "digits beyond float64 precision must survive")
+	})
+
+	t.Run("all symbols", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			assert.Equal(t, "/api/v3/ticker/24hr", r.URL.Path)
+			assert.Empty(t, r.URL.RawQuery)
+			w.Header().Set("Content-Type", "application/json")
+			w.Write([]byte("[" + btcTicker + "," + shibTicker + "]"))
+		}))
+		defer server.Close()
+
+		tickers, err := NewClient(server.URL, nil).GetAllTickers24hr(ctx)
+		require.NoError(t, err)
+		require.Len(t, tickers, 2)
+
+		assert.Equal(t, "BTCUSDT", tickers[0].Symbol)
+		assert.Equal(t, "-94.999998", tickers[0].PriceChange.String())
+		assert.Equal(t, int64(76), tickers[0].Count)
+		assert.Equal(t, "SHIBUSDT", tickers[1].Symbol)
+		assert.Equal(t, "0.00001234", tickers[1].LastPrice.String())
+	})
+}
+
+func TestClient_GetAvgPrice(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/api/v3/avgPrice", r.URL.Path)
+		assert.Equal(t, "BTCUSDT", r.URL.Query().Get("symbol"))
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"mins":5,"price":"9.35751834","closeTime":1694061154503}`))
+	}))
+	defer server.Close()
+
+	client := NewClient(server.URL, nil)
+	avgPrice, err := client.GetAvgPrice(context.Background(), "BTCUSDT")
+	require.NoError(t, err)
+	assert.Equal(t, 5, avgPrice.Mins)
+	assert.Equal(t, "9.35751834", avgPrice.Price.String())
+	assert.Equal(t, int64(1694061154503), avgPrice.CloseTime)
+
+	_, err = client.GetAvgPrice(context.Background(), "")
+	assert.ErrorContains(t, err, "symbol is required")
+}
+
+func TestClient_GetRecentTrades(t *testing.T) {
+	ctx := context.Background()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/api/v3/trades", r.URL.Path)
+		assert.Equal(t, "ETHUSDT", r.URL.Query().Get("symbol"))
+		assert.Equal(t, "1000", r.URL.Query().Get("limit"))
+		assert.NotContains(t, r.URL.RawQuery, "signature=")
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`[{"id":28457,"price":"4.00000100","qty":"12.00000000","quoteQty":"48.000012",
+			"time":1499865549590,"isBuyerMaker":true,"isBestMatch":true}]`))
+	}))
+	defer server.Close()
+
+	client := NewClient(server.URL, auth.NewSigner("key", "secret"))
+	trades, err := client.GetRecentTrades(ctx, "ETHUSDT", 1000)
+	require.NoError(t, err)
+	require.Len(t, trades, 1)
+
+	assert.Equal(t, int64(28457), trades[0].ID)
+	assert.Equal(t, "4.000001", trades[0].Price.String())
+	assert.Equal(t, "12", trades[0].Quantity.String())
+	assert.Equal(t, "48.000012", trades[0].QuoteQty.String())
+	assert.Equal(t, int64(1499865549590), trades[0].Time)
+	assert.True(t, trades[0].IsBuyerMaker)
+	assert.True(t, trades[0].IsBestMatch)
+
+	for _, limit := range []int{-1, 1001} {
+		_, err := client.GetRecentTrades(ctx, "ETHUSDT", limit)
+		assert.ErrorContains(t, err, fmt.Sprintf("invalid limit: %d", limit))
+	}
+	_, err = client.GetRecentTrades(ctx, "", 10)
+	assert.ErrorContains(t, err, "symbol is required")
+}
+
+func TestClient_GetAggTrades(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("parses trades and sends options", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			assert.Equal(t, "/api/v3/aggTrades", r.URL.Path)
+			query := r.URL.Query()
+			assert.Equal(t, "BTCUSDT", query.Get("symbol"))
+			assert.Equal(t, "1700000000000", query.Get("startTime"))
+			assert.Equal(t, "1700003600000", query.Get("endTime"))
+			assert.Equal(t, "2", query.Get("limit"))
+			assert.False(t, query.Has("fromId"))
+			w.Header().Set("Content-Type", "application/json")
+			w.Write([]byte(`[
+				{"a":26129,"p":"0.01633102","q":"4.70443515","f":27781,"l":27781,"T":1498793709153,"m":true,"M":true},
+				{"a":26130,"p":"0.01633103","q":"0.5","f":27782,"l":27784,"T":1498793709160,"m":false,"M":true}
+			]`))
+		}))
+		defer server.Close()
+
+		start := time.UnixMilli(1700000000000)
+		trades, err := NewClient(server.URL, nil).GetAggTrades(ctx, "BTCUSDT", AggTradeOptions{
+			StartTime: start,
+			EndTime:   start.Add(time.Hour),
+			Limit:     2,
+		})
+		require.NoError(t, err)
+		require.Len(t, trades, 2)
+
+		assert.Equal(t, int64(26129), trades[0].ID)
+		assert.Equal(t, "0.01633102", trades[0].Price.String())
+		assert.Equal(t, "4.70443515", trades[0].Quantity.String())
+		assert.Equal(t, int64(1498793709153), trades[0].Time)
+		assert.True(t, trades[0].IsBuyerMaker)
+		assert.Equal(t, int64(27782), trades[1].FirstTradeID)
+		assert.Equal(t, int64(27784), trades[1].LastTradeID)
+		assert.False(t, trades[1].IsBuyerMaker)
+	})
+
+	t.Run("sends fromId alone", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			assert.Equal(t, "26129", r.URL.Query().Get("fromId"))
+			assert.False(t, r.URL.Query().Has("limit"))
+			w.Write([]byte(`[]`))
+		}))
+		defer server.Close()
+
+		trades, err := NewClient(server.URL, nil).GetAggTrades(ctx, "BTCUSDT", AggTradeOptions{FromID: 26129})
+		require.NoError(t, err)
+		assert.Empty(t, trades)
+	})
+
+	t.Run("rejects invalid options before calling Binance", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			t.Errorf("unexpected request %s", r.URL)
+		}))
+		defer server.Close()
+
+		now := time.Now()
+		tests := []struct {
+			name    string
+			symbol  string
+			opts    AggTradeOptions
+			wantErr string
+		}{
+			{"missing symbol", "", AggTradeOptions{}, "symbol is required"},
+			{"limit above maximum", "BTCUSDT", AggTradeOptions{Limit: 1001}, "invalid limit: 1001"},
+			{"negative limit", "BTCUSDT", AggTradeOptions{Limit: -1}, "invalid limit: -1"},
+			{"fromId with startTime", "BTCUSDT", AggTradeOptions{FromID: 1, StartTime: now}, "fromId cannot be combined"},
+			{"fromId with endTime", "BTCUSDT", AggTradeOptions{FromID: 1, EndTime: now}, "fromId cannot be combined"},
+			{"reversed window", "BTCUSDT", AggTradeOptions{StartTime: now, EndTime: now.Add(-time.Second)}, "is before startTime"},
+			{"window over an hour", "BTCUSDT", AggTradeOptions{StartTime: now, EndTime: now.Add(time.Hour + time.Millisecond)}, "at most an hour apart"},
+		}
+
+		client := NewClient(server.URL, nil)
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				_, err := client.GetAggTrades(ctx, tt.symbol, tt.opts)
+				assert.ErrorContains(t, err, tt.wantErr)
+			})
+		}
+	})
+}
+
 func TestClient_GetOpenOrders(t *testing.T) {
 	t.Run("returns empty slice when no orders", func(t *testing.T) {
 		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
@@ -772,6 +1762,42 @@ func TestClient_GetOpenOrders(t *testing.T) {
 	})
 }
 
+type durationSample struct {
+	method, endpoint string
+	seconds          float64
+}
+
+type fakeDurationRecorder struct {
+	mu      sync.Mutex
+	samples []durationSample
+}
+
+func (r *fakeDurationRecorder) RecordHTTPDuration(method, endpoint string, duration float64) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.samples = append(r.samples, durationSample{method, endpoint, duration})
+}
+
+func TestClient_WithMetrics(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		time.Sleep(10 * time.Millisecond)
+		w.Write([]byte(`{}`))
+	}))
+	defer server.Close()
+
+	recorder := &fakeDurationRecorder{}
+	client := NewClient(server.URL, nil, WithMetrics(recorder))
+
+	_, err := client.doRequest(context.Background(), "GET", "/api/v3/depth?symbol=BTCUSDT", nil, false)
+	require.NoError(t, err)
+
+	require.Len(t, recorder.samples, 1)
+	sample := recorder.samples[0]
+	assert.Equal(t, "binance", sample.method)
+	assert.Equal(t, "/api/v3/depth", sample.endpoint)
+	assert.GreaterOrEqual(t, sample.seconds, 0.01)
+}
+
 func TestClient_DoRequest(t *testing.T) {
 	t.Run("retries with exponential backoff", func(t *testing.T) {
 		callCount := 0
@@ -835,6 +1861,90 @@ func TestClient_DoRequest(t *testing.T) {
 		assert.Contains(t, err.Error(), "HTTP 503")
 	})
 
+	t.Run("stops backing off when context is cancelled", func(t *testing.T) {
+		var callCount int32
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			atomic.AddInt32(&callCount, 1)
+			w.WriteHeader(503)
+		}))
+		defer server.Close()
+
+		client := NewClient(server.URL, nil, WithMaxRetries(5))
+		ctx, cancel := context.WithCancel(context.Background())
+		time.AfterFunc(30*time.Millisecond, cancel)
+
+		start := time.Now()
+		_, err := client.doRequest(ctx, "GET", "/test", url.Values{}, false)
+		elapsed := time.Since(start)
+
+		assert.ErrorIs(t, err, context.Canceled)
+		// The first backoff alone is ~100ms; cancellation must cut it short
+		assert.Less(t, elapsed, 75*time.Millisecond)
+		assert.Equal(t, int32(1), atomic.LoadInt32(&callCount))
+	})
+
+	t.Run("gives up once max retry duration is spent", func(t *testing.T) {
+		var callCount int32
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			atomic.AddInt32(&callCount, 1)
+			w.WriteHeader(503)
+		}))
+		defer server.Close()
+
+		budget := 250 * time.Millisecond
+		client := NewClient(server.URL, nil, WithMaxRetries(10), WithMaxRetryDuration(budget))
+
+		start := time.Now()
+		_, err := client.doRequest(context.Background(), "GET", "/test", url.Values{}, false)
+		elapsed := time.Since(start)
+
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "HTTP 503")
+		assert.Less(t, elapsed, budget)
+		// ~100ms + ~200ms backoffs cannot both fit in the budget
+		assert.Less(t, atomic.LoadInt32(&callCount), int32(4))
+	})
+
+	t.Run("retries transient futures backend timeout", func(t *testing.T) {
+		callCount := 0
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			callCount++
+			if callCount == 1 {
+				w.WriteHeader(http.StatusServiceUnavailable)
+				w.Write([]byte(`{"code":-1007,"msg":"Timeout waiting for response from backend server."}`))
+				return
+			}
+			w.WriteHeader(200)
+			w.Write([]byte(`{"orderId":1}`))
+		}))
+		defer server.Close()
+
+		client := NewClient(server.URL, nil, WithMaxRetries(2))
+		body, err := client.doRequest(context.Background(), "POST", "/fapi/v1/order", url.Values{}, false)
+
+		require.NoError(t, err)
+		assert.Contains(t, string(body), "orderId")
+		assert.Equal(t, 2, callCount)
+	})
+
+	t.Run("does not retry futures min notional rejection", func(t *testing.T) {
+		callCount := 0
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			callCount++
+			w.WriteHeader(http.StatusBadRequest)
+			w.Write([]byte(`{"code":-4164,"msg":"Order's notional must be no smaller than 5.0"}`))
+		}))
+		defer server.Close()
+
+		client := NewClient(server.URL, nil, WithMaxRetries(2))
+		_, err := client.doRequest(context.Background(), "POST", "/fapi/v1/order", url.Values{}, false)
+
+		var binanceErr *BinanceError
+		require.ErrorAs(t, err, &binanceErr)
+		assert.Equal(t, -4164, binanceErr.Code)
+		assert.Equal(t, 1, callCount)
+	})
+
 	t.Run("respects rate limit", func(t *testing.T) {
 		requestTimes := make([]time.Time, 0)
 		var mu sync.Mutex
@@ -970,7 +2080,8 @@ func TestClient_ConcurrentRequests(t *testing.T) {
 		}))
 		defer server.Close()
 
-		client := NewClient(server.URL, nil, WithRateLimit(1000, 100)) // High limits
+		// High limits, and no caching so every call reaches the server
+		client := NewClient(server.URL, nil, WithRateLimit(1000, 100), WithExchangeInfoTTL(0))
 		ctx := context.Background()
 
 		const numGoroutines = 20
@@ -1058,3 +2169,158 @@ func TestClient_ParsesAPIErrors(t *testing.T) {
 		}
 	})
 }
+
+// largeExchangeInfo builds a synthetic exchangeInfo payload shaped like the
+// full mainnet response
+func largeExchangeInfo(symbols int) []byte {
+	var b strings.Builder
+	b.WriteString(`{"timezone":"UTC","serverTime":1700000000000,"rateLimits":[{"rateLimitType":"REQUEST_WEIGHT","interval":"MINUTE","limit":6000}],"exchangeFilters":[],"symbols":[`)
+	for i := 0; i < symbols; i++ {
+		if i > 0 {
+			b.WriteByte(',')
+		}
+		fmt.Fprintf(&b, `{"symbol":"SYM%dUSDT","status":"TRADING","baseAsset":"SYM%d","baseAssetPrecision":8,"quoteAsset":"USDT","quoteAssetPrecision":8,"orderTypes":["LIMIT","LIMIT_MAKER","MARKET","STOP_LOSS_LIMIT","TAKE_PROFIT_LIMIT"],"icebergAllowed":true,"ocoAllowed":true,"isSpotTradingAllowed":true,"isMarginTradingAllowed":false,"permissions":["SPOT"],"filters":[{"filterType":"PRICE_FILTER","minPrice":"0.01000000","maxPrice":"1000000.00000000","tickSize":"0.01000000"},{"filterType":"LOT_SIZE","minQty":"0.00001000","maxQty":"9000.00000000","stepSize":"0.00001000"},{"filterType":"NOTIONAL","minNotional":"5.00000000","maxNotional":"9000000.00000000"}]}`, i, i)
+	}
+	b.WriteString(`]}`)
+	return []byte(b.String())
+}
+
+func TestDecodeExchangeInfo(t *testing.T) {
+	payload := largeExchangeInfo(50)
+
+	var want ExchangeInfo
+	require.NoError(t, json.Unmarshal(payload, &want))
+
+	var got ExchangeInfo
+	require.NoError(t, decodeExchangeInfo(bytes.NewReader(payload), &got))
+	assert.Equal(t, want, got)
+	assert.Len(t, got.Symbols, 50)
+
+	t.Run("rejects malformed payload", func(t *testing.T) {
+		var info ExchangeInfo
+		assert.Error(t, decodeExchangeInfo(strings.NewReader(`{"symbols":[{"symbol":`), &info))
+		assert.Error(t, decodeExchangeInfo(strings.NewReader(`[]`), &info))
+	})
+}
+
+func TestGetExchangeInfo_RetriesTruncatedBody(t *testing.T) {
+	payload := largeExchangeInfo(20)
+	var calls int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set("Content-Length", strconv.Itoa(len(payload)))
+		if atomic.AddInt32(&calls, 1) == 1 {
+			// Promise the full payload but drop the connection halfway
+			w.Write(payload[:len(payload)/2])
+			return
+		}
+		w.Write(payload)
+	}))
+	defer server.Close()
+
+	info, err := NewClient(server.URL, nil, WithMaxRetries(1)).GetExchangeInfo(context.Background())
+	require.NoError(t, err)
+	assert.Len(t, info.Symbols, 20)
+	assert.Equal(t, "UTC", info.Timezone)
+	assert.Equal(t, int32(2), atomic.LoadInt32(&calls))
+
+	t.Run("does not retry a malformed payload", func(t *testing.T) {
+		var calls int32
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			atomic.AddInt32(&calls, 1)
+			w.Write([]byte(`{"symbols":{}}`))
+		}))
+		defer server.Close()
+
+		_, err := NewClient(server.URL, nil, WithMaxRetries(2)).GetExchangeInfo(context.Background())
+		assert.Error(t, err)
+		assert.Equal(t, int32(1), atomic.LoadInt32(&calls))
+	})
+}
+
+func BenchmarkDecodeExchangeInfo(b *testing.B) {
+	payload := largeExchangeInfo(3000)
+
+	b.Run("buffered", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			body, err := io.ReadAll(bytes.NewReader(payload))
+			if err != nil {
+				b.Fatal(err)
+			}
+			var info ExchangeInfo
+			if err := json.Unmarshal(body, &info); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+
+	b.Run("streaming", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			var info ExchangeInfo
+			if err := decodeExchangeInfo(bytes.NewReader(payload), &info); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+}
+
+// BenchmarkClient_ConcurrentRequests compares NewTransport with a pool limited
+// to Go's default of two idle connections per host. Each op is a burst of
+// concurrent requests, like a bracket's legs or a batch of signals. After a
+// burst the small pool keeps only two connections, so the next burst redials
+// the rest; dials/op shows this even where loopback handshakes are too cheap
+// to move ns/op. Against Binance each dial is also a TLS handshake.
+func BenchmarkClient_ConcurrentRequests(b *testing.B) {
+	const burst = 16
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// Keep the burst's requests in flight together, as network latency would
+		time.Sleep(time.Millisecond)
+		w.Write([]byte(`{}`))
+	}))
+	defer server.Close()
+
+	for _, bc := range []struct {
+		name        string
+		idlePerHost int
+	}{
+		{"default_pool", http.DefaultMaxIdleConnsPerHost},
+		{"tuned_pool", DefaultMaxIdleConnsPerHost},
+	} {
+		b.Run(bc.name, func(b *testing.B) {
+			var dials atomic.Int64
+			dialer := &net.Dialer{}
+			transport := NewTransport()
+			transport.MaxIdleConnsPerHost = bc.idlePerHost
+			transport.DialContext = func(ctx context.Context, network, addr string) (net.Conn, error) {
+				dials.Add(1)
+				return dialer.DialContext(ctx, network, addr)
+			}
+			defer transport.CloseIdleConnections()
+
+			client := NewClient(server.URL, nil,
+				WithTransport(transport),
+				WithRateLimit(1e9, 1e6),
+				WithMaxRetries(0))
+
+			b.ReportAllocs()
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				var wg sync.WaitGroup
+				for j := 0; j < burst; j++ {
+					wg.Add(1)
+					go func() {
+						defer wg.Done()
+						if err := client.Ping(context.Background()); err != nil {
+							b.Error(err)
+						}
+					}()
+				}
+				wg.Wait()
+			}
+			b.ReportMetric(float64(dials.Load())/float64(b.N), "dials/op")
+		})
+	}
+}
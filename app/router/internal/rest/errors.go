@@ -10,11 +10,19 @@ import (
 	"strings"
 )
 
+// ErrNetwork marks failures to reach the Binance API at all (DNS, dial,
+// reset connections, transport timeouts), as opposed to API rejections
+var ErrNetwork = errors.New("network error")
+
 // BinanceError represents an error response from Binance API
 type BinanceError struct {
 	Code       int    `json:"code"`
 	Message    string `json:"msg"`
 	HTTPStatus int    `json:"-"`
+
+	// Data carries endpoint-specific detail some errors attach, such as the
+	// per-leg results of a failed cancel-replace
+	Data json.RawMessage `json:"data,omitempty"`
 }
 
 // Error implements the error interface
@@ -24,8 +32,17 @@ func (e *BinanceError) Error() string {
 
 // IsRetryable determines if this error should trigger a retry
 func (e *BinanceError) IsRetryable() bool {
+	// Order validation failures will fail the same way on every attempt
+	if e.IsFilterRejection() {
+		return false
+	}
+
 	retryableCodes := map[int]bool{
+		-1001: true, // Internal error; unable to process request (DISCONNECTED)
 		-1003: true, // Too many requests
+		-1006: true, // Unexpected response from message bus
+		-1007: true, // Timeout waiting for backend server response
+		-1008: true, // Server overloaded (futures)
 		-1021: true, // Timestamp outside recv window
 	}
 	return retryableCodes[e.Code]
@@ -43,7 +60,64 @@ func (e *BinanceError) IsAuthError() bool {
 
 // IsRateLimitError checks if this is a rate limiting error
 func (e *BinanceError) IsRateLimitError() bool {
-	return e.Code == -1003
+	return e.IsRateLimit()
+}
+
+// IsRateLimit checks if the request was rejected for exceeding a rate limit
+func (e *BinanceError) IsRateLimit() bool {
+	switch e.Code {
+	case -1003, // Too many requests
+		-1015: // Too many new orders
+		return true
+	}
+	return false
+}
+
+// IsFilterRejection checks if the order failed a symbol filter
+// (price, lot size, notional, ...)
+func (e *BinanceError) IsFilterRejection() bool {
+	switch e.Code {
+	case -1013, // Filter failure
+		-4131, // Futures PERCENT_PRICE
+		-4164: // Futures MIN_NOTIONAL
+		return true
+	}
+	return false
+}
+
+// IsInsufficientBalance checks if the account lacks funds for the order
+func (e *BinanceError) IsInsufficientBalance() bool {
+	switch e.Code {
+	case -2010: // Account has insufficient balance
+		return !e.IsDuplicateOrder()
+	case -2019: // Futures margin is insufficient
+		return true
+	}
+	return false
+}
+
+// IsDuplicateOrder checks if the order was rejected because its client order
+// ID is already in use, typically by an earlier attempt of the same request
+func (e *BinanceError) IsDuplicateOrder() bool {
+	switch e.Code {
+	case -4116: // Futures ClientOrderId is duplicated
+		return true
+	case -2010, -1013:
+		// Spot reports duplicates under generic rejection codes, so the
+		// message tells them apart
+		return strings.Contains(strings.ToLower(e.Message), "duplicate")
+	}
+	return false
+}
+
+// IsUnknownOrder checks if the referenced order does not exist
+func (e *BinanceError) IsUnknownOrder() bool {
+	switch e.Code {
+	case -2011, // Unknown order sent
+		-2013: // Order does not exist
+		return true
+	}
+	return false
 }
 
 // IsOrderError checks if this is an order-related error
@@ -127,13 +201,24 @@ func IsRetryableError(err error) bool {
 	return false
 }
 
-// ErrorWithContext wraps errors with operation context for better debugging
+// wrapNetworkError tags a transport failure with ErrNetwork. Errors caused by
+// the caller cancelling ctx are returned unchanged.
+func wrapNetworkError(ctx context.Context, err error) error {
+	if err == nil || ctx.Err() != nil {
+		return err
+	}
+	return fmt.Errorf("%w: %w", ErrNetwork, err)
+}
+
+// ErrorWithContext wraps errors with operation context for better debugging.
+// Signatures and API keys are masked so signed query strings never leak, and
+// the wrapped error stays reachable through errors.Is and errors.As.
 func ErrorWithContext(err error, operation string) error {
 	if err == nil {
 		return nil
 	}
 
-	return fmt.Errorf("%s: %w", operation, err)
+	return redactError(fmt.Errorf("%s: %w", operation, err))
 }
 
 // APIError is an alias for BinanceError for backwards compatibility
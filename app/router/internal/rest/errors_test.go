@@ -6,6 +6,7 @@ import (
 	"fmt"
 	"io"
 	"net/http"
+	"net/http/httptest"
 	"strings"
 	"testing"
 
@@ -53,6 +54,70 @@ func TestBinanceError(t *testing.T) {
 	})
 }
 
+func TestBinanceError_Categories(t *testing.T) {
+	type categories struct {
+		rateLimit, auth, filter, balance, unknownOrder bool
+	}
+
+	tests := []struct {
+		code int
+		want categories
+	}{
+		{code: -1003, want: categories{rateLimit: true}},
+		{code: -1015, want: categories{rateLimit: true}},
+		{code: -2014, want: categories{auth: true}},
+		{code: -2015, want: categories{auth: true}},
+		{code: -1022, want: categories{auth: true}},
+		{code: -1013, want: categories{filter: true}},
+		{code: -4131, want: categories{filter: true}},
+		{code: -4164, want: categories{filter: true}},
+		{code: -2010, want: categories{balance: true}},
+		{code: -2019, want: categories{balance: true}},
+		{code: -2011, want: categories{unknownOrder: true}},
+		{code: -2013, want: categories{unknownOrder: true}},
+		{code: -1021, want: categories{}},
+		{code: -1000, want: categories{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(fmt.Sprintf("code %d", tt.code), func(t *testing.T) {
+			err := &BinanceError{Code: tt.code}
+			got := categories{
+				rateLimit:    err.IsRateLimit(),
+				auth:         err.IsAuthError(),
+				filter:       err.IsFilterRejection(),
+				balance:      err.IsInsufficientBalance(),
+				unknownOrder: err.IsUnknownOrder(),
+			}
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestBinanceError_IsDuplicateOrder(t *testing.T) {
+	tests := []struct {
+		code int
+		msg  string
+		want bool
+	}{
+		{code: -2010, msg: "Duplicate order sent.", want: true},
+		{code: -1013, msg: "Duplicate order sent.", want: true},
+		{code: -4116, msg: "ClientOrderId is duplicated.", want: true},
+		{code: -2010, msg: "Account has insufficient balance for requested action.", want: false},
+		{code: -1013, msg: "Filter failure: LOT_SIZE", want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(fmt.Sprintf("code %d %s", tt.code, tt.msg), func(t *testing.T) {
+			err := &BinanceError{Code: tt.code, Message: tt.msg}
+			assert.Equal(t, tt.want, err.IsDuplicateOrder())
+			if tt.code == -2010 {
+				assert.Equal(t, !tt.want, err.IsInsufficientBalance())
+			}
+		})
+	}
+}
+
 func TestParseAPIError(t *testing.T) {
 	t.Run("parses valid binance error response", func(t *testing.T) {
 		jsonResponse := `{"code":-1021,"msg":"Timestamp outside of recv window."}`
@@ -131,8 +196,14 @@ func TestParseAPIError(t *testing.T) {
 		}{
 			{-1003, "Too many requests", true},
 			{-1021, "Timestamp outside recv window", true},
+			{-1001, "Internal error; unable to process your request", true},
+			{-1007, "Timeout waiting for response from backend server", true},
+			{-1008, "Server is currently overloaded", true},
 			{-1022, "Invalid signature", false},
 			{-2010, "Account has insufficient balance", false},
+			{-1013, "Filter failure: LOT_SIZE", false},
+			{-4131, "The counterparty's best price does not meet the PERCENT_PRICE filter limit", false},
+			{-4164, "Order's notional must be no smaller than 5.0", false},
 		}
 
 		for _, tc := range testCases {
@@ -248,6 +319,44 @@ func TestErrorWithContext(t *testing.T) {
 		assert.Equal(t, -1021, binanceErr.Code)
 	})
 
+	t.Run("preserves binance error type when redacting", func(t *testing.T) {
+		originalErr := fmt.Errorf("GET /api/v3/order?signature=abc123: %w", &BinanceError{Code: -2013, Message: "Order does not exist."})
+
+		wrappedErr := ErrorWithContext(originalErr, "GetOrder")
+
+		assert.NotContains(t, wrappedErr.Error(), "abc123")
+		var binanceErr *BinanceError
+		assert.True(t, errors.As(wrappedErr, &binanceErr))
+		assert.True(t, binanceErr.IsUnknownOrder())
+	})
+
+	t.Run("preserves network error sentinel", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+		server.Close()
+
+		client := NewClient(server.URL, nil, WithMaxRetries(0))
+		_, err := client.GetExchangeInfo(context.Background())
+		wrappedErr := ErrorWithContext(err, "GetExchangeInfo")
+
+		assert.True(t, errors.Is(wrappedErr, ErrNetwork))
+		var binanceErr *BinanceError
+		assert.False(t, errors.As(wrappedErr, &binanceErr))
+	})
+
+	t.Run("does not tag caller cancellation as network error", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+		defer server.Close()
+
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+
+		client := NewClient(server.URL, nil, WithMaxRetries(0))
+		_, err := client.GetExchangeInfo(ctx)
+
+		assert.ErrorIs(t, err, context.Canceled)
+		assert.False(t, errors.Is(err, ErrNetwork))
+	})
+
 	t.Run("handles nil error", func(t *testing.T) {
 		wrappedErr := ErrorWithContext(nil, "SomeOperation")
 		assert.NoError(t, wrappedErr)
@@ -129,6 +129,46 @@ func TestPlaceFuturesOrder_StopMarket(t *testing.T) {
 	assert.Equal(t, "STOP_MARKET", resp.Type)
 }
 
+func TestPlaceFuturesOrder_SelfTradePrevention(t *testing.T) {
+	t.Run("forwards a valid mode", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			assert.Equal(t, STPModeExpireMaker, r.URL.Query().Get("selfTradePreventionMode"))
+			json.NewEncoder(w).Encode(&FuturesOrderResponse{OrderID: 1, Symbol: "BTCUSDT", Status: "NEW"})
+		}))
+		defer server.Close()
+
+		client := NewClient(server.URL, auth.NewSigner("test-api-key", "test-secret"))
+		_, err := client.PlaceFuturesOrder(context.Background(), &FuturesOrderRequest{
+			Symbol:                  "BTCUSDT",
+			Side:                    "BUY",
+			Type:                    "LIMIT",
+			TimeInForce:             "GTC",
+			Quantity:                decimal.RequireFromString("0.001"),
+			Price:                   decimal.RequireFromString("50000"),
+			SelfTradePreventionMode: STPModeExpireMaker,
+		})
+		require.NoError(t, err)
+	})
+
+	t.Run("rejects an unknown mode locally", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			t.Error("request should not reach the server")
+		}))
+		defer server.Close()
+
+		client := NewClient(server.URL, auth.NewSigner("test-api-key", "test-secret"))
+		_, err := client.PlaceFuturesOrder(context.Background(), &FuturesOrderRequest{
+			Symbol:                  "BTCUSDT",
+			Side:                    "BUY",
+			Type:                    "MARKET",
+			Quantity:                decimal.RequireFromString("0.001"),
+			SelfTradePreventionMode: "EXPIRE_ALL",
+		})
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "invalid selfTradePreventionMode")
+	})
+}
+
 func TestPlaceFuturesOrder_ValidationErrors(t *testing.T) {
 	signer := auth.NewSigner("test-api-key", "test-secret")
 	client := NewClient("http://localhost", signer)
@@ -194,6 +234,26 @@ func TestPlaceFuturesOrder_ValidationErrors(t *testing.T) {
 			},
 			err: "stopPrice is required for STOP",
 		},
+		{
+			name: "take profit market without stop price",
+			req: &FuturesOrderRequest{
+				Symbol:   "BTCUSDT",
+				Side:     "SELL",
+				Type:     "TAKE_PROFIT_MARKET",
+				Quantity: decimal.RequireFromString("1"),
+			},
+			err: "stopPrice is required for TAKE_PROFIT_MARKET orders",
+		},
+		{
+			name: "trailing stop without callback rate",
+			req: &FuturesOrderRequest{
+				Symbol:   "BTCUSDT",
+				Side:     "SELL",
+				Type:     "TRAILING_STOP_MARKET",
+				Quantity: decimal.RequireFromString("1"),
+			},
+			err: "callbackRate is required",
+		},
 	}
 
 	for _, tt := range tests {
@@ -277,6 +337,104 @@ func TestGetFuturesAccount_Success(t *testing.T) {
 	assert.Len(t, resp.Positions, 1)
 }
 
+func TestGetFuturesAccount_ParsesBinanceResponse(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{
+			"totalWalletBalance": "23.72469206",
+			"totalUnrealizedProfit": "0.00000000",
+			"totalMarginBalance": "23.72469206",
+			"availableBalance": "23.72469206",
+			"maxWithdrawAmount": "23.72469206",
+			"updateTime": 0,
+			"assets": [
+				{"asset": "USDT", "walletBalance": "23.72469206", "unrealizedProfit": "0.00000000",
+				 "marginBalance": "23.72469206", "availableBalance": "23.72469206", "maxWithdrawAmount": "23.72469206"},
+				{"asset": "BUSD", "walletBalance": "103.12345678", "unrealizedProfit": "0.00000000",
+				 "marginBalance": "103.12345678", "availableBalance": "126.72469206", "maxWithdrawAmount": "103.12345678"}
+			],
+			"positions": [
+				{"symbol": "BTCUSDT", "initialMargin": "0", "maintMargin": "0", "unrealizedProfit": "0.00000000",
+				 "leverage": "100", "isolated": true, "entryPrice": "0.00000", "maxNotional": "250000",
+				 "positionSide": "BOTH", "positionAmt": "0", "updateTime": 0},
+				{"symbol": "ETHUSDT", "initialMargin": "19.5", "maintMargin": "0.78", "unrealizedProfit": "-1.25",
+				 "leverage": "20", "isolated": false, "entryPrice": "1950.00", "maxNotional": "1000000",
+				 "positionSide": "BOTH", "positionAmt": "0.200", "updateTime": 1700000000000}
+			]
+		}`))
+	}))
+	defer server.Close()
+
+	client := NewClient(server.URL, auth.NewSigner("test-api-key", "test-secret"))
+	account, err := client.GetFuturesAccount(context.Background())
+	require.NoError(t, err)
+
+	assert.Equal(t, "23.72469206", account.TotalWalletBalance.String())
+	assert.True(t, account.TotalUnrealizedProfit.IsZero())
+	assert.Equal(t, "23.72469206", account.AvailableMargin().String())
+	require.Len(t, account.Assets, 2)
+	assert.Equal(t, "BUSD", account.Assets[1].Asset)
+	assert.Equal(t, "126.72469206", account.Assets[1].AvailableBalance.String())
+
+	eth := account.PositionFor("ETHUSDT")
+	require.NotNil(t, eth)
+	assert.Equal(t, "20", eth.Leverage)
+	assert.Equal(t, "0.2", eth.PositionAmt.String())
+	assert.Equal(t, "-1.25", eth.UnrealizedProfit.String())
+	assert.Nil(t, account.PositionFor("SOLUSDT"))
+
+	// A negative available balance leaves no margin for new orders
+	account.AvailableBalance = decimal.RequireFromString("-5")
+	assert.True(t, account.AvailableMargin().IsZero())
+}
+
+func TestGetMarkPrice(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/fapi/v1/premiumIndex", r.URL.Path)
+		assert.Equal(t, "BTCUSDT", r.URL.Query().Get("symbol"))
+		w.Write([]byte(`{"symbol":"BTCUSDT","markPrice":"11793.63104562","indexPrice":"11781.80495970",
+			"lastFundingRate":"0.00038246","nextFundingTime":1597392000000,"time":1597370495002}`))
+	}))
+	defer server.Close()
+
+	client := NewClient(server.URL, nil)
+	markPrice, err := client.GetMarkPrice(context.Background(), "BTCUSDT")
+	require.NoError(t, err)
+	assert.Equal(t, "11793.63104562", markPrice.MarkPrice.String())
+	assert.Equal(t, int64(1597392000000), markPrice.NextFundingTime)
+
+	_, err = client.GetMarkPrice(context.Background(), "")
+	assert.ErrorContains(t, err, "symbol is required")
+}
+
+func TestGetFuturesExchangeInfo_Success(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "GET", r.Method)
+		assert.Equal(t, "/fapi/v1/exchangeInfo", r.URL.Path)
+		assert.NotContains(t, r.URL.RawQuery, "signature=")
+
+		w.Write([]byte(`{"timezone":"UTC","serverTime":1,"symbols":[{"symbol":"BTCUSDT","status":"TRADING",
+			"pricePrecision":2,"quantityPrecision":3,"filters":[
+			{"filterType":"PRICE_FILTER","minPrice":"556.80","maxPrice":"4529764","tickSize":"0.10"},
+			{"filterType":"LOT_SIZE","minQty":"0.001","maxQty":"1000","stepSize":"0.001"},
+			{"filterType":"MIN_NOTIONAL","notional":"100"},
+			{"filterType":"MAX_NUM_ORDERS","limit":200}]}]}`))
+	}))
+	defer server.Close()
+
+	client := NewClient(server.URL, nil)
+
+	info, err := client.GetFuturesExchangeInfo(context.Background())
+	require.NoError(t, err)
+	require.Len(t, info.Symbols, 1)
+
+	symbol := info.Symbols[0]
+	assert.Equal(t, 2, symbol.PricePrecision)
+	assert.Equal(t, 3, symbol.QuantityPrecision)
+	assert.Equal(t, "0.1", symbol.TickSize().String())
+	assert.Equal(t, "100", symbol.MinNotional().String())
+	assert.Equal(t, 200, symbol.MaxNumOrders())
+}
+
 func TestPlaceFuturesOrder_RetryOn5xx(t *testing.T) {
 	attempts := 0
 	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
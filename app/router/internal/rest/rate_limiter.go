@@ -36,6 +36,15 @@ func (rl *RateLimiter) Burst() int {
 	return rl.burst
 }
 
+// Available returns the number of tokens currently in the bucket
+func (rl *RateLimiter) Available() float64 {
+	rl.mu.Lock()
+	defer rl.mu.Unlock()
+
+	rl.refillTokens()
+	return rl.tokens
+}
+
 // TryAcquire attempts to acquire a token without blocking
 func (rl *RateLimiter) TryAcquire() bool {
 	rl.mu.Lock()
@@ -0,0 +1,99 @@
+package rest
+
+import (
+	"errors"
+	"net/url"
+	"regexp"
+	"strings"
+)
+
+// redactedValue replaces sensitive values in logs and error messages
+const redactedValue = "REDACTED"
+
+// sensitiveParams lists query parameters that must never be logged (lowercase)
+var sensitiveParams = map[string]bool{
+	"signature":    true,
+	"secret":       true,
+	"secretkey":    true,
+	"apikey":       true,
+	"x-mbx-apikey": true,
+}
+
+// sensitivePattern matches sensitive key=value pairs embedded in free text
+var sensitivePattern = regexp.MustCompile(`(?i)\b(signature|secret|secretkey|apikey|x-mbx-apikey)=([^&\s"']*)`)
+
+// redactParams returns a copy of params with sensitive values masked
+func redactParams(params url.Values) url.Values {
+	if params == nil {
+		return nil
+	}
+
+	redacted := make(url.Values, len(params))
+	for key, values := range params {
+		if sensitiveParams[strings.ToLower(key)] {
+			masked := make([]string, len(values))
+			for i := range masked {
+				masked[i] = redactedValue
+			}
+			redacted[key] = masked
+			continue
+		}
+		redacted[key] = append([]string(nil), values...)
+	}
+	return redacted
+}
+
+// redactURL masks sensitive query parameters in a URL string
+func redactURL(rawURL string) string {
+	u, err := url.Parse(rawURL)
+	if err != nil || u.RawQuery == "" {
+		return redactString(rawURL)
+	}
+
+	query, err := url.ParseQuery(u.RawQuery)
+	if err != nil {
+		return redactString(rawURL)
+	}
+
+	u.RawQuery = redactParams(query).Encode()
+	return u.String()
+}
+
+// redactString masks sensitive key=value pairs found anywhere in s
+func redactString(s string) string {
+	return sensitivePattern.ReplaceAllString(s, "${1}="+redactedValue)
+}
+
+// redactedError masks sensitive values in the message of a wrapped error
+// while keeping the original error available for errors.Is/As
+type redactedError struct {
+	msg string
+	err error
+}
+
+func (e *redactedError) Error() string {
+	return e.msg
+}
+
+func (e *redactedError) Unwrap() error {
+	return e.err
+}
+
+// redactError strips signed query strings from transport errors, which embed
+// the full request URL in their message
+func redactError(err error) error {
+	if err == nil {
+		return nil
+	}
+
+	var urlErr *url.Error
+	if errors.As(err, &urlErr) {
+		urlErr.URL = redactURL(urlErr.URL)
+	}
+
+	msg := err.Error()
+	if redacted := redactString(msg); redacted != msg {
+		return &redactedError{msg: redacted, err: err}
+	}
+	return err
+}
@@ -0,0 +1,105 @@
+package rest
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"router/internal/auth"
+)
+
+func TestRedactParams(t *testing.T) {
+	t.Run("masks sensitive params and keeps the rest", func(t *testing.T) {
+		params := url.Values{}
+		params.Set("symbol", "BTCUSDT")
+		params.Set("signature", "abc123")
+		params.Set("apiKey", "my-key")
+		params.Set("secret", "my-secret")
+
+		redacted := redactParams(params)
+
+		assert.Equal(t, "BTCUSDT", redacted.Get("symbol"))
+		assert.Equal(t, redactedValue, redacted.Get("signature"))
+		assert.Equal(t, redactedValue, redacted.Get("apiKey"))
+		assert.Equal(t, redactedValue, redacted.Get("secret"))
+		// Original is untouched
+		assert.Equal(t, "abc123", params.Get("signature"))
+	})
+
+	t.Run("handles nil params", func(t *testing.T) {
+		assert.Nil(t, redactParams(nil))
+	})
+}
+
+func TestRedactURL(t *testing.T) {
+	t.Run("logged signed request URL has signature masked", func(t *testing.T) {
+		signer := auth.NewSigner("test-api-key", "test-secret")
+		params := url.Values{}
+		params.Set("symbol", "BTCUSDT")
+		signed := signer.SignedRequest(params)
+		signature := signed.Get("signature")
+		require.NotEmpty(t, signature)
+
+		requestURL := "https://api.binance.com/api/v3/order?" + signed.Encode()
+
+		var buf bytes.Buffer
+		logger := zerolog.New(&buf)
+		logger.Info().Str("url", redactURL(requestURL)).Msg("Sending request")
+
+		assert.NotContains(t, buf.String(), signature)
+		assert.Contains(t, buf.String(), "signature=REDACTED")
+		assert.Contains(t, buf.String(), "symbol=BTCUSDT")
+	})
+
+	t.Run("returns URL without query unchanged", func(t *testing.T) {
+		assert.Equal(t, "https://api.binance.com/api/v3/time", redactURL("https://api.binance.com/api/v3/time"))
+	})
+}
+
+func TestRedactString(t *testing.T) {
+	msg := `Post "https://api.binance.com/api/v3/order?symbol=BTCUSDT&signature=deadbeef": EOF`
+
+	redacted := redactString(msg)
+
+	assert.NotContains(t, redacted, "deadbeef")
+	assert.Contains(t, redacted, "signature=REDACTED")
+}
+
+func TestErrorWithContext_Redaction(t *testing.T) {
+	t.Run("masks signed query strings in wrapped errors", func(t *testing.T) {
+		cause := errors.New("request to /api/v3/order?timestamp=1&signature=deadbeef failed")
+
+		err := ErrorWithContext(cause, "PlaceOrder")
+
+		assert.NotContains(t, err.Error(), "deadbeef")
+		assert.Contains(t, err.Error(), "PlaceOrder")
+		assert.True(t, errors.Is(err, cause))
+	})
+
+	t.Run("transport errors from signed requests do not leak the signature", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+		serverURL := server.URL
+		server.Close()
+
+		signer := auth.NewSigner("test-api-key", "test-secret")
+		client := NewClient(serverURL, signer, WithMaxRetries(0))
+
+		_, err := client.GetAccount(context.Background())
+		require.Error(t, err)
+
+		assert.Contains(t, err.Error(), "signature=REDACTED")
+		assert.NotContains(t, err.Error(), "test-secret")
+
+		var urlErr *url.Error
+		require.True(t, errors.As(err, &urlErr))
+		assert.Contains(t, urlErr.URL, "signature=REDACTED")
+	})
+}
@@ -1,6 +1,8 @@
 package rest
 
 import (
+	"time"
+
 	"github.com/shopspring/decimal"
 )
 
@@ -10,11 +12,60 @@ type OrderRequest struct {
 	Side             string          `json:"side"` // BUY or SELL
 	Type             string          `json:"type"` // MARKET, LIMIT, STOP_LOSS_LIMIT, etc.
 	Quantity         decimal.Decimal `json:"quantity"`
+	QuoteOrderQty    decimal.Decimal `json:"quoteOrderQty,omitempty"` // MARKET only, sized in quote asset
 	Price            decimal.Decimal `json:"price,omitempty"`
 	StopPrice        decimal.Decimal `json:"stopPrice,omitempty"`   // For stop orders
 	TimeInForce      string          `json:"timeInForce,omitempty"` // GTC, IOC, FOK
 	NewClientOrderID string          `json:"newClientOrderId,omitempty"`
 	RecvWindow       int64           `json:"recvWindow,omitempty"`
+
+	// SelfTradePreventionMode is one of the STPMode values; empty leaves the
+	// account default
+	SelfTradePreventionMode string `json:"selfTradePreventionMode,omitempty"`
+}
+
+// Self-trade prevention modes decide which side expires when an order would
+// match another order from the same account
+const (
+	STPModeNone        = "NONE"
+	STPModeExpireTaker = "EXPIRE_TAKER"
+	STPModeExpireMaker = "EXPIRE_MAKER"
+	STPModeExpireBoth  = "EXPIRE_BOTH"
+)
+
+// Cancel-replace modes decide whether the new order is still placed when the
+// cancel fails
+const (
+	CancelReplaceStopOnFailure = "STOP_ON_FAILURE"
+	CancelReplaceAllowFailure  = "ALLOW_FAILURE"
+)
+
+// Cancel-replace leg results
+const (
+	CancelReplaceSuccess      = "SUCCESS"
+	CancelReplaceFailure      = "FAILURE"
+	CancelReplaceNotAttempted = "NOT_ATTEMPTED"
+)
+
+// CancelReplaceRequest cancels an existing spot order and places NewOrder in
+// a single request. The order to cancel is identified by CancelOrderID or
+// CancelOrigClientOrderID, on NewOrder's symbol.
+type CancelReplaceRequest struct {
+	CancelReplaceMode       string
+	CancelOrderID           int64
+	CancelOrigClientOrderID string
+	NewOrder                OrderRequest
+}
+
+// CancelReplaceResponse reports the outcome of both legs. Each leg carries
+// either its response or its error, depending on its result.
+type CancelReplaceResponse struct {
+	CancelResult     string // SUCCESS, FAILURE or NOT_ATTEMPTED
+	NewOrderResult   string // SUCCESS, FAILURE or NOT_ATTEMPTED
+	CancelResponse   *Order
+	NewOrderResponse *OrderResponse
+	CancelError      *BinanceError
+	NewOrderError    *BinanceError
 }
 
 // OrderResponse represents the response from placing an order
@@ -33,6 +84,9 @@ type OrderResponse struct {
 	Type                string          `json:"type"`
 	Side                string          `json:"side"`
 	Fills               []Fill          `json:"fills"`
+	// Slippage is only set by simulated execution: the average fill price's
+	// adverse deviation from the best quote, as a fraction of that quote
+	Slippage *decimal.Decimal `json:"slippage,omitempty"`
 }
 
 // Fill represents a trade execution
@@ -86,6 +140,139 @@ type Symbol struct {
 	OcoAllowed             bool     `json:"ocoAllowed"`
 	IsSpotTradingAllowed   bool     `json:"isSpotTradingAllowed"`
 	IsMarginTradingAllowed bool     `json:"isMarginTradingAllowed"`
+
+	// Futures only
+	PricePrecision    int `json:"pricePrecision"`
+	QuantityPrecision int `json:"quantityPrecision"`
+	QuotePrecision    int `json:"quotePrecision"`
+
+	Filters []SymbolFilter `json:"filters"`
+}
+
+// Filter types reported in exchangeInfo
+const (
+	FilterTypePrice         = "PRICE_FILTER"
+	FilterTypeLotSize       = "LOT_SIZE"
+	FilterTypeMarketLotSize = "MARKET_LOT_SIZE"
+	FilterTypeMinNotional   = "MIN_NOTIONAL"
+	FilterTypeNotional      = "NOTIONAL"
+
+	FilterTypeMaxNumOrders       = "MAX_NUM_ORDERS"
+	FilterTypePercentPrice       = "PERCENT_PRICE"
+	FilterTypePercentPriceBySide = "PERCENT_PRICE_BY_SIDE"
+)
+
+// SymbolFilter is a trading rule from exchangeInfo. Only the fields that
+// belong to FilterType are populated; the rest stay zero.
+type SymbolFilter struct {
+	FilterType string `json:"filterType"`
+
+	// PRICE_FILTER
+	MinPrice decimal.Decimal `json:"minPrice"`
+	MaxPrice decimal.Decimal `json:"maxPrice"`
+	TickSize decimal.Decimal `json:"tickSize"`
+
+	// LOT_SIZE and MARKET_LOT_SIZE
+	MinQty   decimal.Decimal `json:"minQty"`
+	MaxQty   decimal.Decimal `json:"maxQty"`
+	StepSize decimal.Decimal `json:"stepSize"`
+
+	// MIN_NOTIONAL and NOTIONAL (futures MIN_NOTIONAL uses "notional")
+	MinNotional      decimal.Decimal `json:"minNotional"`
+	MaxNotional      decimal.Decimal `json:"maxNotional"`
+	Notional         decimal.Decimal `json:"notional"`
+	ApplyToMarket    bool            `json:"applyToMarket"`
+	ApplyMinToMarket bool            `json:"applyMinToMarket"`
+	AvgPriceMins     int             `json:"avgPriceMins"`
+
+	// MAX_NUM_ORDERS (futures reports the cap as "limit")
+	MaxNumOrders int `json:"maxNumOrders"`
+	Limit        int `json:"limit"`
+
+	// PERCENT_PRICE and PERCENT_PRICE_BY_SIDE
+	MultiplierUp      decimal.Decimal `json:"multiplierUp"`
+	MultiplierDown    decimal.Decimal `json:"multiplierDown"`
+	BidMultiplierUp   decimal.Decimal `json:"bidMultiplierUp"`
+	BidMultiplierDown decimal.Decimal `json:"bidMultiplierDown"`
+	AskMultiplierUp   decimal.Decimal `json:"askMultiplierUp"`
+	AskMultiplierDown decimal.Decimal `json:"askMultiplierDown"`
+}
+
+// Filter returns the filter of the given type, or nil if the symbol has none
+func (s *Symbol) Filter(filterType string) *SymbolFilter {
+	for i := range s.Filters {
+		if s.Filters[i].FilterType == filterType {
+			return &s.Filters[i]
+		}
+	}
+	return nil
+}
+
+// TickSize returns the PRICE_FILTER tick size, or zero if absent
+func (s *Symbol) TickSize() decimal.Decimal {
+	if f := s.Filter(FilterTypePrice); f != nil {
+		return f.TickSize
+	}
+	return decimal.Zero
+}
+
+// StepSize returns the LOT_SIZE step size, or zero if absent
+func (s *Symbol) StepSize() decimal.Decimal {
+	if f := s.Filter(FilterTypeLotSize); f != nil {
+		return f.StepSize
+	}
+	return decimal.Zero
+}
+
+// MinNotional returns the minimum order value from MIN_NOTIONAL or NOTIONAL,
+// or zero if neither is present
+func (s *Symbol) MinNotional() decimal.Decimal {
+	if f := s.Filter(FilterTypeMinNotional); f != nil {
+		if f.MinNotional.IsPositive() {
+			return f.MinNotional
+		}
+		return f.Notional
+	}
+	if f := s.Filter(FilterTypeNotional); f != nil {
+		return f.MinNotional
+	}
+	return decimal.Zero
+}
+
+// MaxNotional returns the maximum order value from the NOTIONAL filter, or
+// zero if the symbol has no upper bound
+func (s *Symbol) MaxNotional() decimal.Decimal {
+	if f := s.Filter(FilterTypeNotional); f != nil {
+		return f.MaxNotional
+	}
+	return decimal.Zero
+}
+
+// MaxNumOrders returns the cap on open orders from MAX_NUM_ORDERS, or zero
+// if the symbol has none
+func (s *Symbol) MaxNumOrders() int {
+	if f := s.Filter(FilterTypeMaxNumOrders); f != nil {
+		if f.MaxNumOrders > 0 {
+			return f.MaxNumOrders
+		}
+		return f.Limit
+	}
+	return 0
+}
+
+// PercentPriceBounds returns the multipliers that bound an order price
+// relative to the reference price. PERCENT_PRICE_BY_SIDE is collapsed to
+// the widest range across both sides; both are zero if neither filter is
+// present.
+func (s *Symbol) PercentPriceBounds() (down, up decimal.Decimal) {
+	if f := s.Filter(FilterTypePercentPrice); f != nil {
+		return f.MultiplierDown, f.MultiplierUp
+	}
+	if f := s.Filter(FilterTypePercentPriceBySide); f != nil {
+		return decimal.Min(f.BidMultiplierDown, f.AskMultiplierDown),
+			decimal.Max(f.BidMultiplierUp, f.AskMultiplierUp)
+	}
+	return decimal.Zero, decimal.Zero
 }
 
 // OrderBook represents order book depth
@@ -138,8 +325,13 @@ type FuturesOrderRequest struct {
 	CallbackRate     decimal.Decimal `json:"callbackRate,omitempty"`
 	WorkingType      string          `json:"workingType,omitempty"`
 	PriceProtect     bool            `json:"priceProtect,omitempty"`
+	PositionSide     string          `json:"positionSide,omitempty"` // BOTH (one-way), LONG or SHORT (hedge mode)
 	NewClientOrderID string          `json:"newClientOrderId,omitempty"`
 	RecvWindow       int64           `json:"recvWindow,omitempty"`
+
+	// SelfTradePreventionMode is one of the STPMode values; empty leaves the
+	// account default
+	SelfTradePreventionMode string `json:"selfTradePreventionMode,omitempty"`
 }
 
 // FuturesOrderResponse represents a futures order response
@@ -178,11 +370,89 @@ type FuturesAccountResponse struct {
 	TotalCrossWalletBalance     decimal.Decimal   `json:"totalCrossWalletBalance"`
 	TotalCrossUnPnl             decimal.Decimal   `json:"totalCrossUnPnl"`
 	MaxWithdrawAmount           decimal.Decimal   `json:"maxWithdrawAmount"`
+	CanTrade                    bool              `json:"canTrade"`
 	UpdateTime                  int64             `json:"updateTime"`
 	Assets                      []FuturesAsset    `json:"assets"`
 	Positions                   []FuturesPosition `json:"positions"`
 }
 
+// AvailableMargin returns the balance free for new initial margin. Binance
+// reports a negative available balance when losses exceed the margin
+// balance; no new margin is available then.
+func (a *FuturesAccountResponse) AvailableMargin() decimal.Decimal {
+	if a.AvailableBalance.IsNegative() {
+		return decimal.Zero
+	}
+	return a.AvailableBalance
+}
+
+// PositionFor returns the position entry for symbol, or nil if the account
+// has none. In hedge mode the LONG and SHORT legs are separate entries with
+// the same leverage; the first one listed is returned.
+func (a *FuturesAccountResponse) PositionFor(symbol string) *FuturesPosition {
+	for i := range a.Positions {
+		if a.Positions[i].Symbol == symbol {
+			return &a.Positions[i]
+		}
+	}
+	return nil
+}
+
+// AvgPrice represents a spot symbol's weighted average price over the
+// last Mins minutes
+type AvgPrice struct {
+	Mins      int             `json:"mins"`
+	Price     decimal.Decimal `json:"price"`
+	CloseTime int64           `json:"closeTime"`
+}
+
+// MaxTradesLimit is the most trades Binance returns from one trades or
+// aggTrades request
+const MaxTradesLimit = 1000
+
+// AggTradeOptions narrows a GetAggTrades query; zero fields are left out.
+// FromID cannot be combined with StartTime or EndTime, and a window with
+// both times must span at most an hour.
+type AggTradeOptions struct {
+	FromID    int64
+	StartTime time.Time
+	EndTime   time.Time
+	Limit     int // defaults to 500 on Binance, at most MaxTradesLimit
+}
+
+// AggTrade is a set of fills at one price from one taker order
+type AggTrade struct {
+	ID           int64           `json:"a"`
+	Price        decimal.Decimal `json:"p"`
+	Quantity     decimal.Decimal `json:"q"`
+	FirstTradeID int64           `json:"f"`
+	LastTradeID  int64           `json:"l"`
+	Time         int64           `json:"T"`
+	IsBuyerMaker bool            `json:"m"`
+	IsBestMatch  bool            `json:"M"`
+}
+
+// Trade is a single public trade
+type Trade struct {
+	ID           int64           `json:"id"`
+	Price        decimal.Decimal `json:"price"`
+	Quantity     decimal.Decimal `json:"qty"`
+	QuoteQty     decimal.Decimal `json:"quoteQty"`
+	Time         int64           `json:"time"`
+	IsBuyerMaker bool            `json:"isBuyerMaker"`
+	IsBestMatch  bool            `json:"isBestMatch"`
+}
+
+// MarkPrice represents a futures symbol's mark price and funding state
+type MarkPrice struct {
+	Symbol          string          `json:"symbol"`
+	MarkPrice       decimal.Decimal `json:"markPrice"`
+	IndexPrice      decimal.Decimal `json:"indexPrice"`
+	LastFundingRate decimal.Decimal `json:"lastFundingRate"`
+	NextFundingTime int64           `json:"nextFundingTime"`
+	Time            int64           `json:"time"`
+}
+
 // FuturesAsset represents a futures account asset
 type FuturesAsset struct {
 	Asset                  string          `json:"asset"`
@@ -219,7 +489,8 @@ type FuturesPosition struct {
 	MarkPrice              decimal.Decimal `json:"markPrice"`
 }
 
-// Ticker24hr represents a 24hr ticker statistics
+// Ticker24hr represents a 24hr ticker statistics. Binance sends numeric
+// fields as strings; decoding them into decimals keeps every digit.
 type Ticker24hr struct {
 	Symbol             string          `json:"symbol"`
 	PriceChange        decimal.Decimal `json:"priceChange"`
@@ -0,0 +1,924 @@
+// Package testutil provides fakes shared by tests across packages.
+package testutil
+
+import (
+	"encoding/json"
+	"fmt"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/gorilla/websocket"
+	"github.com/shopspring/decimal"
+)
+
+// Symbol describes the trading rules the fake publishes in exchangeInfo
+type Symbol struct {
+	Symbol      string
+	BaseAsset   string
+	QuoteAsset  string
+	TickSize    string
+	StepSize    string
+	MinQty      string
+	MinNotional string
+	// MaxQty is the LOT_SIZE maximum quantity, 9000 when empty
+	MaxQty string
+	// MaxNumOrders publishes a MAX_NUM_ORDERS filter when positive
+	MaxNumOrders int
+}
+
+// BTCUSDT is a symbol with realistic mainnet-like filters
+var BTCUSDT = Symbol{
+	Symbol:      "BTCUSDT",
+	BaseAsset:   "BTC",
+	QuoteAsset:  "USDT",
+	TickSize:    "0.01",
+	StepSize:    "0.00001",
+	MinQty:      "0.00001",
+	MinNotional: "5",
+}
+
+// RecordedOrder is an order the fake accepted
+type RecordedOrder struct {
+	Path    string // /api/v3/order or /fapi/v1/order
+	Params  url.Values
+	Header  http.Header
+	OrderID int64
+	Status  string
+}
+
+// ClientOrderID returns the newClientOrderId the order was placed with
+func (o RecordedOrder) ClientOrderID() string {
+	return o.Params.Get("newClientOrderId")
+}
+
+// Type returns the order type
+func (o RecordedOrder) Type() string {
+	return o.Params.Get("type")
+}
+
+// latencyTrigger switches on latency once a matching order is accepted
+type latencyTrigger struct {
+	match   func(url.Values) bool
+	latency time.Duration
+}
+
+type injectedError struct {
+	match func(url.Values) bool
+	code  int
+	msg   string
+}
+
+// FakeBinance is an in-process Binance spot and futures server covering the
+// REST endpoints the router uses plus a WebSocket endpoint that pushes
+// executionReport events for placed spot orders, wrapped in the combined
+// stream envelope. Request signatures are not verified.
+type FakeBinance struct {
+	server   *httptest.Server
+	upgrader websocket.Upgrader
+
+	mu          sync.Mutex
+	symbols     map[string]Symbol
+	balances    map[string]decimal.Decimal
+	orders      []RecordedOrder
+	nextOrderID int64
+	fillOrders  bool
+	fillPrice   decimal.Decimal
+	errors      []injectedError
+	drops       []func(url.Values) bool
+	rateLimited int
+	requests    map[string]int
+	hedgeMode   bool
+	readOnly    bool
+	denyAllOpen bool
+	latency     time.Duration
+	slowAfter   *latencyTrigger
+	markPrices  map[string]decimal.Decimal
+
+	wsMu    sync.Mutex
+	wsConns map[*websocket.Conn]bool
+}
+
+// NewFakeBinance starts a fake server that is shut down when the test ends
+func NewFakeBinance(t testing.TB) *FakeBinance {
+	t.Helper()
+
+	f := &FakeBinance{
+		symbols:     make(map[string]Symbol),
+		balances:    make(map[string]decimal.Decimal),
+		nextOrderID: 1,
+		requests:    make(map[string]int),
+		markPrices:  make(map[string]decimal.Decimal),
+		wsConns:     make(map[*websocket.Conn]bool),
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/api/v3/ping", f.handlePing)
+	mux.HandleFunc("/fapi/v1/ping", f.handlePing)
+	mux.HandleFunc("/api/v3/exchangeInfo", f.handleExchangeInfo(false))
+	mux.HandleFunc("/fapi/v1/exchangeInfo", f.handleExchangeInfo(true))
+	mux.HandleFunc("/api/v3/order", f.handleOrder(false))
+	mux.HandleFunc("/fapi/v1/order", f.handleOrder(true))
+	mux.HandleFunc("/api/v3/openOrders", f.handleOpenOrders("/api/v3/order"))
+	mux.HandleFunc("/fapi/v1/openOrders", f.handleOpenOrders("/fapi/v1/order"))
+	mux.HandleFunc("/api/v3/account", f.handleAccount)
+	mux.HandleFunc("/fapi/v2/account", f.handleFuturesAccount)
+	mux.HandleFunc("/fapi/v1/positionSide/dual", f.handlePositionMode)
+	mux.HandleFunc("/fapi/v1/premiumIndex", f.handleMarkPrice)
+	mux.HandleFunc("/api/v3/userDataStream", f.handleUserDataStream)
+	mux.HandleFunc("/fapi/v1/listenKey", f.handleUserDataStream)
+	mux.HandleFunc("/ws/", f.handleWebSocket)
+	mux.HandleFunc("/stream", f.handleWebSocket)
+
+	f.server = httptest.NewServer(f.middleware(mux))
+	t.Cleanup(f.Close)
+
+	return f
+}
+
+// URL returns the REST base URL, usable for both spot and futures clients
+func (f *FakeBinance) URL() string {
+	return f.server.URL
+}
+
+// WSURL returns the WebSocket base URL
+func (f *FakeBinance) WSURL() string {
+	return "ws" + strings.TrimPrefix(f.server.URL, "http")
+}
+
+// Close disconnects WebSocket clients and stops the server
+func (f *FakeBinance) Close() {
+	f.wsMu.Lock()
+	for conn := range f.wsConns {
+		conn.Close()
+		delete(f.wsConns, conn)
+	}
+	f.wsMu.Unlock()
+
+	f.server.Close()
+}
+
+// AddSymbol publishes a symbol in exchangeInfo and allows orders for it
+func (f *FakeBinance) AddSymbol(symbol Symbol) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.symbols[symbol.Symbol] = symbol
+}
+
+// SetBalance sets the free balance of an asset. The USDT balance doubles as
+// the futures available balance.
+func (f *FakeBinance) SetBalance(asset string, free decimal.Decimal) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.balances[asset] = free
+}
+
+// SetHedgeMode sets the futures position mode reported to clients
+func (f *FakeBinance) SetHedgeMode(hedge bool) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.hedgeMode = hedge
+}
+
+// SetReadOnly makes the account endpoints report that trading is disabled,
+// as they do for a key without trading permission
+func (f *FakeBinance) SetReadOnly(readOnly bool) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.readOnly = readOnly
+}
+
+// DenyAllOpenOrders makes open-order queries without a symbol fail with
+// -2015, as they do for a key without permission for the broad call
+func (f *FakeBinance) DenyAllOpenOrders(deny bool) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.denyAllOpen = deny
+}
+
+// ServeOrderFilled makes subsequent MARKET and LIMIT orders fill immediately.
+// Orders without a price fill at price. Stop and take-profit orders still
+// rest as NEW, as they would on the exchange.
+func (f *FakeBinance) ServeOrderFilled(price decimal.Decimal) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.fillOrders = true
+	f.fillPrice = price
+}
+
+// FillOrder marks the resting order with clientOrderID filled, as if the
+// market had traded through it, and reports the fill on the user stream for
+// spot orders. It reports false if no such order is open.
+func (f *FakeBinance) FillOrder(clientOrderID string) bool {
+	f.mu.Lock()
+	var filled *RecordedOrder
+	for i := range f.orders {
+		if f.orders[i].ClientOrderID() == clientOrderID && f.orders[i].Status == "NEW" {
+			f.orders[i].Status = "FILLED"
+			filled = &f.orders[i]
+			break
+		}
+	}
+	var order RecordedOrder
+	if filled != nil {
+		order = *filled
+	}
+	f.mu.Unlock()
+
+	if filled == nil {
+		return false
+	}
+	if strings.HasPrefix(order.Path, "/api/") {
+		f.Broadcast(map[string]interface{}{
+			"stream": "fake-listen-key",
+			"data":   executionReport(order, decimalParam(order.Params, "quantity")),
+		})
+	}
+	return true
+}
+
+// InjectError fails the next order placement or cancel with a Binance error
+func (f *FakeBinance) InjectError(code int, msg string) {
+	f.InjectErrorFor(nil, code, msg)
+}
+
+// InjectErrorFor fails the next order placement or cancel whose parameters match
+func (f *FakeBinance) InjectErrorFor(match func(url.Values) bool, code int, msg string) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.errors = append(f.errors, injectedError{match: match, code: code, msg: msg})
+}
+
+// DropResponseFor accepts the next order placement whose parameters match,
+// then resets the connection instead of responding, like a network failure
+// after Binance received the order. A nil match drops the next placement.
+func (f *FakeBinance) DropResponseFor(match func(url.Values) bool) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if match == nil {
+		match = func(url.Values) bool { return true }
+	}
+	f.drops = append(f.drops, match)
+}
+
+// OrderTypeIs matches order requests of the given type, for InjectErrorFor
+func OrderTypeIs(orderType string) func(url.Values) bool {
+	return func(params url.Values) bool {
+		return params.Get("type") == orderType
+	}
+}
+
+// SimulateRateLimit answers the next n requests with HTTP 429 and code -1003
+func (f *FakeBinance) SimulateRateLimit(n int) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.rateLimited = n
+}
+
+// SetMarkPrice sets the futures mark price reported for symbol
+func (f *FakeBinance) SetMarkPrice(symbol string, price decimal.Decimal) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.markPrices[symbol] = price
+}
+
+// SetLatency delays every REST response by d, or until the client gives up
+func (f *FakeBinance) SetLatency(d time.Duration) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.latency = d
+}
+
+// SetLatencyAfter delays every REST response by d once an order matching
+// match has been accepted, as when Binance slows down mid-bracket
+func (f *FakeBinance) SetLatencyAfter(match func(url.Values) bool, d time.Duration) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.slowAfter = &latencyTrigger{match: match, latency: d}
+}
+
+// Orders returns the orders accepted so far, in placement order
+func (f *FakeBinance) Orders() []RecordedOrder {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return append([]RecordedOrder(nil), f.orders...)
+}
+
+// RequestCount returns how many requests reached path, including rejected ones
+func (f *FakeBinance) RequestCount(path string) int {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.requests[path]
+}
+
+// WSClientCount returns the number of connected WebSocket clients
+func (f *FakeBinance) WSClientCount() int {
+	f.wsMu.Lock()
+	defer f.wsMu.Unlock()
+	return len(f.wsConns)
+}
+
+// Broadcast sends a JSON message to every connected WebSocket client
+func (f *FakeBinance) Broadcast(v interface{}) error {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Errorf("failed to marshal message: %w", err)
+	}
+
+	f.wsMu.Lock()
+	defer f.wsMu.Unlock()
+
+	for conn := range f.wsConns {
+		if err := conn.WriteMessage(websocket.TextMessage, data); err != nil {
+			conn.Close()
+			delete(f.wsConns, conn)
+		}
+	}
+	return nil
+}
+
+// middleware counts requests and applies latency and rate-limit simulation
+func (f *FakeBinance) middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		f.mu.Lock()
+		f.requests[r.URL.Path]++
+		limited := f.rateLimited > 0
+		latency := f.latency
+		if limited {
+			f.rateLimited--
+		}
+		f.mu.Unlock()
+
+		if latency > 0 && !websocket.IsWebSocketUpgrade(r) {
+			select {
+			case <-time.After(latency):
+			case <-r.Context().Done():
+				return
+			}
+		}
+
+		if limited {
+			writeError(w, http.StatusTooManyRequests, -1003, "Too much request weight used; current limit is 6000 request weight per 1 MINUTE.")
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+func (f *FakeBinance) handlePing(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, map[string]interface{}{})
+}
+
+func (f *FakeBinance) handleExchangeInfo(futures bool) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		only := r.URL.Query().Get("symbol")
+
+		f.mu.Lock()
+		symbols := make([]interface{}, 0, len(f.symbols))
+		for name, s := range f.symbols {
+			if only == "" || only == name {
+				symbols = append(symbols, symbolJSON(s, futures))
+			}
+		}
+		f.mu.Unlock()
+
+		if only != "" && len(symbols) == 0 {
+			writeError(w, http.StatusBadRequest, -1121, "Invalid symbol.")
+			return
+		}
+
+		writeJSON(w, map[string]interface{}{
+			"timezone":   "UTC",
+			"serverTime": time.Now().UnixMilli(),
+			"symbols":    symbols,
+		})
+	}
+}
+
+func (f *FakeBinance) handleOrder(futures bool) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method {
+		case http.MethodPost:
+			f.placeOrder(w, r, futures)
+		case http.MethodGet:
+			f.queryOrder(w, r, futures)
+		case http.MethodDelete:
+			f.cancelOrder(w, r, futures)
+		default:
+			writeError(w, http.StatusMethodNotAllowed, -1000, "Unsupported method.")
+		}
+	}
+}
+
+func (f *FakeBinance) placeOrder(w http.ResponseWriter, r *http.Request, futures bool) {
+	params := r.URL.Query()
+
+	f.mu.Lock()
+	if injected, ok := f.takeError(params); ok {
+		f.mu.Unlock()
+		writeError(w, http.StatusBadRequest, injected.code, injected.msg)
+		return
+	}
+	if _, known := f.symbols[params.Get("symbol")]; !known {
+		f.mu.Unlock()
+		writeError(w, http.StatusBadRequest, -1121, "Invalid symbol.")
+		return
+	}
+	if id := params.Get("newClientOrderId"); id != "" && f.findOrder(r.URL.Path, params.Get("symbol"), id) != nil {
+		f.mu.Unlock()
+		if futures {
+			writeError(w, http.StatusBadRequest, -4116, "ClientOrderId is duplicated.")
+		} else {
+			writeError(w, http.StatusBadRequest, -2010, "Duplicate order sent.")
+		}
+		return
+	}
+
+	order := RecordedOrder{
+		Path:    r.URL.Path,
+		Params:  params,
+		Header:  r.Header.Clone(),
+		OrderID: f.nextOrderID,
+		Status:  "NEW",
+	}
+	f.nextOrderID++
+
+	executedQty := decimal.Zero
+	fillPrice := decimalParam(params, "price")
+	orderType := params.Get("type")
+	if f.fillOrders && (orderType == "MARKET" || orderType == "LIMIT") {
+		order.Status = "FILLED"
+		executedQty = decimalParam(params, "quantity")
+		if fillPrice.IsZero() {
+			fillPrice = f.fillPrice
+		}
+	}
+	f.orders = append(f.orders, order)
+	if f.slowAfter != nil && f.slowAfter.match(params) {
+		f.latency = f.slowAfter.latency
+		f.slowAfter = nil
+	}
+	drop := f.takeDrop(params)
+	f.mu.Unlock()
+
+	switch {
+	case drop:
+		resetConnection(w)
+	case futures:
+		writeJSON(w, futuresOrderJSON(order, executedQty, fillPrice))
+	default:
+		writeJSON(w, spotOrderJSON(order, executedQty, fillPrice))
+	}
+
+	if !futures {
+		f.Broadcast(map[string]interface{}{
+			"stream": "fake-listen-key",
+			"data":   executionReport(order, executedQty),
+		})
+	}
+}
+
+func (f *FakeBinance) queryOrder(w http.ResponseWriter, r *http.Request, futures bool) {
+	params := r.URL.Query()
+
+	f.mu.Lock()
+	found := f.findOrder(r.URL.Path, params.Get("symbol"), params.Get("origClientOrderId"))
+	var order RecordedOrder
+	if found != nil {
+		order = *found
+	}
+	fillPrice := f.fillPrice
+	f.mu.Unlock()
+
+	if found == nil {
+		writeError(w, http.StatusBadRequest, -2013, "Order does not exist.")
+		return
+	}
+
+	executedQty := decimal.Zero
+	if order.Status == "FILLED" {
+		executedQty = decimalParam(order.Params, "quantity")
+	}
+	if price := decimalParam(order.Params, "price"); !price.IsZero() {
+		fillPrice = price
+	}
+	if futures {
+		writeJSON(w, futuresOrderJSON(order, executedQty, fillPrice))
+		return
+	}
+	writeJSON(w, spotOrderJSON(order, executedQty, fillPrice))
+}
+
+// findOrder returns the order placed on path for symbol with clientOrderID,
+// or nil. Callers must hold f.mu.
+func (f *FakeBinance) findOrder(path, symbol, clientOrderID string) *RecordedOrder {
+	for i := range f.orders {
+		o := &f.orders[i]
+		if o.Path == path && o.Params.Get("symbol") == symbol && o.Params.Get("newClientOrderId") == clientOrderID {
+			return o
+		}
+	}
+	return nil
+}
+
+func (f *FakeBinance) cancelOrder(w http.ResponseWriter, r *http.Request, futures bool) {
+	params := r.URL.Query()
+	orderID, _ := strconv.ParseInt(params.Get("orderId"), 10, 64)
+
+	f.mu.Lock()
+	if injected, ok := f.takeError(params); ok {
+		f.mu.Unlock()
+		writeError(w, http.StatusBadRequest, injected.code, injected.msg)
+		return
+	}
+	var cancelled *RecordedOrder
+	for i := range f.orders {
+		if f.orders[i].OrderID == orderID && f.orders[i].Params.Get("symbol") == params.Get("symbol") {
+			if f.orders[i].Status == "NEW" {
+				f.orders[i].Status = "CANCELED"
+				cancelled = &f.orders[i]
+			}
+			break
+		}
+	}
+	var order RecordedOrder
+	if cancelled != nil {
+		order = *cancelled
+	}
+	f.mu.Unlock()
+
+	if cancelled == nil {
+		writeError(w, http.StatusBadRequest, -2011, "Unknown order sent.")
+		return
+	}
+
+	if futures {
+		writeJSON(w, futuresOrderJSON(order, decimal.Zero, decimalParam(order.Params, "price")))
+		return
+	}
+	writeJSON(w, spotOrderJSON(order, decimal.Zero, decimalParam(order.Params, "price")))
+}
+
+func (f *FakeBinance) handleOpenOrders(orderPath string) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		symbol := r.URL.Query().Get("symbol")
+
+		f.mu.Lock()
+		if symbol == "" && f.denyAllOpen {
+			f.mu.Unlock()
+			writeError(w, http.StatusUnauthorized, -2015, "Invalid API-key, IP, or permissions for action.")
+			return
+		}
+		open := make([]interface{}, 0)
+		for _, order := range f.orders {
+			if order.Path != orderPath || order.Status != "NEW" {
+				continue
+			}
+			if symbol != "" && order.Params.Get("symbol") != symbol {
+				continue
+			}
+			open = append(open, spotOrderJSON(order, decimal.Zero, decimalParam(order.Params, "price")))
+		}
+		f.mu.Unlock()
+
+		writeJSON(w, open)
+	}
+}
+
+func (f *FakeBinance) handleAccount(w http.ResponseWriter, r *http.Request) {
+	f.mu.Lock()
+	balances := make([]interface{}, 0, len(f.balances))
+	for asset, free := range f.balances {
+		balances = append(balances, map[string]interface{}{
+			"asset":  asset,
+			"free":   free.String(),
+			"locked": "0",
+		})
+	}
+	canTrade := !f.readOnly
+	f.mu.Unlock()
+
+	writeJSON(w, map[string]interface{}{
+		"canTrade":    canTrade,
+		"canWithdraw": true,
+		"canDeposit":  true,
+		"updateTime":  time.Now().UnixMilli(),
+		"accountType": "SPOT",
+		"balances":    balances,
+	})
+}
+
+func (f *FakeBinance) handleFuturesAccount(w http.ResponseWriter, r *http.Request) {
+	f.mu.Lock()
+	available := f.balances["USDT"]
+	canTrade := !f.readOnly
+	f.mu.Unlock()
+
+	writeJSON(w, map[string]interface{}{
+		"canTrade":           canTrade,
+		"totalWalletBalance": available.String(),
+		"availableBalance":   available.String(),
+		"updateTime":         time.Now().UnixMilli(),
+		"assets":             []interface{}{},
+		"positions":          []interface{}{},
+	})
+}
+
+func (f *FakeBinance) handlePositionMode(w http.ResponseWriter, r *http.Request) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if r.Method != http.MethodPost {
+		writeJSON(w, map[string]bool{"dualSidePosition": f.hedgeMode})
+		return
+	}
+
+	dual, err := strconv.ParseBool(r.URL.Query().Get("dualSidePosition"))
+	if err != nil {
+		writeError(w, http.StatusBadRequest, -1102, "Mandatory parameter 'dualSidePosition' was not sent, was empty/null, or malformed.")
+		return
+	}
+	if dual == f.hedgeMode {
+		writeError(w, http.StatusBadRequest, -4059, "No need to change position side.")
+		return
+	}
+
+	f.hedgeMode = dual
+	writeJSON(w, map[string]interface{}{"code": 200, "msg": "success"})
+}
+
+func (f *FakeBinance) handleMarkPrice(w http.ResponseWriter, r *http.Request) {
+	symbol := r.URL.Query().Get("symbol")
+
+	f.mu.Lock()
+	price, ok := f.markPrices[symbol]
+	f.mu.Unlock()
+
+	if !ok {
+		writeError(w, http.StatusBadRequest, -1121, "Invalid symbol.")
+		return
+	}
+	writeJSON(w, map[string]interface{}{
+		"symbol":          symbol,
+		"markPrice":       price.String(),
+		"indexPrice":      price.String(),
+		"lastFundingRate": "0.00010000",
+		"nextFundingTime": time.Now().Add(time.Hour).UnixMilli(),
+		"time":            time.Now().UnixMilli(),
+	})
+}
+
+func (f *FakeBinance) handleUserDataStream(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		writeJSON(w, map[string]string{})
+		return
+	}
+	writeJSON(w, map[string]string{"listenKey": "fake-listen-key"})
+}
+
+func (f *FakeBinance) handleWebSocket(w http.ResponseWriter, r *http.Request) {
+	conn, err := f.upgrader.Upgrade(w, r, nil)
+	if err != nil {
+		return
+	}
+
+	f.wsMu.Lock()
+	f.wsConns[conn] = true
+	f.wsMu.Unlock()
+
+	// Acknowledge SUBSCRIBE/UNSUBSCRIBE requests until the client goes away
+	go func() {
+		defer func() {
+			f.wsMu.Lock()
+			delete(f.wsConns, conn)
+			f.wsMu.Unlock()
+			conn.Close()
+		}()
+
+		for {
+			var req struct {
+				ID int64 `json:"id"`
+			}
+			if err := conn.ReadJSON(&req); err != nil {
+				return
+			}
+
+			f.wsMu.Lock()
+			err := conn.WriteJSON(map[string]interface{}{"result": nil, "id": req.ID})
+			f.wsMu.Unlock()
+			if err != nil {
+				return
+			}
+		}
+	}()
+}
+
+// takeError removes and returns the first injected error matching params.
+// Callers must hold f.mu.
+func (f *FakeBinance) takeError(params url.Values) (injectedError, bool) {
+	for i, e := range f.errors {
+		if e.match == nil || e.match(params) {
+			f.errors = append(f.errors[:i], f.errors[i+1:]...)
+			return e, true
+		}
+	}
+	return injectedError{}, false
+}
+
+// takeDrop removes the first response drop matching params, reporting whether
+// there was one. Callers must hold f.mu.
+func (f *FakeBinance) takeDrop(params url.Values) bool {
+	for i, match := range f.drops {
+		if match(params) {
+			f.drops = append(f.drops[:i], f.drops[i+1:]...)
+			return true
+		}
+	}
+	return false
+}
+
+// resetConnection aborts the response with a TCP reset so the client sees a
+// connection error rather than a clean EOF
+func resetConnection(w http.ResponseWriter) {
+	hijacker, ok := w.(http.Hijacker)
+	if !ok {
+		return
+	}
+	conn, _, err := hijacker.Hijack()
+	if err != nil {
+		return
+	}
+	if tcp, ok := conn.(*net.TCPConn); ok {
+		tcp.SetLinger(0)
+	}
+	conn.Close()
+}
+
+func symbolJSON(s Symbol, futures bool) map[string]interface{} {
+	notional := map[string]interface{}{
+		"filterType":  "NOTIONAL",
+		"minNotional": s.MinNotional,
+	}
+	if futures {
+		notional = map[string]interface{}{
+			"filterType": "MIN_NOTIONAL",
+			"notional":   s.MinNotional,
+		}
+	}
+
+	maxQty := s.MaxQty
+	if maxQty == "" {
+		maxQty = "9000"
+	}
+
+	filters := []interface{}{
+		map[string]interface{}{
+			"filterType": "PRICE_FILTER",
+			"minPrice":   s.TickSize,
+			"maxPrice":   "1000000",
+			"tickSize":   s.TickSize,
+		},
+		map[string]interface{}{
+			"filterType": "LOT_SIZE",
+			"minQty":     s.MinQty,
+			"maxQty":     maxQty,
+			"stepSize":   s.StepSize,
+		},
+		notional,
+	}
+	if s.MaxNumOrders > 0 {
+		maxNumOrders := map[string]interface{}{
+			"filterType":   "MAX_NUM_ORDERS",
+			"maxNumOrders": s.MaxNumOrders,
+		}
+		if futures {
+			maxNumOrders = map[string]interface{}{
+				"filterType": "MAX_NUM_ORDERS",
+				"limit":      s.MaxNumOrders,
+			}
+		}
+		filters = append(filters, maxNumOrders)
+	}
+
+	return map[string]interface{}{
+		"symbol":               s.Symbol,
+		"status":               "TRADING",
+		"baseAsset":            s.BaseAsset,
+		"baseAssetPrecision":   8,
+		"quoteAsset":           s.QuoteAsset,
+		"quoteAssetPrecision":  8,
+		"orderTypes":           []string{"LIMIT", "MARKET", "STOP_LOSS_LIMIT", "TAKE_PROFIT_LIMIT"},
+		"isSpotTradingAllowed": !futures,
+		"filters":              filters,
+	}
+}
+
+func spotOrderJSON(order RecordedOrder, executedQty, fillPrice decimal.Decimal) map[string]interface{} {
+	p := order.Params
+	fills := []interface{}{}
+	if executedQty.IsPositive() {
+		fills = append(fills, map[string]interface{}{
+			"price":           fillPrice.String(),
+			"qty":             executedQty.String(),
+			"commission":      "0",
+			"commissionAsset": "BNB",
+		})
+	}
+
+	return map[string]interface{}{
+		"symbol":              p.Get("symbol"),
+		"orderId":             order.OrderID,
+		"orderListId":         -1,
+		"clientOrderId":       order.ClientOrderID(),
+		"transactTime":        time.Now().UnixMilli(),
+		"price":               decimalParam(p, "price").String(),
+		"origQty":             decimalParam(p, "quantity").String(),
+		"executedQty":         executedQty.String(),
+		"cummulativeQuoteQty": executedQty.Mul(fillPrice).String(),
+		"status":              order.Status,
+		"timeInForce":         p.Get("timeInForce"),
+		"type":                p.Get("type"),
+		"side":                p.Get("side"),
+		"stopPrice":           decimalParam(p, "stopPrice").String(),
+		"fills":               fills,
+	}
+}
+
+func futuresOrderJSON(order RecordedOrder, executedQty, fillPrice decimal.Decimal) map[string]interface{} {
+	p := order.Params
+	avgPrice := decimal.Zero
+	if executedQty.IsPositive() {
+		avgPrice = fillPrice
+	}
+
+	return map[string]interface{}{
+		"orderId":       order.OrderID,
+		"symbol":        p.Get("symbol"),
+		"status":        order.Status,
+		"clientOrderId": order.ClientOrderID(),
+		"price":         decimalParam(p, "price").String(),
+		"avgPrice":      avgPrice.String(),
+		"origQty":       decimalParam(p, "quantity").String(),
+		"executedQty":   executedQty.String(),
+		"cumQuote":      executedQty.Mul(avgPrice).String(),
+		"timeInForce":   p.Get("timeInForce"),
+		"type":          p.Get("type"),
+		"origType":      p.Get("type"),
+		"reduceOnly":    p.Get("reduceOnly") == "true",
+		"closePosition": p.Get("closePosition") == "true",
+		"side":          p.Get("side"),
+		"positionSide":  positionSideParam(p),
+		"stopPrice":     decimalParam(p, "stopPrice").String(),
+		"workingType":   "CONTRACT_PRICE",
+		"updateTime":    time.Now().UnixMilli(),
+	}
+}
+
+func executionReport(order RecordedOrder, executedQty decimal.Decimal) map[string]interface{} {
+	p := order.Params
+	return map[string]interface{}{
+		"e": "executionReport",
+		"E": time.Now().UnixMilli(),
+		"s": p.Get("symbol"),
+		"c": order.ClientOrderID(),
+		"S": p.Get("side"),
+		"o": p.Get("type"),
+		"q": decimalParam(p, "quantity").String(),
+		"p": decimalParam(p, "price").String(),
+		"X": order.Status,
+		"i": order.OrderID,
+		"z": executedQty.String(),
+	}
+}
+
+func positionSideParam(params url.Values) string {
+	if side := params.Get("positionSide"); side != "" {
+		return side
+	}
+	return "BOTH"
+}
+
+func decimalParam(params url.Values, key string) decimal.Decimal {
+	d, err := decimal.NewFromString(params.Get(key))
+	if err != nil {
+		return decimal.Zero
+	}
+	return d
+}
+
+func writeJSON(w http.ResponseWriter, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(v)
+}
+
+func writeError(w http.ResponseWriter, status, code int, msg string) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(map[string]interface{}{"code": code, "msg": msg})
+}
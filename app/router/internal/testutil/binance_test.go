@@ -0,0 +1,164 @@
+package testutil_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/gorilla/websocket"
+	"github.com/rs/zerolog"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"router/internal/auth"
+	"router/internal/binance"
+	"router/internal/orders"
+	"router/internal/rest"
+	"router/internal/testutil"
+)
+
+func newSpotManager(t *testing.T, fake *testutil.FakeBinance) *orders.Manager {
+	t.Helper()
+
+	signer := auth.NewSigner("test-key", "test-secret")
+	restClient := rest.NewClient(fake.URL(), signer, rest.WithMaxRetries(1))
+	spot, err := binance.NewClient(fake.URL(), signer, restClient, zerolog.Nop())
+	require.NoError(t, err)
+
+	return orders.NewManager(spot, nil, nil, zerolog.Nop())
+}
+
+func TestFakeBinance_BracketPlacement(t *testing.T) {
+	fake := testutil.NewFakeBinance(t)
+	fake.AddSymbol(testutil.BTCUSDT)
+	fake.ServeOrderFilled(decimal.NewFromInt(50000))
+
+	manager := newSpotManager(t, fake)
+
+	resp, err := manager.PlaceBracketOrder(context.Background(), &orders.PlaceBracketRequest{
+		Symbol:           "BTCUSDT",
+		Side:             "BUY",
+		Quantity:         decimal.RequireFromString("0.0012345"),
+		EntryPrice:       decimal.RequireFromString("50000.004"),
+		TakeProfitPrices: []decimal.Decimal{decimal.NewFromInt(51000)},
+		StopLossPrice:    decimal.NewFromInt(49000),
+		OrderType:        "LIMIT",
+	})
+	require.NoError(t, err)
+	assert.False(t, resp.PartialFailure)
+
+	placed := fake.Orders()
+	require.Len(t, placed, 3)
+
+	assert.Equal(t, "LIMIT", placed[0].Type())
+	assert.Equal(t, "FILLED", placed[0].Status)
+	assert.Equal(t, "0.00123", placed[0].Params.Get("quantity"))
+	assert.Equal(t, "50000", placed[0].Params.Get("price"))
+	assert.Equal(t, resp.ClientOrderIDs.Main, placed[0].ClientOrderID())
+
+	assert.Equal(t, "LIMIT", placed[1].Type())
+	assert.Equal(t, "SELL", placed[1].Params.Get("side"))
+
+	assert.Equal(t, "STOP_LOSS_LIMIT", placed[2].Type())
+	assert.Equal(t, "NEW", placed[2].Status)
+	assert.Equal(t, resp.ClientOrderIDs.StopLoss, placed[2].ClientOrderID())
+}
+
+func TestFakeBinance_InjectError(t *testing.T) {
+	fake := testutil.NewFakeBinance(t)
+	fake.AddSymbol(testutil.BTCUSDT)
+	fake.InjectErrorFor(testutil.OrderTypeIs("MARKET"), -2010, "Account has insufficient balance for requested action.")
+
+	client := rest.NewClient(fake.URL(), auth.NewSigner("k", "s"))
+	order := &rest.OrderRequest{
+		Symbol:   "BTCUSDT",
+		Side:     "BUY",
+		Type:     "MARKET",
+		Quantity: decimal.RequireFromString("0.001"),
+	}
+
+	_, err := client.PlaceOrder(context.Background(), order)
+	var binanceErr *rest.BinanceError
+	require.ErrorAs(t, err, &binanceErr)
+	assert.True(t, binanceErr.IsInsufficientBalance())
+
+	// Injected errors fire once
+	resp, err := client.PlaceOrder(context.Background(), order)
+	require.NoError(t, err)
+	assert.Equal(t, "NEW", resp.Status)
+}
+
+func TestFakeBinance_SimulateRateLimit(t *testing.T) {
+	fake := testutil.NewFakeBinance(t)
+	fake.SimulateRateLimit(1)
+
+	client := rest.NewClient(fake.URL(), nil, rest.WithMaxRetries(2))
+	require.NoError(t, client.Ping(context.Background()))
+	assert.Equal(t, 2, fake.RequestCount("/api/v3/ping"))
+}
+
+func TestFakeBinance_ExecutionReports(t *testing.T) {
+	fake := testutil.NewFakeBinance(t)
+	fake.AddSymbol(testutil.BTCUSDT)
+
+	conn, _, err := websocket.DefaultDialer.Dial(fake.WSURL()+"/ws/fake-listen-key", nil)
+	require.NoError(t, err)
+	defer conn.Close()
+
+	require.Eventually(t, func() bool {
+		return fake.WSClientCount() == 1
+	}, time.Second, 10*time.Millisecond)
+
+	client := rest.NewClient(fake.URL(), auth.NewSigner("k", "s"))
+	_, err = client.PlaceOrder(context.Background(), &rest.OrderRequest{
+		Symbol:           "BTCUSDT",
+		Side:             "BUY",
+		Type:             "MARKET",
+		Quantity:         decimal.RequireFromString("0.001"),
+		NewClientOrderID: "ws-report",
+	})
+	require.NoError(t, err)
+
+	var msg struct {
+		Stream string                 `json:"stream"`
+		Data   map[string]interface{} `json:"data"`
+	}
+	conn.SetReadDeadline(time.Now().Add(time.Second))
+	require.NoError(t, conn.ReadJSON(&msg))
+	assert.Equal(t, "executionReport", msg.Data["e"])
+	assert.Equal(t, "ws-report", msg.Data["c"])
+	assert.Equal(t, "NEW", msg.Data["X"])
+}
+
+func TestFakeBinance_DuplicateClientOrderID(t *testing.T) {
+	fake := testutil.NewFakeBinance(t)
+	fake.AddSymbol(testutil.BTCUSDT)
+	fake.DropResponseFor(nil)
+
+	client := rest.NewClient(fake.URL(), auth.NewSigner("k", "s"), rest.WithMaxRetries(0))
+	order := &rest.OrderRequest{
+		Symbol:           "BTCUSDT",
+		Side:             "BUY",
+		Type:             "MARKET",
+		Quantity:         decimal.RequireFromString("0.001"),
+		NewClientOrderID: "dup-1",
+	}
+
+	_, err := client.PlaceOrder(context.Background(), order)
+	require.ErrorIs(t, err, rest.ErrNetwork)
+
+	_, err = client.PlaceOrder(context.Background(), order)
+	var binanceErr *rest.BinanceError
+	require.ErrorAs(t, err, &binanceErr)
+	assert.True(t, binanceErr.IsDuplicateOrder())
+	assert.False(t, binanceErr.IsInsufficientBalance())
+
+	existing, err := client.GetOrder(context.Background(), "BTCUSDT", "dup-1")
+	require.NoError(t, err)
+	assert.Equal(t, "dup-1", existing.ClientOrderID)
+	assert.Len(t, fake.Orders(), 1)
+
+	_, err = client.GetOrder(context.Background(), "BTCUSDT", "missing")
+	require.ErrorAs(t, err, &binanceErr)
+	assert.True(t, binanceErr.IsUnknownOrder())
+}
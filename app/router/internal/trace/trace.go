@@ -0,0 +1,65 @@
+// Package trace carries a per-request trace ID through contexts so an inbound
+// request can be correlated with the exchange calls and log lines it causes.
+package trace
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+
+	"github.com/rs/zerolog"
+)
+
+// Header is the HTTP header a trace ID is read from and propagated in
+const Header = "X-Trace-ID"
+
+// maxIDLength bounds trace IDs accepted from callers so a client cannot bloat
+// every log line and outgoing request
+const maxIDLength = 128
+
+type contextKey struct{}
+
+// WithID returns a copy of ctx carrying id
+func WithID(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, contextKey{}, id)
+}
+
+// ID returns the trace ID carried by ctx, or "" if there is none
+func ID(ctx context.Context) string {
+	id, _ := ctx.Value(contextKey{}).(string)
+	return id
+}
+
+// NewID generates a random 128-bit trace ID in hex
+func NewID() string {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return ""
+	}
+	return hex.EncodeToString(b)
+}
+
+// Valid reports whether id is acceptable as a caller-supplied trace ID: non
+// empty, bounded, and limited to characters safe in headers and logs
+func Valid(id string) bool {
+	if id == "" || len(id) > maxIDLength {
+		return false
+	}
+	for _, r := range id {
+		switch {
+		case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z', r >= '0' && r <= '9':
+		case r == '-' || r == '_' || r == '.':
+		default:
+			return false
+		}
+	}
+	return true
+}
+
+// Logger returns logger with a trace_id field when ctx carries a trace ID
+func Logger(ctx context.Context, logger zerolog.Logger) zerolog.Logger {
+	if id := ID(ctx); id != "" {
+		return logger.With().Str("trace_id", id).Logger()
+	}
+	return logger
+}
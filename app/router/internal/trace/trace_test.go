@@ -0,0 +1,58 @@
+package trace
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestID(t *testing.T) {
+	ctx := context.Background()
+	assert.Empty(t, ID(ctx))
+
+	ctx = WithID(ctx, "abc-123")
+	assert.Equal(t, "abc-123", ID(ctx))
+}
+
+func TestNewID(t *testing.T) {
+	a, b := NewID(), NewID()
+	assert.Len(t, a, 32)
+	assert.NotEqual(t, a, b)
+	assert.True(t, Valid(a))
+}
+
+func TestValid(t *testing.T) {
+	tests := []struct {
+		id   string
+		want bool
+	}{
+		{"4bf92f3577b34da6a3ce929d0e0e4736", true},
+		{"req_1.2-3", true},
+		{"", false},
+		{strings.Repeat("a", maxIDLength+1), false},
+		{"abc\r\nX-Injected: 1", false},
+		{"with space", false},
+	}
+
+	for _, tt := range tests {
+		assert.Equal(t, tt.want, Valid(tt.id), tt.id)
+	}
+}
+
+func TestLogger(t *testing.T) {
+	var buf bytes.Buffer
+	base := zerolog.New(&buf)
+
+	logger := Logger(context.Background(), base)
+	logger.Info().Msg("untraced")
+	assert.NotContains(t, buf.String(), "trace_id")
+
+	buf.Reset()
+	logger = Logger(WithID(context.Background(), "abc"), base)
+	logger.Info().Msg("traced")
+	assert.Contains(t, buf.String(), `"trace_id":"abc"`)
+}
@@ -3,9 +3,12 @@ package websocket
 import (
 	"context"
 	"fmt"
+	"sort"
 	"strings"
 	"sync"
 	"time"
+
+	"github.com/rs/zerolog"
 )
 
 // DefaultBaseURL is the default Binance WebSocket base URL
@@ -18,12 +21,22 @@ type Client struct {
 	connections map[string]*StreamManager // Multiple connections for different stream types
 	connMu      sync.RWMutex
 
+	// Public stream sharding: streamMgr is the first shard, overflow shards
+	// live in connections under shardKey names
+	shards            []*StreamManager
+	streamRoutes      map[string]*StreamManager // stream -> owning shard
+	maxStreamsPerConn int
+	// Capacity promised to subscribes running outside connMu
+	claimed map[*StreamManager]int
+
 	// Connection options to pass to stream managers
 	connOpts []ConnectionOption
 
 	// Subscription handlers
 	depthHandlers  map[string]func(*DepthUpdateEvent) error
 	tickerHandlers map[string]func(*TickerEvent) error
+	allTickers     func([]*TickerEvent) error
+	liquidations   func(*LiquidationEvent) error
 	userHandlers   map[string]*UserDataHandler
 	handlersMu     sync.RWMutex
 }
@@ -38,6 +51,14 @@ func WithBaseURL(url string) ClientOption {
 	}
 }
 
+// WithMaxStreamsPerConnection caps public streams per connection before
+// subscriptions spill over to a new shard
+func WithMaxStreamsPerConnection(max int) ClientOption {
+	return func(c *Client) {
+		c.maxStreamsPerConn = max
+	}
+}
+
 // Client option functions that forward to connection options
 
 // WithAutoReconnectClient enables automatic reconnection for client
@@ -65,11 +86,76 @@ func WithReconnectIntervalClient(interval time.Duration) ClientOption {
 	}
 }
 
+// WithMaxReconnectIntervalClient caps the reconnection backoff for client
+func WithMaxReconnectIntervalClient(interval time.Duration) ClientOption {
+	return func(c *Client) {
+		c.connOpts = append(c.connOpts, WithMaxReconnectInterval(interval))
+	}
+}
+
+// WithReadLimitClient caps the incoming message size for every connection the
+// client opens
+func WithReadLimitClient(bytes int64) ClientOption {
+	return func(c *Client) {
+		c.connOpts = append(c.connOpts, WithReadLimit(bytes))
+	}
+}
+
+// WithHandshakeTimeoutClient bounds the handshake of every connection the
+// client opens
+func WithHandshakeTimeoutClient(timeout time.Duration) ClientOption {
+	return func(c *Client) {
+		c.connOpts = append(c.connOpts, WithHandshakeTimeout(timeout))
+	}
+}
+
+// WithBufferSizesClient sets the read and write buffer sizes of every
+// connection the client opens
+func WithBufferSizesClient(readBytes, writeBytes int) ClientOption {
+	return func(c *Client) {
+		c.connOpts = append(c.connOpts, WithReadBufferSize(readBytes), WithWriteBufferSize(writeBytes))
+	}
+}
+
+// WithSubscribeRetryClient sets the subscribe retry policy of every
+// connection the client opens
+func WithSubscribeRetryClient(retries int, delay time.Duration) ClientOption {
+	return func(c *Client) {
+		c.connOpts = append(c.connOpts, WithSubscribeRetry(retries, delay))
+	}
+}
+
+// WithCompressionClient offers permessage-deflate on every connection the
+// client opens
+func WithCompressionClient(enable bool) ClientOption {
+	return func(c *Client) {
+		c.connOpts = append(c.connOpts, WithCompression(enable))
+	}
+}
+
+// WithMetricsClient reports connection and event metrics from every
+// connection the client opens
+func WithMetricsClient(recorder MetricsRecorder) ClientOption {
+	return func(c *Client) {
+		c.connOpts = append(c.connOpts, WithMetrics(recorder))
+	}
+}
+
+// WithLoggerClient sets the logger every connection uses to report
+// recovered handler panics
+func WithLoggerClient(logger zerolog.Logger) ClientOption {
+	return func(c *Client) {
+		c.connOpts = append(c.connOpts, WithLogger(logger))
+	}
+}
+
 // UserDataHandler handles user data stream events
 type UserDataHandler struct {
-	OnAccountUpdate    func(*AccountUpdateEvent) error
-	OnOrderUpdate      func(*OrderUpdateEvent) error
-	OnListenKeyExpired func() error
+	OnAccountUpdate        func(*AccountUpdateEvent) error
+	OnOrderUpdate          func(*OrderUpdateEvent) error
+	OnFuturesOrderUpdate   func(*FuturesOrderUpdateEvent) error
+	OnFuturesAccountUpdate func(*FuturesAccountUpdateEvent) error
+	OnListenKeyExpired     func() error
 }
 
 // HandleAccountUpdate implements UserStreamHandler
@@ -88,6 +174,22 @@ func (h *UserDataHandler) HandleOrderUpdate(event *OrderUpdateEvent) error {
 	return nil
 }
 
+// HandleFuturesOrderUpdate implements FuturesOrderUpdateHandler
+func (h *UserDataHandler) HandleFuturesOrderUpdate(event *FuturesOrderUpdateEvent) error {
+	if h.OnFuturesOrderUpdate != nil {
+		return h.OnFuturesOrderUpdate(event)
+	}
+	return nil
+}
+
+// HandleFuturesAccountUpdate implements FuturesAccountUpdateHandler
+func (h *UserDataHandler) HandleFuturesAccountUpdate(event *FuturesAccountUpdateEvent) error {
+	if h.OnFuturesAccountUpdate != nil {
+		return h.OnFuturesAccountUpdate(event)
+	}
+	return nil
+}
+
 // HandleListenKeyExpired implements UserStreamHandler
 func (h *UserDataHandler) HandleListenKeyExpired() error {
 	if h.OnListenKeyExpired != nil {
@@ -99,11 +201,14 @@ func (h *UserDataHandler) HandleListenKeyExpired() error {
 // NewClient creates a new WebSocket client
 func NewClient(opts ...ClientOption) *Client {
 	client := &Client{
-		baseURL:        DefaultBaseURL,
-		connections:    make(map[string]*StreamManager),
-		depthHandlers:  make(map[string]func(*DepthUpdateEvent) error),
-		tickerHandlers: make(map[string]func(*TickerEvent) error),
-		userHandlers:   make(map[string]*UserDataHandler),
+		baseURL:           DefaultBaseURL,
+		connections:       make(map[string]*StreamManager),
+		streamRoutes:      make(map[string]*StreamManager),
+		maxStreamsPerConn: MaxStreamsPerConnection,
+		claimed:           make(map[*StreamManager]int),
+		depthHandlers:     make(map[string]func(*DepthUpdateEvent) error),
+		tickerHandlers:    make(map[string]func(*TickerEvent) error),
+		userHandlers:      make(map[string]*UserDataHandler),
 	}
 
 	for _, opt := range opts {
@@ -129,20 +234,40 @@ func (c *Client) State() ConnectionState {
 	return c.streamMgr.State()
 }
 
+// UserDataState returns the connection state of the user data stream opened
+// for listenKey
+func (c *Client) UserDataState(listenKey string) ConnectionState {
+	c.connMu.RLock()
+	defer c.connMu.RUnlock()
+
+	mgr, exists := c.connections[listenKey]
+	if !exists {
+		return StateDisconnected
+	}
+	return mgr.State()
+}
+
 // Connect establishes the main WebSocket connection
 func (c *Client) Connect(ctx context.Context, opts ...ConnectionOption) error {
 	c.connMu.Lock()
-	defer c.connMu.Unlock()
-
-	if c.streamMgr == nil {
+	mgr := c.streamMgr
+	if mgr == nil {
 		// Create main stream manager for public streams
 		url := c.baseURL + "/ws/stream"
-		// Combine client options with any additional options passed to Connect
-		allOpts := append(c.connOpts, opts...)
-		c.streamMgr = NewStreamManager(url, allOpts...)
+		// Combine client options with any additional options passed to
+		// Connect, copying so neither slice's backing array is written
+		allOpts := make([]ConnectionOption, 0, len(c.connOpts)+len(opts))
+		allOpts = append(allOpts, c.connOpts...)
+		allOpts = append(allOpts, opts...)
+		mgr = NewStreamManager(url, allOpts...)
+		mgr.SetMaxStreams(c.maxStreamsPerConn)
+		c.streamMgr = mgr
+		c.connOpts = allOpts
 	}
+	c.connMu.Unlock()
 
-	return c.streamMgr.Connect(ctx)
+	// Dial and replay outside connMu so the client stays usable meanwhile
+	return mgr.Connect(ctx)
 }
 
 // Close closes all connections and cleans up resources
@@ -167,6 +292,9 @@ func (c *Client) Close() error {
 		}
 		delete(c.connections, key)
 	}
+	c.shards = nil
+	c.streamRoutes = make(map[string]*StreamManager)
+	c.claimed = make(map[*StreamManager]int)
 
 	// Clear handlers
 	c.handlersMu.Lock()
@@ -200,7 +328,7 @@ func (c *Client) ActiveSubscriptions() []string {
 }
 
 // SubscribeToDepth subscribes to order book depth updates for a symbol
-func (c *Client) SubscribeToDepth(ctx context.Context, symbol string, handler func(*DepthUpdateEvent) error) error {
+func (c *Client) SubscribeToDepth(ctx context.Context, symbol string, handler func(*DepthUpdateEvent) error, opts ...DepthOption) error {
 	if c.streamMgr == nil {
 		return fmt.Errorf("not connected")
 	}
@@ -209,15 +337,17 @@ func (c *Client) SubscribeToDepth(ctx context.Context, symbol string, handler fu
 	symbol = strings.ToLower(symbol)
 	stream := symbol + "@depth"
 
+	sub := &depthSubscription{}
+	for _, opt := range opts {
+		opt(sub)
+	}
+
 	// Store handler
 	c.handlersMu.Lock()
-	c.depthHandlers[symbol] = handler
+	c.depthHandlers[symbol] = sub.wrap(handler)
 	c.handlersMu.Unlock()
 
-	// Set up routing handler if not already set
-	c.streamMgr.SetDepthHandler(&clientDepthHandler{client: c})
-
-	return c.streamMgr.Subscribe(ctx, stream)
+	return c.subscribePublic(ctx, stream)
 }
 
 // SubscribeToTicker subscribes to 24hr ticker statistics for a symbol
@@ -235,10 +365,76 @@ func (c *Client) SubscribeToTicker(ctx context.Context, symbol string, handler f
 	c.tickerHandlers[symbol] = handler
 	c.handlersMu.Unlock()
 
-	// Set up routing handler if not already set
-	c.streamMgr.SetTickerHandler(&clientTickerHandler{client: c})
+	return c.subscribePublic(ctx, stream)
+}
+
+// AllTickersStream carries 24hr ticker statistics for every symbol that
+// changed, batched into one array per second
+const AllTickersStream = "!ticker@arr"
+
+// SubscribeToAllTickers subscribes to 24hr ticker statistics for the whole
+// market. The handler receives each batch as one slice.
+func (c *Client) SubscribeToAllTickers(ctx context.Context, handler func([]*TickerEvent) error) error {
+	if c.streamMgr == nil {
+		return fmt.Errorf("not connected")
+	}
 
-	return c.streamMgr.Subscribe(ctx, stream)
+	c.handlersMu.Lock()
+	c.allTickers = handler
+	c.handlersMu.Unlock()
+
+	return c.subscribePublic(ctx, AllTickersStream)
+}
+
+// LiquidationsStream carries every forced-liquidation order on USDT-M
+// futures. It is only served by the futures stream endpoint, so the client's
+// base URL must point there.
+const LiquidationsStream = "!forceOrder@arr"
+
+// SubscribeToLiquidations subscribes to market-wide futures liquidation
+// orders
+func (c *Client) SubscribeToLiquidations(ctx context.Context, handler func(*LiquidationEvent) error) error {
+	if c.streamMgr == nil {
+		return fmt.Errorf("not connected")
+	}
+
+	c.handlersMu.Lock()
+	c.liquidations = handler
+	c.handlersMu.Unlock()
+
+	return c.subscribePublic(ctx, LiquidationsStream)
+}
+
+// PublicBatch lists depth and ticker subscriptions to make together, each
+// keyed by symbol
+type PublicBatch struct {
+	Depth  map[string]func(*DepthUpdateEvent) error
+	Ticker map[string]func(*TickerEvent) error
+}
+
+// SubscribeBatch subscribes to every stream in batch with one SUBSCRIBE frame
+// per shard rather than one per stream
+func (c *Client) SubscribeBatch(ctx context.Context, batch PublicBatch) error {
+	if c.streamMgr == nil {
+		return fmt.Errorf("not connected")
+	}
+
+	var streams []string
+	c.handlersMu.Lock()
+	for symbol, handler := range batch.Depth {
+		symbol = strings.ToLower(symbol)
+		c.depthHandlers[symbol] = handler
+		streams = append(streams, symbol+"@depth")
+	}
+	for symbol, handler := range batch.Ticker {
+		symbol = strings.ToLower(symbol)
+		c.tickerHandlers[symbol] = handler
+		streams = append(streams, symbol+"@ticker")
+	}
+	c.handlersMu.Unlock()
+	sort.Strings(streams)
+
+	return c.subscribePublicBatch(ctx, streams)
 }
 
 // SubscribeToUserData subscribes to user data stream using a listen key
@@ -258,10 +454,17 @@ func (c *Client) SubscribeToUserData(ctx context.Context, listenKey string, hand
 	c.userHandlers[listenKey] = handler
 	c.handlersMu.Unlock()
 
-	// Set up routing handler
-	userMgr.SetUserStreamHandler(&clientUserStreamHandler{
-		client:    c,
-		listenKey: listenKey,
+	// Set up routing handler, and re-attach it after every reconnect for as
+	// long as this listen key is still subscribed
+	c.bindUserStream(userMgr, listenKey)
+	userMgr.SetReconnectHandler(func() {
+		c.handlersMu.RLock()
+		_, subscribed := c.userHandlers[listenKey]
+		c.handlersMu.RUnlock()
+
+		if subscribed {
+			c.bindUserStream(userMgr, listenKey)
+		}
 	})
 
 	// Connect if not already connected
@@ -274,6 +477,14 @@ func (c *Client) SubscribeToUserData(ctx context.Context, listenKey string, hand
 	return nil
 }
 
+// bindUserStream routes a user data connection's events to the client
+func (c *Client) bindUserStream(mgr *StreamManager, listenKey string) {
+	mgr.SetUserStreamHandler(&clientUserStreamHandler{
+		client:    c,
+		listenKey: listenKey,
+	})
+}
+
 // UnsubscribeFromDepth unsubscribes from depth updates for a symbol
 func (c *Client) UnsubscribeFromDepth(ctx context.Context, symbol string) error {
 	if c.streamMgr == nil {
@@ -289,7 +500,7 @@ func (c *Client) UnsubscribeFromDepth(ctx context.Context, symbol string) error
 	delete(c.depthHandlers, symbol)
 	c.handlersMu.Unlock()
 
-	return c.streamMgr.Unsubscribe(ctx, stream)
+	return c.unsubscribePublic(ctx, stream)
 }
 
 // UnsubscribeFromTicker unsubscribes from ticker updates for a symbol
@@ -307,7 +518,33 @@ func (c *Client) UnsubscribeFromTicker(ctx context.Context, symbol string) error
 	delete(c.tickerHandlers, symbol)
 	c.handlersMu.Unlock()
 
-	return c.streamMgr.Unsubscribe(ctx, stream)
+	return c.unsubscribePublic(ctx, stream)
+}
+
+// UnsubscribeFromAllTickers unsubscribes from the market-wide ticker stream
+func (c *Client) UnsubscribeFromAllTickers(ctx context.Context) error {
+	if c.streamMgr == nil {
+		return fmt.Errorf("not connected")
+	}
+
+	c.handlersMu.Lock()
+	c.allTickers = nil
+	c.handlersMu.Unlock()
+
+	return c.unsubscribePublic(ctx, AllTickersStream)
+}
+
+// UnsubscribeFromLiquidations unsubscribes from the liquidation stream
+func (c *Client) UnsubscribeFromLiquidations(ctx context.Context) error {
+	if c.streamMgr == nil {
+		return fmt.Errorf("not connected")
+	}
+
+	c.handlersMu.Lock()
+	c.liquidations = nil
+	c.handlersMu.Unlock()
+
+	return c.unsubscribePublic(ctx, LiquidationsStream)
 }
 
 // UnsubscribeFromUserData unsubscribes from user data stream
@@ -330,6 +567,263 @@ func (c *Client) UnsubscribeFromUserData(ctx context.Context, listenKey string)
 	return nil
 }
 
+// subscribePublic subscribes to a public stream on the first shard with spare
+// capacity, opening a new shard connection when all are full. The shard is
+// picked under connMu but dialled and subscribed outside it.
+func (c *Client) subscribePublic(ctx context.Context, stream string) error {
+	c.connMu.Lock()
+	if c.streamMgr == nil {
+		c.connMu.Unlock()
+		return fmt.Errorf("not connected")
+	}
+	mgr, exists := c.streamRoutes[stream]
+	claimed := 0
+	if !exists {
+		mgr, claimed = c.claimShard(1)
+	}
+	c.connMu.Unlock()
+
+	if mgr == nil {
+		var err error
+		if mgr, claimed, err = c.openShard(ctx, 1); err != nil {
+			return err
+		}
+	}
+
+	c.setPublicHandlers(mgr)
+	err := mgr.Subscribe(ctx, stream)
+	return c.finishSubscribe(mgr, claimed, []string{stream}, err)
+}
+
+// subscribePublicBatch spreads streams over shards with spare capacity,
+// opening new shards as needed, and subscribes each shard's share at once
+func (c *Client) subscribePublicBatch(ctx context.Context, streams []string) error {
+	c.connMu.RLock()
+	connected := c.streamMgr != nil
+	var pending []string
+	for _, stream := range streams {
+		if _, exists := c.streamRoutes[stream]; !exists {
+			pending = append(pending, stream)
+		}
+	}
+	c.connMu.RUnlock()
+
+	if !connected {
+		return fmt.Errorf("not connected")
+	}
+
+	for len(pending) > 0 {
+		c.connMu.Lock()
+		if c.streamMgr == nil {
+			c.connMu.Unlock()
+			return fmt.Errorf("not connected")
+		}
+		mgr, n := c.claimShard(len(pending))
+		c.connMu.Unlock()
+
+		if mgr == nil {
+			var err error
+			if mgr, n, err = c.openShard(ctx, len(pending)); err != nil {
+				return err
+			}
+		}
+		share := pending[:n]
+		pending = pending[n:]
+
+		c.setPublicHandlers(mgr)
+		err := mgr.SubscribeMultiple(ctx, share)
+		if err := c.finishSubscribe(mgr, n, share, err); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// finishSubscribe releases the capacity claimed for streams on mgr and, when
+// the subscribe succeeded, routes them to it
+func (c *Client) finishSubscribe(mgr *StreamManager, claimed int, streams []string, err error) error {
+	c.connMu.Lock()
+	defer c.connMu.Unlock()
+
+	if claimed > 0 {
+		if c.claimed[mgr] -= claimed; c.claimed[mgr] <= 0 {
+			delete(c.claimed, mgr)
+		}
+	}
+	if err != nil {
+		return err
+	}
+	if c.streamMgr == nil {
+		return fmt.Errorf("not connected")
+	}
+	for _, stream := range streams {
+		c.streamRoutes[stream] = mgr
+	}
+	return nil
+}
+
+// unsubscribePublic unsubscribes a public stream on the shard that owns it and
+// closes overflow shards once they are empty
+func (c *Client) unsubscribePublic(ctx context.Context, stream string) error {
+	c.connMu.RLock()
+	if c.streamMgr == nil {
+		c.connMu.RUnlock()
+		return fmt.Errorf("not connected")
+	}
+	mgr, exists := c.streamRoutes[stream]
+	if !exists {
+		mgr = c.streamMgr
+	}
+	c.connMu.RUnlock()
+
+	if err := mgr.Unsubscribe(ctx, stream); err != nil {
+		return err
+	}
+
+	c.connMu.Lock()
+	defer c.connMu.Unlock()
+
+	if c.streamRoutes[stream] == mgr {
+		delete(c.streamRoutes, stream)
+	}
+	if mgr != c.streamMgr && mgr.StreamCount() == 0 && c.claimed[mgr] == 0 {
+		c.removeShard(mgr)
+	}
+	return nil
+}
+
+// claimShard claims room for up to want streams on the first shard with
+// spare capacity, returning the shard and how many streams it can take, or
+// nil when every shard is full. Callers must hold connMu.
+func (c *Client) claimShard(want int) (*StreamManager, int) {
+	for _, mgr := range append([]*StreamManager{c.streamMgr}, c.shards...) {
+		if free := mgr.MaxStreams() - mgr.StreamCount() - c.claimed[mgr]; free > 0 {
+			n := min(free, want)
+			c.claimed[mgr] += n
+			return mgr, n
+		}
+	}
+	return nil, 0
+}
+
+// openShard dials a new shard without holding connMu and claims room for up
+// to want streams on it
+func (c *Client) openShard(ctx context.Context, want int) (*StreamManager, int, error) {
+	c.connMu.RLock()
+	url := c.baseURL + "/ws/stream"
+	opts := c.connOpts
+	c.connMu.RUnlock()
+
+	mgr := NewStreamManager(url, opts...)
+	mgr.SetMaxStreams(c.maxStreamsPerConn)
+	if err := mgr.Connect(ctx); err != nil {
+		return nil, 0, fmt.Errorf("failed to open stream shard: %w", err)
+	}
+
+	c.connMu.Lock()
+	defer c.connMu.Unlock()
+
+	// The client may have closed while the shard was dialling
+	if c.streamMgr == nil {
+		mgr.Close()
+		return nil, 0, fmt.Errorf("not connected")
+	}
+	c.shards = append(c.shards, mgr)
+	c.connections[shardKey(mgr)] = mgr
+	n := min(mgr.MaxStreams(), want)
+	c.claimed[mgr] += n
+	return mgr, n, nil
+}
+
+// removeShard closes an overflow shard. Callers must hold connMu.
+func (c *Client) removeShard(mgr *StreamManager) {
+	for i, shard := range c.shards {
+		if shard == mgr {
+			c.shards = append(c.shards[:i], c.shards[i+1:]...)
+			break
+		}
+	}
+	delete(c.connections, shardKey(mgr))
+	mgr.Close()
+}
+
+// setPublicHandlers routes depth and ticker events from a shard to the client
+func (c *Client) setPublicHandlers(mgr *StreamManager) {
+	mgr.SetDepthHandler(&clientDepthHandler{client: c})
+	mgr.SetTickerHandler(&clientTickerHandler{client: c})
+	mgr.SetAllTickersHandler(&clientAllTickersHandler{client: c})
+	mgr.SetLiquidationHandler(&clientLiquidationHandler{client: c})
+}
+
+// Stats sums traffic across every connection the client holds. Open
+// connections are counted, so closed shards and user streams drop out.
+// ConnectedSince is that of the main connection.
+func (c *Client) Stats() ConnectionStats {
+	c.connMu.RLock()
+	defer c.connMu.RUnlock()
+
+	var stats ConnectionStats
+	if c.streamMgr == nil {
+		return stats
+	}
+
+	mgrs := []*StreamManager{c.streamMgr}
+	for _, mgr := range c.connections {
+		mgrs = append(mgrs, mgr)
+	}
+	for _, mgr := range mgrs {
+		s := mgr.Stats()
+		stats.MessagesReceived += s.MessagesReceived
+		stats.MessagesSent += s.MessagesSent
+		stats.BytesReceived += s.BytesReceived
+		stats.BytesSent += s.BytesSent
+		if s.LastActivity.After(stats.LastActivity) {
+			stats.LastActivity = s.LastActivity
+		}
+	}
+	stats.ConnectedSince = c.streamMgr.Stats().ConnectedSince
+	return stats
+}
+
+// StreamActivity returns per-stream message counts across every connection
+func (c *Client) StreamActivity() map[string]StreamActivity {
+	c.connMu.RLock()
+	defer c.connMu.RUnlock()
+
+	activity := make(map[string]StreamActivity)
+	if c.streamMgr == nil {
+		return activity
+	}
+
+	mgrs := []*StreamManager{c.streamMgr}
+	for _, mgr := range c.connections {
+		mgrs = append(mgrs, mgr)
+	}
+	for _, mgr := range mgrs {
+		for stream, a := range mgr.StreamActivity() {
+			activity[stream] = a
+		}
+	}
+	return activity
+}
+
+// ShardCount returns the number of connections carrying public streams
+func (c *Client) ShardCount() int {
+	c.connMu.RLock()
+	defer c.connMu.RUnlock()
+
+	if c.streamMgr == nil {
+		return 0
+	}
+	return 1 + len(c.shards)
+}
+
+// shardKey names an overflow shard in connections. Listen keys never
+// start with "shard:", so these cannot collide with user data streams.
+func shardKey(mgr *StreamManager) string {
+	return fmt.Sprintf("shard:%p", mgr)
+}
+
 // Handler implementations for routing events to user-provided handlers
 
 type clientDepthHandler struct {
@@ -362,11 +856,46 @@ func (h *clientTickerHandler) HandleTickerUpdate(event *TickerEvent) error {
 	return nil
 }
 
+type clientAllTickersHandler struct {
+	client *Client
+}
+
+func (h *clientAllTickersHandler) HandleAllTickers(events []*TickerEvent) error {
+	h.client.handlersMu.RLock()
+	handler := h.client.allTickers
+	h.client.handlersMu.RUnlock()
+
+	if handler != nil {
+		return handler(events)
+	}
+	return nil
+}
+
+type clientLiquidationHandler struct {
+	client *Client
+}
+
+func (h *clientLiquidationHandler) HandleLiquidation(event *LiquidationEvent) error {
+	h.client.handlersMu.RLock()
+	handler := h.client.liquidations
+	h.client.handlersMu.RUnlock()
+
+	if handler != nil {
+		return handler(event)
+	}
+	return nil
+}
+
 type clientUserStreamHandler struct {
 	client    *Client
 	listenKey string
 }
 
+var (
+	_ FuturesOrderUpdateHandler   = (*clientUserStreamHandler)(nil)
+	_ FuturesAccountUpdateHandler = (*clientUserStreamHandler)(nil)
+)
+
 func (h *clientUserStreamHandler) HandleAccountUpdate(event *AccountUpdateEvent) error {
 	h.client.handlersMu.RLock()
 	handler, exists := h.client.userHandlers[h.listenKey]
@@ -389,6 +918,28 @@ func (h *clientUserStreamHandler) HandleOrderUpdate(event *OrderUpdateEvent) err
 	return nil
 }
 
+func (h *clientUserStreamHandler) HandleFuturesOrderUpdate(event *FuturesOrderUpdateEvent) error {
+	h.client.handlersMu.RLock()
+	handler, exists := h.client.userHandlers[h.listenKey]
+	h.client.handlersMu.RUnlock()
+
+	if exists && handler != nil {
+		return handler.HandleFuturesOrderUpdate(event)
+	}
+	return nil
+}
+
+func (h *clientUserStreamHandler) HandleFuturesAccountUpdate(event *FuturesAccountUpdateEvent) error {
+	h.client.handlersMu.RLock()
+	handler, exists := h.client.userHandlers[h.listenKey]
+	h.client.handlersMu.RUnlock()
+
+	if exists && handler != nil {
+		return handler.HandleFuturesAccountUpdate(event)
+	}
+	return nil
+}
+
 func (h *clientUserStreamHandler) HandleListenKeyExpired() error {
 	h.client.handlersMu.RLock()
 	handler, exists := h.client.userHandlers[h.listenKey]
@@ -3,13 +3,18 @@ package websocket
 import (
 	"context"
 	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
 	"sync"
+	"sync/atomic"
 	"testing"
 	"time"
 
 	"github.com/gorilla/websocket"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	"go.uber.org/goleak"
 )
 
 func TestClient_NewClient(t *testing.T) {
@@ -266,6 +271,336 @@ func TestClient_SubscribeToUserData(t *testing.T) {
 	})
 }
 
+// newUserStreamServer pushes each raw frame to the client, as /ws/<listenKey>
+// does, then holds the connection open
+func newUserStreamServer(t *testing.T, frames ...string) *httptest.Server {
+	t.Helper()
+	return newMockWebSocketServer(t, func(conn *websocket.Conn) {
+		defer conn.Close()
+		for _, frame := range frames {
+			conn.WriteMessage(websocket.TextMessage, []byte(frame))
+		}
+		for {
+			if _, _, err := conn.ReadMessage(); err != nil {
+				return
+			}
+		}
+	})
+}
+
+func TestClient_SubscribeToUserData_FuturesOrderUpdate(t *testing.T) {
+	server := newUserStreamServer(t, `{"e":"ORDER_TRADE_UPDATE","E":1,"T":1,
+		"o":{"s":"BTCUSDT","c":"bracket-1-SL","X":"FILLED","ap":"49000"}}`)
+	defer server.Close()
+
+	client := NewClient(WithBaseURL(getWebSocketURL(server.URL)))
+	defer client.Close()
+
+	updates := make(chan *FuturesOrderUpdateEvent, 1)
+	require.NoError(t, client.SubscribeToUserData(context.Background(), "futures-key", &UserDataHandler{
+		OnFuturesOrderUpdate: func(event *FuturesOrderUpdateEvent) error {
+			updates <- event
+			return nil
+		},
+	}))
+
+	select {
+	case event := <-updates:
+		assert.Equal(t, "bracket-1-SL", event.ClientOrderID)
+		assert.Equal(t, "49000", event.AveragePrice.String())
+	case <-time.After(time.Second):
+		t.Fatal("futures order update not received")
+	}
+}
+
+func TestClient_SubscribeToUserData_FuturesAccountUpdate(t *testing.T) {
+	server := newUserStreamServer(t, `{"e":"ACCOUNT_UPDATE","E":1,"T":1,
+		"a":{"m":"ORDER","B":[{"a":"USDT","wb":"1000","cw":"1000","bc":"0"}],
+		"P":[{"s":"BTCUSDT","pa":"0.01","ep":"50000","ps":"BOTH"}]}}`)
+	defer server.Close()
+
+	client := NewClient(WithBaseURL(getWebSocketURL(server.URL)))
+	defer client.Close()
+
+	updates := make(chan *FuturesAccountUpdateEvent, 1)
+	require.NoError(t, client.SubscribeToUserData(context.Background(), "futures-key", &UserDataHandler{
+		OnFuturesAccountUpdate: func(event *FuturesAccountUpdateEvent) error {
+			updates <- event
+			return nil
+		},
+	}))
+
+	select {
+	case event := <-updates:
+		assert.Equal(t, "ORDER", event.Reason)
+		require.Len(t, event.Positions, 1)
+		assert.Equal(t, "0.01", event.Positions[0].PositionAmount.String())
+	case <-time.After(time.Second):
+		t.Fatal("futures account update not received")
+	}
+}
+
+func TestClient_UserDataReconnect(t *testing.T) {
+	orderUpdates := make(chan *OrderUpdateEvent, 4)
+	firstDelivered := make(chan struct{})
+	var connections int32
+
+	server := newMockWebSocketServer(t, func(conn *websocket.Conn) {
+		defer conn.Close()
+		n := atomic.AddInt32(&connections, 1)
+
+		if n == 1 {
+			conn.WriteJSON(StreamMessage{
+				Stream: "flap-key",
+				Data:   json.RawMessage(`{"e":"executionReport","s":"BTCUSDT","c":"before","X":"NEW"}`),
+			})
+			// Drop the connection once the first update has been handled
+			<-firstDelivered
+			return
+		}
+
+		// Give the stream manager time to notice the reconnect and re-bind
+		time.Sleep(300 * time.Millisecond)
+		conn.WriteJSON(StreamMessage{
+			Stream: "flap-key",
+			Data:   json.RawMessage(`{"e":"executionReport","s":"BTCUSDT","c":"after","X":"FILLED"}`),
+		})
+		for {
+			if _, _, err := conn.ReadMessage(); err != nil {
+				return
+			}
+		}
+	})
+	defer server.Close()
+
+	client := NewClient(
+		WithBaseURL(getWebSocketURL(server.URL)),
+		WithAutoReconnectClient(true),
+		WithReconnectIntervalClient(20*time.Millisecond),
+		WithMaxReconnectAttemptsClient(5),
+	)
+	defer client.Close()
+
+	handler := &UserDataHandler{
+		OnOrderUpdate: func(event *OrderUpdateEvent) error {
+			orderUpdates <- event
+			return nil
+		},
+	}
+	require.NoError(t, client.SubscribeToUserData(context.Background(), "flap-key", handler))
+
+	select {
+	case event := <-orderUpdates:
+		assert.Equal(t, "before", event.ClientOrderID)
+	case <-time.After(time.Second):
+		t.Fatal("Order update before reconnect not received")
+	}
+
+	// Simulate the binding being lost across the flap; reconnect must restore it
+	client.connMu.RLock()
+	userMgr := client.connections["flap-key"]
+	client.connMu.RUnlock()
+	userMgr.SetUserStreamHandler(nil)
+	close(firstDelivered)
+
+	select {
+	case event := <-orderUpdates:
+		assert.Equal(t, "after", event.ClientOrderID)
+		assert.Equal(t, "FILLED", event.OrderStatus)
+	case <-time.After(3 * time.Second):
+		t.Fatal("Order update after reconnect not routed to handler")
+	}
+	assert.GreaterOrEqual(t, atomic.LoadInt32(&connections), int32(2))
+}
+
+func TestClient_SubscribeToAllTickers(t *testing.T) {
+	requests := make(chan SubscriptionRequest, 2)
+
+	server := newMockWebSocketServer(t, func(conn *websocket.Conn) {
+		defer conn.Close()
+
+		var req SubscriptionRequest
+		conn.ReadJSON(&req)
+		requests <- req
+		conn.WriteJSON(SubscriptionResponse{ID: req.ID})
+
+		conn.WriteMessage(websocket.TextMessage, []byte(`{"stream":"!ticker@arr","data":[
+			{"e":"24hrTicker","s":"BTCUSDT","c":"50000.01"},
+			{"e":"24hrTicker","s":"ETHUSDT","c":"3000.5"}
+		]}`))
+
+		for {
+			var req SubscriptionRequest
+			if err := conn.ReadJSON(&req); err != nil {
+				return
+			}
+			requests <- req
+			conn.WriteJSON(SubscriptionResponse{ID: req.ID})
+		}
+	})
+	defer server.Close()
+
+	client := NewClient(WithBaseURL(getWebSocketURL(server.URL)))
+	ctx := context.Background()
+	require.NoError(t, client.Connect(ctx))
+	defer client.Close()
+
+	batches := make(chan []*TickerEvent, 1)
+	require.NoError(t, client.SubscribeToAllTickers(ctx, func(events []*TickerEvent) error {
+		batches <- events
+		return nil
+	}))
+
+	req := <-requests
+	assert.Equal(t, []string{AllTickersStream}, req.Params)
+
+	select {
+	case events := <-batches:
+		require.Len(t, events, 2)
+		assert.Equal(t, "BTCUSDT", events[0].Symbol)
+		assert.Equal(t, "ETHUSDT", events[1].Symbol)
+	case <-time.After(time.Second):
+		t.Fatal("ticker array not delivered")
+	}
+
+	require.NoError(t, client.UnsubscribeFromAllTickers(ctx))
+	req = <-requests
+	assert.Equal(t, "UNSUBSCRIBE", req.Method)
+	assert.NotContains(t, client.ActiveSubscriptions(), AllTickersStream)
+}
+
+func TestClient_SubscribeToLiquidations(t *testing.T) {
+	requests := make(chan SubscriptionRequest, 2)
+
+	server := newMockWebSocketServer(t, func(conn *websocket.Conn) {
+		defer conn.Close()
+
+		var req SubscriptionRequest
+		conn.ReadJSON(&req)
+		requests <- req
+		conn.WriteJSON(SubscriptionResponse{ID: req.ID})
+
+		conn.WriteMessage(websocket.TextMessage, []byte(`{"stream":"!forceOrder@arr","data":{
+			"e":"forceOrder","E":1568014460893,
+			"o":{"s":"BTCUSDT","S":"SELL","o":"LIMIT","f":"IOC","q":"0.014","p":"9910",
+				"ap":"9910.5","X":"FILLED","l":"0.014","z":"0.014","T":1568014460891}}}`))
+
+		for {
+			var req SubscriptionRequest
+			if err := conn.ReadJSON(&req); err != nil {
+				return
+			}
+			requests <- req
+			conn.WriteJSON(SubscriptionResponse{ID: req.ID})
+		}
+	})
+	defer server.Close()
+
+	client := NewClient(WithBaseURL(getWebSocketURL(server.URL)))
+	ctx := context.Background()
+	require.NoError(t, client.Connect(ctx))
+	defer client.Close()
+
+	liquidations := make(chan *LiquidationEvent, 1)
+	require.NoError(t, client.SubscribeToLiquidations(ctx, func(event *LiquidationEvent) error {
+		liquidations <- event
+		return nil
+	}))
+
+	req := <-requests
+	assert.Equal(t, []string{LiquidationsStream}, req.Params)
+
+	select {
+	case event := <-liquidations:
+		assert.Equal(t, "forceOrder", event.EventType)
+		assert.Equal(t, "BTCUSDT", event.Symbol)
+		assert.Equal(t, "SELL", event.Side)
+		assert.Equal(t, "9910", event.Price.String())
+		assert.Equal(t, "9910.5", event.AveragePrice.String())
+		assert.Equal(t, "0.014", event.Quantity.String())
+		assert.Equal(t, "FILLED", event.Status)
+		assert.Equal(t, int64(1568014460891), event.Time)
+	case <-time.After(time.Second):
+		t.Fatal("liquidation not delivered")
+	}
+
+	require.NoError(t, client.UnsubscribeFromLiquidations(ctx))
+	req = <-requests
+	assert.Equal(t, "UNSUBSCRIBE", req.Method)
+	assert.NotContains(t, client.ActiveSubscriptions(), LiquidationsStream)
+}
+
+func TestClient_Stats(t *testing.T) {
+	server := newMockWebSocketServer(t, func(conn *websocket.Conn) {
+		defer conn.Close()
+		for {
+			var req SubscriptionRequest
+			if err := conn.ReadJSON(&req); err != nil {
+				return
+			}
+			conn.WriteJSON(SubscriptionResponse{ID: req.ID})
+		}
+	})
+	defer server.Close()
+
+	client := NewClient(WithBaseURL(getWebSocketURL(server.URL)))
+	assert.Equal(t, ConnectionStats{}, client.Stats(), "no stats before connecting")
+
+	ctx := context.Background()
+	require.NoError(t, client.Connect(ctx))
+	defer client.Close()
+	require.NoError(t, client.SubscribeToDepth(ctx, "BTCUSDT", func(*DepthUpdateEvent) error { return nil }))
+
+	frame, err := json.Marshal(SubscriptionRequest{Method: "SUBSCRIBE", Params: []string{"btcusdt@depth"}, ID: 1})
+	require.NoError(t, err)
+
+	stats := client.Stats()
+	assert.Equal(t, int64(1), stats.MessagesSent)
+	assert.Equal(t, int64(len(frame)), stats.BytesSent)
+	assert.Equal(t, int64(1), stats.MessagesReceived)
+	assert.Positive(t, stats.BytesReceived)
+	assert.False(t, stats.ConnectedSince.IsZero())
+}
+
+func TestClient_SubscribeBatch(t *testing.T) {
+	frames := make(chan SubscriptionRequest, 4)
+	server := newMockWebSocketServer(t, func(conn *websocket.Conn) {
+		defer conn.Close()
+		for {
+			var req SubscriptionRequest
+			if err := conn.ReadJSON(&req); err != nil {
+				return
+			}
+			frames <- req
+			conn.WriteJSON(SubscriptionResponse{ID: req.ID})
+		}
+	})
+	defer server.Close()
+
+	client := NewClient(WithBaseURL(getWebSocketURL(server.URL)))
+	ctx := context.Background()
+	require.NoError(t, client.Connect(ctx))
+	defer client.Close()
+
+	noDepth := func(*DepthUpdateEvent) error { return nil }
+	noTicker := func(*TickerEvent) error { return nil }
+	require.NoError(t, client.SubscribeToDepth(ctx, "BTCUSDT", noDepth))
+	<-frames
+
+	err := client.SubscribeBatch(ctx, PublicBatch{
+		Depth:  map[string]func(*DepthUpdateEvent) error{"BTCUSDT": noDepth, "ETHUSDT": noDepth},
+		Ticker: map[string]func(*TickerEvent) error{"ETHUSDT": noTicker, "BNBUSDT": noTicker},
+	})
+	require.NoError(t, err)
+
+	frame := <-frames
+	assert.Equal(t, "SUBSCRIBE", frame.Method)
+	assert.Equal(t, []string{"bnbusdt@ticker", "ethusdt@depth", "ethusdt@ticker"}, frame.Params,
+		"one frame carries every stream not already subscribed")
+	assert.Empty(t, frames)
+	assert.ElementsMatch(t, []string{"btcusdt@depth", "bnbusdt@ticker", "ethusdt@depth", "ethusdt@ticker"}, client.ActiveSubscriptions())
+}
+
 func TestClient_MultipleSubscriptions(t *testing.T) {
 	t.Run("handles multiple concurrent subscriptions", func(t *testing.T) {
 		depthUpdates := make(chan string, 5)
@@ -441,3 +776,224 @@ func TestClient_Close(t *testing.T) {
 		assert.Empty(t, client.ActiveSubscriptions())
 	})
 }
+
+func TestClient_ConnectDoesNotHoldLock(t *testing.T) {
+	upgrader := websocket.Upgrader{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		time.Sleep(300 * time.Millisecond) // slow handshake
+		conn, err := upgrader.Upgrade(w, r, nil)
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		for {
+			if _, _, err := conn.ReadMessage(); err != nil {
+				return
+			}
+		}
+	}))
+	defer server.Close()
+
+	client := NewClient(WithBaseURL(getWebSocketURL(server.URL)))
+	defer client.Close()
+
+	connected := make(chan error, 1)
+	go func() { connected <- client.Connect(context.Background()) }()
+	time.Sleep(50 * time.Millisecond)
+
+	start := time.Now()
+	client.ActiveSubscriptions()
+	assert.Less(t, time.Since(start), 100*time.Millisecond, "reads must not wait for the dial")
+	require.NoError(t, <-connected)
+}
+
+func TestClient_SubscribeDoesNotHoldLock(t *testing.T) {
+	// Every connection after the first dials slowly, and every subscribe
+	// is answered slowly
+	var dials int32
+	upgrader := websocket.Upgrader{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if atomic.AddInt32(&dials, 1) > 1 {
+			time.Sleep(300 * time.Millisecond)
+		}
+		conn, err := upgrader.Upgrade(w, r, nil)
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		for {
+			var req SubscriptionRequest
+			if err := conn.ReadJSON(&req); err != nil {
+				return
+			}
+			time.Sleep(300 * time.Millisecond)
+			conn.WriteJSON(SubscriptionResponse{ID: req.ID})
+		}
+	}))
+	defer server.Close()
+
+	client := NewClient(WithBaseURL(getWebSocketURL(server.URL)), WithMaxStreamsPerConnection(1))
+	require.NoError(t, client.Connect(context.Background()))
+	defer client.Close()
+
+	noop := func(*TickerEvent) error { return nil }
+	assertReadsUnblocked := func(t *testing.T, subscribe func() error) {
+		t.Helper()
+		done := make(chan error, 1)
+		go func() { done <- subscribe() }()
+		time.Sleep(50 * time.Millisecond)
+
+		start := time.Now()
+		client.State()
+		client.Stats()
+		assert.Less(t, time.Since(start), 100*time.Millisecond, "reads must not wait for the subscribe")
+		require.NoError(t, <-done)
+	}
+
+	t.Run("subscribe round-trip", func(t *testing.T) {
+		assertReadsUnblocked(t, func() error {
+			return client.SubscribeToTicker(context.Background(), "BTCUSDT", noop)
+		})
+	})
+
+	t.Run("shard dial", func(t *testing.T) {
+		assertReadsUnblocked(t, func() error {
+			return client.SubscribeToTicker(context.Background(), "ETHUSDT", noop)
+		})
+		assert.Equal(t, 2, client.ShardCount())
+	})
+}
+
+func TestClient_ConnectCopiesOptions(t *testing.T) {
+	// Spare capacity in the client's options must not be written through
+	opts := make([]ConnectionOption, 0, 4)
+	client := NewClient()
+	client.connOpts = append(opts, WithAutoReconnect(false))
+
+	server := newMockWebSocketServer(t, func(conn *websocket.Conn) {
+		defer conn.Close()
+		for {
+			if _, _, err := conn.ReadMessage(); err != nil {
+				return
+			}
+		}
+	})
+	defer server.Close()
+	client.baseURL = getWebSocketURL(server.URL)
+
+	require.NoError(t, client.Connect(context.Background(), WithReadLimit(1024)))
+	defer client.Close()
+
+	assert.Nil(t, opts[:2][1], "Connect appended into the client's backing array")
+	assert.Len(t, client.connOpts, 2)
+}
+
+func TestClient_NoGoroutineLeak(t *testing.T) {
+	server := newMockWebSocketServer(t, func(conn *websocket.Conn) {
+		defer conn.Close()
+		for {
+			var req SubscriptionRequest
+			if err := conn.ReadJSON(&req); err != nil {
+				return
+			}
+			conn.WriteJSON(SubscriptionResponse{Result: nil, ID: req.ID})
+		}
+	})
+	defer server.Close()
+
+	for _, standby := range []bool{false, true} {
+		t.Run(fmt.Sprintf("standby=%v", standby), func(t *testing.T) {
+			// The mock server's goroutines outlive each client
+			defer goleak.VerifyNone(t, goleak.IgnoreCurrent())
+
+			for i := 0; i < 5; i++ {
+				client := NewClient(WithBaseURL(getWebSocketURL(server.URL)))
+				ctx := context.Background()
+
+				require.NoError(t, client.Connect(ctx, WithStandbyConnection(standby)))
+				require.NoError(t, client.SubscribeToDepth(ctx, "BTCUSDT", func(event *DepthUpdateEvent) error {
+					return nil
+				}))
+				require.NoError(t, client.Close())
+			}
+		})
+	}
+}
+
+func TestClient_Sharding(t *testing.T) {
+	var mu sync.Mutex
+	streamsPerConn := make(map[*websocket.Conn]map[string]bool)
+
+	server := newMockWebSocketServer(t, func(conn *websocket.Conn) {
+		defer conn.Close()
+		mu.Lock()
+		streamsPerConn[conn] = make(map[string]bool)
+		mu.Unlock()
+
+		for {
+			var req SubscriptionRequest
+			if err := conn.ReadJSON(&req); err != nil {
+				return
+			}
+			mu.Lock()
+			for _, stream := range req.Params {
+				if req.Method == "SUBSCRIBE" {
+					streamsPerConn[conn][stream] = true
+				} else {
+					delete(streamsPerConn[conn], stream)
+				}
+			}
+			mu.Unlock()
+			conn.WriteJSON(SubscriptionResponse{ID: req.ID})
+		}
+	})
+	defer server.Close()
+
+	client := NewClient(
+		WithBaseURL(getWebSocketURL(server.URL)),
+		WithMaxStreamsPerConnection(2),
+	)
+	ctx := context.Background()
+	require.NoError(t, client.Connect(ctx))
+	defer client.Close()
+
+	symbols := []string{"BTCUSDT", "ETHUSDT", "BNBUSDT", "SOLUSDT", "XRPUSDT"}
+	for _, symbol := range symbols {
+		require.NoError(t, client.SubscribeToDepth(ctx, symbol, func(*DepthUpdateEvent) error { return nil }))
+	}
+
+	assert.Equal(t, 3, client.ShardCount())
+	assert.Len(t, client.ActiveSubscriptions(), 5)
+
+	mu.Lock()
+	for _, streams := range streamsPerConn {
+		assert.LessOrEqual(t, len(streams), 2)
+	}
+	mu.Unlock()
+
+	t.Run("resubscribing an existing stream stays on its shard", func(t *testing.T) {
+		require.NoError(t, client.SubscribeToDepth(ctx, "BTCUSDT", func(*DepthUpdateEvent) error { return nil }))
+		assert.Equal(t, 3, client.ShardCount())
+		assert.Len(t, client.ActiveSubscriptions(), 5)
+	})
+
+	t.Run("unsubscribe routes to owning shard and closes it when empty", func(t *testing.T) {
+		require.NoError(t, client.UnsubscribeFromDepth(ctx, "XRPUSDT"))
+		assert.Equal(t, 2, client.ShardCount())
+		assert.NotContains(t, client.ActiveSubscriptions(), "xrpusdt@depth")
+
+		mu.Lock()
+		for _, streams := range streamsPerConn {
+			assert.False(t, streams["xrpusdt@depth"])
+		}
+		mu.Unlock()
+	})
+
+	t.Run("freed capacity is reused before opening a shard", func(t *testing.T) {
+		require.NoError(t, client.UnsubscribeFromDepth(ctx, "ETHUSDT"))
+		require.NoError(t, client.SubscribeToTicker(ctx, "ADAUSDT", func(*TickerEvent) error { return nil }))
+
+		assert.Equal(t, 2, client.ShardCount())
+		assert.Contains(t, client.ActiveSubscriptions(), "adausdt@ticker")
+	})
+}
@@ -2,27 +2,50 @@ package websocket
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"math/rand"
+	"net"
+	"runtime/debug"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/gorilla/websocket"
+	"github.com/rs/zerolog"
+
+	"router/internal/lifecycle"
 )
 
+// ErrHandlerPanic is reported to the handler error callback when a message
+// handler panics
+var ErrHandlerPanic = errors.New("message handler panicked")
+
 // Connection represents a WebSocket connection with reconnection capabilities
 type Connection struct {
 	url     string
 	state   ConnectionState
 	stateMu sync.RWMutex
 
+	// generation increments on every successful dial
+	generation uint64
+
 	// Connection options
 	pingInterval         time.Duration
 	pongTimeout          time.Duration
 	writeTimeout         time.Duration
 	readTimeout          time.Duration
+	readLimit            int64
+	handshakeTimeout     time.Duration
+	readBufferSize       int
+	writeBufferSize      int
+	compression          bool
 	autoReconnect        bool
 	maxReconnectAttempts int
 	reconnectInterval    time.Duration
+	maxReconnectInterval time.Duration
+	subscribeRetries     int
+	subscribeRetryDelay  time.Duration
 
 	// Pong tracking
 	lastPongTime time.Time
@@ -33,22 +56,82 @@ type Connection struct {
 	connMu  sync.Mutex
 	writeMu sync.Mutex // Protects writes to WebSocket
 
+	// Warm standby: a second dialed connection, guarded by connMu, that is
+	// promoted when the primary fails
+	standbyEnabled  bool
+	standby         *websocket.Conn
+	standbyDialing  atomic.Bool
+	failoverHandler func()
+
 	// Message handling
 	messageHandler func([]byte)
+	handlerErrorFn func(error)
 	handlerMu      sync.RWMutex
+	logger         zerolog.Logger
 
-	// Control channels
-	closeChan chan struct{}
-	doneChan  chan struct{}
-	doneOnce  sync.Once
-	doneMutex sync.Mutex // Protects doneChan recreation
+	// Background loops (ping, read, standby, reconnection) run in group, which
+	// Close stops and joins. Connect after Close starts a fresh group.
+	group   *lifecycle.Group
+	groupMu sync.Mutex
 
 	// Reconnection state
 	reconnectAttempts int
 	reconnecting      bool
+	nextReconnect     time.Duration
 	reconnectMu       sync.Mutex
+
+	// jitter picks the actual wait for a computed backoff; full jitter by
+	// default, replaceable in tests
+	jitter func(backoff time.Duration) time.Duration
+
+	metrics MetricsRecorder
+
+	// Traffic counters, cumulative across reconnects
+	messagesReceived atomic.Int64
+	messagesSent     atomic.Int64
+	bytesReceived    atomic.Int64
+	bytesSent        atomic.Int64
+	sendErrors       atomic.Int64
+	connectedSince   atomic.Int64 // unix nanos of the latest successful dial
+	lastActivity     atomic.Int64 // unix nanos of the latest send or receive
 }
 
+// ConnectionStats is a snapshot of a connection's traffic. Byte counts cover
+// message payloads, not WebSocket framing.
+type ConnectionStats struct {
+	MessagesReceived int64
+	MessagesSent     int64
+	BytesReceived    int64
+	BytesSent        int64
+	ConnectedSince   time.Time // zero until the first dial succeeds
+	LastActivity     time.Time // zero until a message is sent or received
+}
+
+// MetricsRecorder receives connection lifecycle and event counts;
+// metrics.Collector satisfies it
+type MetricsRecorder interface {
+	RecordWebSocketConnection(status string)
+	RecordWebSocketEvent(eventType string)
+}
+
+// Connection statuses reported to the MetricsRecorder
+const (
+	MetricConnected     = "connected"
+	MetricReconnected   = "reconnected"
+	MetricFailedOver    = "failed_over"
+	MetricConnectFailed = "connect_failed"
+	MetricDisconnected  = "disconnected"
+	MetricClosed        = "closed"
+)
+
+// Send outcomes reported as events to the MetricsRecorder
+const (
+	MetricMessageSent        = "message_sent"
+	MetricMessageSendFailed  = "message_send_failed"
+	MetricMessageSendTimeout = "message_send_timeout"
+	MetricHandlerPanic       = "handler_panic"
+)
+
 // ConnectionOption configures connection behavior
 type ConnectionOption func(*Connection)
 
@@ -80,6 +163,58 @@ func WithReadTimeout(timeout time.Duration) ConnectionOption {
 	}
 }
 
+// WithReadLimit caps the size in bytes of a single incoming message. A larger
+// message closes the connection, which then reconnects if enabled.
+func WithReadLimit(bytes int64) ConnectionOption {
+	return func(c *Connection) {
+		c.readLimit = bytes
+	}
+}
+
+// WithHandshakeTimeout bounds the WebSocket handshake of each dial
+func WithHandshakeTimeout(timeout time.Duration) ConnectionOption {
+	return func(c *Connection) {
+		c.handshakeTimeout = timeout
+	}
+}
+
+// WithSubscribeRetry sets how many times a subscribe that fails transiently is
+// retried, and the delay before the first retry, which doubles on each
+// further attempt. Zero retries disables retrying.
+func WithSubscribeRetry(retries int, delay time.Duration) ConnectionOption {
+	return func(c *Connection) {
+		c.subscribeRetries = retries
+		c.subscribeRetryDelay = delay
+	}
+}
+
+// WithReadBufferSize sets the size in bytes of the connection's read buffer.
+// Zero keeps the library default. It does not limit message size; see
+// WithReadLimit.
+func WithReadBufferSize(bytes int) ConnectionOption {
+	return func(c *Connection) {
+		c.readBufferSize = bytes
+	}
+}
+
+// WithWriteBufferSize sets the size in bytes of the connection's write buffer.
+// Zero keeps the library default. Messages larger than the buffer are written
+// in several frames.
+func WithWriteBufferSize(bytes int) ConnectionOption {
+	return func(c *Connection) {
+		c.writeBufferSize = bytes
+	}
+}
+
+// WithCompression offers permessage-deflate when dialing. Binance may decline
+// it; either way handlers receive decompressed payloads. It trades CPU for
+// bandwidth, which pays off on busy combined streams.
+func WithCompression(enable bool) ConnectionOption {
+	return func(c *Connection) {
+		c.compression = enable
+	}
+}
+
 // WithAutoReconnect enables automatic reconnection
 func WithAutoReconnect(enable bool) ConnectionOption {
 	return func(c *Connection) {
@@ -101,6 +236,76 @@ func WithReconnectInterval(interval time.Duration) ConnectionOption {
 	}
 }
 
+// WithMaxReconnectInterval caps the exponential reconnection backoff
+func WithMaxReconnectInterval(interval time.Duration) ConnectionOption {
+	return func(c *Connection) {
+		c.maxReconnectInterval = interval
+	}
+}
+
+// WithMetrics reports connection events and message counts to recorder
+func WithMetrics(recorder MetricsRecorder) ConnectionOption {
+	return func(c *Connection) {
+		c.metrics = recorder
+	}
+}
+
+// WithLogger sets the logger used to report recovered handler panics and
+// unmatched subscription responses
+func WithLogger(logger zerolog.Logger) ConnectionOption {
+	return func(c *Connection) {
+		c.logger = logger
+	}
+}
+
+// WithHandlerErrorCallback sets a callback for errors returned by stream
+// handlers and for handler panics, which are recovered and wrap
+// ErrHandlerPanic. The callback runs on the message's goroutine.
+func WithHandlerErrorCallback(callback func(error)) ConnectionOption {
+	return func(c *Connection) {
+		c.handlerErrorFn = callback
+	}
+}
+
+// WithStandbyConnection keeps a second connection dialed alongside the
+// primary. When the primary fails the standby takes over without a dial, and
+// a replacement standby is dialed in the background.
+func WithStandbyConnection(enable bool) ConnectionOption {
+	return func(c *Connection) {
+		c.standbyEnabled = enable
+	}
+}
+
+// DefaultReadLimit bounds incoming messages well above the largest Binance
+// payloads, such as full-depth snapshots, so a faulty server cannot exhaust
+// memory with one frame
+const DefaultReadLimit = 10 << 20
+
+// DefaultHandshakeTimeout bounds a dial's handshake unless
+// WithHandshakeTimeout overrides it
+const DefaultHandshakeTimeout = 10 * time.Second
+
+// Subscribe retry defaults, unless WithSubscribeRetry overrides them
+const (
+	DefaultSubscribeRetries    = 2
+	DefaultSubscribeRetryDelay = 250 * time.Millisecond
+)
+
+// maxSubscribeRetryDelay caps the backoff between subscribe attempts
+const maxSubscribeRetryDelay = 5 * time.Second
+
+// DefaultMaxReconnectInterval caps the reconnection backoff unless
+// WithMaxReconnectInterval overrides it
+const DefaultMaxReconnectInterval = 30 * time.Second
+
+// ReconnectStatus is a snapshot of a connection's reconnection progress
+type ReconnectStatus struct {
+	Reconnecting bool
+	Attempt      int           // attempts made since the connection was last stable
+	MaxAttempts  int           // attempts allowed before giving up
+	NextDelay    time.Duration // wait before the latest attempt, after jitter
+}
+
 // NewConnection creates a new WebSocket connection
 func NewConnection(url string, opts ...ConnectionOption) *Connection {
 	conn := &Connection{
@@ -110,11 +315,17 @@ func NewConnection(url string, opts ...ConnectionOption) *Connection {
 		pongTimeout:          60 * time.Second,
 		writeTimeout:         10 * time.Second,
 		readTimeout:          60 * time.Second,
+		readLimit:            DefaultReadLimit,
+		handshakeTimeout:     DefaultHandshakeTimeout,
 		autoReconnect:        false,
 		maxReconnectAttempts: 5,
 		reconnectInterval:    5 * time.Second,
-		closeChan:            make(chan struct{}),
-		doneChan:             make(chan struct{}),
+		maxReconnectInterval: DefaultMaxReconnectInterval,
+		subscribeRetries:     DefaultSubscribeRetries,
+		subscribeRetryDelay:  DefaultSubscribeRetryDelay,
+		jitter:               fullJitter,
+		group:                lifecycle.NewGroup(),
+		logger:               zerolog.Nop(),
 	}
 
 	for _, opt := range opts {
@@ -136,6 +347,14 @@ func (c *Connection) State() ConnectionState {
 	return c.state
 }
 
+// Generation returns the number of successful dials, letting observers detect
+// reconnects that complete between state polls
+func (c *Connection) Generation() uint64 {
+	c.stateMu.RLock()
+	defer c.stateMu.RUnlock()
+	return c.generation
+}
+
 // setState sets the connection state
 func (c *Connection) setState(state ConnectionState) {
 	c.stateMu.Lock()
@@ -163,6 +382,32 @@ func (c *Connection) ReadTimeout() time.Duration {
 	return c.readTimeout
 }
 
+// ReadLimit returns the maximum incoming message size in bytes
+func (c *Connection) ReadLimit() int64 {
+	return c.readLimit
+}
+
+// HandshakeTimeout returns the handshake timeout used when dialing
+func (c *Connection) HandshakeTimeout() time.Duration {
+	return c.handshakeTimeout
+}
+
+// ReadBufferSize returns the configured read buffer size, zero for the default
+func (c *Connection) ReadBufferSize() int {
+	return c.readBufferSize
+}
+
+// WriteBufferSize returns the configured write buffer size, zero for the default
+func (c *Connection) WriteBufferSize() int {
+	return c.writeBufferSize
+}
+
+// SubscribeRetry returns how many times transient subscribe failures are
+// retried and the initial delay between attempts
+func (c *Connection) SubscribeRetry() (int, time.Duration) {
+	return c.subscribeRetries, c.subscribeRetryDelay
+}
+
 // Connect establishes the WebSocket connection
 func (c *Connection) Connect(ctx context.Context) error {
 	if c.State() == StateConnected {
@@ -171,39 +416,83 @@ func (c *Connection) Connect(ctx context.Context) error {
 
 	c.setState(StateConnecting)
 
-	// Reset channels for reconnection
-	select {
-	case <-c.closeChan:
-		c.closeChan = make(chan struct{})
-	default:
-	}
-
-	// Handle doneChan without resetting sync.Once
-	c.doneMutex.Lock()
-	select {
-	case <-c.doneChan:
-		// Create new done channel and reset once for next use
-		c.doneChan = make(chan struct{})
-		c.doneOnce = sync.Once{}
-	default:
+	// A closed connection being reused needs a fresh group; reconnects keep
+	// the running one
+	c.groupMu.Lock()
+	if c.group.Stopped() {
+		c.group = lifecycle.NewGroup()
 	}
-	c.doneMutex.Unlock()
+	g := c.group
+	c.groupMu.Unlock()
 
-	dialer := websocket.Dialer{
-		HandshakeTimeout: 10 * time.Second,
-	}
-
-	conn, _, err := dialer.DialContext(ctx, c.url, nil)
+	conn, _, err := c.dialer().DialContext(ctx, c.url, nil)
 	if err != nil {
 		c.setState(StateDisconnected)
+		c.recordConnection(MetricConnectFailed)
 		return fmt.Errorf("failed to connect to %s: %w", c.url, err)
 	}
 
+	// A reconnect racing Close must not leave a socket without its loops
+	if g.Stopped() {
+		conn.Close()
+		c.setState(StateClosed)
+		return fmt.Errorf("connection to %s closed while connecting", c.url)
+	}
+
 	c.connMu.Lock()
 	c.conn = conn
 	c.connMu.Unlock()
 
 	// Set up pong handler before starting loops
+	conn.SetReadLimit(c.readLimit)
+	c.setPongHandler(conn)
+	c.resetReadDeadline(conn)
+
+	c.connectedSince.Store(time.Now().UnixNano())
+
+	c.stateMu.Lock()
+	c.state = StateConnected
+	c.generation++
+	generation := c.generation
+	c.stateMu.Unlock()
+
+	if generation > 1 {
+		c.recordConnection(MetricReconnected)
+	} else {
+		c.recordConnection(MetricConnected)
+	}
+
+	// Start background goroutines
+	g.Go(func() { c.startPingLoop(g, conn) })
+	g.Go(func() { c.startReadLoop(g, conn) })
+
+	if c.standbyEnabled {
+		g.Go(func() { c.maintainStandby(g, 0) })
+	}
+
+	return nil
+}
+
+// dialer returns the dialer used for the primary and standby connections
+func (c *Connection) dialer() *websocket.Dialer {
+	return &websocket.Dialer{
+		HandshakeTimeout:  c.handshakeTimeout,
+		ReadBufferSize:    c.readBufferSize,
+		WriteBufferSize:   c.writeBufferSize,
+		EnableCompression: c.compression,
+	}
+}
+
+// goroutines returns the group the connection's loops currently run in
+func (c *Connection) goroutines() *lifecycle.Group {
+	c.groupMu.Lock()
+	defer c.groupMu.Unlock()
+	return c.group
+}
+
+// setPongHandler tracks pongs on conn. It must be installed before conn's
+// read loop starts.
+func (c *Connection) setPongHandler(conn *websocket.Conn) {
 	conn.SetPongHandler(func(string) error {
 		c.pongMu.Lock()
 		c.lastPongTime = time.Now()
@@ -211,22 +500,180 @@ func (c *Connection) Connect(ctx context.Context) error {
 		conn.SetReadDeadline(time.Now().Add(c.readTimeout))
 		return nil
 	})
+}
 
-	// Initialize pong time
+// resetReadDeadline restarts pong tracking and the read deadline for conn as
+// it becomes the primary
+func (c *Connection) resetReadDeadline(conn *websocket.Conn) {
 	c.pongMu.Lock()
 	c.lastPongTime = time.Now()
 	c.pongMu.Unlock()
 
-	// Set initial read deadline
 	conn.SetReadDeadline(time.Now().Add(c.readTimeout))
+}
 
-	c.setState(StateConnected)
+// isCurrent reports whether conn is the primary connection
+func (c *Connection) isCurrent(conn *websocket.Conn) bool {
+	c.connMu.Lock()
+	defer c.connMu.Unlock()
+	return c.conn == conn
+}
 
-	// Start background goroutines
-	go c.startPingLoop()
-	go c.startReadLoop()
+// HasStandby reports whether a warm standby is connected and ready to take
+// over
+func (c *Connection) HasStandby() bool {
+	c.connMu.Lock()
+	defer c.connMu.Unlock()
+	return c.standby != nil
+}
 
-	return nil
+// setFailoverHandler sets a callback run after a standby has been promoted
+func (c *Connection) setFailoverHandler(handler func()) {
+	c.handlerMu.Lock()
+	defer c.handlerMu.Unlock()
+	c.failoverHandler = handler
+}
+
+// maintainStandby dials a standby after delay unless one is already up or
+// being dialed, retrying every reconnect interval while the primary is
+// connected
+func (c *Connection) maintainStandby(g *lifecycle.Group, delay time.Duration) {
+	if !c.standbyDialing.CompareAndSwap(false, true) {
+		return
+	}
+	defer c.standbyDialing.Store(false)
+
+	for {
+		select {
+		case <-g.Done():
+			return
+		case <-time.After(delay):
+		}
+		delay = c.reconnectInterval
+
+		if c.State() != StateConnected || c.HasStandby() {
+			return
+		}
+
+		ctx, cancel := context.WithTimeout(g.Context(), 10*time.Second)
+		conn, _, err := c.dialer().DialContext(ctx, c.url, nil)
+		cancel()
+		if err != nil {
+			continue
+		}
+
+		// No read deadline until promotion: the standby is never pinged
+		conn.SetReadLimit(c.readLimit)
+		c.setPongHandler(conn)
+
+		c.connMu.Lock()
+		if c.State() != StateConnected || c.standby != nil {
+			c.connMu.Unlock()
+			conn.Close()
+			return
+		}
+		c.standby = conn
+		c.connMu.Unlock()
+
+		// The read loop answers the server's pings while idle and carries on
+		// as the primary reader after promotion
+		g.Go(func() { c.startReadLoop(g, conn) })
+		return
+	}
+}
+
+// promoteStandby swaps the standby in as the primary. It reports false if
+// there was no standby. Callers must hold reconnectMu.
+func (c *Connection) promoteStandby() bool {
+	c.connMu.Lock()
+	standby := c.standby
+	if standby == nil {
+		c.connMu.Unlock()
+		return false
+	}
+	failed := c.conn
+	c.conn = standby
+	c.standby = nil
+	c.connMu.Unlock()
+
+	if failed != nil {
+		failed.Close()
+	}
+
+	c.resetReadDeadline(standby)
+	c.connectedSince.Store(time.Now().UnixNano())
+
+	c.stateMu.Lock()
+	c.state = StateConnected
+	c.generation++
+	c.stateMu.Unlock()
+
+	c.recordConnection(MetricFailedOver)
+
+	g := c.goroutines()
+	g.Go(func() { c.startPingLoop(g, standby) })
+	g.Go(func() { c.maintainStandby(g, 0) })
+
+	c.handlerMu.RLock()
+	handler := c.failoverHandler
+	c.handlerMu.RUnlock()
+	if handler != nil {
+		go handler()
+	}
+
+	return true
+}
+
+// dropStandby discards conn if it is still the standby
+func (c *Connection) dropStandby(conn *websocket.Conn) {
+	c.connMu.Lock()
+	dropped := c.standby == conn
+	if dropped {
+		c.standby = nil
+	}
+	c.connMu.Unlock()
+
+	if dropped {
+		conn.Close()
+		g := c.goroutines()
+		g.Go(func() { c.maintainStandby(g, c.reconnectInterval) })
+	}
+}
+
+// ReconnectStatus reports the current reconnection attempt and the delay
+// chosen for it
+func (c *Connection) ReconnectStatus() ReconnectStatus {
+	c.reconnectMu.Lock()
+	defer c.reconnectMu.Unlock()
+
+	return ReconnectStatus{
+		Reconnecting: c.reconnecting,
+		Attempt:      c.reconnectAttempts,
+		MaxAttempts:  c.maxReconnectAttempts,
+		NextDelay:    c.nextReconnect,
+	}
+}
+
+// reconnectBackoff returns the exponential backoff for attempt, counting
+// from 1, capped at the max reconnect interval
+func (c *Connection) reconnectBackoff(attempt int) time.Duration {
+	backoff := c.reconnectInterval
+	for i := 1; i < attempt && backoff < c.maxReconnectInterval; i++ {
+		backoff *= 2
+	}
+	if c.maxReconnectInterval > 0 && backoff > c.maxReconnectInterval {
+		backoff = c.maxReconnectInterval
+	}
+	return backoff
+}
+
+// fullJitter picks a uniformly random wait in [0, backoff], so clients that
+// drop together do not reconnect together
+func fullJitter(backoff time.Duration) time.Duration {
+	if backoff <= 0 {
+		return 0
+	}
+	return time.Duration(rand.Int63n(int64(backoff) + 1))
 }
 
 // Send sends a message to the WebSocket
@@ -257,6 +704,11 @@ func (c *Connection) Send(ctx context.Context, data []byte) error {
 	// Write message - SetWriteDeadline will handle timeout
 	err := conn.WriteMessage(websocket.TextMessage, data)
 	if err != nil {
+		c.sendErrors.Add(1)
+		c.recordEvent(MetricMessageSendFailed)
+		if isTimeout(err) {
+			c.recordEvent(MetricMessageSendTimeout)
+		}
 		// Check if context was cancelled
 		select {
 		case <-ctx.Done():
@@ -265,9 +717,47 @@ func (c *Connection) Send(ctx context.Context, data []byte) error {
 			return err
 		}
 	}
+	c.recordEvent(MetricMessageSent)
+	c.messagesSent.Add(1)
+	c.bytesSent.Add(int64(len(data)))
+	c.lastActivity.Store(time.Now().UnixNano())
 	return nil
 }
 
+// isTimeout reports whether err is a network timeout, such as a write that
+// passed its deadline
+func isTimeout(err error) bool {
+	var netErr net.Error
+	return errors.As(err, &netErr) && netErr.Timeout()
+}
+
+// SendErrorCount returns how many writes have failed, including timeouts,
+// cumulative across reconnects. A steadily rising count points at a peer that
+// is not reading.
+func (c *Connection) SendErrorCount() int64 {
+	return c.sendErrors.Load()
+}
+
+// Stats returns the connection's traffic counters
+func (c *Connection) Stats() ConnectionStats {
+	return ConnectionStats{
+		MessagesReceived: c.messagesReceived.Load(),
+		MessagesSent:     c.messagesSent.Load(),
+		BytesReceived:    c.bytesReceived.Load(),
+		BytesSent:        c.bytesSent.Load(),
+		ConnectedSince:   unixNanoTime(c.connectedSince.Load()),
+		LastActivity:     unixNanoTime(c.lastActivity.Load()),
+	}
+}
+
+// unixNanoTime converts a stored timestamp, keeping zero as the zero time
+func unixNanoTime(nanos int64) time.Time {
+	if nanos == 0 {
+		return time.Time{}
+	}
+	return time.Unix(0, nanos)
+}
+
 // Close closes the WebSocket connection
 func (c *Connection) Close() error {
 	currentState := c.State()
@@ -276,23 +766,23 @@ func (c *Connection) Close() error {
 	}
 
 	c.setState(StateClosed)
+	c.recordConnection(MetricClosed)
 
 	// Signal shutdown
-	select {
-	case <-c.closeChan:
-		// Already closed
-	default:
-		close(c.closeChan)
-	}
-
-	// Wait a moment for ongoing operations to complete
-	time.Sleep(10 * time.Millisecond)
+	g := c.goroutines()
+	g.Stop()
 
 	c.connMu.Lock()
 	conn := c.conn
+	standby := c.standby
 	c.conn = nil
+	c.standby = nil
 	c.connMu.Unlock()
 
+	if standby != nil {
+		standby.Close()
+	}
+
 	if conn != nil {
 		// Send close frame (thread-safe) with timeout protection
 		closeCtx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
@@ -315,11 +805,10 @@ func (c *Connection) Close() error {
 		conn.Close()
 	}
 
-	// Wait for goroutines to finish
-	select {
-	case <-c.doneChan:
-	case <-time.After(1 * time.Second):
-		// Timeout waiting for graceful shutdown
+	// Wait for goroutines to finish; closing the sockets above unblocks
+	// their reads
+	if !g.Wait(1 * time.Second) {
+		c.logger.Warn().Str("url", c.url).Msg("Timed out waiting for connection goroutines to exit")
 	}
 
 	return nil
@@ -332,38 +821,17 @@ func (c *Connection) SetMessageHandler(handler func([]byte)) {
 	c.messageHandler = handler
 }
 
-// startPingLoop sends periodic ping frames
-func (c *Connection) startPingLoop() {
-	defer func() {
-		c.doneMutex.Lock()
-		defer c.doneMutex.Unlock()
-		c.doneOnce.Do(func() {
-			select {
-			case <-c.doneChan:
-				// Already closed
-			default:
-				close(c.doneChan)
-			}
-		})
-	}()
-
+// startPingLoop sends periodic ping frames on conn while it is the primary
+func (c *Connection) startPingLoop(g *lifecycle.Group, conn *websocket.Conn) {
 	ticker := time.NewTicker(c.pingInterval)
 	defer ticker.Stop()
 
 	for {
 		select {
-		case <-c.closeChan:
+		case <-g.Done():
 			return
 		case <-ticker.C:
-			if c.State() != StateConnected {
-				return
-			}
-
-			c.connMu.Lock()
-			conn := c.conn
-			c.connMu.Unlock()
-
-			if conn == nil {
+			if c.State() != StateConnected || !c.isCurrent(conn) {
 				return
 			}
 
@@ -373,7 +841,7 @@ func (c *Connection) startPingLoop() {
 			c.pongMu.Unlock()
 
 			if timeSinceLastPong > c.pongTimeout {
-				c.handleConnectionError(fmt.Errorf("pong timeout: no pong received for %v", timeSinceLastPong))
+				c.handleConnectionError(conn, fmt.Errorf("pong timeout: no pong received for %v", timeSinceLastPong))
 				return
 			}
 
@@ -384,7 +852,7 @@ func (c *Connection) startPingLoop() {
 			c.writeMu.Unlock()
 
 			if err != nil {
-				c.handleConnectionError(err)
+				c.handleConnectionError(conn, err)
 				return
 			}
 
@@ -394,30 +862,21 @@ func (c *Connection) startPingLoop() {
 			if c.reconnectAttempts > 0 {
 				c.reconnectAttempts = 0
 				c.reconnecting = false
+				c.nextReconnect = 0
 			}
 			c.reconnectMu.Unlock()
 		}
 	}
 }
 
-// startReadLoop reads messages from WebSocket
-func (c *Connection) startReadLoop() {
-	defer func() {
-		c.doneMutex.Lock()
-		defer c.doneMutex.Unlock()
-		c.doneOnce.Do(func() {
-			select {
-			case <-c.doneChan:
-				// Already closed
-			default:
-				close(c.doneChan)
-			}
-		})
-	}()
+// startReadLoop reads messages from conn. A standby's loop only drains
+// control frames until the standby is promoted.
+func (c *Connection) startReadLoop(g *lifecycle.Group, conn *websocket.Conn) {
+	defer c.dropStandby(conn)
 
 	for {
 		select {
-		case <-c.closeChan:
+		case <-g.Done():
 			return
 		default:
 		}
@@ -426,20 +885,24 @@ func (c *Connection) startReadLoop() {
 			return
 		}
 
-		c.connMu.Lock()
-		conn := c.conn
-		c.connMu.Unlock()
-
-		if conn == nil {
-			return
-		}
-
 		// Read message
 		_, message, err := conn.ReadMessage()
 		if err != nil {
-			c.handleConnectionError(err)
+			if errors.Is(err, websocket.ErrReadLimit) {
+				c.logger.Warn().
+					Int64("read_limit", c.readLimit).
+					Str("url", c.url).
+					Msg("Message exceeded read limit, dropping connection")
+			}
+			c.handleConnectionError(conn, err)
 			return
 		}
+		if !c.isCurrent(conn) {
+			continue
+		}
+		c.messagesReceived.Add(1)
+		c.bytesReceived.Add(int64(len(message)))
+		c.lastActivity.Store(time.Now().UnixNano())
 
 		// Handle message
 		c.handlerMu.RLock()
@@ -447,17 +910,62 @@ func (c *Connection) startReadLoop() {
 		c.handlerMu.RUnlock()
 
 		if handler != nil {
-			go handler(message)
+			go c.dispatch(handler, message)
 		}
 	}
 }
 
-// handleConnectionError handles connection errors and triggers reconnection
-func (c *Connection) handleConnectionError(err error) {
+// dispatch runs handler on message, recovering a panic so a faulty handler
+// cannot take down the process or the connection
+func (c *Connection) dispatch(handler func([]byte), message []byte) {
+	defer func() {
+		if r := recover(); r != nil {
+			c.logger.Error().
+				Interface("panic", r).
+				Bytes("stack", debug.Stack()).
+				Str("url", c.url).
+				Msg("Recovered panic in message handler")
+			c.recordEvent(MetricHandlerPanic)
+			c.reportHandlerError(fmt.Errorf("%w: %v", ErrHandlerPanic, r))
+		}
+	}()
+
+	handler(message)
+}
+
+// reportHandlerError passes err to the handler error callback, if any
+func (c *Connection) reportHandlerError(err error) {
+	c.handlerMu.RLock()
+	callback := c.handlerErrorFn
+	c.handlerMu.RUnlock()
+
+	if callback != nil {
+		callback(err)
+	}
+}
+
+// recordConnection reports a lifecycle status if metrics are enabled
+func (c *Connection) recordConnection(status string) {
+	if c.metrics != nil {
+		c.metrics.RecordWebSocketConnection(status)
+	}
+}
+
+// recordEvent reports an event count if metrics are enabled
+func (c *Connection) recordEvent(eventType string) {
+	if c.metrics != nil {
+		c.metrics.RecordWebSocketEvent(eventType)
+	}
+}
+
+// handleConnectionError handles an error on conn, failing over to the
+// standby if one is up and otherwise triggering reconnection. Errors from a
+// connection that is no longer the primary are ignored.
+func (c *Connection) handleConnectionError(conn *websocket.Conn, err error) {
 	c.reconnectMu.Lock()
 	defer c.reconnectMu.Unlock()
 
-	if c.State() == StateClosed {
+	if c.State() == StateClosed || !c.isCurrent(conn) {
 		return
 	}
 
@@ -466,17 +974,26 @@ func (c *Connection) handleConnectionError(err error) {
 		return
 	}
 
+	c.recordConnection(MetricDisconnected)
+
+	if c.promoteStandby() {
+		return
+	}
+
 	if c.autoReconnect && c.reconnectAttempts < c.maxReconnectAttempts {
 		c.reconnecting = true
 		c.setState(StateReconnecting)
-		go c.attemptReconnection()
+		g := c.goroutines()
+		if !g.Go(func() { c.attemptReconnection(g) }) {
+			c.reconnecting = false
+		}
 	} else {
 		c.setState(StateDisconnected)
 	}
 }
 
-// attemptReconnection attempts to reconnect with exponential backoff
-func (c *Connection) attemptReconnection() {
+// attemptReconnection attempts to reconnect with jittered exponential backoff
+func (c *Connection) attemptReconnection(g *lifecycle.Group) {
 	defer func() {
 		c.reconnectMu.Lock()
 		c.reconnecting = false
@@ -490,18 +1007,12 @@ func (c *Connection) attemptReconnection() {
 			break
 		}
 		c.reconnectAttempts++
-		attempts := c.reconnectAttempts
+		backoffDelay := c.jitter(c.reconnectBackoff(c.reconnectAttempts))
+		c.nextReconnect = backoffDelay
 		c.reconnectMu.Unlock()
 
-		// Calculate backoff delay
-		backoffDelay := c.reconnectInterval * time.Duration(1<<uint(attempts-1))
-		maxDelay := 30 * time.Second
-		if backoffDelay > maxDelay {
-			backoffDelay = maxDelay
-		}
-
 		select {
-		case <-c.closeChan:
+		case <-g.Done():
 			return
 		case <-time.After(backoffDelay):
 		}
@@ -511,7 +1022,7 @@ func (c *Connection) attemptReconnection() {
 			return
 		}
 
-		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		ctx, cancel := context.WithTimeout(g.Context(), 10*time.Second)
 		err := c.Connect(ctx)
 		cancel()
 
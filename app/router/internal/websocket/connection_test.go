@@ -24,6 +24,13 @@ func TestNewConnection(t *testing.T) {
 		assert.Equal(t, StateDisconnected, conn.State())
 		assert.Equal(t, 30*time.Second, conn.PingInterval())
 		assert.Equal(t, 60*time.Second, conn.PongTimeout())
+		assert.Equal(t, int64(DefaultReadLimit), conn.ReadLimit())
+		assert.Equal(t, DefaultHandshakeTimeout, conn.HandshakeTimeout())
+		assert.Zero(t, conn.ReadBufferSize())
+		assert.Zero(t, conn.WriteBufferSize())
+		retries, delay := conn.SubscribeRetry()
+		assert.Equal(t, DefaultSubscribeRetries, retries)
+		assert.Equal(t, DefaultSubscribeRetryDelay, delay)
 	})
 
 	t.Run("applies custom options", func(t *testing.T) {
@@ -32,12 +39,24 @@ func TestNewConnection(t *testing.T) {
 			WithPongTimeout(30*time.Second),
 			WithWriteTimeout(5*time.Second),
 			WithReadTimeout(10*time.Second),
+			WithReadLimit(1024),
+			WithHandshakeTimeout(3*time.Second),
+			WithReadBufferSize(2048),
+			WithWriteBufferSize(8192),
+			WithSubscribeRetry(4, time.Second),
 		)
 
 		assert.Equal(t, 15*time.Second, conn.PingInterval())
 		assert.Equal(t, 30*time.Second, conn.PongTimeout())
 		assert.Equal(t, 5*time.Second, conn.WriteTimeout())
 		assert.Equal(t, 10*time.Second, conn.ReadTimeout())
+		assert.Equal(t, int64(1024), conn.ReadLimit())
+		assert.Equal(t, 3*time.Second, conn.HandshakeTimeout())
+		assert.Equal(t, 2048, conn.ReadBufferSize())
+		assert.Equal(t, 8192, conn.WriteBufferSize())
+		retries, delay := conn.SubscribeRetry()
+		assert.Equal(t, 4, retries)
+		assert.Equal(t, time.Second, delay)
 	})
 
 	t.Run("validates URL format", func(t *testing.T) {
@@ -200,6 +219,41 @@ func TestConnection_Send(t *testing.T) {
 		assert.Error(t, lastErr, "Expected timeout error after sending large messages")
 	})
 
+	t.Run("counts write timeouts", func(t *testing.T) {
+		release := make(chan struct{})
+		server := newMockWebSocketServer(t, func(conn *websocket.Conn) {
+			defer conn.Close()
+			// Never read, so the socket buffers fill and writes stall
+			<-release
+		})
+		defer server.Close()
+		defer close(release)
+
+		recorder := newFakeMetricsRecorder()
+		wsConn := NewConnection(getWebSocketURL(server.URL),
+			WithWriteTimeout(20*time.Millisecond),
+			WithWriteBufferSize(1024),
+			WithMetrics(recorder))
+		require.NoError(t, wsConn.Connect(context.Background()))
+		defer wsConn.Close()
+
+		largeMessage := make([]byte, 1024*1024)
+		var err error
+		for i := 0; i < 50 && err == nil; i++ {
+			err = wsConn.Send(context.Background(), largeMessage)
+		}
+		require.Error(t, err)
+		assert.True(t, isTimeout(err), "expected a write timeout, got %v", err)
+
+		assert.Equal(t, int64(1), wsConn.SendErrorCount())
+		assert.Equal(t, 1, recorder.event(MetricMessageSendFailed))
+		assert.Equal(t, 1, recorder.event(MetricMessageSendTimeout))
+
+		// The stalled connection keeps failing and every failure is counted
+		assert.Error(t, wsConn.Send(context.Background(), []byte("ping")))
+		assert.Equal(t, int64(2), wsConn.SendErrorCount())
+	})
+
 	t.Run("fails when not connected", func(t *testing.T) {
 		wsConn := NewConnection("ws://example.com")
 		ctx := context.Background()
@@ -448,6 +502,190 @@ func TestConnection_Reconnection(t *testing.T) {
 	})
 }
 
+func TestConnection_ReconnectBackoff(t *testing.T) {
+	t.Run("doubles from the base interval up to the cap", func(t *testing.T) {
+		wsConn := NewConnection("ws://example.invalid",
+			WithReconnectInterval(100*time.Millisecond),
+			WithMaxReconnectInterval(time.Second))
+
+		tests := []struct {
+			attempt int
+			want    time.Duration
+		}{
+			{1, 100 * time.Millisecond},
+			{2, 200 * time.Millisecond},
+			{3, 400 * time.Millisecond},
+			{4, 800 * time.Millisecond},
+			{5, time.Second},
+			{100, time.Second},
+		}
+		for _, tt := range tests {
+			assert.Equal(t, tt.want, wsConn.reconnectBackoff(tt.attempt), "attempt %d", tt.attempt)
+		}
+	})
+
+	t.Run("defaults the cap", func(t *testing.T) {
+		wsConn := NewConnection("ws://example.invalid")
+		assert.Equal(t, DefaultMaxReconnectInterval, wsConn.reconnectBackoff(64))
+	})
+
+	t.Run("full jitter stays within the backoff", func(t *testing.T) {
+		backoff := 50 * time.Millisecond
+		for i := 0; i < 1000; i++ {
+			delay := fullJitter(backoff)
+			assert.GreaterOrEqual(t, delay, time.Duration(0))
+			assert.LessOrEqual(t, delay, backoff)
+		}
+		assert.Equal(t, time.Duration(0), fullJitter(0))
+	})
+
+	t.Run("status reflects attempts", func(t *testing.T) {
+		drop := make(chan struct{})
+		server := newMockWebSocketServer(t, func(conn *websocket.Conn) {
+			defer conn.Close()
+			<-drop
+		})
+		defer server.Close()
+
+		wsConn := NewConnection(getWebSocketURL(server.URL),
+			WithAutoReconnect(true),
+			WithMaxReconnectAttempts(3),
+			WithReconnectInterval(10*time.Millisecond))
+		var jittered []time.Duration
+		wsConn.jitter = func(backoff time.Duration) time.Duration {
+			jittered = append(jittered, backoff)
+			return backoff
+		}
+
+		require.NoError(t, wsConn.Connect(context.Background()))
+		defer wsConn.Close()
+		assert.Equal(t, ReconnectStatus{MaxAttempts: 3}, wsConn.ReconnectStatus())
+
+		// Refuse redials, then drop the live connection
+		server.Listener.Close()
+		close(drop)
+
+		// A failed dial leaves the state Disconnected between attempts, so
+		// wait for the attempts to run out instead
+		require.Eventually(t, func() bool {
+			status := wsConn.ReconnectStatus()
+			return status.Attempt == 3 && !status.Reconnecting
+		}, 2*time.Second, 10*time.Millisecond)
+		assert.Equal(t, StateDisconnected, wsConn.State())
+
+		status := wsConn.ReconnectStatus()
+		assert.False(t, status.Reconnecting)
+		assert.Equal(t, 3, status.Attempt)
+		assert.Equal(t, 3, status.MaxAttempts)
+		assert.Equal(t, 40*time.Millisecond, status.NextDelay)
+		assert.Equal(t, []time.Duration{10 * time.Millisecond, 20 * time.Millisecond, 40 * time.Millisecond}, jittered)
+	})
+}
+
+func TestConnection_ReadLimit(t *testing.T) {
+	var connections atomic.Int32
+	server := newMockWebSocketServer(t, func(conn *websocket.Conn) {
+		defer conn.Close()
+		// The first connection sends a message over the limit; the
+		// reconnected one a normal one
+		if connections.Add(1) == 1 {
+			conn.WriteMessage(websocket.TextMessage, []byte(strings.Repeat("x", 4096)))
+		} else {
+			conn.WriteMessage(websocket.TextMessage, []byte("ok"))
+		}
+		for {
+			if _, _, err := conn.ReadMessage(); err != nil {
+				return
+			}
+		}
+	})
+	defer server.Close()
+
+	wsConn := NewConnection(getWebSocketURL(server.URL),
+		WithReadLimit(1024),
+		WithAutoReconnect(true),
+		WithReconnectInterval(10*time.Millisecond))
+
+	received := make(chan []byte, 2)
+	wsConn.SetMessageHandler(func(data []byte) {
+		received <- data
+	})
+
+	require.NoError(t, wsConn.Connect(context.Background()))
+	defer wsConn.Close()
+
+	select {
+	case msg := <-received:
+		assert.Equal(t, "ok", string(msg), "oversized message must not reach the handler")
+	case <-time.After(2 * time.Second):
+		t.Fatal("connection did not recover after an oversized message")
+	}
+	assert.Equal(t, uint64(2), wsConn.Generation())
+	assert.Equal(t, StateConnected, wsConn.State())
+}
+
+func TestConnection_Compression(t *testing.T) {
+	tests := []struct {
+		name        string
+		compression bool
+	}{
+		{"negotiates permessage-deflate", true},
+		{"does not offer it when disabled", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			offered := make(chan string, 1)
+			upgrader := websocket.Upgrader{EnableCompression: true}
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				offered <- r.Header.Get("Sec-WebSocket-Extensions")
+				conn, err := upgrader.Upgrade(w, r, nil)
+				if err != nil {
+					return
+				}
+				defer conn.Close()
+				conn.EnableWriteCompression(true)
+				for {
+					messageType, p, err := conn.ReadMessage()
+					if err != nil {
+						return
+					}
+					if err := conn.WriteMessage(messageType, p); err != nil {
+						return
+					}
+				}
+			}))
+			defer server.Close()
+
+			wsConn := NewConnection(getWebSocketURL(server.URL), WithCompression(tt.compression))
+			received := make(chan []byte, 1)
+			wsConn.SetMessageHandler(func(data []byte) {
+				received <- data
+			})
+
+			require.NoError(t, wsConn.Connect(context.Background()))
+			defer wsConn.Close()
+
+			if tt.compression {
+				assert.Contains(t, <-offered, "permessage-deflate")
+			} else {
+				assert.Empty(t, <-offered)
+			}
+
+			// Repetitive payloads are what compression is for
+			payload := `{"stream":"btcusdt@depth","data":` + strings.Repeat(`["50000.00","1.000"],`, 200) + `null}`
+			require.NoError(t, wsConn.Send(context.Background(), []byte(payload)))
+
+			select {
+			case msg := <-received:
+				assert.Equal(t, payload, string(msg))
+			case <-time.After(2 * time.Second):
+				t.Fatal("echoed message not received")
+			}
+		})
+	}
+}
+
 func TestConnection_Close(t *testing.T) {
 	t.Run("closes connection gracefully", func(t *testing.T) {
 		closed := make(chan bool, 1)
@@ -578,8 +816,210 @@ func TestConnection_MessageHandling(t *testing.T) {
 	})
 }
 
+func TestConnection_Metrics(t *testing.T) {
+	t.Run("records lifecycle and sends", func(t *testing.T) {
+		var connectionCount int32
+		server := newMockWebSocketServer(t, func(conn *websocket.Conn) {
+			defer conn.Close()
+			if atomic.AddInt32(&connectionCount, 1) == 1 {
+				// Drop the first connection to force a reconnect
+				conn.ReadMessage()
+				return
+			}
+			for {
+				if _, _, err := conn.ReadMessage(); err != nil {
+					return
+				}
+			}
+		})
+		defer server.Close()
+
+		recorder := newFakeMetricsRecorder()
+		wsConn := NewConnection(getWebSocketURL(server.URL),
+			WithMetrics(recorder),
+			WithAutoReconnect(true),
+			WithReconnectInterval(50*time.Millisecond))
+		ctx := context.Background()
+
+		require.NoError(t, wsConn.Connect(ctx))
+		require.NoError(t, wsConn.Send(ctx, []byte(`{"method":"PING"}`)))
+
+		require.Eventually(t, func() bool {
+			return recorder.connection(MetricReconnected) == 1
+		}, 2*time.Second, 10*time.Millisecond)
+
+		require.NoError(t, wsConn.Close())
+
+		assert.Equal(t, 1, recorder.connection(MetricConnected))
+		assert.Equal(t, 1, recorder.connection(MetricDisconnected))
+		assert.Equal(t, 1, recorder.connection(MetricClosed))
+		assert.Equal(t, 1, recorder.event(MetricMessageSent))
+	})
+
+	t.Run("records failed dials", func(t *testing.T) {
+		recorder := newFakeMetricsRecorder()
+		wsConn := NewConnection("ws://127.0.0.1:1", WithMetrics(recorder))
+
+		require.Error(t, wsConn.Connect(context.Background()))
+		assert.Equal(t, 1, recorder.connection(MetricConnectFailed))
+		assert.Equal(t, 0, recorder.connection(MetricConnected))
+	})
+}
+
+func TestConnection_Stats(t *testing.T) {
+	server := newMockWebSocketServer(t, func(conn *websocket.Conn) {
+		defer conn.Close()
+		// Echo every message back with a fixed 10-byte suffix
+		for {
+			_, msg, err := conn.ReadMessage()
+			if err != nil {
+				return
+			}
+			conn.WriteMessage(websocket.TextMessage, append(msg, []byte("-echo-1234")...))
+		}
+	})
+	defer server.Close()
+
+	wsConn := NewConnection(getWebSocketURL(server.URL))
+	received := make(chan []byte, 4)
+	wsConn.SetMessageHandler(func(msg []byte) { received <- msg })
+
+	assert.Equal(t, ConnectionStats{}, wsConn.Stats())
+
+	before := time.Now()
+	ctx := context.Background()
+	require.NoError(t, wsConn.Connect(ctx))
+	defer wsConn.Close()
+
+	payloads := [][]byte{[]byte("12345"), []byte(`{"method":"LIST_SUBSCRIPTIONS"}`)}
+	for _, payload := range payloads {
+		require.NoError(t, wsConn.Send(ctx, payload))
+		select {
+		case <-received:
+		case <-time.After(time.Second):
+			t.Fatal("echo not received")
+		}
+	}
+
+	stats := wsConn.Stats()
+	assert.Equal(t, int64(2), stats.MessagesSent)
+	assert.Equal(t, int64(2), stats.MessagesReceived)
+	assert.Equal(t, int64(5+31), stats.BytesSent)
+	assert.Equal(t, int64(5+31+2*10), stats.BytesReceived)
+	assert.False(t, stats.ConnectedSince.Before(before))
+	assert.False(t, stats.LastActivity.Before(stats.ConnectedSince))
+}
+
+func TestConnection_StandbyFailover(t *testing.T) {
+	var mu sync.Mutex
+	var conns []*websocket.Conn
+	server := newMockWebSocketServer(t, func(conn *websocket.Conn) {
+		defer conn.Close()
+		mu.Lock()
+		conns = append(conns, conn)
+		mu.Unlock()
+		// Echo so the test can tell which connection is serving
+		for {
+			_, msg, err := conn.ReadMessage()
+			if err != nil {
+				return
+			}
+			conn.WriteMessage(websocket.TextMessage, msg)
+		}
+	})
+	defer server.Close()
+	connCount := func() int {
+		mu.Lock()
+		defer mu.Unlock()
+		return len(conns)
+	}
+
+	recorder := newFakeMetricsRecorder()
+	wsConn := NewConnection(getWebSocketURL(server.URL),
+		WithStandbyConnection(true),
+		WithMetrics(recorder),
+		WithReconnectInterval(50*time.Millisecond))
+	received := make(chan []byte, 4)
+	wsConn.SetMessageHandler(func(msg []byte) { received <- msg })
+
+	ctx := context.Background()
+	require.NoError(t, wsConn.Connect(ctx))
+	defer wsConn.Close()
+
+	require.Eventually(t, wsConn.HasStandby, time.Second, 5*time.Millisecond)
+	assert.Equal(t, 2, connCount())
+
+	// Kill the primary from the server side
+	mu.Lock()
+	primary := conns[0]
+	mu.Unlock()
+	killed := time.Now()
+	primary.Close()
+
+	require.Eventually(t, func() bool {
+		return wsConn.Generation() == 2
+	}, time.Second, time.Millisecond)
+	assert.Less(t, time.Since(killed), 100*time.Millisecond, "standby should take over without a reconnect backoff")
+	assert.Equal(t, StateConnected, wsConn.State())
+	assert.Equal(t, 1, recorder.connection(MetricFailedOver))
+	assert.Equal(t, 0, recorder.connection(MetricReconnected), "failover must not redial the primary")
+
+	// The promoted standby carries traffic both ways
+	require.NoError(t, wsConn.Send(ctx, []byte("after failover")))
+	select {
+	case msg := <-received:
+		assert.Equal(t, "after failover", string(msg))
+	case <-time.After(time.Second):
+		t.Fatal("promoted standby did not deliver messages")
+	}
+
+	// A replacement standby is dialed in the background
+	require.Eventually(t, wsConn.HasStandby, time.Second, 5*time.Millisecond)
+	assert.Equal(t, 3, connCount())
+
+	require.NoError(t, wsConn.Close())
+	assert.False(t, wsConn.HasStandby())
+}
+
 // Helper functions for testing
 
+type fakeMetricsRecorder struct {
+	mu          sync.Mutex
+	connections map[string]int
+	events      map[string]int
+}
+
+func newFakeMetricsRecorder() *fakeMetricsRecorder {
+	return &fakeMetricsRecorder{
+		connections: make(map[string]int),
+		events:      make(map[string]int),
+	}
+}
+
+func (r *fakeMetricsRecorder) RecordWebSocketConnection(status string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.connections[status]++
+}
+
+func (r *fakeMetricsRecorder) RecordWebSocketEvent(eventType string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.events[eventType]++
+}
+
+func (r *fakeMetricsRecorder) connection(status string) int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.connections[status]
+}
+
+func (r *fakeMetricsRecorder) event(eventType string) int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.events[eventType]
+}
+
 func newMockWebSocketServer(t *testing.T, handler func(*websocket.Conn)) *httptest.Server {
 	upgrader := websocket.Upgrader{
 		CheckOrigin: func(r *http.Request) bool { return true },
@@ -0,0 +1,140 @@
+package websocket
+
+import (
+	"strings"
+	"sync"
+)
+
+// DepthGap describes a break in a symbol's depth update sequence
+type DepthGap struct {
+	Symbol           string
+	ExpectedUpdateID int64 // previous FinalUpdateID + 1
+	FirstUpdateID    int64 // FirstUpdateID of the event that broke the sequence
+}
+
+// DepthGapDetector tracks the last FinalUpdateID per symbol and reports
+// events whose FirstUpdateID does not follow on from it
+type DepthGapDetector struct {
+	mu        sync.Mutex
+	sequences map[string]*depthSequence
+	onGap     func(DepthGap)
+}
+
+// depthSequence is one symbol's position in its depth stream
+type depthSequence struct {
+	last    int64
+	pending []*DepthUpdateEvent // events ahead of last, waiting for the ones before them
+}
+
+// NewDepthGapDetector creates a detector that calls onGap for every gap seen
+func NewDepthGapDetector(onGap func(DepthGap)) *DepthGapDetector {
+	return &DepthGapDetector{
+		sequences: make(map[string]*depthSequence),
+		onGap:     onGap,
+	}
+}
+
+// Check records the event and reports false when it confirms a gap in its
+// symbol's sequence. The first event per symbol is always accepted. Stream
+// messages are dispatched concurrently, so an event ahead of the sequence is
+// held until the ones before it arrive and an event already covered is
+// ignored. Only once more than maxDepthReorder events are held are the
+// missing ones treated as lost; tracking then continues from the earliest
+// held event.
+func (d *DepthGapDetector) Check(event *DepthUpdateEvent) bool {
+	symbol := strings.ToUpper(event.Symbol)
+
+	d.mu.Lock()
+	seq, seen := d.sequences[symbol]
+	if !seen {
+		d.sequences[symbol] = &depthSequence{last: event.FinalUpdateID}
+		d.mu.Unlock()
+		return true
+	}
+	gap, ok := seq.add(event)
+	d.mu.Unlock()
+
+	if ok {
+		return true
+	}
+
+	gap.Symbol = symbol
+	if d.onGap != nil {
+		d.onGap(gap)
+	}
+	return false
+}
+
+// Reset forgets the tracked sequence for a symbol, e.g. after a resync
+func (d *DepthGapDetector) Reset(symbol string) {
+	d.mu.Lock()
+	delete(d.sequences, strings.ToUpper(symbol))
+	d.mu.Unlock()
+}
+
+// add advances the sequence with event. It returns false with the missing
+// range once too many events are held waiting for it.
+func (s *depthSequence) add(event *DepthUpdateEvent) (DepthGap, bool) {
+	switch {
+	case event.FinalUpdateID <= s.last:
+		return DepthGap{}, true
+	case event.FirstUpdateID <= s.last+1:
+		s.last = event.FinalUpdateID
+		s.drain()
+		return DepthGap{}, true
+	}
+
+	s.pending = append(s.pending, event)
+	if len(s.pending) <= maxDepthReorder {
+		return DepthGap{}, true
+	}
+
+	sortEvents(s.pending)
+	gap := DepthGap{
+		ExpectedUpdateID: s.last + 1,
+		FirstUpdateID:    s.pending[0].FirstUpdateID,
+	}
+	s.last = s.pending[0].FinalUpdateID
+	s.pending = s.pending[1:]
+	s.drain()
+	return gap, false
+}
+
+// drain moves past held events that now follow on from the sequence
+func (s *depthSequence) drain() {
+	sortEvents(s.pending)
+	for len(s.pending) > 0 && s.pending[0].FirstUpdateID <= s.last+1 {
+		if final := s.pending[0].FinalUpdateID; final > s.last {
+			s.last = final
+		}
+		s.pending = s.pending[1:]
+	}
+}
+
+// DepthOption configures a depth subscription
+type DepthOption func(*depthSubscription)
+
+type depthSubscription struct {
+	gapDetector *DepthGapDetector
+}
+
+// WithGapDetection reports missed depth events for the subscription to onGap.
+// Events are still delivered to the handler, in the order they arrive.
+func WithGapDetection(onGap func(DepthGap)) DepthOption {
+	return func(s *depthSubscription) {
+		s.gapDetector = NewDepthGapDetector(onGap)
+	}
+}
+
+// wrap applies the subscription's options around a depth handler
+func (s *depthSubscription) wrap(handler func(*DepthUpdateEvent) error) func(*DepthUpdateEvent) error {
+	if s.gapDetector == nil || handler == nil {
+		return handler
+	}
+
+	detector := s.gapDetector
+	return func(event *DepthUpdateEvent) error {
+		detector.Check(event)
+		return handler(event)
+	}
+}
@@ -0,0 +1,450 @@
+package websocket
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/shopspring/decimal"
+	"router/internal/rest"
+)
+
+// DefaultDepthSnapshotLimit is how many levels per side the REST snapshot
+// fetches
+const DefaultDepthSnapshotLimit = 1000
+
+// DefaultDepthResyncDelay is the first delay before fetching another snapshot
+// when one could not be lined up with the stream; each retry doubles it
+const DefaultDepthResyncDelay = 250 * time.Millisecond
+
+const (
+	// maxDepthResyncDelay caps the delay between snapshot attempts
+	maxDepthResyncDelay = 10 * time.Second
+	// maxInitialSyncAttempts bounds the snapshot attempts MaintainDepthBook
+	// makes before giving up; later resyncs retry until the book is closed
+	maxInitialSyncAttempts = 5
+	// maxDepthBuffer bounds the events held while waiting for a snapshot.
+	// On overflow the buffer is discarded and a later snapshot catches up.
+	maxDepthBuffer = 10000
+	// maxDepthReorder is how many events may arrive ahead of the book before
+	// the missing ones are treated as lost. Stream messages are dispatched
+	// concurrently, so neighbouring events can reach the handler swapped.
+	maxDepthReorder = 8
+)
+
+// errStaleSnapshot is returned when a snapshot could not be lined up with the
+// buffered stream events
+var errStaleSnapshot = errors.New("snapshot does not line up with the depth stream")
+
+// DepthBookOption configures a book maintained by MaintainDepthBook
+type DepthBookOption func(*DepthBook)
+
+// WithFuturesDepth syncs against the futures depth snapshot and checks stream
+// continuity with each event's PrevFinalUpdateID, as futures streams require
+func WithFuturesDepth() DepthBookOption {
+	return func(b *DepthBook) {
+		b.futures = true
+	}
+}
+
+// WithDepthSnapshotLimit sets how many levels per side the snapshot fetches
+func WithDepthSnapshotLimit(limit int) DepthBookOption {
+	return func(b *DepthBook) {
+		b.snapshotLimit = limit
+	}
+}
+
+// WithDepthResyncDelay sets the first delay between snapshot attempts
+func WithDepthResyncDelay(delay time.Duration) DepthBookOption {
+	return func(b *DepthBook) {
+		b.resyncDelay = delay
+	}
+}
+
+// DepthBook is a local order book kept in step with a symbol's depth stream.
+// It is built from a REST snapshot plus the stream events that follow it and
+// resyncs from a fresh snapshot whenever the stream skips an update. Check
+// Synced before trusting the levels: while a resync is in progress they are
+// left as they were when the gap was found.
+type DepthBook struct {
+	symbol        string
+	futures       bool
+	snapshotLimit int
+	resyncDelay   time.Duration
+	snapshot      func(ctx context.Context) (*rest.OrderBook, error)
+	unsubscribe   func(ctx context.Context) error
+
+	mu            sync.RWMutex
+	bids          map[string]PriceLevel
+	asks          map[string]PriceLevel
+	lastUpdateID  int64
+	synced        bool
+	awaitingFirst bool // the next applied event must straddle the snapshot
+	buffer        []*DepthUpdateEvent
+	pending       []*DepthUpdateEvent // events ahead of the book while synced
+	resyncs       int
+	closed        bool
+
+	buffered chan struct{} // signalled when an event is buffered
+	ctx      context.Context
+	cancel   context.CancelFunc
+	wg       sync.WaitGroup
+}
+
+// MaintainDepthBook subscribes to symbol's depth stream and returns a book
+// synced from a restClient snapshot using Binance's sequence: events are
+// buffered from the stream, the snapshot is fetched once the first arrives,
+// buffered events it already covers are dropped, and the rest are applied on
+// top of it. The book replaces any other depth handler for the symbol on this
+// client. Close it to unsubscribe.
+func (c *Client) MaintainDepthBook(ctx context.Context, symbol string, restClient *rest.Client, opts ...DepthBookOption) (*DepthBook, error) {
+	if restClient == nil {
+		return nil, fmt.Errorf("rest client is required")
+	}
+
+	book := newDepthBook(strings.ToUpper(symbol), opts...)
+	book.snapshot = func(ctx context.Context) (*rest.OrderBook, error) {
+		if book.futures {
+			return restClient.GetFuturesOrderBook(ctx, book.symbol, book.snapshotLimit)
+		}
+		return restClient.GetOrderBook(ctx, book.symbol, book.snapshotLimit)
+	}
+	book.unsubscribe = func(ctx context.Context) error {
+		return c.UnsubscribeFromDepth(ctx, book.symbol)
+	}
+
+	if err := c.SubscribeToDepth(ctx, book.symbol, book.handleEvent); err != nil {
+		book.cancel()
+		return nil, err
+	}
+
+	if err := book.sync(ctx, maxInitialSyncAttempts); err != nil {
+		book.Close(ctx)
+		return nil, fmt.Errorf("failed to sync %s depth book: %w", book.symbol, err)
+	}
+	return book, nil
+}
+
+func newDepthBook(symbol string, opts ...DepthBookOption) *DepthBook {
+	ctx, cancel := context.WithCancel(context.Background())
+	b := &DepthBook{
+		symbol:        symbol,
+		snapshotLimit: DefaultDepthSnapshotLimit,
+		resyncDelay:   DefaultDepthResyncDelay,
+		bids:          make(map[string]PriceLevel),
+		asks:          make(map[string]PriceLevel),
+		buffered:      make(chan struct{}, 1),
+		ctx:           ctx,
+		cancel:        cancel,
+	}
+	for _, opt := range opts {
+		opt(b)
+	}
+	return b
+}
+
+// Symbol returns the book's symbol
+func (b *DepthBook) Symbol() string {
+	return b.symbol
+}
+
+// Synced reports whether the book currently matches the stream
+func (b *DepthBook) Synced() bool {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+	return b.synced
+}
+
+// LastUpdateID returns the update ID the book reflects
+func (b *DepthBook) LastUpdateID() int64 {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+	return b.lastUpdateID
+}
+
+// Resyncs returns how many times a stream gap forced a fresh snapshot
+func (b *DepthBook) Resyncs() int {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+	return b.resyncs
+}
+
+// Bids returns up to limit bid levels, best first. A non-positive limit
+// returns every level.
+func (b *DepthBook) Bids(limit int) []PriceLevel {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+	return sortedLevels(b.bids, limit, func(x, y decimal.Decimal) bool { return x.GreaterThan(y) })
+}
+
+// Asks returns up to limit ask levels, best first. A non-positive limit
+// returns every level.
+func (b *DepthBook) Asks(limit int) []PriceLevel {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+	return sortedLevels(b.asks, limit, func(x, y decimal.Decimal) bool { return x.LessThan(y) })
+}
+
+// BestBid returns the highest bid, or false when there are no bids
+func (b *DepthBook) BestBid() (PriceLevel, bool) {
+	levels := b.Bids(1)
+	if len(levels) == 0 {
+		return PriceLevel{}, false
+	}
+	return levels[0], true
+}
+
+// BestAsk returns the lowest ask, or false when there are no asks
+func (b *DepthBook) BestAsk() (PriceLevel, bool) {
+	levels := b.Asks(1)
+	if len(levels) == 0 {
+		return PriceLevel{}, false
+	}
+	return levels[0], true
+}
+
+// Close stops maintaining the book, waits for any resync in progress and
+// unsubscribes from the depth stream
+func (b *DepthBook) Close(ctx context.Context) error {
+	b.mu.Lock()
+	if b.closed {
+		b.mu.Unlock()
+		return nil
+	}
+	b.closed = true
+	b.synced = false
+	b.mu.Unlock()
+
+	b.cancel()
+	b.wg.Wait()
+
+	if b.unsubscribe == nil {
+		return nil
+	}
+	return b.unsubscribe(ctx)
+}
+
+// handleEvent is the depth stream handler. Events are buffered until the
+// book is synced and applied afterwards; a gap starts a background resync.
+func (b *DepthBook) handleEvent(event *DepthUpdateEvent) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.closed {
+		return nil
+	}
+	if !b.synced {
+		b.bufferLocked(event)
+		return nil
+	}
+	if b.applyLocked(event) {
+		b.applyPendingLocked()
+		return nil
+	}
+
+	// Hold an early event until the ones before it turn up
+	b.pending = append(b.pending, event)
+	if len(b.pending) <= maxDepthReorder {
+		return nil
+	}
+
+	// The stream skipped an update: the book can't be trusted until it is
+	// rebuilt from a snapshot newer than the held events
+	b.synced = false
+	b.resyncs++
+	b.buffer = nil
+	for _, held := range b.pending {
+		b.bufferLocked(held)
+	}
+	b.pending = nil
+
+	b.wg.Add(1)
+	go func() {
+		defer b.wg.Done()
+		// Retries until synced or closed, so the error is only ever
+		// cancellation
+		_ = b.sync(b.ctx, 0)
+	}()
+	return nil
+}
+
+// applyPendingLocked applies held events that now follow on from the book
+func (b *DepthBook) applyPendingLocked() {
+	sortEvents(b.pending)
+	for len(b.pending) > 0 && b.applyLocked(b.pending[0]) {
+		b.pending = b.pending[1:]
+	}
+	if len(b.pending) == 0 {
+		b.pending = nil
+	}
+}
+
+func (b *DepthBook) bufferLocked(event *DepthUpdateEvent) {
+	if len(b.buffer) >= maxDepthBuffer {
+		b.buffer = nil
+	}
+	b.buffer = append(b.buffer, event)
+
+	select {
+	case b.buffered <- struct{}{}:
+	default:
+	}
+}
+
+// sync fetches snapshots until one lines up with the buffered events, making
+// at most attempts tries, or unlimited when attempts is zero
+func (b *DepthBook) sync(ctx context.Context, attempts int) error {
+	delay := b.resyncDelay
+	for attempt := 1; ; attempt++ {
+		// Take the snapshot only once the stream is flowing, so it cannot
+		// predate every buffered event
+		if err := b.waitForBuffered(ctx); err != nil {
+			return err
+		}
+
+		snapshot, err := b.snapshot(ctx)
+		if err == nil {
+			if b.applySnapshot(snapshot) {
+				return nil
+			}
+			err = errStaleSnapshot
+		}
+		if attempts > 0 && attempt >= attempts {
+			return fmt.Errorf("gave up after %d attempts: %w", attempt, err)
+		}
+
+		timer := time.NewTimer(delay)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return ctx.Err()
+		case <-timer.C:
+		}
+		delay = min(delay*2, maxDepthResyncDelay)
+	}
+}
+
+// waitForBuffered blocks until at least one stream event is buffered
+func (b *DepthBook) waitForBuffered(ctx context.Context) error {
+	for {
+		b.mu.RLock()
+		ready := len(b.buffer) > 0
+		b.mu.RUnlock()
+		if ready {
+			return nil
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-b.buffered:
+		}
+	}
+}
+
+// applySnapshot replaces the book with snapshot and replays the buffered
+// events on top of it. It reports false, keeping the buffer for the next
+// attempt, when the events don't follow on from the snapshot.
+func (b *DepthBook) applySnapshot(snapshot *rest.OrderBook) bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.closed {
+		return true
+	}
+
+	b.bids = make(map[string]PriceLevel, len(snapshot.Bids))
+	b.asks = make(map[string]PriceLevel, len(snapshot.Asks))
+	for _, level := range snapshot.Bids {
+		setLevel(b.bids, PriceLevel{Price: level.Price, Quantity: level.Quantity})
+	}
+	for _, level := range snapshot.Asks {
+		setLevel(b.asks, PriceLevel{Price: level.Price, Quantity: level.Quantity})
+	}
+	b.lastUpdateID = snapshot.LastUpdateID
+	b.awaitingFirst = true
+	b.pending = nil
+
+	sortEvents(b.buffer)
+	for _, event := range b.buffer {
+		if !b.applyLocked(event) {
+			return false
+		}
+	}
+
+	b.buffer = nil
+	b.synced = true
+	return true
+}
+
+// applyLocked applies event if it continues the book's sequence. Events the
+// book already reflects are skipped; anything else that doesn't follow on is
+// a gap and reported as false.
+func (b *DepthBook) applyLocked(event *DepthUpdateEvent) bool {
+	if event.FinalUpdateID <= b.lastUpdateID {
+		return true
+	}
+
+	switch {
+	case b.awaitingFirst:
+		// The first event after the snapshot must span its last update
+		if event.FirstUpdateID > b.lastUpdateID+1 {
+			return false
+		}
+	case b.futures:
+		if event.PrevFinalUpdateID != b.lastUpdateID {
+			return false
+		}
+	default:
+		if event.FirstUpdateID != b.lastUpdateID+1 {
+			return false
+		}
+	}
+
+	for _, level := range event.Bids {
+		setLevel(b.bids, level)
+	}
+	for _, level := range event.Asks {
+		setLevel(b.asks, level)
+	}
+	b.lastUpdateID = event.FinalUpdateID
+	b.awaitingFirst = false
+	return true
+}
+
+// sortEvents orders events by update ID, undoing any reordering in dispatch
+func sortEvents(events []*DepthUpdateEvent) {
+	sort.SliceStable(events, func(i, j int) bool {
+		return events[i].FinalUpdateID < events[j].FinalUpdateID
+	})
+}
+
+// setLevel stores level, removing the price when its quantity is zero
+func setLevel(levels map[string]PriceLevel, level PriceLevel) {
+	key := level.Price.String()
+	if level.Quantity.IsZero() {
+		delete(levels, key)
+		return
+	}
+	levels[key] = level
+}
+
+// sortedLevels returns up to limit levels ordered by better
+func sortedLevels(levels map[string]PriceLevel, limit int, better func(x, y decimal.Decimal) bool) []PriceLevel {
+	sorted := make([]PriceLevel, 0, len(levels))
+	for _, level := range levels {
+		sorted = append(sorted, level)
+	}
+	sort.Slice(sorted, func(i, j int) bool {
+		return better(sorted[i].Price, sorted[j].Price)
+	})
+
+	if limit > 0 && len(sorted) > limit {
+		sorted = sorted[:limit]
+	}
+	return sorted
+}
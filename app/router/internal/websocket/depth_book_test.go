@@ -0,0 +1,318 @@
+package websocket
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/gorilla/websocket"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"router/internal/rest"
+)
+
+func level(price, quantity string) PriceLevel {
+	return PriceLevel{Price: decimal.RequireFromString(price), Quantity: decimal.RequireFromString(quantity)}
+}
+
+func restLevel(price, quantity string) rest.PriceLevel {
+	return rest.PriceLevel{Price: decimal.RequireFromString(price), Quantity: decimal.RequireFromString(quantity)}
+}
+
+func depthUpdate(first, final int64, bids, asks []PriceLevel) *DepthUpdateEvent {
+	event := depthEvent("BTCUSDT", first, final)
+	event.Bids = bids
+	event.Asks = asks
+	return event
+}
+
+// levelStrings renders levels as "price:quantity" for compact assertions
+func levelStrings(levels []PriceLevel) []string {
+	out := make([]string, len(levels))
+	for i, l := range levels {
+		out[i] = l.Price.String() + ":" + l.Quantity.String()
+	}
+	return out
+}
+
+func TestDepthBook_Sync(t *testing.T) {
+	snapshot := &rest.OrderBook{
+		LastUpdateID: 102,
+		Bids:         []rest.PriceLevel{restLevel("50000", "1"), restLevel("49900", "2")},
+		Asks:         []rest.PriceLevel{restLevel("50100", "1")},
+	}
+
+	t.Run("applies buffered events after the snapshot, skipping those it covers", func(t *testing.T) {
+		book := newDepthBook("BTCUSDT")
+
+		// All three arrive while the snapshot is in flight
+		require.NoError(t, book.handleEvent(depthUpdate(100, 101, []PriceLevel{level("49990", "9")}, nil)))
+		require.NoError(t, book.handleEvent(depthUpdate(102, 104, []PriceLevel{level("50000", "3")}, nil)))
+		require.NoError(t, book.handleEvent(depthUpdate(105, 106, []PriceLevel{level("49900", "0")}, []PriceLevel{level("50200", "4")})))
+		assert.False(t, book.Synced())
+
+		require.True(t, book.applySnapshot(snapshot))
+		assert.True(t, book.Synced())
+		assert.Equal(t, int64(106), book.LastUpdateID())
+
+		// 100-101 predates the snapshot, so its 49990 bid must not appear
+		assert.Equal(t, []string{"50000:3"}, levelStrings(book.Bids(0)))
+		assert.Equal(t, []string{"50100:1", "50200:4"}, levelStrings(book.Asks(0)))
+
+		// Live events continue the sequence
+		require.NoError(t, book.handleEvent(depthUpdate(107, 107, nil, []PriceLevel{level("50050", "2")})))
+		ask, ok := book.BestAsk()
+		require.True(t, ok)
+		assert.Equal(t, "50050:2", levelStrings([]PriceLevel{ask})[0])
+		assert.Equal(t, 0, book.Resyncs())
+	})
+
+	t.Run("rejects a snapshot older than the buffered events", func(t *testing.T) {
+		book := newDepthBook("BTCUSDT")
+		require.NoError(t, book.handleEvent(depthUpdate(110, 112, nil, nil)))
+
+		// 103-109 were never seen, so the snapshot can't be bridged
+		assert.False(t, book.applySnapshot(snapshot))
+		assert.False(t, book.Synced())
+
+		newer := &rest.OrderBook{LastUpdateID: 111}
+		assert.True(t, book.applySnapshot(newer))
+		assert.Equal(t, int64(112), book.LastUpdateID())
+	})
+
+	t.Run("a snapshot ahead of every buffered event waits for the next one", func(t *testing.T) {
+		book := newDepthBook("BTCUSDT")
+		require.NoError(t, book.handleEvent(depthUpdate(90, 95, nil, nil)))
+
+		require.True(t, book.applySnapshot(snapshot))
+		require.NoError(t, book.handleEvent(depthUpdate(96, 102, nil, nil)))
+		require.NoError(t, book.handleEvent(depthUpdate(103, 103, []PriceLevel{level("49000", "1")}, nil)))
+		assert.True(t, book.Synced())
+		assert.Equal(t, int64(103), book.LastUpdateID())
+	})
+
+	t.Run("events delivered out of order are applied in sequence", func(t *testing.T) {
+		book := newDepthBook("BTCUSDT")
+		require.NoError(t, book.handleEvent(depthUpdate(105, 106, []PriceLevel{level("49950", "5")}, nil)))
+		require.NoError(t, book.handleEvent(depthUpdate(102, 104, []PriceLevel{level("50000", "3")}, nil)))
+		require.True(t, book.applySnapshot(snapshot))
+
+		require.NoError(t, book.handleEvent(depthUpdate(108, 108, []PriceLevel{level("50000", "0")}, nil)))
+		assert.Equal(t, int64(106), book.LastUpdateID(), "108 waits for 107")
+		require.NoError(t, book.handleEvent(depthUpdate(107, 107, []PriceLevel{level("50000", "7")}, nil)))
+
+		assert.True(t, book.Synced())
+		assert.Equal(t, int64(108), book.LastUpdateID())
+		assert.Equal(t, []string{"49950:5", "49900:2"}, levelStrings(book.Bids(0)))
+		assert.Equal(t, 0, book.Resyncs())
+	})
+
+	t.Run("futures continuity follows PrevFinalUpdateID", func(t *testing.T) {
+		book := newDepthBook("BTCUSDT", WithFuturesDepth())
+
+		first := depthUpdate(101, 104, nil, nil)
+		first.PrevFinalUpdateID = 100
+		require.NoError(t, book.handleEvent(first))
+		require.True(t, book.applySnapshot(snapshot))
+
+		// Futures IDs need not be contiguous; pu must match the last u
+		next := depthUpdate(110, 115, nil, nil)
+		next.PrevFinalUpdateID = 104
+		require.NoError(t, book.handleEvent(next))
+		assert.True(t, book.Synced())
+		assert.Equal(t, int64(115), book.LastUpdateID())
+	})
+}
+
+// depthFixture serves a depth stream whose events the test feeds through
+// send, and REST snapshots taken from snapshots in order
+type depthFixture struct {
+	t      *testing.T
+	events chan string
+
+	mu            sync.Mutex
+	snapshots     []rest.OrderBook
+	snapshotCalls int
+	// beforeSnapshot, when set, runs before a snapshot is served
+	beforeSnapshot func(call int)
+}
+
+func newDepthFixture(t *testing.T, snapshots ...rest.OrderBook) (*depthFixture, *Client, *rest.Client) {
+	t.Helper()
+	f := &depthFixture{t: t, events: make(chan string, 100), snapshots: snapshots}
+
+	wsServer := newMockWebSocketServer(t, func(conn *websocket.Conn) {
+		defer conn.Close()
+
+		var writeMu sync.Mutex
+		write := func(v interface{}) error {
+			writeMu.Lock()
+			defer writeMu.Unlock()
+			return conn.WriteJSON(v)
+		}
+
+		var req SubscriptionRequest
+		if err := conn.ReadJSON(&req); err != nil {
+			return
+		}
+		write(SubscriptionResponse{ID: req.ID})
+
+		// Acknowledge the unsubscribe sent when the book closes
+		go func() {
+			for {
+				if err := conn.ReadJSON(&req); err != nil {
+					return
+				}
+				write(SubscriptionResponse{ID: req.ID})
+			}
+		}()
+		for data := range f.events {
+			if err := write(StreamMessage{Stream: "btcusdt@depth", Data: json.RawMessage(data)}); err != nil {
+				return
+			}
+		}
+	})
+	t.Cleanup(wsServer.Close)
+
+	restServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		f.mu.Lock()
+		call := f.snapshotCalls
+		f.snapshotCalls++
+		hook := f.beforeSnapshot
+		f.mu.Unlock()
+		if hook != nil {
+			hook(call)
+		}
+
+		f.mu.Lock()
+		snapshot := f.snapshots[min(call, len(f.snapshots)-1)]
+		f.mu.Unlock()
+
+		raw := map[string]interface{}{"lastUpdateId": snapshot.LastUpdateID, "bids": rawLevels(snapshot.Bids), "asks": rawLevels(snapshot.Asks)}
+		json.NewEncoder(w).Encode(raw)
+	}))
+	t.Cleanup(restServer.Close)
+
+	client := NewClient(WithBaseURL(getWebSocketURL(wsServer.URL)))
+	require.NoError(t, client.Connect(context.Background()))
+	t.Cleanup(func() {
+		close(f.events)
+		client.Close()
+	})
+
+	return f, client, rest.NewClient(restServer.URL, nil, rest.WithMaxRetries(0))
+}
+
+func rawLevels(levels []rest.PriceLevel) [][]string {
+	raw := make([][]string, len(levels))
+	for i, l := range levels {
+		raw[i] = []string{l.Price.String(), l.Quantity.String()}
+	}
+	return raw
+}
+
+func (f *depthFixture) send(first, final int64, bids string) {
+	f.events <- fmt.Sprintf(`{"e":"depthUpdate","s":"BTCUSDT","U":%d,"u":%d,"b":%s,"a":[]}`, first, final, bids)
+}
+
+func (f *depthFixture) calls() int {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.snapshotCalls
+}
+
+func TestClient_MaintainDepthBook(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("events racing the snapshot are applied after it", func(t *testing.T) {
+		fixture, client, restClient := newDepthFixture(t, rest.OrderBook{
+			LastUpdateID: 102,
+			Bids:         []rest.PriceLevel{restLevel("50000", "1")},
+		})
+
+		// Hold the snapshot until the stream has moved past it
+		fixture.beforeSnapshot = func(int) {
+			fixture.send(102, 104, `[["50000","3"]]`)
+			fixture.send(105, 106, `[["49950","5"]]`)
+			time.Sleep(50 * time.Millisecond)
+		}
+		fixture.send(100, 101, `[["49990","9"]]`)
+
+		book, err := client.MaintainDepthBook(ctx, "BTCUSDT", restClient, WithDepthResyncDelay(time.Millisecond))
+		require.NoError(t, err)
+		defer book.Close(ctx)
+
+		assert.True(t, book.Synced())
+		assert.Equal(t, int64(106), book.LastUpdateID())
+		assert.Equal(t, []string{"50000:3", "49950:5"}, levelStrings(book.Bids(0)))
+		assert.Equal(t, 1, fixture.calls())
+	})
+
+	t.Run("resyncs after a gap in the stream", func(t *testing.T) {
+		fixture, client, restClient := newDepthFixture(t,
+			rest.OrderBook{LastUpdateID: 10, Bids: []rest.PriceLevel{restLevel("100", "1")}},
+			rest.OrderBook{LastUpdateID: 30, Bids: []rest.PriceLevel{restLevel("105", "2")}},
+		)
+		fixture.send(9, 11, `[]`)
+
+		book, err := client.MaintainDepthBook(ctx, "BTCUSDT", restClient, WithDepthResyncDelay(time.Millisecond))
+		require.NoError(t, err)
+		defer book.Close(ctx)
+		require.Equal(t, int64(11), book.LastUpdateID())
+
+		// 12-19 are lost; the gap is declared once more events than the
+		// reorder window have arrived past it
+		fixture.send(20, 31, `[["104","1"]]`)
+		for id := int64(32); id <= 32+maxDepthReorder; id++ {
+			fixture.send(id, id, `[]`)
+		}
+		fixture.send(41, 41, `[["106","1"]]`)
+
+		require.Eventually(t, func() bool {
+			return book.Synced() && book.LastUpdateID() == 41
+		}, 2*time.Second, 5*time.Millisecond)
+
+		assert.Equal(t, 1, book.Resyncs())
+		assert.Equal(t, 2, fixture.calls())
+		assert.Equal(t, []string{"106:1", "105:2", "104:1"}, levelStrings(book.Bids(0)))
+	})
+
+	t.Run("retries a snapshot that predates the stream", func(t *testing.T) {
+		fixture, client, restClient := newDepthFixture(t,
+			rest.OrderBook{LastUpdateID: 5},
+			rest.OrderBook{LastUpdateID: 12},
+		)
+		fixture.send(10, 12, `[]`)
+		fixture.send(13, 14, `[["100","1"]]`)
+
+		book, err := client.MaintainDepthBook(ctx, "BTCUSDT", restClient, WithDepthResyncDelay(time.Millisecond))
+		require.NoError(t, err)
+		defer book.Close(ctx)
+
+		require.Eventually(t, func() bool {
+			return book.LastUpdateID() == 14
+		}, 2*time.Second, 5*time.Millisecond)
+		assert.Equal(t, 2, fixture.calls())
+		assert.Equal(t, 0, book.Resyncs())
+	})
+
+	t.Run("gives up when the snapshot never lines up", func(t *testing.T) {
+		fixture, client, restClient := newDepthFixture(t, rest.OrderBook{LastUpdateID: 1})
+		fixture.send(50, 60, `[]`)
+
+		_, err := client.MaintainDepthBook(ctx, "BTCUSDT", restClient, WithDepthResyncDelay(time.Millisecond))
+		require.ErrorIs(t, err, errStaleSnapshot)
+		assert.Equal(t, maxInitialSyncAttempts, fixture.calls())
+	})
+
+	t.Run("requires a rest client", func(t *testing.T) {
+		_, err := NewClient().MaintainDepthBook(ctx, "BTCUSDT", nil)
+		assert.EqualError(t, err, "rest client is required")
+	})
+}
@@ -0,0 +1,119 @@
+package websocket
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func depthEvent(symbol string, first, final int64) *DepthUpdateEvent {
+	return &DepthUpdateEvent{
+		EventType:     "depthUpdate",
+		Symbol:        symbol,
+		FirstUpdateID: first,
+		FinalUpdateID: final,
+	}
+}
+
+func TestDepthGapDetector(t *testing.T) {
+	var gaps []DepthGap
+	detector := NewDepthGapDetector(func(gap DepthGap) {
+		gaps = append(gaps, gap)
+	})
+
+	events := []struct {
+		event      *DepthUpdateEvent
+		contiguous bool
+	}{
+		{depthEvent("BTCUSDT", 100, 105), true}, // first event establishes the sequence
+		{depthEvent("BTCUSDT", 106, 110), true},
+		{depthEvent("ETHUSDT", 7, 9), true}, // symbols are tracked independently
+		{depthEvent("BTCUSDT", 111, 111), true},
+		{depthEvent("ETHUSDT", 10, 12), true},
+	}
+	for i, e := range events {
+		assert.Equal(t, e.contiguous, detector.Check(e.event), "event %d", i)
+	}
+
+	// 112-114 never arrive: the events after them are held until the
+	// reorder window is exceeded
+	first := int64(115)
+	for i := 0; i < maxDepthReorder; i++ {
+		assert.True(t, detector.Check(depthEvent("BTCUSDT", first, first+4)), "held event %d", i)
+		first += 5
+	}
+	assert.Empty(t, gaps)
+
+	assert.False(t, detector.Check(depthEvent("BTCUSDT", first, first+4)))
+	require.Len(t, gaps, 1)
+	assert.Equal(t, DepthGap{Symbol: "BTCUSDT", ExpectedUpdateID: 112, FirstUpdateID: 115}, gaps[0])
+
+	// Tracking resumes after the gap, past every held event
+	first += 5
+	assert.True(t, detector.Check(depthEvent("BTCUSDT", first, first+4)))
+	assert.Len(t, gaps, 1)
+}
+
+func TestDepthGapDetector_ToleratesReordering(t *testing.T) {
+	gapCount := 0
+	detector := NewDepthGapDetector(func(DepthGap) { gapCount++ })
+
+	// Concurrent dispatch can swap neighbouring events and repeat one
+	for _, event := range []*DepthUpdateEvent{
+		depthEvent("BTCUSDT", 1, 5),
+		depthEvent("BTCUSDT", 11, 15),
+		depthEvent("BTCUSDT", 16, 20),
+		depthEvent("BTCUSDT", 6, 10),
+		depthEvent("BTCUSDT", 6, 10),
+		depthEvent("BTCUSDT", 26, 30),
+		depthEvent("BTCUSDT", 21, 25),
+		depthEvent("BTCUSDT", 31, 35),
+	} {
+		assert.True(t, detector.Check(event), "event %d-%d", event.FirstUpdateID, event.FinalUpdateID)
+	}
+	assert.Equal(t, 0, gapCount)
+
+	// A stale event behind the sequence is not a gap either
+	assert.True(t, detector.Check(depthEvent("BTCUSDT", 2, 3)))
+	assert.Equal(t, 0, gapCount)
+}
+
+func TestDepthGapDetector_Reset(t *testing.T) {
+	gapCount := 0
+	detector := NewDepthGapDetector(func(DepthGap) { gapCount++ })
+
+	detector.Check(depthEvent("BTCUSDT", 1, 5))
+	detector.Reset("btcusdt")
+
+	// After a resync the next event starts a fresh sequence
+	assert.True(t, detector.Check(depthEvent("BTCUSDT", 50, 55)))
+	assert.Equal(t, 0, gapCount)
+}
+
+func TestWithGapDetection(t *testing.T) {
+	var gaps []DepthGap
+	var delivered []int64
+
+	sub := &depthSubscription{}
+	WithGapDetection(func(gap DepthGap) { gaps = append(gaps, gap) })(sub)
+
+	handler := sub.wrap(func(event *DepthUpdateEvent) error {
+		delivered = append(delivered, event.FirstUpdateID)
+		return nil
+	})
+
+	require.NoError(t, handler(depthEvent("BTCUSDT", 1, 2)))
+	require.NoError(t, handler(depthEvent("BTCUSDT", 3, 4)))
+	assert.Empty(t, gaps)
+
+	// 5-8 are lost; the gap is reported once the window fills
+	for first := int64(9); len(gaps) == 0; first += 2 {
+		require.NoError(t, handler(depthEvent("BTCUSDT", first, first+1)))
+	}
+	assert.Equal(t, int64(5), gaps[0].ExpectedUpdateID)
+	assert.Equal(t, int64(9), gaps[0].FirstUpdateID)
+
+	// Held and gapped events are still delivered
+	assert.Len(t, delivered, 2+maxDepthReorder+1)
+}
@@ -1,35 +1,130 @@
 package websocket
 
 import (
+	"bytes"
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"slices"
+	"sort"
 	"sync"
 	"sync/atomic"
 	"time"
+
+	"router/internal/rest"
 )
 
+// MaxStreamsPerConnection is Binance's cap on streams for a single connection
+const MaxStreamsPerConnection = 1024
+
+// ErrStreamLimitExceeded is returned when a subscribe would take a connection
+// past its stream limit
+var ErrStreamLimitExceeded = errors.New("stream limit exceeded")
+
+// errSubscribeSend marks a subscribe frame that could not be written
+var errSubscribeSend = errors.New("failed to send subscription request")
+
+// SubscriptionError is a subscribe or unsubscribe request the server rejected
+type SubscriptionError struct {
+	Op   string // "subscription" or "unsubscription"
+	Code int
+	Msg  string
+}
+
+// Error implements the error interface
+func (e *SubscriptionError) Error() string {
+	return fmt.Sprintf("%s failed: [%d] %s", e.Op, e.Code, e.Msg)
+}
+
+// IsRetryable reports whether the rejection is transient, classifying the
+// code the same way REST errors are
+func (e *SubscriptionError) IsRetryable() bool {
+	return (&rest.BinanceError{Code: e.Code}).IsRetryable()
+}
+
+// IsRetryableSubscribeError reports whether a subscribe failure may succeed
+// if attempted again: a frame that failed to send or a transient server
+// rejection. Invalid streams, stream limits and cancellation are permanent.
+func IsRetryableSubscribeError(err error) bool {
+	if err == nil || errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+		return false
+	}
+
+	var subErr *SubscriptionError
+	if errors.As(err, &subErr) {
+		return subErr.IsRetryable()
+	}
+	return errors.Is(err, errSubscribeSend)
+}
+
+// resubscribeRetryInterval is how often the monitor retries a replay that
+// failed on the current connection
+const resubscribeRetryInterval = time.Second
+
+// ControlMessagesPerSecond paces SUBSCRIBE and UNSUBSCRIBE frames. Binance
+// drops connections that send more than 5 messages a second, pings and pongs
+// included, so one slot is left spare.
+const ControlMessagesPerSecond = 4
+
 // StreamManager manages WebSocket streams and subscriptions
 type StreamManager struct {
 	conn              *Connection
 	subscriptions     map[string]bool
+	reserved          map[string]bool // streams with a subscribe request in flight
+	activity          map[string]*streamActivity
+	maxStreams        int
 	subscriptionsMu   sync.RWMutex
 	requestID         int64
-	pendingRequests   map[int]chan SubscriptionResponse
+	pendingRequests   map[int]*pendingRequest
 	pendingRequestsMu sync.RWMutex
 
+	// Gates outbound subscribe and unsubscribe frames
+	controlLimiter *rest.RateLimiter
+
 	// Connection state monitoring
 	lastState        ConnectionState
+	lastGeneration   uint64
 	stateMu          sync.RWMutex
 	stopMonitoring   chan struct{}
 	monitoringActive bool
 
+	// Connection generation whose subscriptions have been replayed. Connect
+	// and the state monitor both resubscribe after a reconnect; the first
+	// one to replay a generation successfully does the work, and the monitor
+	// retries a generation whose replay failed.
+	resubscribeMu          sync.Mutex
+	resubscribedGeneration atomic.Uint64
+	lastResubscribeAttempt time.Time // guarded by stateMu
+
+	// Streams silent for longer than this are resubscribed; zero disables
+	staleResubscribeAfter time.Duration
+
 	// Message handlers
 	depthHandler  DepthHandler
 	tickerHandler TickerHandler
+	allTickers    AllTickersHandler
+	liquidations  LiquidationHandler
 	userHandler   UserStreamHandler
 	eventHandler  EventHandler
 	handlersMu    sync.RWMutex
+
+	// Called after an automatic reconnection has been handled
+	reconnectHandler func()
+}
+
+// streamActivity is the traffic seen on one subscribed stream. Its counters
+// are atomic so messages only take the subscriptions read lock.
+type streamActivity struct {
+	messages    atomic.Int64
+	lastMessage atomic.Int64 // unix nanos, zero until the first message
+	lastSeen    atomic.Int64 // unix nanos of the last message, or of the subscribe
+}
+
+// pendingRequest is a subscribe or unsubscribe frame awaiting its response
+type pendingRequest struct {
+	response chan SubscriptionResponse
+	params   []string
 }
 
 // NewStreamManager creates a new stream manager
@@ -37,13 +132,18 @@ func NewStreamManager(url string, opts ...ConnectionOption) *StreamManager {
 	sm := &StreamManager{
 		conn:            NewConnection(url, opts...),
 		subscriptions:   make(map[string]bool),
-		pendingRequests: make(map[int]chan SubscriptionResponse),
+		reserved:        make(map[string]bool),
+		activity:        make(map[string]*streamActivity),
+		maxStreams:      MaxStreamsPerConnection,
+		pendingRequests: make(map[int]*pendingRequest),
+		controlLimiter:  rest.NewRateLimiter(ControlMessagesPerSecond, 1),
 		lastState:       StateDisconnected,
 		stopMonitoring:  make(chan struct{}),
 	}
 
 	// Set message handler to route incoming messages
 	sm.conn.SetMessageHandler(sm.handleMessage)
+	sm.conn.setFailoverHandler(sm.handleFailover)
 
 	return sm
 }
@@ -53,6 +153,95 @@ func (sm *StreamManager) URL() string {
 	return sm.conn.URL()
 }
 
+// SetMaxStreams overrides the per-connection stream limit
+func (sm *StreamManager) SetMaxStreams(max int) {
+	sm.subscriptionsMu.Lock()
+	defer sm.subscriptionsMu.Unlock()
+	sm.maxStreams = max
+}
+
+// SetControlRate overrides how many subscribe and unsubscribe frames may be
+// sent per second and how many may go out back to back
+func (sm *StreamManager) SetControlRate(perSecond float64, burst int) {
+	sm.subscriptionsMu.Lock()
+	defer sm.subscriptionsMu.Unlock()
+	sm.controlLimiter = rest.NewRateLimiter(perSecond, burst)
+}
+
+// waitControlSlot blocks until another control frame may be sent
+func (sm *StreamManager) waitControlSlot(ctx context.Context) error {
+	sm.subscriptionsMu.RLock()
+	limiter := sm.controlLimiter
+	sm.subscriptionsMu.RUnlock()
+
+	if err := limiter.Wait(ctx); err != nil {
+		return fmt.Errorf("control message rate limited: %w", err)
+	}
+	return nil
+}
+
+// MaxStreams returns the per-connection stream limit
+func (sm *StreamManager) MaxStreams() int {
+	sm.subscriptionsMu.RLock()
+	defer sm.subscriptionsMu.RUnlock()
+	return sm.maxStreams
+}
+
+// StreamCount returns the number of active and in-flight subscriptions
+func (sm *StreamManager) StreamCount() int {
+	sm.subscriptionsMu.RLock()
+	defer sm.subscriptionsMu.RUnlock()
+	return len(sm.subscriptions) + len(sm.reserved)
+}
+
+// reserveStreams claims capacity for streams not yet subscribed, failing if
+// the connection would exceed its limit. It returns the streams it reserved.
+func (sm *StreamManager) reserveStreams(streams []string) ([]string, error) {
+	sm.subscriptionsMu.Lock()
+	defer sm.subscriptionsMu.Unlock()
+
+	var added []string
+	seen := make(map[string]bool, len(streams))
+	for _, stream := range streams {
+		if sm.subscriptions[stream] || sm.reserved[stream] || seen[stream] {
+			continue
+		}
+		seen[stream] = true
+		added = append(added, stream)
+	}
+
+	total := len(sm.subscriptions) + len(sm.reserved) + len(added)
+	if total > sm.maxStreams {
+		return nil, fmt.Errorf("%w: subscribing %d new streams would bring connection to %d, limit is %d",
+			ErrStreamLimitExceeded, len(added), total, sm.maxStreams)
+	}
+
+	for _, stream := range added {
+		sm.reserved[stream] = true
+	}
+	return added, nil
+}
+
+// releaseStreams drops reservations, marking them subscribed on success
+func (sm *StreamManager) releaseStreams(streams []string, subscribed bool) {
+	sm.subscriptionsMu.Lock()
+	defer sm.subscriptionsMu.Unlock()
+
+	now := time.Now().UnixNano()
+	for _, stream := range streams {
+		delete(sm.reserved, stream)
+		if subscribed {
+			sm.subscriptions[stream] = true
+			sm.activityLocked(stream).lastSeen.Store(now)
+		}
+	}
+}
+
+// Stats returns the traffic counters of the underlying connection
+func (sm *StreamManager) Stats() ConnectionStats {
+	return sm.conn.Stats()
+}
+
 // State returns the current connection state
 func (sm *StreamManager) State() ConnectionState {
 	return sm.conn.State()
@@ -70,29 +259,14 @@ func (sm *StreamManager) Connect(ctx context.Context) error {
 	if !sm.monitoringActive {
 		sm.monitoringActive = true
 		sm.lastState = StateConnected
+		sm.lastGeneration = sm.conn.Generation()
 		go sm.monitorConnectionState()
 	}
 	sm.stateMu.Unlock()
 
 	// Resubscribe to active streams if any exist
-	sm.subscriptionsMu.RLock()
-	activeStreams := make([]string, 0, len(sm.subscriptions))
-	for stream := range sm.subscriptions {
-		activeStreams = append(activeStreams, stream)
-	}
-	sm.subscriptionsMu.RUnlock()
-
-	if len(activeStreams) > 0 {
-		// Clear current subscriptions and resubscribe
-		sm.subscriptionsMu.Lock()
-		sm.subscriptions = make(map[string]bool)
-		sm.subscriptionsMu.Unlock()
-
-		// Resubscribe to all previously active streams
-		err = sm.SubscribeMultiple(ctx, activeStreams)
-		if err != nil {
-			return fmt.Errorf("failed to resubscribe to streams: %w", err)
-		}
+	if err := sm.resubscribe(ctx, sm.conn.Generation()); err != nil {
+		return fmt.Errorf("failed to resubscribe to streams: %w", err)
 	}
 
 	return nil
@@ -116,19 +290,21 @@ func (sm *StreamManager) Close() error {
 	sm.subscriptionsMu.Lock()
 	// Clear all subscriptions
 	sm.subscriptions = make(map[string]bool)
+	sm.reserved = make(map[string]bool)
+	sm.activity = make(map[string]*streamActivity)
 	sm.subscriptionsMu.Unlock()
 
 	sm.pendingRequestsMu.Lock()
 	// Close all pending request channels
-	for _, ch := range sm.pendingRequests {
+	for _, pending := range sm.pendingRequests {
 		select {
-		case <-ch:
+		case <-pending.response:
 			// Already closed
 		default:
-			close(ch)
+			close(pending.response)
 		}
 	}
-	sm.pendingRequests = make(map[int]chan SubscriptionResponse)
+	sm.pendingRequests = make(map[int]*pendingRequest)
 	sm.pendingRequestsMu.Unlock()
 
 	return sm.conn.Close()
@@ -139,12 +315,57 @@ func (sm *StreamManager) Subscribe(ctx context.Context, stream string) error {
 	return sm.SubscribeMultiple(ctx, []string{stream})
 }
 
-// SubscribeMultiple subscribes to multiple streams
+// SubscribeMultiple subscribes to multiple streams, retrying transient
+// failures with backoff as configured by WithSubscribeRetry
 func (sm *StreamManager) SubscribeMultiple(ctx context.Context, streams []string) error {
+	retries, delay := sm.conn.SubscribeRetry()
+
+	for attempt := 0; ; attempt++ {
+		err := sm.subscribeOnce(ctx, streams)
+		if err == nil || attempt >= retries || !IsRetryableSubscribeError(err) {
+			return err
+		}
+
+		wait := delay << attempt
+		if wait <= 0 || wait > maxSubscribeRetryDelay {
+			wait = maxSubscribeRetryDelay
+		}
+		sm.conn.logger.Warn().
+			Err(err).
+			Strs("streams", streams).
+			Int("attempt", attempt+1).
+			Dur("retry_in", wait).
+			Msg("Subscribe failed, retrying")
+
+		timer := time.NewTimer(wait)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return err
+		case <-timer.C:
+		}
+	}
+}
+
+// subscribeOnce sends one SUBSCRIBE frame and waits for its response
+func (sm *StreamManager) subscribeOnce(ctx context.Context, streams []string) error {
 	if sm.State() != StateConnected {
 		return fmt.Errorf("not connected")
 	}
 
+	reserved, err := sm.reserveStreams(streams)
+	if err != nil {
+		return err
+	}
+	subscribed := false
+	defer func() {
+		sm.releaseStreams(reserved, subscribed)
+	}()
+
+	if err := sm.waitControlSlot(ctx); err != nil {
+		return err
+	}
+
 	// Create subscription request
 	requestID := int(atomic.AddInt64(&sm.requestID, 1))
 	request := SubscriptionRequest{
@@ -156,7 +377,7 @@ func (sm *StreamManager) SubscribeMultiple(ctx context.Context, streams []string
 	// Create response channel
 	responseChan := make(chan SubscriptionResponse, 1)
 	sm.pendingRequestsMu.Lock()
-	sm.pendingRequests[requestID] = responseChan
+	sm.pendingRequests[requestID] = &pendingRequest{response: responseChan, params: streams}
 	sm.pendingRequestsMu.Unlock()
 
 	// Send subscription request
@@ -173,7 +394,7 @@ func (sm *StreamManager) SubscribeMultiple(ctx context.Context, streams []string
 		sm.pendingRequestsMu.Lock()
 		delete(sm.pendingRequests, requestID)
 		sm.pendingRequestsMu.Unlock()
-		return fmt.Errorf("failed to send subscription request: %w", err)
+		return fmt.Errorf("%w: %w", errSubscribeSend, err)
 	}
 
 	// Wait for response
@@ -184,16 +405,11 @@ func (sm *StreamManager) SubscribeMultiple(ctx context.Context, streams []string
 		sm.pendingRequestsMu.Unlock()
 
 		if response.Error != nil {
-			return fmt.Errorf("subscription failed: [%d] %s", response.Error.Code, response.Error.Msg)
-		}
-
-		// Mark streams as subscribed
-		sm.subscriptionsMu.Lock()
-		for _, stream := range streams {
-			sm.subscriptions[stream] = true
+			return &SubscriptionError{Op: "subscription", Code: response.Error.Code, Msg: response.Error.Msg}
 		}
-		sm.subscriptionsMu.Unlock()
 
+		// Reserved streams are marked subscribed on release
+		subscribed = true
 		return nil
 	case <-ctx.Done():
 		sm.pendingRequestsMu.Lock()
@@ -228,6 +444,10 @@ func (sm *StreamManager) UnsubscribeMultiple(ctx context.Context, streams []stri
 		return nil // Nothing to unsubscribe from
 	}
 
+	if err := sm.waitControlSlot(ctx); err != nil {
+		return err
+	}
+
 	// Create unsubscription request
 	requestID := int(atomic.AddInt64(&sm.requestID, 1))
 	request := SubscriptionRequest{
@@ -239,7 +459,7 @@ func (sm *StreamManager) UnsubscribeMultiple(ctx context.Context, streams []stri
 	// Create response channel
 	responseChan := make(chan SubscriptionResponse, 1)
 	sm.pendingRequestsMu.Lock()
-	sm.pendingRequests[requestID] = responseChan
+	sm.pendingRequests[requestID] = &pendingRequest{response: responseChan, params: subscribedStreams}
 	sm.pendingRequestsMu.Unlock()
 
 	// Send unsubscription request
@@ -267,13 +487,14 @@ func (sm *StreamManager) UnsubscribeMultiple(ctx context.Context, streams []stri
 		sm.pendingRequestsMu.Unlock()
 
 		if response.Error != nil {
-			return fmt.Errorf("unsubscription failed: [%d] %s", response.Error.Code, response.Error.Msg)
+			return &SubscriptionError{Op: "unsubscription", Code: response.Error.Code, Msg: response.Error.Msg}
 		}
 
 		// Remove streams from subscriptions
 		sm.subscriptionsMu.Lock()
 		for _, stream := range subscribedStreams {
 			delete(sm.subscriptions, stream)
+			delete(sm.activity, stream)
 		}
 		sm.subscriptionsMu.Unlock()
 
@@ -299,6 +520,60 @@ func (sm *StreamManager) ActiveSubscriptions() []string {
 	return subscriptions
 }
 
+// StreamActivity is the traffic seen on one subscribed stream
+type StreamActivity struct {
+	Messages    int64
+	LastMessage time.Time // zero until the first message
+}
+
+// StreamActivity returns per-stream message counts for every subscribed
+// stream. Counts survive reconnects and restart from zero after an
+// unsubscribe.
+func (sm *StreamManager) StreamActivity() map[string]StreamActivity {
+	sm.subscriptionsMu.RLock()
+	defer sm.subscriptionsMu.RUnlock()
+
+	activity := make(map[string]StreamActivity, len(sm.subscriptions))
+	for stream := range sm.subscriptions {
+		var a StreamActivity
+		if counters := sm.activity[stream]; counters != nil {
+			a.Messages = counters.messages.Load()
+			if last := counters.lastMessage.Load(); last != 0 {
+				a.LastMessage = time.Unix(0, last)
+			}
+		}
+		activity[stream] = a
+	}
+	return activity
+}
+
+// StaleStreams returns the subscribed streams that have delivered nothing
+// for longer than threshold. A new subscription counts from when it was
+// confirmed.
+func (sm *StreamManager) StaleStreams(threshold time.Duration) []string {
+	sm.subscriptionsMu.RLock()
+	defer sm.subscriptionsMu.RUnlock()
+
+	cutoff := time.Now().Add(-threshold).UnixNano()
+	var stale []string
+	for stream := range sm.subscriptions {
+		if counters := sm.activity[stream]; counters == nil || counters.lastSeen.Load() < cutoff {
+			stale = append(stale, stream)
+		}
+	}
+	sort.Strings(stale)
+	return stale
+}
+
+// SetStaleStreamResubscribe makes the connection monitor resubscribe streams
+// that stay silent for longer than threshold, which Binance can do without
+// dropping the connection. Zero disables it.
+func (sm *StreamManager) SetStaleStreamResubscribe(threshold time.Duration) {
+	sm.stateMu.Lock()
+	defer sm.stateMu.Unlock()
+	sm.staleResubscribeAfter = threshold
+}
+
 // SetDepthHandler sets the depth update handler
 func (sm *StreamManager) SetDepthHandler(handler DepthHandler) {
 	sm.handlersMu.Lock()
@@ -313,6 +588,20 @@ func (sm *StreamManager) SetTickerHandler(handler TickerHandler) {
 	sm.tickerHandler = handler
 }
 
+// SetAllTickersHandler sets the handler for the !ticker@arr stream
+func (sm *StreamManager) SetAllTickersHandler(handler AllTickersHandler) {
+	sm.handlersMu.Lock()
+	defer sm.handlersMu.Unlock()
+	sm.allTickers = handler
+}
+
+// SetLiquidationHandler sets the handler for forceOrder events
+func (sm *StreamManager) SetLiquidationHandler(handler LiquidationHandler) {
+	sm.handlersMu.Lock()
+	defer sm.handlersMu.Unlock()
+	sm.liquidations = handler
+}
+
 // SetUserStreamHandler sets the user stream handler
 func (sm *StreamManager) SetUserStreamHandler(handler UserStreamHandler) {
 	sm.handlersMu.Lock()
@@ -327,21 +616,19 @@ func (sm *StreamManager) SetEventHandler(handler EventHandler) {
 	sm.eventHandler = handler
 }
 
+// SetReconnectHandler sets a callback run after each automatic reconnection,
+// once subscriptions have been replayed and handlers re-bound
+func (sm *StreamManager) SetReconnectHandler(handler func()) {
+	sm.handlersMu.Lock()
+	defer sm.handlersMu.Unlock()
+	sm.reconnectHandler = handler
+}
+
 // handleMessage processes incoming WebSocket messages
 func (sm *StreamManager) handleMessage(data []byte) {
 	// First, try to parse as a subscription response
-	var subResponse SubscriptionResponse
-	if err := json.Unmarshal(data, &subResponse); err == nil && subResponse.ID != 0 {
-		// This is a subscription response
-		sm.pendingRequestsMu.RLock()
-		if responseChan, exists := sm.pendingRequests[subResponse.ID]; exists {
-			select {
-			case responseChan <- subResponse:
-			default:
-				// Channel is full or closed
-			}
-		}
-		sm.pendingRequestsMu.RUnlock()
+	if response, ok := parseSubscriptionResponse(data); ok {
+		sm.deliverResponse(response)
 		return
 	}
 
@@ -351,16 +638,112 @@ func (sm *StreamManager) handleMessage(data []byte) {
 		// Malformed message, ignore
 		return
 	}
+	// Raw streams such as /ws/<listenKey> send the event itself, unwrapped
+	if len(streamMsg.Data) == 0 {
+		streamMsg.Data = data
+	}
+
+	if streamMsg.Stream != "" {
+		sm.subscriptionsMu.RLock()
+		if counters := sm.activity[streamMsg.Stream]; counters != nil && sm.subscriptions[streamMsg.Stream] {
+			now := time.Now().UnixNano()
+			counters.messages.Add(1)
+			counters.lastMessage.Store(now)
+			counters.lastSeen.Store(now)
+		}
+		sm.subscriptionsMu.RUnlock()
+	}
 
 	// Route message based on stream type
 	sm.routeStreamMessage(&streamMsg)
 }
 
+// parseSubscriptionResponse decodes data as the reply to a subscribe or
+// unsubscribe frame. Replies carry a result or an error and no stream, which
+// tells them apart from events even when their id is missing or zero.
+func parseSubscriptionResponse(data []byte) (SubscriptionResponse, bool) {
+	var probe struct {
+		Stream string          `json:"stream"`
+		Result json.RawMessage `json:"result"`
+		Error  json.RawMessage `json:"error"`
+	}
+	if err := json.Unmarshal(data, &probe); err != nil || probe.Stream != "" {
+		return SubscriptionResponse{}, false
+	}
+	if probe.Result == nil && probe.Error == nil {
+		return SubscriptionResponse{}, false
+	}
+
+	var response SubscriptionResponse
+	if err := json.Unmarshal(data, &response); err != nil {
+		return SubscriptionResponse{}, false
+	}
+	return response, true
+}
+
+// deliverResponse hands a subscription response to the request awaiting it.
+// A response without an id goes to the pending request with the same echoed
+// params or, failing that, to the only pending request. Responses that match
+// nothing are logged and dropped.
+func (sm *StreamManager) deliverResponse(response SubscriptionResponse) {
+	sm.pendingRequestsMu.RLock()
+	defer sm.pendingRequestsMu.RUnlock()
+
+	pending, ok := sm.pendingRequests[response.ID]
+	if !ok && response.ID == 0 {
+		pending = sm.matchPendingLocked(response.Params)
+		ok = pending != nil
+	}
+	if !ok {
+		sm.conn.logger.Warn().
+			Int("id", response.ID).
+			Strs("params", response.Params).
+			Int("pending", len(sm.pendingRequests)).
+			Msg("Dropping subscription response that matches no pending request")
+		return
+	}
+
+	select {
+	case pending.response <- response:
+	default:
+		// Already answered
+	}
+}
+
+// matchPendingLocked finds the pending request a response without an id
+// answers: the oldest one with the given params, or the only one when the
+// response echoes no params. Callers must hold pendingRequestsMu.
+func (sm *StreamManager) matchPendingLocked(params []string) *pendingRequest {
+	if len(params) == 0 {
+		if len(sm.pendingRequests) != 1 {
+			return nil
+		}
+		for _, pending := range sm.pendingRequests {
+			return pending
+		}
+	}
+
+	var match *pendingRequest
+	matchID := 0
+	for id, pending := range sm.pendingRequests {
+		if slices.Equal(pending.params, params) && (match == nil || id < matchID) {
+			match, matchID = pending, id
+		}
+	}
+	return match
+}
+
 // routeStreamMessage routes stream messages to appropriate handlers
 func (sm *StreamManager) routeStreamMessage(msg *StreamMessage) {
 	sm.handlersMu.RLock()
 	defer sm.handlersMu.RUnlock()
 
+	// Array streams such as !ticker@arr batch many events in one message
+	if data := bytes.TrimSpace(msg.Data); len(data) > 0 && data[0] == '[' {
+		sm.routeTickerArray(data)
+		return
+	}
+
 	// Parse the event type from the data
 	var eventData map[string]interface{}
 	if err := json.Unmarshal(msg.Data, &eventData); err != nil {
@@ -371,45 +754,90 @@ func (sm *StreamManager) routeStreamMessage(msg *StreamMessage) {
 	if !ok {
 		return
 	}
+	sm.conn.recordEvent(eventType)
 
 	// Route based on event type
+	var err error
 	switch eventType {
 	case "depthUpdate":
 		if sm.depthHandler != nil {
 			var event DepthUpdateEvent
-			if err := json.Unmarshal(msg.Data, &event); err == nil {
-				sm.depthHandler.HandleDepthUpdate(&event)
+			if json.Unmarshal(msg.Data, &event) == nil {
+				err = sm.depthHandler.HandleDepthUpdate(&event)
 			}
 		}
 	case "24hrTicker":
 		if sm.tickerHandler != nil {
 			var event TickerEvent
-			if err := json.Unmarshal(msg.Data, &event); err == nil {
-				sm.tickerHandler.HandleTickerUpdate(&event)
+			if json.Unmarshal(msg.Data, &event) == nil {
+				err = sm.tickerHandler.HandleTickerUpdate(&event)
+			}
+		}
+	case "forceOrder":
+		if sm.liquidations != nil {
+			var event LiquidationEvent
+			if json.Unmarshal(msg.Data, &event) == nil {
+				err = sm.liquidations.HandleLiquidation(&event)
 			}
 		}
 	case "outboundAccountPosition", "outboundAccountInfo":
 		if sm.userHandler != nil {
 			var event AccountUpdateEvent
-			if err := json.Unmarshal(msg.Data, &event); err == nil {
-				sm.userHandler.HandleAccountUpdate(&event)
+			if json.Unmarshal(msg.Data, &event) == nil {
+				err = sm.userHandler.HandleAccountUpdate(&event)
 			}
 		}
 	case "executionReport":
 		if sm.userHandler != nil {
 			var event OrderUpdateEvent
-			if err := json.Unmarshal(msg.Data, &event); err == nil {
-				sm.userHandler.HandleOrderUpdate(&event)
+			if json.Unmarshal(msg.Data, &event) == nil {
+				err = sm.userHandler.HandleOrderUpdate(&event)
+			}
+		}
+	case "ACCOUNT_UPDATE":
+		if handler, ok := sm.userHandler.(FuturesAccountUpdateHandler); ok {
+			var event FuturesAccountUpdateEvent
+			if json.Unmarshal(msg.Data, &event) == nil {
+				err = handler.HandleFuturesAccountUpdate(&event)
+			}
+		}
+	case "ORDER_TRADE_UPDATE":
+		if handler, ok := sm.userHandler.(FuturesOrderUpdateHandler); ok {
+			var event FuturesOrderUpdateEvent
+			if json.Unmarshal(msg.Data, &event) == nil {
+				err = handler.HandleFuturesOrderUpdate(&event)
 			}
 		}
 	case "listenKeyExpired":
 		if sm.userHandler != nil {
-			sm.userHandler.HandleListenKeyExpired()
+			err = sm.userHandler.HandleListenKeyExpired()
 		}
 	default:
 		// Use generic event handler for unknown event types
 		if sm.eventHandler != nil {
-			sm.eventHandler.HandleEvent(eventType, msg.Data)
+			err = sm.eventHandler.HandleEvent(eventType, msg.Data)
+		}
+	}
+
+	if err != nil {
+		sm.conn.reportHandlerError(fmt.Errorf("%s handler for %s: %w", eventType, msg.Stream, err))
+	}
+}
+
+// routeTickerArray delivers a !ticker@arr payload to the all-tickers
+// handler. Callers must hold handlersMu.
+func (sm *StreamManager) routeTickerArray(data []byte) {
+	var events []*TickerEvent
+	if err := json.Unmarshal(data, &events); err != nil {
+		return
+	}
+	for _, event := range events {
+		sm.conn.recordEvent(event.EventType)
+	}
+
+	if sm.allTickers != nil && len(events) > 0 {
+		if err := sm.allTickers.HandleAllTickers(events); err != nil {
+			sm.conn.reportHandlerError(fmt.Errorf("all tickers handler: %w", err))
 		}
 	}
 }
@@ -425,39 +853,186 @@ func (sm *StreamManager) monitorConnectionState() {
 			return
 		case <-ticker.C:
 			currentState := sm.conn.State()
+			generation := sm.conn.Generation()
 
 			sm.stateMu.Lock()
 			lastState := sm.lastState
+			lastGeneration := sm.lastGeneration
+			staleAfter := sm.staleResubscribeAfter
 			sm.lastState = currentState
+			sm.lastGeneration = generation
 			sm.stateMu.Unlock()
 
-			// Check if we've reconnected (transition from non-connected to connected)
-			if lastState != StateConnected && currentState == StateConnected {
-				sm.handleReconnection()
+			// Check if we've reconnected, either by observing the transition or
+			// by a new dial that completed within a single tick
+			if currentState == StateConnected && (lastState != StateConnected || generation != lastGeneration) {
+				sm.handleReconnection(generation)
+			} else if currentState == StateConnected && sm.resubscribedGeneration.Load() < generation {
+				sm.retryResubscribe(generation)
+			} else if currentState == StateConnected && staleAfter > 0 {
+				if err := sm.resubscribeStale(staleAfter); err != nil {
+					sm.conn.logger.Warn().
+						Err(err).
+						Str("url", sm.conn.url).
+						Msg("Stale stream resubscribe failed, will retry")
+				}
 			}
 		}
 	}
 }
 
 // handleReconnection handles automatic resubscription after reconnection
-func (sm *StreamManager) handleReconnection() {
-	sm.subscriptionsMu.RLock()
+func (sm *StreamManager) handleReconnection(generation uint64) {
+	// Snapshot handlers so the user stream binding survives the reconnect even
+	// if resubscription fails or has nothing to replay
+	sm.handlersMu.RLock()
+	userHandler := sm.userHandler
+	reconnectHandler := sm.reconnectHandler
+	sm.handlersMu.RUnlock()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	sm.stateMu.Lock()
+	sm.lastResubscribeAttempt = time.Now()
+	sm.stateMu.Unlock()
+	if err := sm.resubscribe(ctx, generation); err != nil {
+		sm.conn.logger.Warn().
+			Err(err).
+			Str("url", sm.conn.url).
+			Msg("Resubscribe after reconnect failed, will retry")
+	}
+
+	if userHandler != nil {
+		sm.SetUserStreamHandler(userHandler)
+	}
+
+	if reconnectHandler != nil {
+		reconnectHandler()
+	}
+}
+
+// handleFailover replays subscriptions on a promoted standby right away
+// instead of on the monitor's next tick. The monitor still sees the new
+// generation and finishes the reconnection, finding the replay done.
+func (sm *StreamManager) handleFailover() {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	sm.resubscribe(ctx, sm.conn.Generation())
+}
+
+// retryResubscribe replays the subscriptions again after a failed replay on
+// generation, at most once per resubscribeRetryInterval
+func (sm *StreamManager) retryResubscribe(generation uint64) {
+	sm.stateMu.Lock()
+	if time.Since(sm.lastResubscribeAttempt) < resubscribeRetryInterval {
+		sm.stateMu.Unlock()
+		return
+	}
+	sm.lastResubscribeAttempt = time.Now()
+	sm.stateMu.Unlock()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	if err := sm.resubscribe(ctx, generation); err != nil {
+		sm.conn.logger.Warn().
+			Err(err).
+			Str("url", sm.conn.url).
+			Msg("Resubscribe retry failed")
+	}
+}
+
+// resubscribe replays the active subscriptions on connection generation
+// once. Later calls for the same or an older generation wait for the first
+// to finish and then return without sending anything. The generation counts
+// as replayed only when the replay succeeds; on failure the streams stay
+// tracked so the next attempt replays them.
+func (sm *StreamManager) resubscribe(ctx context.Context, generation uint64) error {
+	sm.resubscribeMu.Lock()
+	defer sm.resubscribeMu.Unlock()
+
+	if generation <= sm.resubscribedGeneration.Load() {
+		return nil
+	}
+
+	sm.subscriptionsMu.Lock()
 	activeStreams := make([]string, 0, len(sm.subscriptions))
 	for stream := range sm.subscriptions {
 		activeStreams = append(activeStreams, stream)
 	}
-	sm.subscriptionsMu.RUnlock()
+	// Clear current subscriptions so they are counted again on resubscribe
+	sm.subscriptions = make(map[string]bool)
+	sm.subscriptionsMu.Unlock()
 
 	if len(activeStreams) > 0 {
-		// Clear current subscriptions and resubscribe
-		sm.subscriptionsMu.Lock()
-		sm.subscriptions = make(map[string]bool)
-		sm.subscriptionsMu.Unlock()
+		if err := sm.SubscribeMultiple(ctx, activeStreams); err != nil {
+			sm.restoreStreams(activeStreams)
+			return err
+		}
+	}
+
+	sm.resubscribedGeneration.Store(generation)
+	return nil
+}
 
-		// Resubscribe to all previously active streams
-		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
-		defer cancel()
+// resubscribeStale cycles streams silent for longer than threshold through
+// UNSUBSCRIBE and SUBSCRIBE. Streams that could not be subscribed again stay
+// flagged, so a failed attempt is retried after another full threshold.
+func (sm *StreamManager) resubscribeStale(threshold time.Duration) error {
+	stale := sm.StaleStreams(threshold)
+	if len(stale) == 0 {
+		return nil
+	}
 
-		sm.SubscribeMultiple(ctx, activeStreams)
+	// Restart the clock first so a failed attempt is retried only after
+	// another full threshold
+	sm.subscriptionsMu.RLock()
+	now := time.Now().UnixNano()
+	for _, stream := range stale {
+		if counters := sm.activity[stream]; counters != nil {
+			counters.lastSeen.Store(now)
+		}
+	}
+	sm.subscriptionsMu.RUnlock()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	if err := sm.UnsubscribeMultiple(ctx, stale); err != nil {
+		return fmt.Errorf("failed to unsubscribe stale streams: %w", err)
+	}
+	if err := sm.SubscribeMultiple(ctx, stale); err != nil {
+		sm.restoreStreams(stale)
+		return fmt.Errorf("failed to resubscribe stale streams: %w", err)
+	}
+	return nil
+}
+
+// restoreStreams puts back streams whose resubscribe failed, counting their
+// silence from now so a stale retry waits a full threshold
+func (sm *StreamManager) restoreStreams(streams []string) {
+	sm.subscriptionsMu.Lock()
+	defer sm.subscriptionsMu.Unlock()
+
+	since := time.Now().UnixNano()
+	for _, stream := range streams {
+		if sm.subscriptions[stream] || sm.reserved[stream] {
+			continue
+		}
+		sm.subscriptions[stream] = true
+		sm.activityLocked(stream).lastSeen.Store(since)
+	}
+}
+
+// activityLocked returns the counters for stream, creating them on first use.
+// Callers must hold the subscriptions write lock.
+func (sm *StreamManager) activityLocked(stream string) *streamActivity {
+	counters, ok := sm.activity[stream]
+	if !ok {
+		counters = &streamActivity{}
+		sm.activity[stream] = counters
 	}
+	return counters
 }
@@ -1,14 +1,20 @@
 package websocket
 
 import (
+	"bytes"
 	"context"
 	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http/httptest"
+	"sort"
 	"sync"
 	"sync/atomic"
 	"testing"
 	"time"
 
 	"github.com/gorilla/websocket"
+	"github.com/rs/zerolog"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
@@ -199,6 +205,216 @@ func TestStreamManager_Subscribe(t *testing.T) {
 	})
 }
 
+func TestStreamManager_SubscribeRetry(t *testing.T) {
+	// newServer rejects the first failures SUBSCRIBE frames with code, then
+	// confirms, counting every frame received
+	newServer := func(t *testing.T, failures int, code int, msg string) (*httptest.Server, *atomic.Int32) {
+		var received atomic.Int32
+		server := newMockWebSocketServer(t, func(conn *websocket.Conn) {
+			defer conn.Close()
+			for {
+				var req SubscriptionRequest
+				if err := conn.ReadJSON(&req); err != nil {
+					return
+				}
+				if int(received.Add(1)) <= failures {
+					conn.WriteJSON(map[string]interface{}{
+						"id":    req.ID,
+						"error": map[string]interface{}{"code": code, "msg": msg},
+					})
+					continue
+				}
+				conn.WriteJSON(SubscriptionResponse{ID: req.ID})
+			}
+		})
+		return server, &received
+	}
+
+	subscribe := func(t *testing.T, server *httptest.Server, retries int) (*StreamManager, error) {
+		sm := NewStreamManager(getWebSocketURL(server.URL), WithSubscribeRetry(retries, 10*time.Millisecond))
+		ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+		defer cancel()
+		require.NoError(t, sm.Connect(ctx))
+		t.Cleanup(func() { sm.Close() })
+		return sm, sm.Subscribe(ctx, "btcusdt@depth")
+	}
+
+	t.Run("transient failure succeeds on retry", func(t *testing.T) {
+		server, received := newServer(t, 1, -1001, "Internal error; unable to process your request. Please try again.")
+		defer server.Close()
+
+		sm, err := subscribe(t, server, 2)
+		require.NoError(t, err)
+		assert.Equal(t, int32(2), received.Load())
+		assert.Contains(t, sm.ActiveSubscriptions(), "btcusdt@depth")
+	})
+
+	t.Run("permanent failure is not retried", func(t *testing.T) {
+		server, received := newServer(t, 1, -2011, "Invalid symbol.")
+		defer server.Close()
+
+		sm, err := subscribe(t, server, 2)
+		var subErr *SubscriptionError
+		require.ErrorAs(t, err, &subErr)
+		assert.Equal(t, -2011, subErr.Code)
+		assert.Equal(t, int32(1), received.Load())
+		assert.Empty(t, sm.ActiveSubscriptions())
+	})
+
+	t.Run("gives up after the configured retries", func(t *testing.T) {
+		server, received := newServer(t, 10, -1003, "Too many requests.")
+		defer server.Close()
+
+		_, err := subscribe(t, server, 1)
+		assert.True(t, IsRetryableSubscribeError(err))
+		assert.Equal(t, int32(2), received.Load())
+	})
+
+	t.Run("zero retries disables retrying", func(t *testing.T) {
+		server, received := newServer(t, 1, -1001, "Internal error.")
+		defer server.Close()
+
+		_, err := subscribe(t, server, 0)
+		assert.Error(t, err)
+		assert.Equal(t, int32(1), received.Load())
+	})
+}
+
+func TestIsRetryableSubscribeError(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want bool
+	}{
+		{name: "nil", err: nil, want: false},
+		{name: "send failure", err: fmt.Errorf("%w: %w", errSubscribeSend, errors.New("broken pipe")), want: true},
+		{name: "server busy", err: &SubscriptionError{Op: "subscription", Code: -1001}, want: true},
+		{name: "rate limited", err: &SubscriptionError{Op: "subscription", Code: -1003}, want: true},
+		{name: "invalid symbol", err: &SubscriptionError{Op: "subscription", Code: -2011}, want: false},
+		{name: "invalid request", err: &SubscriptionError{Op: "subscription", Code: 2}, want: false},
+		{name: "stream limit", err: fmt.Errorf("%w: 1025 streams", ErrStreamLimitExceeded), want: false},
+		{name: "not connected", err: errors.New("not connected"), want: false},
+		{name: "cancelled", err: context.Canceled, want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, IsRetryableSubscribeError(tt.err))
+		})
+	}
+}
+
+func TestStreamManager_StreamLimit(t *testing.T) {
+	var requests int32
+	server := newMockWebSocketServer(t, func(conn *websocket.Conn) {
+		defer conn.Close()
+		for {
+			var req SubscriptionRequest
+			if err := conn.ReadJSON(&req); err != nil {
+				return
+			}
+			atomic.AddInt32(&requests, 1)
+			conn.WriteJSON(SubscriptionResponse{ID: req.ID})
+		}
+	})
+	defer server.Close()
+
+	sm := NewStreamManager(getWebSocketURL(server.URL))
+	assert.Equal(t, MaxStreamsPerConnection, sm.MaxStreams())
+	sm.SetMaxStreams(3)
+
+	ctx := context.Background()
+	require.NoError(t, sm.Connect(ctx))
+	defer sm.Close()
+
+	require.NoError(t, sm.SubscribeMultiple(ctx, []string{"btcusdt@depth", "ethusdt@depth"}))
+	assert.Equal(t, 2, sm.StreamCount())
+
+	t.Run("rejects subscribe past the limit without sending", func(t *testing.T) {
+		before := atomic.LoadInt32(&requests)
+
+		err := sm.SubscribeMultiple(ctx, []string{"bnbusdt@depth", "solusdt@depth"})
+		require.Error(t, err)
+		assert.ErrorIs(t, err, ErrStreamLimitExceeded)
+		assert.Contains(t, err.Error(), "limit is 3")
+
+		assert.Equal(t, before, atomic.LoadInt32(&requests))
+		assert.Equal(t, 2, sm.StreamCount())
+		assert.NotContains(t, sm.ActiveSubscriptions(), "bnbusdt@depth")
+	})
+
+	t.Run("already subscribed streams do not count again", func(t *testing.T) {
+		require.NoError(t, sm.SubscribeMultiple(ctx, []string{"btcusdt@depth", "bnbusdt@depth"}))
+		assert.Equal(t, 3, sm.StreamCount())
+	})
+
+	t.Run("capacity frees up after unsubscribe", func(t *testing.T) {
+		assert.ErrorIs(t, sm.Subscribe(ctx, "solusdt@depth"), ErrStreamLimitExceeded)
+
+		require.NoError(t, sm.Unsubscribe(ctx, "ethusdt@depth"))
+		require.NoError(t, sm.Subscribe(ctx, "solusdt@depth"))
+		assert.Equal(t, 3, sm.StreamCount())
+	})
+}
+
+func TestStreamManager_PacesControlMessages(t *testing.T) {
+	var mu sync.Mutex
+	var arrivals []time.Time
+
+	server := newMockWebSocketServer(t, func(conn *websocket.Conn) {
+		defer conn.Close()
+		for {
+			var req SubscriptionRequest
+			if err := conn.ReadJSON(&req); err != nil {
+				return
+			}
+			mu.Lock()
+			arrivals = append(arrivals, time.Now())
+			mu.Unlock()
+			conn.WriteJSON(SubscriptionResponse{ID: req.ID})
+		}
+	})
+	defer server.Close()
+
+	sm := NewStreamManager(getWebSocketURL(server.URL))
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	require.NoError(t, sm.Connect(ctx))
+	defer sm.Close()
+
+	const subscribes = 20
+	var wg sync.WaitGroup
+	errs := make(chan error, subscribes)
+	for i := 0; i < subscribes; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			errs <- sm.Subscribe(ctx, fmt.Sprintf("sym%d@depth", i))
+		}(i)
+	}
+	wg.Wait()
+	close(errs)
+	for err := range errs {
+		require.NoError(t, err)
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	require.Len(t, arrivals, subscribes)
+	sort.Slice(arrivals, func(i, j int) bool { return arrivals[i].Before(arrivals[j]) })
+
+	// No one-second window may see more frames than the pacing allows
+	for i, start := range arrivals {
+		inWindow := 0
+		for _, at := range arrivals[i:] {
+			if at.Sub(start) < time.Second {
+				inWindow++
+			}
+		}
+		assert.LessOrEqual(t, inWindow, ControlMessagesPerSecond, "frames in the second after frame %d", i)
+	}
+}
+
 func TestStreamManager_Unsubscribe(t *testing.T) {
 	t.Run("unsubscribes from stream successfully", func(t *testing.T) {
 		server := newMockWebSocketServer(t, func(conn *websocket.Conn) {
@@ -375,6 +591,311 @@ func TestStreamManager_MessageHandling(t *testing.T) {
 	})
 }
 
+func TestStreamManager_RecoversHandlerPanics(t *testing.T) {
+	server := newMockWebSocketServer(t, func(conn *websocket.Conn) {
+		defer conn.Close()
+
+		messages := []string{
+			`{"stream":"btcusdt@depth","data":{"e":"depthUpdate","s":"PANIC"}}`,
+			`{"stream":"ethusdt@ticker","data":{"e":"24hrTicker","s":"ETHUSDT"}}`,
+			`{"stream":"btcusdt@depth","data":{"e":"depthUpdate","s":"BTCUSDT"}}`,
+		}
+		for _, msg := range messages {
+			conn.WriteMessage(websocket.TextMessage, []byte(msg))
+			time.Sleep(20 * time.Millisecond)
+		}
+		conn.ReadMessage()
+	})
+	defer server.Close()
+
+	var mu sync.Mutex
+	var handlerErrors []error
+	var depthSymbols []string
+
+	recorder := newFakeMetricsRecorder()
+	sm := NewStreamManager(getWebSocketURL(server.URL),
+		WithMetrics(recorder),
+		WithHandlerErrorCallback(func(err error) {
+			mu.Lock()
+			defer mu.Unlock()
+			handlerErrors = append(handlerErrors, err)
+		}))
+	sm.SetDepthHandler(&mockStreamDepthHandler{
+		onDepthUpdate: func(event *DepthUpdateEvent) error {
+			if event.Symbol == "PANIC" {
+				panic("handler bug")
+			}
+			mu.Lock()
+			defer mu.Unlock()
+			depthSymbols = append(depthSymbols, event.Symbol)
+			return nil
+		},
+	})
+	sm.SetTickerHandler(&mockStreamTickerHandler{
+		onTickerUpdate: func(event *TickerEvent) error {
+			return fmt.Errorf("cannot process %s", event.Symbol)
+		},
+	})
+
+	require.NoError(t, sm.Connect(context.Background()))
+	defer sm.Close()
+
+	require.Eventually(t, func() bool {
+		mu.Lock()
+		defer mu.Unlock()
+		return len(depthSymbols) == 1 && len(handlerErrors) == 2
+	}, 2*time.Second, 10*time.Millisecond, "messages after the panic should still be routed")
+
+	mu.Lock()
+	defer mu.Unlock()
+	assert.Equal(t, []string{"BTCUSDT"}, depthSymbols)
+	assert.True(t, errors.Is(handlerErrors[0], ErrHandlerPanic))
+	assert.Contains(t, handlerErrors[0].Error(), "handler bug")
+	assert.EqualError(t, handlerErrors[1], "24hrTicker handler for ethusdt@ticker: cannot process ETHUSDT")
+	assert.Equal(t, 1, recorder.event(MetricHandlerPanic))
+	assert.Equal(t, StateConnected, sm.State(), "a handler panic must not drop the connection")
+}
+
+func TestStreamManager_EventMetrics(t *testing.T) {
+	server := newMockWebSocketServer(t, func(conn *websocket.Conn) {
+		defer conn.Close()
+
+		var req SubscriptionRequest
+		conn.ReadJSON(&req)
+		conn.WriteJSON(SubscriptionResponse{ID: req.ID})
+
+		messages := []string{
+			`{"stream":"btcusdt@depth","data":{"e":"depthUpdate","s":"BTCUSDT"}}`,
+			`{"stream":"btcusdt@depth","data":{"e":"depthUpdate","s":"BTCUSDT"}}`,
+			`{"stream":"btcusdt@ticker","data":{"e":"24hrTicker","s":"BTCUSDT"}}`,
+			`{"stream":"btcusdt@kline_1m","data":{"e":"kline","s":"BTCUSDT"}}`,
+		}
+		for _, msg := range messages {
+			conn.WriteMessage(websocket.TextMessage, []byte(msg))
+		}
+		conn.ReadMessage()
+	})
+	defer server.Close()
+
+	recorder := newFakeMetricsRecorder()
+	sm := NewStreamManager(getWebSocketURL(server.URL), WithMetrics(recorder))
+	ctx := context.Background()
+
+	require.NoError(t, sm.Connect(ctx))
+	defer sm.Close()
+	require.NoError(t, sm.Subscribe(ctx, "btcusdt@depth"))
+
+	require.Eventually(t, func() bool {
+		return recorder.event("depthUpdate") == 2 &&
+			recorder.event("24hrTicker") == 1 &&
+			recorder.event("kline") == 1
+	}, 2*time.Second, 10*time.Millisecond)
+
+	// The subscribe request is the only outbound message
+	assert.Equal(t, 1, recorder.event(MetricMessageSent))
+	assert.Equal(t, 1, recorder.connection(MetricConnected))
+}
+
+type allTickersFunc func([]*TickerEvent) error
+
+func (f allTickersFunc) HandleAllTickers(events []*TickerEvent) error { return f(events) }
+
+func TestStreamManager_RoutesTickerArray(t *testing.T) {
+	recorder := newFakeMetricsRecorder()
+	sm := NewStreamManager("ws://unused", WithMetrics(recorder))
+
+	var received []*TickerEvent
+	sm.SetAllTickersHandler(allTickersFunc(func(events []*TickerEvent) error {
+		received = events
+		return nil
+	}))
+	tickerCalls := 0
+	sm.SetTickerHandler(&mockStreamTickerHandler{onTickerUpdate: func(*TickerEvent) error {
+		tickerCalls++
+		return nil
+	}})
+
+	sm.handleMessage([]byte(`{"stream":"!ticker@arr","data":[
+		{"e":"24hrTicker","s":"BTCUSDT","c":"50000.01"},
+		{"e":"24hrTicker","s":"ETHUSDT","c":"3000.5"},
+		{"e":"24hrTicker","s":"SHIBUSDT","c":"0.000012340000"}
+	]}`))
+
+	require.Len(t, received, 3)
+	assert.Equal(t, "BTCUSDT", received[0].Symbol)
+	assert.Equal(t, "ETHUSDT", received[1].Symbol)
+	assert.Equal(t, "0.00001234", received[2].LastPrice.String())
+	assert.Zero(t, tickerCalls, "array entries must not reach the per-symbol ticker handler")
+	assert.Equal(t, 3, recorder.event("24hrTicker"))
+
+	// Malformed arrays are dropped
+	received = nil
+	sm.handleMessage([]byte(`{"stream":"!ticker@arr","data":[{"e":"24hrTicker","c":"not-a-number"}]}`))
+	assert.Nil(t, received)
+}
+
+func TestStreamManager_RoutesFuturesOrderUpdate(t *testing.T) {
+	sm := NewStreamManager("ws://unused")
+
+	var spot []*OrderUpdateEvent
+	var futures []*FuturesOrderUpdateEvent
+	sm.SetUserStreamHandler(&UserDataHandler{
+		OnOrderUpdate: func(event *OrderUpdateEvent) error {
+			spot = append(spot, event)
+			return nil
+		},
+		OnFuturesOrderUpdate: func(event *FuturesOrderUpdateEvent) error {
+			futures = append(futures, event)
+			return nil
+		},
+	})
+
+	sm.handleMessage([]byte(`{"stream":"listenkey","data":{"e":"ORDER_TRADE_UPDATE","E":1,"T":1,
+		"o":{"s":"BTCUSDT","c":"bracket-1-SL","X":"FILLED","ap":"49000","rp":"-1.5","n":"0.02","N":"USDT"}}}`))
+
+	require.Len(t, futures, 1)
+	assert.Empty(t, spot)
+	assert.Equal(t, "bracket-1-SL", futures[0].ClientOrderID)
+	assert.Equal(t, "49000", futures[0].AveragePrice.String())
+	assert.Equal(t, "-1.5", futures[0].RealizedProfit.String())
+
+	// Handlers without futures support ignore the event
+	sm.SetUserStreamHandler(&mockUserStreamHandler{})
+	assert.NotPanics(t, func() {
+		sm.handleMessage([]byte(`{"stream":"listenkey","data":{"e":"ORDER_TRADE_UPDATE","o":{"s":"BTCUSDT"}}}`))
+	})
+}
+
+func TestStreamManager_RoutesRawUserEvent(t *testing.T) {
+	sm := NewStreamManager("ws://unused")
+
+	var updates []*OrderUpdateEvent
+	sm.SetUserStreamHandler(&UserDataHandler{
+		OnOrderUpdate: func(event *OrderUpdateEvent) error {
+			updates = append(updates, event)
+			return nil
+		},
+	})
+
+	// /ws/<listenKey> delivers events without the combined-stream wrapper
+	sm.handleMessage([]byte(`{"e":"executionReport","E":1,"s":"BTCUSDT","c":"bracket-1-SL","X":"FILLED"}`))
+
+	require.Len(t, updates, 1)
+	assert.Equal(t, "bracket-1-SL", updates[0].ClientOrderID)
+}
+
+func TestStreamManager_RoutesFuturesAccountUpdate(t *testing.T) {
+	sm := NewStreamManager("ws://unused")
+
+	var spot []*AccountUpdateEvent
+	var futures []*FuturesAccountUpdateEvent
+	sm.SetUserStreamHandler(&UserDataHandler{
+		OnAccountUpdate: func(event *AccountUpdateEvent) error {
+			spot = append(spot, event)
+			return nil
+		},
+		OnFuturesAccountUpdate: func(event *FuturesAccountUpdateEvent) error {
+			futures = append(futures, event)
+			return nil
+		},
+	})
+
+	sm.handleMessage([]byte(`{"stream":"listenkey","data":{"e":"ACCOUNT_UPDATE","E":1,"T":1,
+		"a":{"m":"ORDER","B":[{"a":"USDT","wb":"1000","cw":"1000","bc":"0"}],
+		"P":[{"s":"BTCUSDT","pa":"0.01","ep":"50000","ps":"BOTH"}]}}}`))
+
+	require.Len(t, futures, 1)
+	assert.Empty(t, spot)
+	require.Len(t, futures[0].Balances, 1)
+	require.Len(t, futures[0].Positions, 1)
+	assert.Equal(t, "0.01", futures[0].Positions[0].PositionAmount.String())
+
+	sm.SetUserStreamHandler(&mockUserStreamHandler{})
+	assert.NotPanics(t, func() {
+		sm.handleMessage([]byte(`{"stream":"listenkey","data":{"e":"ACCOUNT_UPDATE","a":{}}}`))
+	})
+}
+
+func TestStreamManager_SubscriptionResponseMatching(t *testing.T) {
+	newManager := func(pending map[int][]string) (*StreamManager, map[int]chan SubscriptionResponse, *bytes.Buffer) {
+		var logs bytes.Buffer
+		sm := NewStreamManager("ws://unused", WithLogger(zerolog.New(&logs)))
+		channels := make(map[int]chan SubscriptionResponse)
+		for id, params := range pending {
+			ch := make(chan SubscriptionResponse, 1)
+			channels[id] = ch
+			sm.pendingRequests[id] = &pendingRequest{response: ch, params: params}
+		}
+		return sm, channels, &logs
+	}
+
+	t.Run("id 0 response answers the only pending request", func(t *testing.T) {
+		sm, channels, logs := newManager(map[int][]string{7: {"btcusdt@depth"}})
+		sm.handleMessage([]byte(`{"result":null,"id":0}`))
+
+		require.Len(t, channels[7], 1)
+		assert.Empty(t, logs.String())
+	})
+
+	t.Run("id 0 response is matched by echoed params", func(t *testing.T) {
+		sm, channels, _ := newManager(map[int][]string{
+			3: {"btcusdt@depth"},
+			4: {"ethusdt@ticker"},
+		})
+
+		sm.handleMessage([]byte(`{"result":null,"id":0,"params":["ethusdt@ticker"]}`))
+
+		assert.Empty(t, channels[3])
+		assert.Len(t, channels[4], 1)
+	})
+
+	t.Run("ambiguous id 0 response is dropped", func(t *testing.T) {
+		sm, channels, logs := newManager(map[int][]string{
+			3: {"btcusdt@depth"},
+			4: {"ethusdt@ticker"},
+		})
+
+		sm.handleMessage([]byte(`{"result":null,"id":null}`))
+
+		assert.Empty(t, channels[3])
+		assert.Empty(t, channels[4])
+		assert.Contains(t, logs.String(), "matches no pending request")
+	})
+
+	t.Run("response with an unknown id is dropped", func(t *testing.T) {
+		sm, channels, logs := newManager(map[int][]string{5: {"btcusdt@depth"}})
+		sm.handleMessage([]byte(`{"result":null,"id":42}`))
+
+		assert.Empty(t, channels[5], "an unknown id is not guessed at")
+		assert.Contains(t, logs.String(), `"id":42`)
+		assert.Contains(t, logs.String(), "matches no pending request")
+	})
+
+	t.Run("subscribe completes on an id 0 confirmation", func(t *testing.T) {
+		server := newMockWebSocketServer(t, func(conn *websocket.Conn) {
+			defer conn.Close()
+			for {
+				var req SubscriptionRequest
+				if err := conn.ReadJSON(&req); err != nil {
+					return
+				}
+				conn.WriteMessage(websocket.TextMessage, []byte(`{"result":null,"id":0}`))
+			}
+		})
+		defer server.Close()
+
+		sm := NewStreamManager(getWebSocketURL(server.URL))
+		ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+		defer cancel()
+
+		require.NoError(t, sm.Connect(ctx))
+		defer sm.Close()
+
+		require.NoError(t, sm.Subscribe(ctx, "btcusdt@depth"))
+		assert.Contains(t, sm.ActiveSubscriptions(), "btcusdt@depth")
+	})
+}
+
 func TestStreamManager_Reconnection(t *testing.T) {
 	t.Run("resubscribes to active streams after reconnection", func(t *testing.T) {
 		connectionCount := 0
@@ -437,19 +958,348 @@ func TestStreamManager_Reconnection(t *testing.T) {
 		err = sm.Subscribe(ctx, "btcusdt@depth")
 		require.NoError(t, err)
 
-		// Wait for reconnection and resubscription
-		time.Sleep(300 * time.Millisecond)
+		// Wait for reconnection and resubscription, which waits for a
+		// control message slot after the original subscribe
+		assert.Eventually(t, func() bool {
+			mu.Lock()
+			resubscribed := subscriptionCount >= 2
+			mu.Unlock()
+			return resubscribed && len(sm.ActiveSubscriptions()) == 1
+		}, time.Second, 10*time.Millisecond, "Should have resubscribed")
 
 		mu.Lock()
 		defer mu.Unlock()
 
 		assert.GreaterOrEqual(t, connectionCount, 2, "Should have reconnected")
-		assert.GreaterOrEqual(t, subscriptionCount, 2, "Should have resubscribed")
 
 		// Verify subscription is still active
 		subscriptions := sm.ActiveSubscriptions()
 		assert.Contains(t, subscriptions, "btcusdt@depth")
 	})
+
+	t.Run("resubscribes once when connect and monitor both fire", func(t *testing.T) {
+		var mu sync.Mutex
+		connections := 0
+		subscribes := map[int]int{} // connection -> SUBSCRIBE frames
+
+		server := newMockWebSocketServer(t, func(conn *websocket.Conn) {
+			defer conn.Close()
+
+			mu.Lock()
+			connections++
+			current := connections
+			mu.Unlock()
+
+			for {
+				var req SubscriptionRequest
+				if err := conn.ReadJSON(&req); err != nil {
+					return
+				}
+				mu.Lock()
+				subscribes[current]++
+				mu.Unlock()
+				conn.WriteJSON(SubscriptionResponse{ID: req.ID})
+
+				if current == 1 {
+					return // drop the first connection once subscribed
+				}
+			}
+		})
+		defer server.Close()
+
+		sm := NewStreamManager(getWebSocketURL(server.URL))
+
+		ctx := context.Background()
+		require.NoError(t, sm.Connect(ctx))
+		defer sm.Close()
+		require.NoError(t, sm.Subscribe(ctx, "btcusdt@depth"))
+
+		require.Eventually(t, func() bool {
+			return sm.State() != StateConnected
+		}, time.Second, 10*time.Millisecond)
+
+		// Reconnect by hand; the still-running monitor sees the new
+		// generation and takes the reconnection path as well
+		require.NoError(t, sm.Connect(ctx))
+		sm.handleReconnection(sm.conn.Generation())
+		time.Sleep(300 * time.Millisecond)
+
+		mu.Lock()
+		defer mu.Unlock()
+		assert.Equal(t, 2, connections)
+		assert.Equal(t, 1, subscribes[2], "streams must be resubscribed exactly once")
+		assert.Equal(t, 1, sm.StreamCount())
+		assert.Contains(t, sm.ActiveSubscriptions(), "btcusdt@depth")
+	})
+
+	t.Run("retries a replay that failed", func(t *testing.T) {
+		var mu sync.Mutex
+		connections := 0
+		subscribes := map[int]int{} // connection -> SUBSCRIBE frames
+
+		server := newMockWebSocketServer(t, func(conn *websocket.Conn) {
+			defer conn.Close()
+
+			mu.Lock()
+			connections++
+			current := connections
+			mu.Unlock()
+
+			for {
+				var req SubscriptionRequest
+				if err := conn.ReadJSON(&req); err != nil {
+					return
+				}
+				mu.Lock()
+				subscribes[current]++
+				attempt := subscribes[current]
+				mu.Unlock()
+
+				resp := SubscriptionResponse{ID: req.ID}
+				if current == 2 && attempt == 1 {
+					// Refuse the first replay on the new connection
+					resp.Error = &struct {
+						Code int    `json:"code"`
+						Msg  string `json:"msg"`
+					}{Code: 2, Msg: "Invalid request"}
+				}
+				conn.WriteJSON(resp)
+
+				if current == 1 {
+					return // drop the first connection once subscribed
+				}
+			}
+		})
+		defer server.Close()
+
+		sm := NewStreamManager(getWebSocketURL(server.URL))
+
+		ctx := context.Background()
+		require.NoError(t, sm.Connect(ctx))
+		defer sm.Close()
+		require.NoError(t, sm.Subscribe(ctx, "btcusdt@depth"))
+
+		require.Eventually(t, func() bool {
+			return sm.State() != StateConnected
+		}, time.Second, 10*time.Millisecond)
+
+		require.Error(t, sm.Connect(ctx))
+		assert.Contains(t, sm.ActiveSubscriptions(), "btcusdt@depth", "streams stay tracked after a failed replay")
+
+		require.Eventually(t, func() bool {
+			mu.Lock()
+			defer mu.Unlock()
+			return subscribes[2] == 2
+		}, 3*time.Second, 20*time.Millisecond, "monitor retries the replay")
+		assert.Eventually(t, func() bool {
+			return sm.resubscribedGeneration.Load() == sm.conn.Generation()
+		}, time.Second, 10*time.Millisecond)
+		assert.Equal(t, []string{"btcusdt@depth"}, sm.ActiveSubscriptions())
+	})
+}
+
+func TestStreamManager_StandbyFailover(t *testing.T) {
+	var mu sync.Mutex
+	var conns []*websocket.Conn
+	subscribes := map[int][]string{} // connection -> streams subscribed on it
+
+	server := newMockWebSocketServer(t, func(conn *websocket.Conn) {
+		defer conn.Close()
+		mu.Lock()
+		conns = append(conns, conn)
+		current := len(conns)
+		mu.Unlock()
+
+		for {
+			var req SubscriptionRequest
+			if err := conn.ReadJSON(&req); err != nil {
+				return
+			}
+			mu.Lock()
+			subscribes[current] = append(subscribes[current], req.Params...)
+			mu.Unlock()
+			conn.WriteJSON(SubscriptionResponse{ID: req.ID})
+		}
+	})
+	defer server.Close()
+
+	sm := NewStreamManager(getWebSocketURL(server.URL),
+		WithStandbyConnection(true),
+		WithReconnectInterval(50*time.Millisecond))
+	sm.SetControlRate(100, 10)
+
+	ctx := context.Background()
+	require.NoError(t, sm.Connect(ctx))
+	defer sm.Close()
+
+	streams := []string{"btcusdt@depth", "ethusdt@ticker", "bnbusdt@depth"}
+	require.NoError(t, sm.SubscribeMultiple(ctx, streams))
+	require.Eventually(t, sm.conn.HasStandby, time.Second, 5*time.Millisecond)
+
+	mu.Lock()
+	primary := conns[0]
+	assert.Empty(t, subscribes[2], "the standby stays idle until promoted")
+	mu.Unlock()
+
+	killed := time.Now()
+	primary.Close()
+
+	require.Eventually(t, func() bool {
+		mu.Lock()
+		replayed := len(subscribes[2])
+		mu.Unlock()
+		return replayed == len(streams) && len(sm.ActiveSubscriptions()) == len(streams)
+	}, time.Second, time.Millisecond, "subscriptions should be replayed on the standby")
+	assert.Less(t, time.Since(killed), 200*time.Millisecond)
+
+	mu.Lock()
+	assert.ElementsMatch(t, streams, subscribes[2])
+	mu.Unlock()
+	assert.ElementsMatch(t, streams, sm.ActiveSubscriptions())
+	assert.Equal(t, StateConnected, sm.State())
+
+	// The monitor's pass over the new generation must not replay again
+	time.Sleep(250 * time.Millisecond)
+	mu.Lock()
+	defer mu.Unlock()
+	assert.Len(t, subscribes[2], len(streams))
+}
+
+func TestStreamManager_StaleStreams(t *testing.T) {
+	// newFeedServer confirms every request that reject does not refuse and
+	// streams depth updates for btcusdt@depth only, recording the requests it
+	// sees
+	newFeedServer := func(t *testing.T, requests *[]SubscriptionRequest, mu *sync.Mutex, reject func(n int, req SubscriptionRequest) bool) string {
+		server := newMockWebSocketServer(t, func(conn *websocket.Conn) {
+			defer conn.Close()
+
+			var writeMu sync.Mutex
+			done := make(chan struct{})
+			go func() {
+				ticker := time.NewTicker(20 * time.Millisecond)
+				defer ticker.Stop()
+				for {
+					select {
+					case <-done:
+						return
+					case <-ticker.C:
+						writeMu.Lock()
+						err := conn.WriteJSON(StreamMessage{
+							Stream: "btcusdt@depth",
+							Data:   json.RawMessage(`{"e":"depthUpdate","s":"BTCUSDT"}`),
+						})
+						writeMu.Unlock()
+						if err != nil {
+							return
+						}
+					}
+				}
+			}()
+			defer close(done)
+
+			for {
+				var req SubscriptionRequest
+				if err := conn.ReadJSON(&req); err != nil {
+					return
+				}
+				mu.Lock()
+				*requests = append(*requests, req)
+				refuse := reject != nil && reject(len(*requests), req)
+				mu.Unlock()
+
+				resp := SubscriptionResponse{ID: req.ID}
+				if refuse {
+					resp.Error = &struct {
+						Code int    `json:"code"`
+						Msg  string `json:"msg"`
+					}{Code: 2, Msg: "Invalid request"}
+				}
+				writeMu.Lock()
+				conn.WriteJSON(resp)
+				writeMu.Unlock()
+			}
+		})
+		t.Cleanup(server.Close)
+		return getWebSocketURL(server.URL)
+	}
+
+	t.Run("reports only the idle stream", func(t *testing.T) {
+		var mu sync.Mutex
+		var requests []SubscriptionRequest
+		sm := NewStreamManager(newFeedServer(t, &requests, &mu, nil))
+
+		ctx := context.Background()
+		require.NoError(t, sm.Connect(ctx))
+		defer sm.Close()
+		require.NoError(t, sm.SubscribeMultiple(ctx, []string{"btcusdt@depth", "ethusdt@depth"}))
+
+		assert.Empty(t, sm.StaleStreams(150*time.Millisecond), "new subscriptions start fresh")
+
+		time.Sleep(250 * time.Millisecond)
+		assert.Equal(t, []string{"ethusdt@depth"}, sm.StaleStreams(150*time.Millisecond))
+
+		require.NoError(t, sm.Unsubscribe(ctx, "ethusdt@depth"))
+		assert.Empty(t, sm.StaleStreams(150*time.Millisecond))
+	})
+
+	t.Run("resubscribes streams stale past the threshold", func(t *testing.T) {
+		var mu sync.Mutex
+		var requests []SubscriptionRequest
+		sm := NewStreamManager(newFeedServer(t, &requests, &mu, nil))
+		sm.SetStaleStreamResubscribe(150 * time.Millisecond)
+
+		ctx := context.Background()
+		require.NoError(t, sm.Connect(ctx))
+		defer sm.Close()
+		require.NoError(t, sm.SubscribeMultiple(ctx, []string{"btcusdt@depth", "ethusdt@depth"}))
+
+		require.Eventually(t, func() bool {
+			mu.Lock()
+			defer mu.Unlock()
+			return len(requests) >= 3
+		}, 2*time.Second, 20*time.Millisecond)
+
+		mu.Lock()
+		defer mu.Unlock()
+		assert.Equal(t, "UNSUBSCRIBE", requests[1].Method)
+		assert.Equal(t, []string{"ethusdt@depth"}, requests[1].Params)
+		assert.Equal(t, "SUBSCRIBE", requests[2].Method)
+		assert.Equal(t, []string{"ethusdt@depth"}, requests[2].Params)
+		assert.ElementsMatch(t, []string{"btcusdt@depth", "ethusdt@depth"}, sm.ActiveSubscriptions())
+	})
+
+	t.Run("retries a stream whose resubscribe failed", func(t *testing.T) {
+		var mu sync.Mutex
+		var requests []SubscriptionRequest
+		// The first stale resubscribe is refused
+		sm := NewStreamManager(newFeedServer(t, &requests, &mu, func(n int, req SubscriptionRequest) bool {
+			return n == 3 && req.Method == "SUBSCRIBE"
+		}))
+
+		ctx := context.Background()
+		require.NoError(t, sm.Connect(ctx))
+		defer sm.Close()
+		require.NoError(t, sm.SubscribeMultiple(ctx, []string{"btcusdt@depth", "ethusdt@depth"}))
+
+		time.Sleep(200 * time.Millisecond)
+		err := sm.resubscribeStale(150 * time.Millisecond)
+		var subErr *SubscriptionError
+		require.ErrorAs(t, err, &subErr)
+		assert.Equal(t, 2, subErr.Code)
+
+		// Still tracked, and stale again once another threshold passes
+		assert.ElementsMatch(t, []string{"btcusdt@depth", "ethusdt@depth"}, sm.ActiveSubscriptions())
+		assert.Empty(t, sm.StaleStreams(150*time.Millisecond))
+		time.Sleep(200 * time.Millisecond)
+		assert.Equal(t, []string{"ethusdt@depth"}, sm.StaleStreams(150*time.Millisecond))
+
+		require.NoError(t, sm.resubscribeStale(150*time.Millisecond))
+		mu.Lock()
+		defer mu.Unlock()
+		require.Len(t, requests, 5)
+		assert.Equal(t, "SUBSCRIBE", requests[4].Method)
+		assert.Equal(t, []string{"ethusdt@depth"}, requests[4].Params)
+	})
 }
 
 func TestStreamManager_Close(t *testing.T) {
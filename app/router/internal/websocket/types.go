@@ -27,6 +27,9 @@ type SubscriptionResponse struct {
 		Code int    `json:"code"`
 		Msg  string `json:"msg"`
 	} `json:"error,omitempty"`
+	// Params echoes the request's streams. Binance omits it, but proxies in
+	// front of it may set it, which helps match responses without an id.
+	Params []string `json:"params,omitempty"`
 }
 
 // EventHandler interface for handling different types of real-time events
@@ -44,6 +47,16 @@ type TickerHandler interface {
 	HandleTickerUpdate(event *TickerEvent) error
 }
 
+// AllTickersHandler handles the market-wide ticker array from !ticker@arr
+type AllTickersHandler interface {
+	HandleAllTickers(events []*TickerEvent) error
+}
+
+// LiquidationHandler handles futures forced-liquidation orders
+type LiquidationHandler interface {
+	HandleLiquidation(event *LiquidationEvent) error
+}
+
 // UserStreamHandler handles private user data events
 type UserStreamHandler interface {
 	HandleAccountUpdate(event *AccountUpdateEvent) error
@@ -51,6 +64,20 @@ type UserStreamHandler interface {
 	HandleListenKeyExpired() error
 }
 
+// FuturesAccountUpdateHandler handles futures ACCOUNT_UPDATE events. Like
+// FuturesOrderUpdateHandler, it is an optional extension of
+// UserStreamHandler.
+type FuturesAccountUpdateHandler interface {
+	HandleFuturesAccountUpdate(event *FuturesAccountUpdateEvent) error
+}
+
+// FuturesOrderUpdateHandler handles futures ORDER_TRADE_UPDATE events. A
+// UserStreamHandler that also implements it receives futures order updates;
+// otherwise they are dropped.
+type FuturesOrderUpdateHandler interface {
+	HandleFuturesOrderUpdate(event *FuturesOrderUpdateEvent) error
+}
+
 // DepthUpdateEvent represents order book depth changes
 type DepthUpdateEvent struct {
 	EventType     string       `json:"e"`
@@ -60,6 +87,10 @@ type DepthUpdateEvent struct {
 	FinalUpdateID int64        `json:"u"`
 	Bids          []PriceLevel `json:"b"`
 	Asks          []PriceLevel `json:"a"`
+
+	// PrevFinalUpdateID is the previous event's FinalUpdateID, sent on
+	// futures streams only
+	PrevFinalUpdateID int64 `json:"pu,omitempty"`
 }
 
 // PriceLevel represents a price level in order book
@@ -124,6 +155,62 @@ type TickerEvent struct {
 	Count              int64           `json:"n"`
 }
 
+// LiquidationEvent is a futures forced-liquidation order from a forceOrder
+// event. Binance nests the order under "o"; it is flattened here.
+type LiquidationEvent struct {
+	EventType      string
+	EventTime      int64
+	Symbol         string
+	Side           string
+	OrderType      string
+	TimeInForce    string
+	Quantity       decimal.Decimal
+	Price          decimal.Decimal
+	AveragePrice   decimal.Decimal
+	Status         string
+	FilledQuantity decimal.Decimal
+	Time           int64 // trade time of the liquidation order
+}
+
+// UnmarshalJSON flattens the nested order of a forceOrder event
+func (e *LiquidationEvent) UnmarshalJSON(data []byte) error {
+	var raw struct {
+		EventType string `json:"e"`
+		EventTime int64  `json:"E"`
+		Order     struct {
+			Symbol         string          `json:"s"`
+			Side           string          `json:"S"`
+			OrderType      string          `json:"o"`
+			TimeInForce    string          `json:"f"`
+			Quantity       decimal.Decimal `json:"q"`
+			Price          decimal.Decimal `json:"p"`
+			AveragePrice   decimal.Decimal `json:"ap"`
+			Status         string          `json:"X"`
+			FilledQuantity decimal.Decimal `json:"z"`
+			Time           int64           `json:"T"`
+		} `json:"o"`
+	}
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+
+	*e = LiquidationEvent{
+		EventType:      raw.EventType,
+		EventTime:      raw.EventTime,
+		Symbol:         raw.Order.Symbol,
+		Side:           raw.Order.Side,
+		OrderType:      raw.Order.OrderType,
+		TimeInForce:    raw.Order.TimeInForce,
+		Quantity:       raw.Order.Quantity,
+		Price:          raw.Order.Price,
+		AveragePrice:   raw.Order.AveragePrice,
+		Status:         raw.Order.Status,
+		FilledQuantity: raw.Order.FilledQuantity,
+		Time:           raw.Order.Time,
+	}
+	return nil
+}
+
 // AccountUpdateEvent represents account balance changes
 type AccountUpdateEvent struct {
 	EventType  string    `json:"e"`
@@ -167,6 +254,173 @@ type OrderUpdateEvent struct {
 	TradeID              int64           `json:"t"`
 	IsOrderWorking       bool            `json:"w"`
 	IsMaker              bool            `json:"m"`
+	CumulativeQuoteQty   decimal.Decimal `json:"Z"`
+}
+
+// FuturesAccountUpdateEvent is a futures balance and position change from an
+// ACCOUNT_UPDATE event. Binance nests both under "a"; only the assets and
+// positions that changed are included.
+type FuturesAccountUpdateEvent struct {
+	EventType       string
+	EventTime       int64
+	TransactionTime int64
+	Reason          string // ORDER, FUNDING_FEE, DEPOSIT, MARGIN_TRANSFER, ...
+	Balances        []FuturesBalanceUpdate
+	Positions       []FuturesPositionUpdate
+}
+
+// FuturesBalanceUpdate is one asset's balance in an ACCOUNT_UPDATE event
+type FuturesBalanceUpdate struct {
+	Asset              string          `json:"a"`
+	WalletBalance      decimal.Decimal `json:"wb"`
+	CrossWalletBalance decimal.Decimal `json:"cw"`
+	BalanceChange      decimal.Decimal `json:"bc"` // excluding PnL and commission
+}
+
+// FuturesPositionUpdate is one position in an ACCOUNT_UPDATE event
+type FuturesPositionUpdate struct {
+	Symbol              string          `json:"s"`
+	PositionAmount      decimal.Decimal `json:"pa"`
+	EntryPrice          decimal.Decimal `json:"ep"`
+	BreakEvenPrice      decimal.Decimal `json:"bep"`
+	AccumulatedRealized decimal.Decimal `json:"cr"`
+	UnrealizedProfit    decimal.Decimal `json:"up"`
+	MarginType          string          `json:"mt"`
+	IsolatedWallet      decimal.Decimal `json:"iw"`
+	PositionSide        string          `json:"ps"`
+}
+
+// UnmarshalJSON flattens the nested update of an ACCOUNT_UPDATE event
+func (e *FuturesAccountUpdateEvent) UnmarshalJSON(data []byte) error {
+	var raw struct {
+		EventType       string `json:"e"`
+		EventTime       int64  `json:"E"`
+		TransactionTime int64  `json:"T"`
+		Account         struct {
+			Reason    string                  `json:"m"`
+			Balances  []FuturesBalanceUpdate  `json:"B"`
+			Positions []FuturesPositionUpdate `json:"P"`
+		} `json:"a"`
+	}
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+
+	*e = FuturesAccountUpdateEvent{
+		EventType:       raw.EventType,
+		EventTime:       raw.EventTime,
+		TransactionTime: raw.TransactionTime,
+		Reason:          raw.Account.Reason,
+		Balances:        raw.Account.Balances,
+		Positions:       raw.Account.Positions,
+	}
+	return nil
+}
+
+// FuturesOrderUpdateEvent is a futures order change from an
+// ORDER_TRADE_UPDATE event. Binance nests the order under "o" with field
+// names that differ from the spot executionReport; it is flattened here.
+type FuturesOrderUpdateEvent struct {
+	EventType            string
+	EventTime            int64
+	TransactionTime      int64
+	Symbol               string
+	ClientOrderID        string
+	Side                 string
+	OrderType            string
+	OriginalOrderType    string
+	TimeInForce          string
+	Quantity             decimal.Decimal
+	Price                decimal.Decimal
+	AveragePrice         decimal.Decimal
+	StopPrice            decimal.Decimal
+	ExecutionType        string
+	OrderStatus          string
+	OrderID              int64
+	LastExecutedQuantity decimal.Decimal
+	CumulativeFilledQty  decimal.Decimal
+	LastExecutedPrice    decimal.Decimal
+	CommissionAsset      string
+	CommissionAmount     decimal.Decimal
+	TradeTime            int64
+	TradeID              int64
+	IsMaker              bool
+	ReduceOnly           bool
+	PositionSide         string
+	WorkingType          string
+	RealizedProfit       decimal.Decimal
+}
+
+// UnmarshalJSON flattens the nested order of an ORDER_TRADE_UPDATE event
+func (e *FuturesOrderUpdateEvent) UnmarshalJSON(data []byte) error {
+	var raw struct {
+		EventType       string `json:"e"`
+		EventTime       int64  `json:"E"`
+		TransactionTime int64  `json:"T"`
+		Order           struct {
+			Symbol               string          `json:"s"`
+			ClientOrderID        string          `json:"c"`
+			Side                 string          `json:"S"`
+			OrderType            string          `json:"o"`
+			OriginalOrderType    string          `json:"ot"`
+			TimeInForce          string          `json:"f"`
+			Quantity             decimal.Decimal `json:"q"`
+			Price                decimal.Decimal `json:"p"`
+			AveragePrice         decimal.Decimal `json:"ap"`
+			StopPrice            decimal.Decimal `json:"sp"`
+			ExecutionType        string          `json:"x"`
+			OrderStatus          string          `json:"X"`
+			OrderID              int64           `json:"i"`
+			LastExecutedQuantity decimal.Decimal `json:"l"`
+			CumulativeFilledQty  decimal.Decimal `json:"z"`
+			LastExecutedPrice    decimal.Decimal `json:"L"`
+			CommissionAsset      string          `json:"N"`
+			CommissionAmount     decimal.Decimal `json:"n"`
+			TradeTime            int64           `json:"T"`
+			TradeID              int64           `json:"t"`
+			IsMaker              bool            `json:"m"`
+			ReduceOnly           bool            `json:"R"`
+			PositionSide         string          `json:"ps"`
+			WorkingType          string          `json:"wt"`
+			RealizedProfit       decimal.Decimal `json:"rp"`
+		} `json:"o"`
+	}
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+
+	o := raw.Order
+	*e = FuturesOrderUpdateEvent{
+		EventType:            raw.EventType,
+		EventTime:            raw.EventTime,
+		TransactionTime:      raw.TransactionTime,
+		Symbol:               o.Symbol,
+		ClientOrderID:        o.ClientOrderID,
+		Side:                 o.Side,
+		OrderType:            o.OrderType,
+		OriginalOrderType:    o.OriginalOrderType,
+		TimeInForce:          o.TimeInForce,
+		Quantity:             o.Quantity,
+		Price:                o.Price,
+		AveragePrice:         o.AveragePrice,
+		StopPrice:            o.StopPrice,
+		ExecutionType:        o.ExecutionType,
+		OrderStatus:          o.OrderStatus,
+		OrderID:              o.OrderID,
+		LastExecutedQuantity: o.LastExecutedQuantity,
+		CumulativeFilledQty:  o.CumulativeFilledQty,
+		LastExecutedPrice:    o.LastExecutedPrice,
+		CommissionAsset:      o.CommissionAsset,
+		CommissionAmount:     o.CommissionAmount,
+		TradeTime:            o.TradeTime,
+		TradeID:              o.TradeID,
+		IsMaker:              o.IsMaker,
+		ReduceOnly:           o.ReduceOnly,
+		PositionSide:         o.PositionSide,
+		WorkingType:          o.WorkingType,
+		RealizedProfit:       o.RealizedProfit,
+	}
+	return nil
 }
 
 // ConnectionState represents WebSocket connection status
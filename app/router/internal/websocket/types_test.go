@@ -6,6 +6,7 @@ import (
 
 	"github.com/shopspring/decimal"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestStreamMessage(t *testing.T) {
@@ -360,7 +361,8 @@ func TestOrderUpdateEvent(t *testing.T) {
 			"T": 1499404630606,
 			"t": -1,
 			"w": true,
-			"m": false
+			"m": false,
+			"Z": "0.00000000"
 		}`
 
 		var event OrderUpdateEvent
@@ -384,6 +386,127 @@ func TestOrderUpdateEvent(t *testing.T) {
 	})
 }
 
+func TestFuturesAccountUpdateEvent(t *testing.T) {
+	jsonData := `{
+		"e": "ACCOUNT_UPDATE",
+		"E": 1564745798939,
+		"T": 1564745798938,
+		"a": {
+			"m": "ORDER",
+			"B": [
+				{"a": "USDT", "wb": "122624.12345678", "cw": "100.12345678", "bc": "50.12345678"},
+				{"a": "BUSD", "wb": "1.00000000", "cw": "0.00000000", "bc": "-49.12345678"}
+			],
+			"P": [
+				{
+					"s": "BTCUSDT",
+					"pa": "-0.002",
+					"ep": "51000.0",
+					"bep": "51010.2",
+					"cr": "200",
+					"up": "0.16",
+					"mt": "isolated",
+					"iw": "20.5",
+					"ps": "SHORT"
+				}
+			]
+		}
+	}`
+
+	var event FuturesAccountUpdateEvent
+	require.NoError(t, json.Unmarshal([]byte(jsonData), &event))
+
+	assert.Equal(t, "ACCOUNT_UPDATE", event.EventType)
+	assert.Equal(t, int64(1564745798939), event.EventTime)
+	assert.Equal(t, int64(1564745798938), event.TransactionTime)
+	assert.Equal(t, "ORDER", event.Reason)
+
+	require.Len(t, event.Balances, 2)
+	assert.Equal(t, "USDT", event.Balances[0].Asset)
+	assert.Equal(t, "122624.12345678", event.Balances[0].WalletBalance.String())
+	assert.Equal(t, "100.12345678", event.Balances[0].CrossWalletBalance.String())
+	assert.Equal(t, "50.12345678", event.Balances[0].BalanceChange.String())
+	assert.Equal(t, "-49.12345678", event.Balances[1].BalanceChange.String())
+
+	require.Len(t, event.Positions, 1)
+	position := event.Positions[0]
+	assert.Equal(t, "BTCUSDT", position.Symbol)
+	assert.Equal(t, "-0.002", position.PositionAmount.String())
+	assert.Equal(t, "51000", position.EntryPrice.String())
+	assert.Equal(t, "51010.2", position.BreakEvenPrice.String())
+	assert.Equal(t, "200", position.AccumulatedRealized.String())
+	assert.Equal(t, "0.16", position.UnrealizedProfit.String())
+	assert.Equal(t, "isolated", position.MarginType)
+	assert.Equal(t, "20.5", position.IsolatedWallet.String())
+	assert.Equal(t, "SHORT", position.PositionSide)
+}
+
+func TestFuturesOrderUpdateEvent(t *testing.T) {
+	jsonData := `{
+		"e": "ORDER_TRADE_UPDATE",
+		"E": 1568879465651,
+		"T": 1568879465650,
+		"o": {
+			"s": "BTCUSDT",
+			"c": "bracket-1-TP1",
+			"S": "SELL",
+			"o": "TAKE_PROFIT_MARKET",
+			"f": "GTC",
+			"q": "0.001",
+			"p": "0",
+			"ap": "51000.5",
+			"sp": "51000",
+			"x": "TRADE",
+			"X": "FILLED",
+			"i": 8886774,
+			"l": "0.001",
+			"z": "0.001",
+			"L": "51000.5",
+			"N": "USDT",
+			"n": "0.0204002",
+			"T": 1568879465650,
+			"t": 6789,
+			"b": "0",
+			"a": "0",
+			"m": false,
+			"R": true,
+			"wt": "MARK_PRICE",
+			"ot": "TAKE_PROFIT_MARKET",
+			"ps": "BOTH",
+			"cp": false,
+			"rp": "1.0005",
+			"pP": false,
+			"si": 0,
+			"ss": 0
+		}
+	}`
+
+	var event FuturesOrderUpdateEvent
+	require.NoError(t, json.Unmarshal([]byte(jsonData), &event))
+
+	assert.Equal(t, "ORDER_TRADE_UPDATE", event.EventType)
+	assert.Equal(t, int64(1568879465651), event.EventTime)
+	assert.Equal(t, int64(1568879465650), event.TransactionTime)
+	assert.Equal(t, "BTCUSDT", event.Symbol)
+	assert.Equal(t, "bracket-1-TP1", event.ClientOrderID)
+	assert.Equal(t, "SELL", event.Side)
+	assert.Equal(t, "TAKE_PROFIT_MARKET", event.OrderType)
+	assert.Equal(t, "TRADE", event.ExecutionType)
+	assert.Equal(t, "FILLED", event.OrderStatus)
+	assert.Equal(t, int64(8886774), event.OrderID)
+	assert.Equal(t, "51000.5", event.AveragePrice.String())
+	assert.Equal(t, "51000", event.StopPrice.String())
+	assert.Equal(t, "0.001", event.CumulativeFilledQty.String())
+	assert.Equal(t, "51000.5", event.LastExecutedPrice.String())
+	assert.Equal(t, "USDT", event.CommissionAsset)
+	assert.Equal(t, "0.0204002", event.CommissionAmount.String())
+	assert.Equal(t, "1.0005", event.RealizedProfit.String())
+	assert.Equal(t, int64(6789), event.TradeID)
+	assert.True(t, event.ReduceOnly)
+	assert.Equal(t, "BOTH", event.PositionSide)
+	assert.Equal(t, "MARK_PRICE", event.WorkingType)
+}
+
 func TestConnectionState(t *testing.T) {
 	t.Run("string representation is correct", func(t *testing.T) {
 		testCases := []struct {
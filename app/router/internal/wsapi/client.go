@@ -0,0 +1,233 @@
+package wsapi
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"strconv"
+	"sync"
+
+	"github.com/google/uuid"
+	"github.com/rs/zerolog"
+	"router/internal/auth"
+	"router/internal/rest"
+	"router/internal/websocket"
+)
+
+// Binance WebSocket API endpoints
+const (
+	DefaultURL = "wss://ws-api.binance.com:443/ws-api/v3"
+	TestnetURL = "wss://ws-api.testnet.binance.vision/ws-api/v3"
+)
+
+// Client places orders over the Binance WebSocket API, correlating responses
+// to requests by ID on a single persistent connection
+type Client struct {
+	url      string
+	signer   *auth.Signer
+	conn     *websocket.Connection
+	connOpts []websocket.ConnectionOption
+	logger   zerolog.Logger
+
+	pending   map[string]chan *response
+	pendingMu sync.Mutex
+}
+
+// Option configures the client
+type Option func(*Client)
+
+// WithConnectionOptions passes options through to the underlying connection
+func WithConnectionOptions(opts ...websocket.ConnectionOption) Option {
+	return func(c *Client) {
+		c.connOpts = append(c.connOpts, opts...)
+	}
+}
+
+// WithLogger sets the logger
+func WithLogger(logger zerolog.Logger) Option {
+	return func(c *Client) {
+		c.logger = logger
+	}
+}
+
+// request is a WebSocket API call frame
+type request struct {
+	ID     string                 `json:"id"`
+	Method string                 `json:"method"`
+	Params map[string]interface{} `json:"params,omitempty"`
+}
+
+// response is a WebSocket API reply frame
+type response struct {
+	ID     string             `json:"id"`
+	Status int                `json:"status"`
+	Result json.RawMessage    `json:"result"`
+	Error  *rest.BinanceError `json:"error"`
+}
+
+// NewClient creates a WebSocket API client for the given endpoint
+func NewClient(url string, signer *auth.Signer, opts ...Option) *Client {
+	c := &Client{
+		url:     url,
+		signer:  signer,
+		logger:  zerolog.Nop(),
+		pending: make(map[string]chan *response),
+	}
+
+	for _, opt := range opts {
+		opt(c)
+	}
+
+	c.conn = websocket.NewConnection(url, c.connOpts...)
+	c.conn.SetMessageHandler(c.handleMessage)
+
+	return c
+}
+
+// Connect opens the WebSocket connection
+func (c *Client) Connect(ctx context.Context) error {
+	if err := c.conn.Connect(ctx); err != nil {
+		return fmt.Errorf("failed to connect to WebSocket API: %w", err)
+	}
+	return nil
+}
+
+// Connected reports whether the WebSocket API connection is up
+func (c *Client) Connected() bool {
+	return c.conn.State() == websocket.StateConnected
+}
+
+// Close closes the connection and fails any in-flight requests
+func (c *Client) Close() error {
+	err := c.conn.Close()
+
+	c.pendingMu.Lock()
+	for id, ch := range c.pending {
+		close(ch)
+		delete(c.pending, id)
+	}
+	c.pendingMu.Unlock()
+
+	return err
+}
+
+// PlaceOrder places an order via order.place. Validation and response shape
+// match rest.Client.PlaceOrder so callers can switch transports freely.
+func (c *Client) PlaceOrder(ctx context.Context, req *rest.OrderRequest) (*rest.OrderResponse, error) {
+	if c.signer == nil {
+		return nil, fmt.Errorf("signer required for PlaceOrder")
+	}
+	if err := req.Validate(); err != nil {
+		return nil, err
+	}
+
+	var orderResp rest.OrderResponse
+	if err := c.call(ctx, "order.place", req.Params(), true, &orderResp); err != nil {
+		return nil, rest.ErrorWithContext(err, "PlaceOrder")
+	}
+
+	return &orderResp, nil
+}
+
+// call sends a request and waits for the response with the matching ID
+func (c *Client) call(ctx context.Context, method string, params url.Values, signed bool, out interface{}) error {
+	if signed {
+		// The API key is part of the signed payload on the WebSocket API
+		params.Set("apiKey", c.signer.APIKey())
+		params = c.signer.SignedRequest(params)
+	}
+
+	req := request{
+		ID:     uuid.NewString(),
+		Method: method,
+		Params: encodeParams(params),
+	}
+
+	data, err := json.Marshal(req)
+	if err != nil {
+		return fmt.Errorf("failed to encode %s request: %w", method, err)
+	}
+
+	ch := make(chan *response, 1)
+	c.pendingMu.Lock()
+	c.pending[req.ID] = ch
+	c.pendingMu.Unlock()
+
+	defer func() {
+		c.pendingMu.Lock()
+		delete(c.pending, req.ID)
+		c.pendingMu.Unlock()
+	}()
+
+	if err := c.conn.Send(ctx, data); err != nil {
+		return fmt.Errorf("failed to send %s request: %w", method, err)
+	}
+
+	select {
+	case resp, ok := <-ch:
+		if !ok {
+			return fmt.Errorf("connection closed while waiting for %s response", method)
+		}
+		if resp.Error != nil {
+			resp.Error.HTTPStatus = resp.Status
+			return resp.Error
+		}
+		if resp.Status != 200 {
+			return fmt.Errorf("%s returned status %d", method, resp.Status)
+		}
+		if out != nil {
+			if err := json.Unmarshal(resp.Result, out); err != nil {
+				return fmt.Errorf("failed to decode %s result: %w", method, err)
+			}
+		}
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// handleMessage routes a response frame to the waiting caller
+func (c *Client) handleMessage(data []byte) {
+	var resp response
+	if err := json.Unmarshal(data, &resp); err != nil {
+		c.logger.Warn().Err(err).Msg("Failed to decode WebSocket API message")
+		return
+	}
+
+	// Deliver under the lock so Close cannot close the channel mid-send;
+	// the channel is buffered so this never blocks
+	c.pendingMu.Lock()
+	defer c.pendingMu.Unlock()
+
+	ch, ok := c.pending[resp.ID]
+	if !ok {
+		c.logger.Debug().Str("id", resp.ID).Msg("Dropping WebSocket API response with no pending request")
+		return
+	}
+
+	delete(c.pending, resp.ID)
+	ch <- &resp
+}
+
+// encodeParams converts signed query parameters to the JSON params object.
+// Numeric timing fields are sent as numbers; everything else as strings.
+func encodeParams(params url.Values) map[string]interface{} {
+	if len(params) == 0 {
+		return nil
+	}
+
+	encoded := make(map[string]interface{}, len(params))
+	for key := range params {
+		value := params.Get(key)
+		switch key {
+		case "timestamp", "recvWindow":
+			if n, err := strconv.ParseInt(value, 10, 64); err == nil {
+				encoded[key] = n
+				continue
+			}
+		}
+		encoded[key] = value
+	}
+	return encoded
+}
@@ -0,0 +1,242 @@
+package wsapi
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/gorilla/websocket"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"router/internal/auth"
+	"router/internal/rest"
+)
+
+type mockRequest struct {
+	ID     string                 `json:"id"`
+	Method string                 `json:"method"`
+	Params map[string]interface{} `json:"params"`
+}
+
+// newMockWSAPIServer verifies request signatures and echoes the order back.
+// Requests are answered in reverse order of arrival once batchSize have been
+// received, to exercise ID-based correlation.
+func newMockWSAPIServer(t *testing.T, signer *auth.Signer, batchSize int) *httptest.Server {
+	upgrader := websocket.Upgrader{
+		CheckOrigin: func(r *http.Request) bool { return true },
+	}
+
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		conn, err := upgrader.Upgrade(w, r, nil)
+		if err != nil {
+			t.Logf("WebSocket upgrade failed: %v", err)
+			return
+		}
+		defer conn.Close()
+
+		var batch []mockRequest
+		for {
+			_, data, err := conn.ReadMessage()
+			if err != nil {
+				return
+			}
+
+			// Keep numeric params verbatim so the signature can be recomputed
+			var req mockRequest
+			decoder := json.NewDecoder(bytes.NewReader(data))
+			decoder.UseNumber()
+			if err := decoder.Decode(&req); err != nil {
+				return
+			}
+			batch = append(batch, req)
+			if len(batch) < batchSize {
+				continue
+			}
+
+			for i := len(batch) - 1; i >= 0; i-- {
+				if err := conn.WriteJSON(mockResponse(signer, batch[i])); err != nil {
+					return
+				}
+			}
+			batch = nil
+		}
+	}))
+}
+
+func mockResponse(signer *auth.Signer, req mockRequest) map[string]interface{} {
+	params := url.Values{}
+	for key, value := range req.Params {
+		params.Set(key, fmt.Sprint(value))
+	}
+	signature := params.Get("signature")
+	params.Del("signature")
+
+	if req.Method != "order.place" || !signer.ValidateSignature(params, signature) {
+		return map[string]interface{}{
+			"id":     req.ID,
+			"status": 400,
+			"error":  map[string]interface{}{"code": -1022, "msg": "Signature for this request is not valid."},
+		}
+	}
+	if params.Get("symbol") == "FAILUSDT" {
+		return map[string]interface{}{
+			"id":     req.ID,
+			"status": 400,
+			"error":  map[string]interface{}{"code": -2010, "msg": "Account has insufficient balance for requested action."},
+		}
+	}
+
+	return map[string]interface{}{
+		"id":     req.ID,
+		"status": 200,
+		"result": map[string]interface{}{
+			"symbol":        params.Get("symbol"),
+			"orderId":       1,
+			"clientOrderId": params.Get("newClientOrderId"),
+			"origQty":       params.Get("quantity"),
+			"side":          params.Get("side"),
+			"type":          params.Get("type"),
+			"status":        "NEW",
+		},
+	}
+}
+
+func connectClient(t *testing.T, server *httptest.Server, signer *auth.Signer) *Client {
+	t.Helper()
+
+	client := NewClient(strings.Replace(server.URL, "http://", "ws://", 1), signer)
+	require.NoError(t, client.Connect(context.Background()))
+	t.Cleanup(func() { client.Close() })
+
+	return client
+}
+
+func TestClient_PlaceOrder(t *testing.T) {
+	signer := auth.NewSigner("test-key", "test-secret")
+
+	t.Run("places signed order", func(t *testing.T) {
+		server := newMockWSAPIServer(t, signer, 1)
+		defer server.Close()
+		client := connectClient(t, server, signer)
+
+		resp, err := client.PlaceOrder(context.Background(), &rest.OrderRequest{
+			Symbol:           "BTCUSDT",
+			Side:             "BUY",
+			Type:             "LIMIT",
+			Quantity:         decimal.RequireFromString("0.01"),
+			Price:            decimal.RequireFromString("50000"),
+			TimeInForce:      "GTC",
+			NewClientOrderID: "ws-1",
+		})
+		require.NoError(t, err)
+		assert.Equal(t, "BTCUSDT", resp.Symbol)
+		assert.Equal(t, "ws-1", resp.ClientOrderID)
+		assert.Equal(t, "0.01", resp.OrigQty.String())
+	})
+
+	t.Run("correlates out-of-order responses by id", func(t *testing.T) {
+		server := newMockWSAPIServer(t, signer, 3)
+		defer server.Close()
+		client := connectClient(t, server, signer)
+
+		var wg sync.WaitGroup
+		results := make([]*rest.OrderResponse, 3)
+		errs := make([]error, 3)
+		for i := 0; i < 3; i++ {
+			wg.Add(1)
+			go func(i int) {
+				defer wg.Done()
+				results[i], errs[i] = client.PlaceOrder(context.Background(), &rest.OrderRequest{
+					Symbol:           "BTCUSDT",
+					Side:             "SELL",
+					Type:             "MARKET",
+					Quantity:         decimal.NewFromInt(int64(i + 1)),
+					NewClientOrderID: fmt.Sprintf("ws-%d", i),
+				})
+			}(i)
+		}
+		wg.Wait()
+
+		for i := 0; i < 3; i++ {
+			require.NoError(t, errs[i])
+			assert.Equal(t, fmt.Sprintf("ws-%d", i), results[i].ClientOrderID)
+			assert.Equal(t, decimal.NewFromInt(int64(i+1)).String(), results[i].OrigQty.String())
+		}
+	})
+
+	t.Run("returns Binance error", func(t *testing.T) {
+		server := newMockWSAPIServer(t, signer, 1)
+		defer server.Close()
+		client := connectClient(t, server, signer)
+
+		_, err := client.PlaceOrder(context.Background(), &rest.OrderRequest{
+			Symbol:   "FAILUSDT",
+			Side:     "BUY",
+			Type:     "MARKET",
+			Quantity: decimal.NewFromInt(1),
+		})
+		require.Error(t, err)
+
+		var binanceErr *rest.BinanceError
+		require.True(t, errors.As(err, &binanceErr))
+		assert.Equal(t, -2010, binanceErr.Code)
+		assert.True(t, binanceErr.IsOrderError())
+	})
+
+	t.Run("rejects signature from wrong secret", func(t *testing.T) {
+		server := newMockWSAPIServer(t, signer, 1)
+		defer server.Close()
+		client := connectClient(t, server, auth.NewSigner("test-key", "wrong-secret"))
+
+		_, err := client.PlaceOrder(context.Background(), &rest.OrderRequest{
+			Symbol:   "BTCUSDT",
+			Side:     "BUY",
+			Type:     "MARKET",
+			Quantity: decimal.NewFromInt(1),
+		})
+
+		var binanceErr *rest.BinanceError
+		require.True(t, errors.As(err, &binanceErr))
+		assert.True(t, binanceErr.IsAuthError())
+	})
+
+	t.Run("validates before sending", func(t *testing.T) {
+		client := NewClient("ws://localhost:0", signer)
+
+		_, err := client.PlaceOrder(context.Background(), &rest.OrderRequest{
+			Symbol: "BTCUSDT",
+			Side:   "BUY",
+			Type:   "LIMIT",
+		})
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "quantity is required")
+	})
+
+	t.Run("honors context deadline", func(t *testing.T) {
+		// Server waits for a second request that never arrives
+		server := newMockWSAPIServer(t, signer, 2)
+		defer server.Close()
+		client := connectClient(t, server, signer)
+
+		ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+		defer cancel()
+
+		_, err := client.PlaceOrder(ctx, &rest.OrderRequest{
+			Symbol:   "BTCUSDT",
+			Side:     "BUY",
+			Type:     "MARKET",
+			Quantity: decimal.NewFromInt(1),
+		})
+		assert.ErrorIs(t, err, context.DeadlineExceeded)
+	})
+}